@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"mcp-go-assistant/internal/config"
+	"mcp-go-assistant/internal/godoc"
+	"mcp-go-assistant/internal/preflight"
+	"mcp-go-assistant/internal/rulepack"
+	versionpkg "mcp-go-assistant/internal/version"
+)
+
+// Subcommands run instead of the server
+const (
+	cmdVersion        = "version"
+	cmdValidateConfig = "validate-config"
+	cmdPrintConfig    = "print-config"
+	cmdDoctor         = "doctor"
+	cmdInstallPack    = "install-rule-pack"
+)
+
+// doctorTimeout bounds the go commands run by the doctor subcommand
+const doctorTimeout = 30 * time.Second
+
+// rulePackTimeout bounds fetching a rule pack from git or a registry
+const rulePackTimeout = 5 * time.Minute
+
+// usage prints the command line help
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage:\n")
+	fmt.Fprintf(out, "  %s [flags]                  Start the MCP server\n", os.Args[0])
+	fmt.Fprintf(out, "  %s %s                  Print the version and build information\n", os.Args[0], cmdVersion)
+	fmt.Fprintf(out, "  %s %s [path]   Load and validate the configuration\n", os.Args[0], cmdValidateConfig)
+	fmt.Fprintf(out, "  %s %s [path]      Print the effective configuration\n", os.Args[0], cmdPrintConfig)
+	fmt.Fprintf(out, "  %s %s [path]            Diagnose the configuration, container and go toolchain\n", os.Args[0], cmdDoctor)
+	fmt.Fprintf(out, "  %s %s <source> <version> [path]\n", os.Args[0], cmdInstallPack)
+	fmt.Fprintf(out, "      Install a rule pack from a git URL or oci:// reference and print its config entry\n")
+	fmt.Fprintf(out, "  %s %s [flags] <file.go>...  Review Go files\n", os.Args[0], cmdReview)
+	fmt.Fprintf(out, "  %s %s -watch <dir> [flags]\n", os.Args[0], cmdReview)
+	fmt.Fprintf(out, "      Review Go files under dir as they change\n")
+	fmt.Fprintf(out, "  %s %s [flags] <package> [symbol]\n", os.Args[0], cmdDoc)
+	fmt.Fprintf(out, "      Print Go documentation\n")
+	fmt.Fprintf(out, "  %s %s [flags] <file.go>    Generate tests for a Go file\n", os.Args[0], cmdTestGen)
+	fmt.Fprintf(out, "  %s %s -watch <dir> [flags]\n", os.Args[0], cmdTestGen)
+	fmt.Fprintf(out, "      Generate tests for Go files under dir as they change\n")
+	fmt.Fprintf(out, "  %s %s [flags]                Serve review diagnostics to an editor over LSP\n", os.Args[0], cmdLSP)
+	fmt.Fprintf(out, "\nThe configuration is read from path, MCP_CONFIG or ./config.yaml, with\nMCP_* environment variables and MCP_PROFILE applied. The tool commands\nread it from -config and list their flags with -h.\n\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+// runCommand runs the subcommand named by args[0] and returns its exit code.
+// ok is false when args do not name a subcommand and the server should start.
+func runCommand(args []string, stdout, stderr io.Writer) (code int, ok bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+
+	switch args[0] {
+	case cmdVersion:
+		fmt.Fprintln(stdout, versionpkg.GetVersionInfo().FullString())
+		return 0, true
+	case cmdInstallPack:
+		if len(args) < 3 || len(args) > 4 {
+			fmt.Fprintf(stderr, "%s takes a source, a version and optionally a config file path\n", args[0])
+			return 2, true
+		}
+		path := os.Getenv("MCP_CONFIG")
+		if len(args) == 4 {
+			path = args[3]
+		}
+		return installRulePack(path, args[1], args[2], stdout, stderr), true
+	case cmdReview, cmdDoc, cmdTestGen, cmdLSP:
+		return runToolCommand(args, stdout, stderr), true
+	case cmdValidateConfig, cmdPrintConfig, cmdDoctor:
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n", args[0])
+		return 2, true
+	}
+	if len(args) > 2 {
+		fmt.Fprintf(stderr, "%s takes at most one config file path\n", args[0])
+		return 2, true
+	}
+
+	path := os.Getenv("MCP_CONFIG")
+	if len(args) == 2 {
+		path = args[1]
+	}
+	if args[0] == cmdDoctor {
+		return doctor(path, stdout), true
+	}
+
+	loaded, err := config.LoadFile(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid configuration: %v\n", err)
+		return 1, true
+	}
+
+	if args[0] == cmdValidateConfig {
+		if loaded.Profile != "" {
+			fmt.Fprintf(stdout, "configuration is valid (profile %s)\n", loaded.Profile)
+		} else {
+			fmt.Fprintln(stdout, "configuration is valid")
+		}
+		return 0, true
+	}
+
+	data, err := loaded.EffectiveYAML()
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1, true
+	}
+	_, _ = stdout.Write(data)
+	return 0, true
+}
+
+// doctor prints a diagnosis of the build, configuration, container and go
+// toolchain, returning 1 when any problem was found
+func doctor(path string, stdout io.Writer) int {
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+
+	fmt.Fprintf(stdout, "version:    %s\n", versionpkg.GetVersionInfo().FullString())
+	diagnosis := preflight.Diagnose(ctx, godoc.Toolchain)
+
+	loaded, err := config.LoadFile(path)
+	switch {
+	case err != nil:
+		fmt.Fprintln(stdout, "config:     invalid")
+		diagnosis.Problems = append([]string{fmt.Sprintf("invalid configuration: %v", err)}, diagnosis.Problems...)
+	case loaded.Profile != "":
+		fmt.Fprintf(stdout, "config:     valid (profile %s)\n", loaded.Profile)
+	default:
+		fmt.Fprintln(stdout, "config:     valid")
+	}
+	if err == nil {
+		for _, ref := range loaded.Review.ToRulePackRefs() {
+			if _, err := rulepack.Verify(loaded.Review.RulePacksDir, ref); err != nil {
+				diagnosis.Problems = append(diagnosis.Problems, err.Error())
+				continue
+			}
+			fmt.Fprintf(stdout, "rule pack:  %s %s verified\n", ref.Name, ref.Version)
+		}
+	}
+
+	diagnosis.Write(stdout)
+	if len(diagnosis.Problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// installRulePack installs version of the rule pack at source into the
+// configured rule pack directory and prints the entry that pins it in
+// review.rule_packs
+func installRulePack(path, source, version string, stdout, stderr io.Writer) int {
+	loaded, err := config.LoadFile(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid configuration: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rulePackTimeout)
+	defer cancel()
+	pack, err := rulepack.Install(ctx, loaded.Review.RulePacksDir, source, version)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "installed rule pack %s %s to %s with %d rule(s)\n\n", pack.Manifest.Name, version, pack.Dir, len(pack.Manifest.Rules))
+	fmt.Fprintf(stdout, "Add it to review.rule_packs in the configuration:\n\n")
+	fmt.Fprintf(stdout, "    - name: %s\n      version: %q\n      source: %q\n      sha256: %s\n", pack.Manifest.Name, version, source, pack.Digest)
+	return 0
+}
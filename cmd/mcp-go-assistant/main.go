@@ -2,34 +2,92 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
+	"mcp-go-assistant/internal/artifact"
+	"mcp-go-assistant/internal/buildgen"
+	"mcp-go-assistant/internal/buildtags"
+	"mcp-go-assistant/internal/cache"
+	"mcp-go-assistant/internal/callgraph"
 	"mcp-go-assistant/internal/circuitbreaker"
 	"mcp-go-assistant/internal/codereview"
 	"mcp-go-assistant/internal/config"
+	"mcp-go-assistant/internal/daemon"
+	"mcp-go-assistant/internal/escape"
 	"mcp-go-assistant/internal/godoc"
+	"mcp-go-assistant/internal/grpcapi"
+	"mcp-go-assistant/internal/grpcreview"
+	"mcp-go-assistant/internal/health"
+	"mcp-go-assistant/internal/history"
+	"mcp-go-assistant/internal/implements"
 	"mcp-go-assistant/internal/logging"
 	"mcp-go-assistant/internal/metrics"
+	"mcp-go-assistant/internal/middleware"
+	"mcp-go-assistant/internal/modreview"
+	"mcp-go-assistant/internal/policy"
+	"mcp-go-assistant/internal/preflight"
+	"mcp-go-assistant/internal/profiling"
+	"mcp-go-assistant/internal/prreview"
+	"mcp-go-assistant/internal/prreview/bitbucket"
+	"mcp-go-assistant/internal/prreview/github"
+	"mcp-go-assistant/internal/prreview/gitlab"
+	"mcp-go-assistant/internal/queue"
 	"mcp-go-assistant/internal/ratelimit"
 	"mcp-go-assistant/internal/retry"
+	"mcp-go-assistant/internal/rulepack"
+	"mcp-go-assistant/internal/scaffold"
+	"mcp-go-assistant/internal/schemagen"
+	"mcp-go-assistant/internal/session"
+	"mcp-go-assistant/internal/sqlgen"
+	"mcp-go-assistant/internal/stacktrace"
+	"mcp-go-assistant/internal/structgen"
 	"mcp-go-assistant/internal/testgen"
 	"mcp-go-assistant/internal/types"
 	"mcp-go-assistant/internal/validations"
 	versionpkg "mcp-go-assistant/internal/version"
+	"mcp-go-assistant/pkg/reviewrule"
 
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog"
 )
 
 const (
-	toolGoDoc      = "go-doc"
-	toolCodeReview = "code-review"
-	toolTestGen    = "test-gen"
+	toolGoDoc            = "go-doc"
+	toolCodeReview       = "code-review"
+	toolCodeReviewBatch  = "code-review-batch"
+	toolTestGen          = "test-gen"
+	toolModReview        = "mod-review"
+	toolGenerateMakefile = "generate-makefile"
+	toolScaffold         = "scaffold"
+	toolStackTrace       = "stack-trace"
+	toolProfileSummary   = "profile-summary"
+	toolEscapeAnalysis   = "escape-analysis"
+	toolBuildConstraints = "build-constraints"
+	toolImplementations  = "implementations"
+	toolCallGraph        = "call-graph"
+	toolStructSchema     = "struct-schema"
+	toolJSONToStruct     = "json-to-struct"
+	toolSQLToGo          = "sql-to-go"
+	toolGRPCReview       = "grpc-review"
+	toolHealth           = "health"
+	toolServerStats      = "server-stats"
+	toolConfigureSession = "configure-session"
+	toolGetArtifact      = "get-artifact"
+	toolReviewTrend      = "review-trend"
 )
 
 var (
@@ -38,16 +96,28 @@ var (
 	cfg                      *config.Config
 	logger                   *logging.Logger
 	metricsCol               *metrics.Metrics
+	metricsPersister         *metrics.Persister
 	shutdownChan             chan os.Signal
 	goDocCircuitBreaker      *circuitbreaker.CircuitBreaker
 	codeReviewCircuitBreaker *circuitbreaker.CircuitBreaker
 	testGenCircuitBreaker    *circuitbreaker.CircuitBreaker
+	toolCircuitBreakers      map[string]*circuitbreaker.CircuitBreaker
 	validator                *validations.Validator
+	writePolicy              *policy.Policy
 	rateLimiter              *ratelimit.Limiter
 	rateLimitMiddleware      *ratelimit.Middleware
 	goDocRetryWrapper        *retry.RetryWrapper
 	codeReviewRetryWrapper   *retry.RetryWrapper
 	testGenRetryWrapper      *retry.RetryWrapper
+	healthChecker            *health.HealthChecker
+	codeReviewCache          *cache.Cache[middleware.CachedResponse[*codereview.ReviewResult]]
+	pluginRules              []reviewrule.Rule
+	testGenCache             *cache.Cache[middleware.CachedResponse[*testgen.TestGenResult]]
+	toolQueues               map[string]*queue.Limiter
+	idempotencyStore         *middleware.IdempotencyStore
+	sessionStore             *session.Store
+	artifactStore            *artifact.Store
+	reviewHistory            *history.Store
 )
 
 // printVersion prints the version to stdout
@@ -64,397 +134,1257 @@ func printFullVersion() {
 
 // GoDocTool handles the go-doc tool invocation.
 func GoDocTool(ctx context.Context, _ *mcp.CallToolRequest, params godoc.GoDocParams) (*mcp.CallToolResult, any, error) {
-	startTime := time.Now()
-	log := logger.WithNewRequestID()
-
-	log.InfoEvent().
-		Str("tool", toolGoDoc).
-		Str("package_path", params.PackagePath).
-		Str("symbol_name", params.SymbolName).
-		Msg("processing go-doc request")
-
-	// Check rate limit before processing
-	if rateLimitMiddleware != nil {
-		if err := rateLimitMiddleware.CheckRateLimit(toolGoDoc, "default"); err != nil {
-			duration := time.Since(startTime)
-			mcpErr := WrapRateLimitError(err, toolGoDoc)
-			_ = LogAndHandleError(log, mcpErr, toolGoDoc, duration)
-			return nil, nil, mcpErr
-		}
-	}
-
-	metricsCol.IncrementActiveRequest(toolGoDoc)
-	defer metricsCol.DecrementActiveRequest(toolGoDoc)
-
-	// Validate package path
-	log.LogValidationAttempt("package_path", "package_path", toolGoDoc)
-	metricsCol.RecordValidationAttempt("package_path", toolGoDoc)
-	if err := validator.ValidatePackagePath(params.PackagePath); err != nil {
-		log.LogValidationError("package_path", "package_path", params.PackagePath, toolGoDoc)
-		metricsCol.RecordValidationFailure("package_path", toolGoDoc)
-		mcpErr := WrapValidationError(err, toolGoDoc)
-		_ = LogAndHandleError(log, mcpErr, toolGoDoc, time.Since(startTime))
-		return nil, nil, mcpErr
-	}
-	log.LogValidationSuccess("package_path", "package_path", toolGoDoc)
-
-	// Validate symbol name (if provided)
-	if params.SymbolName != "" {
-		log.LogValidationAttempt("symbol_name", "symbol_name", toolGoDoc)
-		metricsCol.RecordValidationAttempt("symbol_name", toolGoDoc)
-		if err := validator.ValidateInput(params.SymbolName, "symbol_name"); err != nil {
-			log.LogValidationError("symbol_name", "symbol_name", params.SymbolName, toolGoDoc)
-			metricsCol.RecordValidationFailure("symbol_name", toolGoDoc)
-			mcpErr := WrapValidationError(err, toolGoDoc)
-			_ = LogAndHandleError(log, mcpErr, toolGoDoc, time.Since(startTime))
-			return nil, nil, mcpErr
-		}
-		log.LogValidationSuccess("symbol_name", "symbol_name", toolGoDoc)
-	}
-
-	// Validate working directory (if provided)
-	if params.WorkingDir != "" {
-		log.LogValidationAttempt("working_dir", "file_path", toolGoDoc)
-		metricsCol.RecordValidationAttempt("working_dir", toolGoDoc)
-		if err := validator.ValidateFilePath(params.WorkingDir); err != nil {
-			log.LogValidationError("working_dir", "file_path", params.WorkingDir, toolGoDoc)
-			metricsCol.RecordValidationFailure("working_dir", toolGoDoc)
-			mcpErr := WrapValidationError(err, toolGoDoc)
-			_ = LogAndHandleError(log, mcpErr, toolGoDoc, time.Since(startTime))
-			return nil, nil, mcpErr
+	var content []mcp.Content
+	var result *godoc.GoDocResult
+	plain := false
+
+	switch strings.ToLower(params.Mode) {
+	case "", godoc.ModeDoc:
+		if params.GoJSON {
+			doc, err := godoc.GetSourceDocumentation(ctx, params)
+			if err != nil {
+				return nil, nil, err
+			}
+			content = append(content, &mcp.TextContent{Text: doc.String()})
+			result = &godoc.GoDocResult{Doc: doc}
+			break
+		}
+		documentation, err := godoc.GetDocumentation(ctx, params)
+		if err != nil {
+			return nil, nil, err
+		}
+		content = append(content, &mcp.TextContent{Text: documentation})
+		result = &godoc.GoDocResult{Documentation: documentation}
+		// Plain documentation has no structured output unless examples or
+		// related symbols are added
+		plain = true
+	case godoc.ModeAPI:
+		summary, err := godoc.GetAPISummary(ctx, params)
+		if err != nil {
+			return nil, nil, err
 		}
-		log.LogValidationSuccess("working_dir", "file_path", toolGoDoc)
+		content = append(content, &mcp.TextContent{Text: summary.String()})
+		result = &godoc.GoDocResult{API: summary}
+	default:
+		return nil, nil, fmt.Errorf("unsupported mode: %s (supported: doc, api)", params.Mode)
 	}
 
-	// Wrap call with circuit breaker
-	var documentation string
-	var err error
-
-	cbErr := goDocCircuitBreaker.Call(func() error {
-		// Set timeout
-		if params.WorkingDir == "" {
-			var cancel context.CancelFunc
-			ctx, cancel = context.WithTimeout(ctx, cfg.Tools.GoDocTimeout)
-			defer cancel()
+	if params.IncludeExamples {
+		// Examples are returned as separate content items after the prose
+		examples, err := godoc.GetExamples(ctx, params)
+		if err != nil {
+			return nil, nil, err
 		}
+		for _, example := range examples {
+			content = append(content, &mcp.TextContent{Text: example.String()})
+		}
+		result.Examples = examples
+		plain = false
+	}
 
-		// Use retry logic if configured
-		if goDocRetryWrapper != nil {
-			_, retryErr := goDocRetryWrapper.DoWithData(ctx, func(_ uint) (interface{}, error) {
-				documentation, err = godoc.GetDocumentation(ctx, params)
-				return nil, err
-			})
-			return retryErr
+	// Related symbols close the documentation of a symbol as a see-also
+	// section; API summaries list them already. They only save round
+	// trips, so failing to read them does not fail the lookup.
+	if params.SymbolName != "" && result.API == nil {
+		if related, err := godoc.GetRelated(ctx, params); err == nil && len(related) > 0 {
+			content = append(content, &mcp.TextContent{Text: godoc.SeeAlso(related)})
+			result.Related = related
+			plain = false
 		}
+	}
 
-		// No retry logic
-		documentation, err = godoc.GetDocumentation(ctx, params)
-		return err
+	if plain {
+		return &mcp.CallToolResult{Content: content}, nil, nil
+	}
+	return &mcp.CallToolResult{Content: content}, result, nil
+}
+
+// goDocSpec describes the go-doc middleware stack
+func goDocSpec() middleware.ToolSpec[godoc.GoDocParams, any] {
+	return middleware.ToolSpec[godoc.GoDocParams, any]{
+		Name:      toolGoDoc,
+		RateClass: ratelimit.ClassRead,
+		FailureMessage: func(params godoc.GoDocParams) string {
+			return fmt.Sprintf("failed to get documentation for %s", params.PackagePath)
+		},
+		RequestFields: func(e *zerolog.Event, params godoc.GoDocParams) *zerolog.Event {
+			return e.Str("package_path", params.PackagePath).
+				Str("symbol_name", params.SymbolName).
+				Str("mode", params.Mode).
+				Bool("include_examples", params.IncludeExamples).
+				Bool("go_json", params.GoJSON).
+				Bool("include_unexported", params.IncludeUnexported)
+		},
+		SessionDefaults: func(p *godoc.GoDocParams, d session.Defaults) {
+			defaultString(&p.WorkingDir, d.WorkingDir)
+		},
+		Validation: validationSpec(toolGoDoc, middleware.ValidationSpec{
+			{Field: "package_path", Rules: []string{"not_empty", "package_path"}},
+			{Field: "symbol_name", Rules: []string{"symbol_name"}, Optional: true},
+			{Field: "working_dir", Rules: []string{"file_path", "allowed_root"}, Optional: true},
+			{Field: "mode", Rules: []string{"doc_mode"}, Optional: true},
+		}),
+		IdempotencyKey: func(p godoc.GoDocParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolGoDoc],
+		CircuitBreaker: goDocCircuitBreaker,
+		// Documentation for a working directory may need to load modules,
+		// so only standard lookups are bounded
+		Timeout: func(params godoc.GoDocParams) time.Duration {
+			if params.WorkingDir != "" {
+				return 0
+			}
+			return cfg.Tools.GoDocTimeout
+		},
+		Retry: goDocRetryWrapper,
+	}
+}
+
+// ModReviewTool handles the mod-review tool invocation.
+func ModReviewTool(ctx context.Context, _ *mcp.CallToolRequest, params modreview.ModReviewParams) (*mcp.CallToolResult, *modreview.ModReviewResult, error) {
+	result, err := modreview.ReviewModule(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
+
+// modReviewSpec describes the mod-review middleware stack. mod-review
+// shells out to the go command, so it runs behind its own circuit breaker.
+func modReviewSpec() middleware.ToolSpec[modreview.ModReviewParams, *modreview.ModReviewResult] {
+	return middleware.ToolSpec[modreview.ModReviewParams, *modreview.ModReviewResult]{
+		Name:      toolModReview,
+		RateClass: ratelimit.ClassAnalyze,
+		FailureMessage: func(params modreview.ModReviewParams) string {
+			return fmt.Sprintf("failed to review go.mod in %s", params.WorkingDir)
+		},
+		RequestFields: func(e *zerolog.Event, params modreview.ModReviewParams) *zerolog.Event {
+			return e.Str("working_dir", params.WorkingDir)
+		},
+		ResultFields: func(e *zerolog.Event, result *modreview.ModReviewResult) *zerolog.Event {
+			return e.Int("findings", len(result.Findings))
+		},
+		SessionDefaults: func(p *modreview.ModReviewParams, d session.Defaults) {
+			defaultString(&p.WorkingDir, d.WorkingDir)
+		},
+		Validation: validationSpec(toolModReview, middleware.ValidationSpec{
+			{Field: "working_dir", Rules: []string{"file_path", "allowed_root"}, Optional: true},
+		}),
+		IdempotencyKey: func(p modreview.ModReviewParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolModReview],
+		CircuitBreaker: toolCircuitBreakers[toolModReview],
+		Timeout:        middleware.FixedTimeout[modreview.ModReviewParams](cfg.Tools.ModReviewTimeout),
+	}
+}
+
+// GenerateMakefileTool handles the generate-makefile tool invocation.
+func GenerateMakefileTool(ctx context.Context, _ *mcp.CallToolRequest, params buildgen.BuildGenParams) (*mcp.CallToolResult, *buildgen.BuildGenResult, error) {
+	result, err := buildgen.GenerateBuildFile(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
+
+// generateMakefileSpec describes the generate-makefile middleware stack.
+// Generation only reads a few files from the workspace, so it runs
+// without a circuit breaker.
+func generateMakefileSpec() middleware.ToolSpec[buildgen.BuildGenParams, *buildgen.BuildGenResult] {
+	return middleware.ToolSpec[buildgen.BuildGenParams, *buildgen.BuildGenResult]{
+		Name:      toolGenerateMakefile,
+		RateClass: ratelimit.ClassRead,
+		FailureMessage: func(params buildgen.BuildGenParams) string {
+			return fmt.Sprintf("failed to generate build file for %s", params.WorkingDir)
+		},
+		RequestFields: func(e *zerolog.Event, params buildgen.BuildGenParams) *zerolog.Event {
+			return e.Str("working_dir", params.WorkingDir).Str("format", params.Format)
+		},
+		ResultFields: func(e *zerolog.Event, result *buildgen.BuildGenResult) *zerolog.Event {
+			return e.Str("file_name", result.FileName).Int("binaries", len(result.Layout.Binaries))
+		},
+		SessionDefaults: func(p *buildgen.BuildGenParams, d session.Defaults) {
+			defaultString(&p.WorkingDir, d.WorkingDir)
+		},
+		Validation: validationSpec(toolGenerateMakefile, middleware.ValidationSpec{
+			{Field: "working_dir", Rules: []string{"file_path", "allowed_root"}, Optional: true},
+		}),
+		IdempotencyKey: func(p buildgen.BuildGenParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolGenerateMakefile],
+	}
+}
+
+// ScaffoldTool handles the scaffold tool invocation.
+func ScaffoldTool(ctx context.Context, _ *mcp.CallToolRequest, params scaffold.ScaffoldParams) (*mcp.CallToolResult, *scaffold.ScaffoldResult, error) {
+	result, err := scaffold.Generate(ctx, params, scaffold.Options{
+		TemplateDir: cfg.Scaffold.TemplateDir,
+		GoVersion:   cfg.Scaffold.GoVersion,
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
 
-	if cbErr != nil {
-		duration := time.Since(startTime)
+// scaffoldSpec describes the scaffold middleware stack. Scaffolding renders
+// in-memory templates, so it runs without a circuit breaker.
+func scaffoldSpec() middleware.ToolSpec[scaffold.ScaffoldParams, *scaffold.ScaffoldResult] {
+	return middleware.ToolSpec[scaffold.ScaffoldParams, *scaffold.ScaffoldResult]{
+		Name:      toolScaffold,
+		RateClass: ratelimit.ClassRead,
+		FailureMessage: func(params scaffold.ScaffoldParams) string {
+			return fmt.Sprintf("failed to scaffold %s", params.ModulePath)
+		},
+		RequestFields: func(e *zerolog.Event, params scaffold.ScaffoldParams) *zerolog.Event {
+			return e.Str("module_path", params.ModulePath)
+		},
+		ResultFields: func(e *zerolog.Event, result *scaffold.ScaffoldResult) *zerolog.Event {
+			return e.Int("files", len(result.Files)).Int("diagnostic_count", len(result.Diagnostics))
+		},
+		Validation: validationSpec(toolScaffold, middleware.ValidationSpec{
+			{Field: "module_path", Rules: []string{"not_empty", "package_path"}},
+		}),
+		IdempotencyKey: func(p scaffold.ScaffoldParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolScaffold],
+	}
+}
 
-		// Check if error is circuit breaker open
-		var cbOpenErr *circuitbreaker.CircuitBreakerError
-		if errors.As(cbErr, &cbOpenErr) && errors.Is(cbErr, circuitbreaker.ErrCircuitBreakerOpen) {
-			mcpErr := WrapCircuitBreakerError(cbErr, toolGoDoc)
-			_ = LogAndHandleError(log, mcpErr, toolGoDoc, duration)
-			return nil, nil, mcpErr
-		}
+// StackTraceTool handles the stack-trace tool invocation.
+func StackTraceTool(_ context.Context, _ *mcp.CallToolRequest, params stacktrace.StackTraceParams) (*mcp.CallToolResult, *stacktrace.StackTraceResult, error) {
+	result, err := stacktrace.AnalyzeStackTrace(params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
+
+// stackTraceSpec describes the stack-trace middleware stack. The analysis
+// parses the trace in memory and reads only the frames' source lines, so
+// it runs without a circuit breaker.
+func stackTraceSpec() middleware.ToolSpec[stacktrace.StackTraceParams, *stacktrace.StackTraceResult] {
+	return middleware.ToolSpec[stacktrace.StackTraceParams, *stacktrace.StackTraceResult]{
+		Name:      toolStackTrace,
+		RateClass: ratelimit.ClassRead,
+		FailureMessage: func(stacktrace.StackTraceParams) string {
+			return "failed to analyze stack trace"
+		},
+		RequestFields: func(e *zerolog.Event, params stacktrace.StackTraceParams) *zerolog.Event {
+			return e.Str("working_dir", params.WorkingDir).Int("trace_length", len(params.Trace))
+		},
+		ResultFields: func(e *zerolog.Event, result *stacktrace.StackTraceResult) *zerolog.Event {
+			return e.Str("cause", result.Cause.Kind).Int("goroutines", result.Goroutines)
+		},
+		SessionDefaults: func(p *stacktrace.StackTraceParams, d session.Defaults) {
+			defaultString(&p.WorkingDir, d.WorkingDir)
+		},
+		Validation: validationSpec(toolStackTrace, middleware.ValidationSpec{
+			{Field: "trace", Rules: []string{"not_empty"}, Sensitive: true},
+			{Field: "working_dir", Rules: []string{"file_path", "allowed_root"}, Optional: true},
+		}),
+		IdempotencyKey: func(p stacktrace.StackTraceParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolStackTrace],
+	}
+}
+
+// ProfileSummaryTool handles the profile-summary tool invocation.
+func ProfileSummaryTool(_ context.Context, _ *mcp.CallToolRequest, params profiling.ProfileParams) (*mcp.CallToolResult, *profiling.ProfileSummary, error) {
+	result, err := profiling.Summarize(params, profiling.Options{Roots: cfg.Workspace.Roots})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
+
+// profileSummarySpec describes the profile-summary middleware stack. The
+// profile is parsed in memory without invoking the go tool, so it runs
+// without a circuit breaker.
+func profileSummarySpec() middleware.ToolSpec[profiling.ProfileParams, *profiling.ProfileSummary] {
+	return middleware.ToolSpec[profiling.ProfileParams, *profiling.ProfileSummary]{
+		Name:      toolProfileSummary,
+		RateClass: ratelimit.ClassRead,
+		FailureMessage: func(profiling.ProfileParams) string {
+			return "failed to summarize profile"
+		},
+		RequestFields: func(e *zerolog.Event, params profiling.ProfileParams) *zerolog.Event {
+			return e.Str("profile_file", params.ProfileFile).Str("sample_type", params.SampleType).Str("sort_by", params.SortBy)
+		},
+		ResultFields: func(e *zerolog.Event, result *profiling.ProfileSummary) *zerolog.Event {
+			return e.Str("sample_type", result.SampleType).Int("functions", result.Functions)
+		},
+		Validation: validationSpec(toolProfileSummary, middleware.ValidationSpec{
+			{Field: "profile_file", Rules: []string{"file_path"}, Optional: true},
+		}),
+		IdempotencyKey: func(p profiling.ProfileParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolProfileSummary],
+	}
+}
+
+// EscapeAnalysisTool handles the escape-analysis tool invocation.
+func EscapeAnalysisTool(ctx context.Context, _ *mcp.CallToolRequest, params escape.EscapeParams) (*mcp.CallToolResult, *escape.EscapeResult, error) {
+	result, err := escape.Analyze(ctx, params, escape.Options{Roots: cfg.Workspace.Roots})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
+
+// escapeAnalysisSpec describes the escape-analysis middleware stack. The
+// analysis shells out to go build, so it runs behind its own circuit
+// breaker.
+func escapeAnalysisSpec() middleware.ToolSpec[escape.EscapeParams, *escape.EscapeResult] {
+	return middleware.ToolSpec[escape.EscapeParams, *escape.EscapeResult]{
+		Name:      toolEscapeAnalysis,
+		RateClass: ratelimit.ClassAnalyze,
+		FailureMessage: func(params escape.EscapeParams) string {
+			return fmt.Sprintf("failed to analyze escapes in %s", params.WorkingDir)
+		},
+		RequestFields: func(e *zerolog.Event, params escape.EscapeParams) *zerolog.Event {
+			return e.Str("working_dir", params.WorkingDir).Str("package", params.Package).Bool("inlining", params.Inlining)
+		},
+		ResultFields: func(e *zerolog.Event, result *escape.EscapeResult) *zerolog.Event {
+			return e.Int("heap_allocations", result.HeapAllocations).Int("findings", len(result.Findings))
+		},
+		SessionDefaults: func(p *escape.EscapeParams, d session.Defaults) {
+			defaultString(&p.WorkingDir, d.WorkingDir)
+		},
+		Validation: validationSpec(toolEscapeAnalysis, middleware.ValidationSpec{
+			{Field: "working_dir", Rules: []string{"not_empty", "file_path", "allowed_root"}},
+			{Field: "package", Rules: []string{"file_path"}, Optional: true},
+		}),
+		IdempotencyKey: func(p escape.EscapeParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolEscapeAnalysis],
+		CircuitBreaker: toolCircuitBreakers[toolEscapeAnalysis],
+		Timeout:        middleware.FixedTimeout[escape.EscapeParams](cfg.Tools.EscapeAnalysisTimeout),
+	}
+}
+
+// BuildConstraintsTool handles the build-constraints tool invocation.
+func BuildConstraintsTool(_ context.Context, _ *mcp.CallToolRequest, params buildtags.BuildTagsParams) (*mcp.CallToolResult, *buildtags.BuildTagsResult, error) {
+	result, err := buildtags.Analyze(params, buildtags.Options{Roots: cfg.Workspace.Roots})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
+
+// buildConstraintsSpec describes the build-constraints middleware stack.
+// Constraints are read from file headers without invoking the go tool, so
+// it runs without a circuit breaker.
+func buildConstraintsSpec() middleware.ToolSpec[buildtags.BuildTagsParams, *buildtags.BuildTagsResult] {
+	return middleware.ToolSpec[buildtags.BuildTagsParams, *buildtags.BuildTagsResult]{
+		Name:      toolBuildConstraints,
+		RateClass: ratelimit.ClassAnalyze,
+		FailureMessage: func(params buildtags.BuildTagsParams) string {
+			return fmt.Sprintf("failed to inspect build constraints in %s", params.WorkingDir)
+		},
+		RequestFields: func(e *zerolog.Event, params buildtags.BuildTagsParams) *zerolog.Event {
+			return e.Str("working_dir", params.WorkingDir).Strs("platforms", params.Platforms).Strs("tags", params.Tags)
+		},
+		ResultFields: func(e *zerolog.Event, result *buildtags.BuildTagsResult) *zerolog.Event {
+			return e.Int("constrained", len(result.Constrained)).Int("findings", len(result.Findings))
+		},
+		SessionDefaults: func(p *buildtags.BuildTagsParams, d session.Defaults) {
+			defaultString(&p.WorkingDir, d.WorkingDir)
+		},
+		Validation: validationSpec(toolBuildConstraints, middleware.ValidationSpec{
+			{Field: "working_dir", Rules: []string{"not_empty", "file_path", "allowed_root"}},
+		}),
+		IdempotencyKey: func(p buildtags.BuildTagsParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolBuildConstraints],
+	}
+}
+
+// ImplementationsTool handles the implementations tool invocation.
+func ImplementationsTool(ctx context.Context, _ *mcp.CallToolRequest, params implements.ImplementsParams) (*mcp.CallToolResult, *implements.ImplementsResult, error) {
+	result, err := implements.Find(ctx, params, implements.Options{Roots: cfg.Workspace.Roots})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
+
+// implementationsSpec describes the implementations middleware stack. The
+// matcher shells out to go list, so it runs behind its own circuit breaker.
+func implementationsSpec() middleware.ToolSpec[implements.ImplementsParams, *implements.ImplementsResult] {
+	return middleware.ToolSpec[implements.ImplementsParams, *implements.ImplementsResult]{
+		Name:      toolImplementations,
+		RateClass: ratelimit.ClassAnalyze,
+		FailureMessage: func(params implements.ImplementsParams) string {
+			return fmt.Sprintf("failed to match implementations in %s", params.WorkingDir)
+		},
+		RequestFields: func(e *zerolog.Event, params implements.ImplementsParams) *zerolog.Event {
+			return e.Str("working_dir", params.WorkingDir).Str("interface", params.Interface).Str("package", params.Package)
+		},
+		ResultFields: func(e *zerolog.Event, result *implements.ImplementsResult) *zerolog.Event {
+			return e.Int("implementations", len(result.Implementations)).Int("near_misses", len(result.NearMisses))
+		},
+		SessionDefaults: func(p *implements.ImplementsParams, d session.Defaults) {
+			defaultString(&p.WorkingDir, d.WorkingDir)
+		},
+		Validation: validationSpec(toolImplementations, middleware.ValidationSpec{
+			{Field: "working_dir", Rules: []string{"not_empty", "file_path", "allowed_root"}},
+			{Field: "interface", Rules: []string{"type_name"}, Optional: true},
+			{Field: "interface_code", Rules: []string{"code_safety"}, Optional: true, Sensitive: true},
+			{Field: "package", Rules: []string{"file_path"}, Optional: true},
+		}),
+		IdempotencyKey: func(p implements.ImplementsParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolImplementations],
+		CircuitBreaker: toolCircuitBreakers[toolImplementations],
+		Timeout:        middleware.FixedTimeout[implements.ImplementsParams](cfg.Tools.ImplementationsTimeout),
+	}
+}
+
+// CallGraphTool handles the call-graph tool invocation.
+func CallGraphTool(ctx context.Context, _ *mcp.CallToolRequest, params callgraph.CallGraphParams) (*mcp.CallToolResult, *callgraph.CallGraphResult, error) {
+	result, err := callgraph.Extract(ctx, params, callgraph.Options{Roots: cfg.Workspace.Roots})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
+
+// callGraphSpec describes the call-graph middleware stack. The extraction
+// shells out to go list, so it runs behind its own circuit breaker.
+func callGraphSpec() middleware.ToolSpec[callgraph.CallGraphParams, *callgraph.CallGraphResult] {
+	return middleware.ToolSpec[callgraph.CallGraphParams, *callgraph.CallGraphResult]{
+		Name:      toolCallGraph,
+		RateClass: ratelimit.ClassAnalyze,
+		FailureMessage: func(params callgraph.CallGraphParams) string {
+			return fmt.Sprintf("failed to extract the call graph of %s", params.Function)
+		},
+		RequestFields: func(e *zerolog.Event, params callgraph.CallGraphParams) *zerolog.Event {
+			return e.Str("working_dir", params.WorkingDir).Str("function", params.Function).
+				Str("algorithm", params.Algorithm).Str("direction", params.Direction).Int("depth", params.Depth)
+		},
+		ResultFields: func(e *zerolog.Event, result *callgraph.CallGraphResult) *zerolog.Event {
+			return e.Int("nodes", len(result.Nodes)).Bool("truncated", result.Truncated)
+		},
+		SessionDefaults: func(p *callgraph.CallGraphParams, d session.Defaults) {
+			defaultString(&p.WorkingDir, d.WorkingDir)
+		},
+		Validation: validationSpec(toolCallGraph, middleware.ValidationSpec{
+			{Field: "working_dir", Rules: []string{"not_empty", "file_path", "allowed_root"}},
+			{Field: "function", Rules: []string{"not_empty", "function_name"}},
+			{Field: "package", Rules: []string{"file_path"}, Optional: true},
+		}),
+		IdempotencyKey: func(p callgraph.CallGraphParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolCallGraph],
+		CircuitBreaker: toolCircuitBreakers[toolCallGraph],
+		Timeout:        middleware.FixedTimeout[callgraph.CallGraphParams](cfg.Tools.CallGraphTimeout),
+	}
+}
+
+// StructSchemaTool handles the struct-schema tool invocation.
+func StructSchemaTool(ctx context.Context, _ *mcp.CallToolRequest, params schemagen.SchemaParams) (*mcp.CallToolResult, *schemagen.SchemaResult, error) {
+	result, err := schemagen.Generate(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
+
+// structSchemaSpec describes the struct-schema middleware stack. Imports of
+// go_code are resolved through go list, so it runs behind its own circuit
+// breaker.
+func structSchemaSpec() middleware.ToolSpec[schemagen.SchemaParams, *schemagen.SchemaResult] {
+	return middleware.ToolSpec[schemagen.SchemaParams, *schemagen.SchemaResult]{
+		Name:      toolStructSchema,
+		RateClass: ratelimit.ClassRead,
+		FailureMessage: func(schemagen.SchemaParams) string {
+			return "failed to generate schemas"
+		},
+		RequestFields: func(e *zerolog.Event, params schemagen.SchemaParams) *zerolog.Event {
+			return e.Strs("types", params.Types).Str("format", params.Format)
+		},
+		ResultFields: func(e *zerolog.Event, result *schemagen.SchemaResult) *zerolog.Event {
+			return e.Int("schema_count", len(result.Types))
+		},
+		Validation: validationSpec(toolStructSchema, middleware.ValidationSpec{
+			{Field: "go_code", Rules: []string{"not_empty", "code_safety"}, Sensitive: true},
+		}),
+		IdempotencyKey: func(p schemagen.SchemaParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolStructSchema],
+		CircuitBreaker: toolCircuitBreakers[toolStructSchema],
+		Timeout:        middleware.FixedTimeout[schemagen.SchemaParams](cfg.Tools.StructSchemaTimeout),
+	}
+}
+
+// JSONToStructTool handles the json-to-struct tool invocation.
+func JSONToStructTool(ctx context.Context, _ *mcp.CallToolRequest, params structgen.StructGenParams) (*mcp.CallToolResult, *structgen.StructGenResult, error) {
+	result, err := structgen.Generate(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
+
+// jsonToStructSpec describes the json-to-struct middleware stack
+func jsonToStructSpec() middleware.ToolSpec[structgen.StructGenParams, *structgen.StructGenResult] {
+	return middleware.ToolSpec[structgen.StructGenParams, *structgen.StructGenResult]{
+		Name:      toolJSONToStruct,
+		RateClass: ratelimit.ClassRead,
+		FailureMessage: func(params structgen.StructGenParams) string {
+			return fmt.Sprintf("failed to generate Go types from the %s sample", params.Format)
+		},
+		RequestFields: func(e *zerolog.Event, params structgen.StructGenParams) *zerolog.Event {
+			return e.Str("format", params.Format).Str("type_name", params.TypeName).Str("naming", params.Naming)
+		},
+		ResultFields: func(e *zerolog.Event, result *structgen.StructGenResult) *zerolog.Event {
+			return e.Int("type_count", len(result.Types))
+		},
+		Validation: validationSpec(toolJSONToStruct, middleware.ValidationSpec{
+			{Field: "sample", Rules: []string{"not_empty", "max_length"}, Sensitive: true},
+			{Field: "type_name", Rules: []string{"symbol_name"}, Optional: true},
+			{Field: "package_name", Rules: []string{"package_name"}, Optional: true},
+		}),
+		IdempotencyKey: func(p structgen.StructGenParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolJSONToStruct],
+	}
+}
+
+// SQLToGoTool handles the sql-to-go tool invocation.
+func SQLToGoTool(ctx context.Context, _ *mcp.CallToolRequest, params sqlgen.SQLGenParams) (*mcp.CallToolResult, *sqlgen.SQLGenResult, error) {
+	result, err := sqlgen.Generate(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
 
-		// Tool execution error - wrap as internal error
-		mcpErr := types.WrapError(cbErr, fmt.Sprintf("failed to get documentation for %s", params.PackagePath))
-		metricsCol.RecordToolCall(toolGoDoc, "error", duration)
-		_ = LogAndHandleError(log, mcpErr, toolGoDoc, duration)
-		return nil, nil, mcpErr
+// sqlToGoSpec describes the sql-to-go middleware stack
+func sqlToGoSpec() middleware.ToolSpec[sqlgen.SQLGenParams, *sqlgen.SQLGenResult] {
+	return middleware.ToolSpec[sqlgen.SQLGenParams, *sqlgen.SQLGenResult]{
+		Name:      toolSQLToGo,
+		RateClass: ratelimit.ClassRead,
+		FailureMessage: func(sqlgen.SQLGenParams) string {
+			return "failed to generate Go models from sql"
+		},
+		RequestFields: func(e *zerolog.Event, params sqlgen.SQLGenParams) *zerolog.Event {
+			return e.Str("dialect", params.Dialect).Str("nullable", params.Nullable).Bool("queries", params.Queries)
+		},
+		ResultFields: func(e *zerolog.Event, result *sqlgen.SQLGenResult) *zerolog.Event {
+			return e.Int("table_count", len(result.Tables)).Int("enum_count", len(result.Enums))
+		},
+		Validation: validationSpec(toolSQLToGo, middleware.ValidationSpec{
+			{Field: "sql", Rules: []string{"not_empty", "max_length"}, Sensitive: true},
+			{Field: "package_name", Rules: []string{"package_name"}, Optional: true},
+		}),
+		IdempotencyKey: func(p sqlgen.SQLGenParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolSQLToGo],
 	}
+}
 
-	duration := time.Since(startTime)
-	metricsCol.RecordToolCall(toolGoDoc, "success", duration)
-	log.InfoEvent().
-		Dur("duration_ms", duration).
-		Msg("go-doc request completed")
+// GRPCReviewTool handles the grpc-review tool invocation.
+func GRPCReviewTool(ctx context.Context, _ *mcp.CallToolRequest, params grpcreview.GRPCReviewParams) (*mcp.CallToolResult, *grpcreview.GRPCReviewResult, error) {
+	result, err := grpcreview.Review(ctx, params, grpcreview.Options{Roots: cfg.Workspace.Roots})
+	if err != nil {
+		return nil, nil, err
+	}
 
 	return &mcp.CallToolResult{
-		Content: []mcp.Content{&mcp.TextContent{Text: documentation}},
-	}, nil, nil
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
+
+// grpcReviewSpec describes the grpc-review middleware stack. Packages are
+// found with go list, so it runs behind its own circuit breaker.
+func grpcReviewSpec() middleware.ToolSpec[grpcreview.GRPCReviewParams, *grpcreview.GRPCReviewResult] {
+	return middleware.ToolSpec[grpcreview.GRPCReviewParams, *grpcreview.GRPCReviewResult]{
+		Name:      toolGRPCReview,
+		RateClass: ratelimit.ClassAnalyze,
+		FailureMessage: func(params grpcreview.GRPCReviewParams) string {
+			return fmt.Sprintf("failed to review the gRPC services in %s", params.WorkingDir)
+		},
+		RequestFields: func(e *zerolog.Event, params grpcreview.GRPCReviewParams) *zerolog.Event {
+			return e.Str("working_dir", params.WorkingDir).Str("package", params.Package).Str("service", params.Service)
+		},
+		ResultFields: func(e *zerolog.Event, result *grpcreview.GRPCReviewResult) *zerolog.Event {
+			return e.Int("service_count", len(result.Services)).Int("finding_count", len(result.Findings))
+		},
+		SessionDefaults: func(p *grpcreview.GRPCReviewParams, d session.Defaults) {
+			defaultString(&p.WorkingDir, d.WorkingDir)
+		},
+		Validation: validationSpec(toolGRPCReview, middleware.ValidationSpec{
+			{Field: "working_dir", Rules: []string{"not_empty", "file_path", "allowed_root"}},
+			{Field: "package", Rules: []string{"file_path"}, Optional: true},
+			{Field: "service", Rules: []string{"symbol_name"}, Optional: true},
+		}),
+		IdempotencyKey: func(p grpcreview.GRPCReviewParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolGRPCReview],
+		CircuitBreaker: toolCircuitBreakers[toolGRPCReview],
+		Timeout:        middleware.FixedTimeout[grpcreview.GRPCReviewParams](cfg.Tools.GRPCReviewTimeout),
+	}
 }
 
 // CodeReviewTool handles the code-review tool invocation.
-func CodeReviewTool(ctx context.Context, _ *mcp.CallToolRequest, params codereview.CodeReviewParams) (*mcp.CallToolResult, *codereview.ReviewResult, error) {
-	startTime := time.Now()
-	log := logger.WithNewRequestID()
-
-	log.InfoEvent().
-		Str("tool", toolCodeReview).
-		Str("hint", params.Hint).
-		Msg("processing code-review request")
-
-	// Check rate limit before processing
-	if rateLimitMiddleware != nil {
-		if err := rateLimitMiddleware.CheckRateLimit(toolCodeReview, "default"); err != nil {
-			duration := time.Since(startTime)
-			mcpErr := WrapRateLimitError(err, toolCodeReview)
-			_ = LogAndHandleError(log, mcpErr, toolCodeReview, duration)
-			return nil, nil, mcpErr
-		}
-	}
-
-	metricsCol.IncrementActiveRequest(toolCodeReview)
-	defer metricsCol.DecrementActiveRequest(toolCodeReview)
-
-	// Validate code
-	log.LogValidationAttempt("code", "code_safety", toolCodeReview)
-	metricsCol.RecordValidationAttempt("code_safety", toolCodeReview)
-	if err := validator.ValidateCode(params.GoCode); err != nil {
-		log.LogValidationError("code", "code_safety", "code", toolCodeReview)
-		metricsCol.RecordValidationFailure("code_safety", toolCodeReview)
-		mcpErr := WrapValidationError(err, toolCodeReview)
-		_ = LogAndHandleError(log, mcpErr, toolCodeReview, time.Since(startTime))
-		return nil, nil, mcpErr
-	}
-	log.LogValidationSuccess("code", "code_safety", toolCodeReview)
-
-	// Validate hint (if provided)
-	if params.Hint != "" {
-		log.LogValidationAttempt("hint", "hint", toolCodeReview)
-		metricsCol.RecordValidationAttempt("hint", toolCodeReview)
-		if err := validator.ValidateHint(params.Hint); err != nil {
-			log.LogValidationError("hint", "hint", params.Hint, toolCodeReview)
-			metricsCol.RecordValidationFailure("hint", toolCodeReview)
-			mcpErr := WrapValidationError(err, toolCodeReview)
-			_ = LogAndHandleError(log, mcpErr, toolCodeReview, time.Since(startTime))
-			return nil, nil, mcpErr
-		}
-		log.LogValidationSuccess("hint", "hint", toolCodeReview)
-	}
-
-	// Validate guidelines file path (if provided)
-	if params.GuidelinesFile != "" {
-		log.LogValidationAttempt("guidelines_file", "file_path", toolCodeReview)
-		metricsCol.RecordValidationAttempt("guidelines_file", toolCodeReview)
-		if err := validator.ValidateFilePath(params.GuidelinesFile); err != nil {
-			log.LogValidationError("guidelines_file", "file_path", params.GuidelinesFile, toolCodeReview)
-			metricsCol.RecordValidationFailure("guidelines_file", toolCodeReview)
-			mcpErr := WrapValidationError(err, toolCodeReview)
-			_ = LogAndHandleError(log, mcpErr, toolCodeReview, time.Since(startTime))
-			return nil, nil, mcpErr
-		}
-		log.LogValidationSuccess("guidelines_file", "file_path", toolCodeReview)
-	}
-
-	// Wrap call with circuit breaker
+func CodeReviewTool(ctx context.Context, req *mcp.CallToolRequest, params codereview.CodeReviewParams) (*mcp.CallToolResult, *codereview.ReviewResult, error) {
+	// Fall back to the server's default language
+	if params.Language == "" {
+		params.Language = cfg.Localization.Language
+	}
+
+	// Limits the request leaves unset come from the server settings
+	params.Thresholds = params.Thresholds.WithDefaults(cfg.Review.Thresholds.ToThresholds())
+	params.GuidelinesMaxSize = cfg.Review.GuidelinesMaxSize
+	params.ExternalRules = pluginRules
+
+	// Review the whole workspace when a working directory is given
 	var result *codereview.ReviewResult
 	var err error
+	if params.WorkingDir != "" {
+		result, err = codereview.PerformWorkspaceReview(ctx, params, codereview.WorkspaceOptions{
+			Roots:    cfg.Workspace.Roots,
+			Ignore:   cfg.Workspace.Ignore,
+			MaxFiles: cfg.Workspace.MaxFiles,
+			History:  reviewHistory,
+		}, progressNotifier(ctx, req))
+	} else {
+		result, err = codereview.PerformCodeReviewStream(ctx, params, issueStreamer(ctx, req))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
 
-	cbErr := codeReviewCircuitBreaker.Call(func() error {
-		// Set timeout
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, cfg.Tools.CodeReviewTimeout)
-		defer cancel()
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: codereview.FormatResult(result, params)}},
+	}, result, nil
+}
+
+// codeReviewSpec describes the code-review middleware stack
+func codeReviewSpec() middleware.ToolSpec[codereview.CodeReviewParams, *codereview.ReviewResult] {
+	return middleware.ToolSpec[codereview.CodeReviewParams, *codereview.ReviewResult]{
+		Name:      toolCodeReview,
+		RateClass: ratelimit.ClassAnalyze,
+		FailureMessage: func(codereview.CodeReviewParams) string {
+			return "failed to perform code review"
+		},
+		RequestFields: func(e *zerolog.Event, params codereview.CodeReviewParams) *zerolog.Event {
+			return e.Str("hint", params.Hint)
+		},
+		ResultFields: func(e *zerolog.Event, result *codereview.ReviewResult) *zerolog.Event {
+			return e.Int("score", result.Score)
+		},
+		SessionDefaults: func(p *codereview.CodeReviewParams, d session.Defaults) {
+			// A working_dir turns the call into a workspace review, so the
+			// default only applies to calls without code
+			if p.GoCode == "" {
+				defaultString(&p.WorkingDir, d.WorkingDir)
+			}
+			if p.GuidelinesContent == "" {
+				defaultString(&p.GuidelinesFile, d.GuidelinesFile)
+			}
+			defaultString(&p.Language, d.Language)
+			defaultString(&p.Audience, d.Audience)
+			defaultString(&p.OutputFormat, d.OutputFormat)
+			p.Thresholds = p.Thresholds.WithDefaults(d.Thresholds)
+		},
+		Validation: validationSpec(toolCodeReview, middleware.ValidationSpec{
+			{Field: "go_code", Rules: []string{"code_safety"}, Sensitive: true},
+			{Field: "previous_code", Rules: []string{"code_safety"}, Optional: true, Sensitive: true},
+			{Field: "hint", Rules: []string{"hint"}, Optional: true},
+			{Field: "guidelines_file", Rules: []string{"file_path", "allowed_root"}, Optional: true},
+			{Field: "working_dir", Rules: []string{"file_path", "allowed_root"}, Optional: true},
+			{Field: "coverage_file", Rules: []string{"file_path"}, Optional: true},
+		}),
+		Artifact:       codeReviewArtifact,
+		Cache:          codeReviewCache,
+		CacheKey:       codereview.CacheKey,
+		IdempotencyKey: func(p codereview.CodeReviewParams) string { return p.IdempotencyKey },
+		LargeInput: func(p *codereview.CodeReviewParams) middleware.LargeInput {
+			return middleware.LargeInput{Content: &p.GoCode, File: &p.GoCodeFile, Name: "input.go"}
+		},
+		Queue:          toolQueues[toolCodeReview],
+		CircuitBreaker: codeReviewCircuitBreaker,
+		Timeout:        middleware.FixedTimeout[codereview.CodeReviewParams](cfg.Tools.CodeReviewTimeout),
+		Retry:          codeReviewRetryWrapper,
+	}
+}
+
+// codeReviewArtifact describes a review stored as an artifact: the report in
+// the requested output format, summarized by the score and issue counts
+func codeReviewArtifact(params codereview.CodeReviewParams, result *codereview.ReviewResult) middleware.ArtifactView[*codereview.ReviewResult] {
+	if params.Language == "" {
+		params.Language = cfg.Localization.Language
+	}
+	mimeType := "application/json"
+	switch strings.ToLower(params.OutputFormat) {
+	case codereview.OutputFormatCompact:
+		mimeType = "text/plain"
+	case codereview.OutputFormatMarkdown:
+		mimeType = "text/markdown"
+	}
+
+	// The structured result keeps the score, metrics and summary
+	overview := *result
+	overview.Issues = []codereview.Issue{}
+	overview.Suggestions = []codereview.Suggestion{}
+	overview.APIChanges = nil
+	overview.Workspace = nil
+
+	return middleware.ArtifactView[*codereview.ReviewResult]{
+		Content:  codereview.FormatResult(result, params),
+		MIMEType: mimeType,
+		Summary: fmt.Sprintf("%s\n\nScore: %d/100, %d issues and %d suggestions in the full report.",
+			result.Summary, result.Score, len(result.Issues), len(result.Suggestions)),
+		Out: &overview,
+	}
+}
 
-		// Use retry logic if configured
-		if codeReviewRetryWrapper != nil {
-			_, retryErr := codeReviewRetryWrapper.DoWithData(ctx, func(_ uint) (interface{}, error) {
-				result, err = codereview.PerformCodeReview(ctx, params)
-				return nil, err
-			})
-			return retryErr
+// CodeReviewBatchTool handles the code-review-batch tool invocation.
+func CodeReviewBatchTool(ctx context.Context, _ *mcp.CallToolRequest, params codereview.BatchReviewParams) (*mcp.CallToolResult, *codereview.BatchReviewResult, error) {
+	// Fall back to the server's default language
+	if params.Language == "" {
+		params.Language = cfg.Localization.Language
+	}
+
+	// Limits the request leaves unset come from the server settings
+	params.Thresholds = params.Thresholds.WithDefaults(cfg.Review.Thresholds.ToThresholds())
+	params.GuidelinesMaxSize = cfg.Review.GuidelinesMaxSize
+	params.ExternalRules = pluginRules
+
+	result, err := codereview.PerformBatchReview(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, result, nil
+}
+
+// codeReviewBatchSpec describes the code-review-batch middleware stack,
+// which shares the code-review circuit breaker and timeout
+func codeReviewBatchSpec() middleware.ToolSpec[codereview.BatchReviewParams, *codereview.BatchReviewResult] {
+	return middleware.ToolSpec[codereview.BatchReviewParams, *codereview.BatchReviewResult]{
+		Name:      toolCodeReviewBatch,
+		RateClass: ratelimit.ClassAnalyze,
+		FailureMessage: func(codereview.BatchReviewParams) string {
+			return "failed to perform batch code review"
+		},
+		RequestFields: func(e *zerolog.Event, params codereview.BatchReviewParams) *zerolog.Event {
+			return e.Int("item_count", len(params.Items)).Str("hint", params.Hint)
+		},
+		ResultFields: func(e *zerolog.Event, result *codereview.BatchReviewResult) *zerolog.Event {
+			return e.Int("worst_score", result.WorstScore).Int("total_issues", result.TotalIssues)
+		},
+		SessionDefaults: func(p *codereview.BatchReviewParams, d session.Defaults) {
+			if p.GuidelinesContent == "" {
+				defaultString(&p.GuidelinesFile, d.GuidelinesFile)
+			}
+			defaultString(&p.Language, d.Language)
+			p.Thresholds = p.Thresholds.WithDefaults(d.Thresholds)
+		},
+		Validation: validationSpec(toolCodeReviewBatch, middleware.ValidationSpec{
+			{Field: "items.go_code", Rules: []string{"code_safety"}, Sensitive: true},
+			{Field: "hint", Rules: []string{"hint"}, Optional: true},
+			{Field: "guidelines_file", Rules: []string{"file_path", "allowed_root"}, Optional: true},
+		}),
+		IdempotencyKey: func(p codereview.BatchReviewParams) string { return p.IdempotencyKey },
+		Queue:          toolQueues[toolCodeReviewBatch],
+		CircuitBreaker: codeReviewCircuitBreaker,
+		Timeout:        middleware.FixedTimeout[codereview.BatchReviewParams](cfg.Tools.CodeReviewTimeout),
+	}
+}
+
+// TestGenTool handles the test generation tool invocation.
+func TestGenTool(ctx context.Context, _ *mcp.CallToolRequest, params testgen.TestGenParams) (*mcp.CallToolResult, *testgen.TestGenResult, error) {
+	params.Style = params.Style.WithDefaults(cfg.TestGen.Style.ToStyle())
+	result, err := testgen.GenerateTests(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if params.WriteFiles {
+		err := testgen.WriteFiles(result, params.WorkingDir, testgen.WriteOptions{
+			Roots: cfg.Workspace.Roots,
+			Check: func(path string) error {
+				return writePolicy.CheckWrite(policy.Write{Tool: toolTestGen, Path: path, Confirmed: params.Confirm})
+			},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return &mcp.CallToolResult{Content: testGenContent(result)}, result, nil
+}
+
+// testGenContent returns the generated files as separate content blocks
+// named by their suggested paths, followed by links to the files written.
+// A diff against existing tests stays a single block.
+func testGenContent(result *testgen.TestGenResult) []mcp.Content {
+	if result.Diff != nil {
+		return []mcp.Content{&mcp.TextContent{Text: result.String()}}
+	}
+
+	var content []mcp.Content
+	for _, f := range result.Files() {
+		content = append(content, &mcp.TextContent{Text: f.Content, Meta: mcp.Meta{"filename": f.Path}})
+	}
+	for _, path := range result.Written {
+		content = append(content, &mcp.ResourceLink{
+			URI:      fileURI(path),
+			Name:     filepath.Base(path),
+			MIMEType: "text/x-go",
+		})
+	}
+	return content
+}
+
+// testGenArtifact describes generated tests stored as an artifact: the
+// test and mock code, or the diff against existing tests, summarized by
+// the generated files
+func testGenArtifact(_ testgen.TestGenParams, result *testgen.TestGenResult) middleware.ArtifactView[*testgen.TestGenResult] {
+	overview := *result
+	overview.TestCode = ""
+	overview.MockCode = ""
+	overview.TestFiles = nil
+
+	if result.Diff != nil {
+		diff := *result.Diff
+		diff.Patch = ""
+		overview.Diff = &diff
+		return middleware.ArtifactView[*testgen.TestGenResult]{
+			Content:  result.String(),
+			MIMEType: "text/x-diff",
+			Summary:  fmt.Sprintf("Generated a diff adding %d declarations to the existing tests.", len(result.Diff.Added)),
+			Out:      &overview,
 		}
+	}
 
-		// No retry logic
-		result, err = codereview.PerformCodeReview(ctx, params)
-		return err
+	var sb strings.Builder
+	files := result.Files()
+	fmt.Fprintf(&sb, "Generated %d files:", len(files))
+	for _, f := range files {
+		fmt.Fprintf(&sb, "\n- %s (%d lines)", f.Path, strings.Count(f.Content, "\n"))
+	}
+	if len(result.Diagnostics) > 0 {
+		fmt.Fprintf(&sb, "\nThe generated code has %d compilation errors.", len(result.Diagnostics))
+	}
+	return middleware.ArtifactView[*testgen.TestGenResult]{
+		Content:  result.String(),
+		MIMEType: "text/x-go",
+		Summary:  sb.String(),
+		Out:      &overview,
+	}
+}
+
+// fileURI returns the file:// URI of an absolute path. Windows paths such
+// as C:\src\x.go become file:///C:/src/x.go.
+func fileURI(path string) string {
+	slashed := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashed, "/") {
+		slashed = "/" + slashed
+	}
+	return (&url.URL{Scheme: "file", Path: slashed}).String()
+}
+
+// testGenSpec describes the test-gen middleware stack
+func testGenSpec() middleware.ToolSpec[testgen.TestGenParams, *testgen.TestGenResult] {
+	return middleware.ToolSpec[testgen.TestGenParams, *testgen.TestGenResult]{
+		Name:      toolTestGen,
+		RateClass: ratelimit.ClassAnalyze,
+		FailureMessage: func(testgen.TestGenParams) string {
+			return "failed to generate tests"
+		},
+		RequestFields: func(e *zerolog.Event, params testgen.TestGenParams) *zerolog.Event {
+			return e.Str("focus", params.Focus).Str("package_name", params.PackageName)
+		},
+		ResultFields: func(e *zerolog.Event, result *testgen.TestGenResult) *zerolog.Event {
+			return e.Int("interface_count", len(result.Interfaces)).Int("diagnostic_count", len(result.Diagnostics))
+		},
+		SessionDefaults: func(p *testgen.TestGenParams, d session.Defaults) {
+			defaultString(&p.WorkingDir, d.WorkingDir)
+		},
+		Validation: validationSpec(toolTestGen, middleware.ValidationSpec{
+			{Field: "focus", Rules: []string{"focus"}, Optional: true},
+			{Field: "package_name", Rules: []string{"package_name"}, Optional: true},
+			{Field: "go_code", Rules: []string{"code_safety"}, Sensitive: true},
+			{Field: "mock_package", Rules: []string{"package_name"}, Optional: true},
+			{Field: "import_path", Rules: []string{"package_path"}, Optional: true},
+			{Field: "receiver_constructor", Rules: []string{"symbol_name"}, Optional: true},
+			{Field: "existing_tests", Rules: []string{"code_safety"}, Optional: true, Sensitive: true},
+			{Field: "existing_tests_file", Rules: []string{"file_path"}, Optional: true},
+			{Field: "working_dir", Rules: []string{"file_path", "allowed_root"}, Optional: true},
+		}),
+		Artifact:       testGenArtifact,
+		Cache:          testGenCache,
+		CacheKey:       testgen.CacheKey,
+		IdempotencyKey: func(p testgen.TestGenParams) string { return p.IdempotencyKey },
+		LargeInput: func(p *testgen.TestGenParams) middleware.LargeInput {
+			return middleware.LargeInput{Content: &p.GoCode, File: &p.GoCodeFile, Name: "input.go"}
+		},
+		Queue:          toolQueues[toolTestGen],
+		CircuitBreaker: testGenCircuitBreaker,
+		Timeout:        middleware.FixedTimeout[testgen.TestGenParams](cfg.Tools.TestGenTimeout),
+		Retry:          testGenRetryWrapper,
+	}
+}
+
+// validationSpec applies the configured per-tool rule overrides and
+// disabled rules to a tool's built-in validation spec. Unknown rule names
+// in the configuration are fatal so a typo cannot silently disable a check.
+func validationSpec(tool string, spec middleware.ValidationSpec) middleware.ValidationSpec {
+	overrides := cfg.Validations.Tools[tool]
+	for field := range overrides {
+		known := false
+		for _, fr := range spec {
+			known = known || fr.Field == field
+		}
+		if !known {
+			logger.WarnEvent().Str("tool", tool).Str("field", field).Msg("ignoring validation override for unknown parameter")
+		}
+	}
+
+	spec = spec.WithOverrides(overrides, cfg.Validations.DisabledRules)
+	for _, rule := range spec.Rules() {
+		if !validator.HasRule(rule) {
+			logger.FatalEvent().Str("tool", tool).Str("rule", rule).Msg("unknown validation rule")
+		}
+	}
+	return spec
+}
+
+// HealthTool handles the health tool invocation.
+func HealthTool(_ context.Context, _ *mcp.CallToolRequest, _ health.HealthParams) (*mcp.CallToolResult, *health.Health, error) {
+	result := healthChecker.Check()
+	text, err := result.JSON()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}, &result, nil
+}
+
+// healthSpec describes the health middleware stack
+func healthSpec() middleware.ToolSpec[health.HealthParams, *health.Health] {
+	return middleware.ToolSpec[health.HealthParams, *health.Health]{
+		Name:      toolHealth,
+		RateClass: ratelimit.ClassRead,
+		ResultFields: func(e *zerolog.Event, result *health.Health) *zerolog.Event {
+			return e.Str("status", string(result.Status))
+		},
+	}
+}
+
+// ServerStatsTool handles the server-stats tool invocation.
+func ServerStatsTool(_ context.Context, _ *mcp.CallToolRequest, _ metrics.StatsParams) (*mcp.CallToolResult, *metrics.Stats, error) {
+	stats := metricsCol.Stats()
+	stats.CircuitBreakers = make(map[string]string)
+	for _, cb := range []*circuitbreaker.CircuitBreaker{goDocCircuitBreaker, codeReviewCircuitBreaker, testGenCircuitBreaker} {
+		stats.CircuitBreakers[cb.Name()] = string(cb.State())
+	}
+	for _, cb := range toolCircuitBreakers {
+		stats.CircuitBreakers[cb.Name()] = string(cb.State())
+	}
+
+	text, err := stats.JSON()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}, stats, nil
+}
+
+// serverStatsSpec describes the server-stats middleware stack
+func serverStatsSpec() middleware.ToolSpec[metrics.StatsParams, *metrics.Stats] {
+	return middleware.ToolSpec[metrics.StatsParams, *metrics.Stats]{
+		Name:      toolServerStats,
+		RateClass: ratelimit.ClassRead,
+		ResultFields: func(e *zerolog.Event, result *metrics.Stats) *zerolog.Event {
+			return e.Int("total_calls", result.TotalCalls).Int("active_requests", result.ActiveRequests)
+		},
+	}
+}
+
+// ConfigureSessionTool handles the configure-session tool invocation.
+func ConfigureSessionTool(_ context.Context, req *mcp.CallToolRequest, params session.ConfigureParams) (*mcp.CallToolResult, *session.Defaults, error) {
+	if req == nil || req.Session == nil {
+		return nil, nil, fmt.Errorf("configure-session requires a client session")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	defaults := sessionStore.Configure(req.Session, params)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: defaults.String()}},
+	}, &defaults, nil
+}
+
+// configureSessionSpec describes the configure-session middleware stack.
+// Its paths pass the same rules as the parameters they stand in for.
+func configureSessionSpec() middleware.ToolSpec[session.ConfigureParams, *session.Defaults] {
+	return middleware.ToolSpec[session.ConfigureParams, *session.Defaults]{
+		Name:      toolConfigureSession,
+		RateClass: ratelimit.ClassRead,
+		FailureMessage: func(session.ConfigureParams) string {
+			return "failed to configure session"
+		},
+		RequestFields: func(e *zerolog.Event, params session.ConfigureParams) *zerolog.Event {
+			return e.Str("working_dir", params.WorkingDir).Bool("reset", params.Reset)
+		},
+		Validation: validationSpec(toolConfigureSession, middleware.ValidationSpec{
+			{Field: "working_dir", Rules: []string{"file_path", "allowed_root"}, Optional: true},
+			{Field: "guidelines_file", Rules: []string{"file_path", "allowed_root"}, Optional: true},
+		}),
+	}
+}
+
+// GetArtifactTool handles the get-artifact tool invocation.
+func GetArtifactTool(_ context.Context, _ *mcp.CallToolRequest, params artifact.GetParams) (*mcp.CallToolResult, *artifact.Artifact, error) {
+	if params.ID == "" {
+		return nil, nil, types.NewValidationError("id is required", "field", "id")
+	}
+	a, ok := artifactStore.Get(params.ID)
+	if !ok {
+		return nil, nil, types.NewNotFoundError("artifact not found or expired", "id", params.ID)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: a.Content}},
+	}, &a, nil
+}
+
+// getArtifactSpec describes the get-artifact middleware stack
+func getArtifactSpec() middleware.ToolSpec[artifact.GetParams, *artifact.Artifact] {
+	return middleware.ToolSpec[artifact.GetParams, *artifact.Artifact]{
+		Name:      toolGetArtifact,
+		RateClass: ratelimit.ClassRead,
+		FailureMessage: func(artifact.GetParams) string {
+			return "failed to get artifact"
+		},
+		RequestFields: func(e *zerolog.Event, params artifact.GetParams) *zerolog.Event {
+			return e.Str("id", params.ID)
+		},
+		ResultFields: func(e *zerolog.Event, result *artifact.Artifact) *zerolog.Event {
+			return e.Str("artifact_tool", result.Tool).Int("size", result.Size)
+		},
+	}
+}
+
+// ReviewTrendTool handles the review-trend tool invocation.
+func ReviewTrendTool(_ context.Context, _ *mcp.CallToolRequest, params history.TrendParams) (*mcp.CallToolResult, *history.Trend, error) {
+	trend, err := codereview.WorkspaceTrend(params, codereview.WorkspaceOptions{
+		Roots:   cfg.Workspace.Roots,
+		History: reviewHistory,
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: trend.String()}},
+	}, trend, nil
+}
+
+// reviewTrendSpec describes the review-trend middleware stack
+func reviewTrendSpec() middleware.ToolSpec[history.TrendParams, *history.Trend] {
+	return middleware.ToolSpec[history.TrendParams, *history.Trend]{
+		Name:      toolReviewTrend,
+		RateClass: ratelimit.ClassRead,
+		FailureMessage: func(history.TrendParams) string {
+			return "failed to get review trend"
+		},
+		RequestFields: func(e *zerolog.Event, params history.TrendParams) *zerolog.Event {
+			return e.Str("working_dir", params.WorkingDir).Int("runs", params.Runs)
+		},
+		ResultFields: func(e *zerolog.Event, result *history.Trend) *zerolog.Event {
+			return e.Str("direction", result.Direction).Int("score_change", result.ScoreChange)
+		},
+		SessionDefaults: func(p *history.TrendParams, d session.Defaults) {
+			defaultString(&p.WorkingDir, d.WorkingDir)
+		},
+		Validation: validationSpec(toolReviewTrend, middleware.ValidationSpec{
+			{Field: "working_dir", Rules: []string{"not_empty", "file_path", "allowed_root"}},
+		}),
+	}
+}
+
+// defaultString sets *field to value when the call left it empty
+func defaultString(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
+
+// preflightChecks returns the startup checks for the go toolchain, the
+// stdlib documentation cache and the rate-limit store
+func preflightChecks() []preflight.Check {
+	return []preflight.Check{
+		{
+			Name: "go_toolchain",
+			Run:  godoc.Toolchain,
+		},
+		{
+			Name: "stdlib_doc_cache",
+			Run: func(ctx context.Context) (string, error) {
+				cached, err := godoc.WarmCache(ctx, cfg.Preflight.WarmPackages)
+				return fmt.Sprintf("%d/%d packages cached", cached, len(cfg.Preflight.WarmPackages)), err
+			},
+		},
+		{
+			Name: "rate_limit_store",
+			Run: func(context.Context) (string, error) {
+				if rateLimiter == nil {
+					return "rate limiting disabled", nil
+				}
+				if err := rateLimiter.CheckStore(); err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%s store reachable", cfg.RateLimit.StoreType), nil
+			},
+		},
+	}
+}
+
+// runPreflight runs the startup checks and registers their report with the
+// health checker. Failures are fatal or leave the server degraded depending
+// on the configured failure mode.
+func runPreflight() {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Preflight.Timeout)
+	defer cancel()
+
+	checks := preflightChecks()
+	if preflight.InContainer(cfg.Preflight.ContainerMode) {
+		_, detected := preflight.DetectContainer()
+		logger.InfoEvent().
+			Str("detected", detected).
+			Bool("minimal_image", preflight.Minimal()).
+			Msg("container deployment, checking go environment")
+		checks = append(checks, preflight.GoEnvironmentCheck())
+	}
 
-	if cbErr != nil {
-		duration := time.Since(startTime)
+	report := preflight.Run(ctx, checks)
+	healthChecker.RegisterChecker("preflight", report)
 
-		// Check if error is circuit breaker open
-		var cbOpenErr *circuitbreaker.CircuitBreakerError
-		if errors.As(cbErr, &cbOpenErr) && errors.Is(cbErr, circuitbreaker.ErrCircuitBreakerOpen) {
-			mcpErr := WrapCircuitBreakerError(cbErr, toolCodeReview)
-			_ = LogAndHandleError(log, mcpErr, toolCodeReview, duration)
-			return nil, nil, mcpErr
+	for _, result := range report.Results {
+		event := logger.InfoEvent()
+		if !result.Passed {
+			event = logger.WarnEvent().Str("error", result.Error)
 		}
-
-		// Tool execution error - wrap as internal error
-		mcpErr := types.WrapError(cbErr, "failed to perform code review")
-		metricsCol.RecordToolCall(toolCodeReview, "error", duration)
-		_ = LogAndHandleError(log, mcpErr, toolCodeReview, duration)
-		return nil, nil, mcpErr
+		event.
+			Str("check", result.Name).
+			Str("detail", result.Message).
+			Dur("duration_ms", result.Duration).
+			Msg("preflight check completed")
 	}
 
-	duration := time.Since(startTime)
-	metricsCol.RecordToolCall(toolCodeReview, "success", duration)
-	log.InfoEvent().
-		Dur("duration_ms", duration).
-		Int("score", result.Score).
-		Msg("code-review request completed")
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
-	}, result, nil
+	if err := report.Err(); err != nil {
+		if cfg.Preflight.OnFailure == preflight.OnFailureFail {
+			logger.FatalEvent().Err(err).Msg("refusing to start")
+		}
+		logger.WarnEvent().Err(err).Msg("starting in degraded mode")
+	}
 }
 
-// TestGenTool handles the test generation tool invocation.
-func TestGenTool(ctx context.Context, _ *mcp.CallToolRequest, params testgen.TestGenParams) (*mcp.CallToolResult, *testgen.TestGenResult, error) {
-	startTime := time.Now()
-	log := logger.WithNewRequestID()
-
-	log.InfoEvent().
-		Str("tool", toolTestGen).
-		Str("focus", params.Focus).
-		Str("package_name", params.PackageName).
-		Msg("processing test-gen request")
-
-	// Check rate limit before processing
-	if rateLimitMiddleware != nil {
-		if err := rateLimitMiddleware.CheckRateLimit(toolTestGen, "default"); err != nil {
-			duration := time.Since(startTime)
-			mcpErr := WrapRateLimitError(err, toolTestGen)
-			_ = LogAndHandleError(log, mcpErr, toolTestGen, duration)
-			return nil, nil, mcpErr
-		}
-	}
-
-	metricsCol.IncrementActiveRequest(toolTestGen)
-	defer metricsCol.DecrementActiveRequest(toolTestGen)
-
-	// Validate focus (if provided)
-	if params.Focus != "" {
-		log.LogValidationAttempt("focus", "focus", toolTestGen)
-		metricsCol.RecordValidationAttempt("focus", toolTestGen)
-		if err := validator.ValidateFocus(params.Focus); err != nil {
-			log.LogValidationError("focus", "focus", params.Focus, toolTestGen)
-			metricsCol.RecordValidationFailure("focus", toolTestGen)
-			mcpErr := WrapValidationError(err, toolTestGen)
-			_ = LogAndHandleError(log, mcpErr, toolTestGen, time.Since(startTime))
-			return nil, nil, mcpErr
-		}
-		log.LogValidationSuccess("focus", "focus", toolTestGen)
-	}
-
-	// Validate package name (if provided)
-	if params.PackageName != "" {
-		log.LogValidationAttempt("package_name", "package_name", toolTestGen)
-		metricsCol.RecordValidationAttempt("package_name", toolTestGen)
-		if err := validator.ValidatePackageName(params.PackageName); err != nil {
-			log.LogValidationError("package_name", "package_name", params.PackageName, toolTestGen)
-			metricsCol.RecordValidationFailure("package_name", toolTestGen)
-			mcpErr := WrapValidationError(err, toolTestGen)
-			_ = LogAndHandleError(log, mcpErr, toolTestGen, time.Since(startTime))
-			return nil, nil, mcpErr
-		}
-		log.LogValidationSuccess("package_name", "package_name", toolTestGen)
-	}
-
-	// Validate Go code
-	log.LogValidationAttempt("code", "code_safety", toolTestGen)
-	metricsCol.RecordValidationAttempt("code_safety", toolTestGen)
-	if err := validator.ValidateCode(params.GoCode); err != nil {
-		log.LogValidationError("code", "code_safety", "code", toolTestGen)
-		metricsCol.RecordValidationFailure("code_safety", toolTestGen)
-		mcpErr := WrapValidationError(err, toolTestGen)
-		_ = LogAndHandleError(log, mcpErr, toolTestGen, time.Since(startTime))
-		return nil, nil, mcpErr
-	}
-	log.LogValidationSuccess("code", "code_safety", toolTestGen)
-
-	// Wrap call with circuit breaker
-	var result *testgen.TestGenResult
-	var err error
-
-	cbErr := testGenCircuitBreaker.Call(func() error {
-		// Set timeout
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, cfg.Tools.TestGenTimeout)
-		defer cancel()
-
-		// Use retry logic if configured
-		if testGenRetryWrapper != nil {
-			_, retryErr := testGenRetryWrapper.DoWithData(ctx, func(_ uint) (interface{}, error) {
-				result, err = testgen.GenerateTests(ctx, params)
-				return nil, err
-			})
-			return retryErr
-		}
+// progressNotifier returns a callback that forwards progress updates to the
+// client, or nil when the request did not ask for progress notifications
+func progressNotifier(ctx context.Context, req *mcp.CallToolRequest) codereview.ProgressFunc {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return nil
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return nil
+	}
 
-		// No retry logic
-		result, err = testgen.GenerateTests(ctx, params)
-		return err
-	})
+	return func(done, total int, file string) {
+		_ = req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      float64(done),
+			Total:         float64(total),
+			Message:       file,
+		})
+	}
+}
 
-	if cbErr != nil {
-		duration := time.Since(startTime)
+// issueStreamer returns a callback that sends each batch of review issues
+// to the client as a JSON progress message, or nil when the request did not
+// ask for progress notifications
+func issueStreamer(ctx context.Context, req *mcp.CallToolRequest) codereview.StreamFunc {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return nil
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return nil
+	}
 
-		// Check if error is circuit breaker open
-		var cbOpenErr *circuitbreaker.CircuitBreakerError
-		if errors.As(cbErr, &cbOpenErr) && errors.Is(cbErr, circuitbreaker.ErrCircuitBreakerOpen) {
-			mcpErr := WrapCircuitBreakerError(cbErr, toolTestGen)
-			_ = LogAndHandleError(log, mcpErr, toolTestGen, duration)
-			return nil, nil, mcpErr
+	return func(batch codereview.IssueBatch) {
+		message, err := json.Marshal(batch)
+		if err != nil {
+			return
 		}
-
-		// Tool execution error - wrap as internal error
-		mcpErr := types.WrapError(cbErr, "failed to generate tests")
-		metricsCol.RecordToolCall(toolTestGen, "error", duration)
-		_ = LogAndHandleError(log, mcpErr, toolTestGen, duration)
-		return nil, nil, mcpErr
+		_ = req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      float64(batch.Done),
+			Total:         float64(batch.Total),
+			Message:       string(message),
+		})
 	}
-
-	duration := time.Since(startTime)
-	metricsCol.RecordToolCall(toolTestGen, "success", duration)
-	log.InfoEvent().
-		Dur("duration_ms", duration).
-		Int("interface_count", len(result.Interfaces)).
-		Msg("test-gen request completed")
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
-	}, result, nil
 }
 
-// classifyError classifies errors for metrics
+// classifyError classifies errors for metrics by their kind or the
+// standard errors they wrap
 func classifyError(err error) string {
 	if err == nil {
 		return "none"
 	}
 
-	errStr := err.Error()
-
-	// Classify based on error patterns
+	if kind, ok := types.KindOf(err); ok {
+		return string(kind)
+	}
 	switch {
-	case contains(errStr, "timeout", "deadline exceeded"):
+	case errors.Is(err, context.DeadlineExceeded):
 		return "timeout"
-	case contains(errStr, "not found", "no such file"):
+	case errors.Is(err, fs.ErrNotExist):
 		return "not_found"
-	case contains(errStr, "permission", "denied"):
+	case errors.Is(err, fs.ErrPermission):
 		return "permission"
-	case contains(errStr, "parse", "syntax"):
-		return "parse_error"
 	default:
 		return "unknown"
 	}
 }
 
-// contains checks if any of the substrings are in the text
-func contains(text string, substrings ...string) bool {
-	textLower := text
-	for _, s := range substrings {
-		if s != "" && len(s) <= len(textLower) {
-			// Simple substring check
-			for i := 0; i <= len(textLower)-len(s); i++ {
-				if textLower[i:i+len(s)] == s {
-					return true
-				}
-			}
-		}
-	}
-	return false
-}
-
 // FormatError formats an error for API response
 func FormatError(err error, tool string, exposeDetails bool) []byte {
 	if err == nil {
@@ -519,6 +1449,12 @@ func LogAndHandleError(log *logging.Logger, err error, tool string, duration tim
 			code = classifyError(err)
 			category = "unknown"
 		}
+		// Failures of the tool's own work are counted by kind rather than
+		// as generic internal errors
+		if _, ok := types.KindOf(err); ok && (category == "internal" || category == "unknown") {
+			code = classifyError(err)
+			category = "tool"
+		}
 		metricsCol.RecordError(category, code, tool)
 	}
 
@@ -538,67 +1474,45 @@ func LogAndHandleError(log *logging.Logger, err error, tool string, duration tim
 	return err
 }
 
-// WrapValidationError wraps validation errors as MCPError
-func WrapValidationError(err error, tool string) error {
-	if err == nil {
-		return nil
-	}
-
-	if verr, ok := err.(*validations.ValidationError); ok {
-		mcpErr := verr.ToMCPError()
-		if tool != "" {
-			mcpErr = types.AddDetail(mcpErr, "tool", tool)
-		}
-		return mcpErr
-	}
-
-	return types.WrapValidationError(err, fmt.Sprintf("%s validation failed", tool))
-}
-
-// WrapRateLimitError wraps rate limit errors as MCPError
-func WrapRateLimitError(err error, tool string) error {
-	if err == nil {
-		return nil
-	}
-
-	if rerr, ok := err.(*ratelimit.RateLimitError); ok {
-		mcpErr := rerr.ToMCPError()
-		if tool != "" {
-			mcpErr = types.AddDetail(mcpErr, "tool", tool)
-		}
-		return mcpErr
-	}
-
-	return types.WrapRateLimitError(err, fmt.Sprintf("%s rate limit exceeded", tool))
-}
+// initMetricsPersistence restores counters from the snapshot file and
+// returns a persister that keeps it up to date. A missing or unreadable
+// snapshot starts the counters from zero.
+func initMetricsPersistence() *metrics.Persister {
+	sink := metrics.NewFileSink(cfg.Metrics.SnapshotPath)
 
-// WrapCircuitBreakerError wraps circuit breaker errors as MCPError
-func WrapCircuitBreakerError(err error, tool string) error {
-	if err == nil {
-		return nil
+	snapshot, err := sink.Load()
+	if err == nil && snapshot != nil {
+		err = metricsCol.Restore(snapshot)
 	}
-
-	if cerr, ok := err.(*circuitbreaker.CircuitBreakerError); ok {
-		mcpErr := cerr.ToMCPError()
-		if tool != "" {
-			mcpErr = types.AddDetail(mcpErr, "tool", tool)
-		}
-		return mcpErr
+	if err != nil {
+		logger.WarnEvent().Err(err).Str("path", sink.Path).Msg("failed to restore metrics snapshot")
+	} else if snapshot != nil {
+		logger.InfoEvent().
+			Str("path", sink.Path).
+			Time("snapshot_time", snapshot.Timestamp).
+			Msg("metrics restored from snapshot")
 	}
 
-	return types.WrapCircuitBreakerError(err, fmt.Sprintf("%s circuit breaker open", tool))
+	return metrics.NewPersister(metricsCol, sink, cfg.Metrics.SnapshotInterval, func(err error) {
+		logger.WarnEvent().Err(err).Str("path", sink.Path).Msg("failed to save metrics snapshot")
+	})
 }
 
-// init initializes the application
-func init() {
-	var err error
-
-	// Load configuration
-	cfg, err = config.Load()
+// setup loads the configuration and initializes the application
+func setup() {
+	loaded, err := config.Load()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
+	initialize(loaded)
+}
+
+// initialize sets up the logger, validator, resilience stack and stores of
+// the tools from the loaded configuration
+func initialize(loaded *config.Config) {
+	var err error
+	cfg = loaded
 
 	// Initialize logger
 	logger, err = logging.New(
@@ -614,17 +1528,82 @@ func init() {
 
 	// Initialize metrics
 	metricsCol = metrics.New()
+	if cfg.Metrics.SnapshotPath != "" {
+		metricsPersister = initMetricsPersistence()
+	}
+	for tool, budget := range cfg.Metrics.LatencyBudgets {
+		metricsCol.SetLatencyBudget(tool, budget)
+	}
 
 	// Initialize validator
 	validator = validations.NewValidator()
 	validator.SetMaxSize(cfg.Validations.MaxInputSize)
 	validator.SetAllowedChars(cfg.Validations.AllowedChars)
+	validator.AddRule("allowed_root", validations.NewAllowedRootsRule(cfg.Validations.AllowedRoots))
+	validator.AddRule("code_safety", cfg.Validations.CodeSafety.Rule(""))
+	for tool := range cfg.Validations.CodeSafety.Tools {
+		validator.AddToolRule(tool, "code_safety", cfg.Validations.CodeSafety.Rule(tool))
+	}
 
 	logger.InfoEvent().
 		Int("max_input_size", cfg.Validations.MaxInputSize).
 		Str("allowed_chars", cfg.Validations.AllowedChars).
+		Str("code_safety_mode", cfg.Validations.CodeSafety.Mode).
+		Strs("allowed_roots", cfg.Validations.AllowedRoots).
 		Msg("validator initialized")
 
+	// Initialize write policy, consulted by every tool that writes files
+	writePolicy = policy.New(cfg.WritePolicy.ToPolicyConfig(), logger)
+	logger.InfoEvent().
+		Bool("read_only", cfg.WritePolicy.ReadOnly).
+		Strs("allow_paths", cfg.WritePolicy.AllowPaths).
+		Bool("require_confirmation", cfg.WritePolicy.RequireConfirmation).
+		Msg("write policy initialized")
+
+	// Verify rule packs, installing missing ones, before loading their rules
+	wasmModules := cfg.Review.ToWasmModules()
+	for _, ref := range cfg.Review.ToRulePackRefs() {
+		ctx, cancel := context.WithTimeout(context.Background(), rulePackTimeout)
+		pack, err := rulepack.Require(ctx, cfg.Review.RulePacksDir, ref)
+		cancel()
+		if err != nil {
+			logger.FatalEvent().Err(err).Msg("failed to verify rule pack")
+		}
+		modules, err := pack.WasmModules()
+		if err != nil {
+			logger.FatalEvent().Err(err).Msg("failed to verify rule pack")
+		}
+		wasmModules = append(wasmModules, modules...)
+		logger.InfoEvent().
+			Str("name", ref.Name).
+			Str("version", ref.Version).
+			Str("sha256", pack.Digest).
+			Int("rule_count", len(modules)).
+			Msg("rule pack verified")
+	}
+
+	// Load review rules from Go and wasm plugins and rule packs
+	if len(cfg.Review.Plugins) > 0 || len(wasmModules) > 0 {
+		pluginRules, err = codereview.LoadPluginRules(cfg.Review.Plugins)
+		if err != nil {
+			logger.FatalEvent().Err(err).Msg("failed to load review rule plugins")
+		}
+		wasmRules, err := codereview.LoadWasmRules(context.Background(), wasmModules, cfg.Review.WasmLimits.ToWasmLimits())
+		if err != nil {
+			logger.FatalEvent().Err(err).Msg("failed to load wasm review rules")
+		}
+		pluginRules = append(pluginRules, wasmRules...)
+		if err := codereview.CheckRuleNames(pluginRules); err != nil {
+			logger.FatalEvent().Err(err).Msg("failed to load review rule plugins")
+		}
+		logger.InfoEvent().
+			Strs("plugins", cfg.Review.Plugins).
+			Int("wasm_plugins", len(cfg.Review.WasmPlugins)).
+			Int("rule_packs", len(cfg.Review.RulePacks)).
+			Int("rule_count", len(pluginRules)).
+			Msg("review rule plugins loaded")
+	}
+
 	// Initialize circuit breakers
 	goDocCircuitBreaker = circuitbreaker.NewCircuitBreaker(
 		"godoc",
@@ -656,6 +1635,21 @@ func init() {
 		Str("timeout", cfg.Tools.TestGenCircuitBreaker.Timeout.String()).
 		Msg("test-gen circuit breaker initialized")
 
+	// Tools that shell out to the go command use the go-doc settings, but
+	// each has its own breaker so failures of one do not block the others
+	toolCircuitBreakers = make(map[string]*circuitbreaker.CircuitBreaker)
+	for _, tool := range []string{toolModReview, toolEscapeAnalysis, toolImplementations, toolCallGraph, toolStructSchema, toolGRPCReview} {
+		toolCircuitBreakers[tool] = circuitbreaker.NewCircuitBreaker(
+			tool,
+			cfg.Tools.GoDocCircuitBreaker.ToCircuitBreakerConfig(tool),
+		)
+	}
+	logger.InfoEvent().
+		Int("circuit_breakers", len(toolCircuitBreakers)).
+		Str("max_failures", fmt.Sprintf("%d", cfg.Tools.GoDocCircuitBreaker.MaxFailures)).
+		Str("timeout", cfg.Tools.GoDocCircuitBreaker.Timeout.String()).
+		Msg("go toolchain circuit breakers initialized")
+
 	// Initialize rate limiter
 	if cfg.RateLimit.Enabled {
 		// Create rate limit config
@@ -667,13 +1661,16 @@ func init() {
 			Algorithm: ratelimit.Algorithm(cfg.RateLimit.Algorithm),
 			StoreType: ratelimit.StoreType(cfg.RateLimit.StoreType),
 			KeyPrefix: "mcp",
+			MaxKeys:   cfg.RateLimit.MaxKeys,
+			Behavior:  ratelimit.Behavior(cfg.RateLimit.Behavior),
+			MaxWait:   cfg.RateLimit.MaxWait,
 		}
 
 		// Create store based on type
 		var store ratelimit.Store
 		switch rlConfig.StoreType {
 		case ratelimit.StoreMemory:
-			store = ratelimit.NewMemoryStore()
+			store = ratelimit.NewBoundedMemoryStore(rlConfig.MaxKeys)
 		case ratelimit.StoreNoOp:
 			store = ratelimit.NewNoOpStore()
 		}
@@ -703,6 +1700,28 @@ func init() {
 			}
 		}
 
+		// Configure the limits of tool classes
+		for class, classCfg := range cfg.RateLimit.Classes {
+			if classCfg.Enabled {
+				rlClassConfig := &ratelimit.ToolConfig{
+					Enabled: classCfg.Enabled,
+					Limit:   classCfg.Limit,
+					Window:  classCfg.Window,
+				}
+				if err := rateLimiter.SetClassConfig(ratelimit.ToolClass(class), rlClassConfig); err != nil {
+					logger.ErrorEvent().
+						Str("class", class).
+						Err(err).
+						Msg("failed to set tool class rate limit config")
+				}
+				logger.InfoEvent().
+					Str("class", class).
+					Int("limit", classCfg.Limit).
+					Dur("window", classCfg.Window).
+					Msg("tool class rate limit configured")
+			}
+		}
+
 		// Create middleware
 		rateLimitMiddleware = ratelimit.NewMiddleware(rateLimiter, logger)
 
@@ -768,6 +1787,67 @@ func init() {
 			Msg("retry wrappers initialized")
 	}
 
+	// Initialize response caches
+	if cfg.Cache.Enabled {
+		codeReviewCache = cache.New[middleware.CachedResponse[*codereview.ReviewResult]](cfg.Cache.TTL, cfg.Cache.MaxEntries)
+		testGenCache = cache.New[middleware.CachedResponse[*testgen.TestGenResult]](cfg.Cache.TTL, cfg.Cache.MaxEntries)
+		logger.InfoEvent().
+			Dur("ttl", cfg.Cache.TTL).
+			Int("max_entries", cfg.Cache.MaxEntries).
+			Msg("response caches initialized")
+	}
+
+	// Initialize the store for results of calls with an idempotency key
+	if cfg.Idempotency.Enabled {
+		idempotencyStore = middleware.NewIdempotencyStore(cfg.Idempotency.TTL, cfg.Idempotency.MaxEntries)
+		logger.InfoEvent().
+			Dur("ttl", cfg.Idempotency.TTL).
+			Int("max_entries", cfg.Idempotency.MaxEntries).
+			Msg("idempotency store initialized")
+	}
+
+	// Initialize the store for large outputs returned as artifact IDs
+	if cfg.Artifacts.Enabled {
+		artifactStore = artifact.NewStore(cfg.Artifacts.TTL, cfg.Artifacts.MaxEntries, cfg.Artifacts.Threshold)
+		logger.InfoEvent().
+			Dur("ttl", cfg.Artifacts.TTL).
+			Int("max_entries", cfg.Artifacts.MaxEntries).
+			Int("threshold", cfg.Artifacts.Threshold).
+			Msg("artifact store initialized")
+	}
+
+	// Initialize the score history of workspace reviews
+	if cfg.Workspace.HistoryDir != "" {
+		reviewHistory = history.NewStore(cfg.Workspace.HistoryDir, cfg.Workspace.HistoryLimit)
+		logger.InfoEvent().
+			Str("dir", cfg.Workspace.HistoryDir).
+			Int("limit", cfg.Workspace.HistoryLimit).
+			Msg("review history initialized")
+	}
+
+	// Initialize per-tool concurrency queues
+	if cfg.Concurrency.Enabled {
+		toolQueues = make(map[string]*queue.Limiter)
+		for _, tool := range []string{toolGoDoc, toolCodeReview, toolCodeReviewBatch, toolTestGen, toolModReview, toolGenerateMakefile, toolScaffold, toolStackTrace, toolProfileSummary, toolEscapeAnalysis, toolBuildConstraints, toolImplementations, toolCallGraph, toolStructSchema, toolJSONToStruct, toolSQLToGo, toolGRPCReview} {
+			limiter, err := queue.NewLimiter(tool, cfg.Concurrency.ToQueueConfig(tool))
+			if err != nil {
+				logger.FatalEvent().Err(err).Msg("failed to initialize concurrency queue")
+			}
+			limiter.OnDepthChange = func(depth int) {
+				metricsCol.SetQueueDepth(tool, depth)
+			}
+			toolQueues[tool] = limiter
+		}
+		logger.InfoEvent().
+			Int("max_concurrent", cfg.Concurrency.MaxConcurrent).
+			Int("queue_size", cfg.Concurrency.QueueSize).
+			Dur("max_wait", cfg.Concurrency.MaxWait).
+			Msg("concurrency queues initialized")
+	}
+
+	// Defaults set by clients with configure-session, kept per session
+	sessionStore = session.NewStore()
+
 	// Initialize shutdown channel
 	shutdownChan = make(chan os.Signal, 1)
 }
@@ -776,6 +1856,7 @@ func main() {
 	// Parse command line flags
 	flag.BoolVar(&showVersion, "version", false, "Show version information and exit")
 	flag.BoolVar(&showFullVersion, "version-full", false, "Show detailed version information and exit")
+	flag.Usage = usage
 	flag.Parse()
 
 	// Show version if requested
@@ -788,33 +1869,46 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Subcommands inspect the configuration without starting the server
+	if code, ok := runCommand(flag.Args(), os.Stdout, os.Stderr); ok {
+		os.Exit(code)
+	}
+
+	setup()
+
 	// Setup graceful shutdown
 	setupGracefulShutdown()
 
+	build := versionpkg.GetVersionInfo()
 	logger.InfoEvent().
-		Str("version", cfg.Server.Version).
+		Str("version", build.Version).
+		Str("commit", build.GitCommit).
+		Str("build_time", build.BuildTime).
 		Str("name", cfg.Server.Name).
+		Str("profile", cfg.Profile).
 		Msg("starting MCP server")
 
-	server := mcp.NewServer(&mcp.Implementation{
-		Name:    cfg.Server.Name,
-		Version: cfg.Server.Version,
-	}, nil)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        toolGoDoc,
-		Description: "Get Go documentation for packages and symbols using 'go doc' command",
-	}, GoDocTool)
+	// Save metrics periodically and once more on exit
+	if metricsPersister != nil {
+		metricsPersister.Start()
+		defer metricsPersister.Stop()
+	}
 
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        toolCodeReview,
-		Description: "Analyze Go code and provide improvement suggestions based on best practices",
-	}, CodeReviewTool)
+	// Verify the environment before accepting requests
+	healthChecker = health.New(build.Version)
+	healthChecker.SetMetadata("commit", build.GitCommit)
+	healthChecker.SetMetadata("build_time", build.BuildTime)
+	if cfg.Profile != "" {
+		healthChecker.SetMetadata("profile", cfg.Profile)
+	}
+	for _, p := range cfg.Review.RulePacks {
+		healthChecker.SetMetadata("rule_pack."+p.Name, p.Version+" sha256:"+p.SHA256)
+	}
+	if cfg.Preflight.Enabled {
+		runPreflight()
+	}
 
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        toolTestGen,
-		Description: "Generate Go test scaffolding including interfaces, mocks, and table-driven tests. Use focus='interfaces' for interface extraction and mocks, 'table' for table-driven tests, or 'unit' for basic unit tests.",
-	}, TestGenTool)
+	server, deps := newServer(build.Version)
 
 	logger.InfoEvent().Msg("MCP server ready")
 
@@ -825,9 +1919,25 @@ func main() {
 	// Run server in a goroutine
 	serverErr := make(chan error, 1)
 	go func() {
-		serverErr <- server.Run(ctx, &mcp.StdioTransport{})
+		serverErr <- serve(ctx, server)
 	}()
 
+	if cfg.GRPC.Enabled {
+		go func() {
+			if err := serveGRPC(ctx, deps); err != nil {
+				logger.FatalEvent().Err(err).Msg("gRPC API error")
+			}
+		}()
+	}
+
+	if cfg.PRReview.Enabled() {
+		go func() {
+			if err := servePRReview(ctx, deps); err != nil {
+				logger.FatalEvent().Err(err).Msg("pull request review error")
+			}
+		}()
+	}
+
 	// Wait for shutdown signal or server error
 	select {
 	case err := <-serverErr:
@@ -858,6 +1968,313 @@ func main() {
 	}
 }
 
+// newServer creates the MCP server with every enabled tool registered
+// behind the shared middleware stack, returning the stack's dependencies
+// for the other APIs serving the same tools
+func newServer(version string) (*mcp.Server, *middleware.Dependencies) {
+	server := mcp.NewServer(&mcp.Implementation{
+		Name:    cfg.Server.Name,
+		Version: version,
+	}, nil)
+
+	// Every tool runs behind the shared middleware stack
+	deps := &middleware.Dependencies{
+		Logger:      logger,
+		Metrics:     metricsCol,
+		RateLimiter: rateLimitMiddleware,
+		HandleError: LogAndHandleError,
+		Validator:   validator,
+		Idempotency: idempotencyStore,
+		Sessions:    sessionStore,
+		Artifacts:   artifactStore,
+
+		LatencyBudgets:      cfg.Metrics.LatencyBudgets,
+		LargeInputThreshold: cfg.Tools.LargeInputThreshold,
+	}
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolGoDoc,
+		Description: "Get Go documentation for packages and symbols using 'go doc' command, as full documentation or a compact API summary, optionally with runnable examples and their expected output",
+	}, middleware.Wrap(deps, goDocSpec(), GoDocTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolCodeReview,
+		Description: "Analyze Go code and provide improvement suggestions based on best practices",
+	}, middleware.Wrap(deps, codeReviewSpec(), CodeReviewTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolCodeReviewBatch,
+		Description: "Review multiple named Go snippets or files concurrently and return per-item results with a combined summary and worst score",
+	}, middleware.Wrap(deps, codeReviewBatchSpec(), CodeReviewBatchTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolTestGen,
+		Description: "Generate Go test scaffolding including interfaces, mocks, and table-driven tests. Use focus='interfaces' for interface extraction and mocks, 'table' for table-driven tests, or 'unit' for basic unit tests. Test and mock code are returned as separate content blocks named by their suggested files; set write_files with a working_dir inside a registered workspace to write them there.",
+	}, middleware.Wrap(deps, testGenSpec(), TestGenTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolModReview,
+		Description: "Review go.mod and go.sum in a working directory for toolchain mismatches, local replace directives, retracted versions, duplicate major versions and unused requirements",
+	}, middleware.Wrap(deps, modReviewSpec(), ModReviewTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolGenerateMakefile,
+		Description: "Inspect a Go project (cmd/ directories, tests, Dockerfile, lint and release configs) and generate a Makefile or Taskfile with build, test, lint and release targets",
+	}, middleware.Wrap(deps, generateMakefileSpec(), GenerateMakefileTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolScaffold,
+		Description: "Generate a new Go project layout (go.mod, cmd/<name>, internal packages, config loading and logging setup) returned as a map of file paths to contents",
+	}, middleware.Wrap(deps, scaffoldSpec(), ScaffoldTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolStackTrace,
+		Description: "Analyze a pasted Go panic or fatal error: identify the panicking goroutine, the frames in your module (given working_dir) and the likely cause such as a nil dereference, index out of range or concurrent map writes, with structured frames and remediation suggestions",
+	}, middleware.Wrap(deps, stackTraceSpec(), StackTraceTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolProfileSummary,
+		Description: "Summarize a pprof profile (base64-encoded or a file inside a workspace root) as the top functions by flat or cumulative CPU time or allocated bytes, with percentages of the total",
+	}, middleware.Wrap(deps, profileSummarySpec(), ProfileSummaryTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolEscapeAnalysis,
+		Description: "Run the compiler's escape analysis (go build -gcflags=-m=2) on a workspace package and report heap allocations, leaking parameters and optionally inlining decisions with positions and reasons, linked to code-review performance issues in the same functions",
+	}, middleware.Wrap(deps, escapeAnalysisSpec(), EscapeAnalysisTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolBuildConstraints,
+		Description: "Inspect //go:build constraints and GOOS/GOARCH file name suffixes across a workspace, report which files compile for which platforms of a GOOS/GOARCH matrix, and flag files excluded from every platform and tags that are likely typos",
+	}, middleware.Wrap(deps, buildConstraintsSpec(), BuildConstraintsTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolImplementations,
+		Description: "List the workspace types implementing an interface, named (e.g. 'store.Store', 'io.Reader') or pasted, by type-checking the workspace, with near misses that lack or mismatch a few of its methods and the methods to add or fix",
+	}, middleware.Wrap(deps, implementationsSpec(), ImplementationsTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolCallGraph,
+		Description: "Extract a bounded static call graph (CHA or RTA over the SSA form) from a workspace function, following its callees or callers up to a depth limit, as an adjacency list of package-qualified functions with call-site lines and dynamic calls marked",
+	}, middleware.Wrap(deps, callGraphSpec(), CallGraphTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolStructSchema,
+		Description: "Generate a JSON Schema (2020-12) document or OpenAPI 3.1 component schemas from Go struct definitions as encoding/json encodes them, honoring json tags, omitempty, embedded structs, pointers as nullable values and enums from typed const blocks",
+		// Schemas nest schemas, a cycle the SDK cannot infer a schema for
+		OutputSchema: &jsonschema.Schema{Type: "object"},
+	}, middleware.Wrap(deps, structSchemaSpec(), StructSchemaTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolJSONToStruct,
+		Description: "Convert a JSON or YAML sample into Go struct definitions with json/yaml tags, Go or camel-case field naming, pointers or values for fields that are null or missing from some array elements, time.Time for RFC 3339 timestamps and shared types for identical objects",
+	}, middleware.Wrap(deps, jsonToStructSpec(), JSONToStructTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolSQLToGo,
+		Description: "Generate Go model structs from CREATE TABLE statements (PostgreSQL, MySQL or SQLite) with db/json tags, sql.Null types or pointers for nullable columns, enum types from CREATE TYPE ... AS ENUM and optional sqlc-style Get, List, Create and Delete query methods",
+	}, middleware.Wrap(deps, sqlToGoSpec(), SQLToGoTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolGRPCReview,
+		Description: "Review the gRPC service implementations of a workspace against the interfaces protoc-gen-go-grpc generated in its .pb.go files, reporting unimplemented RPCs, missing Unimplemented embeds, handlers that ignore or replace the request context, and errors clients receive as codes.Unknown or status errors with codes.OK",
+	}, middleware.Wrap(deps, grpcReviewSpec(), GRPCReviewTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolHealth,
+		Description: "Report server health, including the startup preflight results (go toolchain, documentation cache, rate-limit store), memory usage and overall status",
+	}, middleware.Wrap(deps, healthSpec(), HealthTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolServerStats,
+		Description: "Report server statistics as JSON without a Prometheus scraper: uptime, calls and error rate per tool, cache hit rate, active requests and circuit breaker states",
+	}, middleware.Wrap(deps, serverStatsSpec(), ServerStatsTool))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        toolConfigureSession,
+		Description: "Set defaults for the rest of this session (working_dir, guidelines_file, review language, audience, output_format and thresholds) that later calls use when they leave those parameters out; returns the defaults in effect",
+	}, middleware.Wrap(deps, configureSessionSpec(), ConfigureSessionTool))
+
+	if artifactStore != nil {
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        toolGetArtifact,
+			Description: "Fetch the full content of a large code-review or test-gen output that was returned as a summary with an artifact ID; artifacts expire after a while",
+		}, middleware.Wrap(deps, getArtifactSpec(), GetArtifactTool))
+
+		server.AddResourceTemplate(&mcp.ResourceTemplate{
+			Name:        "artifact",
+			URITemplate: artifact.URITemplate,
+			Description: "Full content of a large tool output returned as an artifact ID",
+		}, artifactStore.ReadResource)
+	}
+
+	if reviewHistory != nil {
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        toolReviewTrend,
+			Description: "Report how the code-review scores of a workspace changed over its recent working_dir reviews: the score of each run, per-package score history, and the issues the latest run found new or no longer found compared with the run before",
+		}, middleware.Wrap(deps, reviewTrendSpec(), ReviewTrendTool))
+	}
+
+	return server, deps
+}
+
+// serve runs server on the configured transport until ctx is done or, for
+// stdio, the client disconnects
+func serve(ctx context.Context, server *mcp.Server) error {
+	if cfg.Server.Transport != config.TransportUnix {
+		return server.Run(ctx, &mcp.StdioTransport{})
+	}
+
+	ln, err := daemon.Listen(cfg.Server.SocketPath)
+	if err != nil {
+		return err
+	}
+	logger.InfoEvent().
+		Str("address", ln.Addr().String()).
+		Int("max_clients", cfg.Server.MaxClients).
+		Msg("daemon listening")
+	return daemon.Serve(ctx, ln, server, daemon.Options{
+		MaxClients: cfg.Server.MaxClients,
+		Logger:     logger,
+	})
+}
+
+// serveGRPC serves go-doc, code-review and test-gen over gRPC on the
+// configured address until ctx is done, behind the same middleware stack
+// as the MCP tools
+func serveGRPC(ctx context.Context, deps *middleware.Dependencies) error {
+	ln, err := net.Listen("tcp", cfg.GRPC.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.GRPC.Address, err)
+	}
+
+	// gRPC clients cannot call get-artifact, so outputs are returned in full
+	grpcDeps := *deps
+	grpcDeps.Artifacts = nil
+	server := grpcapi.NewServer(grpcapi.Handlers{
+		GoDoc:      middleware.Wrap(&grpcDeps, goDocSpec(), GoDocTool),
+		CodeReview: middleware.Wrap(&grpcDeps, codeReviewSpec(), CodeReviewTool),
+		TestGen:    middleware.Wrap(&grpcDeps, testGenSpec(), TestGenTool),
+	})
+	stop := context.AfterFunc(ctx, server.GracefulStop)
+	defer stop()
+
+	logger.InfoEvent().Str("address", ln.Addr().String()).Msg("gRPC API listening")
+	return server.Serve(ln)
+}
+
+// servePRReview receives the webhooks of the enabled code hosts on the
+// configured address until ctx is done and reviews the pull requests they
+// name with the code-review tool, behind the same middleware stack as the
+// MCP tools
+func servePRReview(ctx context.Context, deps *middleware.Dependencies) error {
+	providers, err := prReviewProviders()
+	if err != nil {
+		return err
+	}
+
+	reviewer, err := prreview.New(prreview.Options{
+		FailOn:    cfg.PRReview.FailOn,
+		Workers:   cfg.PRReview.Workers,
+		QueueSize: cfg.PRReview.QueueSize,
+		Logger:    logger,
+		Review:    codeReviewFunc(deps),
+	})
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", cfg.PRReview.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.PRReview.Address, err)
+	}
+	mux := http.NewServeMux()
+	for path, provider := range providers {
+		mux.Handle(path, reviewer.Handler(provider))
+		logger.InfoEvent().Str("provider", provider.Name()).Str("path", path).Msg("pull request webhook registered")
+	}
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	stop := context.AfterFunc(ctx, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Timeouts.Shutdown)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	})
+	defer stop()
+	go reviewer.Run(ctx)
+
+	logger.InfoEvent().Str("address", ln.Addr().String()).Msg("pull request review webhooks listening")
+	if err := server.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// codeReviewFunc returns a function running the code-review tool behind the
+// middleware stack of deps for frontends that use the structured result
+func codeReviewFunc(deps *middleware.Dependencies) func(context.Context, codereview.CodeReviewParams) (*codereview.ReviewResult, error) {
+	// Frontends get the structured result, not artifact IDs
+	reviewDeps := *deps
+	reviewDeps.Artifacts = nil
+	codeReview := middleware.Wrap(&reviewDeps, codeReviewSpec(), CodeReviewTool)
+	return func(ctx context.Context, params codereview.CodeReviewParams) (*codereview.ReviewResult, error) {
+		result, review, err := codeReview(ctx, nil, params)
+		if err := toolError(result, err); err != nil {
+			return nil, err
+		}
+		if review == nil {
+			return nil, fmt.Errorf("code review returned no result")
+		}
+		return review, nil
+	}
+}
+
+// prReviewProviders returns the enabled code host providers keyed by the
+// path of their webhook
+func prReviewProviders() (map[string]prreview.Provider, error) {
+	providers := map[string]prreview.Provider{}
+	if c := cfg.PRReview.GitHub; c.Enabled {
+		key, err := os.ReadFile(c.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+		}
+		p, err := github.New(github.Options{
+			APIURL:        c.APIURL,
+			AppID:         c.AppID,
+			PrivateKey:    key,
+			WebhookSecret: c.WebhookSecret,
+			CheckName:     c.CheckName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub settings: %w", err)
+		}
+		providers[c.Path] = p
+	}
+	if c := cfg.PRReview.GitLab; c.Enabled {
+		p, err := gitlab.New(gitlab.Options{
+			URL:           c.URL,
+			Token:         c.Token,
+			WebhookSecret: c.WebhookSecret,
+			StatusName:    c.StatusName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitLab settings: %w", err)
+		}
+		providers[c.Path] = p
+	}
+	if c := cfg.PRReview.Bitbucket; c.Enabled {
+		p, err := bitbucket.New(bitbucket.Options{
+			APIURL:        c.APIURL,
+			Token:         c.Token,
+			WebhookSecret: c.WebhookSecret,
+			ReportID:      c.ReportID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid Bitbucket settings: %w", err)
+		}
+		providers[c.Path] = p
+	}
+	return providers, nil
+}
+
 // setupGracefulShutdown sets up signal handling for graceful shutdown
 func setupGracefulShutdown() {
 	signal.Notify(shutdownChan,
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-go-assistant/internal/config"
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/metrics"
+)
+
+// TestMain sets up the process-wide state of the server once, since
+// initialize registers the metrics
+func TestMain(m *testing.M) {
+	historyDir, err := os.MkdirTemp("", "review-history")
+	if err != nil {
+		panic(err)
+	}
+	loaded := config.DefaultConfig()
+	loaded.Logging.Level = "fatal"
+	loaded.Logging.OutputPath = "stderr"
+	loaded.Artifacts.Enabled = true
+	loaded.Workspace.HistoryDir = historyDir
+	initialize(loaded)
+
+	code := m.Run()
+	os.RemoveAll(historyDir)
+	os.Exit(code)
+}
+
+// TestNewServer_RegistersEveryTool registers every tool as the server does
+// on startup, so tools the SDK cannot infer schemas for fail here rather
+// than when the binary starts
+func TestNewServer_RegistersEveryTool(t *testing.T) {
+	ctx := context.Background()
+	cs := connect(t)
+
+	registered := make(map[string]bool)
+	for tool, err := range cs.Tools(ctx, nil) {
+		if err != nil {
+			t.Fatalf("Tools() error = %v", err)
+		}
+		registered[tool.Name] = true
+	}
+
+	want := []string{
+		toolGoDoc, toolCodeReview, toolCodeReviewBatch, toolTestGen, toolModReview,
+		toolGenerateMakefile, toolScaffold, toolStackTrace, toolProfileSummary,
+		toolEscapeAnalysis, toolBuildConstraints, toolImplementations, toolCallGraph,
+		toolStructSchema, toolJSONToStruct, toolSQLToGo, toolGRPCReview, toolHealth,
+		toolServerStats, toolConfigureSession, toolGetArtifact, toolReviewTrend,
+	}
+	for _, name := range want {
+		if !registered[name] {
+			t.Errorf("tool %s is not registered", name)
+		}
+	}
+	if len(registered) != len(want) {
+		t.Errorf("registered %d tools, want %d", len(registered), len(want))
+	}
+}
+
+// TestGoDocTool_PassesRequestID runs go-doc against a go command that
+// prints its environment, so the request ID must reach the subprocess
+func TestGoDocTool_PassesRequestID(t *testing.T) {
+	ctx := context.Background()
+	cs := connect(t)
+
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho \"request $" + logging.RequestIDEnv + "\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "go"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	result, err := cs.CallTool(ctx, &mcp.CallToolParams{
+		Name:      toolGoDoc,
+		Arguments: map[string]any{"package_path": "example.com/shapes"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError || len(result.Content) == 0 {
+		t.Fatalf("expected documentation, got %+v", result)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	requestID, ok := strings.CutPrefix(text, "request ")
+	if !ok || requestID == "" {
+		t.Errorf("expected the go command to see the request ID, got %q", text)
+	}
+}
+
+// TestServerStatsTool_ReportsEveryCircuitBreaker checks that server-stats
+// reports the breaker of every tool that has one
+func TestServerStatsTool_ReportsEveryCircuitBreaker(t *testing.T) {
+	_, stats, err := ServerStatsTool(context.Background(), nil, metrics.StatsParams{})
+	if err != nil {
+		t.Fatalf("ServerStatsTool() error = %v", err)
+	}
+
+	want := []string{
+		"godoc", "code-review", "test-gen", toolModReview, toolEscapeAnalysis,
+		toolImplementations, toolCallGraph, toolStructSchema, toolGRPCReview,
+	}
+	for _, name := range want {
+		if _, ok := stats.CircuitBreakers[name]; !ok {
+			t.Errorf("circuit breaker %s is not reported", name)
+		}
+	}
+	if len(stats.CircuitBreakers) != len(want) {
+		t.Errorf("reported %d circuit breakers, want %d", len(stats.CircuitBreakers), len(want))
+	}
+}
+
+// connect starts a server with every tool and returns a client session
+// connected to it in memory
+func connect(t *testing.T) *mcp.ClientSession {
+	t.Helper()
+
+	server, _ := newServer("test")
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { ss.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "test"}, nil)
+	cs, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client Connect() error = %v", err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
@@ -0,0 +1,574 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-go-assistant/internal/codereview"
+	"mcp-go-assistant/internal/config"
+	"mcp-go-assistant/internal/godoc"
+	"mcp-go-assistant/internal/lsp"
+	"mcp-go-assistant/internal/middleware"
+	"mcp-go-assistant/internal/testgen"
+	versionpkg "mcp-go-assistant/internal/version"
+	"mcp-go-assistant/internal/watch"
+)
+
+// Subcommands that run tools without the MCP transport
+const (
+	cmdReview  = "review"
+	cmdDoc     = "doc"
+	cmdTestGen = "testgen"
+	cmdLSP     = "lsp"
+)
+
+// Exit codes of the tool subcommands, stable for CI pipelines
+const (
+	exitPass     = 0
+	exitFindings = 1 // A review found issues at or above -fail-on
+	exitError    = 2 // Invalid arguments or configuration, or a tool call that failed
+)
+
+// formatSARIF is the review output format for code scanning services,
+// handled by the command rather than the tool
+const formatSARIF = "sarif"
+
+// reviewReport is the JSON report of the review subcommand
+type reviewReport struct {
+	FailOn   string         `json:"fail_on,omitempty"`
+	ExitCode int            `json:"exit_code"`
+	Files    []reviewedFile `json:"files"`
+}
+
+// reviewedFile is the review of a file, or why it could not be reviewed
+type reviewedFile struct {
+	Path   string                   `json:"path"`
+	Result *codereview.ReviewResult `json:"result,omitempty"`
+	Error  string                   `json:"error,omitempty"`
+}
+
+// runToolCommand runs the tool subcommand named by args[0] and returns its
+// exit code
+func runToolCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", os.Getenv("MCP_CONFIG"), "Configuration file; defaults to MCP_CONFIG or ./config.yaml")
+
+	var run func(ctx context.Context, deps *middleware.Dependencies) int
+	switch args[0] {
+	case cmdReview:
+		run = reviewCommand(fs, stdout, stderr)
+	case cmdDoc:
+		run = docCommand(fs, stdout, stderr)
+	case cmdTestGen:
+		run = testGenCommand(fs, stdout, stderr)
+	case cmdLSP:
+		run = lspCommand(fs, stdout, stderr)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitError
+	}
+
+	deps, err := toolDependencies(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid configuration: %v\n", err)
+		return exitError
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return run(ctx, deps)
+}
+
+// toolDependencies initializes the application from the configuration at
+// path like the server does and returns the middleware dependencies of the
+// tools. Logs go to stderr and only errors are logged, unless debugging, so
+// stdout only carries the tool output and stderr the reason a call failed.
+func toolDependencies(path string) (*middleware.Dependencies, error) {
+	loaded, err := config.LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if loaded.Logging.Level != "debug" && loaded.Logging.Level != "trace" {
+		loaded.Logging.Level = "error"
+	}
+	if loaded.Logging.OutputPath == "" || loaded.Logging.OutputPath == "stdout" {
+		loaded.Logging.OutputPath = "stderr"
+	}
+	initialize(loaded)
+
+	// A single call has no one to share limits with or to return
+	// artifact IDs to, and its errors are printed by the command
+	return &middleware.Dependencies{
+		Logger:    logger,
+		Metrics:   metricsCol,
+		Validator: validator,
+	}, nil
+}
+
+// reviewCommand defines the flags of the review subcommand on fs and
+// returns the function reviewing the files named by its arguments. Text
+// formats print a report per file; json and sarif print one document for
+// all files, which -json and -sarif also write to files. With -watch it
+// reviews the files modified under a directory instead, until interrupted.
+func reviewCommand(fs *flag.FlagSet, stdout, stderr io.Writer) func(context.Context, *middleware.Dependencies) int {
+	format := fs.String("format", codereview.OutputFormatCompact, "Output format: text, json, compact, markdown or sarif")
+	hint := fs.String("hint", "", "Focus area of the review")
+	guidelines := fs.String("guidelines", "", "Markdown file with coding guidelines")
+	language := fs.String("language", "", "Language of messages and summaries; defaults to the configured language")
+	failOn := fs.String("fail-on", "", "Lowest issue severity that makes the command exit with 1; empty never fails on issues")
+	jsonFile := fs.String("json", "", "Also write the JSON report to this file")
+	sarifFile := fs.String("sarif", "", "Also write a SARIF 2.1.0 log to this file")
+	watchDir := fs.String("watch", "", "Review the Go files modified under this directory as they change, until interrupted")
+	debounce := fs.Duration("debounce", watch.DefaultDelay, "With -watch, how long files must stay unchanged before they are reviewed")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] <file.go>...\n       %s %s -watch <dir> [flags]\n\nReview Go files with the code-review tool. Exits with 0 when the review\npasses, 1 when an issue reaches -fail-on and 2 when a file could not be\nreviewed.\n\nFlags:\n", os.Args[0], cmdReview, os.Args[0], cmdReview)
+		fs.PrintDefaults()
+	}
+
+	return func(ctx context.Context, deps *middleware.Dependencies) int {
+		if *watchDir != "" {
+			if fs.NArg() != 0 || *failOn != "" || *jsonFile != "" || *sarifFile != "" {
+				fmt.Fprintf(stderr, "-watch reviews the files modified under its directory and takes no files, -fail-on, -json or -sarif\n")
+				return exitError
+			}
+			switch strings.ToLower(*format) {
+			case codereview.OutputFormatText, codereview.OutputFormatCompact, codereview.OutputFormatMarkdown:
+			default:
+				fmt.Fprintf(stderr, "invalid -format with -watch: %s (valid: text, compact, markdown)\n", *format)
+				return exitError
+			}
+			return watchReviews(ctx, deps, *watchDir, *debounce, codereview.CodeReviewParams{
+				GuidelinesFile: *guidelines,
+				Hint:           *hint,
+				Language:       *language,
+				OutputFormat:   *format,
+			}, stdout, stderr)
+		}
+		if fs.NArg() == 0 {
+			fs.Usage()
+			return exitError
+		}
+		if *failOn != "" && !codereview.IsValidSeverity(*failOn) {
+			fmt.Fprintf(stderr, "invalid -fail-on severity: %s (valid: critical, high, medium, low)\n", *failOn)
+			return exitError
+		}
+		document := false
+		switch strings.ToLower(*format) {
+		case codereview.OutputFormatJSON, formatSARIF:
+			document = true
+		case codereview.OutputFormatText, codereview.OutputFormatCompact, codereview.OutputFormatMarkdown:
+		default:
+			fmt.Fprintf(stderr, "invalid -format: %s (valid: text, json, compact, markdown, sarif)\n", *format)
+			return exitError
+		}
+
+		review := middleware.Wrap(deps, codeReviewSpec(), CodeReviewTool)
+		report := reviewReport{FailOn: *failOn, ExitCode: exitPass, Files: []reviewedFile{}}
+		var issues []codereview.Issue
+		for _, path := range fs.Args() {
+			file, err := reviewFile(ctx, review, path, codereview.CodeReviewParams{
+				GuidelinesFile: *guidelines,
+				Hint:           *hint,
+				Language:       *language,
+			})
+			report.Files = append(report.Files, file)
+			if err != nil {
+				fmt.Fprintf(stderr, "%s: %v\n", path, err)
+				report.ExitCode = exitError
+				continue
+			}
+
+			issues = append(issues, file.Result.Issues...)
+			for _, issue := range file.Result.Issues {
+				if *failOn != "" && codereview.SeverityAtLeast(issue.Severity, *failOn) && report.ExitCode == exitPass {
+					report.ExitCode = exitFindings
+				}
+			}
+			if !document {
+				params := codereview.CodeReviewParams{Language: *language, OutputFormat: *format}
+				if params.Language == "" {
+					params.Language = cfg.Localization.Language
+				}
+				writeText(stdout, codereview.FormatResult(file.Result, params))
+			}
+		}
+
+		jsonReport, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintf(stderr, "%v\n", err)
+			return exitError
+		}
+		sarifLog, err := codereview.SARIF(issues, versionpkg.GetVersionInfo().Version)
+		if err != nil {
+			fmt.Fprintf(stderr, "%v\n", err)
+			return exitError
+		}
+		switch strings.ToLower(*format) {
+		case codereview.OutputFormatJSON:
+			writeText(stdout, string(jsonReport))
+		case formatSARIF:
+			writeText(stdout, string(sarifLog))
+		}
+		for path, data := range map[string][]byte{*jsonFile: jsonReport, *sarifFile: sarifLog} {
+			if path == "" {
+				continue
+			}
+			if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+				fmt.Fprintf(stderr, "%v\n", err)
+				return exitError
+			}
+		}
+		return report.ExitCode
+	}
+}
+
+// reviewFile reviews the Go file at path with params and returns the review
+// with its issues located in path
+func reviewFile(ctx context.Context, review mcp.ToolHandlerFor[codereview.CodeReviewParams, *codereview.ReviewResult], path string, params codereview.CodeReviewParams) (reviewedFile, error) {
+	file := reviewedFile{Path: path}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		file.Error = err.Error()
+		return file, err
+	}
+	params.GoCode = string(content)
+	// The command renders its own reports
+	params.OutputFormat = codereview.OutputFormatJSON
+
+	result, out, err := review(ctx, nil, params)
+	if err = toolError(result, err); err == nil && out == nil {
+		err = fmt.Errorf("the review returned no result")
+	}
+	if err != nil {
+		file.Error = err.Error()
+		return file, err
+	}
+
+	// Issues name their file so reports of several files stay apart
+	reviewed := *out
+	reviewed.Issues = make([]codereview.Issue, len(out.Issues))
+	for i, issue := range out.Issues {
+		issue.File = filepath.ToSlash(path)
+		reviewed.Issues[i] = issue
+	}
+	file.Result = &reviewed
+	return file, nil
+}
+
+// watchReviews reviews the Go files modified under dir with params until
+// ctx is done, printing each review under a line counting the issues it
+// found, added and resolved since the previous review of the file
+func watchReviews(ctx context.Context, deps *middleware.Dependencies, dir string, delay time.Duration, params codereview.CodeReviewParams, stdout, stderr io.Writer) int {
+	review := middleware.Wrap(deps, codeReviewSpec(), CodeReviewTool)
+	format := params.OutputFormat
+	if params.Language == "" {
+		params.Language = cfg.Localization.Language
+	}
+	previous := map[string][]string{}
+
+	return watchFiles(ctx, dir, delay, stderr, func(ctx context.Context, paths []string) {
+		for _, path := range paths {
+			file, err := reviewFile(ctx, review, path, params)
+			if err != nil {
+				// Code in the middle of an edit may not parse yet
+				fmt.Fprintf(stderr, "%s: %v\n", path, err)
+				continue
+			}
+
+			// The file may have changed again since the review; its next
+			// batch corrects the counts
+			content, _ := os.ReadFile(path)
+			keys := issueKeys(file.Result.Issues, string(content))
+			summary := fmt.Sprintf("%d issues", len(keys))
+			if last, ok := previous[path]; ok {
+				added, resolved := issueChanges(last, keys)
+				summary += fmt.Sprintf(" (%d new, %d resolved)", added, resolved)
+			}
+			previous[path] = keys
+			fmt.Fprintf(stdout, "==> %s %s: %s\n", time.Now().Format(time.TimeOnly), path, summary)
+			writeText(stdout, codereview.FormatResult(file.Result, codereview.CodeReviewParams{Language: params.Language, OutputFormat: format}))
+		}
+	})
+}
+
+// issueKeys identifies the issues of content by rule, message and the text
+// of their line rather than their position, so edits moving an issue do not
+// make it new
+func issueKeys(issues []codereview.Issue, content string) []string {
+	lines := strings.Split(content, "\n")
+	keys := make([]string, len(issues))
+	for i, issue := range issues {
+		text := ""
+		if issue.Line > 0 && issue.Line <= len(lines) {
+			text = strings.TrimSpace(lines[issue.Line-1])
+		}
+		keys[i] = issue.Rule + "\x00" + issue.Message + "\x00" + text
+	}
+	return keys
+}
+
+// issueChanges counts the issue keys of current missing from last and those
+// of last missing from current
+func issueChanges(last, current []string) (added, resolved int) {
+	seen := map[string]int{}
+	for _, key := range last {
+		seen[key]++
+	}
+	for _, key := range current {
+		if seen[key] > 0 {
+			seen[key]--
+		} else {
+			added++
+		}
+	}
+	for _, n := range seen {
+		resolved += n
+	}
+	return added, resolved
+}
+
+// watchFiles calls handle with the Go files modified under dir, batched
+// after they stayed unchanged for delay, until ctx is done
+func watchFiles(ctx context.Context, dir string, delay time.Duration, stderr io.Writer, handle func(ctx context.Context, paths []string)) int {
+	watcher, err := watch.New(watch.Options{Root: dir, Delay: delay, Logger: logger})
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitError
+	}
+	defer watcher.Close()
+
+	fmt.Fprintf(stderr, "watching %s for changes to Go files; press Ctrl+C to stop\n", dir)
+	if err := watcher.Run(ctx, handle); err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return exitError
+	}
+	return exitPass
+}
+
+// docCommand defines the flags of the doc subcommand on fs and returns the
+// function printing the documentation of the package and optional symbol
+// named by its arguments
+func docCommand(fs *flag.FlagSet, stdout, stderr io.Writer) func(context.Context, *middleware.Dependencies) int {
+	mode := fs.String("mode", godoc.ModeDoc, "Output mode: doc for full documentation or api for a compact API summary")
+	examples := fs.Bool("examples", false, "Include runnable examples and their expected output")
+	unexported := fs.Bool("unexported", false, "Include unexported symbols")
+	dir := fs.String("dir", "", "Module directory to resolve the package in")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] <package> [symbol]\n\nPrint Go documentation with the go-doc tool.\n\nFlags:\n", os.Args[0], cmdDoc)
+		fs.PrintDefaults()
+	}
+
+	return func(ctx context.Context, deps *middleware.Dependencies) int {
+		if fs.NArg() < 1 || fs.NArg() > 2 {
+			fs.Usage()
+			return exitError
+		}
+
+		result, _, err := middleware.Wrap(deps, goDocSpec(), GoDocTool)(ctx, nil, godoc.GoDocParams{
+			PackagePath:       fs.Arg(0),
+			SymbolName:        fs.Arg(1),
+			WorkingDir:        *dir,
+			Mode:              *mode,
+			IncludeExamples:   *examples,
+			IncludeUnexported: *unexported,
+		})
+		if err := toolError(result, err); err != nil {
+			fmt.Fprintf(stderr, "%v\n", err)
+			return exitError
+		}
+		writeText(stdout, contentText(result))
+		return exitPass
+	}
+}
+
+// testGenCommand defines the flags of the testgen subcommand on fs and
+// returns the function generating tests for the file named by its argument.
+// With -watch it generates tests for the files modified under a directory
+// instead, until interrupted, printing what their test files are missing.
+func testGenCommand(fs *flag.FlagSet, stdout, stderr io.Writer) func(context.Context, *middleware.Dependencies) int {
+	focus := fs.String("focus", "", "Focus area: interfaces, unit or table")
+	packageName := fs.String("package", "", "Package of the generated tests; defaults to the external _test package")
+	samePackage := fs.Bool("same-package", false, "Generate tests and mocks inside the source package")
+	mockPackage := fs.String("mock-package", "", "Package for mocks, e.g. mocks")
+	existing := fs.String("existing", "", "Existing test file; prints a diff adding only what is missing")
+	write := fs.Bool("write", false, "Write the generated files next to the source file instead of printing them; existing files are never overwritten")
+	watchDir := fs.String("watch", "", "Generate tests for the Go files modified under this directory as they change, until interrupted")
+	debounce := fs.Duration("debounce", watch.DefaultDelay, "With -watch, how long files must stay unchanged before tests are generated")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] <file.go>\n       %s %s -watch <dir> [flags]\n\nGenerate tests with the test-gen tool.\n\nFlags:\n", os.Args[0], cmdTestGen, os.Args[0], cmdTestGen)
+		fs.PrintDefaults()
+	}
+
+	// generate prints the tests generated for the file at path, as a diff
+	// against the test file at existingPath unless it is empty, or writes
+	// them with -write
+	generate := func(ctx context.Context, deps *middleware.Dependencies, path, existingPath string) error {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		params := testgen.TestGenParams{
+			GoCode:      string(content),
+			PackageName: *packageName,
+			Focus:       *focus,
+			SamePackage: *samePackage,
+			MockPackage: *mockPackage,
+		}
+		if existingPath != "" {
+			tests, err := os.ReadFile(existingPath)
+			if err != nil {
+				return err
+			}
+			params.ExistingTests = string(tests)
+			params.ExistingTestsFile = filepath.Base(existingPath)
+		}
+		if *write {
+			dir, err := filepath.Abs(filepath.Dir(path))
+			if err != nil {
+				return err
+			}
+			// Running the command with -write is the confirmation
+			params.WriteFiles = true
+			params.WorkingDir = dir
+			params.Confirm = true
+		}
+
+		result, out, err := middleware.Wrap(deps, testGenSpec(), TestGenTool)(ctx, nil, params)
+		if err := toolError(result, err); err != nil {
+			return err
+		}
+		if out == nil {
+			writeText(stdout, contentText(result))
+			return nil
+		}
+		if *write {
+			for _, written := range out.Written {
+				fmt.Fprintf(stdout, "wrote %s\n", written)
+			}
+			return nil
+		}
+		writeText(stdout, out.String())
+		return nil
+	}
+
+	return func(ctx context.Context, deps *middleware.Dependencies) int {
+		if *watchDir != "" {
+			if fs.NArg() != 0 || *existing != "" || *write {
+				fmt.Fprintf(stderr, "-watch generates tests for the files modified under its directory and takes no file, -existing or -write\n")
+				return exitError
+			}
+			return watchFiles(ctx, *watchDir, *debounce, stderr, func(ctx context.Context, paths []string) {
+				for _, path := range watchedSources(paths) {
+					existingPath := strings.TrimSuffix(path, ".go") + "_test.go"
+					if _, err := os.Stat(existingPath); err != nil {
+						existingPath = ""
+					}
+					fmt.Fprintf(stdout, "==> %s %s\n", time.Now().Format(time.TimeOnly), path)
+					if err := generate(ctx, deps, path, existingPath); err != nil {
+						fmt.Fprintf(stderr, "%s: %v\n", path, err)
+					}
+				}
+			})
+		}
+		if fs.NArg() != 1 {
+			fs.Usage()
+			return exitError
+		}
+		if err := generate(ctx, deps, fs.Arg(0), *existing); err != nil {
+			fmt.Fprintf(stderr, "%v\n", err)
+			return exitError
+		}
+		return exitPass
+	}
+}
+
+// watchedSources returns the source files to generate tests for when paths
+// were modified: a test file stands for the source file it tests, so
+// editing tests shows what they still miss
+func watchedSources(paths []string) []string {
+	var sources []string
+	seen := map[string]bool{}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			path = strings.TrimSuffix(path, "_test.go") + ".go"
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+		}
+		if !seen[path] {
+			seen[path] = true
+			sources = append(sources, path)
+		}
+	}
+	return sources
+}
+
+// lspCommand defines the flags of the lsp subcommand on fs and returns the
+// function serving review diagnostics to an editor over stdin and stdout
+func lspCommand(fs *flag.FlagSet, stdout, stderr io.Writer) func(context.Context, *middleware.Dependencies) int {
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags]\n\nServe code-review diagnostics to an editor over the Language Server\nProtocol on stdin and stdout.\n\nFlags:\n", os.Args[0], cmdLSP)
+		fs.PrintDefaults()
+	}
+
+	return func(ctx context.Context, deps *middleware.Dependencies) int {
+		if fs.NArg() != 0 {
+			fs.Usage()
+			return exitError
+		}
+		server, err := lsp.New(lsp.Options{
+			Review:  codeReviewFunc(deps),
+			Logger:  logger,
+			Version: versionpkg.GetVersionInfo().Version,
+		})
+		if err == nil {
+			err = server.Serve(ctx, os.Stdin, stdout)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "%v\n", err)
+			return exitError
+		}
+		return exitPass
+	}
+}
+
+// toolError returns the error of a failed tool call, whether returned or
+// reported in the result
+func toolError(result *mcp.CallToolResult, err error) error {
+	if err != nil {
+		return err
+	}
+	if result != nil && result.IsError {
+		return fmt.Errorf("%s", contentText(result))
+	}
+	return nil
+}
+
+// contentText joins the text content of a tool result
+func contentText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, c := range result.Content {
+		if text, ok := c.(*mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// writeText writes text to w, ending it with a newline
+func writeText(w io.Writer, text string) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	_, _ = io.WriteString(w, text)
+}
@@ -0,0 +1,102 @@
+// Package artifact stores large tool outputs under content-hash IDs, so
+// responses can carry a summary and an ID instead of the full output
+package artifact
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"mcp-go-assistant/internal/cache"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// URIPrefix starts the resource URI of an artifact, e.g. artifact://3f2a...
+const URIPrefix = "artifact://"
+
+// URITemplate matches the resource URIs of artifacts
+const URITemplate = URIPrefix + "{id}"
+
+// idLength is the number of hex digits of the SHA-256 content hash kept in IDs
+const idLength = 32
+
+// Artifact is a stored tool output
+type Artifact struct {
+	ID       string    `json:"id"`
+	URI      string    `json:"uri"`
+	Tool     string    `json:"tool"` // Tool that produced the output
+	MIMEType string    `json:"mime_type"`
+	Size     int       `json:"size"` // Content length in bytes
+	Expires  time.Time `json:"expires"`
+	Content  string    `json:"-"` // Returned as text content rather than in the structured result
+}
+
+// GetParams represents the parameters for the get-artifact tool
+type GetParams struct {
+	ID string `json:"id" jsonschema:"description:Artifact ID or artifact:// URI returned in place of a large output"`
+}
+
+// Store keeps artifacts in memory until they expire or are evicted
+type Store struct {
+	artifacts *cache.Cache[Artifact]
+	ttl       time.Duration
+	threshold int
+	now       func() time.Time
+}
+
+// NewStore creates a store keeping at most maxEntries artifacts for ttl
+// each. Outputs larger than threshold bytes are meant to be stored.
+func NewStore(ttl time.Duration, maxEntries, threshold int) *Store {
+	return &Store{
+		artifacts: cache.New[Artifact](ttl, maxEntries),
+		ttl:       ttl,
+		threshold: threshold,
+		now:       time.Now,
+	}
+}
+
+// Threshold returns the size in bytes above which outputs are stored
+func (s *Store) Threshold() int {
+	return s.threshold
+}
+
+// Put stores content and returns its artifact. The ID is derived from the
+// content, so storing the same output again returns the same ID with a new
+// expiry.
+func (s *Store) Put(tool, mimeType, content string) Artifact {
+	id := cache.Hash(content)[:idLength]
+	a := Artifact{
+		ID:       id,
+		URI:      URI(id),
+		Tool:     tool,
+		MIMEType: mimeType,
+		Size:     len(content),
+		Expires:  s.now().Add(s.ttl),
+		Content:  content,
+	}
+	s.artifacts.Set(id, a)
+	return a
+}
+
+// Get returns the artifact with the given ID or URI if it has not expired
+func (s *Store) Get(id string) (Artifact, bool) {
+	return s.artifacts.Get(strings.TrimPrefix(id, URIPrefix))
+}
+
+// URI returns the resource URI of the artifact with the given ID
+func URI(id string) string {
+	return URIPrefix + id
+}
+
+// ReadResource serves artifacts as resources, for clients that read the
+// artifact:// links of responses instead of calling get-artifact
+func (s *Store) ReadResource(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	a, ok := s.Get(req.Params.URI)
+	if !ok || !strings.HasPrefix(req.Params.URI, URIPrefix) {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: a.URI, MIMEType: a.MIMEType, Text: a.Content}},
+	}, nil
+}
@@ -0,0 +1,76 @@
+package artifact
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestStore_PutGet(t *testing.T) {
+	store := NewStore(time.Minute, 10, 100)
+
+	a := store.Put("code-review", "application/json", `{"score":90}`)
+	if len(a.ID) != idLength || a.URI != URIPrefix+a.ID {
+		t.Errorf("unexpected ID %q and URI %q", a.ID, a.URI)
+	}
+	if a.Size != len(`{"score":90}`) || a.Tool != "code-review" {
+		t.Errorf("unexpected artifact %+v", a)
+	}
+
+	for _, id := range []string{a.ID, a.URI} {
+		got, ok := store.Get(id)
+		if !ok || got.Content != `{"score":90}` {
+			t.Errorf("Get(%q) = %+v, %v", id, got, ok)
+		}
+	}
+	if _, ok := store.Get("missing"); ok {
+		t.Error("expected no artifact for an unknown ID")
+	}
+
+	// The same content gets the same ID, other content another one
+	if again := store.Put("code-review", "application/json", `{"score":90}`); again.ID != a.ID {
+		t.Errorf("expected the same ID for the same content, got %q and %q", a.ID, again.ID)
+	}
+	if other := store.Put("code-review", "application/json", `{"score":80}`); other.ID == a.ID {
+		t.Error("expected another ID for other content")
+	}
+}
+
+func TestStore_Expiry(t *testing.T) {
+	store := NewStore(20*time.Millisecond, 10, 100)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	a := store.Put("test-gen", "text/x-go", "package x_test")
+	if !a.Expires.Equal(now.Add(20 * time.Millisecond)) {
+		t.Errorf("Expires = %v, want %v", a.Expires, now.Add(20*time.Millisecond))
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := store.Get(a.ID); ok {
+		t.Error("expected the artifact to expire after the ttl")
+	}
+}
+
+func TestStore_ReadResource(t *testing.T) {
+	store := NewStore(time.Minute, 10, 100)
+	a := store.Put("test-gen", "text/x-go", "package x_test")
+
+	res, err := store.ReadResource(context.Background(), &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: a.URI}})
+	if err != nil {
+		t.Fatalf("ReadResource() error = %v", err)
+	}
+	if len(res.Contents) != 1 || res.Contents[0].Text != "package x_test" || res.Contents[0].MIMEType != "text/x-go" {
+		t.Errorf("unexpected contents %+v", res.Contents)
+	}
+
+	for _, uri := range []string{URIPrefix + "missing", a.ID, "file:///" + a.ID} {
+		_, err := store.ReadResource(context.Background(), &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: uri}})
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("ReadResource(%q) error = %v, want not found", uri, err)
+		}
+	}
+}
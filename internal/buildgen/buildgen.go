@@ -0,0 +1,346 @@
+package buildgen
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// releasePlatforms are the GOOS/GOARCH pairs built by the release target
+// when the project has no goreleaser configuration
+var releasePlatforms = []string{"linux/amd64", "linux/arm64", "darwin/amd64", "darwin/arm64", "windows/amd64"}
+
+// GenerateBuildFile inspects a Go project and generates a Makefile or
+// Taskfile with build, test, lint and release targets
+func GenerateBuildFile(ctx context.Context, params BuildGenParams) (*BuildGenResult, error) {
+	if params.WorkingDir == "" {
+		return nil, fmt.Errorf("working_dir parameter is required")
+	}
+
+	format := strings.ToLower(params.Format)
+	if format == "" {
+		format = FormatMakefile
+	}
+	if format != FormatMakefile && format != FormatTaskfile {
+		return nil, fmt.Errorf("unsupported format: %s (supported: makefile, taskfile)", params.Format)
+	}
+
+	layout, err := DetectLayout(ctx, params.WorkingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BuildGenResult{
+		Format:      format,
+		Layout:      *layout,
+		Suggestions: []string{},
+	}
+
+	switch format {
+	case FormatTaskfile:
+		result.FileName = "Taskfile.yml"
+		result.Content = renderTaskfile(layout)
+	default:
+		result.FileName = "Makefile"
+		result.Content = RenderMakefile(layout)
+	}
+
+	if !layout.HasTests {
+		result.Suggestions = append(result.Suggestions, "No _test.go files found. Consider adding tests; the test target will still run `go test ./...`.")
+	}
+	if !layout.HasGolangCI {
+		result.Suggestions = append(result.Suggestions, "No golangci-lint configuration found; the lint target falls back to gofmt and go vet.")
+	}
+	if len(layout.Binaries) == 0 {
+		result.Suggestions = append(result.Suggestions, "No main packages found; build and release targets compile the library only.")
+	}
+
+	return result, nil
+}
+
+// DetectLayout inspects the project rooted at dir
+func DetectLayout(ctx context.Context, dir string) (*ProjectLayout, error) {
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("go.mod not found in %s", dir)
+	}
+
+	layout := &ProjectLayout{Binaries: []Binary{}}
+	for _, line := range strings.Split(string(goMod), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			layout.ModulePath = strings.Trim(fields[1], `"`)
+		case "go":
+			layout.GoVersion = fields[1]
+		}
+	}
+	if layout.ModulePath == "" {
+		return nil, fmt.Errorf("go.mod in %s has no module directive", dir)
+	}
+
+	// A main package at the root is named after the module
+	if isMainPackage(dir) {
+		layout.Binaries = append(layout.Binaries, Binary{Name: path.Base(layout.ModulePath), Path: "."})
+	}
+
+	// Each cmd/<name> directory holding a main package is a binary
+	if entries, err := os.ReadDir(filepath.Join(dir, "cmd")); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() && isMainPackage(filepath.Join(dir, "cmd", entry.Name())) {
+				layout.Binaries = append(layout.Binaries, Binary{Name: entry.Name(), Path: "./cmd/" + entry.Name()})
+			}
+		}
+	}
+
+	layout.HasDockerfile = fileExists(filepath.Join(dir, "Dockerfile"))
+	layout.HasGolangCI = anyFileExists(dir, ".golangci.yml", ".golangci.yaml", ".golangci.toml", ".golangci.json")
+	layout.HasGoReleaser = anyFileExists(dir, ".goreleaser.yml", ".goreleaser.yaml")
+
+	hasTests, err := containsTests(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk project: %v", err)
+	}
+	layout.HasTests = hasTests
+
+	return layout, nil
+}
+
+// isMainPackage reports whether dir contains a non-test Go file in package main
+func isMainPackage(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.PackageClauseOnly)
+		if err == nil && file.Name.Name == "main" {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTests reports whether any _test.go file exists under dir,
+// skipping hidden, vendor and testdata directories
+func containsTests(ctx context.Context, dir string) (bool, error) {
+	found := false
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if p != dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), "_test.go") {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found, err
+}
+
+// fileExists reports whether a regular file exists at p
+func fileExists(p string) bool {
+	info, err := os.Stat(p)
+	return err == nil && !info.IsDir()
+}
+
+// anyFileExists reports whether any of names exists in dir
+func anyFileExists(dir string, names ...string) bool {
+	for _, name := range names {
+		if fileExists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderMakefile generates a Makefile for the given layout
+func RenderMakefile(layout *ProjectLayout) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# Makefile for %s\n\n", layout.ModulePath))
+	sb.WriteString("GO ?= go\n")
+	sb.WriteString("BIN_DIR := bin\n")
+	sb.WriteString("DIST_DIR := dist\n")
+	sb.WriteString("VERSION ?= $(shell git describe --tags --always --dirty 2>/dev/null || echo dev)\n")
+	sb.WriteString("LDFLAGS := -s -w -X main.version=$(VERSION)\n")
+	if !layout.HasGoReleaser {
+		sb.WriteString(fmt.Sprintf("PLATFORMS := %s\n", strings.Join(releasePlatforms, " ")))
+	}
+	sb.WriteString("\n")
+
+	phony := []string{"all", "build", "test", "lint", "fmt", "release", "clean"}
+	if layout.HasDockerfile {
+		phony = append(phony, "docker-build")
+	}
+	sb.WriteString(fmt.Sprintf(".PHONY: %s\n\n", strings.Join(phony, " ")))
+
+	sb.WriteString("all: lint test build\n\n")
+
+	// Build
+	sb.WriteString("build:\n")
+	if len(layout.Binaries) == 0 {
+		sb.WriteString("\t$(GO) build ./...\n")
+	}
+	for _, bin := range layout.Binaries {
+		sb.WriteString(fmt.Sprintf("\t$(GO) build -ldflags \"$(LDFLAGS)\" -o $(BIN_DIR)/%s %s\n", bin.Name, bin.Path))
+	}
+	sb.WriteString("\n")
+
+	// Test
+	sb.WriteString("test:\n")
+	sb.WriteString("\t$(GO) test -race -cover ./...\n\n")
+
+	// Lint
+	sb.WriteString("lint:\n")
+	if layout.HasGolangCI {
+		sb.WriteString("\tgolangci-lint run ./...\n\n")
+	} else {
+		sb.WriteString("\t@test -z \"$$(gofmt -l .)\" || (gofmt -l . && exit 1)\n")
+		sb.WriteString("\t$(GO) vet ./...\n\n")
+	}
+
+	sb.WriteString("fmt:\n")
+	sb.WriteString("\tgofmt -w .\n\n")
+
+	// Release
+	sb.WriteString("release:\n")
+	switch {
+	case layout.HasGoReleaser:
+		sb.WriteString("\tgoreleaser release --clean\n")
+	case len(layout.Binaries) == 0:
+		sb.WriteString("\t@echo \"library module: tag a version to release\"\n")
+	default:
+		sb.WriteString("\t@for platform in $(PLATFORMS); do \\\n")
+		sb.WriteString("\t\tos=$${platform%/*}; arch=$${platform#*/}; \\\n")
+		for _, bin := range layout.Binaries {
+			sb.WriteString(fmt.Sprintf("\t\tGOOS=$$os GOARCH=$$arch $(GO) build -ldflags \"$(LDFLAGS)\" -o $(DIST_DIR)/%s-$$os-$$arch %s || exit 1; \\\n", bin.Name, bin.Path))
+		}
+		sb.WriteString("\tdone\n")
+	}
+	sb.WriteString("\n")
+
+	// Docker
+	if layout.HasDockerfile {
+		sb.WriteString("docker-build:\n")
+		sb.WriteString(fmt.Sprintf("\tdocker build -t %s:$(VERSION) .\n\n", imageName(layout)))
+	}
+
+	sb.WriteString("clean:\n")
+	sb.WriteString("\trm -rf $(BIN_DIR) $(DIST_DIR)\n")
+
+	return sb.String()
+}
+
+// renderTaskfile generates a Taskfile.yml (go-task v3) for the detected layout
+func renderTaskfile(layout *ProjectLayout) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# Taskfile for %s\n", layout.ModulePath))
+	sb.WriteString("version: '3'\n\n")
+	sb.WriteString("vars:\n")
+	sb.WriteString("  BIN_DIR: bin\n")
+	sb.WriteString("  DIST_DIR: dist\n")
+	sb.WriteString("  VERSION:\n")
+	sb.WriteString("    sh: git describe --tags --always --dirty 2>/dev/null || echo dev\n")
+	sb.WriteString("  LDFLAGS: -s -w -X main.version={{.VERSION}}\n\n")
+	sb.WriteString("tasks:\n")
+
+	sb.WriteString("  default:\n")
+	sb.WriteString("    deps: [lint, test, build]\n\n")
+
+	// Build
+	sb.WriteString("  build:\n")
+	sb.WriteString("    cmds:\n")
+	if len(layout.Binaries) == 0 {
+		sb.WriteString("      - go build ./...\n")
+	}
+	for _, bin := range layout.Binaries {
+		sb.WriteString(fmt.Sprintf("      - go build -ldflags \"{{.LDFLAGS}}\" -o {{.BIN_DIR}}/%s %s\n", bin.Name, bin.Path))
+	}
+	sb.WriteString("\n")
+
+	// Test
+	sb.WriteString("  test:\n")
+	sb.WriteString("    cmds:\n")
+	sb.WriteString("      - go test -race -cover ./...\n\n")
+
+	// Lint
+	sb.WriteString("  lint:\n")
+	sb.WriteString("    cmds:\n")
+	if layout.HasGolangCI {
+		sb.WriteString("      - golangci-lint run ./...\n\n")
+	} else {
+		sb.WriteString("      - test -z \"$(gofmt -l .)\"\n")
+		sb.WriteString("      - go vet ./...\n\n")
+	}
+
+	sb.WriteString("  fmt:\n")
+	sb.WriteString("    cmds:\n")
+	sb.WriteString("      - gofmt -w .\n\n")
+
+	// Release
+	sb.WriteString("  release:\n")
+	sb.WriteString("    cmds:\n")
+	switch {
+	case layout.HasGoReleaser:
+		sb.WriteString("      - goreleaser release --clean\n")
+	case len(layout.Binaries) == 0:
+		sb.WriteString("      - echo \"library module: tag a version to release\"\n")
+	default:
+		for _, platform := range releasePlatforms {
+			goos, goarch, _ := strings.Cut(platform, "/")
+			for _, bin := range layout.Binaries {
+				sb.WriteString(fmt.Sprintf("      - GOOS=%s GOARCH=%s go build -ldflags \"{{.LDFLAGS}}\" -o {{.DIST_DIR}}/%s-%s-%s %s\n",
+					goos, goarch, bin.Name, goos, goarch, bin.Path))
+			}
+		}
+	}
+	sb.WriteString("\n")
+
+	// Docker
+	if layout.HasDockerfile {
+		sb.WriteString("  docker-build:\n")
+		sb.WriteString("    cmds:\n")
+		sb.WriteString(fmt.Sprintf("      - docker build -t %s:{{.VERSION}} .\n\n", imageName(layout)))
+	}
+
+	sb.WriteString("  clean:\n")
+	sb.WriteString("    cmds:\n")
+	sb.WriteString("      - rm -rf {{.BIN_DIR}} {{.DIST_DIR}}\n")
+
+	return sb.String()
+}
+
+// imageName derives a docker image name from the first binary or the module path
+func imageName(layout *ProjectLayout) string {
+	if len(layout.Binaries) > 0 {
+		return strings.ToLower(layout.Binaries[0].Name)
+	}
+	return strings.ToLower(path.Base(layout.ModulePath))
+}
@@ -0,0 +1,148 @@
+package buildgen
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+func TestDetectLayout(t *testing.T) {
+	dir := testutil.WriteModule(t, map[string]string{
+		"go.mod":               "module github.com/acme/tool\n\ngo 1.22\n",
+		"cmd/server/main.go":   "package main\n\nfunc main() {}\n",
+		"cmd/cli/main.go":      "package main\n\nfunc main() {}\n",
+		"cmd/shared/shared.go": "package shared\n",
+		"internal/x/x.go":      "package x\n",
+		"internal/x/x_test.go": "package x\n",
+		"Dockerfile":           "FROM scratch\n",
+		".goreleaser.yaml":     "builds: []\n",
+	})
+
+	layout, err := DetectLayout(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("DetectLayout() error = %v", err)
+	}
+
+	if layout.ModulePath != "github.com/acme/tool" || layout.GoVersion != "1.22" {
+		t.Errorf("unexpected module info: %q %q", layout.ModulePath, layout.GoVersion)
+	}
+	if len(layout.Binaries) != 2 {
+		t.Fatalf("expected 2 binaries, got %+v", layout.Binaries)
+	}
+	if layout.Binaries[0].Name != "cli" || layout.Binaries[1].Path != "./cmd/server" {
+		t.Errorf("unexpected binaries: %+v", layout.Binaries)
+	}
+	if !layout.HasTests || !layout.HasDockerfile || !layout.HasGoReleaser || layout.HasGolangCI {
+		t.Errorf("unexpected detection flags: %+v", layout)
+	}
+}
+
+func TestGenerateBuildFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        map[string]string
+		format       string
+		wantFile     string
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name: "makefile for root main package",
+			files: map[string]string{
+				"go.mod":  "module example.com/hello\n\ngo 1.21\n",
+				"main.go": "package main\n\nfunc main() {}\n",
+			},
+			wantFile: "Makefile",
+			wantContains: []string{
+				"-o $(BIN_DIR)/hello .",
+				"$(GO) vet ./...",
+				"PLATFORMS :=",
+				"$(DIST_DIR)/hello-$$os-$$arch",
+			},
+			wantMissing: []string{"docker-build", "golangci-lint", "goreleaser"},
+		},
+		{
+			name: "makefile with docker and golangci",
+			files: map[string]string{
+				"go.mod":          "module example.com/svc\n\ngo 1.21\n",
+				"cmd/api/main.go": "package main\n\nfunc main() {}\n",
+				"Dockerfile":      "FROM scratch\n",
+				".golangci.yml":   "run: {}\n",
+			},
+			wantFile: "Makefile",
+			wantContains: []string{
+				"-o $(BIN_DIR)/api ./cmd/api",
+				"golangci-lint run ./...",
+				"docker build -t api:$(VERSION) .",
+			},
+		},
+		{
+			name: "taskfile for library",
+			files: map[string]string{
+				"go.mod": "module example.com/lib\n\ngo 1.21\n",
+				"lib.go": "package lib\n",
+			},
+			format:   "taskfile",
+			wantFile: "Taskfile.yml",
+			wantContains: []string{
+				"version: '3'",
+				"- go build ./...",
+				"library module",
+			},
+			wantMissing: []string{"GOOS="},
+		},
+		{
+			name: "taskfile release targets",
+			files: map[string]string{
+				"go.mod":          "module example.com/svc\n\ngo 1.21\n",
+				"cmd/api/main.go": "package main\n\nfunc main() {}\n",
+			},
+			format:   "taskfile",
+			wantFile: "Taskfile.yml",
+			wantContains: []string{
+				"GOOS=linux GOARCH=arm64 go build",
+				"{{.DIST_DIR}}/api-darwin-amd64 ./cmd/api",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := testutil.WriteModule(t, tt.files)
+			result, err := GenerateBuildFile(context.Background(), BuildGenParams{WorkingDir: dir, Format: tt.format})
+			if err != nil {
+				t.Fatalf("GenerateBuildFile() error = %v", err)
+			}
+			if result.FileName != tt.wantFile {
+				t.Errorf("FileName = %q, want %q", result.FileName, tt.wantFile)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(result.Content, want) {
+					t.Errorf("expected content to contain %q\n%s", want, result.Content)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(result.Content, missing) {
+					t.Errorf("expected content not to contain %q\n%s", missing, result.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateBuildFile_Errors(t *testing.T) {
+	if _, err := GenerateBuildFile(context.Background(), BuildGenParams{}); err == nil {
+		t.Error("expected error for empty working_dir")
+	}
+
+	dir := testutil.WriteModule(t, map[string]string{"go.mod": "module example.com/x\n"})
+	if _, err := GenerateBuildFile(context.Background(), BuildGenParams{WorkingDir: dir, Format: "bazel"}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+
+	if _, err := GenerateBuildFile(context.Background(), BuildGenParams{WorkingDir: t.TempDir()}); err == nil {
+		t.Error("expected error for missing go.mod")
+	}
+}
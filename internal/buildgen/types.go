@@ -0,0 +1,45 @@
+package buildgen
+
+// Supported build file formats
+const (
+	FormatMakefile = "makefile"
+	FormatTaskfile = "taskfile"
+)
+
+// BuildGenParams represents the parameters for the generate-makefile tool
+type BuildGenParams struct {
+	WorkingDir     string `json:"working_dir" jsonschema:"description:Root directory of the Go project to inspect"`
+	Format         string `json:"format,omitempty" jsonschema:"description:Output format: 'makefile' (default) or 'taskfile'"`
+	IdempotencyKey string `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// BuildGenResult represents a generated build file
+type BuildGenResult struct {
+	Format      string        `json:"format"`
+	FileName    string        `json:"file_name"`
+	Content     string        `json:"content"`
+	Layout      ProjectLayout `json:"layout"`
+	Suggestions []string      `json:"suggestions,omitempty"`
+}
+
+// ProjectLayout describes what was detected in the project
+type ProjectLayout struct {
+	ModulePath    string   `json:"module_path"`
+	GoVersion     string   `json:"go_version,omitempty"`
+	Binaries      []Binary `json:"binaries"`
+	HasTests      bool     `json:"has_tests"`
+	HasDockerfile bool     `json:"has_dockerfile"`
+	HasGolangCI   bool     `json:"has_golangci"`
+	HasGoReleaser bool     `json:"has_goreleaser"`
+}
+
+// Binary is a main package that produces an executable
+type Binary struct {
+	Name string `json:"name"`
+	Path string `json:"path"` // Package path relative to the module root, e.g. "./cmd/server"
+}
+
+// String returns the generated build file content
+func (r *BuildGenResult) String() string {
+	return r.Content
+}
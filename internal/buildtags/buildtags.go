@@ -0,0 +1,503 @@
+package buildtags
+
+import (
+	"fmt"
+	"go/build"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"mcp-go-assistant/internal/editdistance"
+	"mcp-go-assistant/internal/workspace"
+)
+
+// Inspection limits
+const (
+	MaxFiles = 5000
+	// maxFreeTags bounds the custom tags assigned exhaustively when checking
+	// whether a file can build at all
+	maxFreeTags = 8
+)
+
+// DefaultPlatforms is the matrix used when no platforms are requested
+var DefaultPlatforms = []string{
+	"linux/amd64", "linux/arm64",
+	"darwin/amd64", "darwin/arm64",
+	"windows/amd64", "windows/arm64",
+	"js/wasm",
+}
+
+// knownOS and knownArch mirror the GOOS and GOARCH values recognized by the
+// go command, including those only reserved for file names
+var (
+	knownOS = []string{
+		"aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios", "js",
+		"linux", "nacl", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows", "zos",
+	}
+	knownArch = []string{
+		"386", "amd64", "amd64p32", "arm", "armbe", "arm64", "arm64be", "loong64",
+		"mips", "mipsle", "mips64", "mips64le", "mips64p32", "mips64p32le",
+		"ppc", "ppc64", "ppc64le", "riscv", "riscv64", "s390", "s390x", "sparc", "sparc64", "wasm",
+	}
+	unixOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "hurd": true,
+		"illumos": true, "ios": true, "linux": true, "netbsd": true, "openbsd": true, "solaris": true,
+	}
+	// toolTags are tags set by the toolchain rather than by -tags
+	toolTags = []string{"unix", "cgo", "gc", "gccgo", "race", "msan", "asan"}
+)
+
+// releaseTagRegex matches go1.N release tags
+var releaseTagRegex = regexp.MustCompile(`^go1\.[0-9]+$`)
+
+// Options controls which directories an inspection may visit
+type Options struct {
+	Roots []string // Registered workspace roots; the working directory must be inside one
+}
+
+// platform is one GOOS/GOARCH pair of the matrix
+type platform struct {
+	goos, goarch string
+}
+
+// String returns the platform as GOOS/GOARCH
+func (p platform) String() string {
+	return p.goos + "/" + p.goarch
+}
+
+// goFile holds the build constraints of one Go file
+type goFile struct {
+	rel          string
+	expr         constraint.Expr // nil when the file has no //go:build or // +build lines
+	line         int             // Line of the constraint
+	goos, goarch string          // Implied by the file name
+}
+
+// Analyze inspects the build constraints of every Go file under the working
+// directory and reports which matrix platforms each constrained file
+// compiles for
+func Analyze(params BuildTagsParams, opts Options) (*BuildTagsResult, error) {
+	if params.WorkingDir == "" {
+		return nil, fmt.Errorf("working_dir parameter is required")
+	}
+	root, err := workspace.Dir("build constraint inspection", params.WorkingDir, opts.Roots)
+	if err != nil {
+		return nil, err
+	}
+
+	names := params.Platforms
+	if len(names) == 0 {
+		names = DefaultPlatforms
+	}
+	platforms := make([]platform, 0, len(names))
+	for _, name := range names {
+		p, err := parsePlatform(name)
+		if err != nil {
+			return nil, err
+		}
+		platforms = append(platforms, p)
+	}
+
+	files, err := collectGoFiles(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk workspace: %v", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files found in %s", root)
+	}
+	if len(files) > MaxFiles {
+		return nil, fmt.Errorf("workspace contains %d Go files, exceeding the limit of %d", len(files), MaxFiles)
+	}
+
+	result := &BuildTagsResult{
+		Root:        root,
+		Platforms:   names,
+		Tags:        params.Tags,
+		GoFiles:     len(files),
+		Constrained: []FileReport{},
+		Findings:    []Finding{},
+		Suggestions: []string{},
+	}
+	setTags := make(map[string]bool)
+	for _, tag := range params.Tags {
+		setTags[tag] = true
+	}
+
+	unsetTags := make(map[string]bool)
+	for _, rel := range files {
+		f, finding := readGoFile(root, rel)
+		if finding != nil {
+			result.Findings = append(result.Findings, *finding)
+			continue
+		}
+		if f.expr == nil && f.goos == "" && f.goarch == "" {
+			continue
+		}
+
+		report := FileReport{File: f.rel, NameConstraint: f.nameConstraint(), Platforms: []string{}}
+		if f.expr != nil {
+			report.Constraint = f.expr.String()
+			report.CustomTags = customTags(f.expr)
+		}
+		for _, p := range platforms {
+			if f.matches(p, setTags) {
+				report.Platforms = append(report.Platforms, p.String())
+			}
+		}
+		for _, tag := range report.CustomTags {
+			if !setTags[tag] {
+				unsetTags[tag] = true
+			}
+		}
+		result.Constrained = append(result.Constrained, report)
+
+		result.Findings = append(result.Findings, typoFindings(f)...)
+		if !f.buildable() {
+			result.Findings = append(result.Findings, excludedFinding(f))
+		}
+	}
+
+	addSuggestions(result, unsetTags)
+	result.Summary = fmt.Sprintf("%d of %d Go files are constrained across %d platforms; %d findings",
+		len(result.Constrained), result.GoFiles, len(platforms), len(result.Findings))
+	return result, nil
+}
+
+// parsePlatform parses a GOOS/GOARCH pair
+func parsePlatform(name string) (platform, error) {
+	goos, goarch, ok := strings.Cut(name, "/")
+	if !ok || !contains(knownOS, goos) || !contains(knownArch, goarch) {
+		return platform{}, fmt.Errorf("invalid platform %q: expected GOOS/GOARCH, e.g. linux/amd64", name)
+	}
+	return platform{goos: goos, goarch: goarch}, nil
+}
+
+// collectGoFiles returns the relative paths of Go files under root in
+// lexical order, skipping the directories and files the go command ignores
+func collectGoFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+
+		name := d.Name()
+		ignored := strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+		if d.IsDir() {
+			if ignored || name == "vendor" || name == "testdata" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !ignored && strings.HasSuffix(name, ".go") {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return files, err
+}
+
+// readGoFile reads the build constraints of a file. A finding is returned
+// instead when the constraint lines cannot be parsed.
+func readGoFile(root, rel string) (*goFile, *Finding) {
+	f := &goFile{rel: rel}
+	f.goos, f.goarch = nameConstraint(filepath.Base(rel))
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filepath.Join(root, filepath.FromSlash(rel)), nil, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return nil, &Finding{
+			Kind:       "invalid-constraint",
+			File:       rel,
+			Severity:   "high",
+			Message:    fmt.Sprintf("failed to parse file header: %v", err),
+			Suggestion: "Fix the syntax error before the package clause",
+		}
+	}
+
+	var plusExprs []constraint.Expr
+	plusLine := 0
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, c := range group.List {
+			line := fset.Position(c.Pos()).Line
+			if !constraint.IsGoBuild(c.Text) && !constraint.IsPlusBuild(c.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(c.Text)
+			if err != nil {
+				return nil, &Finding{
+					Kind:       "invalid-constraint",
+					File:       rel,
+					Line:       line,
+					Severity:   "high",
+					Message:    fmt.Sprintf("invalid build constraint %q: %v", c.Text, err),
+					Suggestion: "Use the //go:build syntax with &&, || and ! operators, e.g. //go:build linux && !arm",
+				}
+			}
+			if constraint.IsGoBuild(c.Text) {
+				// //go:build takes precedence over // +build lines
+				f.expr, f.line = expr, line
+				continue
+			}
+			if plusLine == 0 {
+				plusLine = line
+			}
+			plusExprs = append(plusExprs, expr)
+		}
+	}
+
+	if f.expr == nil && len(plusExprs) > 0 {
+		// Multiple // +build lines are ANDed together
+		f.expr, f.line = plusExprs[0], plusLine
+		for _, x := range plusExprs[1:] {
+			f.expr = &constraint.AndExpr{X: f.expr, Y: x}
+		}
+	}
+	return f, nil
+}
+
+// nameConstraint returns the GOOS and GOARCH implied by a file name suffix
+// such as _linux.go, _arm64.go or _windows_amd64_test.go
+func nameConstraint(name string) (goos, goarch string) {
+	name = strings.TrimSuffix(name, ".go")
+	name = strings.TrimSuffix(name, "_test")
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return "", ""
+	}
+	parts := strings.Split(name[i:], "_")
+	n := len(parts)
+	if n >= 2 && contains(knownOS, parts[n-2]) && contains(knownArch, parts[n-1]) {
+		return parts[n-2], parts[n-1]
+	}
+	if contains(knownOS, parts[n-1]) {
+		return parts[n-1], ""
+	}
+	if contains(knownArch, parts[n-1]) {
+		return "", parts[n-1]
+	}
+	return "", ""
+}
+
+// nameConstraint renders the file name constraint as an expression
+func (f *goFile) nameConstraint() string {
+	switch {
+	case f.goos != "" && f.goarch != "":
+		return f.goos + " && " + f.goarch
+	case f.goos != "":
+		return f.goos
+	}
+	return f.goarch
+}
+
+// matches reports whether the file compiles for p with the given custom tags
+func (f *goFile) matches(p platform, tags map[string]bool) bool {
+	if f.goos != "" && !p.hasTag(f.goos) {
+		return false
+	}
+	if f.goarch != "" && f.goarch != p.goarch {
+		return false
+	}
+	if f.expr == nil {
+		return true
+	}
+	return f.expr.Eval(func(tag string) bool {
+		return p.hasTag(tag) || tags[tag]
+	})
+}
+
+// buildable reports whether any known platform and assignment of the
+// file's custom tags builds the file. Files with too many custom tags to
+// enumerate are assumed buildable.
+func (f *goFile) buildable() bool {
+	var free []string
+	if f.expr != nil {
+		free = customTags(f.expr)
+	}
+	if len(free) > maxFreeTags {
+		return true
+	}
+
+	for mask := 0; mask < 1<<len(free); mask++ {
+		tags := make(map[string]bool, len(free))
+		for i, tag := range free {
+			tags[tag] = mask&(1<<i) != 0
+		}
+		for _, goos := range knownOS {
+			for _, goarch := range knownArch {
+				if f.matches(platform{goos: goos, goarch: goarch}, tags) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// hasTag reports whether tag is satisfied on the platform by the toolchain
+func (p platform) hasTag(tag string) bool {
+	switch tag {
+	case p.goos, p.goarch, "gc":
+		return true
+	case "unix":
+		return unixOS[p.goos]
+	case "linux":
+		return p.goos == "android"
+	case "solaris":
+		return p.goos == "illumos"
+	case "darwin":
+		return p.goos == "ios"
+	}
+	return contains(build.Default.ReleaseTags, tag)
+}
+
+// customTags returns the tags of expr that are neither platform nor release
+// tags, in order of appearance. cgo and the other toolchain tags are
+// included since they depend on how the build is run.
+func customTags(expr constraint.Expr) []string {
+	var tags []string
+	walkTags(expr, func(tag string) {
+		if contains(knownOS, tag) || contains(knownArch, tag) || tag == "unix" || tag == "gc" || releaseTagRegex.MatchString(tag) {
+			return
+		}
+		if !contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	})
+	return tags
+}
+
+// walkTags calls fn for every tag of expr
+func walkTags(expr constraint.Expr, fn func(string)) {
+	switch x := expr.(type) {
+	case *constraint.TagExpr:
+		fn(x.Tag)
+	case *constraint.NotExpr:
+		walkTags(x.X, fn)
+	case *constraint.AndExpr:
+		walkTags(x.X, fn)
+		walkTags(x.Y, fn)
+	case *constraint.OrExpr:
+		walkTags(x.X, fn)
+		walkTags(x.Y, fn)
+	}
+}
+
+// typoFindings reports custom tags that are likely misspelled GOOS, GOARCH
+// or toolchain tags
+func typoFindings(f *goFile) []Finding {
+	var findings []Finding
+	for _, tag := range customTags(f.expr) {
+		if contains(toolTags, tag) || strings.HasPrefix(tag, "goexperiment.") {
+			continue
+		}
+		match := likelyTypo(tag)
+		if match == "" {
+			continue
+		}
+		findings = append(findings, Finding{
+			Kind:       "likely-typo",
+			File:       f.rel,
+			Line:       f.line,
+			Severity:   "medium",
+			Message:    fmt.Sprintf("build tag %q is not a known GOOS, GOARCH or toolchain tag; did you mean %q?", tag, match),
+			Suggestion: fmt.Sprintf("Replace %q with %q; unknown tags are silently false, so the constraint never matches as intended", tag, match),
+		})
+	}
+	return findings
+}
+
+// likelyTypo returns the known tag that tag probably misspells, or ""
+func likelyTypo(tag string) string {
+	lower := strings.ToLower(tag)
+	if lower != tag && (contains(knownOS, lower) || contains(knownArch, lower) || contains(toolTags, lower)) {
+		return lower
+	}
+	if len(tag) < 3 {
+		return ""
+	}
+	maxDist := 1
+	if len(tag) > 4 {
+		maxDist = 2
+	}
+
+	// Toolchain tags are left out: short custom tags such as "unit" are
+	// too often one edit away from them
+	best, bestDist := "", maxDist+1
+	for _, c := range append(append([]string{}, knownOS...), knownArch...) {
+		if d := editdistance.Levenshtein(lower, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// excludedFinding reports a file that no platform and tag set builds
+func excludedFinding(f *goFile) Finding {
+	finding := Finding{
+		Kind:       "excluded-everywhere",
+		File:       f.rel,
+		Line:       f.line,
+		Severity:   "high",
+		Message:    "file is excluded from every GOOS/GOARCH combination and tag set",
+		Suggestion: "Fix the constraint or delete the file; use //go:build ignore for files that are intentionally never built",
+	}
+	if name := f.nameConstraint(); name != "" && f.expr != nil {
+		finding.Message = fmt.Sprintf("the file name restricts the file to %s, which contradicts //go:build %s", name, f.expr)
+		finding.Suggestion = "Rename the file or change its //go:build line so that both agree"
+	}
+	return finding
+}
+
+// addSuggestions adds suggestions for the findings and unset custom tags
+func addSuggestions(result *BuildTagsResult, unsetTags map[string]bool) {
+	counts := make(map[string]int)
+	for _, f := range result.Findings {
+		counts[f.Kind]++
+	}
+	if counts["likely-typo"] > 0 {
+		result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+			"Fix %d misspelled build tags; the go command treats unknown tags as unset instead of reporting them", counts["likely-typo"]))
+	}
+	if counts["excluded-everywhere"] > 0 {
+		result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+			"%d files are never compiled, so errors in them go unnoticed; fix or remove them", counts["excluded-everywhere"]))
+	}
+	if len(unsetTags) > 0 {
+		tags := make([]string, 0, len(unsetTags))
+		for tag := range unsetTags {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+			"Files using the custom tags %s are evaluated with those tags unset; pass them in tags to check that configuration", strings.Join(tags, ", ")))
+	}
+	if len(result.Suggestions) == 0 {
+		result.Suggestions = append(result.Suggestions, "All build constraints are valid and every constrained file builds for at least one platform")
+	}
+}
+
+// contains reports whether list contains s
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package buildtags
+
+import (
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+func findReport(result *BuildTagsResult, file string) *FileReport {
+	for i := range result.Constrained {
+		if result.Constrained[i].File == file {
+			return &result.Constrained[i]
+		}
+	}
+	return nil
+}
+
+func TestAnalyze(t *testing.T) {
+	dir := testutil.WriteModule(t, map[string]string{
+		"main.go":                  "package main\n",
+		"sys/sys_linux.go":         "package sys\n",
+		"sys/sys_windows_arm64.go": "package sys\n",
+		"sys/unix.go":              "//go:build unix && !js\n\npackage sys\n",
+		"sys/legacy.go":            "// +build darwin\n// +build !cgo\n\npackage sys\n",
+		"sys/integration_test.go":  "//go:build integration\n\npackage sys\n",
+		"tools.go":                 "//go:build ignore\n\npackage main\n",
+		"vendor/x/x_linux.go":      "package x\n",
+		"_old/old_linux.go":        "package old\n",
+	})
+
+	result, err := Analyze(BuildTagsParams{WorkingDir: dir}, Options{Roots: []string{dir}})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if result.GoFiles != 7 || len(result.Constrained) != 6 || len(result.Findings) != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	tests := []struct {
+		file      string
+		platforms string
+	}{
+		{"sys/sys_linux.go", "linux/amd64 linux/arm64"},
+		{"sys/sys_windows_arm64.go", "windows/arm64"},
+		{"sys/unix.go", "linux/amd64 linux/arm64 darwin/amd64 darwin/arm64"},
+		{"sys/legacy.go", "darwin/amd64 darwin/arm64"},
+		{"sys/integration_test.go", ""},
+		{"tools.go", ""},
+	}
+	for _, tt := range tests {
+		report := findReport(result, tt.file)
+		if report == nil {
+			t.Errorf("%s: not reported", tt.file)
+			continue
+		}
+		if got := strings.Join(report.Platforms, " "); got != tt.platforms {
+			t.Errorf("%s: platforms = %q, want %q", tt.file, got, tt.platforms)
+		}
+	}
+
+	if report := findReport(result, "sys/sys_windows_arm64.go"); report.NameConstraint != "windows && arm64" {
+		t.Errorf("unexpected name constraint %q", report.NameConstraint)
+	}
+	if report := findReport(result, "sys/legacy.go"); report.Constraint != "darwin && !cgo" || strings.Join(report.CustomTags, ",") != "cgo" {
+		t.Errorf("expected the +build lines to be combined, got %+v", report)
+	}
+	if !strings.Contains(strings.Join(result.Suggestions, "\n"), "cgo, ignore, integration") {
+		t.Errorf("expected a suggestion listing the unset tags, got %v", result.Suggestions)
+	}
+}
+
+func TestAnalyze_PlatformsAndTags(t *testing.T) {
+	dir := testutil.WriteModule(t, map[string]string{
+		"db_test.go": "//go:build integration && (linux || freebsd)\n\npackage db\n",
+	})
+
+	result, err := Analyze(BuildTagsParams{
+		WorkingDir: dir,
+		Platforms:  []string{"freebsd/amd64", "windows/386"},
+		Tags:       []string{"integration"},
+	}, Options{Roots: []string{dir}})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if got := strings.Join(result.Constrained[0].Platforms, " "); got != "freebsd/amd64" {
+		t.Errorf("platforms = %q", got)
+	}
+	if len(result.Suggestions) != 1 || !strings.HasPrefix(result.Suggestions[0], "All build constraints are valid") {
+		t.Errorf("unexpected suggestions %v", result.Suggestions)
+	}
+}
+
+func TestAnalyze_Findings(t *testing.T) {
+	dir := testutil.WriteModule(t, map[string]string{
+		"typo.go":           "// Copyright\n\n//go:build linx || Darwin\n\npackage p\n",
+		"never.go":          "//go:build linux && windows\n\npackage p\n",
+		"conflict_plan9.go": "//go:build !plan9\n\npackage p\n",
+		"broken.go":         "//go:build linux &&\n\npackage p\n",
+		"unit.go":           "//go:build unit && amd64\n\npackage p\n",
+	})
+
+	result, err := Analyze(BuildTagsParams{WorkingDir: dir}, Options{Roots: []string{dir}})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	var got []string
+	for _, f := range result.Findings {
+		got = append(got, f.Kind+" "+f.File)
+	}
+	want := []string{
+		"invalid-constraint broken.go",
+		"excluded-everywhere conflict_plan9.go",
+		"excluded-everywhere never.go",
+		"likely-typo typo.go",
+		"likely-typo typo.go",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("findings =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	typo := result.Findings[3]
+	if typo.Line != 3 || !strings.Contains(typo.Message, `did you mean "linux"?`) {
+		t.Errorf("unexpected typo finding %+v", typo)
+	}
+	if !strings.Contains(result.Findings[4].Message, `did you mean "darwin"?`) {
+		t.Errorf("expected the case typo to be reported, got %+v", result.Findings[4])
+	}
+	if !strings.Contains(result.Findings[1].Message, "contradicts //go:build !plan9") {
+		t.Errorf("expected the file name conflict to be explained, got %+v", result.Findings[1])
+	}
+}
+
+func TestAnalyze_Errors(t *testing.T) {
+	dir := testutil.WriteModule(t, map[string]string{"main.go": "package main\n"})
+	empty := t.TempDir()
+
+	tests := []struct {
+		name    string
+		params  BuildTagsParams
+		roots   []string
+		wantErr string
+	}{
+		{"no working dir", BuildTagsParams{}, []string{dir}, "working_dir parameter is required"},
+		{"no roots", BuildTagsParams{WorkingDir: dir}, nil, "requires at least one registered workspace root"},
+		{"outside roots", BuildTagsParams{WorkingDir: dir}, []string{empty}, "not inside a registered workspace"},
+		{"bad platform", BuildTagsParams{WorkingDir: dir, Platforms: []string{"linux"}}, []string{dir}, `invalid platform "linux"`},
+		{"unknown arch", BuildTagsParams{WorkingDir: dir, Platforms: []string{"linux/amd46"}}, []string{dir}, "invalid platform"},
+		{"no files", BuildTagsParams{WorkingDir: empty}, []string{empty}, "no Go files found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Analyze(tt.params, Options{Roots: tt.roots})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNameConstraint(t *testing.T) {
+	tests := []struct {
+		name         string
+		goos, goarch string
+	}{
+		{"linux.go", "", ""},
+		{"file_linux.go", "linux", ""},
+		{"file_arm64.go", "", "arm64"},
+		{"file_windows_amd64_test.go", "windows", "amd64"},
+		{"file_linux_test.go", "linux", ""},
+		{"file_plan.go", "", ""},
+	}
+	for _, tt := range tests {
+		goos, goarch := nameConstraint(tt.name)
+		if goos != tt.goos || goarch != tt.goarch {
+			t.Errorf("nameConstraint(%q) = %q, %q, want %q, %q", tt.name, goos, goarch, tt.goos, tt.goarch)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package buildtags
+
+import "encoding/json"
+
+// BuildTagsParams represents the parameters for the build-constraints tool
+type BuildTagsParams struct {
+	WorkingDir     string   `json:"working_dir" jsonschema:"description:Directory inside a registered workspace root whose Go files are inspected"`
+	Platforms      []string `json:"platforms,omitempty" jsonschema:"description:Optional GOOS/GOARCH pairs of the matrix, e.g. ['linux/amd64', 'windows/arm64'] (defaults to the common first-class ports and js/wasm)"`
+	Tags           []string `json:"tags,omitempty" jsonschema:"description:Optional custom build tags set for the matrix, e.g. ['integration'] or ['cgo']"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// BuildTagsResult represents the platform matrix of a workspace
+type BuildTagsResult struct {
+	Summary     string       `json:"summary"`
+	Root        string       `json:"root"`
+	Platforms   []string     `json:"platforms"`
+	Tags        []string     `json:"tags,omitempty"`
+	GoFiles     int          `json:"go_files"`    // All Go files inspected
+	Constrained []FileReport `json:"constrained"` // Files with a build constraint or a GOOS/GOARCH file name suffix
+	Findings    []Finding    `json:"findings"`
+	Suggestions []string     `json:"suggestions"`
+}
+
+// FileReport lists the matrix platforms a constrained file compiles for
+type FileReport struct {
+	File           string   `json:"file"`
+	Constraint     string   `json:"constraint,omitempty"`      // The //go:build expression
+	NameConstraint string   `json:"name_constraint,omitempty"` // Implied by the file name, e.g. "linux && amd64"
+	Platforms      []string `json:"platforms"`                 // Matrix platforms the file compiles for
+	CustomTags     []string `json:"custom_tags,omitempty"`     // Tags in the expression that are not platform or release tags
+}
+
+// Finding represents a build constraint problem
+type Finding struct {
+	Kind       string `json:"kind"` // "excluded-everywhere", "likely-typo" or "invalid-constraint"
+	File       string `json:"file"`
+	Line       int    `json:"line,omitempty"`
+	Severity   string `json:"severity"` // "low", "medium", "high"
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
+
+// String returns a formatted JSON string of the BuildTagsResult
+func (r *BuildTagsResult) String() string {
+	jsonData, _ := json.MarshalIndent(r, "", "  ")
+	return string(jsonData)
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache_GetSet(t *testing.T) {
+	c := New[string](time.Minute, 10)
+
+	if _, ok := c.Get("missing"); ok {
+		t.Error("expected miss for unknown key")
+	}
+
+	c.Set("a", "1")
+	if v, ok := c.Get("a"); !ok || v != "1" {
+		t.Errorf("expected hit with value 1, got %q (hit %v)", v, ok)
+	}
+
+	c.Set("a", "2")
+	if v, _ := c.Get("a"); v != "2" || c.Len() != 1 {
+		t.Errorf("expected overwrite, got %q with %d entries", v, c.Len())
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	now := time.Now()
+	c := New[int](time.Minute, 10)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	now = now.Add(59 * time.Second)
+	if _, ok := c.Get("a"); !ok {
+		t.Error("expected hit before expiry")
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Error("expected miss after expiry")
+	}
+	if c.Len() != 0 {
+		t.Errorf("expected expired entry to be evicted, got %d entries", c.Len())
+	}
+}
+
+func TestCache_LRUEviction(t *testing.T) {
+	c := New[int](time.Minute, 2)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // a is now the most recently used
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Error("expected recently used entry to be kept")
+	}
+	if c.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", c.Len())
+	}
+}
+
+func TestKey(t *testing.T) {
+	type params struct {
+		Hint string `json:"hint"`
+	}
+
+	k1 := Key("v1", Hash("package a"), params{Hint: "x"})
+	if k1 != Key("v1", Hash("package a"), params{Hint: "x"}) {
+		t.Error("expected identical parts to produce the same key")
+	}
+
+	different := []string{
+		Key("v2", Hash("package a"), params{Hint: "x"}),
+		Key("v1", Hash("package b"), params{Hint: "x"}),
+		Key("v1", Hash("package a"), params{Hint: "y"}),
+	}
+	for i, k := range different {
+		if k == k1 {
+			t.Errorf("expected variant %d to produce a different key", i)
+		}
+	}
+
+	// Part boundaries are significant
+	if Key("ab", "c") == Key("a", "bc") {
+		t.Error("expected part boundaries to affect the key")
+	}
+}
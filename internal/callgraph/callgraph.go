@@ -0,0 +1,486 @@
+// Package callgraph extracts a bounded static call graph around a function
+// of a workspace, built with the CHA or RTA algorithm over its SSA form.
+package callgraph
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"go/types"
+	"slices"
+	"sort"
+	"strings"
+
+	xcallgraph "golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/callgraph/rta"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+
+	"mcp-go-assistant/internal/gocheck"
+	"mcp-go-assistant/internal/golist"
+	"mcp-go-assistant/internal/workspace"
+)
+
+const (
+	// defaultDepth is the number of calls followed unless the request sets
+	// depth
+	defaultDepth = 3
+	// maxDepth caps the depth a request may set
+	maxDepth = 10
+	// maxNodes caps the functions listed, as graphs grow quickly with depth
+	maxNodes = 200
+)
+
+// Options controls which directories may be analyzed
+type Options struct {
+	Roots []string // Registered workspace roots; working_dir and the packages must be inside one
+}
+
+// Extract type-checks the workspace packages matching the package pattern,
+// builds their call graph and returns the part within depth calls of the
+// requested function
+func Extract(ctx context.Context, params CallGraphParams, opts Options) (*CallGraphResult, error) {
+	if params.WorkingDir == "" {
+		return nil, fmt.Errorf("working_dir parameter is required")
+	}
+	if params.Function == "" {
+		return nil, fmt.Errorf("function parameter is required")
+	}
+	algorithm := strings.ToLower(params.Algorithm)
+	switch algorithm {
+	case "":
+		algorithm = "cha"
+	case "cha", "rta":
+	default:
+		return nil, fmt.Errorf("algorithm must be one of: cha, rta (got: %s)", params.Algorithm)
+	}
+	direction := strings.ToLower(params.Direction)
+	switch direction {
+	case "":
+		direction = "callees"
+	case "callees", "callers":
+	default:
+		return nil, fmt.Errorf("direction must be one of: callees, callers (got: %s)", params.Direction)
+	}
+	depth := params.Depth
+	switch {
+	case depth < 0 || depth > maxDepth:
+		return nil, fmt.Errorf("depth must be between 1 and %d: %d", maxDepth, depth)
+	case depth == 0:
+		depth = defaultDepth
+	}
+
+	dir, err := workspace.Dir("call graph extraction", params.WorkingDir, opts.Roots)
+	if err != nil {
+		return nil, err
+	}
+	pattern := params.Package
+	if pattern == "" {
+		pattern = "./..."
+	}
+	if strings.HasPrefix(pattern, "-") {
+		return nil, fmt.Errorf("package must be a package pattern: %s", pattern)
+	}
+
+	listed, err := golist.List(ctx, dir, pattern, golist.Options{})
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []gocheck.Package
+	local := make(map[string]bool)
+	for _, lp := range listed {
+		if len(lp.GoFiles) == 0 {
+			continue
+		}
+		if !workspace.Contains(opts.Roots, lp.Dir) {
+			continue
+		}
+		files, err := lp.ReadFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, gocheck.Package{Path: lp.ImportPath, Files: files})
+		local[lp.ImportPath] = true
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no Go packages in the workspace match %s", pattern)
+	}
+
+	checked := gocheck.CheckIn(ctx, dir, pkgs)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// SSA construction requires well-typed code
+	if len(checked.Diagnostics) > 0 {
+		return nil, fmt.Errorf("the packages do not type-check (%d errors, e.g. %s); fix them or narrow package",
+			len(checked.Diagnostics), checked.Diagnostics[0])
+	}
+
+	prog, err := buildProgram(checked, local)
+	if err != nil {
+		return nil, err
+	}
+	root, err := findFunction(prog, local, params.Function)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := buildGraph(prog, root, algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &grapher{
+		fset:      prog.Fset,
+		workspace: local,
+		external:  params.IncludeExternal,
+		callers:   direction == "callers",
+		depth:     depth,
+	}
+	result := g.walk(graph.CreateNode(root))
+	result.Root = root.String()
+	result.Algorithm = algorithm
+	result.Direction = direction
+	result.Depth = depth
+	addSuggestions(result, algorithm, direction, params.IncludeExternal)
+	return result, nil
+}
+
+// buildProgram builds the SSA form of the workspace packages. Other
+// packages are created from their types only, so calls into them end there.
+func buildProgram(checked *gocheck.Result, workspace map[string]bool) (prog *ssa.Program, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to build the SSA form: %v", r)
+		}
+	}()
+
+	prog = ssa.NewProgram(checked.Fset, ssa.InstantiateGenerics)
+	created := make(map[*types.Package]bool)
+	var create func(pkg *types.Package)
+	create = func(pkg *types.Package) {
+		if created[pkg] {
+			return
+		}
+		created[pkg] = true
+		if workspace[pkg.Path()] {
+			prog.CreatePackage(pkg, checked.Syntax[pkg.Path()], checked.Info[pkg.Path()], true)
+		} else {
+			prog.CreatePackage(pkg, nil, nil, true)
+		}
+		for _, imp := range pkg.Imports() {
+			create(imp)
+		}
+	}
+	paths := make([]string, 0, len(workspace))
+	for path := range workspace {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		create(checked.Types[path])
+	}
+	prog.Build()
+	return prog, nil
+}
+
+// buildGraph builds the call graph of prog with algorithm; RTA only covers
+// the functions reachable from root
+func buildGraph(prog *ssa.Program, root *ssa.Function, algorithm string) (graph *xcallgraph.Graph, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to build the call graph: %v", r)
+		}
+	}()
+
+	if algorithm == "rta" {
+		return rta.Analyze([]*ssa.Function{root}, true).CallGraph, nil
+	}
+	return cha.CallGraph(prog), nil
+}
+
+// findFunction returns the declared workspace function or method name
+// refers to, e.g. "Serve", "server.Serve", "(*Server).Handle" or
+// "example.com/app/server.Server.Handle"
+func findFunction(prog *ssa.Program, workspace map[string]bool, name string) (*ssa.Function, error) {
+	want := strings.NewReplacer("(", "", ")", "", "*", "").Replace(strings.TrimSpace(name))
+	_, short := splitLast(want)
+
+	var found, similar []*ssa.Function
+	for fn := range ssautil.AllFunctions(prog) {
+		if fn.Synthetic != "" || fn.Parent() != nil || fn.Origin() != nil || fn.Pkg == nil || !workspace[fn.Pkg.Pkg.Path()] {
+			continue
+		}
+		base := fn.Name()
+		if recv := fn.Signature.Recv(); recv != nil {
+			base = receiverName(recv.Type()) + "." + base
+		}
+		pkg := fn.Pkg.Pkg
+		switch want {
+		case base, pkg.Name() + "." + base, pkg.Path() + "." + base:
+			found = append(found, fn)
+		default:
+			if fn.Name() == short {
+				similar = append(similar, fn)
+			}
+		}
+	}
+
+	switch len(found) {
+	case 1:
+		return found[0], nil
+	case 0:
+		if len(similar) > 0 {
+			return nil, fmt.Errorf("function %s not found in the workspace; did you mean %s?", name, functionNames(similar))
+		}
+		return nil, fmt.Errorf("function %s not found in the workspace", name)
+	default:
+		return nil, fmt.Errorf("function %s is ambiguous (%s); qualify it with the package path", name, functionNames(found))
+	}
+}
+
+// grapher walks a call graph breadth-first from a root
+type grapher struct {
+	fset      *token.FileSet
+	workspace map[string]bool
+	external  bool // Whether functions outside the workspace are listed
+	callers   bool // Whether edges lead to callers instead of callees
+	depth     int
+}
+
+// walk returns the nodes within g.depth edges of root
+func (g *grapher) walk(root *xcallgraph.Node) *CallGraphResult {
+	result := &CallGraphResult{Nodes: []Node{}, Suggestions: []string{}}
+	index := map[*xcallgraph.Node]int{root: 0}
+	result.Nodes = append(result.Nodes, g.node(root.Func, 0))
+
+	queue := []*xcallgraph.Node{root}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		i := index[cur]
+
+		edges, others := g.edges(cur)
+		if len(edges) == 0 {
+			continue
+		}
+		if result.Nodes[i].Depth == g.depth {
+			result.Nodes[i].Unexpanded = true
+			continue
+		}
+		result.Nodes[i].Edges = edges
+		for _, other := range others {
+			if _, seen := index[other]; seen {
+				continue
+			}
+			if len(result.Nodes) == maxNodes {
+				result.Truncated = true
+				continue
+			}
+			index[other] = len(result.Nodes)
+			result.Nodes = append(result.Nodes, g.node(other.Func, result.Nodes[i].Depth+1))
+			queue = append(queue, other)
+		}
+	}
+
+	edges := 0
+	for _, n := range result.Nodes {
+		edges += len(n.Edges)
+	}
+	result.Summary = fmt.Sprintf("%d functions and %d edges within %d calls of %s", len(result.Nodes), edges, g.depth, root.Func)
+	if result.Truncated {
+		result.Summary += fmt.Sprintf(" (truncated at %d functions)", maxNodes)
+	}
+	return result
+}
+
+// edges returns the edges of n grouped by the function at their other end,
+// in the order of their first call site, with those functions' nodes
+func (g *grapher) edges(n *xcallgraph.Node) ([]Edge, []*xcallgraph.Node) {
+	in := n.Out
+	if g.callers {
+		in = n.In
+	}
+
+	type group struct {
+		edge  Edge
+		other *xcallgraph.Node
+	}
+	var groups []*group
+	byFunc := make(map[*ssa.Function]*group)
+	for _, e := range in {
+		other := e.Callee
+		if g.callers {
+			other = e.Caller
+		}
+		for _, other := range g.declared(other, make(map[*xcallgraph.Node]bool)) {
+			if !g.external && !g.inWorkspace(other.Func) {
+				continue
+			}
+			grp := byFunc[other.Func]
+			if grp == nil {
+				grp = &group{edge: Edge{Function: other.Func.String()}, other: other}
+				byFunc[other.Func] = grp
+				groups = append(groups, grp)
+			}
+			if e.Site != nil && e.Site.Pos().IsValid() {
+				grp.edge.Lines = append(grp.edge.Lines, g.fset.Position(e.Site.Pos()).Line)
+				grp.edge.Dynamic = grp.edge.Dynamic || e.Site.Common().StaticCallee() == nil
+			}
+		}
+	}
+
+	for _, grp := range groups {
+		// Wrappers reach the same function from one call site several times
+		sort.Ints(grp.edge.Lines)
+		grp.edge.Lines = slices.Compact(grp.edge.Lines)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i].edge, groups[j].edge
+		if firstLine(a) != firstLine(b) {
+			return firstLine(a) < firstLine(b)
+		}
+		return a.Function < b.Function
+	})
+	edges := make([]Edge, len(groups))
+	others := make([]*xcallgraph.Node, len(groups))
+	for i, grp := range groups {
+		edges[i], others[i] = grp.edge, grp.other
+	}
+	return edges, others
+}
+
+// declared returns n, or for wrappers such as those of value methods in
+// pointer method sets the functions they lead to
+func (g *grapher) declared(n *xcallgraph.Node, seen map[*xcallgraph.Node]bool) []*xcallgraph.Node {
+	if !isWrapper(n.Func) {
+		return []*xcallgraph.Node{n}
+	}
+	if seen[n] {
+		return nil
+	}
+	seen[n] = true
+
+	edges := n.Out
+	if g.callers {
+		edges = n.In
+	}
+	var nodes []*xcallgraph.Node
+	for _, e := range edges {
+		other := e.Callee
+		if g.callers {
+			other = e.Caller
+		}
+		nodes = append(nodes, g.declared(other, seen)...)
+	}
+	return nodes
+}
+
+// node describes fn at depth, with its position inside the workspace
+func (g *grapher) node(fn *ssa.Function, depth int) Node {
+	n := Node{Function: fn.String(), Depth: depth}
+	if g.inWorkspace(fn) && fn.Pos().IsValid() {
+		pos := g.fset.Position(fn.Pos())
+		n.File, n.Line = pos.Filename, pos.Line
+	}
+	return n
+}
+
+// inWorkspace reports whether fn belongs to an analyzed workspace package
+func (g *grapher) inWorkspace(fn *ssa.Function) bool {
+	return g.workspace[packagePath(fn)]
+}
+
+// addSuggestions explains the limits of the graph
+func addSuggestions(result *CallGraphResult, algorithm, direction string, external bool) {
+	add := func(format string, args ...any) {
+		result.Suggestions = append(result.Suggestions, fmt.Sprintf(format, args...))
+	}
+
+	dynamic, unexpanded := false, false
+	for _, n := range result.Nodes {
+		unexpanded = unexpanded || n.Unexpanded
+		for _, e := range n.Edges {
+			dynamic = dynamic || e.Dynamic
+		}
+	}
+	if dynamic && algorithm == "cha" {
+		add("Dynamic edges lead to every method matching an interface call; use algorithm rta to keep only types created in reachable code.")
+	}
+	if unexpanded {
+		add("Nodes marked unexpanded have %s beyond the depth limit; raise depth or start from them.", direction)
+	}
+	if result.Truncated {
+		add("The graph was truncated at %d functions; lower depth or start from a more specific function.", maxNodes)
+	}
+	if !external {
+		add("Calls to and from packages outside the analyzed workspace packages are omitted; set include_external to list them.")
+	}
+	if direction == "callers" && algorithm == "rta" {
+		add("RTA only knows the code reachable from function, so it finds no callers outside it; use algorithm cha to find callers.")
+	}
+}
+
+// isWrapper reports whether fn is a wrapper, thunk or bound method the SSA
+// builder synthesized to call another function
+func isWrapper(fn *ssa.Function) bool {
+	for _, prefix := range []string{"wrapper for ", "thunk for ", "bound method wrapper for ", "instantiation wrapper of "} {
+		if strings.HasPrefix(fn.Synthetic, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// packagePath returns the import path of the package declaring fn, or of
+// the function a wrapper or instantiation was derived from
+func packagePath(fn *ssa.Function) string {
+	switch {
+	case fn.Pkg != nil:
+		return fn.Pkg.Pkg.Path()
+	case fn.Origin() != nil:
+		return packagePath(fn.Origin())
+	case fn.Object() != nil && fn.Object().Pkg() != nil:
+		return fn.Object().Pkg().Path()
+	}
+	return ""
+}
+
+// receiverName returns the name of the named type of a method receiver
+func receiverName(t types.Type) string {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return t.String()
+}
+
+// functionNames formats the package-qualified names of fns in order
+func functionNames(fns []*ssa.Function) string {
+	names := make([]string, len(fns))
+	for i, fn := range fns {
+		names[i] = fn.String()
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// firstLine returns the line of the first call site of e
+func firstLine(e Edge) int {
+	if len(e.Lines) == 0 {
+		return 0
+	}
+	return e.Lines[0]
+}
+
+// splitLast splits name at its last dot
+func splitLast(name string) (string, string) {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return "", name
+	}
+	return name[:i], name[i+1:]
+}
@@ -0,0 +1,210 @@
+package callgraph
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+// writeModule creates a module with a server package dispatching to
+// handlers through an interface and a main package using it
+func writeModule(t *testing.T) string {
+	t.Helper()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.23\n",
+		"server/server.go": `package server
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Handler interface{ Handle(msg string) string }
+
+type Upper struct{}
+
+func (Upper) Handle(msg string) string { return strings.ToUpper(msg) }
+
+type Echo struct{ prefix string }
+
+func (e *Echo) Handle(msg string) string { return e.prefix + clean(msg) }
+
+func clean(s string) string { return strings.TrimSpace(s) }
+
+type Server struct{ h Handler }
+
+func New() *Server { return &Server{h: Upper{}} }
+
+func (s *Server) Serve(msgs []string) {
+	for _, m := range msgs {
+		fmt.Println(s.dispatch(m))
+	}
+}
+
+func (s *Server) dispatch(m string) string {
+	return s.h.Handle(m)
+}
+`,
+		"cmd/app/main.go": `package main
+
+import (
+	"os"
+
+	"example.com/app/server"
+)
+
+func main() {
+	server.New().Serve(os.Args)
+}
+`,
+	}
+	return testutil.WriteModule(t, files)
+}
+
+// adjacency formats the nodes as "caller -> callee, callee" lines with the
+// module path trimmed
+func adjacency(result *CallGraphResult) string {
+	var lines []string
+	for _, n := range result.Nodes {
+		var edges []string
+		for _, e := range n.Edges {
+			edges = append(edges, e.Function)
+		}
+		line := n.Function
+		if len(edges) > 0 {
+			line += " -> " + strings.Join(edges, ", ")
+		}
+		if n.Unexpanded {
+			line += " ..."
+		}
+		lines = append(lines, line)
+	}
+	return strings.ReplaceAll(strings.Join(lines, "\n"), "example.com/app/", "")
+}
+
+func TestExtract(t *testing.T) {
+	dir := writeModule(t)
+	opts := Options{Roots: []string{dir}}
+
+	tests := []struct {
+		name   string
+		params CallGraphParams
+		want   string
+	}{
+		{
+			name:   "cha callees",
+			params: CallGraphParams{Function: "(*Server).Serve"},
+			want: `(*server.Server).Serve -> (*server.Server).dispatch
+(*server.Server).dispatch -> (*server.Echo).Handle, (server.Upper).Handle
+(*server.Echo).Handle -> server.clean
+(server.Upper).Handle
+server.clean`,
+		},
+		{
+			name:   "rta callees",
+			params: CallGraphParams{Function: "main", Algorithm: "rta"},
+			want: `cmd/app.main -> (*server.Server).Serve, server.New
+(*server.Server).Serve -> (*server.Server).dispatch
+server.New
+(*server.Server).dispatch -> (server.Upper).Handle
+(server.Upper).Handle`,
+		},
+		{
+			name:   "depth limit",
+			params: CallGraphParams{Function: "server.Server.Serve", Depth: 1},
+			want: `(*server.Server).Serve -> (*server.Server).dispatch
+(*server.Server).dispatch ...`,
+		},
+		{
+			name:   "callers",
+			params: CallGraphParams{Function: "clean", Direction: "callers"},
+			want: `server.clean -> (*server.Echo).Handle
+(*server.Echo).Handle -> (*server.Server).dispatch
+(*server.Server).dispatch -> (*server.Server).Serve
+(*server.Server).Serve ...`,
+		},
+		{
+			name:   "external",
+			params: CallGraphParams{Function: "example.com/app/server.Upper.Handle", IncludeExternal: true},
+			want: `(server.Upper).Handle -> strings.ToUpper
+strings.ToUpper`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.WorkingDir = dir
+			result, err := Extract(context.Background(), tt.params, opts)
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if got := adjacency(result); got != tt.want {
+				t.Errorf("unexpected graph:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtract_Edges(t *testing.T) {
+	dir := writeModule(t)
+
+	result, err := Extract(context.Background(), CallGraphParams{WorkingDir: dir, Function: "Server.dispatch"}, Options{Roots: []string{dir}})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	root := result.Nodes[0]
+	if result.Root != "(*example.com/app/server.Server).dispatch" || root.File != "server/server.go" || root.Line != 30 {
+		t.Errorf("unexpected root %s at %s:%d", result.Root, root.File, root.Line)
+	}
+	for _, e := range root.Edges {
+		if !e.Dynamic || len(e.Lines) != 1 || e.Lines[0] != 31 {
+			t.Errorf("expected a dynamic call on line 31, got %+v", e)
+		}
+	}
+	if !strings.HasPrefix(result.Summary, "4 functions and 3 edges within 3 calls") {
+		t.Errorf("unexpected summary %q", result.Summary)
+	}
+	found := false
+	for _, s := range result.Suggestions {
+		found = found || strings.Contains(s, "algorithm rta")
+	}
+	if !found {
+		t.Errorf("expected a suggestion on rta for dynamic edges, got %v", result.Suggestions)
+	}
+}
+
+func TestExtract_Errors(t *testing.T) {
+	dir := writeModule(t)
+	roots := Options{Roots: []string{dir}}
+
+	tests := []struct {
+		name    string
+		params  CallGraphParams
+		opts    Options
+		wantErr string
+	}{
+		{"no working dir", CallGraphParams{Function: "main"}, roots, "working_dir parameter is required"},
+		{"no function", CallGraphParams{WorkingDir: dir}, roots, "function parameter is required"},
+		{"algorithm", CallGraphParams{WorkingDir: dir, Function: "main", Algorithm: "vta"}, roots, "algorithm must be one of"},
+		{"direction", CallGraphParams{WorkingDir: dir, Function: "main", Direction: "up"}, roots, "direction must be one of"},
+		{"depth", CallGraphParams{WorkingDir: dir, Function: "main", Depth: 11}, roots, "depth must be between 1 and 10"},
+		{"no roots", CallGraphParams{WorkingDir: dir, Function: "main"}, Options{}, "requires at least one registered workspace root"},
+		{"outside roots", CallGraphParams{WorkingDir: dir, Function: "main"}, Options{Roots: []string{t.TempDir()}}, "not inside a registered workspace"},
+		{"flag", CallGraphParams{WorkingDir: dir, Function: "main", Package: "-toolexec=x"}, roots, "must be a package pattern"},
+		{"not found", CallGraphParams{WorkingDir: dir, Function: "Missing"}, roots, "function Missing not found"},
+		{"similar", CallGraphParams{WorkingDir: dir, Function: "Server.Handle"}, roots, "did you mean (*example.com/app/server.Echo).Handle, (example.com/app/server.Upper).Handle?"},
+		{"method without type", CallGraphParams{WorkingDir: dir, Function: "Handle"}, roots, "function Handle not found in the workspace; did you mean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Extract(context.Background(), tt.params, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
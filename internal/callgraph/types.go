@@ -0,0 +1,54 @@
+package callgraph
+
+import (
+	"encoding/json"
+)
+
+// CallGraphParams represents the parameters for the call-graph tool
+type CallGraphParams struct {
+	WorkingDir      string `json:"working_dir" jsonschema:"description:Module or package directory inside a registered workspace root"`
+	Function        string `json:"function" jsonschema:"description:Function or method to start from, e.g. 'Serve', 'server.Serve', 'Server.Handle', '(*Server).Handle' or 'example.com/app/server.Server.Handle'"`
+	Package         string `json:"package,omitempty" jsonschema:"description:Optional package pattern relative to working_dir to analyze (defaults to './...')"`
+	Algorithm       string `json:"algorithm,omitempty" jsonschema:"description:Optional call graph algorithm: 'cha' resolves interface calls to every implementation, 'rta' only to types created in code reachable from function (defaults to 'cha')"`
+	Direction       string `json:"direction,omitempty" jsonschema:"description:Optional direction to follow: 'callees' or 'callers' (defaults to 'callees')"`
+	Depth           int    `json:"depth,omitempty" jsonschema:"description:Optional number of calls to follow from function (defaults to 3, at most 10)"`
+	IncludeExternal bool   `json:"include_external,omitempty" jsonschema:"description:Optional; also list calls to and from packages outside the analyzed workspace packages, such as the standard library"`
+	IdempotencyKey  string `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// CallGraphResult is a call graph bounded to the functions within depth
+// calls of the root, as an adjacency list
+type CallGraphResult struct {
+	Summary     string   `json:"summary"`
+	Root        string   `json:"root"` // Package-qualified, e.g. "example.com/app/server.(*Server).Handle"
+	Algorithm   string   `json:"algorithm"`
+	Direction   string   `json:"direction"`
+	Depth       int      `json:"depth"`
+	Nodes       []Node   `json:"nodes"` // Breadth-first from the root
+	Truncated   bool     `json:"truncated,omitempty"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// Node is a function of the graph with its outgoing edges: the functions
+// it calls, or those calling it when following callers
+type Node struct {
+	Function   string `json:"function"`
+	File       string `json:"file,omitempty"` // Relative to working_dir; empty outside the workspace
+	Line       int    `json:"line,omitempty"`
+	Depth      int    `json:"depth"` // Calls from the root
+	Edges      []Edge `json:"edges,omitempty"`
+	Unexpanded bool   `json:"unexpanded,omitempty"` // Edges beyond the depth limit were not followed
+}
+
+// Edge is a call between two functions
+type Edge struct {
+	Function string `json:"function"`
+	Lines    []int  `json:"lines,omitempty"`   // Lines of the call sites in the calling function's file
+	Dynamic  bool   `json:"dynamic,omitempty"` // Interface method or function value call resolved by the algorithm
+}
+
+// String returns a formatted JSON string of the CallGraphResult
+func (r *CallGraphResult) String() string {
+	jsonData, _ := json.MarshalIndent(r, "", "  ")
+	return string(jsonData)
+}
@@ -1,6 +1,8 @@
 package circuitbreaker
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -28,6 +30,10 @@ type CircuitBreaker struct {
 	timeout time.Duration
 	// maxHalfOpenRequests is the number of requests allowed in half-open state
 	maxHalfOpenRequests int
+	// ignoreCancellations leaves cancelled calls out of the failure count
+	ignoreCancellations bool
+	// isFailure reports whether an error counts against the circuit
+	isFailure func(err error) bool
 
 	// state is the current state
 	state State
@@ -52,11 +58,18 @@ func NewCircuitBreaker(name string, config *Config) *CircuitBreaker {
 		panic(fmt.Sprintf("invalid circuit breaker config: %v", err))
 	}
 
+	isFailure := config.IsFailure
+	if isFailure == nil {
+		isFailure = DefaultIsFailure
+	}
+
 	cb := &CircuitBreaker{
 		name:                config.Name,
 		maxFailures:         config.MaxFailures,
 		timeout:             config.Timeout,
 		maxHalfOpenRequests: config.MaxHalfOpenRequests,
+		ignoreCancellations: config.IgnoreCancellations,
+		isFailure:           isFailure,
 		state:               StateClosed,
 		failures:            0,
 		halfOpenRequests:    0,
@@ -70,6 +83,22 @@ func NewCircuitBreaker(name string, config *Config) *CircuitBreaker {
 
 // Call executes the given function with circuit breaker protection
 func (cb *CircuitBreaker) Call(fn func() error) error {
+	return cb.CallContext(context.Background(), func(context.Context) error {
+		return fn()
+	})
+}
+
+// CallContext executes fn with circuit breaker protection unless ctx is
+// already done, in which case it returns the context's error without
+// running fn or recording a result. With IgnoreCancellations set, calls
+// failing after ctx was cancelled are recorded as neither failures nor
+// successes. Errors the IsFailure predicate rejects are returned but recorded
+// as successes.
+func (cb *CircuitBreaker) CallContext(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Transition to half-open if timeout has elapsed in open state
 	cb.mutex.Lock()
 	shouldTransition := cb.state == StateOpen && cb.canAttemptReset()
@@ -87,11 +116,18 @@ func (cb *CircuitBreaker) Call(fn func() error) error {
 	recordRequestAllowed(cb.name)
 
 	// Execute the function
-	err := fn()
+	err := fn(ctx)
 
 	// Record the result
 	if err != nil {
-		cb.RecordFailure(err)
+		if cb.ignoreCancellations && cancelled(ctx, err) {
+			return err
+		}
+		if cb.isFailure(err) {
+			cb.RecordFailure(err)
+		} else {
+			cb.RecordSuccess()
+		}
 		return err
 	}
 
@@ -99,6 +135,13 @@ func (cb *CircuitBreaker) Call(fn func() error) error {
 	return nil
 }
 
+// cancelled reports whether a call failed with err because ctx was
+// cancelled. Deadlines still count as failures, since a slow dependency is
+// what the breaker guards against.
+func cancelled(ctx context.Context, err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled)
+}
+
 // allowRequest determines if a request should be allowed based on current state
 func (cb *CircuitBreaker) allowRequest() bool {
 	cb.mutex.RLock()
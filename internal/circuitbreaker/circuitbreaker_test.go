@@ -1,11 +1,15 @@
 package circuitbreaker
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"mcp-go-assistant/internal/types"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -343,6 +347,133 @@ func TestCircuitBreaker_Call(t *testing.T) {
 	})
 }
 
+func TestCircuitBreaker_CallContext(t *testing.T) {
+	t.Run("cancelled context skips call", func(t *testing.T) {
+		cb := NewCircuitBreaker("test", DefaultConfig("test"))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		called := false
+		err := cb.CallContext(ctx, func(context.Context) error {
+			called = true
+			return nil
+		})
+
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		if called {
+			t.Error("expected function not to be called")
+		}
+	})
+
+	t.Run("ignored cancellation", func(t *testing.T) {
+		config := DefaultConfig("test")
+		config.IgnoreCancellations = true
+		cb := NewCircuitBreaker("test", config)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		err := cb.CallContext(ctx, func(ctx context.Context) error {
+			cancel()
+			return ctx.Err()
+		})
+
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		if cb.Failures() != 0 {
+			t.Errorf("expected no failures, got %d", cb.Failures())
+		}
+	})
+
+	t.Run("counted cancellation", func(t *testing.T) {
+		cb := NewCircuitBreaker("test", DefaultConfig("test"))
+		ctx, cancel := context.WithCancel(context.Background())
+
+		_ = cb.CallContext(ctx, func(ctx context.Context) error {
+			cancel()
+			return ctx.Err()
+		})
+
+		if cb.Failures() != 1 {
+			t.Errorf("expected 1 failure, got %d", cb.Failures())
+		}
+	})
+
+	t.Run("deadline counts as failure", func(t *testing.T) {
+		config := DefaultConfig("test")
+		config.IgnoreCancellations = true
+		cb := NewCircuitBreaker("test", config)
+
+		_ = cb.CallContext(context.Background(), func(context.Context) error {
+			return context.DeadlineExceeded
+		})
+
+		if cb.Failures() != 1 {
+			t.Errorf("expected 1 failure, got %d", cb.Failures())
+		}
+	})
+}
+
+func TestDefaultIsFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"plain error", errors.New("boom"), true},
+		{"internal", types.NewInternalError("boom"), true},
+		{"timeout", types.NewTimeoutError("slow"), true},
+		{"wrapped internal", fmt.Errorf("call: %w", types.NewInternalError("boom")), true},
+		{"validation", types.NewValidationError("bad input"), false},
+		{"not found", types.NewNotFoundError("missing"), false},
+		{"parse failure", types.NewKindError(types.KindParseFailure, "failed to parse Go code"), false},
+		{"package not found", fmt.Errorf("lookup: %w", types.NewKindError(types.KindPackageNotFound, "go doc failed")), false},
+		{"toolchain missing", types.NewKindError(types.KindToolchainMissing, "go toolchain unavailable"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultIsFailure(tt.err); got != tt.want {
+				t.Errorf("DefaultIsFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_IsFailure(t *testing.T) {
+	t.Run("client errors do not trip", func(t *testing.T) {
+		config := DefaultConfig("test")
+		config.MaxFailures = 1
+		cb := NewCircuitBreaker("test", config)
+
+		err := cb.Call(func() error {
+			return types.NewNotFoundError("missing")
+		})
+
+		if err == nil {
+			t.Error("expected the call error to be returned")
+		}
+		if cb.Failures() != 0 || !cb.IsClosed() {
+			t.Errorf("expected closed circuit without failures, got %s with %d", cb.State(), cb.Failures())
+		}
+	})
+
+	t.Run("custom predicate", func(t *testing.T) {
+		config := DefaultConfig("test")
+		config.IsFailure = func(error) bool { return false }
+		cb := NewCircuitBreaker("test", config)
+
+		_ = cb.Call(func() error {
+			return errors.New("test error")
+		})
+
+		if cb.Failures() != 0 {
+			t.Errorf("expected no failures, got %d", cb.Failures())
+		}
+	})
+}
+
 func TestCircuitBreaker_Transitions(t *testing.T) {
 	config := DefaultConfig("test")
 	config.MaxFailures = 3
@@ -1,8 +1,11 @@
 package circuitbreaker
 
 import (
+	"errors"
 	"fmt"
 	"time"
+
+	"mcp-go-assistant/internal/types"
 )
 
 // Config holds circuit breaker configuration
@@ -13,6 +16,12 @@ type Config struct {
 	Timeout time.Duration
 	// MaxHalfOpenRequests is the number of requests allowed in half-open state (default 3)
 	MaxHalfOpenRequests int
+	// IgnoreCancellations keeps calls that fail because their context was
+	// cancelled from counting as failures or successes
+	IgnoreCancellations bool
+	// IsFailure reports whether an error returned by a call counts against
+	// the circuit; other errors count as successes (default DefaultIsFailure)
+	IsFailure func(err error) bool
 	// Name is the circuit breaker name
 	Name string
 }
@@ -47,3 +56,23 @@ func DefaultConfig(name string) *Config {
 		Name:                name,
 	}
 }
+
+// DefaultIsFailure counts internal and timeout errors as failures, leaving
+// out errors caused by the request such as validation or NOT_FOUND errors
+// and tool errors of a kind caused by the input, like unparsable code.
+// Other errors without an MCP category are assumed internal.
+func DefaultIsFailure(err error) bool {
+	if kind, ok := types.KindOf(err); ok && kind.CausedByInput() {
+		return false
+	}
+	var mcpErr types.MCPError
+	if !errors.As(err, &mcpErr) {
+		return true
+	}
+	switch mcpErr.Category() {
+	case "internal", "timeout":
+		return true
+	default:
+		return false
+	}
+}
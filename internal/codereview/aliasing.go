@@ -0,0 +1,320 @@
+package codereview
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// checkAliasing flags slices and maps shared where a copy was probably
+// intended: exported methods returning internal slice or map fields,
+// appends to slice parameters that can write into the caller's array, and
+// goroutines started in a loop using a slice the loop keeps modifying
+func (a *Analyzer) checkAliasing(file *ast.File, result *ReviewResult) {
+	fields := referenceFields(file)
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil {
+			continue
+		}
+		if fd.Recv != nil && fd.Name.IsExported() {
+			a.checkInternalReturns(fd, fields, result)
+		}
+		a.checkParamAppends(fd, result)
+		a.checkLoopGoroutines(fd, result)
+	}
+}
+
+// referenceFields returns, for each struct type in file, its fields of
+// slice or map type with their kind
+func referenceFields(file *ast.File) map[string]map[string]string {
+	fields := make(map[string]map[string]string)
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		for _, field := range st.Fields.List {
+			kind := referenceKind(field.Type)
+			if kind == "" {
+				continue
+			}
+			if fields[spec.Name.Name] == nil {
+				fields[spec.Name.Name] = make(map[string]string)
+			}
+			for _, name := range field.Names {
+				fields[spec.Name.Name][name.Name] = kind
+			}
+		}
+		return true
+	})
+	return fields
+}
+
+// referenceKind returns "slice" or "map" for slice and map types, or ""
+func referenceKind(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "slice"
+		}
+	case *ast.MapType:
+		return "map"
+	}
+	return ""
+}
+
+// checkInternalReturns reports methods returning a slice or map field of
+// their receiver as is, which lets callers change the receiver's state
+func (a *Analyzer) checkInternalReturns(fd *ast.FuncDecl, fields map[string]map[string]string, result *ReviewResult) {
+	recv := fd.Recv.List[0]
+	if len(recv.Names) == 0 {
+		return
+	}
+	recvName := recv.Names[0].Name
+	kinds := fields[receiverTypeName(recv.Type)]
+	if len(kinds) == 0 {
+		return
+	}
+
+	inspectBody(fd.Body, func(n ast.Node) bool {
+		ret, ok := n.(*ast.ReturnStmt)
+		if !ok {
+			return true
+		}
+		for _, res := range ret.Results {
+			sel, ok := res.(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+			x, ok := sel.X.(*ast.Ident)
+			kind := kinds[sel.Sel.Name]
+			if !ok || x.Name != recvName || kind == "" {
+				continue
+			}
+			field := recvName + "." + sel.Sel.Name
+			result.Issues = append(result.Issues, Issue{
+				Type:       "warning",
+				Category:   "aliasing",
+				Line:       a.getLine(res.Pos()),
+				Column:     a.getColumn(res.Pos()),
+				EndLine:    a.getLine(res.End()),
+				Message:    a.msg("aliasing.internal-"+kind, funcName(fd), sel.Sel.Name),
+				Suggestion: a.msg("aliasing.internal-"+kind+".fix", field),
+				Severity:   "medium",
+				Rule:       "internal-reference",
+			})
+		}
+		return true
+	})
+}
+
+// checkParamAppends reports appends to slice parameters. When the slice
+// has spare capacity, append writes into the caller's backing array. Appends
+// whose result the function returns, as append itself does, and parameters
+// replaced by a copy first are not reported.
+func (a *Analyzer) checkParamAppends(fd *ast.FuncDecl, result *ReviewResult) {
+	params := make(map[string]bool)
+	for _, field := range fd.Type.Params.List {
+		if referenceKind(field.Type) != "slice" {
+			continue
+		}
+		for _, name := range field.Names {
+			params[name.Name] = true
+		}
+	}
+	if len(params) == 0 {
+		return
+	}
+
+	// Parameters returned or reassigned to something other than an append
+	// to themselves are owned by this function
+	owned := make(map[string]bool)
+	inspectBody(fd.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ReturnStmt:
+			for name := range params {
+				if usesIdent(node, name) {
+					owned[name] = true
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok || !params[ident.Name] || i >= len(node.Rhs) {
+					continue
+				}
+				if appendTarget(node.Rhs[i]) != ident.Name {
+					owned[ident.Name] = true
+				}
+			}
+		}
+		return true
+	})
+
+	inspectBody(fd.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		name := appendTarget(call)
+		if !params[name] || owned[name] {
+			return true
+		}
+		result.Issues = append(result.Issues, Issue{
+			Type:       "warning",
+			Category:   "aliasing",
+			Line:       a.getLine(call.Pos()),
+			Column:     a.getColumn(call.Pos()),
+			EndLine:    a.getLine(call.End()),
+			Message:    a.msg("aliasing.param-append", name),
+			Suggestion: a.msg("aliasing.param-append.fix", name),
+			Severity:   "medium",
+			Rule:       "param-append",
+		})
+		return true
+	})
+}
+
+// appendTarget returns the variable expr appends to, as in append(s, x),
+// or ""
+func appendTarget(expr ast.Expr) string {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return ""
+	}
+	if fn, ok := call.Fun.(*ast.Ident); !ok || fn.Name != "append" {
+		return ""
+	}
+	if ident, ok := call.Args[0].(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// checkLoopGoroutines reports goroutines started in a loop that use a
+// slice declared before the loop and modified in it, so every goroutine
+// shares, and races on, the same backing array
+func (a *Analyzer) checkLoopGoroutines(fd *ast.FuncDecl, result *ReviewResult) {
+	slices := sliceVars(fd)
+	if len(slices) == 0 {
+		return
+	}
+
+	reported := make(map[*ast.GoStmt]bool)
+	inspectBody(fd.Body, func(n ast.Node) bool {
+		var body *ast.BlockStmt
+		switch loop := n.(type) {
+		case *ast.ForStmt:
+			body = loop.Body
+		case *ast.RangeStmt:
+			body = loop.Body
+		default:
+			return true
+		}
+
+		reused := make(map[string]bool)
+		inspectBody(body, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok {
+				return true
+			}
+			for _, lhs := range assign.Lhs {
+				target := lhs
+				if index, ok := lhs.(*ast.IndexExpr); ok {
+					target = index.X
+				}
+				ident, ok := target.(*ast.Ident)
+				if !ok || !slices[ident.Name] {
+					continue
+				}
+				// A new variable declared in the loop is not shared
+				if assign.Tok == token.DEFINE && target == lhs {
+					reused[ident.Name] = false
+					return true
+				}
+				if _, declared := reused[ident.Name]; !declared {
+					reused[ident.Name] = true
+				}
+			}
+			return true
+		})
+
+		ast.Inspect(body, func(n ast.Node) bool {
+			goStmt, ok := n.(*ast.GoStmt)
+			if !ok || reported[goStmt] {
+				return true
+			}
+			for name, shared := range reused {
+				if !shared || !usesIdent(goStmt.Call, name) {
+					continue
+				}
+				reported[goStmt] = true
+				result.Issues = append(result.Issues, Issue{
+					Type:       "warning",
+					Category:   "aliasing",
+					Line:       a.getLine(goStmt.Pos()),
+					Column:     a.getColumn(goStmt.Pos()),
+					EndLine:    a.getLine(goStmt.End()),
+					Message:    a.msg("aliasing.loop-goroutine", name),
+					Suggestion: a.msg("aliasing.loop-goroutine.fix", name),
+					Severity:   "high",
+					Rule:       "loop-slice-goroutine",
+				})
+				break
+			}
+			return true
+		})
+		return true
+	})
+}
+
+// sliceVars returns the names fd declares with a slice type or a slice
+// literal or make call
+func sliceVars(fd *ast.FuncDecl) map[string]bool {
+	vars := make(map[string]bool)
+	for _, field := range fd.Type.Params.List {
+		if referenceKind(field.Type) == "slice" {
+			for _, name := range field.Names {
+				vars[name.Name] = true
+			}
+		}
+	}
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ValueSpec:
+			for i, name := range node.Names {
+				if referenceKind(node.Type) == "slice" || (i < len(node.Values) && isSliceValue(node.Values[i])) {
+					vars[name.Name] = true
+				}
+			}
+		case *ast.AssignStmt:
+			if node.Tok != token.DEFINE || len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && isSliceValue(node.Rhs[i]) {
+					vars[ident.Name] = true
+				}
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// isSliceValue reports whether expr is a slice literal or a make call
+// creating a slice
+func isSliceValue(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		return referenceKind(e.Type) == "slice"
+	case *ast.CallExpr:
+		fn, ok := e.Fun.(*ast.Ident)
+		return ok && fn.Name == "make" && len(e.Args) > 0 && referenceKind(e.Args[0]) == "slice"
+	}
+	return false
+}
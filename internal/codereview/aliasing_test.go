@@ -0,0 +1,97 @@
+package codereview
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAnalyzeCode_Aliasing(t *testing.T) {
+	code := `package app
+
+import "slices"
+
+type Store struct {
+	items []string
+	index map[string]int
+	name  string
+}
+
+func (s *Store) Items() []string {
+	return s.items
+}
+
+func (s *Store) Index() map[string]int {
+	return s.index
+}
+
+func (s *Store) Copy() []string {
+	return slices.Clone(s.items)
+}
+
+func (s *Store) Name() string {
+	return s.name
+}
+
+func (s *Store) all() []string {
+	return s.items
+}
+
+func Tag(tags []string, tag string) {
+	tags = append(tags, tag)
+	record(tags)
+}
+
+func AppendTag(tags []string, tag string) []string {
+	return append(tags, tag)
+}
+
+func CloneTag(tags []string, tag string) {
+	tags = slices.Clone(tags)
+	tags = append(tags, tag)
+	record(tags)
+}
+
+func Batch(lines []string) {
+	buf := make([]string, 0, 10)
+	for _, line := range lines {
+		buf = append(buf[:0], line)
+		go record(buf)
+	}
+	for _, line := range lines {
+		own := []string{line}
+		own = append(own, line)
+		go record(own)
+	}
+}
+
+func record([]string) {}
+`
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[string]bool{}
+	for _, issue := range result.Issues {
+		if issue.Category == "aliasing" {
+			got[fmt.Sprintf("%d %s: %s", issue.Line, issue.Rule, issue.Message)] = true
+		}
+	}
+
+	want := []string{
+		"12 internal-reference: Store.Items returns the internal slice 'items'; callers can modify the receiver's elements through it",
+		"16 internal-reference: Store.Index returns the internal map 'index'; callers can add, change and delete the receiver's entries through it",
+		"32 param-append: Appending to parameter 'tags' may write into the caller's backing array when it has spare capacity",
+		"50 loop-slice-goroutine: Goroutine started in a loop uses slice 'buf', which the loop keeps modifying; all goroutines share its backing array",
+	}
+	for _, issue := range want {
+		if !got[issue] {
+			t.Errorf("expected issue %q", issue)
+		}
+		delete(got, issue)
+	}
+	for issue := range got {
+		t.Errorf("unexpected aliasing issue %q", issue)
+	}
+}
@@ -1,41 +1,105 @@
 package codereview
 
 import (
-	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
 	"strings"
 	"unicode"
+	"unicode/utf8"
+
+	"mcp-go-assistant/internal/i18n"
+	"mcp-go-assistant/pkg/reviewrule"
+)
+
+// Snippet context defaults
+const (
+	DefaultContextLines = 2
+	MaxContextLines     = 10
+
+	// maxSnippetSpan caps how many lines of a multi-line node are included
+	maxSnippetSpan = 10
 )
 
 // Analyzer performs Go code analysis
 type Analyzer struct {
-	fset       *token.FileSet
-	guidelines []string
-	hint       string
+	fset         *token.FileSet
+	rules        *RuleSet
+	hint         string
+	language     string
+	contextLines int
+	thresholds   Thresholds
+	coverage     []coverageBlock   // Coverage blocks of the analyzed file, if known
+	stream       StreamFunc        // Optional; receives issues as each check finishes
+	streamed     int               // Number of issues already sent to stream
+	ignores      []ignoreScope     // Ignore directives in the analyzed file
+	suppressed   map[string]int    // Issues removed by ignore directives, by rule
+	external     []reviewrule.Rule // Rules loaded from plugins, run after the built-in checks
 }
 
 // NewAnalyzer creates a new code analyzer
 func NewAnalyzer(guidelines []string, hint string) *Analyzer {
+	return NewAnalyzerWithRules(NewRuleSet(guidelines), hint)
+}
+
+// NewAnalyzerWithRules creates a code analyzer using a prebuilt, possibly
+// shared, rule set. Only the per-request state is allocated.
+func NewAnalyzerWithRules(rules *RuleSet, hint string) *Analyzer {
 	return &Analyzer{
-		fset:       token.NewFileSet(),
-		guidelines: guidelines,
-		hint:       hint,
+		fset:         token.NewFileSet(),
+		rules:        rules,
+		hint:         hint,
+		language:     i18n.DefaultLanguage,
+		contextLines: DefaultContextLines,
+		thresholds:   DefaultThresholds(),
 	}
 }
 
+// SetLanguage sets the language used for issue messages and summaries
+func (a *Analyzer) SetLanguage(lang string) {
+	a.language = i18n.Normalize(lang)
+}
+
+// SetContextLines sets the number of context lines included in issue
+// snippets, clamped to MaxContextLines
+func (a *Analyzer) SetContextLines(n int) {
+	if n < 0 {
+		n = 0
+	}
+	if n > MaxContextLines {
+		n = MaxContextLines
+	}
+	a.contextLines = n
+}
+
+// SetThresholds sets the limits for structure and complexity issues; zero
+// fields keep DefaultThresholds
+func (a *Analyzer) SetThresholds(t Thresholds) {
+	a.thresholds = t.WithDefaults(DefaultThresholds())
+}
+
+// setCoverage sets the coverage blocks of the analyzed file, enabling
+// coverage-aware prioritization
+func (a *Analyzer) setCoverage(blocks []coverageBlock) {
+	a.coverage = blocks
+}
+
+// msg returns the localized message for key formatted with args
+func (a *Analyzer) msg(key string, args ...interface{}) string {
+	return messages.Translate(a.language, key, args...)
+}
+
 // AnalyzeCode performs comprehensive Go code analysis
 func (a *Analyzer) AnalyzeCode(code string) (*ReviewResult, error) {
 	// Parse the Go code
 	file, err := parser.ParseFile(a.fset, "", code, parser.ParseComments)
 	if err != nil {
 		return &ReviewResult{
-			Summary: "Code parsing failed",
+			Summary: a.msg("summary.parse-failed"),
 			Issues: []Issue{{
 				Type:     "error",
 				Category: "syntax",
-				Message:  "Failed to parse Go code: " + err.Error(),
+				Message:  a.msg("syntax.parse-failed", err.Error()),
 				Severity: "critical",
 				Rule:     "valid-syntax",
 			}},
@@ -49,16 +113,13 @@ func (a *Analyzer) AnalyzeCode(code string) (*ReviewResult, error) {
 		Metrics:     a.calculateMetrics(file, code),
 	}
 
-	// Perform various checks
-	a.checkNaming(file, result)
-	a.checkStructure(file, result)
-	a.checkComments(file, result)
-	a.checkErrorHandling(file, result)
-	a.checkPerformance(file, result)
-	a.checkSecurity(file, result)
-	a.checkTestability(file, result)
-	a.checkComplexity(file, result)
-	a.applyCustomGuidelines(file, result)
+	// Perform various checks, dropping findings suppressed in source
+	a.ignores = a.collectIgnores(file)
+	a.runChecks(file, result, code)
+	sortIssues(result.Issues)
+	sortSuggestions(result.Suggestions)
+	result.Suppressed = a.suppressed
+	a.attachSnippets(result.Issues, code)
 
 	// Calculate overall score
 	result.Score = a.calculateScore(result)
@@ -77,8 +138,10 @@ func (a *Analyzer) checkNaming(file *ast.File, result *ReviewResult) {
 					Type:       "warning",
 					Category:   "naming",
 					Line:       a.getLine(node.Pos()),
-					Message:    "Exported function should start with capital letter",
-					Suggestion: "Rename to " + capitalize(node.Name.Name),
+					Column:     a.getColumn(node.Pos()),
+					EndLine:    a.getLine(node.End()),
+					Message:    a.msg("naming.exported-function"),
+					Suggestion: a.msg("naming.rename", capitalize(node.Name.Name)),
 					Severity:   "medium",
 					Rule:       "exported-naming",
 				})
@@ -88,8 +151,10 @@ func (a *Analyzer) checkNaming(file *ast.File, result *ReviewResult) {
 					Type:       "style",
 					Category:   "naming",
 					Line:       a.getLine(node.Pos()),
-					Message:    "Function names should use camelCase, not underscores",
-					Suggestion: "Use camelCase naming convention",
+					Column:     a.getColumn(node.Pos()),
+					EndLine:    a.getLine(node.End()),
+					Message:    a.msg("naming.camel-case"),
+					Suggestion: a.msg("naming.camel-case.fix"),
 					Severity:   "low",
 					Rule:       "camel-case",
 				})
@@ -100,8 +165,10 @@ func (a *Analyzer) checkNaming(file *ast.File, result *ReviewResult) {
 					Type:       "warning",
 					Category:   "naming",
 					Line:       a.getLine(node.Pos()),
-					Message:    "Exported type should start with capital letter",
-					Suggestion: "Rename to " + capitalize(node.Name.Name),
+					Column:     a.getColumn(node.Pos()),
+					EndLine:    a.getLine(node.End()),
+					Message:    a.msg("naming.exported-type"),
+					Suggestion: a.msg("naming.rename", capitalize(node.Name.Name)),
 					Severity:   "medium",
 					Rule:       "exported-naming",
 				})
@@ -115,8 +182,10 @@ func (a *Analyzer) checkNaming(file *ast.File, result *ReviewResult) {
 								Type:       "warning",
 								Category:   "naming",
 								Line:       a.getLine(name.Pos()),
-								Message:    "Exported variable should start with capital letter",
-								Suggestion: "Rename to " + capitalize(name.Name),
+								Column:     a.getColumn(name.Pos()),
+								EndLine:    a.getLine(name.End()),
+								Message:    a.msg("naming.exported-variable"),
+								Suggestion: a.msg("naming.rename", capitalize(name.Name)),
 								Severity:   "medium",
 								Rule:       "exported-naming",
 							})
@@ -140,40 +209,46 @@ func (a *Analyzer) checkStructure(file *ast.File, result *ReviewResult) {
 			functionCount++
 			if node.Body != nil {
 				lineCount := a.getLine(node.End()) - a.getLine(node.Pos())
-				if lineCount > 50 {
+				if lineCount > a.thresholds.FunctionLines {
 					longFunctions++
 					result.Issues = append(result.Issues, Issue{
 						Type:       "warning",
 						Category:   "structure",
 						Line:       a.getLine(node.Pos()),
-						Message:    "Function is too long (>50 lines). Consider breaking it down",
-						Suggestion: "Split into smaller, focused functions",
+						Column:     a.getColumn(node.Pos()),
+						EndLine:    a.getLine(node.End()),
+						Message:    a.msg("structure.function-length", a.thresholds.FunctionLines),
+						Suggestion: a.msg("structure.function-length.fix"),
 						Severity:   "medium",
 						Rule:       "function-length",
 					})
 				}
 
 				// Check for too many parameters
-				if node.Type.Params != nil && len(node.Type.Params.List) > 5 {
+				if node.Type.Params != nil && len(node.Type.Params.List) > a.thresholds.Parameters {
 					result.Issues = append(result.Issues, Issue{
 						Type:       "warning",
 						Category:   "structure",
 						Line:       a.getLine(node.Pos()),
-						Message:    "Function has too many parameters (>5)",
-						Suggestion: "Consider using a struct to group related parameters",
+						Column:     a.getColumn(node.Pos()),
+						EndLine:    a.getLine(node.End()),
+						Message:    a.msg("structure.parameter-count", a.thresholds.Parameters),
+						Suggestion: a.msg("structure.parameter-count.fix"),
 						Severity:   "medium",
 						Rule:       "parameter-count",
 					})
 				}
 			}
 		case *ast.StructType:
-			if len(node.Fields.List) > 10 {
+			if len(node.Fields.List) > a.thresholds.StructFields {
 				result.Issues = append(result.Issues, Issue{
 					Type:       "warning",
 					Category:   "structure",
 					Line:       a.getLine(node.Pos()),
-					Message:    "Struct has many fields (>10). Consider if it's doing too much",
-					Suggestion: "Consider breaking into smaller structs",
+					Column:     a.getColumn(node.Pos()),
+					EndLine:    a.getLine(node.End()),
+					Message:    a.msg("structure.struct-size", a.thresholds.StructFields),
+					Suggestion: a.msg("structure.struct-size.fix"),
 					Severity:   "low",
 					Rule:       "struct-size",
 				})
@@ -185,8 +260,8 @@ func (a *Analyzer) checkStructure(file *ast.File, result *ReviewResult) {
 	if functionCount > 20 {
 		result.Suggestions = append(result.Suggestions, Suggestion{
 			Category: "structure",
-			Message:  "File contains many functions. Consider splitting into multiple files",
-			Impact:   "Improved maintainability and organization",
+			Message:  a.msg("structure.many-functions"),
+			Impact:   a.msg("structure.many-functions.impact"),
 		})
 	}
 }
@@ -201,8 +276,10 @@ func (a *Analyzer) checkComments(file *ast.File, result *ReviewResult) {
 					Type:       "warning",
 					Category:   "documentation",
 					Line:       a.getLine(node.Pos()),
-					Message:    "Exported function lacks documentation comment",
-					Suggestion: "Add a comment starting with function name",
+					Column:     a.getColumn(node.Pos()),
+					EndLine:    a.getLine(node.End()),
+					Message:    a.msg("docs.exported-function"),
+					Suggestion: a.msg("docs.exported-function.fix"),
 					Severity:   "medium",
 					Rule:       "exported-docs",
 				})
@@ -215,8 +292,10 @@ func (a *Analyzer) checkComments(file *ast.File, result *ReviewResult) {
 							Type:       "warning",
 							Category:   "documentation",
 							Line:       a.getLine(node.Pos()),
-							Message:    "Exported type lacks documentation comment",
-							Suggestion: "Add a comment starting with type name",
+							Column:     a.getColumn(node.Pos()),
+							EndLine:    a.getLine(node.End()),
+							Message:    a.msg("docs.exported-type"),
+							Suggestion: a.msg("docs.exported-type.fix"),
 							Severity:   "medium",
 							Rule:       "exported-docs",
 						})
@@ -242,8 +321,10 @@ func (a *Analyzer) checkErrorHandling(file *ast.File, result *ReviewResult) {
 									Type:       "warning",
 									Category:   "error-handling",
 									Line:       a.getLine(node.Pos()),
-									Message:    "Error is being ignored",
-									Suggestion: "Handle error appropriately",
+									Column:     a.getColumn(node.Pos()),
+									EndLine:    a.getLine(node.End()),
+									Message:    a.msg("error-handling.ignored"),
+									Suggestion: a.msg("error-handling.ignored.fix"),
 									Severity:   "high",
 									Rule:       "error-handling",
 								})
@@ -269,8 +350,10 @@ func (a *Analyzer) checkPerformance(file *ast.File, result *ReviewResult) {
 							Type:       "warning",
 							Category:   "performance",
 							Line:       a.getLine(binExpr.Pos()),
-							Message:    "String concatenation in loop can be inefficient",
-							Suggestion: "Consider using strings.Builder or bytes.Buffer",
+							Column:     a.getColumn(binExpr.Pos()),
+							EndLine:    a.getLine(binExpr.End()),
+							Message:    a.msg("performance.string-concat"),
+							Suggestion: a.msg("performance.string-concat.fix"),
 							Severity:   "medium",
 							Rule:       "string-concatenation",
 						})
@@ -295,8 +378,10 @@ func (a *Analyzer) checkSecurity(file *ast.File, result *ReviewResult) {
 							Type:       "warning",
 							Category:   "security",
 							Line:       a.getLine(node.Pos()),
-							Message:    "Use of unsafe package should be carefully reviewed",
-							Suggestion: "Ensure unsafe operations are necessary and correct",
+							Column:     a.getColumn(node.Pos()),
+							EndLine:    a.getLine(node.End()),
+							Message:    a.msg("security.unsafe"),
+							Suggestion: a.msg("security.unsafe.fix"),
 							Severity:   "high",
 							Rule:       "unsafe-usage",
 						})
@@ -333,9 +418,9 @@ func (a *Analyzer) checkTestability(file *ast.File, result *ReviewResult) {
 	if hasGlobalVars {
 		result.Suggestions = append(result.Suggestions, Suggestion{
 			Category: "testability",
-			Message:  "Global variables can make testing difficult",
-			Example:  "Consider dependency injection or configuration structs",
-			Impact:   "Improved testability and maintainability",
+			Message:  a.msg("testability.globals"),
+			Example:  a.msg("testability.globals.example"),
+			Impact:   a.msg("testability.globals.impact"),
 		})
 	}
 }
@@ -346,13 +431,15 @@ func (a *Analyzer) checkComplexity(file *ast.File, result *ReviewResult) {
 		switch node := n.(type) {
 		case *ast.FuncDecl:
 			complexity := calculateCyclomaticComplexity(node)
-			if complexity > 10 {
+			if complexity > a.thresholds.Complexity {
 				result.Issues = append(result.Issues, Issue{
 					Type:       "warning",
 					Category:   "complexity",
 					Line:       a.getLine(node.Pos()),
-					Message:    "Function has high cyclomatic complexity",
-					Suggestion: "Consider breaking down into smaller functions",
+					Column:     a.getColumn(node.Pos()),
+					EndLine:    a.getLine(node.End()),
+					Message:    a.msg("complexity.cyclomatic"),
+					Suggestion: a.msg("complexity.cyclomatic.fix"),
 					Severity:   "medium",
 					Rule:       "cyclomatic-complexity",
 				})
@@ -364,29 +451,30 @@ func (a *Analyzer) checkComplexity(file *ast.File, result *ReviewResult) {
 
 // applyCustomGuidelines applies user-provided guidelines
 func (a *Analyzer) applyCustomGuidelines(file *ast.File, result *ReviewResult) {
-	for _, guideline := range a.guidelines {
-		// Simple pattern matching for custom guidelines
-		if strings.Contains(strings.ToLower(guideline), "no panic") {
-			ast.Inspect(file, func(n ast.Node) bool {
-				if call, ok := n.(*ast.CallExpr); ok {
-					if ident, ok := call.Fun.(*ast.Ident); ok {
-						if ident.Name == "panic" {
-							result.Issues = append(result.Issues, Issue{
-								Type:       "warning",
-								Category:   "custom",
-								Line:       a.getLine(call.Pos()),
-								Message:    "Custom guideline: avoid using panic",
-								Suggestion: "Return an error instead",
-								Severity:   "medium",
-								Rule:       "custom-no-panic",
-							})
-						}
-					}
+	if !a.rules.noPanic {
+		return
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok {
+				if ident.Name == "panic" {
+					result.Issues = append(result.Issues, Issue{
+						Type:       "warning",
+						Category:   "custom",
+						Line:       a.getLine(call.Pos()),
+						Column:     a.getColumn(call.Pos()),
+						EndLine:    a.getLine(call.End()),
+						Message:    a.msg("custom.no-panic"),
+						Suggestion: a.msg("custom.no-panic.fix"),
+						Severity:   "medium",
+						Rule:       "custom-no-panic",
+					})
 				}
-				return true
-			})
+			}
 		}
-	}
+		return true
+	})
 }
 
 // Helper functions
@@ -396,6 +484,10 @@ func (a *Analyzer) getLine(pos token.Pos) int {
 	return position.Line
 }
 
+func (a *Analyzer) getColumn(pos token.Pos) int {
+	return a.fset.Position(pos).Column
+}
+
 func (a *Analyzer) calculateMetrics(file *ast.File, code string) Metrics {
 	lines := strings.Split(code, "\n")
 	functionCount := 0
@@ -459,28 +551,29 @@ func (a *Analyzer) generateSummary(result *ReviewResult) string {
 	issueCount := len(result.Issues)
 	suggestionCount := len(result.Suggestions)
 
-	if issueCount == 0 {
-		return "Code looks good! No major issues found."
+	summary := a.msg("summary.clean")
+	if issueCount > 0 {
+		summary = a.msg("summary.issues", issueCount, suggestionCount, result.Score)
 	}
-
-	return fmt.Sprintf("Found %d issues and %d suggestions. Overall score: %d/100",
-		issueCount, suggestionCount, result.Score)
+	if n := suppressedCount(result.Suppressed); n > 0 {
+		summary += " " + a.msg("summary.suppressed", n)
+	}
+	return summary
 }
 
 // Utility functions
 
 func isCapitalized(name string) bool {
-	if len(name) == 0 {
-		return false
-	}
-	return unicode.IsUpper(rune(name[0]))
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
 }
 
 func capitalize(name string) string {
-	if len(name) == 0 {
+	r, size := utf8.DecodeRuneInString(name)
+	if size == 0 {
 		return name
 	}
-	return strings.ToUpper(name[:1]) + name[1:]
+	return string(unicode.ToUpper(r)) + name[size:]
 }
 
 func containsUnderscore(name string) bool {
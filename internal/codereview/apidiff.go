@@ -0,0 +1,251 @@
+package codereview
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"sort"
+
+	"mcp-go-assistant/internal/types"
+)
+
+// apiSymbol is a single exported element of a package API
+type apiSymbol struct {
+	kind      string
+	signature string
+	line      int
+}
+
+// apiSurface maps qualified symbol names to their exported declarations
+type apiSurface map[string]apiSymbol
+
+// CompareAPI parses two versions of a package's source and reports the
+// exported API changes between them, classified as breaking or compatible
+func CompareAPI(oldCode, newCode string) ([]APIChange, error) {
+	oldAPI, err := extractAPI(oldCode)
+	if err != nil {
+		return nil, types.NewKindError(types.KindParseFailure, "failed to parse previous code: %v", err)
+	}
+	newAPI, err := extractAPI(newCode)
+	if err != nil {
+		return nil, types.NewKindError(types.KindParseFailure, "failed to parse new code: %v", err)
+	}
+
+	changes := []APIChange{}
+
+	for name, oldSym := range oldAPI {
+		newSym, ok := newAPI[name]
+		if !ok {
+			changes = append(changes, APIChange{
+				Symbol:   name,
+				Kind:     oldSym.kind,
+				Change:   "removed",
+				Breaking: true,
+				Old:      oldSym.signature,
+			})
+			continue
+		}
+		if oldSym.kind != newSym.kind || oldSym.signature != newSym.signature {
+			changes = append(changes, APIChange{
+				Symbol:   name,
+				Kind:     newSym.kind,
+				Change:   "changed",
+				Breaking: isBreakingChange(oldSym, newSym),
+				Old:      oldSym.signature,
+				New:      newSym.signature,
+				Line:     newSym.line,
+			})
+		}
+	}
+
+	for name, newSym := range newAPI {
+		if _, ok := oldAPI[name]; ok {
+			continue
+		}
+		changes = append(changes, APIChange{
+			Symbol: name,
+			Kind:   newSym.kind,
+			Change: "added",
+			// New methods on an interface break every existing implementation
+			Breaking: newSym.kind == "interface-method",
+			New:      newSym.signature,
+			Line:     newSym.line,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Breaking != changes[j].Breaking {
+			return changes[i].Breaking
+		}
+		return changes[i].Symbol < changes[j].Symbol
+	})
+
+	return changes, nil
+}
+
+// isBreakingChange reports whether replacing oldSym with newSym breaks callers
+func isBreakingChange(oldSym, newSym apiSymbol) bool {
+	// Constant values may change without breaking compilation
+	if oldSym.kind == "constant" && newSym.kind == "constant" {
+		return false
+	}
+	return true
+}
+
+// extractAPI collects the exported API surface of the given source
+func extractAPI(code string) (apiSurface, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", code, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	api := make(apiSurface)
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			name := d.Name.Name
+			kind := "function"
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				recv := receiverTypeName(d.Recv.List[0].Type)
+				if !ast.IsExported(recv) {
+					continue
+				}
+				name = recv + "." + name
+				kind = "method"
+			}
+			api[name] = apiSymbol{
+				kind:      kind,
+				signature: nodeString(fset, d.Type),
+				line:      fset.Position(d.Pos()).Line,
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if s.Name.IsExported() {
+						addTypeAPI(fset, api, s)
+					}
+				case *ast.ValueSpec:
+					kind := "variable"
+					if d.Tok == token.CONST {
+						kind = "constant"
+					}
+					for i, name := range s.Names {
+						if !name.IsExported() {
+							continue
+						}
+						signature := ""
+						if s.Type != nil {
+							signature = nodeString(fset, s.Type)
+						}
+						if kind == "constant" && i < len(s.Values) {
+							signature += " = " + nodeString(fset, s.Values[i])
+						}
+						api[name.Name] = apiSymbol{
+							kind:      kind,
+							signature: signature,
+							line:      fset.Position(name.Pos()).Line,
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return api, nil
+}
+
+// addTypeAPI records an exported type along with its exported struct fields
+// or interface methods
+func addTypeAPI(fset *token.FileSet, api apiSurface, spec *ast.TypeSpec) {
+	typeName := spec.Name.Name
+	line := fset.Position(spec.Pos()).Line
+
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		api[typeName] = apiSymbol{kind: "type", signature: "struct", line: line}
+		for _, field := range t.Fields.List {
+			for _, name := range field.Names {
+				if !name.IsExported() {
+					continue
+				}
+				api[typeName+"."+name.Name] = apiSymbol{
+					kind:      "field",
+					signature: nodeString(fset, field.Type),
+					line:      fset.Position(name.Pos()).Line,
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		api[typeName] = apiSymbol{kind: "type", signature: "interface", line: line}
+		for _, method := range t.Methods.List {
+			if len(method.Names) == 0 {
+				// Embedded interfaces are tracked by their printed form
+				embedded := nodeString(fset, method.Type)
+				api[typeName+"."+embedded] = apiSymbol{
+					kind:      "interface-method",
+					signature: embedded,
+					line:      fset.Position(method.Pos()).Line,
+				}
+				continue
+			}
+			for _, name := range method.Names {
+				api[typeName+"."+name.Name] = apiSymbol{
+					kind:      "interface-method",
+					signature: nodeString(fset, method.Type),
+					line:      fset.Position(name.Pos()).Line,
+				}
+			}
+		}
+	default:
+		api[typeName] = apiSymbol{kind: "type", signature: nodeString(fset, spec.Type), line: line}
+	}
+}
+
+// receiverTypeName returns the base type name of a method receiver
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	}
+	return ""
+}
+
+// nodeString prints an AST node in canonical Go syntax
+func nodeString(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// addAPIChangeIssues reports breaking API changes as review issues
+func (a *Analyzer) addAPIChangeIssues(result *ReviewResult) {
+	for _, change := range result.APIChanges {
+		if !change.Breaking {
+			continue
+		}
+		result.Issues = append(result.Issues, Issue{
+			Type:       "error",
+			Category:   "api-stability",
+			Line:       change.Line,
+			Message:    a.msg("api.breaking-change", change.Kind, change.Symbol, change.Change),
+			Suggestion: a.msg("api.breaking-change.fix"),
+			Severity:   "high",
+			Rule:       "api-breaking-change",
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package codereview
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/token"
+	"reflect"
+)
+
+var (
+	posType    = reflect.TypeOf(token.NoPos)
+	tokenType  = reflect.TypeOf(token.ILLEGAL)
+	objectType = reflect.TypeOf((*ast.Object)(nil))
+	scopeType  = reflect.TypeOf((*ast.Scope)(nil))
+	nodeType   = reflect.TypeOf((*ast.Node)(nil)).Elem()
+)
+
+// astPosition is a source position in the JSON AST
+type astPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// encodeAST returns file as JSON for rules running outside the process.
+// Every node is an object with its Go type name in "_type", its extent in
+// "_pos" and "_end", and its exported fields under their Go names. Tokens
+// are encoded as their text and positions as line and column. Scopes,
+// objects and unresolved identifiers, which are derived from the rest, are
+// left out.
+func encodeAST(fset *token.FileSet, file *ast.File) ([]byte, error) {
+	return json.Marshal(astValue(fset, reflect.ValueOf(file)))
+}
+
+// astValue converts an AST value to plain JSON-encodable data
+func astValue(fset *token.FileSet, v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() || v.Type() == objectType || v.Type() == scopeType {
+			return nil
+		}
+		return astValue(fset, v.Elem())
+	case reflect.Slice:
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = astValue(fset, v.Index(i))
+		}
+		return items
+	case reflect.Struct:
+		return astObject(fset, v)
+	}
+
+	switch v.Type() {
+	case posType:
+		return astPos(fset, v.Interface().(token.Pos))
+	case tokenType:
+		return v.Interface().(token.Token).String()
+	}
+	return v.Interface()
+}
+
+// astObject converts an AST node struct to a JSON object
+func astObject(fset *token.FileSet, v reflect.Value) map[string]any {
+	obj := map[string]any{"_type": v.Type().Name()}
+	if v.CanAddr() && v.Addr().Type().Implements(nodeType) {
+		node := v.Addr().Interface().(ast.Node)
+		obj["_pos"] = astPos(fset, node.Pos())
+		obj["_end"] = astPos(fset, node.End())
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() || (v.Type().Name() == "File" && field.Name == "Unresolved") {
+			continue
+		}
+		if value := astValue(fset, v.Field(i)); value != nil {
+			obj[field.Name] = value
+		}
+	}
+	return obj
+}
+
+// astPos converts pos to a line and column, or nil when it is unknown
+func astPos(fset *token.FileSet, pos token.Pos) any {
+	if !pos.IsValid() {
+		return nil
+	}
+	p := fset.Position(pos)
+	return astPosition{Line: p.Line, Column: p.Column}
+}
@@ -0,0 +1,212 @@
+package codereview
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Audiences of the review summary
+const (
+	AudienceHuman = "human" // Prose summary in the requested language
+	AudienceLLM   = "llm"   // Compact, deterministic block for a calling model
+)
+
+// briefTopIssues is the number of issues listed in a brief
+const briefTopIssues = 5
+
+// Next actions of a brief
+const (
+	ActionFix             = "fix"               // Apply the issue's suggestion
+	ActionAddTests        = "add-tests"         // Cover the file's untested code with issues
+	ActionReviewAPIChange = "review-api-change" // Confirm a breaking API change is intended
+	ActionNone            = "none"              // Nothing left to do
+)
+
+// Brief is the summary of a review for the model that called the tool:
+// counts instead of prose, the most severe issues and what to do next.
+// Its content depends only on the review result.
+type Brief struct {
+	Score       int            `json:"score"`
+	Issues      int            `json:"issues"`
+	Severities  map[string]int `json:"severities"` // Issues by severity
+	Categories  map[string]int `json:"categories"` // Issues by category
+	TopIssues   []BriefIssue   `json:"top_issues"` // At most five, most severe first
+	NextActions []NextAction   `json:"next_actions"`
+}
+
+// BriefIssue is an issue listed in a brief
+type BriefIssue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Category string `json:"category"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line"`
+	Message  string `json:"message"`
+}
+
+// NextAction is a follow-up step recommended by a brief
+type NextAction struct {
+	Action string `json:"action"` // "fix", "add-tests", "review-api-change" or "none"
+	Rule   string `json:"rule,omitempty"`
+	Target string `json:"target,omitempty"` // Location or symbol, e.g. "store.go:12" or "Store.Get"
+	Detail string `json:"detail,omitempty"`
+}
+
+// isValidAudience reports whether audience is a supported summary audience
+func isValidAudience(audience string) bool {
+	switch strings.ToLower(audience) {
+	case "", AudienceHuman, AudienceLLM:
+		return true
+	}
+	return false
+}
+
+// applyAudience replaces the summary of result with its brief when the
+// review is for a model. issues are all issues of the review, which for
+// workspaces are more than the top issues in result.
+func applyAudience(result *ReviewResult, issues []Issue, audience string) {
+	if strings.ToLower(audience) != AudienceLLM {
+		return
+	}
+	result.Brief = newBrief(result, issues)
+	result.Summary = result.Brief.String()
+}
+
+// newBrief builds the brief of result with issues
+func newBrief(result *ReviewResult, issues []Issue) *Brief {
+	b := &Brief{
+		Score:       result.Score,
+		Issues:      len(issues),
+		Severities:  make(map[string]int),
+		Categories:  make(map[string]int),
+		TopIssues:   []BriefIssue{},
+		NextActions: []NextAction{},
+	}
+	for _, issue := range issues {
+		b.Severities[issue.Severity]++
+		b.Categories[issue.Category]++
+	}
+
+	sorted := make([]Issue, len(issues))
+	copy(sorted, issues)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, c := sorted[i], sorted[j]
+		if ra, rc := severityRank(a.Severity), severityRank(c.Severity); ra != rc {
+			return ra < rc
+		}
+		if a.File != c.File {
+			return a.File < c.File
+		}
+		if a.Line != c.Line {
+			return a.Line < c.Line
+		}
+		if a.Column != c.Column {
+			return a.Column < c.Column
+		}
+		return a.Rule < c.Rule
+	})
+	if len(sorted) > briefTopIssues {
+		sorted = sorted[:briefTopIssues]
+	}
+
+	for _, issue := range sorted {
+		b.TopIssues = append(b.TopIssues, BriefIssue{
+			Rule:     issue.Rule,
+			Severity: issue.Severity,
+			Category: issue.Category,
+			File:     issue.File,
+			Line:     issue.Line,
+			Message:  issue.Message,
+		})
+		b.NextActions = append(b.NextActions, NextAction{
+			Action: ActionFix,
+			Rule:   issue.Rule,
+			Target: location(issue.File, issue.Line),
+			Detail: issue.Suggestion,
+		})
+	}
+
+	var untested []string
+	for _, issue := range issues {
+		if issue.Uncovered && !containsString(untested, issue.File) {
+			untested = append(untested, issue.File)
+		}
+	}
+	sort.Strings(untested)
+	for _, file := range untested {
+		b.NextActions = append(b.NextActions, NextAction{Action: ActionAddTests, Target: file})
+	}
+
+	for _, change := range result.APIChanges {
+		if change.Breaking {
+			b.NextActions = append(b.NextActions, NextAction{
+				Action: ActionReviewAPIChange,
+				Target: change.Symbol,
+				Detail: change.Change + " " + change.Kind,
+			})
+		}
+	}
+
+	if len(b.NextActions) == 0 {
+		b.NextActions = append(b.NextActions, NextAction{Action: ActionNone})
+	}
+	return b
+}
+
+// location formats a file and line as file:line, or just the line for
+// single-file reviews
+func location(file string, line int) string {
+	if file == "" {
+		return fmt.Sprintf("line %d", line)
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// String renders the brief as a block of key: value lines with counts in
+// a fixed order, e.g.
+//
+//	score: 72/100
+//	issues: 3 (critical=0 high=1 medium=1 low=1)
+//	categories: error-handling=1 naming=2
+//	top_issues:
+//	1. high unchecked-error line 12: Error returned by f is not checked
+//	next_actions:
+//	- fix unchecked-error line 12: Handle or return the error
+func (b *Brief) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("score: %d/100\n", b.Score))
+
+	severities := make([]string, len(severityOrder))
+	for i, severity := range severityOrder {
+		severities[i] = fmt.Sprintf("%s=%d", severity, b.Severities[severity])
+	}
+	sb.WriteString(fmt.Sprintf("issues: %d (%s)\n", b.Issues, strings.Join(severities, " ")))
+
+	categories := make([]string, 0, len(b.Categories))
+	for category, n := range b.Categories {
+		categories = append(categories, fmt.Sprintf("%s=%d", category, n))
+	}
+	sort.Strings(categories)
+	sb.WriteString("categories: " + strings.Join(categories, " ") + "\n")
+
+	sb.WriteString("top_issues:\n")
+	for i, issue := range b.TopIssues {
+		sb.WriteString(fmt.Sprintf("%d. %s %s %s: %s\n", i+1, issue.Severity, issue.Rule, location(issue.File, issue.Line), issue.Message))
+	}
+
+	sb.WriteString("next_actions:\n")
+	for _, a := range b.NextActions {
+		line := "- " + a.Action
+		for _, field := range []string{a.Rule, a.Target} {
+			if field != "" {
+				line += " " + field
+			}
+		}
+		if a.Detail != "" {
+			line += ": " + a.Detail
+		}
+		sb.WriteString(line + "\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,219 @@
+package codereview
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"mcp-go-assistant/internal/i18n"
+	"mcp-go-assistant/pkg/reviewrule"
+)
+
+// Batch review limits
+const (
+	DefaultBatchWorkers = 4
+	MaxBatchWorkers     = 16
+	MaxBatchItems       = 50
+)
+
+// BatchItem is a single named snippet or file in a batch review
+type BatchItem struct {
+	Name   string `json:"name" jsonschema:"description:Name of the snippet or file path used to identify results"`
+	GoCode string `json:"go_code" jsonschema:"description:The Go code content to analyze"`
+}
+
+// BatchReviewParams represents the parameters for the code-review-batch tool
+type BatchReviewParams struct {
+	Items             []BatchItem `json:"items" jsonschema:"description:List of named Go snippets or files to review"`
+	GuidelinesFile    string      `json:"guidelines_file,omitempty" jsonschema:"description:Optional path to markdown file with coding guidelines applied to every item"`
+	GuidelinesContent string      `json:"guidelines_content,omitempty" jsonschema:"description:Optional markdown content with coding guidelines applied to every item"`
+	Hint              string      `json:"hint,omitempty" jsonschema:"description:Optional hint or specific focus area for the review"`
+	Language          string      `json:"language,omitempty" jsonschema:"description:Optional language for messages and summaries: 'en', 'ja' or 'es' (defaults to server setting)"`
+	MaxWorkers        int         `json:"max_workers,omitempty" jsonschema:"description:Optional number of concurrent reviews (default 4, max 16)"`
+	Thresholds        Thresholds  `json:"thresholds,omitempty" jsonschema:"description:Optional limits for function length, parameter count, struct fields and cyclomatic complexity; unset limits use the server settings"`
+	IdempotencyKey    string      `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+
+	// Set by the server to the number of bytes read from guidelines_file;
+	// not part of the tool schema
+	GuidelinesMaxSize int64 `json:"-"`
+	// Set by the server to the rules loaded from plugins; not part of the
+	// tool schema
+	ExternalRules []reviewrule.Rule `json:"-"`
+}
+
+// BatchItemResult is the review outcome for a single batch item
+type BatchItemResult struct {
+	Name   string        `json:"name"`
+	Result *ReviewResult `json:"result,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// BatchReviewResult aggregates the results of a batch review
+type BatchReviewResult struct {
+	Summary      string            `json:"summary"`
+	Results      []BatchItemResult `json:"results"`
+	TotalIssues  int               `json:"total_issues"`
+	WorstScore   int               `json:"worst_score"`
+	WorstItem    string            `json:"worst_item,omitempty"`
+	AverageScore int               `json:"average_score"`
+	FailedItems  int               `json:"failed_items"`
+	Packages     []PackageDesign   `json:"packages,omitempty"` // Design of the reviewed packages, with several items
+}
+
+// String returns a formatted JSON string of the BatchReviewResult
+func (r *BatchReviewResult) String() string {
+	jsonData, _ := json.MarshalIndent(r, "", "  ")
+	return string(jsonData)
+}
+
+// PerformBatchReview reviews multiple snippets concurrently under a bounded
+// worker pool and aggregates the results in input order
+func PerformBatchReview(ctx context.Context, params BatchReviewParams) (*BatchReviewResult, error) {
+	if len(params.Items) == 0 {
+		return nil, fmt.Errorf("items parameter is required")
+	}
+	if len(params.Items) > MaxBatchItems {
+		return nil, fmt.Errorf("too many items: %d (max %d)", len(params.Items), MaxBatchItems)
+	}
+	if params.Language != "" && !i18n.IsSupported(params.Language) {
+		return nil, fmt.Errorf("unsupported language: %s", params.Language)
+	}
+	if err := params.Thresholds.validate(); err != nil {
+		return nil, err
+	}
+
+	workers := params.MaxWorkers
+	if workers <= 0 {
+		workers = DefaultBatchWorkers
+	}
+	if workers > MaxBatchWorkers {
+		workers = MaxBatchWorkers
+	}
+	if workers > len(params.Items) {
+		workers = len(params.Items)
+	}
+
+	// Build the rule set once for all items. On failure every item reports
+	// the error when it loads the guidelines itself.
+	rules, err := loadRuleSet(CodeReviewParams{
+		GuidelinesFile:    params.GuidelinesFile,
+		GuidelinesContent: params.GuidelinesContent,
+		GuidelinesMaxSize: params.GuidelinesMaxSize,
+	})
+	if err != nil {
+		rules = nil
+	}
+
+	results := make([]BatchItemResult, len(params.Items))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				item := params.Items[i]
+				results[i] = BatchItemResult{Name: item.Name}
+
+				result, err := reviewCode(ctx, CodeReviewParams{
+					GoCode:            item.GoCode,
+					GuidelinesFile:    params.GuidelinesFile,
+					GuidelinesContent: params.GuidelinesContent,
+					GuidelinesMaxSize: params.GuidelinesMaxSize,
+					Hint:              params.Hint,
+					Language:          params.Language,
+					Thresholds:        params.Thresholds,
+					ExternalRules:     params.ExternalRules,
+				}, rules, nil)
+				if err != nil {
+					results[i].Error = err.Error()
+					continue
+				}
+				results[i].Result = result
+			}
+		}()
+	}
+
+	for i := range params.Items {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			close(jobs)
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	packages := designBatch(params.Items, results, params.Language)
+	batch := aggregateBatchResults(results, params.Language)
+	batch.Packages = packages
+	return batch, nil
+}
+
+// designBatch computes the design of the packages of the reviewed items,
+// named like files, when there are several, and adds the kitchen-sink
+// package issues to the results of the items
+func designBatch(items []BatchItem, results []BatchItemResult, lang string) []PackageDesign {
+	var files []designFile
+	for i, item := range items {
+		if results[i].Result != nil {
+			files = append(files, designFile{path: item.Name, code: item.GoCode})
+		}
+	}
+	if len(files) < 2 {
+		return nil
+	}
+
+	designs, issues := designPackages(files, "", lang, DefaultContextLines)
+	for _, issue := range issues {
+		for i := range results {
+			if results[i].Name == issue.File && results[i].Result != nil {
+				results[i].Result.Issues = append(results[i].Result.Issues, issue)
+				break
+			}
+		}
+	}
+	return designs
+}
+
+// aggregateBatchResults computes totals, the worst score and a combined summary
+func aggregateBatchResults(results []BatchItemResult, lang string) *BatchReviewResult {
+	batch := &BatchReviewResult{
+		Results:    results,
+		WorstScore: 100,
+	}
+
+	reviewed := 0
+	scoreSum := 0
+	for _, r := range results {
+		if r.Result == nil {
+			batch.FailedItems++
+			continue
+		}
+		reviewed++
+		scoreSum += r.Result.Score
+		batch.TotalIssues += len(r.Result.Issues)
+		if r.Result.Score < batch.WorstScore || batch.WorstItem == "" {
+			batch.WorstScore = r.Result.Score
+			batch.WorstItem = r.Name
+		}
+	}
+
+	if reviewed > 0 {
+		batch.AverageScore = scoreSum / reviewed
+	} else {
+		batch.WorstScore = 0
+	}
+
+	batch.Summary = messages.Translate(i18n.Normalize(lang), "batch.summary",
+		reviewed, len(results), batch.TotalIssues, batch.AverageScore, batch.WorstScore, batch.WorstItem)
+	if batch.FailedItems > 0 {
+		batch.Summary += " " + messages.Translate(i18n.Normalize(lang), "batch.failed", batch.FailedItems)
+	}
+
+	return batch
+}
@@ -0,0 +1,46 @@
+package codereview
+
+import (
+	"context"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+var benchmarkCorpora = []struct {
+	name      string
+	functions int
+}{
+	{"small", testutil.CorpusSmall},
+	{"medium", testutil.CorpusMedium},
+	{"large", testutil.CorpusLarge},
+}
+
+func BenchmarkAnalyzeCode(b *testing.B) {
+	for _, corpus := range benchmarkCorpora {
+		code := testutil.GoSource(corpus.functions)
+		b.Run(corpus.name, func(b *testing.B) {
+			b.SetBytes(int64(len(code)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := NewAnalyzerWithRules(DefaultRuleSet(), "").AnalyzeCode(code); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPerformCodeReview(b *testing.B) {
+	params := CodeReviewParams{
+		GoCode:       testutil.GoSource(testutil.CorpusMedium),
+		Hint:         "performance",
+		PreviousCode: testutil.GoSource(testutil.CorpusMedium - 1),
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := PerformCodeReview(context.Background(), params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
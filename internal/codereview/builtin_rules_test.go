@@ -0,0 +1,54 @@
+package codereview_test
+
+import (
+	"testing"
+
+	"mcp-go-assistant/pkg/reviewrule/analyzertest"
+)
+
+func TestErrorStringStyle(t *testing.T) {
+	analyzertest.RunBuiltin(t, "error-string-style",
+		analyzertest.Test{Name: "capitalized", Src: `package store
+
+import "errors"
+
+var ErrMissing = errors.New("Missing record") // want "starts with a capital letter"
+`},
+		analyzertest.Test{Name: "punctuation", Src: `package store
+
+import "fmt"
+
+func load(id string) error {
+	return fmt.Errorf("load %s failed.", id) // want "ends with punctuation"
+}
+`},
+		analyzertest.Test{Name: "acronym", Src: `package store
+
+import "errors"
+
+var ErrStatus = errors.New("HTTP status unknown")
+`},
+	)
+}
+
+func TestParamAppend(t *testing.T) {
+	analyzertest.RunBuiltin(t, "param-append",
+		analyzertest.Test{Name: "shared", Src: `package p
+
+func collect(dst []int, n int) {
+	for i := 0; i < n; i++ {
+		dst = append(dst, i) // want "dst"
+	}
+}
+`},
+		analyzertest.Test{Name: "returned", Src: `package p
+
+func collect(dst []int, n int) []int {
+	for i := 0; i < n; i++ {
+		dst = append(dst, i)
+	}
+	return dst
+}
+`},
+	)
+}
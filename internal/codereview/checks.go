@@ -0,0 +1,137 @@
+package codereview
+
+import (
+	"go/ast"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// analysisCheck is a named review pass over a parsed file
+type analysisCheck struct {
+	name string
+	run  func(a *Analyzer, file *ast.File, result *ReviewResult)
+
+	// Reads or changes the issues of other checks, so it runs after the
+	// independent checks have been merged
+	dependent bool
+}
+
+// analysisChecks are the passes AnalyzeCode runs. Independent checks only
+// append issues and suggestions and run concurrently; their results are
+// merged in table order so the output does not depend on scheduling.
+var analysisChecks = []analysisCheck{
+	{name: "naming", run: (*Analyzer).checkNaming},
+	{name: "structure", run: (*Analyzer).checkStructure},
+	{name: "comments", run: (*Analyzer).checkComments},
+	{name: "error-handling", run: (*Analyzer).checkErrorHandling},
+	{name: "ineffective-errors", run: (*Analyzer).checkIneffectiveErrors},
+	{name: "performance", run: (*Analyzer).checkPerformance},
+	{name: "security", run: (*Analyzer).checkSecurity},
+	{name: "testability", run: (*Analyzer).checkTestability},
+	{name: "global-state", run: (*Analyzer).checkGlobalState},
+	{name: "complexity", run: (*Analyzer).checkComplexity},
+	{name: "generics", run: (*Analyzer).checkGenerics},
+	{name: "dead-code", run: (*Analyzer).checkDeadCode},
+	{name: "deadlines", run: (*Analyzer).checkDeadlines},
+	{name: "logging", run: (*Analyzer).checkLogging},
+	{name: "struct-tags", run: (*Analyzer).checkStructTags},
+	{name: "aliasing", run: (*Analyzer).checkAliasing},
+	{name: "modernize", run: (*Analyzer).checkModernize},
+	{name: "receivers", run: (*Analyzer).checkReceivers},
+	{name: "guidelines", run: (*Analyzer).applyCustomGuidelines},
+	{name: "plugins", run: (*Analyzer).runExternalRules},
+	{name: "coverage", run: (*Analyzer).checkCoverage, dependent: true},
+}
+
+// checkWorkers bounds the number of checks run at once for a single file
+var checkWorkers = runtime.GOMAXPROCS(0)
+
+// runChecks runs analysisChecks over file, the independent ones on a worker
+// pool, and streams each check's issues as it finishes
+func (a *Analyzer) runChecks(file *ast.File, result *ReviewResult, code string) {
+	var independent, dependent []int
+	for i, check := range analysisChecks {
+		if check.dependent {
+			dependent = append(dependent, i)
+		} else {
+			independent = append(independent, i)
+		}
+	}
+
+	partials := make([]ReviewResult, len(analysisChecks))
+	jobs := make(chan int)
+	finished := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(checkWorkers, len(independent)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				analysisChecks[i].run(a, file, &partials[i])
+				finished <- i
+			}
+		}()
+	}
+	go func() {
+		for _, i := range independent {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(finished)
+	}()
+
+	// Stream in completion order, then merge in table order
+	done := 0
+	for i := range finished {
+		done++
+		partials[i].Issues = a.suppress(partials[i].Issues)
+		a.streamIssues(analysisChecks[i].name, done, partials[i].Issues, code)
+	}
+	for _, i := range independent {
+		result.Issues = append(result.Issues, partials[i].Issues...)
+		result.Suggestions = append(result.Suggestions, partials[i].Suggestions...)
+	}
+
+	for _, i := range dependent {
+		before := len(result.Issues)
+		analysisChecks[i].run(a, file, result)
+		result.Issues = append(result.Issues[:before], a.suppress(result.Issues[before:])...)
+		done++
+		a.streamIssues(analysisChecks[i].name, done, result.Issues[before:], code)
+	}
+}
+
+// sortIssues orders issues by position: file, line and column, then rule
+// and message for issues at the same position. Checks run concurrently and
+// some iterate over maps, so results are sorted to be identical run to run.
+func sortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Message < b.Message
+	})
+}
+
+// sortSuggestions orders suggestions by category, then message
+func sortSuggestions(suggestions []Suggestion) {
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Category != suggestions[j].Category {
+			return suggestions[i].Category < suggestions[j].Category
+		}
+		return suggestions[i].Message < suggestions[j].Message
+	})
+}
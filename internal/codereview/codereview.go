@@ -5,29 +5,150 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"mcp-go-assistant/internal/cache"
+	"mcp-go-assistant/internal/i18n"
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/types"
 )
 
 // PerformCodeReview analyzes Go code and returns improvement suggestions
 func PerformCodeReview(ctx context.Context, params CodeReviewParams) (*ReviewResult, error) {
+	return PerformCodeReviewStream(ctx, params, nil)
+}
+
+// PerformCodeReviewStream is PerformCodeReview that also sends the issues
+// of each check to stream as they are found, when stream is non-nil, ending
+// with a batch carrying the summary and score
+func PerformCodeReviewStream(ctx context.Context, params CodeReviewParams, stream StreamFunc) (*ReviewResult, error) {
+	return reviewCode(ctx, params, nil, stream)
+}
+
+// ValidateOptions checks the language, output format, audience and
+// thresholds of a review; empty values are allowed
+func ValidateOptions(language, outputFormat, audience string, thresholds Thresholds) error {
+	if language != "" && !i18n.IsSupported(language) {
+		return fmt.Errorf("unsupported language: %s", language)
+	}
+	if !isValidOutputFormat(outputFormat) {
+		return fmt.Errorf("unsupported output format: %s (supported: %s)", outputFormat, supportedOutputFormats)
+	}
+	if !isValidAudience(audience) {
+		return fmt.Errorf("unsupported audience: %s (supported: %s, %s)", audience, AudienceHuman, AudienceLLM)
+	}
+	return thresholds.validate()
+}
+
+// reviewCode reviews params.GoCode with rules, or with the rule set loaded
+// from params when rules is nil
+func reviewCode(ctx context.Context, params CodeReviewParams, rules *RuleSet, stream StreamFunc) (*ReviewResult, error) {
+	// Read spooled code back only now that it is needed
+	if params.GoCode == "" && params.GoCodeFile != "" {
+		code, err := os.ReadFile(params.GoCodeFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spooled go_code: %v", err)
+		}
+		params.GoCode = string(code)
+	}
+
 	// Validate input
 	if params.GoCode == "" {
 		return nil, fmt.Errorf("go_code parameter is required")
 	}
 
+	if err := ValidateOptions(params.Language, params.OutputFormat, params.Audience, params.Thresholds); err != nil {
+		return nil, err
+	}
+
 	// Parse guidelines
+	if rules == nil {
+		var err error
+		if rules, err = loadRuleSet(params); err != nil {
+			return nil, logging.ErrorWithRequestID(ctx, err)
+		}
+	}
+
+	coverage, err := loadCoverage(ctx, params, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Create analyzer with guidelines and hint
+	analyzer := newConfiguredAnalyzer(rules, params)
+	analyzer.SetStream(stream)
+	if coverage != nil {
+		blocks, ok := coverage.blocksFor(params.CoverageFile)
+		if !ok && params.CoverageFile == "" {
+			return nil, fmt.Errorf("coverage profile covers %d files; set coverage_file to select one", len(coverage))
+		}
+		if !ok {
+			return nil, fmt.Errorf("coverage profile has no unique entry for %s", params.CoverageFile)
+		}
+		analyzer.setCoverage(blocks)
+	}
+
+	// Perform analysis
+	result, err := analyzer.AnalyzeCode(params.GoCode)
+	if err != nil {
+		return nil, fmt.Errorf("code analysis failed: %v", err)
+	}
+	result.Warnings = rules.warnings(params.Language)
+
+	// Add hint-specific analysis if provided
+	if params.Hint != "" {
+		analyzer.addHintSpecificAnalysis(result, params.Hint)
+	}
+
+	// Compare exported API against the previous version if provided
+	if params.PreviousCode != "" {
+		changes, err := CompareAPI(params.PreviousCode, params.GoCode)
+		if err != nil {
+			return nil, fmt.Errorf("api comparison failed: %w", err)
+		}
+		result.APIChanges = changes
+		before := len(result.Issues)
+		analyzer.addAPIChangeIssues(result)
+		result.Issues = append(result.Issues[:before], analyzer.suppress(result.Issues[before:])...)
+		result.Suppressed = analyzer.suppressed
+		analyzer.attachSnippets(result.Issues, params.GoCode)
+		result.Score = analyzer.calculateScore(result)
+		result.Summary = analyzer.generateSummary(result)
+	}
+
+	applyAudience(result, result.Issues, params.Audience)
+	analyzer.finishStream(result)
+
+	// Issues and suggestions added after the checks join the sorted ones
+	// once they have been streamed
+	sortIssues(result.Issues)
+	sortSuggestions(result.Suggestions)
+	return result, nil
+}
+
+// loadRuleSet builds the rule set for the guidelines in the file and
+// content parameters, falling back to the shared default rule set when none
+// are provided
+func loadRuleSet(params CodeReviewParams) (*RuleSet, error) {
 	var guidelines []string
+	var truncatedAt int64
 	parser := NewGuidelinesParser()
 
-	// Load guidelines from file if provided
+	// Load guidelines from file if provided, reading at most the size limit
 	if params.GuidelinesFile != "" {
-		if _, err := os.Stat(params.GuidelinesFile); err == nil {
-			fileGuidelines, err := parser.ParseFile(params.GuidelinesFile)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse guidelines file: %v", err)
-			}
-			guidelines = append(guidelines, fileGuidelines...)
-		} else {
-			return nil, fmt.Errorf("guidelines file not found: %s", params.GuidelinesFile)
+		if err := checkGuidelinesFile(params.GuidelinesFile); err != nil {
+			return nil, err
+		}
+		limit := params.GuidelinesMaxSize
+		if limit <= 0 {
+			limit = DefaultGuidelinesMaxSize
+		}
+		fileGuidelines, truncated, err := parser.ParseFileLimit(params.GuidelinesFile, limit)
+		if err != nil {
+			return nil, types.NewKindError(types.KindParseFailure, "failed to parse guidelines file: %v", err)
+		}
+		guidelines = append(guidelines, fileGuidelines...)
+		if truncated {
+			truncatedAt = limit
 		}
 	}
 
@@ -37,61 +158,81 @@ func PerformCodeReview(ctx context.Context, params CodeReviewParams) (*ReviewRes
 		guidelines = append(guidelines, contentGuidelines...)
 	}
 
-	// Add default guidelines if none provided
+	// Use the default guidelines if none provided
+	if len(guidelines) == 0 && truncatedAt == 0 {
+		return DefaultRuleSet(), nil
+	}
 	if len(guidelines) == 0 {
 		guidelines = GetDefaultGuidelines()
 	}
 
-	// Create analyzer with guidelines and hint
-	analyzer := NewAnalyzer(guidelines, params.Hint)
-
-	// Perform analysis
-	result, err := analyzer.AnalyzeCode(params.GoCode)
-	if err != nil {
-		return nil, fmt.Errorf("code analysis failed: %v", err)
+	rules := NewRuleSet(guidelines)
+	if truncatedAt > 0 {
+		rules.truncatedFile, rules.truncatedAt = params.GuidelinesFile, truncatedAt
 	}
+	return rules, nil
+}
 
-	// Add hint-specific analysis if provided
-	if params.Hint != "" {
-		addHintSpecificAnalysis(result, params.Hint)
+// newConfiguredAnalyzer creates an analyzer with the per-request options
+// from params applied
+func newConfiguredAnalyzer(rules *RuleSet, params CodeReviewParams) *Analyzer {
+	analyzer := NewAnalyzerWithRules(rules, params.Hint)
+	analyzer.SetLanguage(params.Language)
+	analyzer.SetThresholds(params.Thresholds)
+	if params.ContextLines > 0 {
+		analyzer.SetContextLines(params.ContextLines)
 	}
-
-	return result, nil
+	analyzer.SetExternalRules(params.ExternalRules)
+	return analyzer
 }
 
 // addHintSpecificAnalysis adds analysis based on the provided hint
-func addHintSpecificAnalysis(result *ReviewResult, hint string) {
+func (a *Analyzer) addHintSpecificAnalysis(result *ReviewResult, hint string) {
 	hintLower := strings.ToLower(hint)
 
 	if strings.Contains(hintLower, "performance") {
 		result.Suggestions = append(result.Suggestions, Suggestion{
 			Category: "performance",
-			Message:  "Focus on performance: Look for opportunities to optimize algorithms, reduce allocations, and improve efficiency",
-			Impact:   "Better runtime performance and resource utilization",
+			Message:  a.msg("hint.performance"),
+			Impact:   a.msg("hint.performance.impact"),
 		})
 	}
 
 	if strings.Contains(hintLower, "security") {
 		result.Suggestions = append(result.Suggestions, Suggestion{
 			Category: "security",
-			Message:  "Focus on security: Validate inputs, handle sensitive data carefully, and avoid common vulnerabilities",
-			Impact:   "Improved application security and reduced attack surface",
+			Message:  a.msg("hint.security"),
+			Impact:   a.msg("hint.security.impact"),
 		})
 	}
 
 	if strings.Contains(hintLower, "test") {
 		result.Suggestions = append(result.Suggestions, Suggestion{
 			Category: "testability",
-			Message:  "Focus on testability: Make functions pure, inject dependencies, and avoid global state",
-			Impact:   "Easier testing and better code reliability",
+			Message:  a.msg("hint.testability"),
+			Impact:   a.msg("hint.testability.impact"),
 		})
 	}
 
 	if strings.Contains(hintLower, "maintainability") || strings.Contains(hintLower, "readability") {
 		result.Suggestions = append(result.Suggestions, Suggestion{
 			Category: "maintainability",
-			Message:  "Focus on maintainability: Use clear naming, add documentation, and simplify complex logic",
-			Impact:   "Easier code maintenance and team collaboration",
+			Message:  a.msg("hint.maintainability"),
+			Impact:   a.msg("hint.maintainability.impact"),
 		})
 	}
 }
+
+// CacheKey returns the response cache key for params, derived from the code
+// hashes and the remaining parameters. The cache lives in the process, so a
+// result is never reused by a build with other rules. Reviews that read
+// files from disk are not cacheable.
+func CacheKey(params CodeReviewParams) (string, bool) {
+	if params.WorkingDir != "" || params.GuidelinesFile != "" {
+		return "", false
+	}
+
+	code, previous, coverage := cache.Hash(params.GoCode), cache.Hash(params.PreviousCode), cache.Hash(params.CoverageProfile)
+	params.GoCode, params.PreviousCode, params.CoverageProfile, params.IdempotencyKey = "", "", "", ""
+	return cache.Key(code, previous, coverage, params), true
+}
@@ -2,10 +2,15 @@ package codereview
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"mcp-go-assistant/internal/history"
+	"mcp-go-assistant/internal/testutil"
 )
 
 func TestPerformCodeReview(t *testing.T) {
@@ -174,6 +179,54 @@ func TestPerformCodeReview_WithInvalidGuidelinesFile(t *testing.T) {
 	}
 }
 
+func TestPerformCodeReview_GuidelinesFileLimits(t *testing.T) {
+	tmpDir := t.TempDir()
+	code := "package main\n\nfunc test() {}\n"
+
+	large := filepath.Join(tmpDir, "large.md")
+	content := "- Avoid global state\n" + strings.Repeat("- Keep functions short\n", 100)
+	if err := os.WriteFile(large, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to create guidelines file: %v", err)
+	}
+
+	result, err := PerformCodeReview(context.Background(), CodeReviewParams{GoCode: code, GuidelinesFile: large, GuidelinesMaxSize: 64})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "64 bytes") {
+		t.Errorf("expected truncation warning, got %v", result.Warnings)
+	}
+	if !strings.Contains(result.Compact(), "warning: ") {
+		t.Errorf("expected warning in compact output, got %q", result.Compact())
+	}
+
+	result, err = PerformCodeReview(context.Background(), CodeReviewParams{GoCode: code, GuidelinesFile: large})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("expected no warning within the default limit, got %v", result.Warnings)
+	}
+
+	script := filepath.Join(tmpDir, "guidelines.sh")
+	if err := os.WriteFile(script, []byte("- Avoid global state\n"), 0600); err != nil {
+		t.Fatalf("failed to create guidelines file: %v", err)
+	}
+	_, err = PerformCodeReview(context.Background(), CodeReviewParams{GoCode: code, GuidelinesFile: script})
+	if err == nil || !strings.Contains(err.Error(), ".md or .txt") {
+		t.Errorf("expected extension error, got %v", err)
+	}
+
+	dir := filepath.Join(tmpDir, "dir.md")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_, err = PerformCodeReview(context.Background(), CodeReviewParams{GoCode: code, GuidelinesFile: dir})
+	if err == nil || !strings.Contains(err.Error(), "not a regular file") {
+		t.Errorf("expected regular file error, got %v", err)
+	}
+}
+
 func TestAddHintSpecificAnalysis(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -539,3 +592,1385 @@ func main() {
 		t.Log("No suggestions generated for complex code")
 	}
 }
+
+func TestCheckDeadCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		wantRule  string
+		wantFound bool
+	}{
+		{
+			name: "unused unexported function",
+			code: `
+package main
+
+func helper() {}
+
+func main() {}
+`,
+			wantRule:  "unused-symbol",
+			wantFound: true,
+		},
+		{
+			name: "used unexported function",
+			code: `
+package main
+
+func helper() {}
+
+func main() { helper() }
+`,
+			wantRule:  "unused-symbol",
+			wantFound: false,
+		},
+		{
+			name: "unused unexported constant",
+			code: `
+package main
+
+const limit = 10
+
+func main() {}
+`,
+			wantRule:  "unused-symbol",
+			wantFound: true,
+		},
+		{
+			name: "code after return",
+			code: `
+package main
+
+func main() {
+	return
+	println("never")
+}
+`,
+			wantRule:  "unreachable-code",
+			wantFound: true,
+		},
+		{
+			name: "code after panic",
+			code: `
+package main
+
+func main() {
+	panic("boom")
+	println("never")
+}
+`,
+			wantRule:  "unreachable-code",
+			wantFound: true,
+		},
+		{
+			name: "labeled statement after goto",
+			code: `
+package main
+
+func main() {
+	goto done
+done:
+	println("reached")
+}
+`,
+			wantRule:  "unreachable-code",
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := NewAnalyzer(nil, "")
+			result, err := analyzer.AnalyzeCode(tt.code)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			found := false
+			for _, issue := range result.Issues {
+				if issue.Rule == tt.wantRule {
+					found = true
+					if issue.Severity != "low" {
+						t.Errorf("expected low severity, got '%s'", issue.Severity)
+					}
+				}
+			}
+
+			if found != tt.wantFound {
+				t.Errorf("expected rule '%s' found=%v, got %v", tt.wantRule, tt.wantFound, found)
+			}
+		})
+	}
+}
+
+func TestCheckIneffectiveErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		wantRule  string
+		wantFound bool
+	}{
+		{
+			name: "logged error in function returning error",
+			code: `
+package main
+
+func load() error {
+	err := read()
+	if err != nil {
+		log.Printf("read failed: %v", err)
+	}
+	return nil
+}
+`,
+			wantRule:  "swallowed-error",
+			wantFound: true,
+		},
+		{
+			name: "logged and returned error",
+			code: `
+package main
+
+func load() error {
+	err := read()
+	if err != nil {
+		log.Printf("read failed: %v", err)
+		return err
+	}
+	return nil
+}
+`,
+			wantRule:  "swallowed-error",
+			wantFound: false,
+		},
+		{
+			name: "logged error in function without error result",
+			code: `
+package main
+
+func main() {
+	if err := read(); err != nil {
+		log.Println(err)
+	}
+}
+`,
+			wantRule:  "swallowed-error",
+			wantFound: false,
+		},
+		{
+			name: "logged error in closure returning error",
+			code: `
+package main
+
+func main() {
+	run(func() error {
+		if err := read(); err != nil {
+			logger.Error().Err(err).Msg("read failed")
+		}
+		return nil
+	})
+}
+`,
+			wantRule:  "swallowed-error",
+			wantFound: true,
+		},
+		{
+			name: "error overwritten before check",
+			code: `
+package main
+
+func write(w io.Writer) error {
+	_, err := w.Write(header)
+	_, err = w.Write(body)
+	return err
+}
+`,
+			wantRule:  "overwritten-error",
+			wantFound: true,
+		},
+		{
+			name: "error checked before reassignment",
+			code: `
+package main
+
+func write(w io.Writer) error {
+	_, err := w.Write(header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+`,
+			wantRule:  "overwritten-error",
+			wantFound: false,
+		},
+		{
+			name: "error shadowed in nested block",
+			code: `
+package main
+
+func open(name string) (*File, error) {
+	var f *File
+	var err error
+	if name != "" {
+		f, err := openFile(name)
+		_ = f
+		_ = err
+	}
+	return f, err
+}
+`,
+			wantRule:  "shadowed-error",
+			wantFound: true,
+		},
+		{
+			name: "error scoped to if statement",
+			code: `
+package main
+
+func open(name string) error {
+	err := check(name)
+	if err := validate(name); err != nil {
+		return err
+	}
+	return err
+}
+`,
+			wantRule:  "shadowed-error",
+			wantFound: false,
+		},
+		{
+			name: "comparison with new error",
+			code: `
+package main
+
+func isMissing(err error) bool {
+	return err == errors.New("missing")
+}
+`,
+			wantRule:  "error-comparison",
+			wantFound: true,
+		},
+		{
+			name: "comparison with error text",
+			code: `
+package main
+
+func isMissing(err error) bool {
+	return err.Error() == "missing"
+}
+`,
+			wantRule:  "error-comparison",
+			wantFound: true,
+		},
+		{
+			name: "comparison with sentinel error",
+			code: `
+package main
+
+func isEOF(err error) bool {
+	return err == io.EOF
+}
+`,
+			wantRule:  "error-comparison",
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := NewAnalyzer(nil, "")
+			result, err := analyzer.AnalyzeCode(tt.code)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			found := false
+			for _, issue := range result.Issues {
+				if issue.Rule == tt.wantRule {
+					found = true
+					if issue.Category != "error-handling" {
+						t.Errorf("expected error-handling category, got '%s'", issue.Category)
+					}
+				}
+			}
+
+			if found != tt.wantFound {
+				t.Errorf("expected rule '%s' found=%v, got %v", tt.wantRule, tt.wantFound, found)
+			}
+		})
+	}
+}
+
+func TestCompareAPI(t *testing.T) {
+	oldCode := `
+package lib
+
+const Version = "1.0"
+
+type Config struct {
+	Name    string
+	Timeout int
+}
+
+type Store interface {
+	Get(key string) (string, error)
+}
+
+func Open(path string) error { return nil }
+
+func Close() {}
+`
+	newCode := `
+package lib
+
+const Version = "1.1"
+
+type Config struct {
+	Name  string
+	Retry bool
+}
+
+type Store interface {
+	Get(key string) (string, error)
+	Put(key, value string) error
+}
+
+func Open(path string, readOnly bool) error { return nil }
+
+func Flush() {}
+`
+
+	changes, err := CompareAPI(oldCode, newCode)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]struct {
+		change   string
+		breaking bool
+	}{
+		"Version":        {"changed", false},
+		"Config.Timeout": {"removed", true},
+		"Config.Retry":   {"added", false},
+		"Store.Put":      {"added", true},
+		"Open":           {"changed", true},
+		"Close":          {"removed", true},
+		"Flush":          {"added", false},
+	}
+
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(want), len(changes), changes)
+	}
+
+	for _, c := range changes {
+		w, ok := want[c.Symbol]
+		if !ok {
+			t.Errorf("unexpected change for '%s'", c.Symbol)
+			continue
+		}
+		if c.Change != w.change || c.Breaking != w.breaking {
+			t.Errorf("%s: expected %s/breaking=%v, got %s/breaking=%v", c.Symbol, w.change, w.breaking, c.Change, c.Breaking)
+		}
+	}
+
+	// Breaking changes are listed first
+	if !changes[0].Breaking {
+		t.Error("expected breaking changes to be ordered first")
+	}
+}
+
+func TestPerformCodeReview_WithPreviousCode(t *testing.T) {
+	params := CodeReviewParams{
+		GoCode:       "package lib\n\n// Run runs.\nfunc Run(n int) {}\n",
+		PreviousCode: "package lib\n\n// Run runs.\nfunc Run() {}\n",
+	}
+
+	result, err := PerformCodeReview(context.TODO(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.APIChanges) != 1 {
+		t.Fatalf("expected 1 API change, got %d", len(result.APIChanges))
+	}
+
+	found := false
+	for _, issue := range result.Issues {
+		if issue.Rule == "api-breaking-change" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected api-breaking-change issue")
+	}
+
+	_, err = PerformCodeReview(context.TODO(), CodeReviewParams{GoCode: "package lib", PreviousCode: "not go"})
+	if err == nil {
+		t.Error("expected error for unparseable previous code")
+	}
+}
+
+func TestPerformCodeReviewStream(t *testing.T) {
+	params := CodeReviewParams{
+		GoCode:       "package lib\n\nfunc Run_fast(n int) {}\n",
+		PreviousCode: "package lib\n\n// Run_fast runs.\nfunc Run_fast() {}\n",
+	}
+
+	var batches []IssueBatch
+	result, err := PerformCodeReviewStream(context.TODO(), params, func(batch IssueBatch) {
+		batches = append(batches, batch)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(batches) != len(analysisChecks)+1 {
+		t.Fatalf("expected one batch per check and a final batch, got %d", len(batches))
+	}
+
+	var streamed []Issue
+	for i, batch := range batches {
+		if batch.Done != i+1 || batch.Total != len(batches) {
+			t.Errorf("batch %d: progress %d/%d", i, batch.Done, batch.Total)
+		}
+		streamed = append(streamed, batch.Issues...)
+	}
+	if len(streamed) != len(result.Issues) {
+		t.Errorf("streamed %d issues, result has %d", len(streamed), len(result.Issues))
+	}
+
+	var naming *IssueBatch
+	for i := range batches {
+		if batches[i].Check == "naming" {
+			naming = &batches[i]
+		}
+	}
+	if naming == nil || len(naming.Issues) == 0 || naming.Issues[0].Snippet == "" {
+		t.Errorf("expected a naming batch with snippets, got %+v", naming)
+	}
+	final := batches[len(batches)-1]
+	if !final.Final || final.Score != result.Score || final.Summary != result.Summary {
+		t.Errorf("expected the final batch to carry the summary and score, got %+v", final)
+	}
+	if len(final.Issues) == 0 || final.Issues[0].Rule != "api-breaking-change" {
+		t.Errorf("expected the API change issues in the final batch, got %+v", final.Issues)
+	}
+}
+
+func TestAnalyzeCode_ConcurrentChecksDeterministic(t *testing.T) {
+	code := `package lib
+
+import "fmt"
+
+var Global_value = 1
+
+func Do_work(items []string) (out string) {
+	for _, item := range items {
+		out = out + item
+	}
+	if err := fmt.Errorf("x"); err != nil {
+		panic(err)
+	}
+	return out
+}
+`
+	analyze := func(workers int) string {
+		defer func(n int) { checkWorkers = n }(checkWorkers)
+		checkWorkers = workers
+
+		result, err := NewAnalyzer([]string{"No panic in library code"}, "").AnalyzeCode(code)
+		if err != nil {
+			t.Fatalf("AnalyzeCode() error = %v", err)
+		}
+		return result.String()
+	}
+
+	sequential := analyze(1)
+	for i := 0; i < 5; i++ {
+		if concurrent := analyze(8); concurrent != sequential {
+			t.Fatalf("concurrent result differs from sequential result:\n%s\nwant\n%s", concurrent, sequential)
+		}
+	}
+}
+
+func TestPerformCodeReview_StableOrder(t *testing.T) {
+	params := CodeReviewParams{
+		Hint:         "performance",
+		PreviousCode: "package lib\n\nfunc Removed() {}\n",
+		GoCode: `package lib
+
+import "fmt"
+
+var counter, Total_count int
+
+func Inc() { counter++ }
+
+func Reset() { counter = 0 }
+
+func Do_work(items []string) (out string) {
+	for _, item := range items {
+		out = out + item
+	}
+	if err := fmt.Errorf("x"); err != nil {
+		panic(err)
+	}
+	return out
+}
+`,
+	}
+
+	first, err := PerformCodeReview(context.TODO(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 1; i < len(first.Issues); i++ {
+		prev, cur := first.Issues[i-1], first.Issues[i]
+		if prev.Line > cur.Line || (prev.Line == cur.Line && prev.Column > cur.Column) {
+			t.Errorf("issues not ordered by position: %d:%d %s before %d:%d %s",
+				prev.Line, prev.Column, prev.Rule, cur.Line, cur.Column, cur.Rule)
+		}
+	}
+	for i := 1; i < len(first.Suggestions); i++ {
+		if first.Suggestions[i-1].Category > first.Suggestions[i].Category {
+			t.Errorf("suggestions not ordered by category: %q before %q", first.Suggestions[i-1].Category, first.Suggestions[i].Category)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		again, err := PerformCodeReview(context.TODO(), params)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if again.String() != first.String() {
+			t.Fatalf("run %d differs from the first:\n%s\nwant\n%s", i, again.String(), first.String())
+		}
+	}
+}
+
+func TestLoadRuleSet(t *testing.T) {
+	rules, err := loadRuleSet(CodeReviewParams{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rules != DefaultRuleSet() {
+		t.Error("expected requests without guidelines to share the default rule set")
+	}
+
+	rules, err = loadRuleSet(CodeReviewParams{GuidelinesContent: "- No panic in handlers\n- Use no panic anywhere\n"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rules == DefaultRuleSet() || !rules.noPanic {
+		t.Fatalf("expected a custom rule set with the no-panic rule, got %+v", rules)
+	}
+
+	result, err := NewAnalyzerWithRules(rules, "").AnalyzeCode("package lib\n\nfunc f() { panic(1) }\n")
+	if err != nil {
+		t.Fatalf("AnalyzeCode() error = %v", err)
+	}
+	count := 0
+	for _, issue := range result.Issues {
+		if issue.Rule == "custom-no-panic" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected one custom-no-panic issue, got %d", count)
+	}
+}
+
+func TestPerformCodeReviewStream_ParseError(t *testing.T) {
+	var batches []IssueBatch
+	_, err := PerformCodeReviewStream(context.TODO(), CodeReviewParams{GoCode: "not go"}, func(batch IssueBatch) {
+		batches = append(batches, batch)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(batches) != 1 || !batches[0].Final || len(batches[0].Issues) != 1 || batches[0].Issues[0].Rule != "valid-syntax" {
+		t.Errorf("expected a single final batch with the syntax issue, got %+v", batches)
+	}
+}
+
+func TestPerformCodeReview_SpooledCode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.go")
+	if err := os.WriteFile(path, []byte("package lib\n\nfunc Run_fast() {}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := PerformCodeReview(context.TODO(), CodeReviewParams{GoCodeFile: path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Issues) == 0 || result.Issues[0].Snippet == "" {
+		t.Errorf("expected issues with snippets from the spooled code, got %+v", result.Issues)
+	}
+
+	_, err = PerformCodeReview(context.TODO(), CodeReviewParams{GoCodeFile: filepath.Join(t.TempDir(), "missing.go")})
+	if err == nil || !strings.Contains(err.Error(), "failed to read spooled go_code") {
+		t.Errorf("expected a read error, got %v", err)
+	}
+}
+
+func TestPerformCodeReview_Language(t *testing.T) {
+	code := `
+package main
+
+func helper() {}
+
+func main() {}
+`
+
+	tests := []struct {
+		name        string
+		language    string
+		wantErr     bool
+		wantMessage string
+	}{
+		{"default english", "", false, "Unexported function 'helper' is never used"},
+		{"japanese", "ja", false, "エクスポートされない関数 'helper' は使用されていません"},
+		{"spanish", "es", false, "El símbolo sin exportar 'helper' (función) nunca se usa"},
+		{"unsupported", "fr", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := PerformCodeReview(context.TODO(), CodeReviewParams{GoCode: code, Language: tt.language})
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			found := false
+			for _, issue := range result.Issues {
+				if issue.Message == tt.wantMessage {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected issue message '%s'", tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestMessageCatalogsComplete(t *testing.T) {
+	for lang, catalog := range map[string]map[string]string{"ja": japaneseMessages, "es": spanishMessages} {
+		for key := range englishMessages {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("%s catalog missing key '%s'", lang, key)
+			}
+		}
+	}
+}
+
+func TestFormatMarkdown(t *testing.T) {
+	params := CodeReviewParams{
+		GoCode: `package main
+
+func helper() {}
+
+func main() {}
+`,
+		OutputFormat: "markdown",
+	}
+
+	result, err := PerformCodeReview(context.TODO(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report := FormatResult(result, params)
+
+	for _, want := range []string{
+		"## Code Review Report",
+		"img.shields.io/badge/score-",
+		"#### Low (",
+		"func helper() {}",
+		"| Lines of code |",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected report to contain '%s'", want)
+		}
+	}
+
+	// JSON remains the default format
+	params.OutputFormat = ""
+	if got := FormatResult(result, params); got != result.String() {
+		t.Error("expected default format to be JSON")
+	}
+
+	params.OutputFormat = "xml"
+	if _, err := PerformCodeReview(context.TODO(), params); err == nil {
+		t.Error("expected error for unsupported output format")
+	}
+}
+
+func TestFormatResult_Formats(t *testing.T) {
+	params := CodeReviewParams{GoCode: `package main
+
+func helper() {}
+
+func load() {
+	x, _ := strconv.Atoi("1")
+	println(x)
+}
+
+func main() { load() }
+`}
+
+	result, err := PerformCodeReview(context.TODO(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	params.OutputFormat = "text"
+	if got := FormatResult(result, params); got != result.String() {
+		t.Error("expected text format to match String()")
+	}
+
+	params.OutputFormat = "json"
+	minified := FormatResult(result, params)
+	if strings.Contains(minified, "\n") || len(minified) >= len(result.String()) {
+		t.Errorf("expected minified JSON, got %q", minified)
+	}
+	var decoded ReviewResult
+	if err := json.Unmarshal([]byte(minified), &decoded); err != nil || decoded.Score != result.Score {
+		t.Errorf("expected minified JSON to round-trip, got %v", err)
+	}
+
+	params.OutputFormat = "COMPACT"
+	lines := strings.Split(strings.TrimSpace(FormatResult(result, params)), "\n")
+	if len(lines) != len(result.Issues)+1 || lines[0] != result.Summary {
+		t.Fatalf("expected summary and one line per issue, got %q", lines)
+	}
+	if lines[1] != "6:10: high error-handling: Error is being ignored" {
+		t.Errorf("expected most severe issue first, got %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[len(lines)-1], "3:6: low unused-symbol:") {
+		t.Errorf("expected least severe issue last, got %q", lines[len(lines)-1])
+	}
+
+	result.Issues[0].File = "pkg/main.go"
+	if compact := result.Compact(); !strings.Contains(compact, "\npkg/main.go:") {
+		t.Errorf("expected file prefix for workspace issues, got %q", compact)
+	}
+}
+
+func TestPerformCodeReview_AudienceLLM(t *testing.T) {
+	params := CodeReviewParams{Audience: "llm", GoCode: `package main
+
+func helper() {}
+
+func load() {
+	x, _ := strconv.Atoi("1")
+	println(x)
+}
+
+func main() { load() }
+`}
+
+	result, err := PerformCodeReview(context.TODO(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Brief == nil {
+		t.Fatal("expected a brief for the llm audience")
+	}
+	if result.Brief.Issues != len(result.Issues) || result.Brief.Score != result.Score {
+		t.Errorf("unexpected brief counts %+v", result.Brief)
+	}
+	if len(result.Brief.TopIssues) == 0 || result.Brief.TopIssues[0].Rule != "error-handling" || result.Brief.NextActions[0].Action != ActionFix {
+		t.Errorf("expected the ignored error first, got %+v", result.Brief)
+	}
+
+	lines := strings.Split(result.Summary, "\n")
+	if lines[0] != fmt.Sprintf("score: %d/100", result.Score) || !strings.HasPrefix(lines[1], fmt.Sprintf("issues: %d (critical=0 high=1", len(result.Issues))) {
+		t.Errorf("unexpected summary header %q", lines[:2])
+	}
+	if !strings.Contains(result.Summary, "\ntop_issues:\n1. high error-handling line 6: Error is being ignored\n") ||
+		!strings.Contains(result.Summary, "\nnext_actions:\n- fix error-handling line 6: ") {
+		t.Errorf("unexpected summary:\n%s", result.Summary)
+	}
+
+	// The brief is deterministic
+	again, err := PerformCodeReview(context.TODO(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.Summary != result.Summary {
+		t.Errorf("expected identical summaries, got:\n%s\n---\n%s", result.Summary, again.Summary)
+	}
+
+	clean, err := PerformCodeReview(context.TODO(), CodeReviewParams{Audience: "LLM", GoCode: "package store\n"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(clean.Summary, "top_issues:\nnext_actions:\n- none") {
+		t.Errorf("expected no issues and no actions, got:\n%s", clean.Summary)
+	}
+
+	if human, _ := PerformCodeReview(context.TODO(), CodeReviewParams{GoCode: "package store\n"}); human.Brief != nil {
+		t.Error("expected no brief for the default audience")
+	}
+	if _, err := PerformCodeReview(context.TODO(), CodeReviewParams{Audience: "robot", GoCode: "package store\n"}); err == nil {
+		t.Error("expected an error for an unknown audience")
+	}
+}
+
+func TestIssueSnippets(t *testing.T) {
+	code := `package main
+
+func main() {}
+
+func helper() {
+	println("x")
+}
+`
+
+	tests := []struct {
+		name         string
+		contextLines int
+		wantStart    int
+	}{
+		{
+			name:         "default context",
+			contextLines: 0,
+			wantStart:    3,
+		},
+		{
+			name:         "one context line",
+			contextLines: 1,
+			wantStart:    4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := PerformCodeReview(context.TODO(), CodeReviewParams{GoCode: code, ContextLines: tt.contextLines})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var issue *Issue
+			for i := range result.Issues {
+				if result.Issues[i].Rule == "unused-symbol" {
+					issue = &result.Issues[i]
+				}
+			}
+			if issue == nil {
+				t.Fatal("expected unused-symbol issue")
+			}
+
+			if issue.Line != 5 || issue.Column != 6 || issue.EndLine != 5 {
+				t.Errorf("expected position 5:6-5, got %d:%d-%d", issue.Line, issue.Column, issue.EndLine)
+			}
+			if issue.SnippetStartLine != tt.wantStart {
+				t.Errorf("expected snippet start %d, got %d", tt.wantStart, issue.SnippetStartLine)
+			}
+			if !strings.Contains(issue.Snippet, "func helper() {") {
+				t.Errorf("expected snippet to contain offending line, got %q", issue.Snippet)
+			}
+		})
+	}
+}
+
+func TestExtractSnippet(t *testing.T) {
+	lines := strings.Split("a\nb\nc\nd\ne", "\n")
+
+	tests := []struct {
+		name      string
+		line      int
+		endLine   int
+		context   int
+		want      string
+		wantStart int
+	}{
+		{"single line with context", 3, 3, 1, "b\nc\nd", 2},
+		{"clamped at start", 1, 1, 2, "a\nb\nc", 1},
+		{"clamped at end", 5, 5, 2, "c\nd\ne", 3},
+		{"multi-line node", 2, 4, 0, "b\nc\nd", 2},
+		{"out of range", 9, 9, 1, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, start := extractSnippet(lines, tt.line, tt.endLine, tt.context)
+			if got != tt.want || start != tt.wantStart {
+				t.Errorf("expected %q at %d, got %q at %d", tt.want, tt.wantStart, got, start)
+			}
+		})
+	}
+}
+
+func TestCapitalize(t *testing.T) {
+	tests := []struct {
+		name          string
+		want          string
+		isCapitalized bool
+	}{
+		{"", "", false},
+		{"server", "Server", false},
+		{"Server", "Server", true},
+		{"éclair", "Éclair", false},
+		{"Ωmega", "Ωmega", true},
+		{"名前", "名前", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := capitalize(tt.name); got != tt.want {
+				t.Errorf("capitalize(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+			if got := isCapitalized(tt.name); got != tt.isCapitalized {
+				t.Errorf("isCapitalized(%q) = %v, want %v", tt.name, got, tt.isCapitalized)
+			}
+		})
+	}
+}
+
+func TestPerformBatchReview(t *testing.T) {
+	params := BatchReviewParams{
+		Items: []BatchItem{
+			{Name: "good.go", GoCode: "package main\n\nfunc main() {}\n"},
+			{Name: "dead.go", GoCode: "package main\n\nfunc a() {}\n\nfunc b() {}\n\nfunc main() {}\n"},
+			{Name: "empty.go", GoCode: ""},
+		},
+		MaxWorkers: 2,
+	}
+
+	result, err := PerformBatchReview(context.TODO(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(result.Results))
+	}
+
+	// Results keep input order
+	for i, item := range params.Items {
+		if result.Results[i].Name != item.Name {
+			t.Errorf("expected result %d to be '%s', got '%s'", i, item.Name, result.Results[i].Name)
+		}
+	}
+
+	if result.FailedItems != 1 || result.Results[2].Error == "" {
+		t.Errorf("expected empty item to fail, got %+v", result.Results[2])
+	}
+	if result.WorstItem != "dead.go" {
+		t.Errorf("expected worst item 'dead.go', got '%s'", result.WorstItem)
+	}
+	if result.WorstScore >= result.Results[0].Result.Score {
+		t.Errorf("expected worst score below %d, got %d", result.Results[0].Result.Score, result.WorstScore)
+	}
+	if result.Summary == "" {
+		t.Error("expected summary to be set")
+	}
+}
+
+func TestPerformBatchReview_Errors(t *testing.T) {
+	if _, err := PerformBatchReview(context.TODO(), BatchReviewParams{}); err == nil {
+		t.Error("expected error for empty items")
+	}
+
+	items := make([]BatchItem, MaxBatchItems+1)
+	if _, err := PerformBatchReview(context.TODO(), BatchReviewParams{Items: items}); err == nil {
+		t.Error("expected error for too many items")
+	}
+}
+
+func TestPerformWorkspaceReview(t *testing.T) {
+	files := map[string]string{
+		"main.go":               "package main\n\nfunc main() {}\n",
+		"pkg/util/util.go":      "package util\n\nfunc helper() {}\n",
+		"pkg/util/extra.go":     "package util\n\nconst limit = 1\n",
+		"vendor/dep/dep.go":     "package dep\n",
+		"testdata/sample.go":    "package sample\n",
+		"gen/generated.pb.go":   "package gen\n",
+		".hidden/ignored.go":    "package hidden\n",
+		"pkg/util/README.md":    "# util\n",
+		"internal/skip/skip.go": "package skip\n",
+	}
+	root := testutil.WriteModule(t, files)
+
+	opts := WorkspaceOptions{
+		Roots:  []string{root},
+		Ignore: []string{"*.pb.go", "internal/skip"},
+	}
+
+	var progress []string
+	result, err := PerformWorkspaceReview(context.TODO(), CodeReviewParams{WorkingDir: root}, opts, func(done, total int, file string) {
+		if total != 3 {
+			t.Errorf("expected total 3, got %d", total)
+		}
+		progress = append(progress, file)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantFiles := []string{"main.go", "pkg/util/extra.go", "pkg/util/util.go"}
+	if strings.Join(progress, ",") != strings.Join(wantFiles, ",") {
+		t.Errorf("expected progress for %v, got %v", wantFiles, progress)
+	}
+
+	if result.Workspace == nil || result.Workspace.Files != 3 {
+		t.Fatalf("expected workspace report for 3 files, got %+v", result.Workspace)
+	}
+	if len(result.Workspace.Packages) != 2 {
+		t.Fatalf("expected 2 packages, got %d", len(result.Workspace.Packages))
+	}
+
+	util := result.Workspace.Packages[1]
+	if util.Path != "pkg/util" || util.Files != 2 || util.Issues != 3 {
+		t.Errorf("unexpected rollup for pkg/util: %+v", util)
+	}
+	for _, issue := range result.Issues {
+		if issue.File == "" {
+			t.Error("expected workspace issues to carry a file path")
+		}
+	}
+}
+
+func TestPerformWorkspaceReview_Roots(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		roots []string
+	}{
+		{"no registered roots", nil},
+		{"outside registered root", []string{filepath.Join(root, "other")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PerformWorkspaceReview(context.TODO(), CodeReviewParams{WorkingDir: root}, WorkspaceOptions{Roots: tt.roots}, nil)
+			if err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+
+	_, err := PerformWorkspaceReview(context.TODO(), CodeReviewParams{WorkingDir: root}, WorkspaceOptions{Roots: []string{root}, MaxFiles: 1}, nil)
+	if err != nil {
+		t.Errorf("unexpected error at file limit: %v", err)
+	}
+}
+
+func TestPerformWorkspaceReview_History(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "main.go")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	opts := WorkspaceOptions{Roots: []string{root}, History: history.NewStore(t.TempDir(), 0)}
+
+	if _, err := WorkspaceTrend(history.TrendParams{WorkingDir: root}, opts); err == nil {
+		t.Error("expected an error before the first review")
+	}
+
+	write("package main\n\nfunc main() {}\n\nfunc unused() {}\n")
+	if _, err := PerformWorkspaceReview(context.TODO(), CodeReviewParams{WorkingDir: root}, opts, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	write("package main\n\nfunc main() {}\n")
+	if _, err := PerformWorkspaceReview(context.TODO(), CodeReviewParams{WorkingDir: root}, opts, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	trend, err := WorkspaceTrend(history.TrendParams{WorkingDir: root}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(trend.Runs) != 2 || trend.Direction != history.DirectionImproving {
+		t.Errorf("expected two runs improving, got %+v", trend)
+	}
+	if len(trend.FixedIssues) != 1 || trend.FixedIssues[0].Rule != "unused-symbol" || len(trend.NewIssues) != 0 {
+		t.Errorf("expected the unused function to be fixed, got new %+v and fixed %+v", trend.NewIssues, trend.FixedIssues)
+	}
+	if len(trend.Packages) != 1 || trend.Packages[0].Path != "." {
+		t.Errorf("unexpected package trends %+v", trend.Packages)
+	}
+
+	if _, err := WorkspaceTrend(history.TrendParams{WorkingDir: root}, WorkspaceOptions{Roots: []string{root}}); err == nil {
+		t.Error("expected an error without a history store")
+	}
+	if _, err := WorkspaceTrend(history.TrendParams{WorkingDir: t.TempDir()}, opts); err == nil {
+		t.Error("expected an error outside the registered roots")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	base := CodeReviewParams{GoCode: "package a", Hint: "performance"}
+
+	key, ok := CacheKey(base)
+	if !ok || key == "" {
+		t.Fatal("expected inline review to be cacheable")
+	}
+	if again, _ := CacheKey(base); again != key {
+		t.Error("expected identical params to produce the same key")
+	}
+
+	variants := []CodeReviewParams{
+		{GoCode: "package b", Hint: "performance"},
+		{GoCode: "package a", Hint: "security"},
+		{GoCode: "package a", Hint: "performance", PreviousCode: "package a"},
+		{GoCode: "package a", Hint: "performance", Language: "ja"},
+		{GoCode: "package a", Hint: "performance", CoverageProfile: coverageTestProfile},
+	}
+	for i, p := range variants {
+		if k, _ := CacheKey(p); k == key {
+			t.Errorf("expected variant %d to produce a different key", i)
+		}
+	}
+
+	for _, p := range []CodeReviewParams{
+		{GoCode: "package a", GuidelinesFile: "guidelines.md"},
+		{WorkingDir: "."},
+	} {
+		if _, ok := CacheKey(p); ok {
+			t.Errorf("expected params reading from disk not to be cacheable: %+v", p)
+		}
+	}
+}
+
+const coverageTestCode = `package demo
+
+import "strconv"
+
+func covered(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func classify(s string) string {
+	n, _ := strconv.Atoi(s)
+	if n < 0 {
+		return "negative"
+	}
+	if n == 0 {
+		return "zero"
+	}
+	if n < 10 {
+		return "small"
+	}
+	if n < 100 {
+		return "medium"
+	}
+	return "large"
+}
+`
+
+// coverageTestProfile covers covered() and leaves classify() unexecuted
+const coverageTestProfile = `mode: set
+example.com/demo/demo.go:5.29,8.2 2 1
+example.com/demo/demo.go:10.32,12.11 2 0
+example.com/demo/demo.go:12.11,14.3 1 0
+example.com/demo/demo.go:15.2,15.12 1 0
+example.com/demo/demo.go:15.12,17.3 1 0
+`
+
+func TestParseCoverageProfile(t *testing.T) {
+	profile, err := ParseCoverageProfile(coverageTestProfile)
+	if err != nil {
+		t.Fatalf("ParseCoverageProfile() error = %v", err)
+	}
+	blocks, ok := profile.blocksFor("demo.go")
+	if !ok || len(blocks) != 5 {
+		t.Fatalf("expected 5 blocks for demo.go, got %d (found %v)", len(blocks), ok)
+	}
+	if total, covered := statementCoverage(blocks, 5, 8); total != 2 || covered != 2 {
+		t.Errorf("expected covered() fully covered, got %d/%d", covered, total)
+	}
+
+	for _, content := range []string{"", "mode: set\n", "mode: set\ndemo.go:1.1,2.2 x 1\n", "no colon here"} {
+		if _, err := ParseCoverageProfile(content); err == nil {
+			t.Errorf("expected error for profile %q", content)
+		}
+	}
+
+	multi := CoverageProfile{"a/x/demo.go": nil, "a/y/demo.go": nil}
+	if _, ok := multi.blocksFor("demo.go"); ok {
+		t.Error("expected ambiguous file name not to match")
+	}
+	if _, ok := multi.blocksFor("x/demo.go"); !ok {
+		t.Error("expected path suffix to match")
+	}
+	if _, ok := multi.blocksFor("x\\demo.go"); !ok {
+		t.Error("expected backslash path suffix to match")
+	}
+	windows := CoverageProfile{"C:\\src\\app\\demo.go": nil}
+	if _, ok := windows.blocksFor("app/demo.go"); !ok {
+		t.Error("expected Windows profile path to match")
+	}
+}
+
+func TestPerformCodeReview_Coverage(t *testing.T) {
+	plain, err := PerformCodeReview(context.TODO(), CodeReviewParams{GoCode: coverageTestCode})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := PerformCodeReview(context.TODO(), CodeReviewParams{
+		GoCode:          coverageTestCode,
+		CoverageProfile: coverageTestProfile,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Metrics.TestCoverage != "28.6%" {
+		t.Errorf("expected file coverage 28.6%%, got %s", result.Metrics.TestCoverage)
+	}
+
+	severities := make(map[int]string)
+	var untested *Issue
+	for i, issue := range result.Issues {
+		switch issue.Rule {
+		case "error-handling":
+			severities[issue.Line] = issue.Severity
+		case "untested-complex-function":
+			untested = &result.Issues[i]
+		}
+	}
+	if severities[6] != "high" {
+		t.Errorf("expected issue in covered function to keep its severity, got %q", severities[6])
+	}
+	if severities[11] != "critical" {
+		t.Errorf("expected issue in uncovered function to be raised to critical, got %q", severities[11])
+	}
+	if untested == nil || untested.Line != 10 || !untested.Uncovered || !strings.Contains(untested.Message, "classify") {
+		t.Errorf("expected untested complex function issue for classify, got %+v", untested)
+	}
+	if result.Score >= plain.Score {
+		t.Errorf("expected coverage to lower the score, got %d (was %d)", result.Score, plain.Score)
+	}
+
+	if report := FormatMarkdown(result, "en"); !strings.Contains(report, "_(untested)_") {
+		t.Error("expected markdown report to mark untested issues")
+	}
+}
+
+func TestPerformCodeReview_CoverageErrors(t *testing.T) {
+	multi := coverageTestProfile + "example.com/demo/other.go:1.1,2.2 1 1\n"
+
+	tests := []struct {
+		name   string
+		params CodeReviewParams
+		want   string
+	}{
+		{"run without working dir", CodeReviewParams{GoCode: coverageTestCode, RunCoverage: true}, "requires working_dir"},
+		{"ambiguous profile", CodeReviewParams{GoCode: coverageTestCode, CoverageProfile: multi}, "set coverage_file"},
+		{"unknown file", CodeReviewParams{GoCode: coverageTestCode, CoverageProfile: multi, CoverageFile: "missing.go"}, "no unique entry"},
+		{"invalid profile", CodeReviewParams{GoCode: coverageTestCode, CoverageProfile: "garbage"}, "invalid coverage profile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PerformCodeReview(context.TODO(), tt.params)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := PerformCodeReview(context.TODO(), CodeReviewParams{
+		GoCode: coverageTestCode, CoverageProfile: multi, CoverageFile: "demo/demo.go",
+	}); err != nil {
+		t.Errorf("expected coverage_file to select the entry, got %v", err)
+	}
+}
+
+func TestPerformWorkspaceReview_RunCoverage(t *testing.T) {
+	files := map[string]string{
+		"go.mod":       "module example.com/demo\n\ngo 1.23\n",
+		"demo.go":      coverageTestCode,
+		"demo_test.go": "package demo\n\nimport \"testing\"\n\nfunc TestCovered(t *testing.T) {\n\tif covered(\"1\") != 1 {\n\t\tt.Fail()\n\t}\n}\n",
+	}
+	root := testutil.WriteModule(t, files)
+
+	result, err := PerformWorkspaceReview(context.TODO(), CodeReviewParams{WorkingDir: root, RunCoverage: true},
+		WorkspaceOptions{Roots: []string{root}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Metrics.TestCoverage == "unknown" {
+		t.Error("expected workspace coverage to be reported")
+	}
+	found := false
+	for _, issue := range result.Issues {
+		if issue.Rule == "untested-complex-function" && issue.File == "demo.go" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected untested complex function issue in demo.go, got %+v", result.Issues)
+	}
+}
+
+func TestPerformCodeReview_Thresholds(t *testing.T) {
+	code := `package lib
+
+// Sum adds its arguments
+func Sum(a, b int, c int, d int) int {
+	return a + b + c + d
+}
+`
+	rules := func(result *ReviewResult) map[string]string {
+		found := map[string]string{}
+		for _, issue := range result.Issues {
+			found[issue.Rule] = issue.Message
+		}
+		return found
+	}
+
+	result, err := PerformCodeReview(context.TODO(), CodeReviewParams{GoCode: code})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := rules(result)["parameter-count"]; ok {
+		t.Errorf("expected no parameter-count issue at the default limit, got %+v", result.Issues)
+	}
+
+	result, err = PerformCodeReview(context.TODO(), CodeReviewParams{GoCode: code, Thresholds: Thresholds{Parameters: 2, Complexity: 1}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := rules(result)
+	if msg, ok := found["parameter-count"]; !ok || !strings.Contains(msg, ">2") {
+		t.Errorf("expected a parameter-count issue naming the limit, got %q", msg)
+	}
+	if _, ok := found["cyclomatic-complexity"]; ok {
+		t.Errorf("expected complexity 1 to stay within the limit, got %+v", result.Issues)
+	}
+
+	_, err = PerformCodeReview(context.TODO(), CodeReviewParams{GoCode: code, Thresholds: Thresholds{FunctionLines: -1}})
+	if err == nil || !strings.Contains(err.Error(), "thresholds.function_lines") {
+		t.Errorf("expected a negative threshold error, got %v", err)
+	}
+}
+
+func TestThresholds_WithDefaults(t *testing.T) {
+	got := Thresholds{Parameters: 3}.WithDefaults(DefaultThresholds())
+	want := Thresholds{FunctionLines: 50, Parameters: 3, StructFields: 10, Complexity: 10}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
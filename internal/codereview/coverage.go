@@ -0,0 +1,247 @@
+package codereview
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"math"
+	"os"
+	"os/exec"
+	"strings"
+
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/types"
+)
+
+// untestedComplexity is the cyclomatic complexity from which a function
+// without coverage is reported as a testability issue
+const untestedComplexity = 5
+
+// coverageBlock is one block of a Go coverage profile
+type coverageBlock struct {
+	StartLine int
+	EndLine   int
+	NumStmt   int
+	Count     int
+}
+
+// CoverageProfile holds the blocks of a coverage profile keyed by the file
+// path recorded by go test, e.g. example.com/mod/pkg/file.go
+type CoverageProfile map[string][]coverageBlock
+
+// ParseCoverageProfile parses the output of go test -coverprofile
+func ParseCoverageProfile(content string) (CoverageProfile, error) {
+	profile := make(CoverageProfile)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if lineNo == 1 && strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		// file.go:startLine.startCol,endLine.endCol numStmt count
+		colon := strings.LastIndex(line, ":")
+		if colon < 0 {
+			return nil, types.NewKindError(types.KindParseFailure, "invalid coverage profile line %d: %q", lineNo, line)
+		}
+		file := line[:colon]
+
+		var block coverageBlock
+		var startCol, endCol int
+		if _, err := fmt.Sscanf(line[colon+1:], "%d.%d,%d.%d %d %d",
+			&block.StartLine, &startCol, &block.EndLine, &endCol, &block.NumStmt, &block.Count); err != nil {
+			return nil, types.NewKindError(types.KindParseFailure, "invalid coverage profile line %d: %q", lineNo, line)
+		}
+		profile[file] = append(profile[file], block)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read coverage profile: %v", err)
+	}
+	if len(profile) == 0 {
+		return nil, fmt.Errorf("coverage profile contains no blocks")
+	}
+
+	return profile, nil
+}
+
+// RunCoverage runs the tests under dir and returns the coverage profile.
+// A profile written by a run with failing tests is still returned.
+func RunCoverage(ctx context.Context, dir string) (string, error) {
+	tmp, err := os.CreateTemp("", "coverage-*.out")
+	if err != nil {
+		return "", fmt.Errorf("failed to create coverage file: %v", err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	cmd := exec.CommandContext(ctx, "go", "test", "-coverprofile="+tmp.Name(), "./...")
+	cmd.Dir = dir
+	cmd.Env = logging.CommandEnv(ctx, nil)
+	output, runErr := cmd.CombinedOutput()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", ctxErr
+	}
+
+	data, err := os.ReadFile(tmp.Name())
+	if err != nil || len(data) == 0 {
+		if errors.Is(runErr, exec.ErrNotFound) {
+			return "", logging.ErrorWithRequestID(ctx, types.NewKindError(types.KindToolchainMissing, "go test failed: %v", runErr))
+		}
+		if runErr != nil {
+			return "", fmt.Errorf("go test failed: %v\nOutput: %s", runErr, strings.TrimSpace(string(output)))
+		}
+		return "", errors.New("go test did not write a coverage profile")
+	}
+	return string(data), nil
+}
+
+// blocksFor returns the blocks for the file with the given path relative to
+// the module or workspace root. An empty name selects the only file of a
+// single-file profile; a name matching several files selects none.
+func (p CoverageProfile) blocksFor(name string) ([]coverageBlock, bool) {
+	if name == "" {
+		if len(p) != 1 {
+			return nil, false
+		}
+		for _, blocks := range p {
+			return blocks, true
+		}
+	}
+
+	name = slashPath(name)
+	if blocks, ok := p[name]; ok {
+		return blocks, true
+	}
+	var match []coverageBlock
+	matches := 0
+	for file, blocks := range p {
+		if strings.HasSuffix(slashPath(file), "/"+name) {
+			match = blocks
+			matches++
+		}
+	}
+	return match, matches == 1
+}
+
+// slashPath converts backslash separators to slashes whatever the host OS,
+// since Windows clients may send either form
+func slashPath(path string) string {
+	return strings.ReplaceAll(path, "\\", "/")
+}
+
+// statementCoverage returns the number of statements and covered statements
+// in blocks lying within the lines from start to end
+func statementCoverage(blocks []coverageBlock, start, end int) (total, covered int) {
+	for _, b := range blocks {
+		if b.StartLine < start || b.EndLine > end {
+			continue
+		}
+		total += b.NumStmt
+		if b.Count > 0 {
+			covered += b.NumStmt
+		}
+	}
+	return total, covered
+}
+
+// loadCoverage returns the coverage profile requested by params, running
+// the tests in dir when params.RunCoverage is set. It returns nil when no
+// coverage was requested.
+func loadCoverage(ctx context.Context, params CodeReviewParams, dir string) (CoverageProfile, error) {
+	content := params.CoverageProfile
+	if params.RunCoverage {
+		if dir == "" {
+			return nil, fmt.Errorf("run_coverage requires working_dir")
+		}
+		var err error
+		if content, err = RunCoverage(ctx, dir); err != nil {
+			return nil, err
+		}
+	}
+	if content == "" {
+		return nil, nil
+	}
+	return ParseCoverageProfile(content)
+}
+
+// formatCoverage renders a statement coverage percentage
+func formatCoverage(total, covered int) string {
+	if total == 0 {
+		return "unknown"
+	}
+	return fmt.Sprintf("%.1f%%", float64(covered)*100/float64(total))
+}
+
+// checkCoverage raises the severity of issues in functions without test
+// coverage and reports complex functions that no test exercises
+func (a *Analyzer) checkCoverage(file *ast.File, result *ReviewResult) {
+	if a.coverage == nil {
+		return
+	}
+
+	total, covered := statementCoverage(a.coverage, 0, math.MaxInt)
+	result.Metrics.TestCoverage = formatCoverage(total, covered)
+
+	type span struct{ start, end int }
+	var uncovered []span
+	var untested []Issue
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		start, end := a.getLine(fn.Pos()), a.getLine(fn.End())
+		total, covered := statementCoverage(a.coverage, start, end)
+		if total == 0 || covered > 0 {
+			continue
+		}
+		uncovered = append(uncovered, span{start, end})
+
+		if complexity := calculateCyclomaticComplexity(fn); complexity >= untestedComplexity {
+			untested = append(untested, Issue{
+				Type:       "warning",
+				Category:   "testability",
+				Line:       start,
+				Column:     a.getColumn(fn.Pos()),
+				EndLine:    end,
+				Message:    a.msg("coverage.untested-complex", fn.Name.Name, complexity),
+				Suggestion: a.msg("coverage.untested-complex.fix"),
+				Severity:   "high",
+				Rule:       "untested-complex-function",
+				Uncovered:  true,
+			})
+		}
+	}
+
+	for i := range result.Issues {
+		issue := &result.Issues[i]
+		for _, s := range uncovered {
+			if issue.Line >= s.start && issue.Line <= s.end {
+				issue.Severity = raiseSeverity(issue.Severity)
+				issue.Uncovered = true
+				break
+			}
+		}
+	}
+	result.Issues = append(result.Issues, untested...)
+}
+
+// raiseSeverity returns the next higher severity
+func raiseSeverity(severity string) string {
+	for i, s := range severityOrder {
+		if s == severity && i > 0 {
+			return severityOrder[i-1]
+		}
+	}
+	return severity
+}
@@ -0,0 +1,163 @@
+package codereview
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// checkDeadCode flags unexported symbols that are never referenced and
+// statements that can never be reached
+func (a *Analyzer) checkDeadCode(file *ast.File, result *ReviewResult) {
+	a.checkUnusedSymbols(file, result)
+	a.checkUnreachableCode(file, result)
+}
+
+// declaredSymbol is a top-level declaration considered for dead code detection
+type declaredSymbol struct {
+	ident *ast.Ident
+	kind  string
+}
+
+// checkUnusedSymbols reports unexported top-level functions, types, constants
+// and variables that are not referenced anywhere in the file
+func (a *Analyzer) checkUnusedSymbols(file *ast.File, result *ReviewResult) {
+	var symbols []declaredSymbol
+	declIdents := make(map[*ast.Ident]bool)
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			// Methods may satisfy interfaces defined elsewhere, so skip them
+			if d.Recv != nil {
+				continue
+			}
+			declIdents[d.Name] = true
+			symbols = append(symbols, declaredSymbol{ident: d.Name, kind: "function"})
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					declIdents[s.Name] = true
+					symbols = append(symbols, declaredSymbol{ident: s.Name, kind: "type"})
+				case *ast.ValueSpec:
+					kind := "variable"
+					if d.Tok == token.CONST {
+						kind = "constant"
+					}
+					for _, name := range s.Names {
+						declIdents[name] = true
+						symbols = append(symbols, declaredSymbol{ident: name, kind: kind})
+					}
+				}
+			}
+		}
+	}
+
+	// Count references by name. Shadowing locals may hide an unused symbol,
+	// which keeps the check conservative.
+	references := make(map[string]int)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && !declIdents[ident] {
+			references[ident.Name]++
+		}
+		return true
+	})
+
+	for _, sym := range symbols {
+		name := sym.ident.Name
+		if sym.ident.IsExported() || name == "_" || name == "main" || name == "init" {
+			continue
+		}
+		if references[name] > 0 {
+			continue
+		}
+		result.Issues = append(result.Issues, Issue{
+			Type:       "style",
+			Category:   "dead-code",
+			Line:       a.getLine(sym.ident.Pos()),
+			Column:     a.getColumn(sym.ident.Pos()),
+			EndLine:    a.getLine(sym.ident.End()),
+			Message:    a.msg("dead-code.unused-symbol", a.msg("symbol."+sym.kind), name),
+			Suggestion: a.msg("dead-code.unused-symbol.fix", name),
+			Severity:   "low",
+			Rule:       "unused-symbol",
+		})
+	}
+}
+
+// checkUnreachableCode reports the first statement following a return,
+// panic, or unconditional branch within the same block
+func (a *Analyzer) checkUnreachableCode(file *ast.File, result *ReviewResult) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		var stmts []ast.Stmt
+		switch node := n.(type) {
+		case *ast.BlockStmt:
+			stmts = node.List
+		case *ast.CaseClause:
+			stmts = node.Body
+		case *ast.CommClause:
+			stmts = node.Body
+		default:
+			return true
+		}
+
+		for i, stmt := range stmts {
+			if !isTerminatingStmt(stmt) || i == len(stmts)-1 {
+				continue
+			}
+			next := stmts[i+1]
+			// A label makes the following code reachable via goto
+			if _, ok := next.(*ast.LabeledStmt); ok {
+				continue
+			}
+			result.Issues = append(result.Issues, Issue{
+				Type:       "warning",
+				Category:   "dead-code",
+				Line:       a.getLine(next.Pos()),
+				Column:     a.getColumn(next.Pos()),
+				EndLine:    a.getLine(next.End()),
+				Message:    a.msg("dead-code.unreachable", terminatingStmtName(stmt)),
+				Suggestion: a.msg("dead-code.unreachable.fix"),
+				Severity:   "low",
+				Rule:       "unreachable-code",
+			})
+			break
+		}
+		return true
+	})
+}
+
+// isTerminatingStmt reports whether control never continues past stmt
+func isTerminatingStmt(stmt ast.Stmt) bool {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return s.Tok == token.GOTO || s.Tok == token.BREAK || s.Tok == token.CONTINUE
+	case *ast.ExprStmt:
+		return isPanicCall(s.X)
+	}
+	return false
+}
+
+// terminatingStmtName describes a terminating statement for issue messages
+func terminatingStmtName(stmt ast.Stmt) string {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		return "return"
+	case *ast.BranchStmt:
+		return s.Tok.String()
+	default:
+		return "panic"
+	}
+}
+
+// isPanicCall reports whether expr is a call to the builtin panic
+func isPanicCall(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := call.Fun.(*ast.Ident)
+	return ok && ident.Name == "panic"
+}
@@ -0,0 +1,336 @@
+package codereview
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+// Corrected code shown with deadline issues
+const (
+	httpClientExample = `client := &http.Client{Timeout: 10 * time.Second}`
+
+	cancelExample = `ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+defer cancel()`
+
+	timeAfterExample = `timer := time.NewTimer(timeout)
+defer timer.Stop()
+for {
+	timer.Reset(timeout)
+	select {
+	case msg := <-ch:
+		handle(msg)
+	case <-timer.C:
+		return
+	}
+}`
+)
+
+// sqlContextMethods maps database/sql methods to their context variants
+var sqlContextMethods = map[string]string{
+	"Query":    "QueryContext",
+	"QueryRow": "QueryRowContext",
+	"Exec":     "ExecContext",
+	"Prepare":  "PrepareContext",
+	"Begin":    "BeginTx",
+	"Ping":     "PingContext",
+}
+
+// checkDeadlines flags code that can wait forever: http.Client without a
+// Timeout, context cancel functions that are not deferred, time.After in
+// select loops and database/sql calls without a context
+func (a *Analyzer) checkDeadlines(file *ast.File, result *ReviewResult) {
+	imports := importNames(file)
+	a.checkHTTPClientTimeout(file, imports["net/http"], result)
+	a.checkContextCancel(file, imports["context"], result)
+	a.checkTimeAfterLoops(file, imports["time"], result)
+	if sqlName, ok := imports["database/sql"]; ok {
+		a.checkSQLContext(file, sqlName, result)
+	}
+}
+
+// checkHTTPClientTimeout reports http.Client literals without a Timeout
+func (a *Analyzer) checkHTTPClientTimeout(file *ast.File, httpName string, result *ReviewResult) {
+	if httpName == "" {
+		return
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || !isPkgSelector(lit.Type, httpName, "Client") {
+			return true
+		}
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Timeout" {
+					return true
+				}
+			}
+		}
+		result.Issues = append(result.Issues, a.deadlineIssue(lit, "medium", "http-client-timeout",
+			a.msg("deadlines.http-client"), a.msg("deadlines.http-client.fix"), httpClientExample))
+		return true
+	})
+}
+
+// checkContextCancel reports cancel functions of context.WithTimeout and
+// context.WithDeadline that are discarded, or never deferred in the
+// function creating them. A cancel function returned, stored or passed on
+// is the caller's responsibility and not reported.
+func (a *Analyzer) checkContextCancel(file *ast.File, contextName string, result *ReviewResult) {
+	if contextName == "" {
+		return
+	}
+	forEachFuncBody(file, func(body *ast.BlockStmt) {
+		inspectBody(body, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 {
+				return true
+			}
+			call, ok := assign.Rhs[0].(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			fn := ""
+			for _, name := range []string{"WithTimeout", "WithDeadline"} {
+				if isPkgSelector(call.Fun, contextName, name) {
+					fn = name
+				}
+			}
+			cancel, ok := assign.Lhs[1].(*ast.Ident)
+			if fn == "" || !ok {
+				return true
+			}
+
+			switch {
+			case cancel.Name == "_":
+				result.Issues = append(result.Issues, a.deadlineIssue(call, "high", "context-cancel",
+					a.msg("deadlines.cancel-discarded", fn), a.msg("deadlines.cancel.fix"), cancelExample))
+			case !cancelHandled(body, cancel.Name):
+				result.Issues = append(result.Issues, a.deadlineIssue(call, "medium", "context-cancel",
+					a.msg("deadlines.cancel-not-deferred", cancel.Name, fn), a.msg("deadlines.cancel.fix"), cancelExample))
+			}
+			return true
+		})
+	})
+}
+
+// cancelHandled reports whether body defers a call to cancel, uses it in a
+// closure, or uses it other than by calling it, handing the responsibility
+// on
+func cancelHandled(body *ast.BlockStmt, cancel string) bool {
+	called := make(map[*ast.Ident]bool)
+	handled := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.DeferStmt, *ast.FuncLit:
+			// A deferred call or closure, e.g. a goroutine, takes care of it
+			handled = handled || references(node, cancel)
+		case *ast.AssignStmt:
+			// The assignment creating cancel is not a use
+			for _, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name == cancel {
+					called[ident] = true
+				}
+			}
+		case *ast.CallExpr:
+			if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == cancel {
+				called[ident] = true
+			}
+		case *ast.Ident:
+			if node.Name == cancel && !called[node] {
+				handled = true
+			}
+		}
+		return !handled
+	})
+	return handled
+}
+
+// references reports whether node mentions the identifier name
+func references(node ast.Node, name string) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// checkTimeAfterLoops reports time.After receives in select statements
+// inside loops, which allocate a new timer on every iteration
+func (a *Analyzer) checkTimeAfterLoops(file *ast.File, timeName string, result *ReviewResult) {
+	if timeName == "" {
+		return
+	}
+	reported := make(map[*ast.CallExpr]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		var body *ast.BlockStmt
+		switch loop := n.(type) {
+		case *ast.ForStmt:
+			body = loop.Body
+		case *ast.RangeStmt:
+			body = loop.Body
+		default:
+			return true
+		}
+
+		inspectBody(body, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectStmt)
+			if !ok {
+				return true
+			}
+			for _, stmt := range sel.Body.List {
+				clause, ok := stmt.(*ast.CommClause)
+				if !ok {
+					continue
+				}
+				call := timeAfterReceive(clause.Comm, timeName)
+				if call == nil || reported[call] {
+					continue
+				}
+				reported[call] = true
+				result.Issues = append(result.Issues, a.deadlineIssue(call, "medium", "time-after-loop",
+					a.msg("deadlines.time-after-loop"), a.msg("deadlines.time-after-loop.fix"), timeAfterExample))
+			}
+			return true
+		})
+		return true
+	})
+}
+
+// timeAfterReceive returns the time.After call received from by a select
+// case, such as "case <-time.After(d):"
+func timeAfterReceive(comm ast.Stmt, timeName string) *ast.CallExpr {
+	var expr ast.Expr
+	switch stmt := comm.(type) {
+	case *ast.ExprStmt:
+		expr = stmt.X
+	case *ast.AssignStmt:
+		if len(stmt.Rhs) == 1 {
+			expr = stmt.Rhs[0]
+		}
+	}
+	recv, ok := expr.(*ast.UnaryExpr)
+	if !ok || recv.Op != token.ARROW {
+		return nil
+	}
+	call, ok := recv.X.(*ast.CallExpr)
+	if !ok || !isPkgSelector(call.Fun, timeName, "After") {
+		return nil
+	}
+	return call
+}
+
+// checkSQLContext reports database/sql method calls that have a context
+// variant, in files importing database/sql. Without type information any
+// receiver other than an imported package is assumed to be a sql value.
+func (a *Analyzer) checkSQLContext(file *ast.File, sqlName string, result *ReviewResult) {
+	packages := make(map[string]bool)
+	for _, name := range importNames(file) {
+		packages[name] = true
+	}
+	packages[sqlName] = true
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		variant, ok := sqlContextMethods[sel.Sel.Name]
+		if !ok {
+			return true
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok && packages[pkg.Name] {
+			return true
+		}
+
+		fixed := &ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: sel.X, Sel: ast.NewIdent(variant)},
+			Args: append([]ast.Expr{ast.NewIdent("ctx")}, call.Args...),
+		}
+		if variant == "BeginTx" {
+			fixed.Args = append(fixed.Args, ast.NewIdent("nil"))
+		}
+		result.Issues = append(result.Issues, a.deadlineIssue(call, "medium", "sql-context",
+			a.msg("deadlines.sql-context", sel.Sel.Name), a.msg("deadlines.sql-context.fix", variant), types.ExprString(fixed)))
+		return true
+	})
+}
+
+// deadlineIssue builds a reliability issue for node with corrected example
+// code
+func (a *Analyzer) deadlineIssue(node ast.Node, severity, rule, message, suggestion, example string) Issue {
+	return Issue{
+		Type:       "warning",
+		Category:   "reliability",
+		Line:       a.getLine(node.Pos()),
+		Column:     a.getColumn(node.Pos()),
+		EndLine:    a.getLine(node.End()),
+		Message:    message,
+		Suggestion: suggestion,
+		Example:    example,
+		Severity:   severity,
+		Rule:       rule,
+	}
+}
+
+// importNames returns the local name of each import path in file, using
+// the last path element for unnamed imports. Dot and blank imports are
+// skipped.
+func importNames(file *ast.File) map[string]string {
+	names := make(map[string]string)
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path
+		for i := len(path) - 1; i >= 0; i-- {
+			if path[i] == '/' {
+				name = path[i+1:]
+				break
+			}
+		}
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue
+		}
+		names[path] = name
+	}
+	return names
+}
+
+// isPkgSelector reports whether expr is pkg.name
+func isPkgSelector(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == pkg
+}
+
+// forEachFuncBody calls f with the body of every function declaration and
+// literal in file
+func forEachFuncBody(file *ast.File, f func(body *ast.BlockStmt)) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			if fn.Body != nil {
+				f(fn.Body)
+			}
+		case *ast.FuncLit:
+			f(fn.Body)
+		}
+		return true
+	})
+}
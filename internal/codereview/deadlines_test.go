@@ -0,0 +1,158 @@
+package codereview
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeCode_Deadlines(t *testing.T) {
+	code := `package app
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+var client = &http.Client{}
+
+var timed = http.Client{Timeout: time.Second}
+
+func Fetch(ctx context.Context, db *sql.DB) error {
+	ctx, _ = context.WithTimeout(ctx, time.Second)
+	rows, err := db.Query("SELECT 1", 2)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	_, err = db.ExecContext(ctx, "DELETE FROM t")
+	return err
+}
+
+func Leak(ctx context.Context) error {
+	ctx, cancel := context.WithDeadline(ctx, time.Now())
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	cancel()
+	return nil
+}
+
+func Deferred(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	_ = ctx
+}
+
+func Returned(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, time.Second)
+}
+
+func Handed(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	return ctx, cancel
+}
+
+func Watch(ctx context.Context, ch <-chan int) {
+	for {
+		select {
+		case v := <-ch:
+			_ = v
+		case <-time.After(time.Second):
+			return
+		}
+	}
+}
+
+func Once(ch <-chan int) {
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+	}
+}
+`
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[int]Issue{}
+	for _, issue := range result.Issues {
+		if issue.Category == "reliability" {
+			got[issue.Line] = issue
+		}
+	}
+
+	want := map[int]string{
+		10: "http-client-timeout: http.Client has no Timeout; a slow or unresponsive server can block the request forever",
+		15: "context-cancel: The cancel function returned by context.WithTimeout is discarded; the context is only released when the timer fires",
+		16: "sql-context: Query does not take a context; the query keeps running after the caller gives up or its deadline passes",
+		26: "context-cancel: cancel from context.WithDeadline is never deferred; any return before it is called leaks the context and its timer",
+		54: "time-after-loop: time.After in a select inside a loop creates a new timer on every iteration",
+	}
+	for line, msg := range want {
+		issue, ok := got[line]
+		if !ok || issue.Rule+": "+issue.Message != msg {
+			t.Errorf("line %d: expected %q, got %q", line, msg, issue.Rule+": "+issue.Message)
+			continue
+		}
+		if issue.Example == "" {
+			t.Errorf("line %d: expected corrected example code", line)
+		}
+	}
+	for line, issue := range got {
+		if _, ok := want[line]; !ok {
+			t.Errorf("unexpected reliability issue on line %d: %s", line, issue.Message)
+		}
+	}
+
+	if example := got[16].Example; example != `db.QueryContext(ctx, "SELECT 1", 2)` {
+		t.Errorf("unexpected sql example %q", example)
+	}
+	if !strings.Contains(got[10].Example, "Timeout:") {
+		t.Errorf("unexpected http example %q", got[10].Example)
+	}
+}
+
+func TestAnalyzeCode_DeadlinesNeedImports(t *testing.T) {
+	code := `package app
+
+type Client struct{}
+
+type db struct{}
+
+func (db) Query(string) {}
+
+var c = &Client{}
+
+func Run(d db) {
+	d.Query("x")
+}
+`
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, issue := range result.Issues {
+		if issue.Category == "reliability" {
+			t.Errorf("unexpected reliability issue without the packages imported: %+v", issue)
+		}
+	}
+}
+
+func TestFormatMarkdown_Example(t *testing.T) {
+	result := &ReviewResult{Issues: []Issue{{
+		Message:  "http.Client has no Timeout",
+		Severity: "medium",
+		Rule:     "http-client-timeout",
+		Example:  httpClientExample,
+	}}}
+
+	report := FormatMarkdown(result, "en")
+	if !strings.Contains(report, "  - Example:\n\n    ```go\n    "+httpClientExample+"\n    ```") {
+		t.Errorf("expected example code block in report:\n%s", report)
+	}
+}
@@ -0,0 +1,237 @@
+package codereview
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"mcp-go-assistant/internal/i18n"
+)
+
+// kitchenSinkNames are package names that say nothing about what the
+// package provides, so unrelated code accumulates in it
+var kitchenSinkNames = map[string]bool{
+	"util": true, "utils": true, "utility": true, "utilities": true,
+	"common": true, "helper": true, "helpers": true,
+	"misc": true, "shared": true, "base": true,
+}
+
+// PackageDesign describes the design of a package from the files reviewed
+// together. Coupling only counts imports between the reviewed packages.
+type PackageDesign struct {
+	Path             string   `json:"path"` // Directory of the package's files
+	Name             string   `json:"name"`
+	Files            int      `json:"files"`
+	Symbols          int      `json:"symbols"` // Top-level declarations and methods
+	ExportedSymbols  int      `json:"exported_symbols"`
+	ExportedRatio    float64  `json:"exported_ratio"`
+	AfferentCoupling int      `json:"afferent_coupling"`      // Reviewed packages importing this one
+	EfferentCoupling int      `json:"efferent_coupling"`      // Reviewed packages this one imports
+	Instability      float64  `json:"instability"`            // Efferent / (afferent + efferent); 0 without coupling
+	Dependents       []string `json:"dependents,omitempty"`   // Paths, or names at the root, of the importing packages
+	Dependencies     []string `json:"dependencies,omitempty"` // Paths, or names at the root, of the imported packages
+	AverageFileLines int      `json:"average_file_lines"`
+	KitchenSink      bool     `json:"kitchen_sink,omitempty"` // Named like a catch-all, such as util or common
+}
+
+// designFile is a reviewed file considered for package design metrics
+type designFile struct {
+	path string // Slash-separated; its directory and package name identify the package
+	code string
+}
+
+// designPackage is a package while its design metrics are computed
+type designPackage struct {
+	design  PackageDesign
+	imports map[string]bool
+	lines   int
+	clause  Issue // Position of the package clause of its first file
+}
+
+// designPackages computes the design metrics of the packages of files, in
+// path order, and returns an issue for each kitchen-sink package. Files
+// belong to the package of their directory and name, so snippets reviewed
+// without a directory are told apart by name. Test files and files that do
+// not parse are left out. Imports are matched to packages by modulePath and
+// directory when the module is known, and by path suffix otherwise. Issue
+// snippets show contextLines lines around the package clause.
+func designPackages(files []designFile, modulePath, lang string, contextLines int) ([]PackageDesign, []Issue) {
+	packages := make(map[string]*designPackage)
+	for _, f := range files {
+		if strings.HasSuffix(f.path, "_test.go") {
+			continue
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "", f.code, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+
+		dir := path.Dir(f.path)
+		key := dir + " " + file.Name.Name
+		pkg, ok := packages[key]
+		if !ok {
+			pos := fset.Position(file.Name.Pos())
+			snippet, start := extractSnippet(strings.Split(f.code, "\n"), pos.Line, pos.Line, contextLines)
+			pkg = &designPackage{
+				design:  PackageDesign{Path: dir, Name: file.Name.Name},
+				imports: make(map[string]bool),
+				clause: Issue{
+					File: f.path, Line: pos.Line, Column: pos.Column, EndLine: pos.Line,
+					Snippet: snippet, SnippetStartLine: start,
+				},
+			}
+			packages[key] = pkg
+		}
+		pkg.design.Files++
+		pkg.lines += fset.File(file.Pos()).LineCount()
+		for _, spec := range file.Imports {
+			pkg.imports[strings.Trim(spec.Path.Value, "`\"")] = true
+		}
+		symbols, exported := countSymbols(file)
+		pkg.design.Symbols += symbols
+		pkg.design.ExportedSymbols += exported
+	}
+
+	keys := make([]string, 0, len(packages))
+	for key := range packages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		pkg := packages[key]
+		for _, otherKey := range keys {
+			other := packages[otherKey]
+			if otherKey != key && importsPackage(pkg.imports, other, modulePath) {
+				pkg.design.Dependencies = append(pkg.design.Dependencies, other.design.label())
+				other.design.Dependents = append(other.design.Dependents, pkg.design.label())
+			}
+		}
+	}
+
+	t := func(key string, args ...interface{}) string {
+		return messages.Translate(i18n.Normalize(lang), key, args...)
+	}
+	designs := make([]PackageDesign, 0, len(keys))
+	var issues []Issue
+	for _, key := range keys {
+		pkg := packages[key]
+		d := &pkg.design
+		d.AfferentCoupling = len(d.Dependents)
+		d.EfferentCoupling = len(d.Dependencies)
+		if d.Symbols > 0 {
+			d.ExportedRatio = roundRatio(float64(d.ExportedSymbols) / float64(d.Symbols))
+		}
+		if coupling := d.AfferentCoupling + d.EfferentCoupling; coupling > 0 {
+			d.Instability = roundRatio(float64(d.EfferentCoupling) / float64(coupling))
+		}
+		d.AverageFileLines = pkg.lines / d.Files
+		d.KitchenSink = kitchenSinkNames[d.Name]
+		designs = append(designs, *d)
+
+		if d.KitchenSink {
+			issue := pkg.clause
+			issue.Type = "warning"
+			issue.Category = "structure"
+			issue.Message = t("design.kitchen-sink", d.Name, d.Symbols)
+			issue.Suggestion = t("design.kitchen-sink.fix")
+			issue.Severity = "low"
+			issue.Rule = "kitchen-sink-package"
+			issues = append(issues, issue)
+		}
+	}
+	return designs, issues
+}
+
+// label names the package in dependency lists: its directory, or its name
+// for snippets reviewed without one
+func (d *PackageDesign) label() string {
+	if d.Path == "." {
+		return d.Name
+	}
+	return d.Path
+}
+
+// countSymbols counts the top-level declarations and methods of file and
+// the exported ones. Methods count as exported when their type is too.
+func countSymbols(file *ast.File) (symbols, exported int) {
+	count := func(name string, isExported bool) {
+		if name == "_" || name == "init" {
+			return
+		}
+		symbols++
+		if isExported {
+			exported++
+		}
+	}
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			isExported := d.Name.IsExported()
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				isExported = isExported && ast.IsExported(receiverTypeName(d.Recv.List[0].Type))
+			}
+			count(d.Name.Name, isExported)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					count(s.Name.Name, s.Name.IsExported())
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						count(name.Name, name.IsExported())
+					}
+				}
+			}
+		}
+	}
+	return symbols, exported
+}
+
+// importsPackage reports whether imports include pkg: its import path under
+// modulePath when known, or else any path ending in its label
+func importsPackage(imports map[string]bool, pkg *designPackage, modulePath string) bool {
+	if modulePath != "" {
+		importPath := modulePath
+		if pkg.design.Path != "." {
+			importPath += "/" + pkg.design.Path
+		}
+		return imports[importPath]
+	}
+
+	suffix := pkg.design.label()
+	for imp := range imports {
+		if imp == suffix || strings.HasSuffix(imp, "/"+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// roundRatio rounds a ratio to two decimals
+func roundRatio(r float64) float64 {
+	return math.Round(r*100) / 100
+}
+
+// readModulePath returns the module path declared by the go.mod file in
+// dir, or an empty string without one
+func readModulePath(dir string) string {
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(goMod), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+package codereview
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+func TestDesignPackages(t *testing.T) {
+	files := []designFile{
+		{path: "api/handler.go", code: `package api
+
+import (
+	"example.com/app/store"
+	"example.com/app/util"
+)
+
+type Handler struct{ s *store.Store }
+
+func (h *Handler) Serve() {}
+
+func (h *Handler) decode() {}
+
+func New() *Handler { return nil }
+
+var _ = util.Trim
+`},
+		{path: "store/store.go", code: `package store
+
+import "example.com/app/util"
+
+type Store struct{}
+
+type row struct{}
+
+func (r row) Key() string { return util.Trim("") }
+
+const limit = 10
+`},
+		{path: "store/store_test.go", code: "package store\n\nfunc TestStore() {}\n"},
+		{path: "util/util.go", code: `package util
+
+func Trim(s string) string { return s }
+
+func init() {}
+`},
+		{path: "util/broken.go", code: "package util\n\nfunc {"},
+	}
+
+	designs, issues := designPackages(files, "example.com/app", "", DefaultContextLines)
+
+	want := []PackageDesign{
+		{
+			Path: "api", Name: "api", Files: 1, Symbols: 4, ExportedSymbols: 3, ExportedRatio: 0.75,
+			EfferentCoupling: 2, Instability: 1, Dependencies: []string{"store", "util"}, AverageFileLines: 16,
+		},
+		{
+			Path: "store", Name: "store", Files: 1, Symbols: 4, ExportedSymbols: 1, ExportedRatio: 0.25,
+			AfferentCoupling: 1, EfferentCoupling: 1, Instability: 0.5,
+			Dependents: []string{"api"}, Dependencies: []string{"util"}, AverageFileLines: 11,
+		},
+		{
+			Path: "util", Name: "util", Files: 1, Symbols: 1, ExportedSymbols: 1, ExportedRatio: 1,
+			AfferentCoupling: 2, Dependents: []string{"api", "store"}, AverageFileLines: 5, KitchenSink: true,
+		},
+	}
+	if !reflect.DeepEqual(designs, want) {
+		t.Errorf("designs =\n%+v\nwant\n%+v", designs, want)
+	}
+
+	if len(issues) != 1 {
+		t.Fatalf("expected one kitchen-sink issue, got %+v", issues)
+	}
+	issue := issues[0]
+	if issue.Rule != "kitchen-sink-package" || issue.File != "util/util.go" || issue.Line != 1 || issue.Column != 9 {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+	if issue.Message != "Package util is named like a catch-all and holds 1 symbols, so unrelated code accumulates in it" {
+		t.Errorf("unexpected message %q", issue.Message)
+	}
+	if issue.Snippet != "package util\n\nfunc Trim(s string) string { return s }" || issue.SnippetStartLine != 1 {
+		t.Errorf("unexpected snippet %q from line %d", issue.Snippet, issue.SnippetStartLine)
+	}
+}
+
+func TestDesignPackages_WithoutModule(t *testing.T) {
+	files := []designFile{
+		{path: "main.go", code: "package main\n\nimport \"example.com/app/helpers\"\n\nfunc main() { helpers.Run() }\n"},
+		{path: "helpers.go", code: "package helpers\n\nfunc Run() {}\n"},
+	}
+
+	designs, issues := designPackages(files, "", "", DefaultContextLines)
+	if len(designs) != 2 {
+		t.Fatalf("expected 2 packages, got %+v", designs)
+	}
+	helpers, main := designs[0], designs[1]
+	if helpers.Name != "helpers" || !reflect.DeepEqual(helpers.Dependents, []string{"main"}) || !helpers.KitchenSink {
+		t.Errorf("unexpected design for helpers: %+v", helpers)
+	}
+	if main.Name != "main" || !reflect.DeepEqual(main.Dependencies, []string{"helpers"}) || main.KitchenSink {
+		t.Errorf("unexpected design for main: %+v", main)
+	}
+	if len(issues) != 1 || issues[0].File != "helpers.go" {
+		t.Errorf("expected a kitchen-sink issue for helpers.go, got %+v", issues)
+	}
+}
+
+func TestPerformBatchReview_Design(t *testing.T) {
+	params := BatchReviewParams{
+		Items: []BatchItem{
+			{Name: "cmd/main.go", GoCode: "package main\n\nimport \"example.com/app/common\"\n\nfunc main() { common.Run() }\n"},
+			{Name: "common/common.go", GoCode: "package common\n\n// Run runs\nfunc Run() {}\n"},
+		},
+	}
+
+	result, err := PerformBatchReview(context.TODO(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Packages) != 2 || result.Packages[1].Path != "common" || result.Packages[1].AfferentCoupling != 1 {
+		t.Errorf("unexpected packages %+v", result.Packages)
+	}
+
+	found := false
+	for _, issue := range result.Results[1].Result.Issues {
+		found = found || issue.Rule == "kitchen-sink-package"
+	}
+	if !found {
+		t.Error("expected a kitchen-sink issue for common/common.go")
+	}
+
+	single, err := PerformBatchReview(context.TODO(), BatchReviewParams{Items: params.Items[1:]})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if single.Packages != nil {
+		t.Errorf("expected no package designs for a single item, got %+v", single.Packages)
+	}
+}
+
+func TestPerformWorkspaceReview_Design(t *testing.T) {
+	files := map[string]string{
+		"go.mod":            "module example.com/app\n",
+		"main.go":           "package main\n\nimport \"example.com/app/store\"\n\nfunc main() { store.Open() }\n",
+		"store/store.go":    "package store\n\n// Open opens\nfunc Open() {}\n",
+		"vendor/x/store.go": "package store\n",
+	}
+	root := testutil.WriteModule(t, files)
+
+	result, err := PerformWorkspaceReview(context.TODO(), CodeReviewParams{WorkingDir: root}, WorkspaceOptions{Roots: []string{root}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	packages := result.Workspace.Packages
+	if len(packages) != 2 {
+		t.Fatalf("expected 2 packages, got %+v", packages)
+	}
+	for _, pkg := range packages {
+		if pkg.Design == nil || pkg.Design.Path != pkg.Path {
+			t.Fatalf("expected design for %s, got %+v", pkg.Path, pkg.Design)
+		}
+	}
+	if d := packages[1].Design; d.Path != "store" || !reflect.DeepEqual(d.Dependents, []string{"main"}) || d.Instability != 0 {
+		t.Errorf("unexpected design for store: %+v", d)
+	}
+}
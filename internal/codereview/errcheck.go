@@ -0,0 +1,394 @@
+package codereview
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// logMethodPrefixes are method name prefixes of calls that only log
+var logMethodPrefixes = []string{"Print", "Log", "Debug", "Info", "Warn", "Error", "Msg", "Send"}
+
+// checkIneffectiveErrors flags error checks that do not stop execution,
+// errors overwritten or shadowed before they are checked, and comparisons
+// against values that can never match
+func (a *Analyzer) checkIneffectiveErrors(file *ast.File, result *ReviewResult) {
+	a.checkSwallowedErrors(file, result)
+	a.checkOverwrittenErrors(file, result)
+	a.checkErrorComparisons(file, result)
+}
+
+// checkSwallowedErrors reports if-err blocks that only log the error in
+// functions that could return it instead
+func (a *Analyzer) checkSwallowedErrors(file *ast.File, result *ReviewResult) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		var typ *ast.FuncType
+		var body *ast.BlockStmt
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			typ, body = fn.Type, fn.Body
+		case *ast.FuncLit:
+			typ, body = fn.Type, fn.Body
+		default:
+			return true
+		}
+		if body == nil || !returnsError(typ) {
+			return true
+		}
+
+		// Function literals are visited on their own with their own results
+		inspectBody(body, func(n ast.Node) bool {
+			ifStmt, ok := n.(*ast.IfStmt)
+			if !ok {
+				return true
+			}
+			name, ok := errNotNilCheck(ifStmt.Cond)
+			if !ok || ifStmt.Else != nil || !onlyLogs(ifStmt.Body) {
+				return true
+			}
+			result.Issues = append(result.Issues, Issue{
+				Type:       "warning",
+				Category:   "error-handling",
+				Line:       a.getLine(ifStmt.Pos()),
+				Column:     a.getColumn(ifStmt.Pos()),
+				EndLine:    a.getLine(ifStmt.End()),
+				Message:    a.msg("error-handling.swallowed", name),
+				Suggestion: a.msg("error-handling.swallowed.fix"),
+				Severity:   "high",
+				Rule:       "swallowed-error",
+			})
+			return true
+		})
+		return true
+	})
+}
+
+// checkOverwrittenErrors reports errors that are reassigned before they are
+// checked, and errors declared with := in a nested block that shadow an
+// outer error used after the block
+func (a *Analyzer) checkOverwrittenErrors(file *ast.File, result *ReviewResult) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		stmts := blockStmts(n)
+		for i, stmt := range stmts {
+			if name, ok := assignedError(stmt); ok {
+				if next, use := nextUse(stmts[i+1:], name); use == useOverwrite {
+					result.Issues = append(result.Issues, Issue{
+						Type:       "warning",
+						Category:   "error-handling",
+						Line:       a.getLine(stmt.Pos()),
+						Column:     a.getColumn(stmt.Pos()),
+						EndLine:    a.getLine(stmt.End()),
+						Message:    a.msg("error-handling.overwritten", name, a.getLine(next.Pos())),
+						Suggestion: a.msg("error-handling.overwritten.fix"),
+						Severity:   "high",
+						Rule:       "overwritten-error",
+					})
+				}
+			}
+
+			name, ok := errorVar(stmt)
+			if !ok {
+				continue
+			}
+			// Later assignments to the outer error start their own scan
+			for j := i + 1; j < len(stmts); j++ {
+				if assign, ok := stmts[j].(*ast.AssignStmt); ok && assignsIdent(assign, name) {
+					break
+				}
+				shadow := shadowingDecl(stmts[j], name)
+				if shadow == nil {
+					continue
+				}
+				if _, use := nextUse(stmts[j+1:], name); use == useRead {
+					result.Issues = append(result.Issues, Issue{
+						Type:       "warning",
+						Category:   "error-handling",
+						Line:       a.getLine(shadow.Pos()),
+						Column:     a.getColumn(shadow.Pos()),
+						EndLine:    a.getLine(shadow.End()),
+						Message:    a.msg("error-handling.shadowed", name, a.getLine(stmt.Pos())),
+						Suggestion: a.msg("error-handling.shadowed.fix"),
+						Severity:   "high",
+						Rule:       "shadowed-error",
+					})
+				}
+			}
+		}
+		return true
+	})
+}
+
+// checkErrorComparisons reports == and != comparisons against newly created
+// errors, which never match, and against error message text
+func (a *Analyzer) checkErrorComparisons(file *ast.File, result *ReviewResult) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		bin, ok := n.(*ast.BinaryExpr)
+		if !ok || (bin.Op != token.EQL && bin.Op != token.NEQ) {
+			return true
+		}
+
+		key := ""
+		switch {
+		case isNewErrorCall(bin.X) || isNewErrorCall(bin.Y):
+			key = "error-handling.fresh-comparison"
+		case isErrorTextCall(bin.X) && isStringLit(bin.Y), isErrorTextCall(bin.Y) && isStringLit(bin.X):
+			key = "error-handling.text-comparison"
+		default:
+			return true
+		}
+		result.Issues = append(result.Issues, Issue{
+			Type:       "warning",
+			Category:   "error-handling",
+			Line:       a.getLine(bin.Pos()),
+			Column:     a.getColumn(bin.Pos()),
+			EndLine:    a.getLine(bin.End()),
+			Message:    a.msg(key),
+			Suggestion: a.msg("error-handling.comparison.fix"),
+			Severity:   "medium",
+			Rule:       "error-comparison",
+		})
+		return true
+	})
+}
+
+// useKind classifies how a statement refers to an error variable
+type useKind int
+
+const (
+	useNone      useKind = iota // Not referenced before control leaves the block
+	useRead                     // Read, e.g. checked or returned
+	useOverwrite                // Assigned again without being read
+)
+
+// nextUse returns the first statement in stmts referring to name and how
+// it does so. Scanning stops at statements that leave the block.
+func nextUse(stmts []ast.Stmt, name string) (ast.Stmt, useKind) {
+	for _, stmt := range stmts {
+		if assign, ok := stmt.(*ast.AssignStmt); ok && assignsIdent(assign, name) {
+			for _, rhs := range assign.Rhs {
+				if usesIdent(rhs, name) {
+					return stmt, useRead
+				}
+			}
+			return stmt, useOverwrite
+		}
+		if usesIdent(stmt, name) {
+			return stmt, useRead
+		}
+		if isTerminatingStmt(stmt) {
+			break
+		}
+	}
+	return nil, useNone
+}
+
+// shadowingDecl returns the first := declaration of name in a block nested
+// in stmt. Declarations in if, for and switch init statements are scoped to
+// their statement by design and are not reported.
+func shadowingDecl(stmt ast.Stmt, name string) ast.Stmt {
+	var found ast.Stmt
+	inspectBody(stmt, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		for _, s := range blockStmts(n) {
+			if assign, ok := s.(*ast.AssignStmt); ok && assign.Tok == token.DEFINE && assignsIdent(assign, name) {
+				found = s
+				return false
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// assignedError returns the error variable assigned from a call by stmt
+func assignedError(stmt ast.Stmt) (string, bool) {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || (assign.Tok != token.ASSIGN && assign.Tok != token.DEFINE) || len(assign.Rhs) != 1 {
+		return "", false
+	}
+	if _, ok := assign.Rhs[0].(*ast.CallExpr); !ok {
+		return "", false
+	}
+	for _, lhs := range assign.Lhs {
+		if ident, ok := lhs.(*ast.Ident); ok && isErrorName(ident.Name) {
+			return ident.Name, true
+		}
+	}
+	return "", false
+}
+
+// errorVar returns the error variable declared or assigned by stmt
+func errorVar(stmt ast.Stmt) (string, bool) {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		for _, lhs := range s.Lhs {
+			if ident, ok := lhs.(*ast.Ident); ok && isErrorName(ident.Name) {
+				return ident.Name, true
+			}
+		}
+	case *ast.DeclStmt:
+		if gen, ok := s.Decl.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+			for _, spec := range gen.Specs {
+				for _, ident := range spec.(*ast.ValueSpec).Names {
+					if isErrorName(ident.Name) {
+						return ident.Name, true
+					}
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// assignsIdent reports whether assign has name on its left-hand side
+func assignsIdent(assign *ast.AssignStmt, name string) bool {
+	for _, lhs := range assign.Lhs {
+		if ident, ok := lhs.(*ast.Ident); ok && ident.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// usesIdent reports whether node refers to name anywhere, including in
+// function literals
+func usesIdent(node ast.Node, name string) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// blockStmts returns the statement list of a block or case clause
+func blockStmts(n ast.Node) []ast.Stmt {
+	switch node := n.(type) {
+	case *ast.BlockStmt:
+		return node.List
+	case *ast.CaseClause:
+		return node.Body
+	case *ast.CommClause:
+		return node.Body
+	}
+	return nil
+}
+
+// inspectBody walks node like ast.Inspect without entering function literals
+func inspectBody(node ast.Node, f func(ast.Node) bool) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		return f(n)
+	})
+}
+
+// returnsError reports whether the last result of typ is an error
+func returnsError(typ *ast.FuncType) bool {
+	if typ.Results == nil || len(typ.Results.List) == 0 {
+		return false
+	}
+	ident, ok := typ.Results.List[len(typ.Results.List)-1].Type.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
+// errNotNilCheck returns the error variable of a cond such as err != nil
+func errNotNilCheck(cond ast.Expr) (string, bool) {
+	bin, ok := cond.(*ast.BinaryExpr)
+	if !ok || bin.Op != token.NEQ {
+		return "", false
+	}
+	x, y := bin.X, bin.Y
+	if isNilIdent(x) {
+		x, y = y, x
+	}
+	ident, ok := x.(*ast.Ident)
+	if !ok || !isNilIdent(y) || !isErrorName(ident.Name) {
+		return "", false
+	}
+	return ident.Name, true
+}
+
+// onlyLogs reports whether every statement in block is a logging call, so
+// execution continues after the block
+func onlyLogs(block *ast.BlockStmt) bool {
+	if len(block.List) == 0 {
+		return false
+	}
+	for _, stmt := range block.List {
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			return false
+		}
+		call, ok := expr.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !hasLogPrefix(sel.Sel.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasLogPrefix reports whether a method name is that of a logging call
+func hasLogPrefix(name string) bool {
+	for _, prefix := range logMethodPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isErrorName reports whether a variable name conventionally holds an error
+func isErrorName(name string) bool {
+	return name == "err" || strings.HasSuffix(name, "Err")
+}
+
+// isNilIdent reports whether expr is the predeclared nil
+func isNilIdent(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "nil"
+}
+
+// isNewErrorCall reports whether expr creates a new error value with
+// errors.New or fmt.Errorf
+func isNewErrorCall(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && ((pkg.Name == "errors" && sel.Sel.Name == "New") || (pkg.Name == "fmt" && sel.Sel.Name == "Errorf"))
+}
+
+// isErrorTextCall reports whether expr is a call such as err.Error()
+func isErrorTextCall(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Error" {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && isErrorName(ident.Name)
+}
+
+// isStringLit reports whether expr is a string literal
+func isStringLit(expr ast.Expr) bool {
+	lit, ok := expr.(*ast.BasicLit)
+	return ok && lit.Kind == token.STRING
+}
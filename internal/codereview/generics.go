@@ -0,0 +1,278 @@
+package codereview
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// maxInlineConstraintTerms is the number of union terms and methods an
+// inline constraint may have before a named constraint reads better
+const maxInlineConstraintTerms = 3
+
+// Constraints suggested for type parameters constrained by any, from the
+// least to the most specific
+const (
+	needsComparable = iota + 1
+	needsOrdered
+	needsNumeric
+)
+
+// anyConstraintMessages are the message keys for each suggested constraint
+var anyConstraintMessages = map[int]string{
+	needsComparable: "generics.any-comparable",
+	needsOrdered:    "generics.any-ordered",
+	needsNumeric:    "generics.any-numeric",
+}
+
+// typeParam is a type parameter and its constraint
+type typeParam struct {
+	name       *ast.Ident
+	constraint ast.Expr
+}
+
+// checkGenerics reviews the type parameters of generic functions, methods
+// and types: inline constraints that are too complex, and any constraints
+// where the code needs comparable, ordered or numeric types
+func (a *Analyzer) checkGenerics(file *ast.File, result *ReviewResult) {
+	// Methods use the constraints declared by their receiver's type
+	typeParams := make(map[string][]typeParam)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok && spec.TypeParams != nil {
+			typeParams[spec.Name.Name] = typeParamList(spec.TypeParams)
+		}
+		return true
+	})
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.TypeSpec:
+			params := typeParamList(node.TypeParams)
+			a.checkConstraints(params, result)
+			a.checkAnyConstraints(params, node.Type, nil, result)
+		case *ast.FuncDecl:
+			params := typeParamList(node.Type.TypeParams)
+			a.checkConstraints(params, result)
+			if node.Recv != nil && len(node.Recv.List) > 0 {
+				params = append(params, receiverTypeParams(node.Recv.List[0].Type, typeParams)...)
+			}
+			a.checkAnyConstraints(params, node, node.Type.Params, result)
+		}
+		return true
+	})
+}
+
+// typeParamList flattens a type parameter list
+func typeParamList(fields *ast.FieldList) []typeParam {
+	if fields == nil {
+		return nil
+	}
+	var params []typeParam
+	for _, field := range fields.List {
+		for _, name := range field.Names {
+			params = append(params, typeParam{name: name, constraint: field.Type})
+		}
+	}
+	return params
+}
+
+// receiverTypeParams binds the type parameter names of a method receiver
+// such as Pair[A, B] to the constraints declared by the receiver's type
+func receiverTypeParams(recv ast.Expr, declared map[string][]typeParam) []typeParam {
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+
+	var indices []ast.Expr
+	switch t := recv.(type) {
+	case *ast.IndexExpr:
+		indices = []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		indices = t.Indices
+	default:
+		return nil
+	}
+
+	constraints := declared[receiverTypeName(recv)]
+	var params []typeParam
+	for i, index := range indices {
+		name, ok := index.(*ast.Ident)
+		if !ok || name.Name == "_" || i >= len(constraints) {
+			continue
+		}
+		params = append(params, typeParam{name: name, constraint: constraints[i].constraint})
+	}
+	return params
+}
+
+// isAnyConstraint reports whether a constraint allows every type
+func isAnyConstraint(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name == "any"
+	case *ast.InterfaceType:
+		return t.Methods == nil || len(t.Methods.List) == 0
+	}
+	return false
+}
+
+// constraintTerms counts the union terms and methods of a constraint
+func constraintTerms(expr ast.Expr) int {
+	switch t := expr.(type) {
+	case *ast.BinaryExpr:
+		if t.Op == token.OR {
+			return constraintTerms(t.X) + constraintTerms(t.Y)
+		}
+	case *ast.ParenExpr:
+		return constraintTerms(t.X)
+	case *ast.InterfaceType:
+		terms := 0
+		for _, field := range t.Methods.List {
+			if len(field.Names) == 0 {
+				terms += constraintTerms(field.Type)
+			} else {
+				terms += len(field.Names)
+			}
+		}
+		return terms
+	}
+	return 1
+}
+
+// checkConstraints reports inline constraints with more terms than
+// maxInlineConstraintTerms
+func (a *Analyzer) checkConstraints(params []typeParam, result *ReviewResult) {
+	for _, param := range params {
+		terms := constraintTerms(param.constraint)
+		if terms <= maxInlineConstraintTerms {
+			continue
+		}
+		result.Issues = append(result.Issues, Issue{
+			Type:       "style",
+			Category:   "generics",
+			Line:       a.getLine(param.constraint.Pos()),
+			Column:     a.getColumn(param.constraint.Pos()),
+			EndLine:    a.getLine(param.constraint.End()),
+			Message:    a.msg("generics.complex-constraint", param.name.Name, terms),
+			Suggestion: a.msg("generics.complex-constraint.fix"),
+			Severity:   "low",
+			Rule:       "complex-constraint",
+		})
+	}
+}
+
+// checkAnyConstraints reports type parameters constrained by any that node
+// uses as map keys, compares, orders or computes with. fnParams holds the
+// function parameters whose types are followed through the body.
+func (a *Analyzer) checkAnyConstraints(params []typeParam, node ast.Node, fnParams *ast.FieldList, result *ReviewResult) {
+	anyParams := make(map[string]bool)
+	for _, param := range params {
+		if isAnyConstraint(param.constraint) {
+			anyParams[param.name.Name] = true
+		}
+	}
+	if len(anyParams) == 0 {
+		return
+	}
+
+	// Variables of a type parameter's type, and slices of it
+	values := make(map[string]string)
+	slices := make(map[string]string)
+	declare := func(names []*ast.Ident, typ ast.Expr) {
+		for _, name := range names {
+			if tp := anyTypeParam(typ, anyParams); tp != "" {
+				values[name.Name] = tp
+			} else if arr, ok := typ.(*ast.ArrayType); ok {
+				if tp := anyTypeParam(arr.Elt, anyParams); tp != "" {
+					slices[name.Name] = tp
+				}
+			}
+		}
+	}
+	if fnParams != nil {
+		for _, field := range fnParams.List {
+			declare(field.Names, field.Type)
+		}
+	}
+
+	needs := make(map[string]int)
+	need := func(expr ast.Expr, level int) {
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			return
+		}
+		if tp := values[ident.Name]; tp != "" && level > needs[tp] {
+			needs[tp] = level
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			declare(n.Names, n.Type)
+		case *ast.RangeStmt:
+			if x, ok := n.X.(*ast.Ident); ok && slices[x.Name] != "" {
+				if value, ok := n.Value.(*ast.Ident); ok {
+					values[value.Name] = slices[x.Name]
+				}
+			}
+		case *ast.MapType:
+			if tp := anyTypeParam(n.Key, anyParams); tp != "" && needs[tp] < needsComparable {
+				needs[tp] = needsComparable
+			}
+		case *ast.BinaryExpr:
+			if level := operatorNeeds(n.Op); level > 0 {
+				need(n.X, level)
+				need(n.Y, level)
+			}
+		case *ast.AssignStmt:
+			if level := operatorNeeds(n.Tok); level > 0 {
+				for _, lhs := range n.Lhs {
+					need(lhs, level)
+				}
+			}
+		}
+		return true
+	})
+
+	for _, param := range params {
+		level := needs[param.name.Name]
+		if level == 0 || !anyParams[param.name.Name] {
+			continue
+		}
+		key := anyConstraintMessages[level]
+		result.Issues = append(result.Issues, Issue{
+			Type:       "warning",
+			Category:   "generics",
+			Line:       a.getLine(param.name.Pos()),
+			Column:     a.getColumn(param.name.Pos()),
+			EndLine:    a.getLine(param.name.End()),
+			Message:    a.msg(key, param.name.Name),
+			Suggestion: a.msg(key+".fix", param.name.Name),
+			Severity:   "medium",
+			Rule:       "any-constraint",
+		})
+	}
+}
+
+// anyTypeParam returns the name of the any-constrained type parameter typ
+// refers to, or ""
+func anyTypeParam(typ ast.Expr, anyParams map[string]bool) string {
+	if ident, ok := typ.(*ast.Ident); ok && anyParams[ident.Name] {
+		return ident.Name
+	}
+	return ""
+}
+
+// operatorNeeds returns the constraint an operator requires of its operands
+func operatorNeeds(op token.Token) int {
+	switch op {
+	case token.EQL, token.NEQ:
+		return needsComparable
+	case token.LSS, token.LEQ, token.GTR, token.GEQ:
+		return needsOrdered
+	case token.ADD, token.SUB, token.MUL, token.QUO, token.REM,
+		token.ADD_ASSIGN, token.SUB_ASSIGN, token.MUL_ASSIGN, token.QUO_ASSIGN, token.REM_ASSIGN:
+		return needsNumeric
+	}
+	return 0
+}
@@ -0,0 +1,156 @@
+package codereview
+
+import (
+	"go/parser"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeCode_Generics(t *testing.T) {
+	code := `package lib
+
+// Contains reports whether s contains v
+func Contains[T any](s []T, v T) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Sum adds the values
+func Sum[T any](xs []T) T {
+	var total T
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+
+// Max returns the larger value
+func Max[T interface{}](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Keys returns the keys of m
+func Keys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Set is a set of values
+type Set[T any] map[T]struct{}
+
+// Pair holds two values
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Same reports whether both values are equal
+func (p Pair[A, B]) Same(other Pair[A, B]) bool {
+	return p.First == other.First
+}
+
+// Scale multiplies a number
+func Scale[T ~int | ~int8 | ~int16 | ~int32 | ~int64](v T, by T) T {
+	return v * by
+}
+`
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[int]string{}
+	for _, issue := range result.Issues {
+		if issue.Category == "generics" {
+			got[issue.Line] = issue.Rule + ": " + issue.Message
+		}
+	}
+
+	want := map[int]string{
+		4:  "any-constraint: Type parameter T is constrained by any but compared or used as a map key",
+		14: "any-constraint: Type parameter T is constrained by any but used in arithmetic",
+		23: "any-constraint: Type parameter T is constrained by any but ordered with <, <=, > or >=",
+		40: "any-constraint: Type parameter T is constrained by any but compared or used as a map key",
+		54: "complex-constraint: Type parameter T has an inline constraint with 5 terms",
+	}
+	for line, msg := range want {
+		if got[line] != msg {
+			t.Errorf("line %d: expected %q, got %q", line, msg, got[line])
+		}
+	}
+	for line, msg := range got {
+		if _, ok := want[line]; !ok {
+			t.Errorf("unexpected generics issue on line %d: %s", line, msg)
+		}
+	}
+}
+
+func TestAnalyzeCode_GenericsMethodReceiver(t *testing.T) {
+	code := `package lib
+
+// Box holds a value
+type Box[T any] struct {
+	value T
+}
+
+// Less reports whether the box holds a smaller value
+func (b *Box[T]) Less(other T) bool {
+	return b.value < other
+}
+
+// Equal reports whether the box holds v
+func (b *Box[T]) Equal(v T) bool {
+	return v == v
+}
+`
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[int]string{}
+	for _, issue := range result.Issues {
+		if issue.Rule == "any-constraint" {
+			got[issue.Line] = issue.Message
+		}
+	}
+	if len(got) != 2 || !strings.Contains(got[9], "ordered") || !strings.Contains(got[14], "compared") {
+		t.Errorf("expected suggestions on both method receivers, got %v", got)
+	}
+}
+
+func TestConstraintTerms(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want int
+	}{
+		{"any", "any", 1},
+		{"union", "~int | ~string | float64", 3},
+		{"interface", "interface{ ~int | ~int64; String() string }", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := parser.ParseExpr(tt.code)
+			if err != nil {
+				t.Fatalf("failed to parse %q: %v", tt.code, err)
+			}
+			if got := constraintTerms(expr); got != tt.want {
+				t.Errorf("expected %d terms, got %d", tt.want, got)
+			}
+		})
+	}
+}
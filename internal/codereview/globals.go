@@ -0,0 +1,308 @@
+package codereview
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// maxInitStatements is the number of statements an init function may have
+// before it counts as heavy package initialization
+const maxInitStatements = 10
+
+// ioPackages are packages whose functions reach the file system, network,
+// processes or environment, which init functions should not depend on
+var ioPackages = map[string]bool{
+	"os":      true,
+	"ioutil":  true,
+	"net":     true,
+	"sql":     true,
+	"exec":    true,
+	"syscall": true,
+}
+
+// checkGlobalState reviews package initialization and package-level state:
+// init functions doing heavy work or I/O, variables written from several
+// functions, and sync.Once guarding package-level values
+func (a *Analyzer) checkGlobalState(file *ast.File, result *ReviewResult) {
+	globals := packageVars(file)
+	onces := make(map[string]bool)
+	for name, spec := range globals {
+		if isSyncOnce(spec.Type) {
+			onces[name] = true
+		}
+	}
+
+	writers := make(map[string][]string)
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil {
+			continue
+		}
+		if fd.Recv == nil && fd.Name.Name == "init" {
+			a.checkInit(fd, result)
+		}
+
+		locals := localNames(fd)
+		for name := range writtenGlobals(fd.Body, globals, locals) {
+			writers[name] = append(writers[name], funcName(fd))
+		}
+		a.checkSyncOnce(fd.Body, globals, onces, locals, result)
+	}
+
+	names := make([]string, 0, len(writers))
+	for name := range writers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		funcs := writers[name]
+		if len(funcs) < 2 || onces[name] {
+			continue
+		}
+		ident := globals[name].Names[0]
+		for _, n := range globals[name].Names {
+			if n.Name == name {
+				ident = n
+			}
+		}
+		result.Issues = append(result.Issues, Issue{
+			Type:       "warning",
+			Category:   "testability",
+			Line:       a.getLine(ident.Pos()),
+			Column:     a.getColumn(ident.Pos()),
+			EndLine:    a.getLine(ident.End()),
+			Message:    a.msg("globals.mutable", name, len(funcs), strings.Join(funcs, ", ")),
+			Suggestion: a.msg("globals.mutable.fix"),
+			Severity:   "medium",
+			Rule:       "mutable-global",
+		})
+	}
+}
+
+// checkInit reports an init function with more than maxInitStatements
+// statements, or one calling into I/O packages
+func (a *Analyzer) checkInit(fd *ast.FuncDecl, result *ReviewResult) {
+	statements := 0
+	var calls []string
+	seen := make(map[string]bool)
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Deferred work is not run by init itself
+			return false
+		case *ast.BlockStmt:
+		case ast.Stmt:
+			statements++
+		case *ast.CallExpr:
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok {
+				if pkg, ok := sel.X.(*ast.Ident); ok && ioPackages[pkg.Name] {
+					call := pkg.Name + "." + sel.Sel.Name
+					if !seen[call] {
+						seen[call] = true
+						calls = append(calls, call)
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	issue := func(message string) {
+		result.Issues = append(result.Issues, Issue{
+			Type:       "warning",
+			Category:   "testability",
+			Line:       a.getLine(fd.Pos()),
+			Column:     a.getColumn(fd.Pos()),
+			EndLine:    a.getLine(fd.End()),
+			Message:    message,
+			Suggestion: a.msg("globals.heavy-init.fix"),
+			Severity:   "medium",
+			Rule:       "heavy-init",
+		})
+	}
+	switch {
+	case len(calls) > 0:
+		issue(a.msg("globals.init-io", strings.Join(calls, ", ")))
+	case statements > maxInitStatements:
+		issue(a.msg("globals.heavy-init", statements))
+	}
+}
+
+// checkSyncOnce reports calls to Do on a package-level sync.Once whose
+// function assigns package-level variables, i.e. a hand-written lazy
+// initializer of package state
+func (a *Analyzer) checkSyncOnce(body *ast.BlockStmt, globals map[string]*ast.ValueSpec, onces, locals map[string]bool, result *ReviewResult) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Do" {
+			return true
+		}
+		once, ok := sel.X.(*ast.Ident)
+		if !ok || !onces[once.Name] || locals[once.Name] {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+
+		var assigned []string
+		for name := range writtenGlobals(lit.Body, globals, locals) {
+			assigned = append(assigned, name)
+		}
+		if len(assigned) == 0 {
+			return true
+		}
+		sort.Strings(assigned)
+		result.Issues = append(result.Issues, Issue{
+			Type:       "suggestion",
+			Category:   "testability",
+			Line:       a.getLine(call.Pos()),
+			Column:     a.getColumn(call.Pos()),
+			EndLine:    a.getLine(call.End()),
+			Message:    a.msg("globals.sync-once", once.Name, strings.Join(assigned, ", ")),
+			Suggestion: a.msg("globals.sync-once.fix"),
+			Severity:   "low",
+			Rule:       "sync-once-global",
+		})
+		return true
+	})
+}
+
+// packageVars returns the package-level variables of file by name
+func packageVars(file *ast.File) map[string]*ast.ValueSpec {
+	vars := make(map[string]*ast.ValueSpec)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for _, name := range vs.Names {
+				if name.Name != "_" {
+					vars[name.Name] = vs
+				}
+			}
+		}
+	}
+	return vars
+}
+
+// isSyncOnce reports whether typ is sync.Once
+func isSyncOnce(typ ast.Expr) bool {
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Once" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "sync"
+}
+
+// localNames returns the names fd declares itself, which shadow
+// package-level variables of the same name
+func localNames(fd *ast.FuncDecl) map[string]bool {
+	locals := make(map[string]bool)
+	declare := func(fl *ast.FieldList) {
+		if fl == nil {
+			return
+		}
+		for _, field := range fl.List {
+			for _, name := range field.Names {
+				locals[name.Name] = true
+			}
+		}
+	}
+	declare(fd.Recv)
+	declare(fd.Type.Params)
+	declare(fd.Type.Results)
+
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if node.Tok == token.DEFINE {
+				for _, lhs := range node.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						locals[ident.Name] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, name := range node.Names {
+				locals[name.Name] = true
+			}
+		case *ast.RangeStmt:
+			if node.Tok == token.DEFINE {
+				for _, expr := range []ast.Expr{node.Key, node.Value} {
+					if ident, ok := expr.(*ast.Ident); ok {
+						locals[ident.Name] = true
+					}
+				}
+			}
+		case *ast.FuncLit:
+			declare(node.Type.Params)
+			declare(node.Type.Results)
+		}
+		return true
+	})
+	return locals
+}
+
+// writtenGlobals returns the package-level variables node assigns,
+// increments or writes elements or fields of, ignoring shadowed names
+func writtenGlobals(node ast.Node, globals map[string]*ast.ValueSpec, locals map[string]bool) map[string]bool {
+	written := make(map[string]bool)
+	write := func(expr ast.Expr) {
+		// Writes to x.f, x[i] and *x change the state reachable from x
+		for {
+			switch e := expr.(type) {
+			case *ast.SelectorExpr:
+				expr = e.X
+				continue
+			case *ast.IndexExpr:
+				expr = e.X
+				continue
+			case *ast.StarExpr:
+				expr = e.X
+				continue
+			case *ast.ParenExpr:
+				expr = e.X
+				continue
+			case *ast.Ident:
+				if globals[e.Name] != nil && !locals[e.Name] {
+					written[e.Name] = true
+				}
+			}
+			return
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			if stmt.Tok != token.DEFINE {
+				for _, lhs := range stmt.Lhs {
+					write(lhs)
+				}
+			}
+		case *ast.IncDecStmt:
+			write(stmt.X)
+		}
+		return true
+	})
+	return written
+}
+
+// funcName returns the name of a function or method, e.g. Store.Get
+func funcName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return fd.Name.Name
+	}
+	return receiverTypeName(fd.Recv.List[0].Type) + "." + fd.Name.Name
+}
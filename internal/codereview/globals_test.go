@@ -0,0 +1,123 @@
+package codereview
+
+import (
+	"testing"
+)
+
+func TestAnalyzeCode_GlobalState(t *testing.T) {
+	code := `package app
+
+import (
+	"database/sql"
+	"os"
+	"sync"
+)
+
+var (
+	db       *sql.DB
+	counters = map[string]int{}
+	hits     int
+	limit    = 10
+	once     sync.Once
+	config   map[string]string
+)
+
+func init() {
+	db, _ = sql.Open("postgres", os.Getenv("DSN"))
+}
+
+func Record(name string) {
+	counters[name]++
+	hits++
+}
+
+func Reset() {
+	counters = map[string]int{}
+	hits = 0
+}
+
+func Limit(limit int) int {
+	limit = limit * 2
+	return limit
+}
+
+func Config() map[string]string {
+	once.Do(func() {
+		config = map[string]string{"env": "prod"}
+	})
+	return config
+}
+
+func setupDB() {
+	db = nil
+}
+`
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[int]string{}
+	for _, issue := range result.Issues {
+		switch issue.Rule {
+		case "heavy-init", "mutable-global", "sync-once-global":
+			got[issue.Line] = issue.Rule + ": " + issue.Message
+		}
+	}
+
+	want := map[int]string{
+		10: "mutable-global: Package-level variable db is written from 2 functions (init, setupDB)",
+		11: "mutable-global: Package-level variable counters is written from 2 functions (Record, Reset)",
+		12: "mutable-global: Package-level variable hits is written from 2 functions (Record, Reset)",
+		18: "heavy-init: init() calls sql.Open, os.Getenv; I/O during package initialization makes every test depend on the environment",
+		38: "sync-once-global: once.Do lazily initializes package-level config",
+	}
+	for line, msg := range want {
+		if got[line] != msg {
+			t.Errorf("line %d: expected %q, got %q", line, msg, got[line])
+		}
+	}
+	for line, msg := range got {
+		if _, ok := want[line]; !ok {
+			t.Errorf("unexpected global state issue on line %d: %s", line, msg)
+		}
+	}
+}
+
+func TestAnalyzeCode_HeavyInit(t *testing.T) {
+	code := `package app
+
+var registry = map[string]int{}
+
+func init() {
+	for i := 0; i < 3; i++ {
+		registry["a"] = i
+		registry["b"] = i
+		registry["c"] = i
+		registry["d"] = i
+		registry["e"] = i
+		registry["f"] = i
+		registry["g"] = i
+		registry["h"] = i
+		registry["i"] = i
+	}
+}
+`
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var messages []string
+	for _, issue := range result.Issues {
+		if issue.Rule == "heavy-init" {
+			messages = append(messages, issue.Message)
+		}
+	}
+	want := "init() runs 12 statements; heavy package initialization runs in every test binary and cannot be configured or skipped"
+	if len(messages) != 1 || messages[0] != want {
+		t.Errorf("expected %q, got %v", want, messages)
+	}
+}
@@ -2,11 +2,22 @@ package codereview
 
 import (
 	"bufio"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 )
 
+// DefaultGuidelinesMaxSize is the number of bytes read from a guidelines
+// file when no limit is configured
+const DefaultGuidelinesMaxSize = 1 << 20
+
+// guidelinesExtensions are the file types accepted as guidelines files
+var guidelinesExtensions = []string{".md", ".txt"}
+
 // GuidelinesParser parses markdown guidelines
 type GuidelinesParser struct{}
 
@@ -17,14 +28,27 @@ func NewGuidelinesParser() *GuidelinesParser {
 
 // ParseFile parses guidelines from a markdown file
 func (p *GuidelinesParser) ParseFile(filePath string) ([]string, error) {
+	guidelines, _, err := p.ParseFileLimit(filePath, 0)
+	return guidelines, err
+}
+
+// ParseFileLimit parses guidelines from at most limit bytes of a markdown
+// file and reports whether the rest of the file was skipped. A limit of
+// zero reads the whole file.
+func (p *GuidelinesParser) ParseFileLimit(filePath string, limit int64) ([]string, bool, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer func() { _ = file.Close() }()
 
+	var r io.Reader = file
+	if limit > 0 {
+		r = io.LimitReader(file, limit)
+	}
+
 	var guidelines []string
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -32,8 +56,34 @@ func (p *GuidelinesParser) ParseFile(filePath string) ([]string, error) {
 			guidelines = append(guidelines, guideline)
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, false, err
+	}
 
-	return guidelines, scanner.Err()
+	// The limited reader stops exactly at the limit, so any byte left in
+	// the file was skipped
+	truncated := false
+	if limit > 0 {
+		n, _ := file.Read(make([]byte, 1))
+		truncated = n > 0
+	}
+	return guidelines, truncated, nil
+}
+
+// checkGuidelinesFile rejects guidelines files that are missing, of another
+// type than markdown or text, or not regular files such as devices or pipes
+func checkGuidelinesFile(path string) error {
+	if !slices.Contains(guidelinesExtensions, strings.ToLower(filepath.Ext(path))) {
+		return fmt.Errorf("guidelines file must be a .md or .txt file: %s", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("guidelines file not found: %s", path)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("guidelines file is not a regular file: %s", path)
+	}
+	return nil
 }
 
 // ParseContent parses guidelines from markdown content
@@ -0,0 +1,142 @@
+package codereview
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// Source comments suppressing findings. "//nolint:mcp-review" suppresses
+// every rule; "//mcp:ignore rule-a,rule-b" suppresses the listed rules, or
+// every rule when none is listed. Text after a further "//" is a reason.
+const (
+	nolintPrefix = "//nolint:"
+	nolintLinter = "mcp-review"
+	ignorePrefix = "//mcp:ignore"
+)
+
+// ignoreScope suppresses rules on the lines from through to. A nil rules
+// map suppresses every rule.
+type ignoreScope struct {
+	from, to int
+	rules    map[string]bool
+}
+
+// matches reports whether the scope suppresses issue
+func (s ignoreScope) matches(issue Issue) bool {
+	if issue.Line < s.from || issue.Line > s.to {
+		return false
+	}
+	return s.rules == nil || s.rules[issue.Rule]
+}
+
+// parseIgnoreDirective returns the rules suppressed by a comment, or ok
+// false if the comment is not a directive
+func parseIgnoreDirective(text string) (rules map[string]bool, ok bool) {
+	if reason := strings.Index(text[2:], "//"); reason >= 0 {
+		text = text[:reason+2]
+	}
+	text = strings.TrimSpace(text)
+
+	if linters, found := strings.CutPrefix(text, nolintPrefix); found {
+		for _, linter := range strings.Split(linters, ",") {
+			if strings.TrimSpace(linter) == nolintLinter {
+				return nil, true
+			}
+		}
+		return nil, false
+	}
+
+	names, found := strings.CutPrefix(text, ignorePrefix)
+	if !found || (names != "" && names[0] != ' ' && names[0] != '\t') {
+		return nil, false
+	}
+	for _, name := range strings.FieldsFunc(names, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		if rules == nil {
+			rules = make(map[string]bool)
+		}
+		rules[name] = true
+	}
+	return rules, true
+}
+
+// collectIgnores returns the scopes of the ignore directives in file. A
+// directive in the doc comment of a declaration, spec or field covers the
+// whole node; any other directive covers its own line and the next one.
+func (a *Analyzer) collectIgnores(file *ast.File) []ignoreScope {
+	docNodes := make(map[*ast.CommentGroup]ast.Node)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			docNodes[node.Doc] = node
+		case *ast.GenDecl:
+			docNodes[node.Doc] = node
+		case *ast.TypeSpec:
+			docNodes[node.Doc] = node
+		case *ast.ValueSpec:
+			docNodes[node.Doc] = node
+		case *ast.Field:
+			docNodes[node.Doc] = node
+		}
+		return true
+	})
+	delete(docNodes, nil)
+
+	var scopes []ignoreScope
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			rules, ok := parseIgnoreDirective(comment.Text)
+			if !ok {
+				continue
+			}
+
+			line := a.getLine(comment.Pos())
+			scope := ignoreScope{from: line, to: line + 1, rules: rules}
+			if node, ok := docNodes[group]; ok {
+				scope.from = a.getLine(node.Pos())
+				scope.to = a.getLine(node.End())
+			}
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// suppress removes the issues covered by an ignore directive, counting
+// them by rule, and returns the remaining issues
+func (a *Analyzer) suppress(issues []Issue) []Issue {
+	if len(a.ignores) == 0 {
+		return issues
+	}
+
+	kept := issues[:0]
+	for _, issue := range issues {
+		if a.ignored(issue) {
+			if a.suppressed == nil {
+				a.suppressed = make(map[string]int)
+			}
+			a.suppressed[issue.Rule]++
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	return kept
+}
+
+// ignored reports whether an ignore directive covers issue
+func (a *Analyzer) ignored(issue Issue) bool {
+	for _, scope := range a.ignores {
+		if scope.matches(issue) {
+			return true
+		}
+	}
+	return false
+}
+
+// suppressedCount returns the number of issues removed by ignore directives
+func suppressedCount(suppressed map[string]int) int {
+	total := 0
+	for _, n := range suppressed {
+		total += n
+	}
+	return total
+}
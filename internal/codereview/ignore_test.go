@@ -0,0 +1,132 @@
+package codereview
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseIgnoreDirective(t *testing.T) {
+	tests := []struct {
+		text  string
+		ok    bool
+		rules []string
+		all   bool
+	}{
+		{text: "//nolint:mcp-review", ok: true, all: true},
+		{text: "//nolint:errcheck,mcp-review // generated", ok: true, all: true},
+		{text: "//nolint:errcheck", ok: false},
+		{text: "//mcp:ignore", ok: true, all: true},
+		{text: "//mcp:ignore camel-case", ok: true, rules: []string{"camel-case"}},
+		{text: "//mcp:ignore camel-case,parameter-count // legacy API", ok: true, rules: []string{"camel-case", "parameter-count"}},
+		{text: "//mcp:ignored", ok: false},
+		{text: "// mcp:ignore is documented here", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			rules, ok := parseIgnoreDirective(tt.text)
+			if ok != tt.ok {
+				t.Fatalf("expected ok %v, got %v", tt.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if tt.all {
+				if rules != nil {
+					t.Errorf("expected every rule to be suppressed, got %v", rules)
+				}
+				return
+			}
+			if len(rules) != len(tt.rules) {
+				t.Fatalf("expected rules %v, got %v", tt.rules, rules)
+			}
+			for _, rule := range tt.rules {
+				if !rules[rule] {
+					t.Errorf("expected rule %s to be suppressed, got %v", rule, rules)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeCode_IgnoreDirectives(t *testing.T) {
+	code := `package lib
+
+// Run_fast runs quickly
+//
+//mcp:ignore camel-case
+func Run_fast() {}
+
+// Run_slow runs slowly
+func Run_slow() {}
+
+// Run_all runs everything
+//nolint:mcp-review // kept for compatibility
+func Run_all(a int, b int, c int, d int, e int, f int) {}
+`
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, issue := range result.Issues {
+		if issue.Line >= 5 && issue.Line <= 6 && issue.Rule == "camel-case" {
+			t.Errorf("expected camel-case on Run_fast to be suppressed, got %+v", issue)
+		}
+		if issue.Line >= 12 {
+			t.Errorf("expected every issue on Run_all to be suppressed, got %+v", issue)
+		}
+	}
+
+	found := false
+	for _, issue := range result.Issues {
+		if issue.Rule == "camel-case" && issue.Line == 9 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected camel-case on Run_slow to be reported, got %+v", result.Issues)
+	}
+
+	if result.Suppressed["camel-case"] != 2 || result.Suppressed["parameter-count"] != 1 {
+		t.Errorf("expected suppressed counts by rule, got %v", result.Suppressed)
+	}
+	if !strings.Contains(result.Summary, "suppressed by ignore directives") {
+		t.Errorf("expected the summary to report suppressed findings, got %q", result.Summary)
+	}
+}
+
+func TestAnalyzeCode_IgnoreNextLine(t *testing.T) {
+	code := `package lib
+
+import "os"
+
+// Clean writes the marker files
+func Clean() {
+	//mcp:ignore error-handling
+	n, _ := os.Stdout.WriteString("x")
+	m, _ := os.Stdout.WriteString("y")
+	_, _ = n, m
+}
+`
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := map[int]bool{}
+	for _, issue := range result.Issues {
+		lines[issue.Line] = true
+	}
+	if lines[8] {
+		t.Errorf("expected issues on the line after the directive to be suppressed, got %+v", result.Issues)
+	}
+	if suppressedCount(result.Suppressed) == 0 {
+		t.Errorf("expected suppressed findings, got %+v", result.Issues)
+	}
+	if !lines[9] {
+		t.Errorf("expected issues on later lines to be reported, got %+v", result.Issues)
+	}
+}
@@ -0,0 +1,190 @@
+package codereview
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// printFuncs are the fmt and log functions that write straight to the
+// process's standard streams
+var printFuncs = map[string]map[string]bool{
+	"fmt": {"Print": true, "Printf": true, "Println": true},
+	"log": {"Print": true, "Printf": true, "Println": true},
+}
+
+// checkLogging reviews logging and error reporting style: printing from
+// library packages, error strings that do not follow Go conventions, and
+// errors that are both logged and returned
+func (a *Analyzer) checkLogging(file *ast.File, result *ReviewResult) {
+	imports := importNames(file)
+	if file.Name.Name != "main" {
+		a.checkLibraryPrints(file, imports, result)
+	}
+	a.checkErrorStrings(file, imports, result)
+	a.checkLogAndReturn(file, result)
+}
+
+// checkLibraryPrints reports fmt.Print and log.Print calls in non-main
+// packages, which write to output the importing program does not control
+func (a *Analyzer) checkLibraryPrints(file *ast.File, imports map[string]string, result *ReviewResult) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		for path, funcs := range printFuncs {
+			name, imported := imports[path]
+			if !imported {
+				continue
+			}
+			for fn := range funcs {
+				if isPkgSelector(call.Fun, name, fn) {
+					result.Issues = append(result.Issues, Issue{
+						Type:       "suggestion",
+						Category:   "logging",
+						Line:       a.getLine(call.Pos()),
+						Column:     a.getColumn(call.Pos()),
+						EndLine:    a.getLine(call.End()),
+						Message:    a.msg("logging.library-print", path+"."+fn, file.Name.Name),
+						Suggestion: a.msg("logging.library-print.fix"),
+						Severity:   "medium",
+						Rule:       "library-print",
+					})
+				}
+			}
+		}
+		return true
+	})
+}
+
+// checkErrorStrings reports errors.New and fmt.Errorf messages that start
+// with a capital letter or end with punctuation, as they are usually
+// wrapped into other messages
+func (a *Analyzer) checkErrorStrings(file *ast.File, imports map[string]string, result *ReviewResult) {
+	errorsName, fmtName := imports["errors"], imports["fmt"]
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		if !(errorsName != "" && isPkgSelector(call.Fun, errorsName, "New")) &&
+			!(fmtName != "" && isPkgSelector(call.Fun, fmtName, "Errorf")) {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		text, err := strconv.Unquote(lit.Value)
+		if err != nil || text == "" {
+			return true
+		}
+
+		var key string
+		switch {
+		case startsWithCapital(text):
+			key = "logging.error-capitalized"
+		case strings.ContainsRune(".!?:\n", lastRune(text)):
+			key = "logging.error-punctuation"
+		default:
+			return true
+		}
+		result.Issues = append(result.Issues, Issue{
+			Type:       "suggestion",
+			Category:   "logging",
+			Line:       a.getLine(lit.Pos()),
+			Column:     a.getColumn(lit.Pos()),
+			EndLine:    a.getLine(lit.End()),
+			Message:    a.msg(key, text),
+			Suggestion: a.msg("logging.error-string.fix"),
+			Severity:   "low",
+			Rule:       "error-string-style",
+		})
+		return true
+	})
+}
+
+// checkLogAndReturn reports if-err blocks that log an error and then return
+// it, so the same failure is reported at every level it passes through
+func (a *Analyzer) checkLogAndReturn(file *ast.File, result *ReviewResult) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		ifStmt, ok := n.(*ast.IfStmt)
+		if !ok {
+			return true
+		}
+		name, ok := errNotNilCheck(ifStmt.Cond)
+		if !ok {
+			return true
+		}
+
+		var logged ast.Node
+		for _, stmt := range ifStmt.Body.List {
+			if logged == nil && isLogCallWith(stmt, name) {
+				logged = stmt
+				continue
+			}
+			ret, ok := stmt.(*ast.ReturnStmt)
+			if logged == nil || !ok || !usesIdent(ret, name) {
+				continue
+			}
+			result.Issues = append(result.Issues, Issue{
+				Type:       "warning",
+				Category:   "logging",
+				Line:       a.getLine(logged.Pos()),
+				Column:     a.getColumn(logged.Pos()),
+				EndLine:    a.getLine(ret.End()),
+				Message:    a.msg("logging.log-and-return", name),
+				Suggestion: a.msg("logging.log-and-return.fix"),
+				Severity:   "medium",
+				Rule:       "log-and-return",
+			})
+			break
+		}
+		return true
+	})
+}
+
+// isLogCallWith reports whether stmt is a logging call that is passed the
+// error variable name
+func isLogCallWith(stmt ast.Stmt, name string) bool {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !hasLogPrefix(sel.Sel.Name) {
+		return false
+	}
+	for _, arg := range call.Args {
+		if usesIdent(arg, name) {
+			return true
+		}
+	}
+	// Chained loggers such as log.Error().Err(err).Msg("failed")
+	return usesIdent(sel.X, name)
+}
+
+// startsWithCapital reports whether text starts with a capitalized word.
+// Acronyms and identifiers such as "HTTP" or "ID" keep their case.
+func startsWithCapital(text string) bool {
+	first, size := utf8.DecodeRuneInString(text)
+	if !unicode.IsUpper(first) {
+		return false
+	}
+	second, _ := utf8.DecodeRuneInString(text[size:])
+	return unicode.IsLower(second)
+}
+
+// lastRune returns the final rune of text
+func lastRune(text string) rune {
+	r, _ := utf8.DecodeLastRuneInString(text)
+	return r
+}
@@ -0,0 +1,93 @@
+package codereview
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAnalyzeCode_Logging(t *testing.T) {
+	code := `package store
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+var ErrMissing = errors.New("Missing record")
+
+func Load(id string) error {
+	fmt.Println("loading", id)
+	if err := fetch(id); err != nil {
+		log.Printf("fetch %s: %v", id, err)
+		return fmt.Errorf("load %s: %w", id, err)
+	}
+	if id == "" {
+		return errors.New("empty id.")
+	}
+	return fmt.Errorf("HTTP status %d", 500)
+}
+
+func fetch(id string) error {
+	if err := check(id); err != nil {
+		log.Printf("check failed: %v", err)
+	}
+	return nil
+}
+
+func check(id string) error {
+	return fmt.Errorf("ID %q unknown", id)
+}
+`
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[string]bool{}
+	for _, issue := range result.Issues {
+		if issue.Category == "logging" {
+			got[fmt.Sprintf("%d %s: %s", issue.Line, issue.Rule, issue.Message)] = true
+		}
+	}
+
+	want := []string{
+		`9 error-string-style: Error string "Missing record" starts with a capital letter`,
+		"12 library-print: fmt.Println in library package 'store' writes to output the importing program does not control",
+		"14 library-print: log.Printf in library package 'store' writes to output the importing program does not control",
+		"14 log-and-return: Error 'err' is logged and returned, so it is reported more than once",
+		`18 error-string-style: Error string "empty id." ends with punctuation or a newline`,
+		"25 library-print: log.Printf in library package 'store' writes to output the importing program does not control",
+	}
+	for _, issue := range want {
+		if !got[issue] {
+			t.Errorf("expected issue %q", issue)
+		}
+		delete(got, issue)
+	}
+	for issue := range got {
+		t.Errorf("unexpected logging issue %q", issue)
+	}
+}
+
+func TestAnalyzeCode_LoggingInMain(t *testing.T) {
+	code := `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hello")
+}
+`
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, issue := range result.Issues {
+		if issue.Rule == "library-print" {
+			t.Errorf("unexpected library-print issue in package main: %s", issue.Message)
+		}
+	}
+}
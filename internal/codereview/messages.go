@@ -0,0 +1,685 @@
+package codereview
+
+import "mcp-go-assistant/internal/i18n"
+
+// messages is the catalog used for issue messages, suggestions and summaries.
+// Rules reference message keys and register their text for each language here.
+var messages = i18n.NewCatalog()
+
+func init() {
+	messages.Register(i18n.English, englishMessages)
+	messages.Register(i18n.Japanese, japaneseMessages)
+	messages.Register(i18n.Spanish, spanishMessages)
+}
+
+var englishMessages = map[string]string{
+	// Summary
+	"summary.parse-failed": "Code parsing failed",
+	"summary.clean":        "Code looks good! No major issues found.",
+	"summary.issues":       "Found %d issues and %d suggestions. Overall score: %d/100",
+	"summary.suppressed":   "%d findings suppressed by ignore directives.",
+	"guidelines.truncated": "Guidelines file %s is larger than %d bytes; only guidelines before the limit were applied.",
+
+	// Syntax
+	"syntax.parse-failed": "Failed to parse Go code: %s",
+
+	// Naming
+	"naming.exported-function": "Exported function should start with capital letter",
+	"naming.exported-type":     "Exported type should start with capital letter",
+	"naming.exported-variable": "Exported variable should start with capital letter",
+	"naming.rename":            "Rename to %s",
+	"naming.camel-case":        "Function names should use camelCase, not underscores",
+	"naming.camel-case.fix":    "Use camelCase naming convention",
+
+	// Structure
+	"structure.function-length":       "Function is too long (>%d lines). Consider breaking it down",
+	"structure.function-length.fix":   "Split into smaller, focused functions",
+	"structure.parameter-count":       "Function has too many parameters (>%d)",
+	"structure.parameter-count.fix":   "Consider using a struct to group related parameters",
+	"structure.struct-size":           "Struct has many fields (>%d). Consider if it's doing too much",
+	"structure.struct-size.fix":       "Consider breaking into smaller structs",
+	"structure.many-functions":        "File contains many functions. Consider splitting into multiple files",
+	"structure.many-functions.impact": "Improved maintainability and organization",
+
+	// Documentation
+	"docs.exported-function":     "Exported function lacks documentation comment",
+	"docs.exported-function.fix": "Add a comment starting with function name",
+	"docs.exported-type":         "Exported type lacks documentation comment",
+	"docs.exported-type.fix":     "Add a comment starting with type name",
+
+	// Error handling
+	"error-handling.ignored":          "Error is being ignored",
+	"error-handling.ignored.fix":      "Handle error appropriately",
+	"error-handling.swallowed":        "Error '%s' is logged but execution continues",
+	"error-handling.swallowed.fix":    "Return the error, wrapped with context, so callers can react to the failure",
+	"error-handling.overwritten":      "Error '%s' is overwritten on line %d before it is checked",
+	"error-handling.overwritten.fix":  "Check the error before assigning the variable again",
+	"error-handling.shadowed":         "'%s' declared here shadows the error from line %d, which is used after this block",
+	"error-handling.shadowed.fix":     "Assign with = instead of := so the outer error is updated",
+	"error-handling.fresh-comparison": "Comparison with a newly created error is never equal",
+	"error-handling.text-comparison":  "Error is compared by its message text",
+	"error-handling.comparison.fix":   "Compare against a sentinel error with errors.Is, or use errors.As for error types",
+
+	// Performance
+	"performance.string-concat":     "String concatenation in loop can be inefficient",
+	"performance.string-concat.fix": "Consider using strings.Builder or bytes.Buffer",
+
+	// Security
+	"security.unsafe":     "Use of unsafe package should be carefully reviewed",
+	"security.unsafe.fix": "Ensure unsafe operations are necessary and correct",
+
+	// Testability
+	"testability.globals":         "Global variables can make testing difficult",
+	"testability.globals.example": "Consider dependency injection or configuration structs",
+	"testability.globals.impact":  "Improved testability and maintainability",
+
+	// Package initialization and global state
+	"globals.heavy-init":     "init() runs %d statements; heavy package initialization runs in every test binary and cannot be configured or skipped",
+	"globals.init-io":        "init() calls %s; I/O during package initialization makes every test depend on the environment",
+	"globals.heavy-init.fix": "Move the logic into an explicit constructor or setup function called from main, so tests can run it with their own inputs",
+	"globals.mutable":        "Package-level variable %s is written from %d functions (%s)",
+	"globals.mutable.fix":    "Keep the state in a struct passed to the functions that need it, so tests get isolated instances and can run in parallel",
+	"globals.sync-once":      "%s.Do lazily initializes package-level %s",
+	"globals.sync-once.fix":  "Use a lazy constructor such as sync.OnceValue, or build the value explicitly and inject it so tests can substitute it",
+
+	// Deadlines and timeouts
+	"deadlines.http-client":         "http.Client has no Timeout; a slow or unresponsive server can block the request forever",
+	"deadlines.http-client.fix":     "Set Timeout on the client, or give every request a context with a deadline",
+	"deadlines.cancel-discarded":    "The cancel function returned by context.%s is discarded; the context is only released when the timer fires",
+	"deadlines.cancel-not-deferred": "%s from context.%s is never deferred; any return before it is called leaks the context and its timer",
+	"deadlines.cancel.fix":          "Call defer cancel() right after creating the context",
+	"deadlines.time-after-loop":     "time.After in a select inside a loop creates a new timer on every iteration",
+	"deadlines.time-after-loop.fix": "Create one time.Timer before the loop, Reset it on each iteration and Stop it when done",
+	"deadlines.sql-context":         "%s does not take a context; the query keeps running after the caller gives up or its deadline passes",
+	"deadlines.sql-context.fix":     "Use %s and pass the caller's context",
+
+	// Logging
+	"logging.library-print":      "%s in library package '%s' writes to output the importing program does not control",
+	"logging.library-print.fix":  "Return errors or values to the caller, or accept a logger such as *slog.Logger as a dependency",
+	"logging.error-capitalized":  "Error string %q starts with a capital letter",
+	"logging.error-punctuation":  "Error string %q ends with punctuation or a newline",
+	"logging.error-string.fix":   "Start error strings in lower case without trailing punctuation, since they are usually wrapped into other messages",
+	"logging.log-and-return":     "Error '%s' is logged and returned, so it is reported more than once",
+	"logging.log-and-return.fix": "Either handle the error by logging it, or return it wrapped with context and let the caller decide",
+
+	// Struct tags
+	"struct-tags.malformed":          "Struct tag %q is malformed; it must be space-separated key:\"value\" pairs",
+	"struct-tags.malformed.fix":      "Write the tag as key:\"value\" pairs separated by single spaces, e.g. json:\"name,omitempty\" db:\"name\"",
+	"struct-tags.unknown-key":        "Struct tag key '%s' looks like a misspelling of '%s'",
+	"struct-tags.unknown-option":     "Unknown %s tag option '%s'",
+	"struct-tags.unknown-option.fix": "Remove the option; %s supports: %s",
+	"struct-tags.no-options.fix":     "Remove the option; %s tags only take a name",
+	"struct-tags.did-you-mean":       "Did you mean '%s'?",
+	"struct-tags.duplicate":          "Field '%s' has the same %s name '%s' as the field on line %d; one of them is silently ignored",
+	"struct-tags.duplicate.fix":      "Give each field a distinct name in its tag, or skip one with \"-\"",
+	"struct-tags.missing":            "Exported field '%s' of '%s' has no %s tag, but the type is marshaled in this file",
+	"struct-tags.missing.fix":        "Add a tag to fix the encoded name, e.g. %s:\"%s\", or \"-\" to skip the field",
+
+	// Aliasing and mutation
+	"aliasing.internal-slice":     "%s returns the internal slice '%s'; callers can modify the receiver's elements through it",
+	"aliasing.internal-slice.fix": "Return a copy with slices.Clone(%s), or document that callers must not modify the result",
+	"aliasing.internal-map":       "%s returns the internal map '%s'; callers can add, change and delete the receiver's entries through it",
+	"aliasing.internal-map.fix":   "Return a copy with maps.Clone(%s), or document that callers must not modify the result",
+	"aliasing.param-append":       "Appending to parameter '%s' may write into the caller's backing array when it has spare capacity",
+	"aliasing.param-append.fix":   "Copy the slice first, e.g. %[1]s = slices.Clone(%[1]s), return the result as append does, or document that the function takes ownership",
+	"aliasing.loop-goroutine":     "Goroutine started in a loop uses slice '%s', which the loop keeps modifying; all goroutines share its backing array",
+	"aliasing.loop-goroutine.fix": "Pass each goroutine its own copy, e.g. slices.Clone(%s), or allocate a new slice on every iteration",
+
+	// Modernization
+	"modernize.interface-any":      "interface{} can be written as any since Go 1.18",
+	"modernize.interface-any.fix":  "Replace interface{} with any",
+	"modernize.ioutil":             "ioutil.%s is deprecated since Go 1.16",
+	"modernize.ioutil.fix":         "Use %s, which behaves the same",
+	"modernize.ioutil-readdir.fix": "Use %s, which returns []os.DirEntry instead of []fs.FileInfo; call Info on the entries that need it",
+	"modernize.strings-title":      "strings.Title is deprecated since Go 1.18; it mishandles Unicode punctuation and ignores the language of the text",
+	"modernize.strings-title.fix":  "Use cases.Title from golang.org/x/text/cases with the language of the text",
+	"modernize.rand-seed":          "rand.Seed is deprecated since Go 1.20; the global generator is seeded randomly at startup",
+	"modernize.rand-seed.fix":      "Remove the call, or use a generator of its own from rand.New(rand.NewSource(seed)) when a reproducible sequence is needed",
+	"modernize.errors-is":          "Error is compared with %s by == or !=, which fails once the error is wrapped",
+	"modernize.errors-is.fix":      "Use errors.Is, which also matches wrapped errors",
+	"modernize.slices-loop":        "Loop can be replaced by %s",
+	"modernize.slices-loop.fix":    "Use %s, available since Go 1.21",
+
+	// Receivers
+	"receivers.mixed":            "%s has a value receiver, but %d other methods of %s use pointer receivers",
+	"receivers.mixed.fix":        "Use a pointer receiver, *%s, for every method so the type has one method set",
+	"receivers.inconsistent":     "Receiver of %s is named %s, but the other methods of %s name it %s",
+	"receivers.inconsistent.fix": "Rename the receiver to %s",
+	"receivers.generic":          "Receiver name '%s' is a generic name that says nothing about the receiver",
+	"receivers.generic.fix":      "Use a short name reflecting the type, such as %s",
+	"receivers.large":            "%s copies its receiver of about %d bytes on every call",
+	"receivers.large.fix":        "Use a pointer receiver, *%s",
+
+	// Package design
+	"design.kitchen-sink":     "Package %s is named like a catch-all and holds %d symbols, so unrelated code accumulates in it",
+	"design.kitchen-sink.fix": "Move each helper to a package named for what it provides, or next to its only caller",
+
+	// Complexity
+	"complexity.cyclomatic":     "Function has high cyclomatic complexity",
+	"complexity.cyclomatic.fix": "Consider breaking down into smaller functions",
+
+	// Generics
+	"generics.complex-constraint":     "Type parameter %s has an inline constraint with %d terms",
+	"generics.complex-constraint.fix": "Declare a named constraint interface and reuse it",
+	"generics.any-comparable":         "Type parameter %s is constrained by any but compared or used as a map key",
+	"generics.any-comparable.fix":     "Constrain %s by comparable",
+	"generics.any-ordered":            "Type parameter %s is constrained by any but ordered with <, <=, > or >=",
+	"generics.any-ordered.fix":        "Constrain %s by cmp.Ordered",
+	"generics.any-numeric":            "Type parameter %s is constrained by any but used in arithmetic",
+	"generics.any-numeric.fix":        "Constrain %s by a numeric constraint such as ~int | ~float64",
+
+	// Coverage
+	"coverage.untested-complex":     "Function %s has cyclomatic complexity %d and no test coverage",
+	"coverage.untested-complex.fix": "Add tests covering its branches before changing it",
+
+	// Custom guidelines
+	"custom.no-panic":     "Custom guideline: avoid using panic",
+	"custom.no-panic.fix": "Return an error instead",
+
+	// Plugin rules
+	"plugin.failed": "Rule %s failed and was skipped: %v",
+
+	// Dead code
+	"symbol.function":             "function",
+	"symbol.type":                 "type",
+	"symbol.constant":             "constant",
+	"symbol.variable":             "variable",
+	"dead-code.unused-symbol":     "Unexported %s '%s' is never used",
+	"dead-code.unused-symbol.fix": "Safe to delete: '%s' is not referenced in the provided code",
+	"dead-code.unreachable":       "Unreachable code after %s",
+	"dead-code.unreachable.fix":   "Safe to delete: this code can never execute",
+
+	// API stability
+	"api.breaking-change":     "Breaking API change: %s '%s' was %s",
+	"api.breaking-change.fix": "Keep the previous API and deprecate it, or release the change in a new major version",
+
+	// Hints
+	"hint.performance":            "Focus on performance: Look for opportunities to optimize algorithms, reduce allocations, and improve efficiency",
+	"hint.performance.impact":     "Better runtime performance and resource utilization",
+	"hint.security":               "Focus on security: Validate inputs, handle sensitive data carefully, and avoid common vulnerabilities",
+	"hint.security.impact":        "Improved application security and reduced attack surface",
+	"hint.testability":            "Focus on testability: Make functions pure, inject dependencies, and avoid global state",
+	"hint.testability.impact":     "Easier testing and better code reliability",
+	"hint.maintainability":        "Focus on maintainability: Use clear naming, add documentation, and simplify complex logic",
+	"hint.maintainability.impact": "Easier code maintenance and team collaboration",
+
+	// Markdown report
+	"report.title":                 "Code Review Report",
+	"report.issues":                "Issues",
+	"report.no-issues":             "No issues found.",
+	"report.line":                  "line",
+	"report.uncovered":             "untested",
+	"report.suggestion":            "Suggestion",
+	"report.example":               "Example",
+	"report.warning":               "Warning",
+	"report.suggestions":           "Suggestions",
+	"report.metrics":               "Metrics",
+	"report.metric":                "Metric",
+	"report.value":                 "Value",
+	"severity.critical":            "Critical",
+	"severity.high":                "High",
+	"severity.medium":              "Medium",
+	"severity.low":                 "Low",
+	"metric.lines-of-code":         "Lines of code",
+	"metric.cyclomatic-complexity": "Max cyclomatic complexity",
+	"metric.function-count":        "Functions",
+	"metric.type-count":            "Types",
+	"metric.test-coverage":         "Test coverage",
+	"metric.maintainability":       "Maintainability",
+
+	// Batch review
+	"batch.summary": "Reviewed %d of %d items: %d issues in total, average score %d/100, worst score %d/100 (%s).",
+	"batch.failed":  "%d items failed to review.",
+
+	// Workspace review
+	"workspace.summary":        "Reviewed %d files in %d packages: %d issues in total, average score %d/100",
+	"workspace.history-failed": "The review was not added to the score history: %v",
+}
+
+var japaneseMessages = map[string]string{
+	// Summary
+	"summary.parse-failed": "コードの解析に失敗しました",
+	"summary.clean":        "問題は見つかりませんでした。良いコードです！",
+	"summary.issues":       "%d 件の問題と %d 件の提案が見つかりました。総合スコア: %d/100",
+	"summary.suppressed":   "無視ディレクティブにより %d 件の指摘を抑制しました。",
+	"guidelines.truncated": "ガイドラインファイル %s が %d バイトを超えています。上限までのガイドラインのみを適用しました。",
+
+	// Syntax
+	"syntax.parse-failed": "Go コードの解析に失敗しました: %s",
+
+	// Naming
+	"naming.exported-function": "エクスポートされる関数は大文字で始める必要があります",
+	"naming.exported-type":     "エクスポートされる型は大文字で始める必要があります",
+	"naming.exported-variable": "エクスポートされる変数は大文字で始める必要があります",
+	"naming.rename":            "%s に名前を変更してください",
+	"naming.camel-case":        "関数名にはアンダースコアではなく camelCase を使用してください",
+	"naming.camel-case.fix":    "camelCase の命名規則を使用してください",
+
+	// Structure
+	"structure.function-length":       "関数が長すぎます (%d 行超)。分割を検討してください",
+	"structure.function-length.fix":   "小さく目的の明確な関数に分割してください",
+	"structure.parameter-count":       "関数の引数が多すぎます (%d 個超)",
+	"structure.parameter-count.fix":   "関連する引数を構造体にまとめることを検討してください",
+	"structure.struct-size":           "構造体のフィールドが多すぎます (%d 個超)。責務が多すぎないか検討してください",
+	"structure.struct-size.fix":       "より小さな構造体に分割することを検討してください",
+	"structure.many-functions":        "ファイルに関数が多く含まれています。複数ファイルへの分割を検討してください",
+	"structure.many-functions.impact": "保守性と構成の改善",
+
+	// Documentation
+	"docs.exported-function":     "エクスポートされる関数にドキュメントコメントがありません",
+	"docs.exported-function.fix": "関数名で始まるコメントを追加してください",
+	"docs.exported-type":         "エクスポートされる型にドキュメントコメントがありません",
+	"docs.exported-type.fix":     "型名で始まるコメントを追加してください",
+
+	// Error handling
+	"error-handling.ignored":          "エラーが無視されています",
+	"error-handling.ignored.fix":      "エラーを適切に処理してください",
+	"error-handling.swallowed":        "エラー '%s' はログに記録されるだけで処理が続行されます",
+	"error-handling.swallowed.fix":    "呼び出し元が失敗に対処できるよう、コンテキストを付けてエラーを返してください",
+	"error-handling.overwritten":      "エラー '%s' はチェックされる前に %d 行目で上書きされています",
+	"error-handling.overwritten.fix":  "変数に再代入する前にエラーをチェックしてください",
+	"error-handling.shadowed":         "ここで宣言された '%s' は %d 行目のエラーを隠しています。外側のエラーはこのブロックの後で使用されます",
+	"error-handling.shadowed.fix":     "外側のエラーが更新されるよう := ではなく = で代入してください",
+	"error-handling.fresh-comparison": "新しく生成したエラーとの比較は決して等しくなりません",
+	"error-handling.text-comparison":  "エラーがメッセージ文字列で比較されています",
+	"error-handling.comparison.fix":   "センチネルエラーとは errors.Is で、エラー型とは errors.As で比較してください",
+
+	// Performance
+	"performance.string-concat":     "ループ内での文字列連結は非効率になる可能性があります",
+	"performance.string-concat.fix": "strings.Builder または bytes.Buffer の使用を検討してください",
+
+	// Security
+	"security.unsafe":     "unsafe パッケージの使用は慎重にレビューする必要があります",
+	"security.unsafe.fix": "unsafe な操作が必要かつ正しいことを確認してください",
+
+	// Testability
+	"testability.globals":         "グローバル変数はテストを難しくする可能性があります",
+	"testability.globals.example": "依存性の注入や設定用構造体の使用を検討してください",
+	"testability.globals.impact":  "テスト容易性と保守性の向上",
+
+	// Package initialization and global state
+	"globals.heavy-init":     "init() が %d 個の文を実行しています。重いパッケージ初期化はすべてのテストバイナリで実行され、設定もスキップもできません",
+	"globals.init-io":        "init() が %s を呼び出しています。パッケージ初期化中の I/O によってすべてのテストが環境に依存します",
+	"globals.heavy-init.fix": "ロジックを main から呼び出す明示的なコンストラクタまたはセットアップ関数に移し、テストが独自の入力で実行できるようにしてください",
+	"globals.mutable":        "パッケージレベル変数 %s が %d 個の関数から書き込まれています (%s)",
+	"globals.mutable.fix":    "状態を構造体にまとめて必要な関数に渡し、テストが独立したインスタンスを使い並列実行できるようにしてください",
+	"globals.sync-once":      "%s.Do がパッケージレベルの %s を遅延初期化しています",
+	"globals.sync-once.fix":  "sync.OnceValue のような遅延コンストラクタを使うか、値を明示的に構築して注入し、テストで差し替えられるようにしてください",
+
+	// Deadlines and timeouts
+	"deadlines.http-client":         "http.Client に Timeout がありません。遅い、または応答しないサーバーによってリクエストが永久にブロックされる可能性があります",
+	"deadlines.http-client.fix":     "クライアントに Timeout を設定するか、各リクエストに期限付きのコンテキストを渡してください",
+	"deadlines.cancel-discarded":    "context.%s が返す cancel 関数が破棄されています。コンテキストはタイマーが発火するまで解放されません",
+	"deadlines.cancel-not-deferred": "context.%[2]s の %[1]s が defer されていません。呼び出し前に return するとコンテキストとタイマーがリークします",
+	"deadlines.cancel.fix":          "コンテキストの作成直後に defer cancel() を呼び出してください",
+	"deadlines.time-after-loop":     "ループ内の select で time.After を使うと、反復ごとに新しいタイマーが作成されます",
+	"deadlines.time-after-loop.fix": "ループの前に time.Timer を 1 つ作成し、反復ごとに Reset して、終了時に Stop してください",
+	"deadlines.sql-context":         "%s はコンテキストを受け取りません。呼び出し元が諦めたり期限を過ぎたりしてもクエリは実行され続けます",
+	"deadlines.sql-context.fix":     "%s を使い、呼び出し元のコンテキストを渡してください",
+
+	// Logging
+	"logging.library-print":      "ライブラリパッケージ '%[2]s' の %[1]s は、インポートするプログラムが制御できない出力に書き込みます",
+	"logging.library-print.fix":  "エラーや値を呼び出し元に返すか、*slog.Logger などのロガーを依存として受け取ってください",
+	"logging.error-capitalized":  "エラー文字列 %q が大文字で始まっています",
+	"logging.error-punctuation":  "エラー文字列 %q が句読点または改行で終わっています",
+	"logging.error-string.fix":   "エラー文字列は他のメッセージに埋め込まれることが多いため、小文字で始め末尾に句読点を付けないでください",
+	"logging.log-and-return":     "エラー '%s' がログ出力されたうえで返されており、複数回報告されます",
+	"logging.log-and-return.fix": "ログ出力してエラーを処理するか、コンテキストでラップして返し呼び出し元に判断を任せてください",
+
+	// Struct tags
+	"struct-tags.malformed":          "構造体タグ %q の形式が不正です。スペース区切りの key:\"value\" の組である必要があります",
+	"struct-tags.malformed.fix":      "タグは key:\"value\" の組を 1 つのスペースで区切って記述してください。例: json:\"name,omitempty\" db:\"name\"",
+	"struct-tags.unknown-key":        "構造体タグのキー '%s' は '%s' のスペルミスのようです",
+	"struct-tags.unknown-option":     "%s タグの不明なオプション '%s'",
+	"struct-tags.unknown-option.fix": "オプションを削除してください。%s で使えるのは次のとおりです: %s",
+	"struct-tags.no-options.fix":     "オプションを削除してください。%s タグは名前のみを受け取ります",
+	"struct-tags.did-you-mean":       "'%s' の誤りではありませんか？",
+	"struct-tags.duplicate":          "フィールド '%s' の %s 名 '%s' が %[4]d 行目のフィールドと重複しています。一方は黙って無視されます",
+	"struct-tags.duplicate.fix":      "各フィールドのタグに異なる名前を付けるか、一方を \"-\" でスキップしてください",
+	"struct-tags.missing":            "'%[2]s' のエクスポートされたフィールド '%[1]s' に %[3]s タグがありませんが、この型はこのファイルでマーシャルされています",
+	"struct-tags.missing.fix":        "エンコード名を固定するタグを追加してください。例: %s:\"%s\"。フィールドをスキップするには \"-\" を使います",
+
+	// Aliasing and mutation
+	"aliasing.internal-slice":     "%s が内部スライス '%s' をそのまま返しています。呼び出し元がレシーバーの要素を変更できます",
+	"aliasing.internal-slice.fix": "slices.Clone(%s) でコピーを返すか、結果を変更してはならないことをドキュメントに記載してください",
+	"aliasing.internal-map":       "%s が内部マップ '%s' をそのまま返しています。呼び出し元がレシーバーのエントリを追加、変更、削除できます",
+	"aliasing.internal-map.fix":   "maps.Clone(%s) でコピーを返すか、結果を変更してはならないことをドキュメントに記載してください",
+	"aliasing.param-append":       "パラメータ '%s' への append は、容量に余裕がある場合に呼び出し元の基底配列へ書き込む可能性があります",
+	"aliasing.param-append.fix":   "先にスライスをコピーする (例: %[1]s = slices.Clone(%[1]s))、append と同様に結果を返す、または関数が所有権を持つことをドキュメントに記載してください",
+	"aliasing.loop-goroutine":     "ループ内で起動した goroutine がスライス '%s' を使用していますが、ループはこれを変更し続けます。すべての goroutine が同じ基底配列を共有します",
+	"aliasing.loop-goroutine.fix": "各 goroutine に slices.Clone(%s) などで個別のコピーを渡すか、反復ごとに新しいスライスを割り当ててください",
+
+	// Modernization
+	"modernize.interface-any":      "Go 1.18 以降、interface{} は any と書けます",
+	"modernize.interface-any.fix":  "interface{} を any に置き換えてください",
+	"modernize.ioutil":             "ioutil.%s は Go 1.16 で非推奨になりました",
+	"modernize.ioutil.fix":         "同じ動作をする %s を使用してください",
+	"modernize.ioutil-readdir.fix": "%s を使用してください。[]fs.FileInfo ではなく []os.DirEntry を返すため、必要なエントリでは Info を呼び出してください",
+	"modernize.strings-title":      "strings.Title は Go 1.18 で非推奨になりました。Unicode の句読点を正しく扱えず、テキストの言語も考慮しません",
+	"modernize.strings-title.fix":  "テキストの言語を指定して golang.org/x/text/cases の cases.Title を使用してください",
+	"modernize.rand-seed":          "rand.Seed は Go 1.20 で非推奨になりました。グローバルな生成器は起動時にランダムにシードされます",
+	"modernize.rand-seed.fix":      "呼び出しを削除するか、再現可能な系列が必要な場合は rand.New(rand.NewSource(seed)) で専用の生成器を使用してください",
+	"modernize.errors-is":          "エラーが == または != で %s と比較されています。エラーがラップされると比較は失敗します",
+	"modernize.errors-is.fix":      "ラップされたエラーにも一致する errors.Is を使用してください",
+	"modernize.slices-loop":        "このループは %s で置き換えられます",
+	"modernize.slices-loop.fix":    "Go 1.21 以降で使える %s を使用してください",
+
+	// Receivers
+	"receivers.mixed":            "%[1]s は値レシーバーですが、%[3]s の他の %[2]d 個のメソッドはポインタレシーバーを使用しています",
+	"receivers.mixed.fix":        "型のメソッドセットを一つにするため、すべてのメソッドでポインタレシーバー *%s を使用してください",
+	"receivers.inconsistent":     "%s のレシーバー名は %s ですが、%s の他のメソッドでは %s です",
+	"receivers.inconsistent.fix": "レシーバー名を %s に変更してください",
+	"receivers.generic":          "レシーバー名 '%s' は汎用的な名前で、レシーバーについて何も表していません",
+	"receivers.generic.fix":      "%s など、型を反映した短い名前を使用してください",
+	"receivers.large":            "%s は呼び出しのたびに約 %d バイトのレシーバーをコピーします",
+	"receivers.large.fix":        "ポインタレシーバー *%s を使用してください",
+
+	// Package design
+	"design.kitchen-sink":     "パッケージ %s は何でも入れる場所のような名前で %d 個のシンボルを持ち、無関係なコードが集まりがちです",
+	"design.kitchen-sink.fix": "各ヘルパーを、提供する機能を表す名前のパッケージか、唯一の呼び出し元の近くに移動してください",
+
+	// Complexity
+	"complexity.cyclomatic":     "関数の循環的複雑度が高すぎます",
+	"complexity.cyclomatic.fix": "より小さな関数に分割することを検討してください",
+
+	// Generics
+	"generics.complex-constraint":     "型パラメータ %s のインライン制約に %d 個の項があります",
+	"generics.complex-constraint.fix": "名前付きの制約インターフェースを宣言して再利用してください",
+	"generics.any-comparable":         "型パラメータ %s は any で制約されていますが、比較またはマップのキーに使われています",
+	"generics.any-comparable.fix":     "%s を comparable で制約してください",
+	"generics.any-ordered":            "型パラメータ %s は any で制約されていますが、<、<=、>、>= で順序付けされています",
+	"generics.any-ordered.fix":        "%s を cmp.Ordered で制約してください",
+	"generics.any-numeric":            "型パラメータ %s は any で制約されていますが、算術演算に使われています",
+	"generics.any-numeric.fix":        "%s を ~int | ~float64 のような数値制約で制約してください",
+
+	// Coverage
+	"coverage.untested-complex":     "関数 %s は循環的複雑度が %d ですが、テストでカバーされていません",
+	"coverage.untested-complex.fix": "変更する前に分岐を網羅するテストを追加してください",
+
+	// Custom guidelines
+	"custom.no-panic":     "カスタムガイドライン: panic の使用を避けてください",
+	"custom.no-panic.fix": "代わりにエラーを返してください",
+
+	// Plugin rules
+	"plugin.failed": "ルール %s が失敗したためスキップされました: %v",
+
+	// Dead code
+	"symbol.function":             "関数",
+	"symbol.type":                 "型",
+	"symbol.constant":             "定数",
+	"symbol.variable":             "変数",
+	"dead-code.unused-symbol":     "エクスポートされない%s '%s' は使用されていません",
+	"dead-code.unused-symbol.fix": "削除しても安全です: '%s' は提供されたコード内で参照されていません",
+	"dead-code.unreachable":       "%s の後のコードには到達できません",
+	"dead-code.unreachable.fix":   "削除しても安全です: このコードは決して実行されません",
+
+	// API stability
+	"api.breaking-change":     "破壊的な API 変更: %s '%s' (%s)",
+	"api.breaking-change.fix": "以前の API を残して非推奨にするか、新しいメジャーバージョンで変更をリリースしてください",
+
+	// Hints
+	"hint.performance":            "パフォーマンスに注目: アルゴリズムの最適化、アロケーションの削減、効率の改善の機会を探してください",
+	"hint.performance.impact":     "実行時パフォーマンスとリソース利用の改善",
+	"hint.security":               "セキュリティに注目: 入力を検証し、機密データを慎重に扱い、一般的な脆弱性を避けてください",
+	"hint.security.impact":        "アプリケーションのセキュリティ向上と攻撃対象領域の縮小",
+	"hint.testability":            "テスト容易性に注目: 関数を純粋にし、依存関係を注入し、グローバル状態を避けてください",
+	"hint.testability.impact":     "テストの容易化とコードの信頼性向上",
+	"hint.maintainability":        "保守性に注目: 明確な命名、ドキュメントの追加、複雑なロジックの簡素化を行ってください",
+	"hint.maintainability.impact": "コード保守とチーム協業の容易化",
+
+	// Markdown report
+	"report.title":                 "コードレビューレポート",
+	"report.issues":                "問題",
+	"report.no-issues":             "問題は見つかりませんでした。",
+	"report.line":                  "行",
+	"report.uncovered":             "テストなし",
+	"report.suggestion":            "提案",
+	"report.example":               "例",
+	"report.warning":               "警告",
+	"report.suggestions":           "提案",
+	"report.metrics":               "メトリクス",
+	"report.metric":                "メトリクス",
+	"report.value":                 "値",
+	"severity.critical":            "重大",
+	"severity.high":                "高",
+	"severity.medium":              "中",
+	"severity.low":                 "低",
+	"metric.lines-of-code":         "コード行数",
+	"metric.cyclomatic-complexity": "最大循環的複雑度",
+	"metric.function-count":        "関数数",
+	"metric.type-count":            "型数",
+	"metric.test-coverage":         "テストカバレッジ",
+	"metric.maintainability":       "保守性",
+
+	// Batch review
+	"batch.summary": "%[2]d 件中 %[1]d 件をレビューしました: 問題は合計 %[3]d 件、平均スコア %[4]d/100、最低スコア %[5]d/100 (%[6]s)。",
+	"batch.failed":  "%d 件のレビューに失敗しました。",
+
+	// Workspace review
+	"workspace.summary":        "%d 個のファイル (%d パッケージ) をレビューしました: 問題は合計 %d 件、平均スコア %d/100",
+	"workspace.history-failed": "レビューをスコア履歴に追加できませんでした: %v",
+}
+
+var spanishMessages = map[string]string{
+	// Summary
+	"summary.parse-failed": "Falló el análisis del código",
+	"summary.clean":        "¡El código se ve bien! No se encontraron problemas importantes.",
+	"summary.issues":       "Se encontraron %d problemas y %d sugerencias. Puntuación general: %d/100",
+	"summary.suppressed":   "%d hallazgos suprimidos por directivas de omisión.",
+	"guidelines.truncated": "El archivo de pautas %s supera los %d bytes; solo se aplicaron las pautas anteriores al límite.",
+
+	// Syntax
+	"syntax.parse-failed": "No se pudo analizar el código Go: %s",
+
+	// Naming
+	"naming.exported-function": "Las funciones exportadas deben comenzar con mayúscula",
+	"naming.exported-type":     "Los tipos exportados deben comenzar con mayúscula",
+	"naming.exported-variable": "Las variables exportadas deben comenzar con mayúscula",
+	"naming.rename":            "Renombrar a %s",
+	"naming.camel-case":        "Los nombres de funciones deben usar camelCase, no guiones bajos",
+	"naming.camel-case.fix":    "Use la convención de nombres camelCase",
+
+	// Structure
+	"structure.function-length":       "La función es demasiado larga (>%d líneas). Considere dividirla",
+	"structure.function-length.fix":   "Divida en funciones más pequeñas y enfocadas",
+	"structure.parameter-count":       "La función tiene demasiados parámetros (>%d)",
+	"structure.parameter-count.fix":   "Considere usar un struct para agrupar parámetros relacionados",
+	"structure.struct-size":           "El struct tiene muchos campos (>%d). Considere si hace demasiado",
+	"structure.struct-size.fix":       "Considere dividirlo en structs más pequeños",
+	"structure.many-functions":        "El archivo contiene muchas funciones. Considere dividirlo en varios archivos",
+	"structure.many-functions.impact": "Mejor mantenibilidad y organización",
+
+	// Documentation
+	"docs.exported-function":     "La función exportada no tiene comentario de documentación",
+	"docs.exported-function.fix": "Agregue un comentario que comience con el nombre de la función",
+	"docs.exported-type":         "El tipo exportado no tiene comentario de documentación",
+	"docs.exported-type.fix":     "Agregue un comentario que comience con el nombre del tipo",
+
+	// Error handling
+	"error-handling.ignored":          "Se está ignorando un error",
+	"error-handling.ignored.fix":      "Maneje el error adecuadamente",
+	"error-handling.swallowed":        "El error '%s' solo se registra y la ejecución continúa",
+	"error-handling.swallowed.fix":    "Devuelva el error, con contexto, para que quien llama pueda reaccionar al fallo",
+	"error-handling.overwritten":      "El error '%s' se sobrescribe en la línea %d antes de comprobarse",
+	"error-handling.overwritten.fix":  "Compruebe el error antes de volver a asignar la variable",
+	"error-handling.shadowed":         "'%s' declarado aquí oculta el error de la línea %d, que se usa después de este bloque",
+	"error-handling.shadowed.fix":     "Asigne con = en lugar de := para actualizar el error externo",
+	"error-handling.fresh-comparison": "La comparación con un error recién creado nunca es igual",
+	"error-handling.text-comparison":  "El error se compara por el texto de su mensaje",
+	"error-handling.comparison.fix":   "Compare con un error centinela usando errors.Is, o use errors.As para tipos de error",
+
+	// Performance
+	"performance.string-concat":     "La concatenación de cadenas dentro de un bucle puede ser ineficiente",
+	"performance.string-concat.fix": "Considere usar strings.Builder o bytes.Buffer",
+
+	// Security
+	"security.unsafe":     "El uso del paquete unsafe debe revisarse cuidadosamente",
+	"security.unsafe.fix": "Asegúrese de que las operaciones unsafe sean necesarias y correctas",
+
+	// Testability
+	"testability.globals":         "Las variables globales pueden dificultar las pruebas",
+	"testability.globals.example": "Considere la inyección de dependencias o structs de configuración",
+	"testability.globals.impact":  "Mejor capacidad de prueba y mantenibilidad",
+
+	// Package initialization and global state
+	"globals.heavy-init":     "init() ejecuta %d sentencias; una inicialización de paquete pesada se ejecuta en cada binario de pruebas y no se puede configurar ni omitir",
+	"globals.init-io":        "init() llama a %s; la E/S durante la inicialización del paquete hace que todas las pruebas dependan del entorno",
+	"globals.heavy-init.fix": "Mueva la lógica a un constructor o función de configuración explícita llamada desde main, para que las pruebas la ejecuten con sus propias entradas",
+	"globals.mutable":        "La variable de paquete %s se escribe desde %d funciones (%s)",
+	"globals.mutable.fix":    "Mantenga el estado en un struct que se pase a las funciones que lo necesitan, para que las pruebas usen instancias aisladas y se ejecuten en paralelo",
+	"globals.sync-once":      "%s.Do inicializa de forma diferida la variable de paquete %s",
+	"globals.sync-once.fix":  "Use un constructor diferido como sync.OnceValue, o construya el valor explícitamente e inyéctelo para que las pruebas puedan sustituirlo",
+
+	// Deadlines and timeouts
+	"deadlines.http-client":         "http.Client no tiene Timeout; un servidor lento o que no responde puede bloquear la petición para siempre",
+	"deadlines.http-client.fix":     "Defina Timeout en el cliente, o dé a cada petición un contexto con fecha límite",
+	"deadlines.cancel-discarded":    "La función cancel devuelta por context.%s se descarta; el contexto solo se libera cuando vence el temporizador",
+	"deadlines.cancel-not-deferred": "%s de context.%s nunca se difiere; cualquier return antes de llamarla filtra el contexto y su temporizador",
+	"deadlines.cancel.fix":          "Llame a defer cancel() justo después de crear el contexto",
+	"deadlines.time-after-loop":     "time.After en un select dentro de un bucle crea un temporizador nuevo en cada iteración",
+	"deadlines.time-after-loop.fix": "Cree un único time.Timer antes del bucle, llame a Reset en cada iteración y a Stop al terminar",
+	"deadlines.sql-context":         "%s no recibe un contexto; la consulta sigue ejecutándose aunque el llamador desista o venza su plazo",
+	"deadlines.sql-context.fix":     "Use %s y pase el contexto del llamador",
+
+	// Logging
+	"logging.library-print":      "%s en el paquete de biblioteca '%s' escribe en una salida que el programa que lo importa no controla",
+	"logging.library-print.fix":  "Devuelva errores o valores al llamador, o reciba un logger como *slog.Logger como dependencia",
+	"logging.error-capitalized":  "La cadena de error %q empieza con mayúscula",
+	"logging.error-punctuation":  "La cadena de error %q termina con puntuación o un salto de línea",
+	"logging.error-string.fix":   "Empiece las cadenas de error en minúscula y sin puntuación final, ya que suelen incluirse en otros mensajes",
+	"logging.log-and-return":     "El error '%s' se registra y se devuelve, por lo que se informa más de una vez",
+	"logging.log-and-return.fix": "Gestione el error registrándolo, o devuélvalo envuelto con contexto y deje que el llamador decida",
+
+	// Struct tags
+	"struct-tags.malformed":          "La etiqueta de estructura %q está mal formada; debe ser pares key:\"value\" separados por espacios",
+	"struct-tags.malformed.fix":      "Escriba la etiqueta como pares key:\"value\" separados por un espacio, p. ej. json:\"name,omitempty\" db:\"name\"",
+	"struct-tags.unknown-key":        "La clave de etiqueta '%s' parece un error tipográfico de '%s'",
+	"struct-tags.unknown-option":     "Opción desconocida de la etiqueta %s: '%s'",
+	"struct-tags.unknown-option.fix": "Elimine la opción; %s admite: %s",
+	"struct-tags.no-options.fix":     "Elimine la opción; las etiquetas %s solo admiten un nombre",
+	"struct-tags.did-you-mean":       "¿Quiso decir '%s'?",
+	"struct-tags.duplicate":          "El campo '%s' tiene el mismo nombre %s '%s' que el campo de la línea %d; uno de ellos se ignora sin aviso",
+	"struct-tags.duplicate.fix":      "Dé a cada campo un nombre distinto en su etiqueta, u omita uno con \"-\"",
+	"struct-tags.missing":            "El campo exportado '%s' de '%s' no tiene etiqueta %s, pero el tipo se serializa en este archivo",
+	"struct-tags.missing.fix":        "Añada una etiqueta para fijar el nombre codificado, p. ej. %s:\"%s\", o \"-\" para omitir el campo",
+
+	// Aliasing and mutation
+	"aliasing.internal-slice":     "%s devuelve el slice interno '%s'; los llamadores pueden modificar los elementos del receptor a través de él",
+	"aliasing.internal-slice.fix": "Devuelva una copia con slices.Clone(%s), o documente que los llamadores no deben modificar el resultado",
+	"aliasing.internal-map":       "%s devuelve el mapa interno '%s'; los llamadores pueden añadir, cambiar y borrar entradas del receptor a través de él",
+	"aliasing.internal-map.fix":   "Devuelva una copia con maps.Clone(%s), o documente que los llamadores no deben modificar el resultado",
+	"aliasing.param-append":       "Añadir al parámetro '%s' con append puede escribir en el array subyacente del llamador si tiene capacidad libre",
+	"aliasing.param-append.fix":   "Copie el slice primero, p. ej. %[1]s = slices.Clone(%[1]s), devuelva el resultado como hace append, o documente que la función toma posesión de él",
+	"aliasing.loop-goroutine":     "Una goroutine iniciada en un bucle usa el slice '%s', que el bucle sigue modificando; todas las goroutines comparten su array subyacente",
+	"aliasing.loop-goroutine.fix": "Pase a cada goroutine su propia copia, p. ej. slices.Clone(%s), o cree un slice nuevo en cada iteración",
+
+	// Modernization
+	"modernize.interface-any":      "Desde Go 1.18, interface{} puede escribirse como any",
+	"modernize.interface-any.fix":  "Reemplace interface{} por any",
+	"modernize.ioutil":             "ioutil.%s está obsoleto desde Go 1.16",
+	"modernize.ioutil.fix":         "Use %s, que se comporta igual",
+	"modernize.ioutil-readdir.fix": "Use %s, que devuelve []os.DirEntry en lugar de []fs.FileInfo; llame a Info en las entradas que lo necesiten",
+	"modernize.strings-title":      "strings.Title está obsoleto desde Go 1.18; no trata bien la puntuación Unicode e ignora el idioma del texto",
+	"modernize.strings-title.fix":  "Use cases.Title de golang.org/x/text/cases con el idioma del texto",
+	"modernize.rand-seed":          "rand.Seed está obsoleto desde Go 1.20; el generador global recibe una semilla aleatoria al iniciar",
+	"modernize.rand-seed.fix":      "Elimine la llamada, o use un generador propio con rand.New(rand.NewSource(seed)) cuando necesite una secuencia reproducible",
+	"modernize.errors-is":          "El error se compara con %s usando == o !=, lo que falla en cuanto el error se envuelve",
+	"modernize.errors-is.fix":      "Use errors.Is, que también reconoce errores envueltos",
+	"modernize.slices-loop":        "El bucle puede reemplazarse por %s",
+	"modernize.slices-loop.fix":    "Use %s, disponible desde Go 1.21",
+
+	// Receivers
+	"receivers.mixed":            "%s tiene un receptor por valor, pero otros %d métodos de %s usan receptores puntero",
+	"receivers.mixed.fix":        "Use un receptor puntero, *%s, en todos los métodos para que el tipo tenga un único conjunto de métodos",
+	"receivers.inconsistent":     "El receptor de %s se llama %s, pero los demás métodos de %s lo llaman %s",
+	"receivers.inconsistent.fix": "Renombre el receptor a %s",
+	"receivers.generic":          "El nombre de receptor '%s' es genérico y no dice nada del receptor",
+	"receivers.generic.fix":      "Use un nombre corto que refleje el tipo, como %s",
+	"receivers.large":            "%s copia su receptor de unos %d bytes en cada llamada",
+	"receivers.large.fix":        "Use un receptor puntero, *%s",
+
+	// Package design
+	"design.kitchen-sink":     "El paquete %s tiene nombre de cajón de sastre y contiene %d símbolos, por lo que acumula código sin relación",
+	"design.kitchen-sink.fix": "Mueva cada utilidad a un paquete nombrado por lo que ofrece, o junto a su único llamador",
+
+	// Complexity
+	"complexity.cyclomatic":     "La función tiene una complejidad ciclomática alta",
+	"complexity.cyclomatic.fix": "Considere dividirla en funciones más pequeñas",
+
+	// Generics
+	"generics.complex-constraint":     "El parámetro de tipo %s tiene una restricción en línea con %d términos",
+	"generics.complex-constraint.fix": "Declare una interfaz de restricción con nombre y reutilícela",
+	"generics.any-comparable":         "El parámetro de tipo %s está restringido por any pero se compara o se usa como clave de mapa",
+	"generics.any-comparable.fix":     "Restrinja %s con comparable",
+	"generics.any-ordered":            "El parámetro de tipo %s está restringido por any pero se ordena con <, <=, > o >=",
+	"generics.any-ordered.fix":        "Restrinja %s con cmp.Ordered",
+	"generics.any-numeric":            "El parámetro de tipo %s está restringido por any pero se usa en operaciones aritméticas",
+	"generics.any-numeric.fix":        "Restrinja %s con una restricción numérica como ~int | ~float64",
+
+	// Coverage
+	"coverage.untested-complex":     "La función %s tiene una complejidad ciclomática de %d y ninguna cobertura de pruebas",
+	"coverage.untested-complex.fix": "Agregue pruebas que cubran sus ramas antes de modificarla",
+
+	// Custom guidelines
+	"custom.no-panic":     "Guía personalizada: evite usar panic",
+	"custom.no-panic.fix": "Devuelva un error en su lugar",
+
+	// Plugin rules
+	"plugin.failed": "La regla %s falló y se omitió: %v",
+
+	// Dead code
+	"symbol.function":             "función",
+	"symbol.type":                 "tipo",
+	"symbol.constant":             "constante",
+	"symbol.variable":             "variable",
+	"dead-code.unused-symbol":     "El símbolo sin exportar '%[2]s' (%[1]s) nunca se usa",
+	"dead-code.unused-symbol.fix": "Se puede eliminar: '%s' no se referencia en el código proporcionado",
+	"dead-code.unreachable":       "Código inalcanzable después de %s",
+	"dead-code.unreachable.fix":   "Se puede eliminar: este código nunca se ejecuta",
+
+	// API stability
+	"api.breaking-change":     "Cambio incompatible de API: %s '%s' (%s)",
+	"api.breaking-change.fix": "Mantenga la API anterior y márquela como obsoleta, o publique el cambio en una nueva versión mayor",
+
+	// Hints
+	"hint.performance":            "Enfoque en rendimiento: busque oportunidades para optimizar algoritmos, reducir asignaciones y mejorar la eficiencia",
+	"hint.performance.impact":     "Mejor rendimiento en tiempo de ejecución y uso de recursos",
+	"hint.security":               "Enfoque en seguridad: valide las entradas, maneje los datos sensibles con cuidado y evite vulnerabilidades comunes",
+	"hint.security.impact":        "Mayor seguridad de la aplicación y menor superficie de ataque",
+	"hint.testability":            "Enfoque en capacidad de prueba: haga funciones puras, inyecte dependencias y evite el estado global",
+	"hint.testability.impact":     "Pruebas más sencillas y código más confiable",
+	"hint.maintainability":        "Enfoque en mantenibilidad: use nombres claros, agregue documentación y simplifique la lógica compleja",
+	"hint.maintainability.impact": "Mantenimiento más sencillo y mejor colaboración en equipo",
+
+	// Markdown report
+	"report.title":                 "Informe de revisión de código",
+	"report.issues":                "Problemas",
+	"report.no-issues":             "No se encontraron problemas.",
+	"report.line":                  "línea",
+	"report.uncovered":             "sin pruebas",
+	"report.suggestion":            "Sugerencia",
+	"report.example":               "Ejemplo",
+	"report.warning":               "Advertencia",
+	"report.suggestions":           "Sugerencias",
+	"report.metrics":               "Métricas",
+	"report.metric":                "Métrica",
+	"report.value":                 "Valor",
+	"severity.critical":            "Crítico",
+	"severity.high":                "Alto",
+	"severity.medium":              "Medio",
+	"severity.low":                 "Bajo",
+	"metric.lines-of-code":         "Líneas de código",
+	"metric.cyclomatic-complexity": "Complejidad ciclomática máxima",
+	"metric.function-count":        "Funciones",
+	"metric.type-count":            "Tipos",
+	"metric.test-coverage":         "Cobertura de pruebas",
+	"metric.maintainability":       "Mantenibilidad",
+
+	// Batch review
+	"batch.summary": "Se revisaron %d de %d elementos: %d problemas en total, puntuación media %d/100, peor puntuación %d/100 (%s).",
+	"batch.failed":  "%d elementos no pudieron revisarse.",
+
+	// Workspace review
+	"workspace.summary":        "Se revisaron %d archivos en %d paquetes: %d problemas en total, puntuación media %d/100",
+	"workspace.history-failed": "La revisión no se añadió al historial de puntuaciones: %v",
+}
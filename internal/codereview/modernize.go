@@ -0,0 +1,389 @@
+package codereview
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// interfaceAnyExample shows the any alias, which reads the same wherever
+// interface{} is used
+const interfaceAnyExample = `// Before
+func Encode(v interface{}) ([]byte, error)
+
+// After
+func Encode(v any) ([]byte, error)`
+
+// ioutilReplacements maps the members of io/ioutil, deprecated since Go
+// 1.16, to the package and name replacing them
+var ioutilReplacements = map[string][2]string{
+	"ReadAll":   {"io", "ReadAll"},
+	"ReadFile":  {"os", "ReadFile"},
+	"WriteFile": {"os", "WriteFile"},
+	"ReadDir":   {"os", "ReadDir"},
+	"NopCloser": {"io", "NopCloser"},
+	"TempFile":  {"os", "CreateTemp"},
+	"TempDir":   {"os", "MkdirTemp"},
+	"Discard":   {"io", "Discard"},
+}
+
+// checkModernize suggests modern replacements for dated idioms: any
+// instead of interface{}, the deprecated io/ioutil, strings.Title and
+// rand.Seed, sentinel errors compared with == instead of errors.Is, and
+// loops that slices.Contains or slices.Index replace. Each issue shows the
+// code before and after.
+func (a *Analyzer) checkModernize(file *ast.File, result *ReviewResult) {
+	imports := importNames(file)
+	fields := referenceFields(file)
+	// A package declaring its own any cannot use the predeclared one
+	anyShadowed := file.Scope.Lookup("any") != nil
+	reported := make(map[ast.Node]bool)
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			// Is methods implement errors.Is and compare targets with ==
+			if node.Recv != nil && node.Name.Name == "Is" {
+				return false
+			}
+		case *ast.InterfaceType:
+			if !anyShadowed && node.Methods != nil && len(node.Methods.List) == 0 {
+				result.Issues = append(result.Issues, a.modernizeIssue(node, "low", "interface-any",
+					a.msg("modernize.interface-any"), a.msg("modernize.interface-any.fix"), interfaceAnyExample))
+			}
+		case *ast.CallExpr:
+			a.checkModernCall(node, imports, reported, result)
+		case *ast.SelectorExpr:
+			// Members of io/ioutil used without a call, such as ioutil.Discard
+			if !reported[node] {
+				if replacement, ok := a.ioutilReplacement(node, imports); ok {
+					result.Issues = append(result.Issues, a.modernizeIssue(node, "low", "deprecated-ioutil",
+						a.msg("modernize.ioutil", node.Sel.Name), a.ioutilFix(node.Sel.Name, replacement),
+						beforeAfter(types.ExprString(node), types.ExprString(replacement))))
+				}
+			}
+		case *ast.BinaryExpr:
+			a.checkSentinelComparison(node, result)
+		case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+			stmts := blockStmts(node)
+			for i, stmt := range stmts {
+				if loop, ok := stmt.(*ast.RangeStmt); ok {
+					var next ast.Stmt
+					if i+1 < len(stmts) {
+						next = stmts[i+1]
+					}
+					a.checkSearchLoop(loop, next, fields, result)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// checkModernCall reports calls to deprecated io/ioutil functions,
+// strings.Title and rand.Seed, marking the reported selectors in reported
+func (a *Analyzer) checkModernCall(call *ast.CallExpr, imports map[string]string, reported map[ast.Node]bool, result *ReviewResult) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+
+	if replacement, ok := a.ioutilReplacement(sel, imports); ok {
+		reported[sel] = true
+		fixed := *call
+		fixed.Fun = replacement
+		result.Issues = append(result.Issues, a.modernizeIssue(call, "low", "deprecated-ioutil",
+			a.msg("modernize.ioutil", sel.Sel.Name), a.ioutilFix(sel.Sel.Name, replacement),
+			beforeAfter(types.ExprString(call), types.ExprString(&fixed))))
+		return
+	}
+
+	if name, ok := imports["strings"]; ok && isPkgSelector(sel, name, "Title") && len(call.Args) == 1 {
+		fixed := &ast.CallExpr{
+			Fun: &ast.SelectorExpr{
+				X: &ast.CallExpr{
+					Fun:  &ast.SelectorExpr{X: ast.NewIdent("cases"), Sel: ast.NewIdent("Title")},
+					Args: []ast.Expr{&ast.SelectorExpr{X: ast.NewIdent("language"), Sel: ast.NewIdent("English")}},
+				},
+				Sel: ast.NewIdent("String"),
+			},
+			Args: call.Args,
+		}
+		result.Issues = append(result.Issues, a.modernizeIssue(call, "low", "strings-title",
+			a.msg("modernize.strings-title"), a.msg("modernize.strings-title.fix"),
+			beforeAfter(types.ExprString(call), types.ExprString(fixed))))
+		return
+	}
+
+	if name, ok := imports["math/rand"]; ok && isPkgSelector(sel, name, "Seed") && len(call.Args) == 1 {
+		// Seeding from the clock only asked for different numbers on each
+		// run, which the global generator now gives; a fixed seed asked
+		// for a reproducible sequence, which needs a generator of its own
+		after := "// Remove the call"
+		if !references(call.Args[0], "Now") {
+			after = "rng := " + types.ExprString(&ast.CallExpr{
+				Fun: &ast.SelectorExpr{X: ast.NewIdent(name), Sel: ast.NewIdent("New")},
+				Args: []ast.Expr{&ast.CallExpr{
+					Fun:  &ast.SelectorExpr{X: ast.NewIdent(name), Sel: ast.NewIdent("NewSource")},
+					Args: call.Args,
+				}},
+			})
+		}
+		result.Issues = append(result.Issues, a.modernizeIssue(call, "low", "rand-seed",
+			a.msg("modernize.rand-seed"), a.msg("modernize.rand-seed.fix"),
+			beforeAfter(types.ExprString(call), after)))
+	}
+}
+
+// ioutilReplacement returns the replacement of sel when it selects a
+// deprecated member of io/ioutil, using the file's names for io and os
+func (a *Analyzer) ioutilReplacement(sel *ast.SelectorExpr, imports map[string]string) (*ast.SelectorExpr, bool) {
+	name, ok := imports["io/ioutil"]
+	if !ok {
+		return nil, false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok || ident.Name != name {
+		return nil, false
+	}
+	replacement, ok := ioutilReplacements[sel.Sel.Name]
+	if !ok {
+		return nil, false
+	}
+	pkg := replacement[0]
+	if local, ok := imports[pkg]; ok {
+		pkg = local
+	}
+	return &ast.SelectorExpr{X: ast.NewIdent(pkg), Sel: ast.NewIdent(replacement[1])}, true
+}
+
+// ioutilFix returns the suggestion replacing the io/ioutil member name,
+// noting the changed result of ReadDir
+func (a *Analyzer) ioutilFix(name string, replacement *ast.SelectorExpr) string {
+	if name == "ReadDir" {
+		return a.msg("modernize.ioutil-readdir.fix", types.ExprString(replacement))
+	}
+	return a.msg("modernize.ioutil.fix", types.ExprString(replacement))
+}
+
+// checkSentinelComparison reports an error variable compared with a
+// sentinel error by == or !=, which misses the sentinel once it is wrapped
+func (a *Analyzer) checkSentinelComparison(bin *ast.BinaryExpr, result *ReviewResult) {
+	if bin.Op != token.EQL && bin.Op != token.NEQ {
+		return
+	}
+	err, sentinel := bin.X, bin.Y
+	if isSentinelError(err) {
+		err, sentinel = sentinel, err
+	}
+	ident, ok := err.(*ast.Ident)
+	if !ok || !isErrorName(ident.Name) || !isSentinelError(sentinel) {
+		return
+	}
+
+	var fixed ast.Expr = &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: ast.NewIdent("errors"), Sel: ast.NewIdent("Is")},
+		Args: []ast.Expr{err, sentinel},
+	}
+	if bin.Op == token.NEQ {
+		fixed = &ast.UnaryExpr{Op: token.NOT, X: fixed}
+	}
+	result.Issues = append(result.Issues, a.modernizeIssue(bin, "medium", "errors-is",
+		a.msg("modernize.errors-is", types.ExprString(sentinel)), a.msg("modernize.errors-is.fix"),
+		beforeAfter(types.ExprString(bin), types.ExprString(fixed))))
+}
+
+// isSentinelError reports whether expr names a sentinel error by
+// convention, such as ErrNotFound, errClosed, io.EOF or sql.ErrNoRows
+func isSentinelError(expr ast.Expr) bool {
+	name := ""
+	switch e := expr.(type) {
+	case *ast.Ident:
+		name = e.Name
+	case *ast.SelectorExpr:
+		if isPkgSelector(e, "io", "EOF") {
+			return true
+		}
+		name = e.Sel.Name
+	default:
+		return false
+	}
+	for _, prefix := range []string{"Err", "err"} {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && rest != "" {
+			r, _ := utf8.DecodeRuneInString(rest)
+			return unicode.IsUpper(r)
+		}
+	}
+	return false
+}
+
+// checkSearchLoop reports a range loop over a slice that returns true when
+// it finds an element equal to a value, or returns the element's index
+// with -1 after the loop, which slices.Contains and slices.Index replace.
+// next is the statement following the loop, or nil.
+func (a *Analyzer) checkSearchLoop(loop *ast.RangeStmt, next ast.Stmt, fields map[string]map[string]string, result *ReviewResult) {
+	if len(loop.Body.List) != 1 || !isSliceExpr(loop.X, fields) {
+		return
+	}
+	ifStmt, ok := loop.Body.List[0].(*ast.IfStmt)
+	if !ok || ifStmt.Init != nil || ifStmt.Else != nil || len(ifStmt.Body.List) != 1 {
+		return
+	}
+	cond, ok := ifStmt.Cond.(*ast.BinaryExpr)
+	if !ok || cond.Op != token.EQL {
+		return
+	}
+	ret, ok := ifStmt.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return
+	}
+
+	key, _ := loop.Key.(*ast.Ident)
+	value, _ := loop.Value.(*ast.Ident)
+	if key != nil && key.Name == "_" {
+		key = nil
+	}
+	if value != nil && value.Name == "_" {
+		value = nil
+	}
+	if loop.Tok != token.DEFINE || (key == nil && value == nil) {
+		return
+	}
+
+	// The element is the value variable, or the slice indexed by the key
+	isElement := func(expr ast.Expr) bool {
+		if value != nil {
+			ident, ok := expr.(*ast.Ident)
+			return ok && ident.Name == value.Name
+		}
+		index, ok := expr.(*ast.IndexExpr)
+		if !ok || types.ExprString(index.X) != types.ExprString(loop.X) {
+			return false
+		}
+		ident, ok := index.Index.(*ast.Ident)
+		return ok && ident.Name == key.Name
+	}
+	target := cond.Y
+	if !isElement(cond.X) {
+		if !isElement(cond.Y) {
+			return
+		}
+		target = cond.X
+	}
+	for _, v := range []*ast.Ident{key, value} {
+		if v != nil && references(target, v.Name) {
+			return
+		}
+	}
+
+	call := func(name string) string {
+		return types.ExprString(&ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: ast.NewIdent("slices"), Sel: ast.NewIdent(name)},
+			Args: []ast.Expr{loop.X, target},
+		})
+	}
+	nextResult := func() string {
+		nextRet, ok := next.(*ast.ReturnStmt)
+		if !ok || len(nextRet.Results) != 1 {
+			return ""
+		}
+		return types.ExprString(nextRet.Results[0])
+	}
+
+	found := types.ExprString(ret.Results[0])
+	switch {
+	case found == "true":
+		before, after := nodeString(a.fset, loop), "if "+call("Contains")+" {\n\treturn true\n}"
+		if nextResult() == "false" {
+			before, after = before+"\n"+nodeString(a.fset, next), "return "+call("Contains")
+		}
+		result.Issues = append(result.Issues, a.modernizeIssue(loop, "low", "slices-contains",
+			a.msg("modernize.slices-loop", "slices.Contains"), a.msg("modernize.slices-loop.fix", call("Contains")),
+			beforeAfter(before, after)))
+	case key != nil && found == key.Name && nextResult() == "-1":
+		result.Issues = append(result.Issues, a.modernizeIssue(loop, "low", "slices-index",
+			a.msg("modernize.slices-loop", "slices.Index"), a.msg("modernize.slices-loop.fix", call("Index")),
+			beforeAfter(nodeString(a.fset, loop)+"\n"+nodeString(a.fset, next), "return "+call("Index"))))
+	}
+}
+
+// isSliceExpr reports whether expr is known to be a slice: a slice literal,
+// a variable or parameter declared with a slice type or value, or a slice
+// field of a struct declared in the file
+func isSliceExpr(expr ast.Expr, fields map[string]map[string]string) bool {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		return referenceKind(e.Type) == "slice"
+	case *ast.Ident:
+		return e.Obj != nil && declaresSlice(e.Obj.Decl, e.Name)
+	case *ast.SelectorExpr:
+		ident, ok := e.X.(*ast.Ident)
+		if !ok || ident.Obj == nil {
+			return false
+		}
+		field, ok := ident.Obj.Decl.(*ast.Field)
+		if !ok {
+			return false
+		}
+		typ := field.Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		name, ok := typ.(*ast.Ident)
+		return ok && fields[name.Name][e.Sel.Name] == "slice"
+	}
+	return false
+}
+
+// declaresSlice reports whether decl declares name as a slice
+func declaresSlice(decl any, name string) bool {
+	switch d := decl.(type) {
+	case *ast.Field:
+		if _, ok := d.Type.(*ast.Ellipsis); ok {
+			return true
+		}
+		return referenceKind(d.Type) == "slice"
+	case *ast.ValueSpec:
+		for i, n := range d.Names {
+			if n.Name == name {
+				return referenceKind(d.Type) == "slice" || (i < len(d.Values) && isSliceValue(d.Values[i]))
+			}
+		}
+	case *ast.AssignStmt:
+		if len(d.Lhs) != len(d.Rhs) {
+			return false
+		}
+		for i, lhs := range d.Lhs {
+			if ident, ok := lhs.(*ast.Ident); ok && ident.Name == name {
+				return isSliceValue(d.Rhs[i])
+			}
+		}
+	}
+	return false
+}
+
+// beforeAfter joins the code of an issue and its modern replacement into
+// an example
+func beforeAfter(before, after string) string {
+	return "// Before\n" + before + "\n\n// After\n" + after
+}
+
+// modernizeIssue builds a modernization issue for node with before and
+// after example code
+func (a *Analyzer) modernizeIssue(node ast.Node, severity, rule, message, suggestion, example string) Issue {
+	return Issue{
+		Type:       "style",
+		Category:   "modernization",
+		Line:       a.getLine(node.Pos()),
+		Column:     a.getColumn(node.Pos()),
+		EndLine:    a.getLine(node.End()),
+		Message:    message,
+		Suggestion: suggestion,
+		Example:    example,
+		Severity:   severity,
+		Rule:       rule,
+	}
+}
@@ -0,0 +1,151 @@
+package codereview
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAnalyzeCode_Modernize(t *testing.T) {
+	code := `package app
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type Set struct {
+	names []string
+}
+
+type myErr struct{}
+
+func (myErr) Error() string { return "mine" }
+
+func (e myErr) Is(target error) bool { return target == ErrNotFound }
+
+func Load(path string, v interface{}) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err == ErrNotFound || err != io.EOF {
+		return nil, err
+	}
+	io.Copy(ioutil.Discard, nil)
+	return data, nil
+}
+
+func Name(s string) string {
+	rand.Seed(time.Now().UnixNano())
+	rand.Seed(42)
+	return strings.Title(s)
+}
+
+func (s *Set) Has(name string) bool {
+	for _, n := range s.names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func Index(names []string, name string) int {
+	for i := range names {
+		if names[i] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func Found(names []string, name string) bool {
+	for _, n := range names {
+		if name == n {
+			return true
+		}
+	}
+	names = nil
+	return false
+}
+
+func Keys(m map[string]int, key string) bool {
+	for k := range m {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func Prefix(names []string, name string) bool {
+	for _, n := range names {
+		if n == name+n {
+			return true
+		}
+	}
+	return false
+}
+`
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, issue := range result.Issues {
+		if issue.Category == "modernization" {
+			got[fmt.Sprintf("%d %s: %s", issue.Line, issue.Rule, issue.Message)] = issue.Example
+		}
+	}
+
+	want := map[string]string{
+		"24 interface-any: interface{} can be written as any since Go 1.18":                                                                   interfaceAnyExample,
+		"25 deprecated-ioutil: ioutil.ReadFile is deprecated since Go 1.16":                                                                   "// Before\nioutil.ReadFile(path)\n\n// After\nos.ReadFile(path)",
+		"26 errors-is: Error is compared with ErrNotFound by == or !=, which fails once the error is wrapped":                                 "// Before\nerr == ErrNotFound\n\n// After\nerrors.Is(err, ErrNotFound)",
+		"26 errors-is: Error is compared with io.EOF by == or !=, which fails once the error is wrapped":                                      "// Before\nerr != io.EOF\n\n// After\n!errors.Is(err, io.EOF)",
+		"29 deprecated-ioutil: ioutil.Discard is deprecated since Go 1.16":                                                                    "// Before\nioutil.Discard\n\n// After\nio.Discard",
+		"34 rand-seed: rand.Seed is deprecated since Go 1.20; the global generator is seeded randomly at startup":                             "// Before\nrand.Seed(time.Now().UnixNano())\n\n// After\n// Remove the call",
+		"35 rand-seed: rand.Seed is deprecated since Go 1.20; the global generator is seeded randomly at startup":                             "// Before\nrand.Seed(42)\n\n// After\nrng := rand.New(rand.NewSource(42))",
+		"36 strings-title: strings.Title is deprecated since Go 1.18; it mishandles Unicode punctuation and ignores the language of the text": "// Before\nstrings.Title(s)\n\n// After\ncases.Title(language.English).String(s)",
+		"40 slices-contains: Loop can be replaced by slices.Contains":                                                                         "// Before\nfor _, n := range s.names {\n\tif n == name {\n\t\treturn true\n\t}\n}\nreturn false\n\n// After\nreturn slices.Contains(s.names, name)",
+		"49 slices-index: Loop can be replaced by slices.Index":                                                                               "// Before\nfor i := range names {\n\tif names[i] == name {\n\t\treturn i\n\t}\n}\nreturn -1\n\n// After\nreturn slices.Index(names, name)",
+		"58 slices-contains: Loop can be replaced by slices.Contains":                                                                         "// Before\nfor _, n := range names {\n\tif name == n {\n\t\treturn true\n\t}\n}\n\n// After\nif slices.Contains(names, name) {\n\treturn true\n}",
+	}
+	for issue, example := range want {
+		gotExample, ok := got[issue]
+		if !ok {
+			t.Errorf("expected issue %q", issue)
+			continue
+		}
+		if gotExample != example {
+			t.Errorf("issue %q: example =\n%s\nwant\n%s", issue, gotExample, example)
+		}
+		delete(got, issue)
+	}
+	for issue := range got {
+		t.Errorf("unexpected modernization issue %q", issue)
+	}
+}
+
+func TestAnalyzeCode_ModernizeShadowedAny(t *testing.T) {
+	code := `package app
+
+type any = interface{}
+
+func Print(v any) {}
+`
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, issue := range result.Issues {
+		if issue.Rule == "interface-any" {
+			t.Errorf("unexpected issue %+v for a package declaring any", issue)
+		}
+	}
+}
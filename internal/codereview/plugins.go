@@ -0,0 +1,146 @@
+package codereview
+
+import (
+	"fmt"
+	"go/ast"
+	"plugin"
+
+	"mcp-go-assistant/pkg/reviewrule"
+)
+
+// LoadPluginRules opens the Go plugins at paths and returns the rules they
+// provide, in order. Every plugin must export reviewrule.Symbol as a
+// func() []reviewrule.Rule, and rule names must be unique.
+func LoadPluginRules(paths []string) ([]reviewrule.Rule, error) {
+	var rules []reviewrule.Rule
+	seen := make(map[string]string)
+	for _, path := range paths {
+		p, err := plugin.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rule plugin %s: %w", path, err)
+		}
+		sym, err := p.Lookup(reviewrule.Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("rule plugin %s: %w", path, err)
+		}
+		provide, ok := sym.(func() []reviewrule.Rule)
+		if !ok {
+			return nil, fmt.Errorf("rule plugin %s: %s has type %T, want func() []reviewrule.Rule", path, reviewrule.Symbol, sym)
+		}
+
+		for _, rule := range provide() {
+			if err := addPluginRule(seen, rule, path); err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// addPluginRule records the name of rule from path in seen, failing for
+// nameless rules and names already taken
+func addPluginRule(seen map[string]string, rule reviewrule.Rule, path string) error {
+	if rule == nil {
+		return fmt.Errorf("rule plugin %s: nil rule", path)
+	}
+	name := rule.Name()
+	if name == "" {
+		return fmt.Errorf("rule plugin %s: rule %T has no name", path, rule)
+	}
+	if other, ok := seen[name]; ok {
+		return fmt.Errorf("rule plugin %s: rule %q is already provided by %s", path, name, other)
+	}
+	seen[name] = path
+	return nil
+}
+
+// CheckRuleNames fails when two external rules, such as rules from Go and
+// wasm plugins, share a name
+func CheckRuleNames(rules []reviewrule.Rule) error {
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if seen[rule.Name()] {
+			return fmt.Errorf("review rule %q is defined more than once", rule.Name())
+		}
+		seen[rule.Name()] = true
+	}
+	return nil
+}
+
+// SetExternalRules sets the rules, typically loaded from plugins, run after
+// the built-in checks
+func (a *Analyzer) SetExternalRules(rules []reviewrule.Rule) {
+	a.external = rules
+}
+
+// runExternalRules runs the external rules over file, turning their
+// findings into issues. A rule that panics is reported as an issue instead
+// of failing the review.
+func (a *Analyzer) runExternalRules(file *ast.File, result *ReviewResult) {
+	input := &reviewrule.File{Fset: a.fset, AST: file}
+	for _, rule := range a.external {
+		findings, err := checkExternalRule(rule, input)
+		if err != nil {
+			result.Issues = append(result.Issues, Issue{
+				Type:     "error",
+				Category: "plugin",
+				Message:  a.msg("plugin.failed", rule.Name(), err),
+				Severity: "low",
+				Rule:     rule.Name(),
+			})
+			continue
+		}
+		for _, f := range findings {
+			result.Issues = append(result.Issues, a.externalIssue(rule.Name(), f))
+		}
+	}
+}
+
+// fallibleRule is a rule that can report why a check failed, such as a
+// wasm module that trapped or timed out
+type fallibleRule interface {
+	checkFile(file *reviewrule.File) ([]reviewrule.Finding, error)
+}
+
+// checkExternalRule runs rule, recovering from a panic
+func checkExternalRule(rule reviewrule.Rule, file *reviewrule.File) (findings []reviewrule.Finding, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	if fr, ok := rule.(fallibleRule); ok {
+		return fr.checkFile(file)
+	}
+	return rule.Check(file), nil
+}
+
+// externalIssue converts a finding of the named rule to an issue
+func (a *Analyzer) externalIssue(name string, f reviewrule.Finding) Issue {
+	issue := Issue{
+		Type:       "warning",
+		Category:   f.Category,
+		Message:    f.Message,
+		Suggestion: f.Suggestion,
+		Severity:   f.Severity,
+		Rule:       name,
+	}
+	if issue.Category == "" {
+		issue.Category = "custom"
+	}
+	switch issue.Severity {
+	case reviewrule.SeverityLow, reviewrule.SeverityMedium, reviewrule.SeverityHigh, reviewrule.SeverityCritical:
+	default:
+		issue.Severity = reviewrule.SeverityMedium
+	}
+	if f.Pos.IsValid() {
+		issue.Line = a.getLine(f.Pos)
+		issue.Column = a.getColumn(f.Pos)
+		issue.EndLine = issue.Line
+	}
+	if f.End.IsValid() {
+		issue.EndLine = a.getLine(f.End)
+	}
+	return issue
+}
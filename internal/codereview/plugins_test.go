@@ -0,0 +1,109 @@
+package codereview
+
+import (
+	"go/ast"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/pkg/reviewrule"
+)
+
+// todoRule reports functions named Todo
+type todoRule struct{}
+
+func (todoRule) Name() string { return "acme-no-todo" }
+
+func (todoRule) Check(file *reviewrule.File) []reviewrule.Finding {
+	var findings []reviewrule.Finding
+	for _, decl := range file.AST.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Name.Name == "Todo" {
+			findings = append(findings, reviewrule.Finding{
+				Pos:      fd.Pos(),
+				End:      fd.End(),
+				Message:  "Todo functions must not be merged",
+				Severity: "bogus",
+			})
+		}
+	}
+	return findings
+}
+
+// panicRule always panics
+type panicRule struct{}
+
+func (panicRule) Name() string { return "acme-panic" }
+
+func (panicRule) Check(*reviewrule.File) []reviewrule.Finding { panic("boom") }
+
+func TestAnalyzeCode_ExternalRules(t *testing.T) {
+	code := `package app
+
+// Todo is unfinished
+func Todo() {
+}
+`
+
+	analyzer := NewAnalyzer(nil, "")
+	analyzer.SetExternalRules([]reviewrule.Rule{todoRule{}, panicRule{}})
+	result, err := analyzer.AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var found, failed bool
+	for _, issue := range result.Issues {
+		switch issue.Rule {
+		case "acme-no-todo":
+			found = true
+			if issue.Line != 4 || issue.EndLine != 5 || issue.Category != "custom" || issue.Severity != "medium" {
+				t.Errorf("unexpected plugin issue %+v", issue)
+			}
+		case "acme-panic":
+			failed = true
+			if !strings.Contains(issue.Message, "boom") {
+				t.Errorf("expected the panic in the message, got %q", issue.Message)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected an issue from the external rule")
+	}
+	if !failed {
+		t.Error("expected the panicking rule to be reported")
+	}
+}
+
+func TestAnalyzeCode_ExternalRulesIgnored(t *testing.T) {
+	code := `package app
+
+//mcp:ignore acme-no-todo // tracked elsewhere
+func Todo() {
+}
+`
+
+	analyzer := NewAnalyzer(nil, "")
+	analyzer.SetExternalRules([]reviewrule.Rule{todoRule{}})
+	result, err := analyzer.AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, issue := range result.Issues {
+		if issue.Rule == "acme-no-todo" {
+			t.Errorf("expected the ignore directive to suppress %+v", issue)
+		}
+	}
+}
+
+func TestLoadPluginRules_Errors(t *testing.T) {
+	if _, err := LoadPluginRules([]string{"testdata/missing.so"}); err == nil {
+		t.Error("expected an error for a missing plugin")
+	}
+
+	seen := map[string]string{}
+	if err := addPluginRule(seen, todoRule{}, "a.so"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := addPluginRule(seen, todoRule{}, "b.so"); err == nil || !strings.Contains(err.Error(), "a.so") {
+		t.Errorf("expected a duplicate rule error naming a.so, got %v", err)
+	}
+}
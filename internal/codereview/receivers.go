@@ -0,0 +1,212 @@
+package codereview
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxValueReceiverBytes is the estimated size above which copying a value
+// receiver on every call costs more than passing a pointer
+const maxValueReceiverBytes = 128
+
+// wordBytes is the size of pointers, ints and types whose size is unknown,
+// assuming a 64-bit platform
+const wordBytes = 8
+
+// genericReceiverNames are receiver names borrowed from other languages,
+// which say nothing about the receiver
+var genericReceiverNames = map[string]bool{"this": true, "self": true, "me": true}
+
+// basicTypeSizes are the sizes of predeclared types other than words
+var basicTypeSizes = map[string]int{
+	"bool": 1, "int8": 1, "uint8": 1, "byte": 1,
+	"int16": 2, "uint16": 2,
+	"int32": 4, "uint32": 4, "rune": 4, "float32": 4,
+	"complex128": 16, "string": 16, "error": 16, "any": 16,
+}
+
+// receiverMethod is a method with what the receiver checks need of it
+type receiverMethod struct {
+	decl    *ast.FuncDecl
+	recv    *ast.Field
+	name    string // Empty for unnamed and blank receivers
+	pointer bool
+}
+
+// checkReceivers reviews the receivers of the methods in file, per type:
+// pointer and value receivers mixed, receivers named differently across
+// methods or with generic names such as this and self, and value receivers
+// of large types declared in the file
+func (a *Analyzer) checkReceivers(file *ast.File, result *ReviewResult) {
+	methods := make(map[string][]receiverMethod)
+	var typeNames []string
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || len(fd.Recv.List) != 1 {
+			continue
+		}
+		recv := fd.Recv.List[0]
+		typeName := receiverTypeName(recv.Type)
+		if typeName == "" {
+			continue
+		}
+		method := receiverMethod{decl: fd, recv: recv}
+		if len(recv.Names) == 1 && recv.Names[0].Name != "_" {
+			method.name = recv.Names[0].Name
+		}
+		_, method.pointer = recv.Type.(*ast.StarExpr)
+		if methods[typeName] == nil {
+			typeNames = append(typeNames, typeName)
+		}
+		methods[typeName] = append(methods[typeName], method)
+	}
+
+	specs := make(map[string]*ast.TypeSpec)
+	for _, decl := range file.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				specs[ts.Name.Name] = ts
+			}
+		}
+	}
+
+	for _, typeName := range typeNames {
+		a.checkReceiverKinds(typeName, methods[typeName], specs, result)
+		a.checkReceiverNames(typeName, methods[typeName], result)
+	}
+}
+
+// checkReceiverKinds reports the value receivers of a type that also has
+// pointer receivers, and otherwise value receivers of a type estimated
+// larger than maxValueReceiverBytes
+func (a *Analyzer) checkReceiverKinds(typeName string, methods []receiverMethod, specs map[string]*ast.TypeSpec, result *ReviewResult) {
+	pointers := 0
+	for _, m := range methods {
+		if m.pointer {
+			pointers++
+		}
+	}
+
+	size := 0
+	if spec, ok := specs[typeName]; ok {
+		size = estimatedSize(spec.Type, specs, map[string]bool{typeName: true})
+	}
+	for _, m := range methods {
+		switch {
+		case m.pointer:
+		case pointers > 0:
+			result.Issues = append(result.Issues, a.receiverIssue(m.recv, "warning", "structure", "medium", "mixed-receivers",
+				a.msg("receivers.mixed", funcName(m.decl), pointers, typeName), a.msg("receivers.mixed.fix", typeName)))
+		case size > maxValueReceiverBytes:
+			result.Issues = append(result.Issues, a.receiverIssue(m.recv, "warning", "performance", "low", "large-value-receiver",
+				a.msg("receivers.large", funcName(m.decl), size), a.msg("receivers.large.fix", typeName)))
+		}
+	}
+}
+
+// checkReceiverNames reports receivers with generic names, and receivers
+// named differently from the name most methods of the type use
+func (a *Analyzer) checkReceiverNames(typeName string, methods []receiverMethod, result *ReviewResult) {
+	counts := make(map[string]int)
+	common := ""
+	for _, m := range methods {
+		if m.name == "" || genericReceiverNames[m.name] {
+			continue
+		}
+		counts[m.name]++
+		// Ties go to the name used first
+		if counts[m.name] > counts[common] {
+			common = m.name
+		}
+	}
+
+	for _, m := range methods {
+		switch {
+		case m.name == "":
+		case genericReceiverNames[m.name]:
+			suggested := common
+			if suggested == "" {
+				suggested = receiverNameFor(typeName)
+			}
+			result.Issues = append(result.Issues, a.receiverIssue(m.recv, "style", "naming", "low", "receiver-name",
+				a.msg("receivers.generic", m.name), a.msg("receivers.generic.fix", suggested)))
+		case m.name != common:
+			result.Issues = append(result.Issues, a.receiverIssue(m.recv, "style", "naming", "low", "receiver-name",
+				a.msg("receivers.inconsistent", funcName(m.decl), m.name, typeName, common), a.msg("receivers.inconsistent.fix", common)))
+		}
+	}
+}
+
+// receiverNameFor returns the conventional receiver name of a type: its
+// first letter, lowercased
+func receiverNameFor(typeName string) string {
+	r, _ := utf8.DecodeRuneInString(typeName)
+	return string(unicode.ToLower(r))
+}
+
+// estimatedSize estimates the size in bytes of a value of type expr,
+// resolving the types declared in the file through specs and ignoring
+// padding. Types from other packages count as a word; seen guards against
+// recursive types.
+func estimatedSize(expr ast.Expr, specs map[string]*ast.TypeSpec, seen map[string]bool) int {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if size, ok := basicTypeSizes[t.Name]; ok {
+			return size
+		}
+		spec, ok := specs[t.Name]
+		if !ok || seen[t.Name] {
+			return wordBytes
+		}
+		seen[t.Name] = true
+		defer delete(seen, t.Name)
+		return estimatedSize(spec.Type, specs, seen)
+	case *ast.ParenExpr:
+		return estimatedSize(t.X, specs, seen)
+	case *ast.ArrayType:
+		if t.Len == nil {
+			// Pointer, length and capacity
+			return 3 * wordBytes
+		}
+		lit, ok := t.Len.(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT {
+			return wordBytes
+		}
+		n, err := strconv.Atoi(strings.ReplaceAll(lit.Value, "_", ""))
+		if err != nil {
+			return wordBytes
+		}
+		return n * estimatedSize(t.Elt, specs, seen)
+	case *ast.StructType:
+		size := 0
+		for _, field := range t.Fields.List {
+			n := max(len(field.Names), 1)
+			size += n * estimatedSize(field.Type, specs, seen)
+		}
+		return size
+	case *ast.InterfaceType:
+		return 2 * wordBytes
+	}
+	// Pointers, maps, channels, functions and types of other packages
+	return wordBytes
+}
+
+// receiverIssue builds an issue for the receiver of a method
+func (a *Analyzer) receiverIssue(recv *ast.Field, issueType, category, severity, rule, message, suggestion string) Issue {
+	return Issue{
+		Type:       issueType,
+		Category:   category,
+		Line:       a.getLine(recv.Pos()),
+		Column:     a.getColumn(recv.Pos()),
+		EndLine:    a.getLine(recv.End()),
+		Message:    message,
+		Suggestion: suggestion,
+		Severity:   severity,
+		Rule:       rule,
+	}
+}
@@ -0,0 +1,126 @@
+package codereview
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestAnalyzeCode_Receivers(t *testing.T) {
+	code := `package app
+
+import "time"
+
+type Cache struct {
+	items map[string]string
+}
+
+func (c *Cache) Get(key string) string { return c.items[key] }
+
+func (c *Cache) Set(key, value string) { c.items[key] = value }
+
+func (cache Cache) Len() int { return len(cache.items) }
+
+func (this *Cache) Clear() { this.items = nil }
+
+type Point struct {
+	X, Y float64
+}
+
+func (p Point) Add(q Point) Point { return Point{p.X + q.X, p.Y + q.Y} }
+
+func (self Point) Scale(f float64) Point { return Point{self.X * f, self.Y * f} }
+
+func (Point) Origin() Point { return Point{} }
+
+type Record struct {
+	ID      [16]byte
+	Name    string
+	Tags    []string
+	Created time.Time
+	Body    [64]byte
+	Meta    Point
+	Next    *Record
+}
+
+func (r Record) Title() string { return r.Name }
+
+type Small struct {
+	Name string
+}
+
+func (s Small) String() string { return s.Name }
+`
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[string]bool{}
+	for _, issue := range result.Issues {
+		switch issue.Rule {
+		case "mixed-receivers", "receiver-name", "large-value-receiver":
+			got[fmt.Sprintf("%d %s: %s (%s)", issue.Line, issue.Rule, issue.Message, issue.Suggestion)] = true
+		}
+	}
+
+	want := []string{
+		"13 mixed-receivers: Cache.Len has a value receiver, but 3 other methods of Cache use pointer receivers (Use a pointer receiver, *Cache, for every method so the type has one method set)",
+		"13 receiver-name: Receiver of Cache.Len is named cache, but the other methods of Cache name it c (Rename the receiver to c)",
+		"15 receiver-name: Receiver name 'this' is a generic name that says nothing about the receiver (Use a short name reflecting the type, such as c)",
+		"23 receiver-name: Receiver name 'self' is a generic name that says nothing about the receiver (Use a short name reflecting the type, such as p)",
+		"37 large-value-receiver: Record.Title copies its receiver of about 152 bytes on every call (Use a pointer receiver, *Record)",
+	}
+	for _, issue := range want {
+		if !got[issue] {
+			t.Errorf("expected issue %q", issue)
+		}
+		delete(got, issue)
+	}
+	for issue := range got {
+		t.Errorf("unexpected receiver issue %q", issue)
+	}
+}
+
+func TestEstimatedSize(t *testing.T) {
+	code := `package app
+
+type List struct {
+	Value int
+	Next  *List
+}
+
+type Grid [4][4]int32
+
+type Tree struct {
+	Left, Right Tree
+	Pair        struct{ A, B string }
+}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "", code, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	specs := make(map[string]*ast.TypeSpec)
+	for _, decl := range file.Decls {
+		for _, spec := range decl.(*ast.GenDecl).Specs {
+			ts := spec.(*ast.TypeSpec)
+			specs[ts.Name.Name] = ts
+		}
+	}
+
+	tests := map[string]int{
+		"List": 16,
+		"Grid": 64,
+		// Recursive fields count as a word
+		"Tree": 2*wordBytes + 32,
+	}
+	for name, want := range tests {
+		if got := estimatedSize(specs[name].Type, specs, map[string]bool{name: true}); got != want {
+			t.Errorf("estimatedSize(%s) = %d, want %d", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package codereview
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"mcp-go-assistant/internal/i18n"
+)
+
+// Supported output formats for code review results
+const (
+	OutputFormatText     = "text"
+	OutputFormatJSON     = "json"
+	OutputFormatCompact  = "compact"
+	OutputFormatMarkdown = "markdown"
+)
+
+// supportedOutputFormats lists the output formats for error messages
+const supportedOutputFormats = "text, json, compact, markdown"
+
+// severityOrder lists issue severities from most to least severe
+var severityOrder = []string{"critical", "high", "medium", "low"}
+
+// IsValidSeverity reports whether severity is one of the issue severities
+func IsValidSeverity(severity string) bool {
+	return slices.Contains(severityOrder, severity)
+}
+
+// SeverityAtLeast reports whether severity is min or more severe
+func SeverityAtLeast(severity, min string) bool {
+	return severityRank(severity) <= severityRank(min)
+}
+
+// isValidOutputFormat reports whether format is a supported output format
+func isValidOutputFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case "", OutputFormatText, OutputFormatJSON, OutputFormatCompact, OutputFormatMarkdown:
+		return true
+	}
+	return false
+}
+
+// FormatResult renders a review result in the output format requested by
+// params. The structured result is returned separately, so smaller formats
+// only shrink the text content.
+func FormatResult(result *ReviewResult, params CodeReviewParams) string {
+	switch strings.ToLower(params.OutputFormat) {
+	case OutputFormatJSON:
+		return result.JSON()
+	case OutputFormatCompact:
+		return result.Compact()
+	case OutputFormatMarkdown:
+		return FormatMarkdown(result, params.Language)
+	default:
+		return result.String()
+	}
+}
+
+// JSON returns the review result as minified JSON
+func (r *ReviewResult) JSON() string {
+	jsonData, _ := json.Marshal(r)
+	return string(jsonData)
+}
+
+// Compact returns the summary followed by one line per issue, most severe
+// first, in the form [file:]line:column: severity rule: message
+func (r *ReviewResult) Compact() string {
+	var sb strings.Builder
+	sb.WriteString(r.Summary)
+	sb.WriteString("\n")
+	for _, warning := range r.Warnings {
+		sb.WriteString("warning: " + warning + "\n")
+	}
+	for _, severity := range severityOrder {
+		for _, issue := range r.Issues {
+			if issue.Severity != severity {
+				continue
+			}
+			if issue.File != "" {
+				sb.WriteString(issue.File + ":")
+			}
+			sb.WriteString(fmt.Sprintf("%d:%d: %s %s: %s\n", issue.Line, issue.Column, issue.Severity, issue.Rule, issue.Message))
+		}
+	}
+	return sb.String()
+}
+
+// FormatMarkdown renders a review result as a markdown report suitable for
+// posting as a pull request comment
+func FormatMarkdown(result *ReviewResult, lang string) string {
+	t := func(key string, args ...interface{}) string {
+		return messages.Translate(i18n.Normalize(lang), key, args...)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("## " + t("report.title") + "\n\n")
+	sb.WriteString(scoreBadge(result.Score) + "\n\n")
+	sb.WriteString(result.Summary + "\n\n")
+	for _, warning := range result.Warnings {
+		sb.WriteString("> **" + t("report.warning") + ":** " + warning + "\n\n")
+	}
+
+	// Issues grouped by severity
+	sb.WriteString("### " + t("report.issues") + "\n\n")
+	if len(result.Issues) == 0 {
+		sb.WriteString(t("report.no-issues") + "\n\n")
+	}
+	for _, severity := range severityOrder {
+		var group []Issue
+		for _, issue := range result.Issues {
+			if issue.Severity == severity {
+				group = append(group, issue)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("#### %s (%d)\n\n", t("severity."+severity), len(group)))
+		for _, issue := range group {
+			location := ""
+			if issue.Line > 0 {
+				location = fmt.Sprintf(" (%s %d)", t("report.line"), issue.Line)
+			}
+			if issue.Uncovered {
+				location += " _(" + t("report.uncovered") + ")_"
+			}
+			sb.WriteString(fmt.Sprintf("- **%s**%s `%s`\n", issue.Message, location, issue.Rule))
+			if issue.Suggestion != "" {
+				sb.WriteString(fmt.Sprintf("  - %s: %s\n", t("report.suggestion"), issue.Suggestion))
+			}
+			if issue.Example != "" {
+				sb.WriteString(fmt.Sprintf("  - %s:\n\n    ```go\n", t("report.example")))
+				for _, l := range strings.Split(issue.Example, "\n") {
+					sb.WriteString("    " + l + "\n")
+				}
+				sb.WriteString("    ```\n")
+			}
+			if issue.Snippet != "" {
+				sb.WriteString("\n  ```go\n")
+				for _, l := range strings.Split(issue.Snippet, "\n") {
+					sb.WriteString("  " + l + "\n")
+				}
+				sb.WriteString("  ```\n")
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// Suggestions
+	if len(result.Suggestions) > 0 {
+		sb.WriteString("### " + t("report.suggestions") + "\n\n")
+		for _, s := range result.Suggestions {
+			sb.WriteString(fmt.Sprintf("- **%s**: %s", s.Category, s.Message))
+			if s.Impact != "" {
+				sb.WriteString(fmt.Sprintf(" _(%s)_", s.Impact))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	// Metrics table
+	sb.WriteString("### " + t("report.metrics") + "\n\n")
+	sb.WriteString(fmt.Sprintf("| %s | %s |\n", t("report.metric"), t("report.value")))
+	sb.WriteString("| --- | --- |\n")
+	sb.WriteString(fmt.Sprintf("| %s | %d |\n", t("metric.lines-of-code"), result.Metrics.LinesOfCode))
+	sb.WriteString(fmt.Sprintf("| %s | %d |\n", t("metric.cyclomatic-complexity"), result.Metrics.CyclomaticComplexity))
+	sb.WriteString(fmt.Sprintf("| %s | %d |\n", t("metric.function-count"), result.Metrics.FunctionCount))
+	sb.WriteString(fmt.Sprintf("| %s | %d |\n", t("metric.type-count"), result.Metrics.TypeCount))
+	sb.WriteString(fmt.Sprintf("| %s | %s |\n", t("metric.test-coverage"), result.Metrics.TestCoverage))
+	sb.WriteString(fmt.Sprintf("| %s | %s |\n", t("metric.maintainability"), result.Metrics.Maintainability))
+
+	return sb.String()
+}
+
+// scoreBadge returns a shields.io badge image for the review score
+func scoreBadge(score int) string {
+	color := "red"
+	switch {
+	case score >= 80:
+		color = "brightgreen"
+	case score >= 60:
+		color = "yellow"
+	}
+	return fmt.Sprintf("![score](https://img.shields.io/badge/score-%d%%2F100-%s)", score, color)
+}
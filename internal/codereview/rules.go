@@ -0,0 +1,49 @@
+package codereview
+
+import (
+	"strings"
+	"sync"
+
+	"mcp-go-assistant/internal/i18n"
+)
+
+// RuleSet is the request-independent part of an analyzer: the guidelines
+// and the custom rules derived from them. A rule set is never modified after
+// it is built, so one instance can be shared by concurrent reviews.
+type RuleSet struct {
+	guidelines []string
+	noPanic    bool // A guideline forbids calling panic
+
+	truncatedFile string // Guidelines file cut at the size limit
+	truncatedAt   int64  // Bytes of truncatedFile that were read
+}
+
+// NewRuleSet builds the rule set for guidelines
+func NewRuleSet(guidelines []string) *RuleSet {
+	rules := &RuleSet{guidelines: guidelines}
+	for _, guideline := range guidelines {
+		if strings.Contains(strings.ToLower(guideline), "no panic") {
+			rules.noPanic = true
+		}
+	}
+	return rules
+}
+
+// defaultRuleSet is built from the default guidelines on first use
+var defaultRuleSet = sync.OnceValue(func() *RuleSet {
+	return NewRuleSet(GetDefaultGuidelines())
+})
+
+// DefaultRuleSet returns the shared rule set for the default guidelines
+func DefaultRuleSet() *RuleSet {
+	return defaultRuleSet()
+}
+
+// warnings returns the problems met loading the rule set, reported with
+// every review that uses it
+func (r *RuleSet) warnings(lang string) []string {
+	if r.truncatedAt == 0 {
+		return nil
+	}
+	return []string{messages.Translate(i18n.Normalize(lang), "guidelines.truncated", r.truncatedFile, r.truncatedAt)}
+}
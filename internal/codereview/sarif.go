@@ -0,0 +1,135 @@
+package codereview
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+)
+
+// SARIF log format constants
+const (
+	sarifVersion  = "2.1.0"
+	sarifSchema   = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolName = "mcp-go-assistant"
+)
+
+// sarifLog is a SARIF 2.1.0 log with the parts code scanning services read
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID         string          `json:"id"`
+	Properties sarifProperties `json:"properties"`
+}
+
+type sarifProperties struct {
+	Category string `json:"category,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+}
+
+// SARIF returns issues as a SARIF 2.1.0 log of a single run, for code
+// scanning services such as GitHub code scanning. Issues are located in
+// their File, which should be relative to the repository root; version is
+// the version of the reviewing tool.
+func SARIF(issues []Issue, version string) ([]byte, error) {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: sarifToolName, Version: version, Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+	ruleIndex := map[string]int{}
+	for _, issue := range issues {
+		index, ok := ruleIndex[issue.Rule]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndex[issue.Rule] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:         issue.Rule,
+				Properties: sarifProperties{Category: issue.Category},
+			})
+		}
+
+		text := issue.Message
+		if issue.Suggestion != "" {
+			text += "\n\n" + issue.Suggestion
+		}
+		result := sarifResult{
+			RuleID:    issue.Rule,
+			RuleIndex: index,
+			Level:     sarifLevel(issue.Severity),
+			Message:   sarifMessage{Text: text},
+		}
+		if issue.File != "" {
+			location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(issue.File)}}
+			if issue.Line > 0 {
+				location.Region = &sarifRegion{StartLine: issue.Line, StartColumn: issue.Column}
+				if issue.EndLine >= issue.Line {
+					location.Region.EndLine = issue.EndLine
+				}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: location}}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	return json.MarshalIndent(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}}, "", "  ")
+}
+
+// sarifLevel maps an issue severity to a SARIF result level
+func sarifLevel(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,69 @@
+package codereview
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSARIF(t *testing.T) {
+	issues := []Issue{
+		{File: "pkg/a.go", Line: 3, Column: 2, EndLine: 5, Severity: "high", Rule: "unchecked-error", Category: "errors", Message: "Error not checked", Suggestion: "Check it"},
+		{File: "pkg/b.go", Line: 7, Severity: "medium", Rule: "naming", Category: "naming", Message: "Rename it"},
+		{File: "pkg/b.go", Line: 9, Severity: "low", Rule: "unchecked-error", Message: "Error not checked"},
+		{Severity: "critical", Rule: "package", Message: "Not on a line"},
+	}
+	data, err := SARIF(issues, "v1.2.3")
+	if err != nil {
+		t.Fatalf("SARIF() error = %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("invalid SARIF log: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log %s", data)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "mcp-go-assistant" || run.Tool.Driver.Version != "v1.2.3" {
+		t.Errorf("unexpected driver %+v", run.Tool.Driver)
+	}
+	if len(run.Tool.Driver.Rules) != 3 || run.Tool.Driver.Rules[0].ID != "unchecked-error" || run.Tool.Driver.Rules[0].Properties.Category != "errors" {
+		t.Errorf("expected each rule once in order of appearance, got %+v", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != len(issues) {
+		t.Fatalf("expected %d results, got %d", len(issues), len(run.Results))
+	}
+
+	first := run.Results[0]
+	region := first.Locations[0].PhysicalLocation.Region
+	if first.Level != "error" || first.Message.Text != "Error not checked\n\nCheck it" ||
+		first.Locations[0].PhysicalLocation.ArtifactLocation.URI != "pkg/a.go" ||
+		region.StartLine != 3 || region.StartColumn != 2 || region.EndLine != 5 {
+		t.Errorf("unexpected result %+v", first)
+	}
+	if levels := []string{run.Results[1].Level, run.Results[2].Level}; levels[0] != "warning" || levels[1] != "note" {
+		t.Errorf("expected medium issues as warnings and low ones as notes, got %v", levels)
+	}
+	if run.Results[2].RuleIndex != 0 {
+		t.Errorf("expected the repeated rule to share index 0, got %d", run.Results[2].RuleIndex)
+	}
+	if len(run.Results[3].Locations) != 0 {
+		t.Errorf("expected no location for an issue without a file, got %+v", run.Results[3].Locations)
+	}
+}
+
+func TestSARIF_NoIssues(t *testing.T) {
+	data, err := SARIF(nil, "")
+	if err != nil {
+		t.Fatalf("SARIF() error = %v", err)
+	}
+	var log map[string]any
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("invalid SARIF log: %v", err)
+	}
+	results := log["runs"].([]any)[0].(map[string]any)["results"]
+	if results == nil || len(results.([]any)) != 0 {
+		t.Errorf("expected an empty results array, got %v", results)
+	}
+}
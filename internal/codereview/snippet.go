@@ -0,0 +1,40 @@
+package codereview
+
+import "strings"
+
+// attachSnippets fills in the source snippet for each issue that has a line
+// number and no snippet yet
+func (a *Analyzer) attachSnippets(issues []Issue, code string) {
+	lines := strings.Split(code, "\n")
+
+	for i := range issues {
+		issue := &issues[i]
+		if issue.Line <= 0 || issue.Snippet != "" {
+			continue
+		}
+		issue.Snippet, issue.SnippetStartLine = extractSnippet(lines, issue.Line, issue.EndLine, a.contextLines)
+	}
+}
+
+// extractSnippet returns the source lines from line to endLine (1-based) with
+// context lines on each side, along with the first line number included.
+// Nodes spanning more than maxSnippetSpan lines are truncated to their start.
+func extractSnippet(lines []string, line, endLine, context int) (string, int) {
+	if line <= 0 || line > len(lines) {
+		return "", 0
+	}
+	if endLine < line || endLine-line >= maxSnippetSpan {
+		endLine = line
+	}
+
+	start := line - context
+	if start < 1 {
+		start = 1
+	}
+	end := endLine + context
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	return strings.Join(lines[start-1:end], "\n"), start
+}
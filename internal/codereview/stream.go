@@ -0,0 +1,62 @@
+package codereview
+
+// IssueBatch is a group of issues streamed to the client while code is
+// still being reviewed. The final batch carries the issues found after the
+// checks, such as API changes, along with the summary and score. Streamed
+// issues are preliminary: coverage can still raise their severity, so the
+// tool result is authoritative.
+type IssueBatch struct {
+	Check   string  `json:"check,omitempty"` // Check that found the issues; empty for the final batch
+	Done    int     `json:"done"`
+	Total   int     `json:"total"`
+	Issues  []Issue `json:"issues"`
+	Final   bool    `json:"final,omitempty"`
+	Summary string  `json:"summary,omitempty"` // Set on the final batch
+	Score   int     `json:"score,omitempty"`   // Set on the final batch
+}
+
+// StreamFunc receives issue batches while code is reviewed
+type StreamFunc func(batch IssueBatch)
+
+// SetStream sets the function receiving issue batches. Each check sends
+// one batch; the final batch is sent by finishStream.
+func (a *Analyzer) SetStream(stream StreamFunc) {
+	a.stream = stream
+}
+
+// streamIssues sends the issues found by check with their snippets
+// attached. finishStream sends the rest of the result, so the issues
+// streamed before it must make up the start of the result's issues.
+func (a *Analyzer) streamIssues(check string, done int, issues []Issue, code string) {
+	if a.stream == nil {
+		return
+	}
+
+	a.attachSnippets(issues, code)
+	a.streamed += len(issues)
+	a.stream(IssueBatch{
+		Check:  check,
+		Done:   done,
+		Total:  len(analysisChecks) + 1,
+		Issues: append([]Issue{}, issues...),
+	})
+}
+
+// finishStream sends the issues not yet streamed together with the summary
+// and score of the completed review
+func (a *Analyzer) finishStream(result *ReviewResult) {
+	if a.stream == nil {
+		return
+	}
+
+	total := len(analysisChecks) + 1
+	a.stream(IssueBatch{
+		Done:    total,
+		Total:   total,
+		Issues:  append([]Issue{}, result.Issues[a.streamed:]...),
+		Final:   true,
+		Summary: result.Summary,
+		Score:   result.Score,
+	})
+	a.streamed = len(result.Issues)
+}
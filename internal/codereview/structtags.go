@@ -0,0 +1,436 @@
+package codereview
+
+import (
+	"go/ast"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"mcp-go-assistant/internal/editdistance"
+)
+
+// tagOptions are the options accepted after the name by each validated
+// struct tag key
+var tagOptions = map[string][]string{
+	"json":         {"omitempty", "omitzero", "string", "inline"},
+	"yaml":         {"omitempty", "flow", "inline"},
+	"mapstructure": {"omitempty", "omitzero", "squash", "remain"},
+	"db":           nil,
+}
+
+// tagKeys are the validated struct tag keys in report order
+var tagKeys = []string{"json", "yaml", "mapstructure", "db"}
+
+// otherTagKeys are struct tag keys of other libraries, never reported as
+// misspellings of the validated keys
+var otherTagKeys = map[string]bool{
+	"bson": true, "toml": true, "xml": true, "hcl": true, "csv": true,
+	"form": true, "query": true, "uri": true, "url": true, "header": true,
+	"env": true, "validate": true, "binding": true, "gorm": true, "msgpack": true,
+	"protobuf": true, "schema": true, "default": true, "jsonschema": true,
+}
+
+// marshalPackages maps the import paths of encoding packages to the struct
+// tag key they read
+var marshalPackages = map[string]string{
+	"encoding/json":                       "json",
+	"gopkg.in/yaml.v2":                    "yaml",
+	"gopkg.in/yaml.v3":                    "yaml",
+	"github.com/goccy/go-yaml":            "yaml",
+	"github.com/mitchellh/mapstructure":   "mapstructure",
+	"github.com/go-viper/mapstructure/v2": "mapstructure",
+}
+
+// marshalFuncs are package functions and encoder methods whose arguments
+// are encoded or decoded
+var marshalFuncs = map[string]bool{
+	"Marshal":       true,
+	"MarshalIndent": true,
+	"Unmarshal":     true,
+	"Decode":        true,
+	"Encode":        true,
+}
+
+// structTag is a key:"value" pair of a struct tag
+type structTag struct {
+	key, value string
+}
+
+// checkStructTags validates json, yaml, mapstructure and db struct tags:
+// malformed syntax, misspelled keys and options, fields encoding to the same
+// name, and exported fields without a tag in types the file marshals
+func (a *Analyzer) checkStructTags(file *ast.File, result *ReviewResult) {
+	marshaled := marshaledTypes(file)
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		a.checkStructFields(spec.Name.Name, st, marshaled[spec.Name.Name], result)
+		return true
+	})
+}
+
+// checkStructFields validates the tags of one struct type. keys are the tag
+// keys of the encodings the file marshals the type with.
+func (a *Analyzer) checkStructFields(typeName string, st *ast.StructType, keys map[string]bool, result *ReviewResult) {
+	fieldTags := make([]map[string]string, len(st.Fields.List))
+	tagged := make(map[string]bool)
+	for i, field := range st.Fields.List {
+		fieldTags[i] = make(map[string]string)
+		if field.Tag == nil {
+			continue
+		}
+		raw, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		tags, ok := parseStructTag(raw)
+		if !ok {
+			fieldTags[i] = nil
+			result.Issues = append(result.Issues, a.tagIssue(field, "high", "malformed-struct-tag",
+				a.msg("struct-tags.malformed", raw), a.msg("struct-tags.malformed.fix")))
+			continue
+		}
+		for _, tag := range tags {
+			if _, known := tagOptions[tag.key]; !known {
+				if otherTagKeys[tag.key] {
+					continue
+				}
+				if guess := closestWord(tag.key, tagKeys); guess != "" {
+					result.Issues = append(result.Issues, a.tagIssue(field, "high", "struct-tag-typo",
+						a.msg("struct-tags.unknown-key", tag.key, guess), a.msg("struct-tags.did-you-mean", guess)))
+				}
+				continue
+			}
+			fieldTags[i][tag.key] = tag.value
+			tagged[tag.key] = true
+			a.checkTagOptions(field, tag, result)
+		}
+	}
+
+	// Names only clash for encodings the struct is tagged for or marshaled
+	// with; an untagged struct is not meant for the others
+	names := make(map[string]map[string]*ast.Field)
+	for i, field := range st.Fields.List {
+		if fieldTags[i] == nil {
+			continue
+		}
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			for _, key := range tagKeys {
+				value, ok := fieldTags[i][key]
+				if !ok && keys[key] {
+					result.Issues = append(result.Issues, a.tagIssue(field, "low", "missing-struct-tag",
+						a.msg("struct-tags.missing", ident.Name, typeName, key),
+						a.msg("struct-tags.missing.fix", key, lowerFirst(ident.Name))))
+				}
+				name := encodedName(key, value, ident.Name)
+				if name == "" || (!tagged[key] && !keys[key]) {
+					continue
+				}
+				if names[key] == nil {
+					names[key] = make(map[string]*ast.Field)
+				}
+				if first, dup := names[key][name]; dup {
+					result.Issues = append(result.Issues, a.tagIssue(field, "high", "duplicate-struct-tag",
+						a.msg("struct-tags.duplicate", ident.Name, key, name, a.getLine(first.Pos())),
+						a.msg("struct-tags.duplicate.fix")))
+					continue
+				}
+				names[key][name] = field
+			}
+		}
+	}
+}
+
+// checkTagOptions reports options a tag key does not support
+func (a *Analyzer) checkTagOptions(field *ast.Field, tag structTag, result *ReviewResult) {
+	parts := strings.Split(tag.value, ",")
+	for _, opt := range parts[1:] {
+		if opt == "" || contains(tagOptions[tag.key], opt) {
+			continue
+		}
+		// json v2 format options such as format:RFC3339
+		if tag.key == "json" && strings.HasPrefix(opt, "format:") {
+			continue
+		}
+		suggestion := a.msg("struct-tags.unknown-option.fix", tag.key, strings.Join(tagOptions[tag.key], ", "))
+		if guess := closestWord(opt, tagOptions[tag.key]); guess != "" {
+			suggestion = a.msg("struct-tags.did-you-mean", guess)
+		} else if len(tagOptions[tag.key]) == 0 {
+			suggestion = a.msg("struct-tags.no-options.fix", tag.key)
+		}
+		result.Issues = append(result.Issues, a.tagIssue(field, "high", "struct-tag-typo",
+			a.msg("struct-tags.unknown-option", tag.key, opt), suggestion))
+	}
+}
+
+// tagIssue builds a struct tag issue positioned at the field's tag, or the
+// field itself when it has none
+func (a *Analyzer) tagIssue(field *ast.Field, severity, rule, message, suggestion string) Issue {
+	var node ast.Node = field
+	if field.Tag != nil {
+		node = field.Tag
+	}
+	return Issue{
+		Type:       "warning",
+		Category:   "struct-tags",
+		Line:       a.getLine(node.Pos()),
+		Column:     a.getColumn(node.Pos()),
+		EndLine:    a.getLine(node.End()),
+		Message:    message,
+		Suggestion: suggestion,
+		Severity:   severity,
+		Rule:       rule,
+	}
+}
+
+// parseStructTag splits a struct tag into its key:"value" pairs following
+// the reflect.StructTag convention, and reports whether it is well formed
+func parseStructTag(tag string) ([]structTag, bool) {
+	var tags []structTag
+	for {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			return tags, true
+		}
+
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return nil, false
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return nil, false
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			return nil, false
+		}
+		tags = append(tags, structTag{key: key, value: value})
+		tag = tag[i+1:]
+		if tag != "" && tag[0] != ' ' {
+			return nil, false
+		}
+	}
+}
+
+// encodedName returns the name a field is encoded under for key, given the
+// tag value, or "" when the field is skipped. Encodings that match names
+// case-insensitively or lowercase untagged names are compared in lower case.
+func encodedName(key, value, field string) string {
+	name, _, _ := strings.Cut(value, ",")
+	if value == "-" {
+		return ""
+	}
+	if name == "" {
+		name = field
+		if key == "yaml" || key == "db" {
+			name = strings.ToLower(name)
+		}
+	}
+	if key == "mapstructure" {
+		name = strings.ToLower(name)
+	}
+	return name
+}
+
+// marshaledTypes returns, for each type name, the struct tag keys of the
+// encodings the file passes values of that type to
+func marshaledTypes(file *ast.File) map[string]map[string]bool {
+	encoders := make(map[string]string)
+	for path, name := range importNames(file) {
+		if key, ok := marshalPackages[path]; ok {
+			encoders[name] = key
+		}
+	}
+	types := make(map[string]map[string]bool)
+	if len(encoders) == 0 {
+		return types
+	}
+
+	vars := varTypes(file)
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		key := marshalKey(call, encoders)
+		if key == "" {
+			return true
+		}
+		for _, arg := range call.Args {
+			name := exprTypeName(arg, vars)
+			if name == "" {
+				continue
+			}
+			if types[name] == nil {
+				types[name] = make(map[string]bool)
+			}
+			types[name][key] = true
+		}
+		return true
+	})
+	return types
+}
+
+// marshalKey returns the struct tag key read by call, such as
+// json.Marshal(v) or json.NewEncoder(w).Encode(v), or "" for other calls
+func marshalKey(call *ast.CallExpr, encoders map[string]string) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !marshalFuncs[sel.Sel.Name] {
+		return ""
+	}
+	x := sel.X
+	if inner, ok := x.(*ast.CallExpr); ok {
+		x = inner.Fun
+		innerSel, ok := x.(*ast.SelectorExpr)
+		if !ok || !strings.HasPrefix(innerSel.Sel.Name, "New") {
+			return ""
+		}
+		x = innerSel.X
+	}
+	if pkg, ok := x.(*ast.Ident); ok {
+		return encoders[pkg.Name]
+	}
+	return ""
+}
+
+// varTypes maps variable and parameter names in file to the name of their
+// declared or composite literal type. Names declared with different types
+// in different functions keep the last one seen.
+func varTypes(file *ast.File) map[string]string {
+	vars := make(map[string]string)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.Field:
+			if name := typeName(node.Type); name != "" {
+				for _, ident := range node.Names {
+					vars[ident.Name] = name
+				}
+			}
+		case *ast.ValueSpec:
+			name := typeName(node.Type)
+			for i, ident := range node.Names {
+				if name == "" && i < len(node.Values) {
+					vars[ident.Name] = exprTypeName(node.Values[i], nil)
+				} else if name != "" {
+					vars[ident.Name] = name
+				}
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					if name := exprTypeName(node.Rhs[i], nil); name != "" {
+						vars[ident.Name] = name
+					}
+				}
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// exprTypeName returns the type name of a composite literal, its address,
+// or a variable in vars
+func exprTypeName(expr ast.Expr, vars map[string]string) string {
+	switch e := expr.(type) {
+	case *ast.UnaryExpr:
+		return exprTypeName(e.X, vars)
+	case *ast.CompositeLit:
+		return typeName(e.Type)
+	case *ast.Ident:
+		return vars[e.Name]
+	}
+	return ""
+}
+
+// typeName returns the local type name of a named type, a pointer to it, or
+// a slice, array or map of it
+func typeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return typeName(t.X)
+	case *ast.ArrayType:
+		return typeName(t.Elt)
+	case *ast.MapType:
+		return typeName(t.Value)
+	}
+	return ""
+}
+
+// closestWord returns the word in words that s probably misspells, or ""
+func closestWord(s string, words []string) string {
+	lower := strings.ToLower(s)
+	best, bestDist := "", 3
+	if len(s) <= 4 {
+		bestDist = 2
+	}
+	for _, w := range words {
+		if lower == w {
+			return w
+		}
+		d := editdistance.Levenshtein(lower, w)
+		if sameLetters(lower, w) {
+			d = 1 // Transposed letters such as "josn"
+		}
+		if d < bestDist {
+			best, bestDist = w, d
+		}
+	}
+	return best
+}
+
+// sameLetters reports whether a and b are anagrams of each other
+func sameLetters(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x, y := []byte(a), []byte(b)
+	slices.Sort(x)
+	slices.Sort(y)
+	return string(x) == string(y)
+}
+
+// contains reports whether list holds s
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// lowerFirst lowercases the first letter of name
+func lowerFirst(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
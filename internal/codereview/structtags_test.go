@@ -0,0 +1,86 @@
+package codereview
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAnalyzeCode_StructTags(t *testing.T) {
+	code := "package app\n" +
+		"\n" +
+		"import \"encoding/json\"\n" +
+		"\n" +
+		"type User struct {\n" +
+		"\tID    int    `json:\"id\"`\n" +
+		"\tName  string `json:\"name,omitempy\"`\n" +
+		"\tEmail string `json:\"id\"`\n" +
+		"\tAge   int    `json: \"age\"`\n" +
+		"\tRole  string `josn:\"role\"`\n" +
+		"\tNotes string\n" +
+		"\tKey   string `bson:\"key\" db:\"key,omitempty\"`\n" +
+		"\tskip  bool\n" +
+		"}\n" +
+		"\n" +
+		"type Row struct {\n" +
+		"\tA string `db:\"a\" yaml:\"a\"`\n" +
+		"\tB string `db:\"b\" yaml:\"A\"`\n" +
+		"}\n" +
+		"\n" +
+		"func Encode(u *User) ([]byte, error) {\n" +
+		"\treturn json.Marshal(u)\n" +
+		"}\n"
+
+	result, err := NewAnalyzer(nil, "").AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[string]bool{}
+	for _, issue := range result.Issues {
+		if issue.Category == "struct-tags" {
+			got[fmt.Sprintf("%d %s: %s (%s)", issue.Line, issue.Rule, issue.Message, issue.Suggestion)] = true
+		}
+	}
+
+	want := []string{
+		"7 struct-tag-typo: Unknown json tag option 'omitempy' (Did you mean 'omitempty'?)",
+		"8 duplicate-struct-tag: Field 'Email' has the same json name 'id' as the field on line 6; one of them is silently ignored (Give each field a distinct name in its tag, or skip one with \"-\")",
+		"9 malformed-struct-tag: Struct tag \"json: \\\"age\\\"\" is malformed; it must be space-separated key:\"value\" pairs (Write the tag as key:\"value\" pairs separated by single spaces, e.g. json:\"name,omitempty\" db:\"name\")",
+		"10 struct-tag-typo: Struct tag key 'josn' looks like a misspelling of 'json' (Did you mean 'json'?)",
+		"10 missing-struct-tag: Exported field 'Role' of 'User' has no json tag, but the type is marshaled in this file (Add a tag to fix the encoded name, e.g. json:\"role\", or \"-\" to skip the field)",
+		"11 missing-struct-tag: Exported field 'Notes' of 'User' has no json tag, but the type is marshaled in this file (Add a tag to fix the encoded name, e.g. json:\"notes\", or \"-\" to skip the field)",
+		"12 struct-tag-typo: Unknown db tag option 'omitempty' (Remove the option; db tags only take a name)",
+		"12 missing-struct-tag: Exported field 'Key' of 'User' has no json tag, but the type is marshaled in this file (Add a tag to fix the encoded name, e.g. json:\"key\", or \"-\" to skip the field)",
+	}
+	for _, issue := range want {
+		if !got[issue] {
+			t.Errorf("expected issue %q", issue)
+		}
+		delete(got, issue)
+	}
+	for issue := range got {
+		t.Errorf("unexpected struct tag issue %q", issue)
+	}
+}
+
+func TestParseStructTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []structTag
+		ok   bool
+	}{
+		{tag: ``, ok: true},
+		{tag: `json:"a,omitempty" db:"a"`, want: []structTag{{"json", "a,omitempty"}, {"db", "a"}}, ok: true},
+		{tag: `json:"a\"b"`, want: []structTag{{"json", `a"b`}}, ok: true},
+		{tag: `json:a`},
+		{tag: `json:"a"db:"b"`},
+		{tag: `json:"a`},
+		{tag: `:"a"`},
+	}
+	for _, tt := range tests {
+		got, ok := parseStructTag(tt.tag)
+		if ok != tt.ok || fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("parseStructTag(%q) = %v, %v; want %v, %v", tt.tag, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package codereview
+
+import "fmt"
+
+// DefaultThresholds returns the limits used when neither the request nor
+// the server sets them
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		FunctionLines: 50,
+		Parameters:    5,
+		StructFields:  10,
+		Complexity:    10,
+	}
+}
+
+// WithDefaults returns t with its zero fields taken from defaults
+func (t Thresholds) WithDefaults(defaults Thresholds) Thresholds {
+	if t.FunctionLines == 0 {
+		t.FunctionLines = defaults.FunctionLines
+	}
+	if t.Parameters == 0 {
+		t.Parameters = defaults.Parameters
+	}
+	if t.StructFields == 0 {
+		t.StructFields = defaults.StructFields
+	}
+	if t.Complexity == 0 {
+		t.Complexity = defaults.Complexity
+	}
+	return t
+}
+
+// validate rejects negative limits
+func (t Thresholds) validate() error {
+	limits := []struct {
+		name  string
+		value int
+	}{
+		{"function_lines", t.FunctionLines},
+		{"parameters", t.Parameters},
+		{"struct_fields", t.StructFields},
+		{"complexity", t.Complexity},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			return fmt.Errorf("thresholds.%s must not be negative: %d", limit.name, limit.value)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package codereview
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"mcp-go-assistant/pkg/reviewrule"
+)
 
 // CodeReviewParams represents the parameters for the code-review tool
 type CodeReviewParams struct {
@@ -8,15 +12,51 @@ type CodeReviewParams struct {
 	GuidelinesFile    string `json:"guidelines_file,omitempty" jsonschema:"description:Optional path to markdown file with coding guidelines"`
 	GuidelinesContent string `json:"guidelines_content,omitempty" jsonschema:"description:Optional markdown content with coding guidelines"`
 	Hint              string `json:"hint,omitempty" jsonschema:"description:Optional hint or specific focus area for the review"`
+	WorkingDir        string `json:"working_dir,omitempty" jsonschema:"description:Optional registered workspace directory; when set every .go file under it is reviewed instead of go_code"`
+	PreviousCode      string `json:"previous_code,omitempty" jsonschema:"description:Optional previous version of the code to check for exported API changes"`
+	Language          string `json:"language,omitempty" jsonschema:"description:Optional language for messages and summaries: 'en', 'ja' or 'es' (defaults to server setting)"`
+	ContextLines      int    `json:"context_lines,omitempty" jsonschema:"description:Optional number of context lines around issue snippets (default 2, max 10)"`
+	OutputFormat      string `json:"output_format,omitempty" jsonschema:"description:Optional output format of the text content: 'text' (default; indented JSON), 'json' (minified), 'compact' (one line per issue) or 'markdown' for a report suitable for PR comments"`
+	Audience          string `json:"audience,omitempty" jsonschema:"description:Optional reader of the summary: 'human' (default) for prose or 'llm' for a compact deterministic block with counts by severity and category, the top 5 issues with rule IDs and a list of next actions, also returned as the structured brief"`
+	CoverageProfile   string `json:"coverage_profile,omitempty" jsonschema:"description:Optional contents of a go test -coverprofile file used to prioritize issues in untested code"`
+	CoverageFile      string `json:"coverage_file,omitempty" jsonschema:"description:Optional path of go_code's file in the coverage profile, e.g. 'pkg/file.go'; not needed when the profile covers a single file"`
+	RunCoverage       bool   `json:"run_coverage,omitempty" jsonschema:"description:Optional; run go test with coverage in working_dir instead of passing coverage_profile"`
+	IdempotencyKey    string `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+
+	Thresholds Thresholds `json:"thresholds,omitempty" jsonschema:"description:Optional limits for function length, parameter count, struct fields and cyclomatic complexity; unset limits use the server settings"`
+
+	// Set by the server when go_code was spooled to a temporary file; not
+	// part of the tool schema
+	GoCodeFile string `json:"-"`
+	// Set by the server to the number of bytes read from guidelines_file;
+	// zero uses DefaultGuidelinesMaxSize. Not part of the tool schema.
+	GuidelinesMaxSize int64 `json:"-"`
+	// Set by the server to the rules loaded from plugins; not part of the
+	// tool schema
+	ExternalRules []reviewrule.Rule `json:"-"`
+}
+
+// Thresholds are the limits above which structure and complexity issues
+// are reported. Zero fields fall back to defaults.
+type Thresholds struct {
+	FunctionLines int `json:"function_lines,omitempty" jsonschema:"description:Lines a function body may span (default 50)"`
+	Parameters    int `json:"parameters,omitempty" jsonschema:"description:Parameters a function may take (default 5)"`
+	StructFields  int `json:"struct_fields,omitempty" jsonschema:"description:Fields a struct may declare (default 10)"`
+	Complexity    int `json:"complexity,omitempty" jsonschema:"description:Cyclomatic complexity a function may reach (default 10)"`
 }
 
 // ReviewResult represents the complete result of a code review
 type ReviewResult struct {
-	Summary     string       `json:"summary"`
-	Issues      []Issue      `json:"issues"`
-	Suggestions []Suggestion `json:"suggestions"`
-	Score       int          `json:"score"` // 0-100 score
-	Metrics     Metrics      `json:"metrics"`
+	Summary     string           `json:"summary"`
+	Issues      []Issue          `json:"issues"`
+	Suggestions []Suggestion     `json:"suggestions"`
+	Score       int              `json:"score"` // 0-100 score
+	Metrics     Metrics          `json:"metrics"`
+	APIChanges  []APIChange      `json:"api_changes,omitempty"`
+	Suppressed  map[string]int   `json:"suppressed,omitempty"` // Issues removed by ignore directives in the source, by rule
+	Workspace   *WorkspaceReport `json:"workspace,omitempty"`
+	Warnings    []string         `json:"warnings,omitempty"` // Problems with the inputs that did not stop the review, e.g. truncated guidelines
+	Brief       *Brief           `json:"brief,omitempty"`    // Set for the llm audience, whose summary renders it
 }
 
 // Issue represents a code issue found during review
@@ -25,10 +65,18 @@ type Issue struct {
 	Category   string `json:"category"`   // "naming", "structure", "performance", etc.
 	Line       int    `json:"line"`       // Line number (0 if not specific)
 	Column     int    `json:"column"`     // Column number (0 if not specific)
+	EndLine    int    `json:"end_line"`   // Last line of the offending node (0 if not specific)
 	Message    string `json:"message"`    // Description of the issue
 	Suggestion string `json:"suggestion"` // How to fix it
 	Severity   string `json:"severity"`   // "low", "medium", "high", "critical"
 	Rule       string `json:"rule"`       // Which Go best practice rule
+
+	Example string `json:"example,omitempty"` // Corrected code, if applicable
+
+	Snippet          string `json:"snippet,omitempty"`            // Offending source with surrounding context
+	SnippetStartLine int    `json:"snippet_start_line,omitempty"` // Line number of the first snippet line
+	File             string `json:"file,omitempty"`               // Relative file path for workspace reviews
+	Uncovered        bool   `json:"uncovered,omitempty"`          // In a function without test coverage; severity was raised
 }
 
 // Suggestion represents a general improvement suggestion
@@ -49,6 +97,17 @@ type Metrics struct {
 	Maintainability      string `json:"maintainability"` // "low", "medium", "high"
 }
 
+// APIChange represents a change to the exported API between two versions
+type APIChange struct {
+	Symbol   string `json:"symbol"`        // Qualified name, e.g. "Type.Method"
+	Kind     string `json:"kind"`          // "function", "method", "type", "field", "interface-method", "constant", "variable"
+	Change   string `json:"change"`        // "added", "removed", "changed"
+	Breaking bool   `json:"breaking"`      // Whether existing callers or implementations break
+	Old      string `json:"old,omitempty"` // Previous signature
+	New      string `json:"new,omitempty"` // New signature
+	Line     int    `json:"line"`          // Line in the new code (0 if removed)
+}
+
 // String returns a formatted JSON string of the ReviewResult
 func (r *ReviewResult) String() string {
 	jsonData, _ := json.MarshalIndent(r, "", "  ")
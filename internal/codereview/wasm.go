@@ -0,0 +1,246 @@
+package codereview
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+
+	"mcp-go-assistant/pkg/reviewrule"
+)
+
+// Wasm rule ABI. A module exports its linear memory and two functions:
+//
+//	alloc(size i32) -> i32        returns the address of size writable bytes
+//	check(ptr i32, len i32) -> i64
+//
+// check receives the UTF-8 JSON document {"ast": <file>, "config": <config>}
+// at ptr, with the file encoded as described at encodeAST and config the
+// module's configuration, omitted when it has none. It returns the address
+// of its JSON output in the high 32 bits and its length in the low 32 bits.
+// The output is {"issues": [...]}, each issue with line, column, end_line,
+// message, suggestion, severity and category, or {"error": "..."}.
+//
+// Modules may import WASI; they get no arguments, environment, clock
+// access beyond WASI's, or file system, and their output is discarded.
+// Each check runs in a fresh instance.
+const (
+	wasmAllocExport = "alloc"
+	wasmCheckExport = "check"
+	wasmPageSize    = 64 * 1024
+)
+
+// WasmModule is a wasm rule module to load
+type WasmModule struct {
+	Path   string
+	Name   string          // Rule name; defaults to the file name without extension
+	Config json.RawMessage // Optional JSON configuration passed with every file
+}
+
+// WasmLimits bound the resources of every wasm rule check
+type WasmLimits struct {
+	MaxMemory int64         // Bytes of linear memory a module may use
+	Timeout   time.Duration // Time a single check may run
+}
+
+// wasmRule is a review rule implemented by a wasm module
+type wasmRule struct {
+	name    string
+	runtime wazero.Runtime
+	module  wazero.CompiledModule
+	config  json.RawMessage
+	timeout time.Duration
+}
+
+// wasmOutput is the JSON a module's check function returns
+type wasmOutput struct {
+	Issues []wasmIssue `json:"issues"`
+	Error  string      `json:"error"`
+}
+
+// wasmIssue is an issue reported by a wasm module
+type wasmIssue struct {
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	EndLine    int    `json:"end_line"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+	Severity   string `json:"severity"`
+	Category   string `json:"category"`
+}
+
+// LoadWasmRules compiles the wasm modules and returns a rule for each, in
+// order. Every module gets its own runtime limited by limits.
+func LoadWasmRules(ctx context.Context, modules []WasmModule, limits WasmLimits) ([]reviewrule.Rule, error) {
+	if limits.MaxMemory <= 0 || limits.Timeout <= 0 {
+		return nil, errors.New("wasm rule limits must be positive")
+	}
+	pages := uint32((limits.MaxMemory + wasmPageSize - 1) / wasmPageSize)
+
+	var rules []reviewrule.Rule
+	for _, m := range modules {
+		rule, err := loadWasmRule(ctx, m, pages, limits.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// loadWasmRule compiles one module and checks that it implements the ABI
+func loadWasmRule(ctx context.Context, m WasmModule, pages uint32, timeout time.Duration) (*wasmRule, error) {
+	if len(m.Config) > 0 && !json.Valid(m.Config) {
+		return nil, fmt.Errorf("wasm rule %s has invalid JSON config", m.Path)
+	}
+	bin, err := os.ReadFile(m.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wasm rule %s: %w", m.Path, err)
+	}
+
+	config := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(pages).
+		WithCloseOnContextDone(true)
+	runtime := wazero.NewRuntimeWithConfig(ctx, config)
+	wasi_snapshot_preview1.MustInstantiate(ctx, runtime)
+
+	compiled, err := runtime.CompileModule(ctx, bin)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("failed to compile wasm rule %s: %w", m.Path, err)
+	}
+	exports := compiled.ExportedFunctions()
+	for _, name := range []string{wasmAllocExport, wasmCheckExport} {
+		if _, ok := exports[name]; !ok {
+			runtime.Close(ctx)
+			return nil, fmt.Errorf("wasm rule %s does not export %s", m.Path, name)
+		}
+	}
+
+	name := m.Name
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(m.Path), filepath.Ext(m.Path))
+	}
+	return &wasmRule{name: name, runtime: runtime, module: compiled, config: m.Config, timeout: timeout}, nil
+}
+
+// Name returns the rule name
+func (r *wasmRule) Name() string {
+	return r.name
+}
+
+// Check runs the module over file, dropping findings when it fails. The
+// analyzer calls checkFile instead to report the failure.
+func (r *wasmRule) Check(file *reviewrule.File) []reviewrule.Finding {
+	findings, _ := r.checkFile(file)
+	return findings
+}
+
+// checkFile runs the module over file in a fresh instance
+func (r *wasmRule) checkFile(file *reviewrule.File) ([]reviewrule.Finding, error) {
+	input, err := encodeAST(file.Fset, file.AST)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode AST: %w", err)
+	}
+	input = append([]byte(`{"ast":`), input...)
+	if len(r.config) > 0 {
+		input = append(append(input, `,"config":`...), r.config...)
+	}
+	input = append(input, '}')
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	// An empty name lets checks of different files run concurrently
+	mod, err := r.runtime.InstantiateModule(ctx, r.module, wazero.NewModuleConfig().
+		WithName("").
+		WithStartFunctions("_initialize"))
+	if err != nil {
+		return nil, wasmError(ctx, err)
+	}
+	defer mod.Close(context.Background())
+
+	output, err := callWasmCheck(ctx, mod, input)
+	if err != nil {
+		return nil, wasmError(ctx, err)
+	}
+
+	var out wasmOutput
+	if err := json.Unmarshal(output, &out); err != nil {
+		return nil, fmt.Errorf("invalid output: %w", err)
+	}
+	if out.Error != "" {
+		return nil, errors.New(out.Error)
+	}
+
+	tokFile := file.Fset.File(file.AST.Pos())
+	findings := make([]reviewrule.Finding, 0, len(out.Issues))
+	for _, issue := range out.Issues {
+		findings = append(findings, reviewrule.Finding{
+			Pos:        linePos(tokFile, issue.Line, issue.Column),
+			End:        linePos(tokFile, issue.EndLine, 1),
+			Message:    issue.Message,
+			Suggestion: issue.Suggestion,
+			Severity:   issue.Severity,
+			Category:   issue.Category,
+		})
+	}
+	return findings, nil
+}
+
+// callWasmCheck copies input into the module and returns the output of its
+// check function
+func callWasmCheck(ctx context.Context, mod api.Module, input []byte) ([]byte, error) {
+	res, err := mod.ExportedFunction(wasmAllocExport).Call(ctx, uint64(len(input)))
+	if err != nil {
+		return nil, err
+	}
+	ptr := uint32(res[0])
+	if !mod.Memory().Write(ptr, input) {
+		return nil, fmt.Errorf("%s returned an address out of memory", wasmAllocExport)
+	}
+
+	res, err = mod.ExportedFunction(wasmCheckExport).Call(ctx, uint64(ptr), uint64(len(input)))
+	if err != nil {
+		return nil, err
+	}
+	output, ok := mod.Memory().Read(uint32(res[0]>>32), uint32(res[0]))
+	if !ok {
+		return nil, fmt.Errorf("%s returned output out of memory", wasmCheckExport)
+	}
+	return output, nil
+}
+
+// wasmError explains an error from running a module, which is a timeout
+// when ctx expired
+func wasmError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.New("timed out")
+	}
+	return err
+}
+
+// linePos returns the position of line and column in file, or token.NoPos
+// when the line does not exist
+func linePos(file *token.File, line, column int) token.Pos {
+	if file == nil || line < 1 || line > file.LineCount() {
+		return token.NoPos
+	}
+	pos := file.LineStart(line)
+	if column > 1 {
+		pos += token.Pos(column - 1)
+	}
+	if int(pos) > file.Base()+file.Size() {
+		return token.NoPos
+	}
+	return pos
+}
@@ -0,0 +1,161 @@
+package codereview
+
+import (
+	"context"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// wasmTestModule assembles a module implementing the rule ABI: alloc
+// returns address 1024 and check runs checkBody, with output stored at
+// address 0
+func wasmTestModule(checkBody []byte, output string) []byte {
+	section := func(id byte, content ...byte) []byte {
+		return append([]byte{id, byte(len(content))}, content...)
+	}
+	name := func(s string) []byte { return append([]byte{byte(len(s))}, s...) }
+
+	mod := []byte("\x00asm\x01\x00\x00\x00")
+	// Types: (i32) -> i32 and (i32, i32) -> i64
+	mod = append(mod, section(1, 2, 0x60, 1, 0x7f, 1, 0x7f, 0x60, 2, 0x7f, 0x7f, 1, 0x7e)...)
+	mod = append(mod, section(3, 2, 0, 1)...)
+	mod = append(mod, section(5, 1, 0, 1)...)
+
+	exports := []byte{3}
+	exports = append(append(exports, name("memory")...), 2, 0)
+	exports = append(append(exports, name("alloc")...), 0, 0)
+	exports = append(append(exports, name("check")...), 0, 1)
+	mod = append(mod, section(7, exports...)...)
+
+	alloc := []byte{0, 0x41, 0x80, 0x08, 0x0b} // i32.const 1024
+	check := append([]byte{0}, checkBody...)
+	code := []byte{2, byte(len(alloc))}
+	code = append(append(code, alloc...), byte(len(check)))
+	mod = append(mod, section(10, append(code, check...)...)...)
+
+	data := append([]byte{1, 0, 0x41, 0, 0x0b}, name(output)...)
+	return append(mod, section(11, data...)...)
+}
+
+// wasmReturn is a function body returning the i64 constant n
+func wasmReturn(n int) []byte {
+	body := []byte{0x42}
+	for {
+		b := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 && b&0x40 == 0 {
+			return append(body, b, 0x0b)
+		}
+		body = append(body, b|0x80)
+	}
+}
+
+// writeWasm writes module to a file in a temporary directory
+func writeWasm(t *testing.T, name string, module []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, module, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestWasmRules(t *testing.T) {
+	output := `{"issues":[{"line":3,"column":6,"end_line":4,"message":"from wasm","severity":"high","category":"acme"}]}`
+	failing := `{"error":"unsupported file"}`
+	// loop br 0 end, then return 0
+	loop := append([]byte{0x03, 0x40, 0x0c, 0x00, 0x0b}, wasmReturn(0)...)
+
+	rules, err := LoadWasmRules(context.Background(), []WasmModule{
+		{Path: writeWasm(t, "acme-rule.wasm", wasmTestModule(wasmReturn(len(output)), output))},
+		{Path: writeWasm(t, "fail.wasm", wasmTestModule(wasmReturn(len(failing)), failing)), Name: "acme-fail"},
+		{Path: writeWasm(t, "loop.wasm", wasmTestModule(loop, "")), Name: "acme-loop"},
+	}, WasmLimits{MaxMemory: 1 << 20, Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	code := `package app
+
+func Run() {
+}
+`
+	analyzer := NewAnalyzer(nil, "")
+	analyzer.SetExternalRules(rules)
+	result, err := analyzer.AnalyzeCode(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[string]Issue{}
+	for _, issue := range result.Issues {
+		got[issue.Rule] = issue
+	}
+	if issue := got["acme-rule"]; issue.Message != "from wasm" || issue.Line != 3 || issue.Column != 6 || issue.EndLine != 4 || issue.Severity != "high" || issue.Category != "acme" {
+		t.Errorf("unexpected wasm issue %+v", issue)
+	}
+	if issue := got["acme-fail"]; !strings.Contains(issue.Message, "unsupported file") || issue.Category != "plugin" {
+		t.Errorf("expected the module error to be reported, got %+v", issue)
+	}
+	if issue := got["acme-loop"]; !strings.Contains(issue.Message, "timed out") {
+		t.Errorf("expected the endless module to time out, got %+v", issue)
+	}
+}
+
+func TestLoadWasmRules_Errors(t *testing.T) {
+	limits := WasmLimits{MaxMemory: 1 << 20, Timeout: time.Second}
+	if _, err := LoadWasmRules(context.Background(), []WasmModule{{Path: "testdata/missing.wasm"}}, limits); err == nil {
+		t.Error("expected an error for a missing module")
+	}
+	invalid := writeWasm(t, "invalid.wasm", []byte("not wasm"))
+	if _, err := LoadWasmRules(context.Background(), []WasmModule{{Path: invalid}}, limits); err == nil {
+		t.Error("expected an error for an invalid module")
+	}
+	noExports := writeWasm(t, "empty.wasm", []byte("\x00asm\x01\x00\x00\x00"))
+	if _, err := LoadWasmRules(context.Background(), []WasmModule{{Path: noExports}}, limits); err == nil || !strings.Contains(err.Error(), "alloc") {
+		t.Errorf("expected an error for a module without the ABI exports, got %v", err)
+	}
+	if _, err := LoadWasmRules(context.Background(), []WasmModule{{Path: noExports, Config: []byte("{")}}, limits); err == nil || !strings.Contains(err.Error(), "config") {
+		t.Errorf("expected an error for invalid config, got %v", err)
+	}
+	if _, err := LoadWasmRules(context.Background(), nil, WasmLimits{}); err == nil {
+		t.Error("expected an error for zero limits")
+	}
+}
+
+func TestEncodeAST(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", "package app\n\nvar x = 1\n", parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := encodeAST(fset, file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded struct {
+		Type  string `json:"_type"`
+		Name  struct{ Name string }
+		Decls []struct {
+			Type string      `json:"_type"`
+			Pos  astPosition `json:"_pos"`
+			Tok  string
+		}
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if decoded.Type != "File" || decoded.Name.Name != "app" || len(decoded.Decls) != 1 {
+		t.Fatalf("unexpected AST %s", data)
+	}
+	if decl := decoded.Decls[0]; decl.Type != "GenDecl" || decl.Tok != "var" || decl.Pos != (astPosition{Line: 3, Column: 1}) {
+		t.Errorf("unexpected declaration %+v", decl)
+	}
+}
@@ -0,0 +1,397 @@
+package codereview
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"mcp-go-assistant/internal/history"
+	"mcp-go-assistant/internal/i18n"
+)
+
+// Workspace review limits
+const (
+	DefaultWorkspaceMaxFiles = 500
+	maxWorkspaceTopIssues    = 20
+	maxPackageTopIssues      = 5
+)
+
+// defaultWorkspaceIgnores are directory names always skipped during a walk
+var defaultWorkspaceIgnores = []string{"vendor", "testdata"}
+
+// WorkspaceOptions controls which directories a workspace review may visit
+// and where its scores are recorded
+type WorkspaceOptions struct {
+	Roots    []string       // Registered workspace roots; the working directory must be inside one
+	Ignore   []string       // Extra glob patterns matched against names and relative paths
+	MaxFiles int            // Maximum number of files reviewed in one request
+	History  *history.Store // Records a snapshot of every review when set
+}
+
+// ProgressFunc receives progress updates while a workspace is reviewed
+type ProgressFunc func(done, total int, file string)
+
+// WorkspaceReport summarizes a workspace-wide review
+type WorkspaceReport struct {
+	Root     string          `json:"root"`
+	Files    int             `json:"files"`
+	Packages []PackageRollup `json:"packages"`
+}
+
+// PackageRollup aggregates review results for a single package directory
+type PackageRollup struct {
+	Path      string         `json:"path"`
+	Files     int            `json:"files"`
+	Issues    int            `json:"issues"`
+	Score     int            `json:"score"` // Average score of files in the package
+	TopIssues []Issue        `json:"top_issues"`
+	Design    *PackageDesign `json:"design,omitempty"`
+}
+
+// PerformWorkspaceReview reviews every Go file under params.WorkingDir and
+// returns a per-package rollup. Progress is reported after each file when
+// progress is non-nil.
+func PerformWorkspaceReview(ctx context.Context, params CodeReviewParams, opts WorkspaceOptions, progress ProgressFunc) (*ReviewResult, error) {
+	if params.WorkingDir == "" {
+		return nil, fmt.Errorf("working_dir parameter is required")
+	}
+	if err := ValidateOptions(params.Language, params.OutputFormat, params.Audience, params.Thresholds); err != nil {
+		return nil, err
+	}
+
+	root, err := resolveWorkspaceRoot(params.WorkingDir, opts.Roots)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := collectGoFiles(root, opts.Ignore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk workspace: %v", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files found in %s", root)
+	}
+	maxFiles := opts.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = DefaultWorkspaceMaxFiles
+	}
+	if len(files) > maxFiles {
+		return nil, fmt.Errorf("workspace contains %d Go files, exceeding the limit of %d", len(files), maxFiles)
+	}
+
+	rules, err := loadRuleSet(params)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage, err := loadCoverage(ctx, params, root)
+	if err != nil {
+		return nil, err
+	}
+	var covTotal, covCovered int
+
+	fileResults := make(map[string]*ReviewResult, len(files))
+	designFiles := make([]designFile, 0, len(files))
+	for i, rel := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		code, err := os.ReadFile(filepath.Join(root, rel))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", rel, err)
+		}
+
+		analyzer := newConfiguredAnalyzer(rules, params)
+		if blocks, ok := coverage.blocksFor(rel); ok {
+			analyzer.setCoverage(blocks)
+			total, covered := statementCoverage(blocks, 0, math.MaxInt)
+			covTotal += total
+			covCovered += covered
+		}
+		result, err := analyzer.AnalyzeCode(string(code))
+		if err != nil {
+			return nil, fmt.Errorf("code analysis failed for %s: %v", rel, err)
+		}
+		for j := range result.Issues {
+			result.Issues[j].File = filepath.ToSlash(rel)
+		}
+		fileResults[rel] = result
+		designFiles = append(designFiles, designFile{path: filepath.ToSlash(rel), code: string(code)})
+
+		if progress != nil {
+			progress(i+1, len(files), filepath.ToSlash(rel))
+		}
+	}
+
+	contextLines := DefaultContextLines
+	if params.ContextLines > 0 {
+		contextLines = min(params.ContextLines, MaxContextLines)
+	}
+	designs, designIssues := designPackages(designFiles, readModulePath(root), params.Language, contextLines)
+	for _, issue := range designIssues {
+		result := fileResults[filepath.FromSlash(issue.File)]
+		result.Issues = append(result.Issues, issue)
+	}
+
+	overall := rollupWorkspace(root, files, fileResults, designs, params.Language)
+	overall.Warnings = rules.warnings(params.Language)
+	if coverage != nil {
+		overall.Metrics.TestCoverage = formatCoverage(covTotal, covCovered)
+	}
+	var issues []Issue
+	for _, rel := range files {
+		issues = append(issues, fileResults[rel].Issues...)
+	}
+	if opts.History != nil {
+		if err := opts.History.Record(root, workspaceSnapshot(overall, issues)); err != nil {
+			overall.Warnings = append(overall.Warnings, messages.Translate(i18n.Normalize(params.Language), "workspace.history-failed", err))
+		}
+	}
+	applyAudience(overall, issues, params.Audience)
+	return overall, nil
+}
+
+// WorkspaceTrend returns how the reviews of the workspace at
+// params.WorkingDir changed over the runs recorded in opts.History
+func WorkspaceTrend(params history.TrendParams, opts WorkspaceOptions) (*history.Trend, error) {
+	if params.WorkingDir == "" {
+		return nil, fmt.Errorf("working_dir parameter is required")
+	}
+	if opts.History == nil {
+		return nil, fmt.Errorf("review history is not enabled")
+	}
+	root, err := resolveWorkspaceRoot(params.WorkingDir, opts.Roots)
+	if err != nil {
+		return nil, err
+	}
+	return opts.History.Trend(root, params.Runs)
+}
+
+// workspaceSnapshot returns the scores and every issue of a workspace review
+// for its history
+func workspaceSnapshot(overall *ReviewResult, issues []Issue) history.Snapshot {
+	snapshot := history.Snapshot{
+		Score:    overall.Score,
+		Files:    overall.Workspace.Files,
+		Packages: make([]history.PackageScore, 0, len(overall.Workspace.Packages)),
+		Issues:   make([]history.Issue, 0, len(issues)),
+	}
+	for _, pkg := range overall.Workspace.Packages {
+		snapshot.Packages = append(snapshot.Packages, history.PackageScore{Path: pkg.Path, Score: pkg.Score, Issues: pkg.Issues})
+	}
+	for _, issue := range issues {
+		snapshot.Issues = append(snapshot.Issues, history.Issue{
+			File:     issue.File,
+			Line:     issue.Line,
+			Rule:     issue.Rule,
+			Severity: issue.Severity,
+			Message:  issue.Message,
+		})
+	}
+	return snapshot
+}
+
+// resolveWorkspaceRoot returns the absolute working directory after checking
+// that it lies within one of the registered roots
+func resolveWorkspaceRoot(dir string, roots []string) (string, error) {
+	if len(roots) == 0 {
+		return "", fmt.Errorf("workspace review requires at least one registered workspace root")
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("invalid working directory: %v", err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("working directory not found: %s", dir)
+	}
+
+	for _, root := range roots {
+		rootAbs, err := filepath.Abs(root)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(rootAbs, abs)
+		if err != nil {
+			continue
+		}
+		if rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))) {
+			return abs, nil
+		}
+	}
+
+	return "", fmt.Errorf("working directory %s is not inside a registered workspace", dir)
+}
+
+// collectGoFiles returns the relative paths of Go files under root in
+// lexical order, skipping hidden, vendor, testdata and ignored paths
+func collectGoFiles(root string, ignore []string) ([]string, error) {
+	var files []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		name := d.Name()
+		if d.IsDir() {
+			if strings.HasPrefix(name, ".") || containsString(defaultWorkspaceIgnores, name) || matchesAny(ignore, name, rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if strings.HasSuffix(name, ".go") && !matchesAny(ignore, name, rel) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+
+	return files, err
+}
+
+// matchesAny reports whether name or rel matches any of the glob patterns
+func matchesAny(patterns []string, name, rel string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether s is in list
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// rollupWorkspace aggregates per-file results into package rollups with
+// their designs, and an overall result carrying the most severe issues
+func rollupWorkspace(root string, files []string, fileResults map[string]*ReviewResult, designs []PackageDesign, lang string) *ReviewResult {
+	packages := make(map[string]*PackageRollup)
+	var pkgOrder []string
+	packageScores := make(map[string]int)
+
+	overall := &ReviewResult{
+		Issues:      []Issue{},
+		Suggestions: []Suggestion{},
+		Metrics:     Metrics{TestCoverage: "unknown", Maintainability: "high"},
+	}
+
+	var allIssues []Issue
+	scoreSum := 0
+	for _, rel := range files {
+		result := fileResults[rel]
+		dir := filepath.ToSlash(filepath.Dir(rel))
+
+		pkg, ok := packages[dir]
+		if !ok {
+			pkg = &PackageRollup{Path: dir, TopIssues: []Issue{}}
+			packages[dir] = pkg
+			pkgOrder = append(pkgOrder, dir)
+		}
+		pkg.Files++
+		pkg.Issues += len(result.Issues)
+		pkg.TopIssues = append(pkg.TopIssues, result.Issues...)
+		packageScores[dir] += result.Score
+
+		allIssues = append(allIssues, result.Issues...)
+		scoreSum += result.Score
+		for rule, n := range result.Suppressed {
+			if overall.Suppressed == nil {
+				overall.Suppressed = make(map[string]int)
+			}
+			overall.Suppressed[rule] += n
+		}
+
+		overall.Metrics.LinesOfCode += result.Metrics.LinesOfCode
+		overall.Metrics.FunctionCount += result.Metrics.FunctionCount
+		overall.Metrics.TypeCount += result.Metrics.TypeCount
+		if result.Metrics.CyclomaticComplexity > overall.Metrics.CyclomaticComplexity {
+			overall.Metrics.CyclomaticComplexity = result.Metrics.CyclomaticComplexity
+		}
+		overall.Metrics.Maintainability = lowerMaintainability(overall.Metrics.Maintainability, result.Metrics.Maintainability)
+	}
+
+	report := &WorkspaceReport{Root: root, Files: len(files)}
+	for _, dir := range pkgOrder {
+		pkg := packages[dir]
+		pkg.Score = packageScores[dir] / pkg.Files
+		pkg.TopIssues = topIssues(pkg.TopIssues, maxPackageTopIssues)
+		for i := range designs {
+			if designs[i].Path == dir {
+				pkg.Design = &designs[i]
+				break
+			}
+		}
+		report.Packages = append(report.Packages, *pkg)
+	}
+
+	overall.Issues = topIssues(allIssues, maxWorkspaceTopIssues)
+	overall.Score = scoreSum / len(files)
+	overall.Workspace = report
+	overall.Summary = messages.Translate(i18n.Normalize(lang), "workspace.summary",
+		len(files), len(report.Packages), len(allIssues), overall.Score)
+	if n := suppressedCount(overall.Suppressed); n > 0 {
+		overall.Summary += " " + messages.Translate(i18n.Normalize(lang), "summary.suppressed", n)
+	}
+
+	return overall
+}
+
+// topIssues returns up to limit issues ordered by severity, keeping the
+// original order among issues of equal severity
+func topIssues(issues []Issue, limit int) []Issue {
+	sorted := make([]Issue, len(issues))
+	copy(sorted, issues)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return severityRank(sorted[i].Severity) < severityRank(sorted[j].Severity)
+	})
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+// severityRank returns the position of severity in severityOrder, with
+// unknown severities ranked last
+func severityRank(severity string) int {
+	for i, s := range severityOrder {
+		if s == severity {
+			return i
+		}
+	}
+	return len(severityOrder)
+}
+
+// lowerMaintainability returns the worse of two maintainability ratings
+func lowerMaintainability(a, b string) string {
+	rank := map[string]int{"low": 0, "medium": 1, "high": 2}
+	if rank[b] < rank[a] {
+		return b
+	}
+	return a
+}
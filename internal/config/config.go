@@ -3,13 +3,24 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
 	"mcp-go-assistant/internal/circuitbreaker"
+	"mcp-go-assistant/internal/codereview"
+	"mcp-go-assistant/internal/i18n"
+	"mcp-go-assistant/internal/policy"
+	"mcp-go-assistant/internal/preflight"
+	"mcp-go-assistant/internal/prreview/bitbucket"
+	"mcp-go-assistant/internal/prreview/github"
+	"mcp-go-assistant/internal/prreview/gitlab"
+	"mcp-go-assistant/internal/queue"
 	"mcp-go-assistant/internal/ratelimit"
 	"mcp-go-assistant/internal/retry"
-	versionpkg "mcp-go-assistant/internal/version"
+	"mcp-go-assistant/internal/rulepack"
+	"mcp-go-assistant/internal/testgen"
+	"mcp-go-assistant/internal/validations"
 
 	"github.com/spf13/viper"
 )
@@ -20,10 +31,57 @@ type ValidationConfig struct {
 	AllowedChars  string   `mapstructure:"allowed_chars"`
 	EnabledRules  []string `mapstructure:"enabled_rules"`
 	DisabledRules []string `mapstructure:"disabled_rules"`
+	// AllowedRoots confines working_dir and guidelines_file to these
+	// directories; empty allows any path
+	AllowedRoots []string `mapstructure:"allowed_roots"`
+	// Tools overrides the validation rules per tool, keyed by tool name
+	// and then by parameter name
+	Tools map[string]map[string][]string `mapstructure:"tools"`
+	// CodeSafety configures the dangerous patterns of the code_safety rule
+	CodeSafety CodeSafetyConfig `mapstructure:"code_safety"`
+}
+
+// CodeSafetyConfig contains the settings of the code_safety rule
+type CodeSafetyConfig struct {
+	Mode      string                          `mapstructure:"mode"`      // "block" rejects code with a dangerous pattern; "warn" only logs it
+	Patterns  []string                        `mapstructure:"patterns"`  // Dangerous patterns; empty uses the built-in list
+	Allowlist []string                        `mapstructure:"allowlist"` // Patterns accepted for every tool
+	Tools     map[string]CodeSafetyToolConfig `mapstructure:"tools"`     // Per-tool overrides, keyed by tool name
+}
+
+// CodeSafetyToolConfig overrides the code_safety settings for one tool
+type CodeSafetyToolConfig struct {
+	Mode      string   `mapstructure:"mode"`      // Empty uses the shared mode
+	Patterns  []string `mapstructure:"patterns"`  // Replace the shared patterns when set
+	Allowlist []string `mapstructure:"allowlist"` // Added to the shared allowlist
+}
+
+// Rule returns the code_safety rule for tool, or the shared rule when tool
+// is empty
+func (c *CodeSafetyConfig) Rule(tool string) *validations.CodeSafetyRule {
+	rule := &validations.CodeSafetyRule{Mode: c.Mode, Allowlist: append([]string{}, c.Allowlist...)}
+	if len(c.Patterns) > 0 {
+		rule.Patterns = c.Patterns
+	}
+
+	if override, ok := c.Tools[tool]; ok && tool != "" {
+		if override.Mode != "" {
+			rule.Mode = override.Mode
+		}
+		if len(override.Patterns) > 0 {
+			rule.Patterns = override.Patterns
+		}
+		rule.Allowlist = append(rule.Allowlist, override.Allowlist...)
+	}
+	return rule
 }
 
 // Config holds all application configuration
 type Config struct {
+	// Profile names the block under profiles that was overlaid on the
+	// base configuration, if any
+	Profile string `mapstructure:"profile"`
+
 	Server        ServerConfig        `mapstructure:"server"`
 	Logging       LoggingConfig       `mapstructure:"logging"`
 	Metrics       MetricsConfig       `mapstructure:"metrics"`
@@ -33,16 +91,43 @@ type Config struct {
 	RateLimit     RateLimitConfig     `mapstructure:"rate_limit"`
 	ErrorHandling ErrorHandlingConfig `mapstructure:"error_handling"`
 	Retry         RetryConfig         `mapstructure:"retry"`
+	Localization  LocalizationConfig  `mapstructure:"localization"`
+	Workspace     WorkspaceConfig     `mapstructure:"workspace"`
+	WritePolicy   WritePolicyConfig   `mapstructure:"write_policy"`
+	Review        ReviewConfig        `mapstructure:"review"`
+	Scaffold      ScaffoldConfig      `mapstructure:"scaffold"`
+	TestGen       TestGenConfig       `mapstructure:"test_gen"`
+	Preflight     PreflightConfig     `mapstructure:"preflight"`
+	Cache         CacheConfig         `mapstructure:"cache"`
+	Idempotency   IdempotencyConfig   `mapstructure:"idempotency"`
+	Artifacts     ArtifactConfig      `mapstructure:"artifacts"`
+	GRPC          GRPCConfig          `mapstructure:"grpc"`
+	PRReview      PRReviewConfig      `mapstructure:"pr_review"`
+	Concurrency   ConcurrencyConfig   `mapstructure:"concurrency"`
 }
 
+// Server transports
+const (
+	TransportStdio = "stdio" // A single client on stdin and stdout
+	TransportUnix  = "unix"  // Clients of a Unix domain socket, each in its own session
+)
+
 // ServerConfig contains server-related settings
 type ServerConfig struct {
 	Name         string        `mapstructure:"name"`
-	Version      string        `mapstructure:"version"`
 	Host         string        `mapstructure:"host"`
 	Port         int           `mapstructure:"port"`
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	// Transport is "stdio" for a single client or "unix" for a daemon
+	// serving the clients of a Unix domain socket
+	Transport string `mapstructure:"transport"`
+	// SocketPath is the socket of the unix transport; a socket passed by
+	// systemd socket activation takes precedence
+	SocketPath string `mapstructure:"socket_path"`
+	// MaxClients bounds the clients connected to the unix transport at
+	// once; 0 is unlimited
+	MaxClients int `mapstructure:"max_clients"`
 }
 
 // LoggingConfig contains logging-related settings
@@ -64,10 +149,279 @@ type ErrorHandlingConfig struct {
 	CategoryMappings map[string]string `mapstructure:"category_mappings"` // Custom category mappings
 }
 
+// LocalizationConfig contains output localization settings
+type LocalizationConfig struct {
+	Language string `mapstructure:"language"` // Default language for tool output: en, ja, es
+}
+
+// WritePolicyConfig controls which filesystem writes tools may perform
+type WritePolicyConfig struct {
+	ReadOnly            bool     `mapstructure:"read_only"`            // Reject every write
+	AllowPaths          []string `mapstructure:"allow_paths"`          // Directories writes must resolve into; empty allows any path
+	RequireConfirmation bool     `mapstructure:"require_confirmation"` // Writes need the confirm argument of the tool
+}
+
+// ToPolicyConfig converts WritePolicyConfig to policy.Config
+func (c *WritePolicyConfig) ToPolicyConfig() policy.Config {
+	return policy.Config{
+		ReadOnly:            c.ReadOnly,
+		AllowPaths:          c.AllowPaths,
+		RequireConfirmation: c.RequireConfirmation,
+	}
+}
+
+// WorkspaceConfig contains settings for workspace-wide reviews
+type WorkspaceConfig struct {
+	Roots        []string `mapstructure:"roots"`         // Registered workspace roots
+	Ignore       []string `mapstructure:"ignore"`        // Glob patterns skipped in addition to vendor and testdata
+	MaxFiles     int      `mapstructure:"max_files"`     // Maximum Go files reviewed per request
+	HistoryDir   string   `mapstructure:"history_dir"`   // Directory keeping the score history of each workspace; empty disables review-trend
+	HistoryLimit int      `mapstructure:"history_limit"` // Review runs kept per workspace
+}
+
+// ReviewConfig contains settings for code reviews
+type ReviewConfig struct {
+	Thresholds        ReviewThresholdsConfig `mapstructure:"thresholds"`
+	GuidelinesMaxSize int64                  `mapstructure:"guidelines_max_size"` // Bytes read from a guidelines file; the rest is skipped with a warning
+	Plugins           []string               `mapstructure:"plugins"`             // Go plugins providing additional review rules, loaded at startup
+	WasmPlugins       []WasmPluginConfig     `mapstructure:"wasm_plugins"`        // Wasm modules providing additional review rules, loaded at startup
+	WasmLimits        WasmLimitsConfig       `mapstructure:"wasm_limits"`
+	RulePacksDir      string                 `mapstructure:"rule_packs_dir"` // Directory rule packs are installed to
+	RulePacks         []RulePackConfig       `mapstructure:"rule_packs"`     // Versioned rule packs, verified and loaded at startup
+}
+
+// RulePackConfig is an installed rule pack pinned to a version and digest
+type RulePackConfig struct {
+	Name    string `mapstructure:"name"`
+	Version string `mapstructure:"version"`
+	Source  string `mapstructure:"source"` // Git URL or oci:// reference; a missing pack is installed from it
+	SHA256  string `mapstructure:"sha256"` // Digest printed by install-rule-pack
+}
+
+// sha256Pattern matches the hex digest recorded for a rule pack
+var sha256Pattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// ToRulePackRefs returns the configured rule packs
+func (c *ReviewConfig) ToRulePackRefs() []rulepack.Ref {
+	refs := make([]rulepack.Ref, len(c.RulePacks))
+	for i, p := range c.RulePacks {
+		refs[i] = rulepack.Ref{Name: p.Name, Version: p.Version, Source: p.Source, SHA256: p.SHA256}
+	}
+	return refs
+}
+
+// WasmPluginConfig is a wasm module implementing a review rule
+type WasmPluginConfig struct {
+	Path string `mapstructure:"path"`
+	Name string `mapstructure:"name"` // Rule name; defaults to the file name without extension
+}
+
+// WasmLimitsConfig bounds the resources of every wasm rule check
+type WasmLimitsConfig struct {
+	MaxMemoryMB int           `mapstructure:"max_memory_mb"` // Linear memory a module may use
+	Timeout     time.Duration `mapstructure:"timeout"`       // Time a module may spend on one file
+}
+
+// ToWasmLimits returns the wasm rule limits
+func (c *WasmLimitsConfig) ToWasmLimits() codereview.WasmLimits {
+	return codereview.WasmLimits{
+		MaxMemory: int64(c.MaxMemoryMB) << 20,
+		Timeout:   c.Timeout,
+	}
+}
+
+// ToWasmModules returns the wasm modules to load
+func (c *ReviewConfig) ToWasmModules() []codereview.WasmModule {
+	modules := make([]codereview.WasmModule, len(c.WasmPlugins))
+	for i, p := range c.WasmPlugins {
+		modules[i] = codereview.WasmModule{Path: p.Path, Name: p.Name}
+	}
+	return modules
+}
+
+// ReviewThresholdsConfig contains the limits above which code-review reports
+// structure and complexity issues. Requests can override each limit.
+type ReviewThresholdsConfig struct {
+	FunctionLines int `mapstructure:"function_lines"` // Lines a function body may span
+	Parameters    int `mapstructure:"parameters"`     // Parameters a function may take
+	StructFields  int `mapstructure:"struct_fields"`  // Fields a struct may declare
+	Complexity    int `mapstructure:"complexity"`     // Cyclomatic complexity a function may reach
+}
+
+// ToThresholds returns the code-review thresholds
+func (c *ReviewThresholdsConfig) ToThresholds() codereview.Thresholds {
+	return codereview.Thresholds{
+		FunctionLines: c.FunctionLines,
+		Parameters:    c.Parameters,
+		StructFields:  c.StructFields,
+		Complexity:    c.Complexity,
+	}
+}
+
+// ScaffoldConfig contains settings for the scaffold tool
+type ScaffoldConfig struct {
+	TemplateDir string `mapstructure:"template_dir"` // Optional directory overriding the built-in project templates
+	GoVersion   string `mapstructure:"go_version"`   // Default go directive for generated go.mod files
+}
+
+// TestGenConfig contains settings for the test-gen tool
+type TestGenConfig struct {
+	Style TestGenStyleConfig `mapstructure:"style"`
+}
+
+// TestGenStyleConfig is the team convention for generated tests. Requests
+// can override each setting.
+type TestGenStyleConfig struct {
+	Naming string `mapstructure:"naming"` // "standard" (TestFunc) or "underscore" (Test_Func)
+	Layout string `mapstructure:"layout"` // "single" file or "per-function" files
+	Assert string `mapstructure:"assert"` // "none", "stdlib" or "testify" assertions in table-driven tests
+}
+
+// ToStyle returns the test-gen style
+func (c *TestGenStyleConfig) ToStyle() testgen.Style {
+	return testgen.Style{Naming: c.Naming, Layout: c.Layout, Assert: c.Assert}
+}
+
+// PreflightConfig contains settings for the startup checks
+type PreflightConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	OnFailure     string        `mapstructure:"on_failure"`     // "fail" refuses to start, "degrade" starts with degraded health
+	Timeout       time.Duration `mapstructure:"timeout"`        // Upper bound for all checks together
+	WarmPackages  []string      `mapstructure:"warm_packages"`  // Standard library packages whose docs are cached at startup
+	ContainerMode string        `mapstructure:"container_mode"` // "auto", "on" or "off"; in a container the go env directories are checked
+}
+
+// CacheConfig contains settings for the code-review and test-gen response caches
+type CacheConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	TTL        time.Duration `mapstructure:"ttl"`         // How long a cached response is reused
+	MaxEntries int           `mapstructure:"max_entries"` // Responses kept per tool before evicting the least recently used
+}
+
+// IdempotencyConfig contains settings for the results stored under
+// client-supplied idempotency keys
+type IdempotencyConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	TTL        time.Duration `mapstructure:"ttl"`         // How long a result is returned for repeated calls with its key
+	MaxEntries int           `mapstructure:"max_entries"` // Results kept across all tools before evicting the least recently used
+}
+
+// ArtifactConfig contains settings for the large code-review and test-gen
+// outputs returned as artifact IDs and fetched with get-artifact
+type ArtifactConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	TTL        time.Duration `mapstructure:"ttl"`         // How long an artifact can be fetched
+	MaxEntries int           `mapstructure:"max_entries"` // Artifacts kept before evicting the least recently used
+	Threshold  int           `mapstructure:"threshold"`   // Outputs larger than this many bytes are stored as artifacts
+}
+
+// GRPCConfig contains settings for the gRPC API serving go-doc,
+// code-review and test-gen alongside MCP
+type GRPCConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Address string `mapstructure:"address"` // TCP address to listen on, e.g. "127.0.0.1:50051"
+}
+
+// PRReviewConfig contains settings for the webhook listener that reviews
+// pull requests of GitHub, GitLab and Bitbucket and reports issues on
+// changed lines
+type PRReviewConfig struct {
+	Address   string                  `mapstructure:"address"`    // TCP address of the webhook listener
+	FailOn    string                  `mapstructure:"fail_on"`    // Lowest issue severity that fails the review; empty never fails it
+	Workers   int                     `mapstructure:"workers"`    // Pull requests reviewed at once
+	QueueSize int                     `mapstructure:"queue_size"` // Pull requests waiting for a worker before deliveries are refused
+	GitHub    PRReviewGitHubConfig    `mapstructure:"github"`
+	GitLab    PRReviewGitLabConfig    `mapstructure:"gitlab"`
+	Bitbucket PRReviewBitbucketConfig `mapstructure:"bitbucket"`
+}
+
+// Enabled reports whether any provider is enabled
+func (c *PRReviewConfig) Enabled() bool {
+	return c.GitHub.Enabled || c.GitLab.Enabled || c.Bitbucket.Enabled
+}
+
+// PRReviewGitHubConfig connects the reviewer to GitHub as a GitHub App
+// reporting through the Checks API
+type PRReviewGitHubConfig struct {
+	Enabled        bool   `mapstructure:"enabled"`
+	Path           string `mapstructure:"path"` // URL path receiving the deliveries
+	AppID          int64  `mapstructure:"app_id"`
+	PrivateKeyFile string `mapstructure:"private_key_file"` // PEM private key of the app
+	WebhookSecret  string `mapstructure:"webhook_secret"`
+	APIURL         string `mapstructure:"api_url"` // REST API, e.g. https://github.example.com/api/v3 for GitHub Enterprise Server
+	CheckName      string `mapstructure:"check_name"`
+}
+
+// PRReviewGitLabConfig connects the reviewer to GitLab, reporting through
+// merge request discussions and commit statuses
+type PRReviewGitLabConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	Path          string `mapstructure:"path"` // URL path receiving the deliveries
+	URL           string `mapstructure:"url"`  // Instance URL
+	Token         string `mapstructure:"token"`
+	WebhookSecret string `mapstructure:"webhook_secret"`
+	StatusName    string `mapstructure:"status_name"`
+}
+
+// PRReviewBitbucketConfig connects the reviewer to Bitbucket Cloud,
+// reporting through Code Insights reports
+type PRReviewBitbucketConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	Path          string `mapstructure:"path"` // URL path receiving the deliveries
+	APIURL        string `mapstructure:"api_url"`
+	Token         string `mapstructure:"token"`
+	WebhookSecret string `mapstructure:"webhook_secret"`
+	ReportID      string `mapstructure:"report_id"`
+}
+
+// ConcurrencyConfig bounds concurrent tool executions and queues the excess
+type ConcurrencyConfig struct {
+	Enabled       bool                             `mapstructure:"enabled"`
+	MaxConcurrent int                              `mapstructure:"max_concurrent"` // Executions per tool running at once
+	QueueSize     int                              `mapstructure:"queue_size"`     // Calls per tool waiting for a slot; 0 rejects immediately
+	MaxWait       time.Duration                    `mapstructure:"max_wait"`       // Longest a call waits in the queue
+	Tools         map[string]ConcurrencyToolConfig `mapstructure:"tools"`
+}
+
+// ConcurrencyToolConfig overrides the concurrency limits for one tool; zero
+// values inherit the global setting
+type ConcurrencyToolConfig struct {
+	MaxConcurrent int           `mapstructure:"max_concurrent"`
+	QueueSize     int           `mapstructure:"queue_size"`
+	MaxWait       time.Duration `mapstructure:"max_wait"`
+}
+
+// ToQueueConfig returns the queue.Config for the named tool
+func (c *ConcurrencyConfig) ToQueueConfig(tool string) queue.Config {
+	cfg := queue.Config{
+		MaxConcurrent: c.MaxConcurrent,
+		QueueSize:     c.QueueSize,
+		MaxWait:       c.MaxWait,
+	}
+	if override, ok := c.Tools[tool]; ok {
+		if override.MaxConcurrent != 0 {
+			cfg.MaxConcurrent = override.MaxConcurrent
+		}
+		if override.QueueSize != 0 {
+			cfg.QueueSize = override.QueueSize
+		}
+		if override.MaxWait != 0 {
+			cfg.MaxWait = override.MaxWait
+		}
+	}
+	return cfg
+}
+
 // MetricsConfig contains metrics-related settings
 type MetricsConfig struct {
-	Enabled bool   `mapstructure:"enabled"`
-	Path    string `mapstructure:"path"`
+	Enabled          bool          `mapstructure:"enabled"`
+	Path             string        `mapstructure:"path"`
+	SnapshotPath     string        `mapstructure:"snapshot_path"`     // Optional file where counters are saved and reloaded at startup
+	SnapshotInterval time.Duration `mapstructure:"snapshot_interval"` // How often counters are saved
+
+	// Target latency per tool; successful calls slower than their budget
+	// are counted so analysis regressions show up on dashboards
+	LatencyBudgets map[string]time.Duration `mapstructure:"latency_budgets"`
 }
 
 // ToolsConfig contains tool-specific settings
@@ -75,6 +429,13 @@ type ToolsConfig struct {
 	GoDocTimeout             time.Duration        `mapstructure:"godoc_timeout"`
 	CodeReviewTimeout        time.Duration        `mapstructure:"code_review_timeout"`
 	TestGenTimeout           time.Duration        `mapstructure:"test_gen_timeout"`
+	ModReviewTimeout         time.Duration        `mapstructure:"mod_review_timeout"`
+	EscapeAnalysisTimeout    time.Duration        `mapstructure:"escape_analysis_timeout"`
+	ImplementationsTimeout   time.Duration        `mapstructure:"implementations_timeout"`
+	CallGraphTimeout         time.Duration        `mapstructure:"call_graph_timeout"`
+	StructSchemaTimeout      time.Duration        `mapstructure:"struct_schema_timeout"`
+	GRPCReviewTimeout        time.Duration        `mapstructure:"grpc_review_timeout"`
+	LargeInputThreshold      int                  `mapstructure:"large_input_threshold"` // Bytes of go_code above which it is spooled to a temporary file; 0 disables
 	GoDocCircuitBreaker      CircuitBreakerConfig `mapstructure:"godoc_circuit_breaker"`
 	CodeReviewCircuitBreaker CircuitBreakerConfig `mapstructure:"code_review_circuit_breaker"`
 	TestGenCircuitBreaker    CircuitBreakerConfig `mapstructure:"test_gen_circuit_breaker"`
@@ -85,6 +446,7 @@ type CircuitBreakerConfig struct {
 	MaxFailures         int           `mapstructure:"max_failures"`
 	Timeout             time.Duration `mapstructure:"timeout"`
 	MaxHalfOpenRequests int           `mapstructure:"max_half_open_requests"`
+	IgnoreCancellations bool          `mapstructure:"ignore_cancellations"` // Don't count calls cancelled by the client as failures
 }
 
 // ToCircuitBreakerConfig converts to circuitbreaker.Config
@@ -94,6 +456,7 @@ func (c *CircuitBreakerConfig) ToCircuitBreakerConfig(name string) *circuitbreak
 		MaxFailures:         c.MaxFailures,
 		Timeout:             c.Timeout,
 		MaxHalfOpenRequests: c.MaxHalfOpenRequests,
+		IgnoreCancellations: c.IgnoreCancellations,
 	}
 }
 
@@ -113,7 +476,11 @@ type RateLimitConfig struct {
 	Mode      string                         `mapstructure:"mode"`
 	Algorithm string                         `mapstructure:"algorithm"`
 	StoreType string                         `mapstructure:"store_type"`
+	MaxKeys   int                            `mapstructure:"max_keys"` // Keys kept by the memory store; 0 is unbounded
+	Behavior  string                         `mapstructure:"behavior"` // reject or wait
+	MaxWait   time.Duration                  `mapstructure:"max_wait"` // Longest wait of a call in wait behavior
 	Tools     map[string]RateLimitToolConfig `mapstructure:"tools"`
+	Classes   map[string]RateLimitToolConfig `mapstructure:"classes"` // Limits of tools without their own, by class (read, analyze)
 }
 
 // RateLimitToolConfig contains tool-specific rate limiting configuration
@@ -175,11 +542,11 @@ func DefaultConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
 			Name:         "mcp-go-assistant",
-			Version:      versionpkg.Version,
 			Host:         "0.0.0.0",
 			Port:         8080,
 			ReadTimeout:  30 * time.Second,
 			WriteTimeout: 30 * time.Second,
+			Transport:    TransportStdio,
 		},
 		Logging: LoggingConfig{
 			Level:      "info",
@@ -196,28 +563,142 @@ func DefaultConfig() *Config {
 			TrackMetrics:     true,
 			CategoryMappings: map[string]string{},
 		},
+		Localization: LocalizationConfig{
+			Language: i18n.DefaultLanguage,
+		},
+		Workspace: WorkspaceConfig{
+			Roots:        []string{},
+			Ignore:       []string{},
+			MaxFiles:     500,
+			HistoryDir:   "",
+			HistoryLimit: 50,
+		},
+		WritePolicy: WritePolicyConfig{
+			ReadOnly:            false,
+			AllowPaths:          []string{},
+			RequireConfirmation: false,
+		},
+		Review: ReviewConfig{
+			Thresholds: ReviewThresholdsConfig{
+				FunctionLines: 50,
+				Parameters:    5,
+				StructFields:  10,
+				Complexity:    10,
+			},
+			GuidelinesMaxSize: 1024 * 1024,
+			Plugins:           []string{},
+			WasmPlugins:       []WasmPluginConfig{},
+			WasmLimits: WasmLimitsConfig{
+				MaxMemoryMB: 16,
+				Timeout:     2 * time.Second,
+			},
+			RulePacksDir: "rule-packs",
+			RulePacks:    []RulePackConfig{},
+		},
+		Scaffold: ScaffoldConfig{
+			TemplateDir: "",
+			GoVersion:   "1.23",
+		},
+		TestGen: TestGenConfig{
+			Style: TestGenStyleConfig{
+				Naming: testgen.NamingStandard,
+				Layout: testgen.LayoutSingle,
+				Assert: testgen.AssertNone,
+			},
+		},
+		Preflight: PreflightConfig{
+			Enabled:       true,
+			OnFailure:     preflight.OnFailureDegrade,
+			Timeout:       30 * time.Second,
+			WarmPackages:  []string{"fmt", "errors", "context", "io", "os", "strings", "time", "net/http"},
+			ContainerMode: preflight.ContainerAuto,
+		},
+		Cache: CacheConfig{
+			Enabled:    true,
+			TTL:        10 * time.Minute,
+			MaxEntries: 256,
+		},
+		Idempotency: IdempotencyConfig{
+			Enabled:    true,
+			TTL:        15 * time.Minute,
+			MaxEntries: 1024,
+		},
+		Artifacts: ArtifactConfig{
+			Enabled:    true,
+			TTL:        30 * time.Minute,
+			MaxEntries: 256,
+			Threshold:  32 * 1024,
+		},
+		GRPC: GRPCConfig{
+			Enabled: false,
+			Address: "127.0.0.1:50051",
+		},
+		PRReview: PRReviewConfig{
+			Address:   "127.0.0.1:8088",
+			FailOn:    "high",
+			Workers:   2,
+			QueueSize: 32,
+			GitHub: PRReviewGitHubConfig{
+				Path:      "/webhook/github",
+				APIURL:    github.DefaultAPIURL,
+				CheckName: github.DefaultCheckName,
+			},
+			GitLab: PRReviewGitLabConfig{
+				Path:       "/webhook/gitlab",
+				URL:        gitlab.DefaultURL,
+				StatusName: gitlab.DefaultStatusName,
+			},
+			Bitbucket: PRReviewBitbucketConfig{
+				Path:     "/webhook/bitbucket",
+				APIURL:   bitbucket.DefaultAPIURL,
+				ReportID: bitbucket.DefaultReportID,
+			},
+		},
+		Concurrency: ConcurrencyConfig{
+			Enabled:       true,
+			MaxConcurrent: 8,
+			QueueSize:     16,
+			MaxWait:       10 * time.Second,
+			Tools:         map[string]ConcurrencyToolConfig{},
+		},
 		Metrics: MetricsConfig{
-			Enabled: true,
-			Path:    "/metrics",
+			Enabled:          true,
+			Path:             "/metrics",
+			SnapshotPath:     "",
+			SnapshotInterval: 1 * time.Minute,
+			LatencyBudgets: map[string]time.Duration{
+				"code-review": 2 * time.Second,
+				"test-gen":    2 * time.Second,
+			},
 		},
 		Tools: ToolsConfig{
-			GoDocTimeout:      30 * time.Second,
-			CodeReviewTimeout: 60 * time.Second,
-			TestGenTimeout:    45 * time.Second,
+			GoDocTimeout:           30 * time.Second,
+			CodeReviewTimeout:      60 * time.Second,
+			TestGenTimeout:         45 * time.Second,
+			ModReviewTimeout:       60 * time.Second,
+			EscapeAnalysisTimeout:  60 * time.Second,
+			ImplementationsTimeout: 60 * time.Second,
+			CallGraphTimeout:       60 * time.Second,
+			StructSchemaTimeout:    30 * time.Second,
+			GRPCReviewTimeout:      60 * time.Second,
+			LargeInputThreshold:    1024 * 1024,
 			GoDocCircuitBreaker: CircuitBreakerConfig{
 				MaxFailures:         5,
 				Timeout:             30 * time.Second,
 				MaxHalfOpenRequests: 3,
+				IgnoreCancellations: true,
 			},
 			CodeReviewCircuitBreaker: CircuitBreakerConfig{
 				MaxFailures:         5,
 				Timeout:             30 * time.Second,
 				MaxHalfOpenRequests: 3,
+				IgnoreCancellations: true,
 			},
 			TestGenCircuitBreaker: CircuitBreakerConfig{
 				MaxFailures:         5,
 				Timeout:             30 * time.Second,
 				MaxHalfOpenRequests: 3,
+				IgnoreCancellations: true,
 			},
 		},
 		Timeouts: TimeoutConfig{
@@ -237,6 +718,16 @@ func DefaultConfig() *Config {
 				"symbol_name",
 			},
 			DisabledRules: []string{},
+			AllowedRoots:  []string{},
+			CodeSafety: CodeSafetyConfig{
+				Mode: validations.CodeSafetyBlock,
+				Tools: map[string]CodeSafetyToolConfig{
+					// Code under review is only parsed, never run
+					"code-review":       {Mode: validations.CodeSafetyWarn},
+					"code-review-batch": {Mode: validations.CodeSafetyWarn},
+					"test-gen":          {Mode: validations.CodeSafetyBlock},
+				},
+			},
 		},
 		RateLimit: RateLimitConfig{
 			Enabled:   true,
@@ -245,6 +736,9 @@ func DefaultConfig() *Config {
 			Mode:      string(ratelimit.ModePerTool),
 			Algorithm: string(ratelimit.AlgorithmTokenBucket),
 			StoreType: string(ratelimit.StoreMemory),
+			MaxKeys:   10000,
+			Behavior:  string(ratelimit.BehaviorReject),
+			MaxWait:   10 * time.Second,
 			Tools: map[string]RateLimitToolConfig{
 				"godoc": {
 					Enabled: true,
@@ -262,6 +756,18 @@ func DefaultConfig() *Config {
 					Window:  1 * time.Minute,
 				},
 			},
+			Classes: map[string]RateLimitToolConfig{
+				string(ratelimit.ClassRead): {
+					Enabled: true,
+					Limit:   100,
+					Window:  1 * time.Minute,
+				},
+				string(ratelimit.ClassAnalyze): {
+					Enabled: true,
+					Limit:   30,
+					Window:  1 * time.Minute,
+				},
+			},
 		},
 		Retry: RetryConfig{
 			Enabled:      true,
@@ -302,6 +808,12 @@ func DefaultConfig() *Config {
 // Config file path can be provided via MCP_CONFIG environment variable
 // Environment variables override config file settings
 func Load() (*Config, error) {
+	return LoadFile(os.Getenv("MCP_CONFIG"))
+}
+
+// LoadFile loads configuration like Load, reading configPath instead of the
+// MCP_CONFIG file. An empty path searches the default locations.
+func LoadFile(configPath string) (*Config, error) {
 	v := viper.New()
 	cfg := DefaultConfig()
 
@@ -309,7 +821,6 @@ func Load() (*Config, error) {
 	setDefaults(v)
 
 	// Read config file if specified
-	configPath := os.Getenv("MCP_CONFIG")
 	if configPath != "" {
 		v.SetConfigFile(configPath)
 	} else {
@@ -330,6 +841,11 @@ func Load() (*Config, error) {
 	// Environment variable bindings
 	bindEnvVars(v)
 
+	// Overlay the selected profile on the file settings
+	if err := applyProfile(v); err != nil {
+		return nil, err
+	}
+
 	// Unmarshal config
 	if err := v.Unmarshal(cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
@@ -343,12 +859,43 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// applyProfile merges the profiles.<name> block selected by the profile key
+// over the rest of the file. Profile settings replace file settings key by
+// key; environment variables still override both.
+func applyProfile(v *viper.Viper) error {
+	profile := v.GetString("profile")
+	if profile == "" {
+		return nil
+	}
+
+	key := "profiles." + profile
+	if !v.IsSet(key) {
+		return fmt.Errorf("unknown config profile %q: no profiles.%s block in the config file", profile, profile)
+	}
+	overlay := v.GetStringMap(key)
+	delete(overlay, "profiles")
+	delete(overlay, "profile")
+	if err := v.MergeConfigMap(overlay); err != nil {
+		return fmt.Errorf("failed to apply config profile %q: %w", profile, err)
+	}
+	return nil
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	if c.Server.Port <= 0 || c.Server.Port > 65535 {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
 	}
 
+	switch c.Server.Transport {
+	case TransportStdio, TransportUnix:
+	default:
+		return fmt.Errorf("invalid server transport: %s (valid: stdio, unix)", c.Server.Transport)
+	}
+	if c.Server.MaxClients < 0 {
+		return fmt.Errorf("server max_clients must not be negative")
+	}
+
 	if err := validateLogLevel(c.Logging.Level); err != nil {
 		return err
 	}
@@ -357,6 +904,10 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if !i18n.IsSupported(c.Localization.Language) {
+		return fmt.Errorf("unsupported localization language: %s (supported: en, ja, es)", c.Localization.Language)
+	}
+
 	if c.Timeouts.Default <= 0 {
 		return fmt.Errorf("default timeout must be positive")
 	}
@@ -365,9 +916,151 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("shutdown timeout must be positive")
 	}
 
+	if c.RateLimit.MaxKeys < 0 {
+		return fmt.Errorf("rate limit max_keys cannot be negative")
+	}
+
+	switch ratelimit.Behavior(c.RateLimit.Behavior) {
+	case ratelimit.BehaviorReject:
+		// Calls over the limit fail right away
+	case ratelimit.BehaviorWait:
+		if c.RateLimit.MaxWait <= 0 {
+			return fmt.Errorf("rate limit max_wait must be positive in wait behavior")
+		}
+	default:
+		return fmt.Errorf("invalid rate limit behavior: %s (valid: reject, wait)", c.RateLimit.Behavior)
+	}
+
+	for class, classCfg := range c.RateLimit.Classes {
+		switch ratelimit.ToolClass(class) {
+		case ratelimit.ClassRead, ratelimit.ClassAnalyze:
+			// Valid classes
+		default:
+			return fmt.Errorf("invalid rate limit class: %s (valid: read, analyze)", class)
+		}
+		if classCfg.Enabled && (classCfg.Limit <= 0 || classCfg.Window <= 0) {
+			return fmt.Errorf("rate limit and window of class %s must be positive", class)
+		}
+	}
+
+	if c.Preflight.OnFailure != preflight.OnFailureFail && c.Preflight.OnFailure != preflight.OnFailureDegrade {
+		return fmt.Errorf("invalid preflight on_failure: %s (valid: fail, degrade)", c.Preflight.OnFailure)
+	}
+
+	switch c.Preflight.ContainerMode {
+	case preflight.ContainerAuto, preflight.ContainerOn, preflight.ContainerOff:
+	default:
+		return fmt.Errorf("invalid preflight container_mode: %s (valid: auto, on, off)", c.Preflight.ContainerMode)
+	}
+
+	if c.Preflight.Enabled && c.Preflight.Timeout <= 0 {
+		return fmt.Errorf("preflight timeout must be positive")
+	}
+
+	if c.Cache.Enabled && (c.Cache.TTL <= 0 || c.Cache.MaxEntries <= 0) {
+		return fmt.Errorf("cache ttl and max_entries must be positive when caching is enabled")
+	}
+
+	if c.Idempotency.Enabled && (c.Idempotency.TTL <= 0 || c.Idempotency.MaxEntries <= 0) {
+		return fmt.Errorf("idempotency ttl and max_entries must be positive when idempotency keys are enabled")
+	}
+
+	if c.Artifacts.Enabled && (c.Artifacts.TTL <= 0 || c.Artifacts.MaxEntries <= 0 || c.Artifacts.Threshold <= 0) {
+		return fmt.Errorf("artifact ttl, max_entries and threshold must be positive when artifacts are enabled")
+	}
+
+	if c.GRPC.Enabled && c.GRPC.Address == "" {
+		return fmt.Errorf("grpc address is required when the gRPC API is enabled")
+	}
+
+	if err := validatePRReview(&c.PRReview); err != nil {
+		return err
+	}
+
+	if c.Concurrency.Enabled {
+		cfg := c.Concurrency.ToQueueConfig("")
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid concurrency config: %w", err)
+		}
+		for tool := range c.Concurrency.Tools {
+			cfg := c.Concurrency.ToQueueConfig(tool)
+			if err := cfg.Validate(); err != nil {
+				return fmt.Errorf("invalid concurrency config for %s: %w", tool, err)
+			}
+		}
+	}
+
+	if c.Workspace.HistoryDir != "" && c.Workspace.HistoryLimit <= 0 {
+		return fmt.Errorf("workspace history limit must be positive when a history directory is set")
+	}
+
+	if c.Metrics.SnapshotPath != "" && c.Metrics.SnapshotInterval <= 0 {
+		return fmt.Errorf("metrics snapshot interval must be positive when a snapshot path is set")
+	}
+
+	if t := c.Review.Thresholds; t.FunctionLines <= 0 || t.Parameters <= 0 || t.StructFields <= 0 || t.Complexity <= 0 {
+		return fmt.Errorf("review thresholds must be positive")
+	}
+
+	if c.Review.GuidelinesMaxSize <= 0 {
+		return fmt.Errorf("review guidelines max size must be positive")
+	}
+
+	if c.Review.WasmLimits.MaxMemoryMB <= 0 || c.Review.WasmLimits.Timeout <= 0 {
+		return fmt.Errorf("review wasm limits must be positive")
+	}
+	for _, p := range c.Review.WasmPlugins {
+		if p.Path == "" {
+			return fmt.Errorf("review wasm plugin path must not be empty")
+		}
+	}
+	if len(c.Review.RulePacks) > 0 && c.Review.RulePacksDir == "" {
+		return fmt.Errorf("review rule packs dir must not be empty when rule packs are configured")
+	}
+	for _, p := range c.Review.RulePacks {
+		if p.Name == "" || p.Version == "" {
+			return fmt.Errorf("review rule packs need a name and version")
+		}
+		if !sha256Pattern.MatchString(p.SHA256) {
+			return fmt.Errorf("review rule pack %s %s needs a sha256 of 64 hex digits", p.Name, p.Version)
+		}
+	}
+
+	if err := c.TestGen.Style.ToStyle().Validate(); err != nil {
+		return fmt.Errorf("test_gen %w", err)
+	}
+
+	if err := validateCodeSafetyMode(c.Validations.CodeSafety.Mode, false); err != nil {
+		return err
+	}
+	for tool, override := range c.Validations.CodeSafety.Tools {
+		if err := validateCodeSafetyMode(override.Mode, true); err != nil {
+			return fmt.Errorf("%w for %s", err, tool)
+		}
+	}
+
+	if c.Tools.LargeInputThreshold < 0 {
+		return fmt.Errorf("large input threshold cannot be negative")
+	}
+
+	for tool, budget := range c.Metrics.LatencyBudgets {
+		if budget <= 0 {
+			return fmt.Errorf("latency budget for %s must be positive", tool)
+		}
+	}
+
 	return nil
 }
 
+// validateCodeSafetyMode checks if the code_safety mode is valid; per-tool
+// overrides may leave it empty
+func validateCodeSafetyMode(mode string, optional bool) error {
+	if (optional && mode == "") || mode == validations.CodeSafetyBlock || mode == validations.CodeSafetyWarn {
+		return nil
+	}
+	return fmt.Errorf("invalid code_safety mode: %s (valid: block, warn)", mode)
+}
+
 // validateLogLevel checks if the log level is valid
 func validateLogLevel(level string) error {
 	validLevels := map[string]bool{
@@ -401,39 +1094,189 @@ func validateLogFormat(format string) error {
 	return nil
 }
 
+// validatePRReview checks the pull request review settings when a
+// provider is enabled
+func validatePRReview(c *PRReviewConfig) error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.Address == "" {
+		return fmt.Errorf("pr_review address is required when a provider is enabled")
+	}
+	if c.FailOn != "" && !codereview.IsValidSeverity(c.FailOn) {
+		return fmt.Errorf("invalid pr_review fail_on: %s (valid: critical, high, medium, low or empty)", c.FailOn)
+	}
+	if c.Workers <= 0 || c.QueueSize < 0 {
+		return fmt.Errorf("pr_review workers must be positive and queue_size not negative")
+	}
+
+	paths := map[string]string{}
+	checkPath := func(provider, path string) error {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("pr_review %s path must start with /", provider)
+		}
+		if other, ok := paths[path]; ok {
+			return fmt.Errorf("pr_review %s and %s share the path %s", other, provider, path)
+		}
+		paths[path] = provider
+		return nil
+	}
+	if c.GitHub.Enabled {
+		if err := checkPath("github", c.GitHub.Path); err != nil {
+			return err
+		}
+		if c.GitHub.AppID <= 0 || c.GitHub.PrivateKeyFile == "" || c.GitHub.WebhookSecret == "" {
+			return fmt.Errorf("pr_review github app_id, private_key_file and webhook_secret are required")
+		}
+	}
+	if c.GitLab.Enabled {
+		if err := checkPath("gitlab", c.GitLab.Path); err != nil {
+			return err
+		}
+		if c.GitLab.Token == "" || c.GitLab.WebhookSecret == "" {
+			return fmt.Errorf("pr_review gitlab token and webhook_secret are required")
+		}
+	}
+	if c.Bitbucket.Enabled {
+		if err := checkPath("bitbucket", c.Bitbucket.Path); err != nil {
+			return err
+		}
+		if c.Bitbucket.Token == "" || c.Bitbucket.WebhookSecret == "" {
+			return fmt.Errorf("pr_review bitbucket token and webhook_secret are required")
+		}
+	}
+	return nil
+}
+
 // setDefaults sets default values in viper
 func setDefaults(v *viper.Viper) {
 	cfg := DefaultConfig()
 
 	v.SetDefault("server.name", cfg.Server.Name)
-	v.SetDefault("server.version", cfg.Server.Version)
 	v.SetDefault("server.host", cfg.Server.Host)
 	v.SetDefault("server.port", cfg.Server.Port)
 	v.SetDefault("server.read_timeout", cfg.Server.ReadTimeout)
 	v.SetDefault("server.write_timeout", cfg.Server.WriteTimeout)
+	v.SetDefault("server.transport", cfg.Server.Transport)
+	v.SetDefault("server.socket_path", cfg.Server.SocketPath)
+	v.SetDefault("server.max_clients", cfg.Server.MaxClients)
 
 	v.SetDefault("logging.level", cfg.Logging.Level)
 	v.SetDefault("logging.format", cfg.Logging.Format)
 	v.SetDefault("logging.output_path", cfg.Logging.OutputPath)
 	v.SetDefault("logging.no_color", cfg.Logging.NoColor)
 
+	v.SetDefault("localization.language", cfg.Localization.Language)
+
+	v.SetDefault("workspace.roots", cfg.Workspace.Roots)
+	v.SetDefault("workspace.ignore", cfg.Workspace.Ignore)
+	v.SetDefault("workspace.max_files", cfg.Workspace.MaxFiles)
+	v.SetDefault("workspace.history_dir", cfg.Workspace.HistoryDir)
+	v.SetDefault("workspace.history_limit", cfg.Workspace.HistoryLimit)
+	v.SetDefault("write_policy.read_only", cfg.WritePolicy.ReadOnly)
+	v.SetDefault("write_policy.allow_paths", cfg.WritePolicy.AllowPaths)
+	v.SetDefault("write_policy.require_confirmation", cfg.WritePolicy.RequireConfirmation)
+
+	v.SetDefault("review.thresholds.function_lines", cfg.Review.Thresholds.FunctionLines)
+	v.SetDefault("review.thresholds.parameters", cfg.Review.Thresholds.Parameters)
+	v.SetDefault("review.thresholds.struct_fields", cfg.Review.Thresholds.StructFields)
+	v.SetDefault("review.thresholds.complexity", cfg.Review.Thresholds.Complexity)
+	v.SetDefault("review.guidelines_max_size", cfg.Review.GuidelinesMaxSize)
+	v.SetDefault("review.plugins", cfg.Review.Plugins)
+	v.SetDefault("review.wasm_plugins", cfg.Review.WasmPlugins)
+	v.SetDefault("review.wasm_limits.max_memory_mb", cfg.Review.WasmLimits.MaxMemoryMB)
+	v.SetDefault("review.wasm_limits.timeout", cfg.Review.WasmLimits.Timeout)
+	v.SetDefault("review.rule_packs_dir", cfg.Review.RulePacksDir)
+	v.SetDefault("review.rule_packs", cfg.Review.RulePacks)
+
+	v.SetDefault("scaffold.template_dir", cfg.Scaffold.TemplateDir)
+	v.SetDefault("scaffold.go_version", cfg.Scaffold.GoVersion)
+	v.SetDefault("test_gen.style.naming", cfg.TestGen.Style.Naming)
+	v.SetDefault("test_gen.style.layout", cfg.TestGen.Style.Layout)
+	v.SetDefault("test_gen.style.assert", cfg.TestGen.Style.Assert)
+
+	v.SetDefault("preflight.enabled", cfg.Preflight.Enabled)
+	v.SetDefault("preflight.on_failure", cfg.Preflight.OnFailure)
+	v.SetDefault("preflight.timeout", cfg.Preflight.Timeout)
+	v.SetDefault("preflight.warm_packages", cfg.Preflight.WarmPackages)
+	v.SetDefault("preflight.container_mode", cfg.Preflight.ContainerMode)
+
+	v.SetDefault("cache.enabled", cfg.Cache.Enabled)
+	v.SetDefault("cache.ttl", cfg.Cache.TTL)
+	v.SetDefault("cache.max_entries", cfg.Cache.MaxEntries)
+
+	v.SetDefault("idempotency.enabled", cfg.Idempotency.Enabled)
+	v.SetDefault("idempotency.ttl", cfg.Idempotency.TTL)
+	v.SetDefault("idempotency.max_entries", cfg.Idempotency.MaxEntries)
+
+	v.SetDefault("artifacts.enabled", cfg.Artifacts.Enabled)
+	v.SetDefault("artifacts.ttl", cfg.Artifacts.TTL)
+	v.SetDefault("artifacts.max_entries", cfg.Artifacts.MaxEntries)
+	v.SetDefault("artifacts.threshold", cfg.Artifacts.Threshold)
+
+	v.SetDefault("grpc.enabled", cfg.GRPC.Enabled)
+	v.SetDefault("grpc.address", cfg.GRPC.Address)
+
+	v.SetDefault("pr_review.address", cfg.PRReview.Address)
+	v.SetDefault("pr_review.fail_on", cfg.PRReview.FailOn)
+	v.SetDefault("pr_review.workers", cfg.PRReview.Workers)
+	v.SetDefault("pr_review.queue_size", cfg.PRReview.QueueSize)
+	v.SetDefault("pr_review.github.enabled", cfg.PRReview.GitHub.Enabled)
+	v.SetDefault("pr_review.github.path", cfg.PRReview.GitHub.Path)
+	v.SetDefault("pr_review.github.app_id", cfg.PRReview.GitHub.AppID)
+	v.SetDefault("pr_review.github.private_key_file", cfg.PRReview.GitHub.PrivateKeyFile)
+	v.SetDefault("pr_review.github.webhook_secret", cfg.PRReview.GitHub.WebhookSecret)
+	v.SetDefault("pr_review.github.api_url", cfg.PRReview.GitHub.APIURL)
+	v.SetDefault("pr_review.github.check_name", cfg.PRReview.GitHub.CheckName)
+	v.SetDefault("pr_review.gitlab.enabled", cfg.PRReview.GitLab.Enabled)
+	v.SetDefault("pr_review.gitlab.path", cfg.PRReview.GitLab.Path)
+	v.SetDefault("pr_review.gitlab.url", cfg.PRReview.GitLab.URL)
+	v.SetDefault("pr_review.gitlab.token", cfg.PRReview.GitLab.Token)
+	v.SetDefault("pr_review.gitlab.webhook_secret", cfg.PRReview.GitLab.WebhookSecret)
+	v.SetDefault("pr_review.gitlab.status_name", cfg.PRReview.GitLab.StatusName)
+	v.SetDefault("pr_review.bitbucket.enabled", cfg.PRReview.Bitbucket.Enabled)
+	v.SetDefault("pr_review.bitbucket.path", cfg.PRReview.Bitbucket.Path)
+	v.SetDefault("pr_review.bitbucket.api_url", cfg.PRReview.Bitbucket.APIURL)
+	v.SetDefault("pr_review.bitbucket.token", cfg.PRReview.Bitbucket.Token)
+	v.SetDefault("pr_review.bitbucket.webhook_secret", cfg.PRReview.Bitbucket.WebhookSecret)
+	v.SetDefault("pr_review.bitbucket.report_id", cfg.PRReview.Bitbucket.ReportID)
+
+	// Concurrency defaults
+	v.SetDefault("concurrency.enabled", cfg.Concurrency.Enabled)
+	v.SetDefault("concurrency.max_concurrent", cfg.Concurrency.MaxConcurrent)
+	v.SetDefault("concurrency.queue_size", cfg.Concurrency.QueueSize)
+	v.SetDefault("concurrency.max_wait", cfg.Concurrency.MaxWait)
+
 	v.SetDefault("metrics.enabled", cfg.Metrics.Enabled)
 	v.SetDefault("metrics.path", cfg.Metrics.Path)
+	v.SetDefault("metrics.snapshot_path", cfg.Metrics.SnapshotPath)
+	v.SetDefault("metrics.snapshot_interval", cfg.Metrics.SnapshotInterval)
+	v.SetDefault("metrics.latency_budgets", cfg.Metrics.LatencyBudgets)
 
 	v.SetDefault("tools.godoc_timeout", cfg.Tools.GoDocTimeout)
 	v.SetDefault("tools.code_review_timeout", cfg.Tools.CodeReviewTimeout)
 	v.SetDefault("tools.test_gen_timeout", cfg.Tools.TestGenTimeout)
+	v.SetDefault("tools.mod_review_timeout", cfg.Tools.ModReviewTimeout)
+	v.SetDefault("tools.escape_analysis_timeout", cfg.Tools.EscapeAnalysisTimeout)
+	v.SetDefault("tools.implementations_timeout", cfg.Tools.ImplementationsTimeout)
+	v.SetDefault("tools.call_graph_timeout", cfg.Tools.CallGraphTimeout)
+	v.SetDefault("tools.struct_schema_timeout", cfg.Tools.StructSchemaTimeout)
+	v.SetDefault("tools.grpc_review_timeout", cfg.Tools.GRPCReviewTimeout)
+	v.SetDefault("tools.large_input_threshold", cfg.Tools.LargeInputThreshold)
 
 	// Circuit breaker defaults
 	v.SetDefault("tools.godoc_circuit_breaker.max_failures", cfg.Tools.GoDocCircuitBreaker.MaxFailures)
 	v.SetDefault("tools.godoc_circuit_breaker.timeout", cfg.Tools.GoDocCircuitBreaker.Timeout)
 	v.SetDefault("tools.godoc_circuit_breaker.max_half_open_requests", cfg.Tools.GoDocCircuitBreaker.MaxHalfOpenRequests)
+	v.SetDefault("tools.godoc_circuit_breaker.ignore_cancellations", cfg.Tools.GoDocCircuitBreaker.IgnoreCancellations)
 	v.SetDefault("tools.code_review_circuit_breaker.max_failures", cfg.Tools.CodeReviewCircuitBreaker.MaxFailures)
 	v.SetDefault("tools.code_review_circuit_breaker.timeout", cfg.Tools.CodeReviewCircuitBreaker.Timeout)
 	v.SetDefault("tools.code_review_circuit_breaker.max_half_open_requests", cfg.Tools.CodeReviewCircuitBreaker.MaxHalfOpenRequests)
+	v.SetDefault("tools.code_review_circuit_breaker.ignore_cancellations", cfg.Tools.CodeReviewCircuitBreaker.IgnoreCancellations)
 	v.SetDefault("tools.test_gen_circuit_breaker.max_failures", cfg.Tools.TestGenCircuitBreaker.MaxFailures)
 	v.SetDefault("tools.test_gen_circuit_breaker.timeout", cfg.Tools.TestGenCircuitBreaker.Timeout)
 	v.SetDefault("tools.test_gen_circuit_breaker.max_half_open_requests", cfg.Tools.TestGenCircuitBreaker.MaxHalfOpenRequests)
+	v.SetDefault("tools.test_gen_circuit_breaker.ignore_cancellations", cfg.Tools.TestGenCircuitBreaker.IgnoreCancellations)
 
 	v.SetDefault("timeouts.default", cfg.Timeouts.Default)
 	v.SetDefault("timeouts.shutdown", cfg.Timeouts.Shutdown)
@@ -445,6 +1288,10 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("validations.allowed_chars", cfg.Validations.AllowedChars)
 	v.SetDefault("validations.enabled_rules", cfg.Validations.EnabledRules)
 	v.SetDefault("validations.disabled_rules", cfg.Validations.DisabledRules)
+	v.SetDefault("validations.allowed_roots", cfg.Validations.AllowedRoots)
+	v.SetDefault("validations.code_safety.mode", cfg.Validations.CodeSafety.Mode)
+	v.SetDefault("validations.code_safety.patterns", cfg.Validations.CodeSafety.Patterns)
+	v.SetDefault("validations.code_safety.allowlist", cfg.Validations.CodeSafety.Allowlist)
 
 	// Rate limiting
 	v.SetDefault("rate_limit.enabled", cfg.RateLimit.Enabled)
@@ -453,6 +1300,9 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("rate_limit.mode", cfg.RateLimit.Mode)
 	v.SetDefault("rate_limit.algorithm", cfg.RateLimit.Algorithm)
 	v.SetDefault("rate_limit.store_type", cfg.RateLimit.StoreType)
+	v.SetDefault("rate_limit.max_keys", cfg.RateLimit.MaxKeys)
+	v.SetDefault("rate_limit.behavior", cfg.RateLimit.Behavior)
+	v.SetDefault("rate_limit.max_wait", cfg.RateLimit.MaxWait)
 
 	// Retry
 	v.SetDefault("retry.enabled", cfg.Retry.Enabled)
@@ -466,11 +1316,17 @@ func setDefaults(v *viper.Viper) {
 
 // bindEnvVars binds environment variables to config keys
 func bindEnvVars(v *viper.Viper) {
+	// Profile
+	_ = v.BindEnv("profile", "MCP_PROFILE")
+
 	// Server
 	_ = v.BindEnv("server.host", "MCP_HOST")
 	_ = v.BindEnv("server.port", "MCP_PORT")
 	_ = v.BindEnv("server.read_timeout", "MCP_READ_TIMEOUT")
 	_ = v.BindEnv("server.write_timeout", "MCP_WRITE_TIMEOUT")
+	_ = v.BindEnv("server.transport", "MCP_TRANSPORT")
+	_ = v.BindEnv("server.socket_path", "MCP_SOCKET_PATH")
+	_ = v.BindEnv("server.max_clients", "MCP_MAX_CLIENTS")
 
 	// Logging
 	_ = v.BindEnv("logging.level", "MCP_LOG_LEVEL")
@@ -478,25 +1334,115 @@ func bindEnvVars(v *viper.Viper) {
 	_ = v.BindEnv("logging.output_path", "MCP_LOG_OUTPUT")
 	_ = v.BindEnv("logging.no_color", "MCP_LOG_NO_COLOR")
 
+	// Localization
+	_ = v.BindEnv("localization.language", "MCP_LANGUAGE")
+
+	// Workspace
+	_ = v.BindEnv("workspace.max_files", "MCP_WORKSPACE_MAX_FILES")
+	_ = v.BindEnv("workspace.history_dir", "MCP_WORKSPACE_HISTORY_DIR")
+
+	// Write policy
+	_ = v.BindEnv("write_policy.read_only", "MCP_WRITE_POLICY_READ_ONLY")
+	_ = v.BindEnv("write_policy.require_confirmation", "MCP_WRITE_POLICY_REQUIRE_CONFIRMATION")
+
+	// Review
+	_ = v.BindEnv("review.thresholds.function_lines", "MCP_REVIEW_FUNCTION_LINES")
+	_ = v.BindEnv("review.thresholds.parameters", "MCP_REVIEW_PARAMETERS")
+	_ = v.BindEnv("review.thresholds.struct_fields", "MCP_REVIEW_STRUCT_FIELDS")
+	_ = v.BindEnv("review.thresholds.complexity", "MCP_REVIEW_COMPLEXITY")
+	_ = v.BindEnv("review.guidelines_max_size", "MCP_REVIEW_GUIDELINES_MAX_SIZE")
+
+	// Scaffold
+	_ = v.BindEnv("scaffold.template_dir", "MCP_SCAFFOLD_TEMPLATE_DIR")
+	_ = v.BindEnv("scaffold.go_version", "MCP_SCAFFOLD_GO_VERSION")
+
+	// Test generation
+	_ = v.BindEnv("test_gen.style.naming", "MCP_TEST_GEN_NAMING")
+	_ = v.BindEnv("test_gen.style.layout", "MCP_TEST_GEN_LAYOUT")
+	_ = v.BindEnv("test_gen.style.assert", "MCP_TEST_GEN_ASSERT")
+
+	// Preflight
+	_ = v.BindEnv("preflight.enabled", "MCP_PREFLIGHT_ENABLED")
+	_ = v.BindEnv("preflight.on_failure", "MCP_PREFLIGHT_ON_FAILURE")
+	_ = v.BindEnv("preflight.timeout", "MCP_PREFLIGHT_TIMEOUT")
+	_ = v.BindEnv("preflight.container_mode", "MCP_PREFLIGHT_CONTAINER_MODE")
+
+	// Cache
+	_ = v.BindEnv("cache.enabled", "MCP_CACHE_ENABLED")
+	_ = v.BindEnv("cache.ttl", "MCP_CACHE_TTL")
+	_ = v.BindEnv("cache.max_entries", "MCP_CACHE_MAX_ENTRIES")
+
+	// Idempotency
+	_ = v.BindEnv("idempotency.enabled", "MCP_IDEMPOTENCY_ENABLED")
+	_ = v.BindEnv("idempotency.ttl", "MCP_IDEMPOTENCY_TTL")
+	_ = v.BindEnv("idempotency.max_entries", "MCP_IDEMPOTENCY_MAX_ENTRIES")
+
+	// Artifacts
+	_ = v.BindEnv("artifacts.enabled", "MCP_ARTIFACTS_ENABLED")
+	_ = v.BindEnv("artifacts.ttl", "MCP_ARTIFACTS_TTL")
+	_ = v.BindEnv("artifacts.max_entries", "MCP_ARTIFACTS_MAX_ENTRIES")
+	_ = v.BindEnv("artifacts.threshold", "MCP_ARTIFACTS_THRESHOLD")
+
+	// gRPC API
+	_ = v.BindEnv("grpc.enabled", "MCP_GRPC_ENABLED")
+	_ = v.BindEnv("grpc.address", "MCP_GRPC_ADDRESS")
+
+	// Pull request review
+	_ = v.BindEnv("pr_review.address", "MCP_PR_REVIEW_ADDRESS")
+	_ = v.BindEnv("pr_review.fail_on", "MCP_PR_REVIEW_FAIL_ON")
+	_ = v.BindEnv("pr_review.workers", "MCP_PR_REVIEW_WORKERS")
+	_ = v.BindEnv("pr_review.queue_size", "MCP_PR_REVIEW_QUEUE_SIZE")
+	_ = v.BindEnv("pr_review.github.enabled", "MCP_PR_REVIEW_GITHUB_ENABLED")
+	_ = v.BindEnv("pr_review.github.app_id", "MCP_PR_REVIEW_GITHUB_APP_ID")
+	_ = v.BindEnv("pr_review.github.private_key_file", "MCP_PR_REVIEW_GITHUB_PRIVATE_KEY_FILE")
+	_ = v.BindEnv("pr_review.github.webhook_secret", "MCP_PR_REVIEW_GITHUB_WEBHOOK_SECRET")
+	_ = v.BindEnv("pr_review.github.api_url", "MCP_PR_REVIEW_GITHUB_API_URL")
+	_ = v.BindEnv("pr_review.gitlab.enabled", "MCP_PR_REVIEW_GITLAB_ENABLED")
+	_ = v.BindEnv("pr_review.gitlab.url", "MCP_PR_REVIEW_GITLAB_URL")
+	_ = v.BindEnv("pr_review.gitlab.token", "MCP_PR_REVIEW_GITLAB_TOKEN")
+	_ = v.BindEnv("pr_review.gitlab.webhook_secret", "MCP_PR_REVIEW_GITLAB_WEBHOOK_SECRET")
+	_ = v.BindEnv("pr_review.bitbucket.enabled", "MCP_PR_REVIEW_BITBUCKET_ENABLED")
+	_ = v.BindEnv("pr_review.bitbucket.api_url", "MCP_PR_REVIEW_BITBUCKET_API_URL")
+	_ = v.BindEnv("pr_review.bitbucket.token", "MCP_PR_REVIEW_BITBUCKET_TOKEN")
+	_ = v.BindEnv("pr_review.bitbucket.webhook_secret", "MCP_PR_REVIEW_BITBUCKET_WEBHOOK_SECRET")
+
+	// Concurrency
+	_ = v.BindEnv("concurrency.enabled", "MCP_CONCURRENCY_ENABLED")
+	_ = v.BindEnv("concurrency.max_concurrent", "MCP_CONCURRENCY_MAX_CONCURRENT")
+	_ = v.BindEnv("concurrency.queue_size", "MCP_CONCURRENCY_QUEUE_SIZE")
+	_ = v.BindEnv("concurrency.max_wait", "MCP_CONCURRENCY_MAX_WAIT")
+
 	// Metrics
 	_ = v.BindEnv("metrics.enabled", "MCP_METRICS_ENABLED")
 	_ = v.BindEnv("metrics.path", "MCP_METRICS_PATH")
+	_ = v.BindEnv("metrics.snapshot_path", "MCP_METRICS_SNAPSHOT_PATH")
+	_ = v.BindEnv("metrics.snapshot_interval", "MCP_METRICS_SNAPSHOT_INTERVAL")
 
 	// Tools
 	_ = v.BindEnv("tools.godoc_timeout", "MCP_GODOC_TIMEOUT")
 	_ = v.BindEnv("tools.code_review_timeout", "MCP_CODE_REVIEW_TIMEOUT")
 	_ = v.BindEnv("tools.test_gen_timeout", "MCP_TEST_GEN_TIMEOUT")
+	_ = v.BindEnv("tools.mod_review_timeout", "MCP_MOD_REVIEW_TIMEOUT")
+	_ = v.BindEnv("tools.escape_analysis_timeout", "MCP_ESCAPE_ANALYSIS_TIMEOUT")
+	_ = v.BindEnv("tools.implementations_timeout", "MCP_IMPLEMENTATIONS_TIMEOUT")
+	_ = v.BindEnv("tools.call_graph_timeout", "MCP_CALL_GRAPH_TIMEOUT")
+	_ = v.BindEnv("tools.struct_schema_timeout", "MCP_STRUCT_SCHEMA_TIMEOUT")
+	_ = v.BindEnv("tools.grpc_review_timeout", "MCP_GRPC_REVIEW_TIMEOUT")
+	_ = v.BindEnv("tools.large_input_threshold", "MCP_LARGE_INPUT_THRESHOLD")
 
 	// Circuit breaker settings
 	_ = v.BindEnv("tools.godoc_circuit_breaker.max_failures", "MCP_GODOC_CB_MAX_FAILURES")
 	_ = v.BindEnv("tools.godoc_circuit_breaker.timeout", "MCP_GODOC_CB_TIMEOUT")
 	_ = v.BindEnv("tools.godoc_circuit_breaker.max_half_open_requests", "MCP_GODOC_CB_MAX_HALF_OPEN")
+	_ = v.BindEnv("tools.godoc_circuit_breaker.ignore_cancellations", "MCP_GODOC_CB_IGNORE_CANCELLATIONS")
 	_ = v.BindEnv("tools.code_review_circuit_breaker.max_failures", "MCP_CODE_REVIEW_CB_MAX_FAILURES")
 	_ = v.BindEnv("tools.code_review_circuit_breaker.timeout", "MCP_CODE_REVIEW_CB_TIMEOUT")
 	_ = v.BindEnv("tools.code_review_circuit_breaker.max_half_open_requests", "MCP_CODE_REVIEW_CB_MAX_HALF_OPEN")
+	_ = v.BindEnv("tools.code_review_circuit_breaker.ignore_cancellations", "MCP_CODE_REVIEW_CB_IGNORE_CANCELLATIONS")
 	_ = v.BindEnv("tools.test_gen_circuit_breaker.max_failures", "MCP_TEST_GEN_CB_MAX_FAILURES")
 	_ = v.BindEnv("tools.test_gen_circuit_breaker.timeout", "MCP_TEST_GEN_CB_TIMEOUT")
 	_ = v.BindEnv("tools.test_gen_circuit_breaker.max_half_open_requests", "MCP_TEST_GEN_CB_MAX_HALF_OPEN")
+	_ = v.BindEnv("tools.test_gen_circuit_breaker.ignore_cancellations", "MCP_TEST_GEN_CB_IGNORE_CANCELLATIONS")
 
 	// Timeouts
 	_ = v.BindEnv("timeouts.default", "MCP_TIMEOUT_DEFAULT")
@@ -507,6 +1453,7 @@ func bindEnvVars(v *viper.Viper) {
 	// Validations
 	_ = v.BindEnv("validations.max_input_size", "MCP_VALIDATION_MAX_INPUT_SIZE")
 	_ = v.BindEnv("validations.allowed_chars", "MCP_VALIDATION_ALLOWED_CHARS")
+	_ = v.BindEnv("validations.code_safety.mode", "MCP_VALIDATION_CODE_SAFETY_MODE")
 
 	// Rate limiting
 	_ = v.BindEnv("rate_limit.enabled", "MCP_RATELIMIT_ENABLED")
@@ -515,6 +1462,9 @@ func bindEnvVars(v *viper.Viper) {
 	_ = v.BindEnv("rate_limit.mode", "MCP_RATELIMIT_MODE")
 	_ = v.BindEnv("rate_limit.algorithm", "MCP_RATELIMIT_ALGORITHM")
 	_ = v.BindEnv("rate_limit.store_type", "MCP_RATELIMIT_STORE_TYPE")
+	_ = v.BindEnv("rate_limit.max_keys", "MCP_RATELIMIT_MAX_KEYS")
+	_ = v.BindEnv("rate_limit.behavior", "MCP_RATELIMIT_BEHAVIOR")
+	_ = v.BindEnv("rate_limit.max_wait", "MCP_RATELIMIT_MAX_WAIT")
 
 	// Error handling
 	_ = v.BindEnv("error_handling.verbosity", "MCP_ERROR_VERBOSITY")
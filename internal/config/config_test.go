@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -65,6 +66,26 @@ func TestConfig_Validate(t *testing.T) {
 			}(),
 			wantErr: true,
 		},
+		{
+			name: "invalid transport",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Server.Transport = "tcp"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "unix transport",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Server.Transport = TransportUnix
+				cfg.Server.SocketPath = "/run/mcp.sock"
+				cfg.Server.MaxClients = 8
+				return cfg
+			}(),
+			wantErr: false,
+		},
 		{
 			name: "invalid log level",
 			config: func() *Config {
@@ -101,6 +122,252 @@ func TestConfig_Validate(t *testing.T) {
 			}(),
 			wantErr: true,
 		},
+		{
+			name: "unsupported language",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Localization.Language = "fr"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "invalid preflight failure mode",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Preflight.OnFailure = "ignore"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "invalid preflight container mode",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Preflight.ContainerMode = "docker"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "zero preflight timeout",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Preflight.Timeout = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "zero cache max entries",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Cache.MaxEntries = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "disabled cache ignores limits",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Cache.Enabled = false
+				cfg.Cache.TTL = 0
+				return cfg
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "zero idempotency ttl",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Idempotency.TTL = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "zero artifact threshold",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Artifacts.Threshold = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "disabled artifacts ignore threshold",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Artifacts.Enabled = false
+				cfg.Artifacts.Threshold = 0
+				return cfg
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "grpc without address",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.GRPC.Enabled = true
+				cfg.GRPC.Address = ""
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "github review without credentials",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.PRReview.GitHub.Enabled = true
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "gitlab review with token",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.PRReview.GitLab.Enabled = true
+				cfg.PRReview.GitLab.Token = "token"
+				cfg.PRReview.GitLab.WebhookSecret = "secret"
+				return cfg
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "review providers sharing a path",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.PRReview.GitLab = PRReviewGitLabConfig{Enabled: true, Path: "/webhook", Token: "token", WebhookSecret: "secret"}
+				cfg.PRReview.Bitbucket = PRReviewBitbucketConfig{Enabled: true, Path: "/webhook", Token: "token", WebhookSecret: "secret"}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "review with invalid fail_on",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.PRReview.Bitbucket.Enabled = true
+				cfg.PRReview.Bitbucket.Token = "token"
+				cfg.PRReview.Bitbucket.WebhookSecret = "secret"
+				cfg.PRReview.FailOn = "severe"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "zero max concurrent",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Concurrency.MaxConcurrent = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "invalid per-tool concurrency",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Concurrency.Tools = map[string]ConcurrencyToolConfig{"code-review": {QueueSize: -1}}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "metrics snapshot without interval",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Metrics.SnapshotPath = "/tmp/metrics.json"
+				cfg.Metrics.SnapshotInterval = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative rate limit max keys",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.RateLimit.MaxKeys = -1
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "invalid rate limit behavior",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.RateLimit.Behavior = "queue"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "rate limit wait without max wait",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.RateLimit.Behavior = "wait"
+				cfg.RateLimit.MaxWait = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "unknown rate limit class",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.RateLimit.Classes["write"] = RateLimitToolConfig{Enabled: true, Limit: 10, Window: time.Minute}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "rate limit class without limit",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.RateLimit.Classes["analyze"] = RateLimitToolConfig{Enabled: true, Window: time.Minute}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "workspace history without limit",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Workspace.HistoryDir = "/tmp/history"
+				cfg.Workspace.HistoryLimit = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative large input threshold",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Tools.LargeInputThreshold = -1
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "zero review threshold",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Review.Thresholds.Complexity = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "zero latency budget",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.Metrics.LatencyBudgets = map[string]time.Duration{"code-review": 0}
+				return cfg
+			}(),
+			wantErr: true,
+		},
 		{
 			name: "valid custom timeouts",
 			config: func() *Config {
@@ -279,6 +546,8 @@ logging:
 metrics:
   enabled: false
   path: "/custom-metrics"
+  latency_budgets:
+    code-review: 500ms
 `
 
 	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
@@ -316,6 +585,10 @@ metrics:
 	if cfg.Metrics.Path != "/custom-metrics" {
 		t.Errorf("expected metrics path '/custom-metrics', got '%s'", cfg.Metrics.Path)
 	}
+
+	if budget := cfg.Metrics.LatencyBudgets["code-review"]; budget != 500*time.Millisecond {
+		t.Errorf("expected code-review latency budget 500ms, got %v", budget)
+	}
 }
 
 func TestLoad_WithEnvVars(t *testing.T) {
@@ -469,3 +742,162 @@ func TestConfig_ToolSpecificConfig(t *testing.T) {
 		t.Errorf("expected test-gen max attempts 2, got %d", cfg.Retry.Tools["test-gen"].MaxAttempts)
 	}
 }
+
+func TestLoad_WithValidationToolOverrides(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+validations:
+  disabled_rules: ["symbol_name"]
+  tools:
+    test-gen:
+      package_name: []
+    go-doc:
+      package_path: ["not_empty", "max_length"]
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+	t.Setenv("MCP_CONFIG", configPath)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if len(cfg.Validations.DisabledRules) != 1 || cfg.Validations.DisabledRules[0] != "symbol_name" {
+		t.Errorf("expected disabled rules [symbol_name], got %v", cfg.Validations.DisabledRules)
+	}
+
+	rules, ok := cfg.Validations.Tools["test-gen"]["package_name"]
+	if !ok || len(rules) != 0 {
+		t.Errorf("expected empty rule override for test-gen package_name, got %v (present %v)", rules, ok)
+	}
+
+	rules = cfg.Validations.Tools["go-doc"]["package_path"]
+	if len(rules) != 2 || rules[0] != "not_empty" || rules[1] != "max_length" {
+		t.Errorf("expected go-doc package_path override, got %v", rules)
+	}
+}
+
+func TestCodeSafetyConfig_Rule(t *testing.T) {
+	cfg := DefaultConfig().Validations.CodeSafety
+	cfg.Allowlist = []string{"eval("}
+	cfg.Tools["code-review"] = CodeSafetyToolConfig{Mode: "warn", Allowlist: []string{"exec.Command("}}
+	cfg.Tools["test-gen"] = CodeSafetyToolConfig{Patterns: []string{"unsafe.Pointer("}}
+
+	shared := cfg.Rule("")
+	if shared.Mode != "block" || shared.Patterns != nil || len(shared.Allowlist) != 1 {
+		t.Errorf("unexpected shared rule: %+v", shared)
+	}
+
+	review := cfg.Rule("code-review")
+	if review.Mode != "warn" || len(review.Allowlist) != 2 || review.Allowlist[1] != "exec.Command(" {
+		t.Errorf("unexpected code-review rule: %+v", review)
+	}
+
+	testGen := cfg.Rule("test-gen")
+	if testGen.Mode != "block" || len(testGen.Patterns) != 1 || testGen.Patterns[0] != "unsafe.Pointer(" {
+		t.Errorf("unexpected test-gen rule: %+v", testGen)
+	}
+
+	cfg.Tools["code-review"] = CodeSafetyToolConfig{Mode: "off"}
+	full := DefaultConfig()
+	full.Validations.CodeSafety = cfg
+	if err := full.Validate(); err == nil || !strings.Contains(err.Error(), "code-review") {
+		t.Errorf("expected invalid mode error for code-review, got %v", err)
+	}
+}
+
+func TestConcurrencyConfig_ToQueueConfig(t *testing.T) {
+	cfg := DefaultConfig().Concurrency
+	cfg.Tools = map[string]ConcurrencyToolConfig{
+		"code-review": {MaxConcurrent: 2, MaxWait: time.Minute},
+	}
+
+	got := cfg.ToQueueConfig("code-review")
+	if got.MaxConcurrent != 2 || got.QueueSize != cfg.QueueSize || got.MaxWait != time.Minute {
+		t.Errorf("expected overrides merged with defaults, got %+v", got)
+	}
+
+	if got := cfg.ToQueueConfig("go-doc"); got.MaxConcurrent != cfg.MaxConcurrent {
+		t.Errorf("expected global settings for tool without overrides, got %+v", got)
+	}
+}
+
+func TestLoad_WithProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+logging:
+  level: "debug"
+  format: "console"
+error_handling:
+  expose_details: true
+rate_limit:
+  limit: 100
+  tools:
+    code-review:
+      enabled: true
+      limit: 30
+      window: 1m
+profiles:
+  prod:
+    logging:
+      format: "json"
+    error_handling:
+      expose_details: false
+    rate_limit:
+      limit: 20
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+	t.Setenv("MCP_CONFIG", configPath)
+
+	t.Run("base", func(t *testing.T) {
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.Profile != "" || cfg.Logging.Format != "console" || !cfg.ErrorHandling.ExposeDetails || cfg.RateLimit.Limit != 100 {
+			t.Errorf("expected the base settings without a profile, got %+v", cfg)
+		}
+	})
+
+	t.Run("prod", func(t *testing.T) {
+		t.Setenv("MCP_PROFILE", "prod")
+		t.Setenv("MCP_LOG_LEVEL", "warn")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.Profile != "prod" {
+			t.Errorf("expected profile prod, got %q", cfg.Profile)
+		}
+		if cfg.Logging.Format != "json" || cfg.ErrorHandling.ExposeDetails || cfg.RateLimit.Limit != 20 {
+			t.Errorf("expected the prod overlay to apply, got logging %+v, expose_details %v, limit %d",
+				cfg.Logging, cfg.ErrorHandling.ExposeDetails, cfg.RateLimit.Limit)
+		}
+		if cfg.Logging.Level != "warn" {
+			t.Errorf("expected the environment to override the profile, got level %q", cfg.Logging.Level)
+		}
+		if cfg.RateLimit.Tools["code-review"].Limit != 30 {
+			t.Errorf("expected base settings outside the overlay to be kept, got %+v", cfg.RateLimit.Tools)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Setenv("MCP_PROFILE", "staging")
+
+		_, err := Load()
+		if err == nil || !strings.Contains(err.Error(), `unknown config profile "staging"`) {
+			t.Errorf("expected an unknown profile error, got %v", err)
+		}
+	})
+}
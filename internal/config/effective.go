@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces the values of secret settings in Settings
+const Redacted = "[REDACTED]"
+
+// secretKeyParts mark setting names whose values are never printed
+var secretKeyParts = []string{"password", "secret", "token", "api_key", "credential", "private_key"}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Settings returns the configuration as nested maps keyed like the config
+// file. Durations are rendered as strings and secret values are redacted,
+// so the result can be printed and read back as a config file.
+func (c *Config) Settings() map[string]any {
+	settings, _ := settingsOf(reflect.ValueOf(*c)).(map[string]any)
+	return settings
+}
+
+// EffectiveYAML returns Settings as YAML
+func (c *Config) EffectiveYAML() ([]byte, error) {
+	data, err := yaml.Marshal(c.Settings())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	return data, nil
+}
+
+// settingsOf converts v to the values Settings returns
+func settingsOf(v reflect.Value) any {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		settings := make(map[string]any, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			key, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if key == "-" {
+				continue
+			}
+			if key == "" {
+				key = strings.ToLower(field.Name)
+			}
+			settings[key] = settingOf(key, v.Field(i))
+		}
+		return settings
+	case reflect.Map:
+		settings := make(map[string]any, v.Len())
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, k := range keys {
+			key := fmt.Sprint(k.Interface())
+			settings[key] = settingOf(key, v.MapIndex(k))
+		}
+		return settings
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return []any{}
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = settingsOf(v.Index(i))
+		}
+		return items
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return settingsOf(v.Elem())
+	default:
+		return v.Interface()
+	}
+}
+
+// settingOf converts the value of the setting named key, redacting it if
+// the name marks a secret
+func settingOf(key string, v reflect.Value) any {
+	if isSecretKey(key) && !v.IsZero() {
+		return Redacted
+	}
+	return settingsOf(v)
+}
+
+// isSecretKey reports whether a setting named key holds a secret
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
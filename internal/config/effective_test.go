@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfig_Settings(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ErrorHandling.CategoryMappings = map[string]string{
+		"timeout":      "transient",
+		"github_token": "ghp_example",
+	}
+
+	settings := cfg.Settings()
+
+	server, ok := settings["server"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected server settings, got %T", settings["server"])
+	}
+	if server["port"] != cfg.Server.Port {
+		t.Errorf("expected port %d, got %v", cfg.Server.Port, server["port"])
+	}
+	if server["read_timeout"] != cfg.Server.ReadTimeout.String() {
+		t.Errorf("expected read_timeout %q, got %v", cfg.Server.ReadTimeout, server["read_timeout"])
+	}
+
+	mappings := settings["error_handling"].(map[string]any)["category_mappings"].(map[string]any)
+	if mappings["github_token"] != Redacted {
+		t.Errorf("expected the token to be redacted, got %v", mappings["github_token"])
+	}
+	if mappings["timeout"] != "transient" {
+		t.Errorf("expected other values to be kept, got %v", mappings["timeout"])
+	}
+}
+
+func TestConfig_EffectiveYAML_RoundTrip(t *testing.T) {
+	t.Setenv("MCP_PORT", "9999")
+
+	cfg, err := LoadFile("")
+	if err != nil {
+		t.Fatalf("LoadFile() failed: %v", err)
+	}
+	data, err := cfg.EffectiveYAML()
+	if err != nil {
+		t.Fatalf("EffectiveYAML() failed: %v", err)
+	}
+	if !strings.Contains(string(data), "port: 9999") {
+		t.Errorf("expected the environment override in the output, got:\n%s", data)
+	}
+
+	path := filepath.Join(t.TempDir(), "effective.yaml")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Setenv("MCP_PORT", "")
+
+	reloaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() of the printed configuration failed: %v", err)
+	}
+	if reloaded.Server.Port != 9999 || reloaded.Cache.TTL != cfg.Cache.TTL {
+		t.Errorf("expected the printed configuration to load back, got port %d and cache ttl %v", reloaded.Server.Port, reloaded.Cache.TTL)
+	}
+}
@@ -0,0 +1,171 @@
+// Package daemon serves MCP sessions to several clients at once over a
+// Unix domain socket, for servers shared by a team
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/ratelimit"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// listenFDsStart is the first file descriptor passed by socket activation
+const listenFDsStart = 3
+
+// socketMode lets the owner and group of the socket connect
+const socketMode = 0o660
+
+// Listen returns the listener of the daemon: the socket passed by systemd
+// socket activation when there is one, else a new Unix socket at path. A
+// stale socket left at path by a previous run is replaced.
+func Listen(path string) (net.Listener, error) {
+	if ln, err := activatedListener(); ln != nil || err != nil {
+		return ln, err
+	}
+	if path == "" {
+		return nil, fmt.Errorf("socket_path is required without socket activation")
+	}
+
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s is in use by another server", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, socketMode); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("failed to set socket permissions: %w", err)
+	}
+	return ln, nil
+}
+
+// activatedListener returns the first socket passed with the LISTEN_PID and
+// LISTEN_FDS protocol of systemd, or nil when the process was not socket
+// activated
+func activatedListener() (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, nil
+	}
+
+	file := os.NewFile(listenFDsStart, "LISTEN_FD_3")
+	defer file.Close()
+	ln, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use activated socket: %w", err)
+	}
+	return ln, nil
+}
+
+// Options configures Serve
+type Options struct {
+	MaxClients int // Connected clients at once; 0 is unlimited
+	Logger     *logging.Logger
+}
+
+// Serve accepts clients on ln until ctx is done, connecting each to server
+// in its own session. Calls of a client carry its rate-limit identity,
+// client-1, client-2 and so on in order of connection. When ctx is done,
+// the listener and open sessions are closed and Serve returns nil after
+// the sessions end.
+func Serve(ctx context.Context, ln net.Listener, server *mcp.Server, opts Options) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		sessions = make(map[*mcp.ServerSession]bool)
+		clients  int
+	)
+
+	stop := context.AfterFunc(ctx, func() {
+		ln.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for ss := range sessions {
+			ss.Close()
+		}
+	})
+	defer stop()
+
+	for n := 1; ; n++ {
+		conn, err := ln.Accept()
+		if err != nil {
+			wg.Wait()
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("failed to accept client: %w", err)
+		}
+
+		id := fmt.Sprintf("client-%d", n)
+		mu.Lock()
+		full := opts.MaxClients > 0 && clients >= opts.MaxClients
+		if !full {
+			clients++
+		}
+		mu.Unlock()
+		if full {
+			opts.Logger.WarnEvent().Str("client", id).Int("max_clients", opts.MaxClients).Msg("client rejected: too many clients")
+			conn.Close()
+			continue
+		}
+
+		transport := &mcp.IOTransport{Reader: conn, Writer: conn}
+		ss, err := server.Connect(ratelimit.WithClientID(ctx, id), transport, nil)
+		if err != nil {
+			opts.Logger.WarnEvent().Str("client", id).Err(err).Msg("failed to connect client")
+			conn.Close()
+			mu.Lock()
+			clients--
+			mu.Unlock()
+			continue
+		}
+
+		mu.Lock()
+		sessions[ss] = true
+		mu.Unlock()
+		if ctx.Err() != nil {
+			// Connected while shutting down, after the open sessions were closed
+			ss.Close()
+		}
+		opts.Logger.InfoEvent().Str("client", id).Msg("client connected")
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := ss.Wait()
+			mu.Lock()
+			delete(sessions, ss)
+			clients--
+			mu.Unlock()
+
+			event := opts.Logger.InfoEvent().Str("client", id)
+			if err != nil && ctx.Err() == nil {
+				event = event.Err(err)
+			}
+			event.Msg("client disconnected")
+		}()
+	}
+}
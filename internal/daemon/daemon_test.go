@@ -0,0 +1,220 @@
+package daemon
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/ratelimit"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type whoamiParams struct{}
+
+type whoamiResult struct {
+	ClientID string `json:"client_id"`
+}
+
+// newTestServer returns a server with a whoami tool reporting the
+// rate-limit identity of the caller
+func newTestServer() *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v0.0.1"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "whoami"}, func(ctx context.Context, _ *mcp.CallToolRequest, _ whoamiParams) (*mcp.CallToolResult, whoamiResult, error) {
+		return nil, whoamiResult{ClientID: ratelimit.ClientIDFromContext(ctx)}, nil
+	})
+	return server
+}
+
+// socketPath returns a socket path short enough for the limits of Unix
+// socket addresses
+func socketPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "mcpd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "mcp.sock")
+}
+
+// startDaemon serves a test server on a new socket and returns its path
+// and a function stopping it and returning the result of Serve
+func startDaemon(t *testing.T, maxClients int) (string, func() error) {
+	t.Helper()
+	log, err := logging.New("error", "json", "stderr", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := socketPath(t)
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, ln, newTestServer(), Options{MaxClients: maxClients, Logger: log})
+	}()
+
+	stop := func() error {
+		cancel()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("Serve() did not return after cancel")
+			return nil
+		}
+	}
+	t.Cleanup(func() { cancel() })
+	return path, stop
+}
+
+// connect opens an MCP client session on the socket at path
+func connect(t *testing.T, path string) *mcp.ClientSession {
+	t.Helper()
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("dial %s: %v", path, err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, nil)
+	cs, err := client.Connect(context.Background(), &mcp.IOTransport{Reader: conn, Writer: conn}, nil)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+// whoami returns the client identity the server sees for cs
+func whoami(t *testing.T, cs *mcp.ClientSession) string {
+	t.Helper()
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "whoami"})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	result, ok := res.StructuredContent.(map[string]any)
+	if !ok {
+		t.Fatalf("unexpected structured content %#v", res.StructuredContent)
+	}
+	id, _ := result["client_id"].(string)
+	return id
+}
+
+func TestServe_ConcurrentClients(t *testing.T) {
+	path, stop := startDaemon(t, 0)
+
+	first := connect(t, path)
+	second := connect(t, path)
+
+	// Both sessions stay usable while the other is open
+	for range 2 {
+		if got := whoami(t, first); got != "client-1" {
+			t.Errorf("first client identity = %q, want client-1", got)
+		}
+		if got := whoami(t, second); got != "client-2" {
+			t.Errorf("second client identity = %q, want client-2", got)
+		}
+	}
+
+	if err := stop(); err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the socket to be removed on shutdown, got %v", err)
+	}
+}
+
+func TestServe_MaxClients(t *testing.T) {
+	path, stop := startDaemon(t, 1)
+	defer stop()
+
+	first := connect(t, path)
+	if got := whoami(t, first); got != "client-1" {
+		t.Fatalf("first client identity = %q, want client-1", got)
+	}
+
+	// The second client is disconnected before it can initialize
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, nil)
+	if cs, err := client.Connect(context.Background(), &mcp.IOTransport{Reader: conn, Writer: conn}, nil); err == nil {
+		cs.Close()
+		t.Error("expected the client over max_clients to be rejected")
+	}
+
+	// A slot frees up when a client leaves
+	first.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.Dial("unix", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cs, err := client.Connect(context.Background(), &mcp.IOTransport{Reader: conn, Writer: conn}, nil)
+		if err == nil {
+			defer cs.Close()
+			if got := whoami(t, cs); got == "client-1" || got == "default" {
+				t.Errorf("expected a new identity for the next client, got %q", got)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no slot became free: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestListen(t *testing.T) {
+	path := socketPath(t)
+
+	// A socket left behind by a crashed server is replaced
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer ln.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != socketMode {
+		t.Errorf("socket mode = %v, want %v", info.Mode().Perm(), os.FileMode(socketMode))
+	}
+
+	// A socket in use is not taken over
+	if _, err := Listen(path); err == nil {
+		t.Error("expected an error for a socket in use")
+	}
+
+	// Neither is a regular file
+	file := filepath.Join(filepath.Dir(path), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Listen(file); err == nil {
+		t.Error("expected an error for a path that is not a socket")
+	}
+
+	if _, err := Listen(""); err == nil {
+		t.Error("expected an error without a socket path")
+	}
+}
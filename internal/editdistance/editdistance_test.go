@@ -0,0 +1,29 @@
+package editdistance
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "json", 4},
+		{"json", "", 4},
+		{"json", "json", 0},
+		{"jsno", "json", 2},
+		{"jason", "json", 1},
+		{"linx", "linux", 1},
+		{"ommitempty", "omitempty", 1},
+		{"net/htp", "net/http", 1},
+		{"strings", "strconv", 4},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Levenshtein(tt.b, tt.a); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
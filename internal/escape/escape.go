@@ -0,0 +1,488 @@
+package escape
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mcp-go-assistant/internal/codereview"
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/workspace"
+)
+
+// Options controls which directories an analysis may build
+type Options struct {
+	Roots []string // Registered workspace roots; working_dir and the package must be inside one
+}
+
+// listedPackage mirrors the fields of `go list -json` used by the analysis
+type listedPackage struct {
+	ImportPath string   `json:"ImportPath"`
+	Dir        string   `json:"Dir"`
+	GoFiles    []string `json:"GoFiles"`
+	CgoFiles   []string `json:"CgoFiles"`
+}
+
+var (
+	// outputRegex matches a compiler diagnostic, e.g. "./a.go:8:2: moved to heap: t"
+	outputRegex = regexp.MustCompile(`^(.+\.go):(\d+):(\d+): (.*)$`)
+	// stepRegex matches one step of a -m=2 flow explanation
+	stepRegex = regexp.MustCompile(`^from (.*) \(([^()]+)\) at (.+\.go):(\d+):(\d+)$`)
+	// leakRegex matches "leaking param: p" and "leaking param content: p to result ~r0 level=0"
+	leakRegex         = regexp.MustCompile(`^leaking param( content)?: (\S+)(?: to result (\S+) level=\d+)?$`)
+	canInlineRegex    = regexp.MustCompile(`^can inline (\S+)(?: with cost (\d+))?`)
+	cannotInlineRegex = regexp.MustCompile(`^cannot inline (\S+): (.*)$`)
+)
+
+// reasons describes the flow step kinds of the compiler, in the order they
+// are preferred when a flow has several
+var reasons = []struct {
+	tag        string
+	reason     string
+	suggestion string
+}{
+	{"too large for stack", "too large for the stack", "Large values are allocated on the heap; reuse them through sync.Pool or a field of a long-lived struct"},
+	{"non-constant size", "allocated with a size not known at compile time", "Slices made with a size not known at compile time are heap-allocated; use a constant capacity when the size has a small upper bound"},
+	{"captured by a closure", "captured by a closure that escapes", "Variables captured by escaping closures move to the heap; pass them to the closure as parameters instead"},
+	{"interface-converted", "converted to an interface", "Values escape through interface conversions; keep concrete types on hot paths"},
+	{"return", "returned to the caller", "Values escape because they are returned by reference; return small structs by value or let the caller pass the destination"},
+	{"call parameter", "passed to a call parameter that escapes", "Values escape as arguments of calls such as fmt functions or append; avoid these calls on hot paths or reuse buffers"},
+	{"key of map put", "stored in a map", "Keys and values stored in maps escape; store values instead of pointers where possible"},
+	{"value of map put", "stored in a map", "Keys and values stored in maps escape; store values instead of pointers where possible"},
+	{"assign", "assigned to a location that escapes", "Values escape when assigned to globals, fields of escaping structs or interfaces; keep them local on hot paths"},
+}
+
+// entry is a parsed finding with the flow step kind that explains it
+type entry struct {
+	Finding
+	tag string
+}
+
+// Analyze builds the requested package with -gcflags=-m=2 and returns the
+// compiler's escape and inlining decisions as findings
+func Analyze(ctx context.Context, params EscapeParams, opts Options) (*EscapeResult, error) {
+	if params.WorkingDir == "" {
+		return nil, fmt.Errorf("working_dir parameter is required")
+	}
+	dir, err := workspace.Dir("escape analysis", params.WorkingDir, opts.Roots)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := params.Package
+	if pattern == "" {
+		pattern = "."
+	}
+	if strings.HasPrefix(pattern, "-") || strings.Contains(pattern, "...") {
+		return nil, fmt.Errorf("package must name a single package: %s", pattern)
+	}
+
+	pkg, err := listPackage(ctx, dir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if !workspace.Contains(opts.Roots, pkg.Dir) {
+		return nil, fmt.Errorf("package %s is not inside a registered workspace", pkg.ImportPath)
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "build", "-gcflags=-m=2", "-o", os.DevNull, pkg.ImportPath)
+	cmd.Dir = dir
+	cmd.Env = logging.CommandEnv(ctx, nil)
+	output, err := cmd.CombinedOutput()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("go build failed: %v\nOutput: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	files := make(map[string]bool)
+	for _, name := range append(pkg.GoFiles, pkg.CgoFiles...) {
+		files[name] = true
+	}
+	entries := parseOutput(output, files)
+
+	result := &EscapeResult{
+		Package:     pkg.ImportPath,
+		Findings:    []Finding{},
+		Suggestions: []string{},
+	}
+	funcs := make(map[string][]funcSpan)
+	for _, e := range entries {
+		if _, ok := funcs[e.File]; !ok {
+			funcs[e.File] = parseFuncs(filepath.Join(pkg.Dir, e.File))
+		}
+	}
+
+	tags := make(map[string]int)
+	for _, e := range entries {
+		e.Function = enclosingFunc(funcs[e.File], e.Line)
+		switch e.Kind {
+		case "moved-to-heap", "escapes-to-heap":
+			result.HeapAllocations++
+			tags[e.tag]++
+		case "leaking-param":
+			result.LeakingParams++
+		case "can-inline":
+			result.Inlinable++
+		case "cannot-inline":
+			result.NotInlinable++
+		}
+		if !params.Inlining && (e.Kind == "can-inline" || e.Kind == "cannot-inline") {
+			continue
+		}
+		e.File = relativeFile(dir, pkg.Dir, e.File)
+		result.Findings = append(result.Findings, e.Finding)
+	}
+
+	linkReviewIssues(result, dir, pkg.Dir, funcs)
+	addSuggestions(result, tags, params.Inlining)
+	result.Summary = summarize(result, params.Inlining)
+	return result, nil
+}
+
+// listPackage resolves pattern in dir to exactly one package
+func listPackage(ctx context.Context, dir, pattern string) (*listedPackage, error) {
+	cmd := exec.CommandContext(ctx, "go", "list", "-json", pattern)
+	cmd.Dir = dir
+	cmd.Env = logging.CommandEnv(ctx, nil)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("go list failed: %v\nOutput: %s", err, msg)
+		}
+		return nil, fmt.Errorf("go list failed: %v", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(output))
+	var pkg listedPackage
+	if err := dec.Decode(&pkg); err != nil {
+		return nil, fmt.Errorf("failed to decode go list output: %v", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("package must name a single package: %s", pattern)
+	}
+	return &pkg, nil
+}
+
+// parseOutput converts -m=2 compiler output into findings in files, keyed
+// by base name. Flow explanations precede the finding at the same position.
+func parseOutput(output []byte, files map[string]bool) []entry {
+	flows := make(map[string][]step)
+	seen := make(map[string]bool)
+	var entries []entry
+	var flowKey string
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		m := outputRegex.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		file := filepath.Base(m[1])
+		if !files[file] {
+			continue
+		}
+		line, _ := strconv.Atoi(m[2])
+		column, _ := strconv.Atoi(m[3])
+		key := fmt.Sprintf("%s:%d:%d", file, line, column)
+		msg := m[4]
+
+		switch {
+		case strings.HasPrefix(msg, " "):
+			// Continuation of the explanation started at this position
+			if key == flowKey {
+				if s, ok := parseStep(strings.TrimSpace(msg)); ok {
+					flows[key] = append(flows[key], s)
+				}
+			}
+			continue
+		case strings.HasSuffix(msg, ":"):
+			flowKey = key
+			continue
+		}
+		flowKey = ""
+
+		e, ok := parseFinding(msg)
+		if !ok {
+			continue
+		}
+		e.File, e.Line, e.Column = file, line, column
+		id := e.Kind + "|" + key + "|" + e.Variable
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if steps := flows[key]; len(steps) > 0 {
+			if e.Kind == "moved-to-heap" || e.Kind == "escapes-to-heap" {
+				s := explain(steps)
+				e.tag = s.tag
+				e.Reason = describe(s.tag) + ": " + s.expr
+			}
+			for _, s := range steps {
+				e.Flow = append(e.Flow, s.String())
+			}
+		}
+		entries = append(entries, e)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return entries
+}
+
+// parseFinding converts the summary line of one decision into a finding
+// with a default reason
+func parseFinding(msg string) (entry, bool) {
+	var e entry
+	switch {
+	case strings.HasPrefix(msg, "moved to heap: "):
+		e.Kind = "moved-to-heap"
+		e.Variable = strings.TrimPrefix(msg, "moved to heap: ")
+		e.Reason = "its address outlives the function"
+	case strings.HasSuffix(msg, " escapes to heap"):
+		e.Kind = "escapes-to-heap"
+		e.Variable = strings.TrimSuffix(msg, " escapes to heap")
+		e.Reason = "the compiler cannot prove it stays on the stack"
+	case leakRegex.MatchString(msg):
+		m := leakRegex.FindStringSubmatch(msg)
+		e.Kind = "leaking-param"
+		e.Variable = m[2]
+		target := "the heap"
+		if m[3] != "" {
+			target = "result " + m[3]
+		}
+		if m[1] != "" {
+			e.Reason = "the values it points to flow to " + target
+		} else {
+			e.Reason = "it flows to " + target
+		}
+	case canInlineRegex.MatchString(msg):
+		m := canInlineRegex.FindStringSubmatch(msg)
+		e.Kind = "can-inline"
+		e.Variable = m[1]
+		e.Reason = "within the inlining budget"
+		if m[2] != "" {
+			e.Reason = "inlining cost " + m[2]
+		}
+	case cannotInlineRegex.MatchString(msg):
+		m := cannotInlineRegex.FindStringSubmatch(msg)
+		e.Kind = "cannot-inline"
+		e.Variable = m[1]
+		e.Reason = m[2]
+	default:
+		return e, false
+	}
+	return e, true
+}
+
+// step is one step of a flow explanation
+type step struct {
+	expr string
+	tag  string
+	pos  string // base file name, line and column
+}
+
+// String returns the step in the compiler's notation
+func (s step) String() string {
+	return fmt.Sprintf("%s (%s) at %s", s.expr, s.tag, s.pos)
+}
+
+// parseStep parses "from &t (address-of) at ./a.go:9:9"
+func parseStep(text string) (step, bool) {
+	m := stepRegex.FindStringSubmatch(text)
+	if m == nil {
+		return step{}, false
+	}
+	return step{expr: m[1], tag: m[2], pos: fmt.Sprintf("%s:%s:%s", filepath.Base(m[3]), m[4], m[5])}, true
+}
+
+// explain returns the step that best explains a flow: the most specific
+// known step kind, or the final step
+func explain(steps []step) step {
+	for _, r := range reasons {
+		for _, s := range steps {
+			if s.tag == r.tag {
+				return s
+			}
+		}
+	}
+	return steps[len(steps)-1]
+}
+
+// describe returns a readable reason for a flow step kind
+func describe(tag string) string {
+	for _, r := range reasons {
+		if r.tag == tag {
+			return r.reason
+		}
+	}
+	return tag
+}
+
+// funcSpan is the line range of a top-level function
+type funcSpan struct {
+	name       string
+	start, end int
+}
+
+// parseFuncs returns the functions declared in file, or nil when it cannot
+// be parsed
+func parseFuncs(file string) []funcSpan {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	var spans []funcSpan
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		name := fn.Name.Name
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			name = receiverName(fn.Recv.List[0].Type) + "." + name
+		}
+		spans = append(spans, funcSpan{name: name, start: fset.Position(fn.Pos()).Line, end: fset.Position(fn.End()).Line})
+	}
+	return spans
+}
+
+// receiverName renders a receiver type as the compiler does, e.g. (*Server)
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return "(*" + receiverName(t.X) + ")"
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// enclosingFunc returns the name of the function containing line
+func enclosingFunc(spans []funcSpan, line int) string {
+	for _, s := range spans {
+		if line >= s.start && line <= s.end {
+			return s.name
+		}
+	}
+	return ""
+}
+
+// relativeFile returns the path of a package file relative to dir
+func relativeFile(dir, pkgDir, name string) string {
+	rel, err := filepath.Rel(dir, filepath.Join(pkgDir, name))
+	if err != nil {
+		return name
+	}
+	return filepath.ToSlash(rel)
+}
+
+// linkReviewIssues reviews the files with findings and attaches the
+// code-review performance issues to the findings in the same function
+func linkReviewIssues(result *EscapeResult, dir, pkgDir string, funcs map[string][]funcSpan) {
+	names := make([]string, 0, len(funcs))
+	for name := range funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		code, err := os.ReadFile(filepath.Join(pkgDir, name))
+		if err != nil {
+			continue
+		}
+		review, err := codereview.NewAnalyzer(nil, "").AnalyzeCode(string(code))
+		if err != nil {
+			continue
+		}
+
+		rel := relativeFile(dir, pkgDir, name)
+		for _, issue := range review.Issues {
+			if issue.Category != "performance" {
+				continue
+			}
+			issue.File = rel
+			result.ReviewIssues = append(result.ReviewIssues, issue)
+
+			fn := enclosingFunc(funcs[name], issue.Line)
+			if fn == "" {
+				continue
+			}
+			related := fmt.Sprintf("%s:%d: %s", rel, issue.Line, issue.Message)
+			heap := 0
+			for i := range result.Findings {
+				f := &result.Findings[i]
+				if f.File == rel && f.Function == fn {
+					f.Related = append(f.Related, related)
+					if f.Kind == "moved-to-heap" || f.Kind == "escapes-to-heap" {
+						heap++
+					}
+				}
+			}
+			if heap > 0 {
+				result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+					"%s has %d heap allocations and code-review reports %q at %s:%d; %s",
+					fn, heap, issue.Message, rel, issue.Line, issue.Suggestion))
+			}
+		}
+	}
+}
+
+// addSuggestions adds one suggestion per kind of heap escape found
+func addSuggestions(result *EscapeResult, tags map[string]int, inlining bool) {
+	added := make(map[string]bool)
+	for _, r := range reasons {
+		if tags[r.tag] == 0 || added[r.suggestion] {
+			continue
+		}
+		added[r.suggestion] = true
+		result.Suggestions = append(result.Suggestions, r.suggestion)
+	}
+	if inlining && result.NotInlinable > 0 {
+		result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+			"%d functions exceed the inlining budget; move rarely taken branches into separate functions to keep hot paths inlinable", result.NotInlinable))
+	}
+	if result.HeapAllocations > 0 {
+		result.Suggestions = append(result.Suggestions, "Measure with go test -bench -benchmem before removing allocations that are not on a hot path")
+	}
+}
+
+// summarize builds a one-line summary of the analysis
+func summarize(result *EscapeResult, inlining bool) string {
+	summary := fmt.Sprintf("%s: %d heap allocations, %d leaking parameters", result.Package, result.HeapAllocations, result.LeakingParams)
+	if inlining {
+		summary += fmt.Sprintf(", %d functions inlinable, %d not inlinable", result.Inlinable, result.NotInlinable)
+	}
+	return summary
+}
@@ -0,0 +1,196 @@
+package escape
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+const testSource = `package store
+
+import "fmt"
+
+type Item struct{ Name string }
+
+func New(name string) *Item {
+	item := Item{Name: name}
+	return &item
+}
+
+func Keep(p *Item) *Item { return p }
+
+func Log(n int) {
+	fmt.Println(n)
+}
+
+func Join(items []*Item) string {
+	out := ""
+	for _, item := range items {
+		out = out + item.Name
+	}
+	return out + fmt.Sprint(len(items))
+}
+`
+
+// writeModule creates a module with the store package above
+func writeModule(t *testing.T) string {
+	t.Helper()
+	files := map[string]string{
+		"go.mod":         "module example.com/app\n\ngo 1.23\n",
+		"store/store.go": testSource,
+	}
+	return testutil.WriteModule(t, files)
+}
+
+func findFinding(result *EscapeResult, kind, variable string) *Finding {
+	for i := range result.Findings {
+		if f := &result.Findings[i]; f.Kind == kind && f.Variable == variable {
+			return f
+		}
+	}
+	return nil
+}
+
+func TestAnalyze(t *testing.T) {
+	dir := writeModule(t)
+
+	result, err := Analyze(context.Background(), EscapeParams{WorkingDir: dir, Package: "./store"}, Options{Roots: []string{dir}})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if result.Package != "example.com/app/store" || result.HeapAllocations == 0 || result.LeakingParams == 0 {
+		t.Errorf("unexpected result header %+v", result)
+	}
+
+	moved := findFinding(result, "moved-to-heap", "item")
+	if moved == nil {
+		t.Fatalf("expected item to be moved to heap, got %+v", result.Findings)
+	}
+	if moved.File != "store/store.go" || moved.Line != 8 || moved.Function != "New" {
+		t.Errorf("unexpected position %+v", moved)
+	}
+	if !strings.HasPrefix(moved.Reason, "returned to the caller") || len(moved.Flow) == 0 {
+		t.Errorf("expected the return flow as reason, got %q %v", moved.Reason, moved.Flow)
+	}
+
+	leak := findFinding(result, "leaking-param", "p")
+	if leak == nil || leak.Reason != "it flows to result ~r0" || leak.Function != "Keep" {
+		t.Errorf("unexpected leaking param %+v", leak)
+	}
+
+	if n := findFinding(result, "escapes-to-heap", "n"); n == nil || n.Function != "Log" {
+		t.Errorf("expected n to escape in Log, got %+v", n)
+	}
+	for _, f := range result.Findings {
+		if f.Kind == "can-inline" || f.Kind == "cannot-inline" {
+			t.Errorf("unexpected inlining finding without inlining: %+v", f)
+		}
+	}
+	if result.Inlinable == 0 {
+		t.Errorf("expected inlinable functions to be counted")
+	}
+}
+
+func TestAnalyze_ReviewIssues(t *testing.T) {
+	dir := writeModule(t)
+
+	result, err := Analyze(context.Background(), EscapeParams{WorkingDir: filepath.Join(dir, "store"), Inlining: true}, Options{Roots: []string{dir}})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if len(result.ReviewIssues) != 1 || result.ReviewIssues[0].File != "store.go" || result.ReviewIssues[0].Line != 21 {
+		t.Fatalf("expected the string concatenation issue, got %+v", result.ReviewIssues)
+	}
+	linked := false
+	for _, f := range result.Findings {
+		if f.Function == "Join" && f.Kind != "can-inline" {
+			linked = linked || (len(f.Related) == 1 && strings.HasPrefix(f.Related[0], "store.go:21: "))
+		}
+		if f.Function != "Join" && len(f.Related) > 0 {
+			t.Errorf("unexpected related issues on %+v", f)
+		}
+	}
+	if !linked {
+		t.Errorf("expected Join findings to link the code-review issue, got %+v", result.Findings)
+	}
+
+	if findFinding(result, "can-inline", "New") == nil {
+		t.Errorf("expected inlining findings, got %+v", result.Findings)
+	}
+	if !strings.Contains(result.Summary, "functions inlinable") {
+		t.Errorf("unexpected summary %q", result.Summary)
+	}
+	found := false
+	for _, s := range result.Suggestions {
+		found = found || strings.HasPrefix(s, "Join has")
+	}
+	if !found {
+		t.Errorf("expected a suggestion for Join, got %v", result.Suggestions)
+	}
+}
+
+func TestAnalyze_Errors(t *testing.T) {
+	dir := writeModule(t)
+	roots := Options{Roots: []string{dir}}
+
+	tests := []struct {
+		name    string
+		params  EscapeParams
+		opts    Options
+		wantErr string
+	}{
+		{"no working dir", EscapeParams{}, roots, "working_dir parameter is required"},
+		{"no roots", EscapeParams{WorkingDir: dir}, Options{}, "requires at least one registered workspace root"},
+		{"outside roots", EscapeParams{WorkingDir: dir}, Options{Roots: []string{t.TempDir()}}, "not inside a registered workspace"},
+		{"pattern", EscapeParams{WorkingDir: dir, Package: "./..."}, roots, "must name a single package"},
+		{"flag", EscapeParams{WorkingDir: dir, Package: "-toolexec=x"}, roots, "must name a single package"},
+		{"missing package", EscapeParams{WorkingDir: dir, Package: "./missing"}, roots, "go list failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Analyze(context.Background(), tt.params, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseOutput(t *testing.T) {
+	output := `# example.com/app/b
+./b.go:14:2: n escapes to heap in Closure:
+./b.go:14:2:   flow: {storage for func literal} ← &n:
+./b.go:14:2:     from n (captured by a closure) at ./b.go:15:33
+./b.go:14:2:     from n (reference) at ./b.go:15:33
+./b.go:14:2: moved to heap: n
+./b.go:15:14: append escapes to heap
+./b.go:24:11: make([]int, n) does not escape
+./b.go:30:27: leaking param content: k
+./b.go:34:6: cannot inline Big: function too complex: cost 179 exceeds budget 80
+/usr/local/go/src/fmt/print.go:10:1: moved to heap: x
+`
+	entries := parseOutput([]byte(output), map[string]bool{"b.go": true})
+
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Kind+" "+e.Variable+": "+e.Reason)
+	}
+	want := []string{
+		"moved-to-heap n: captured by a closure that escapes: n",
+		"escapes-to-heap append: the compiler cannot prove it stays on the stack",
+		"leaking-param k: the values it points to flow to the heap",
+		"cannot-inline Big: function too complex: cost 179 exceeds budget 80",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("parseOutput() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if entries[0].tag != "captured by a closure" || len(entries[0].Flow) != 2 || entries[0].Flow[0] != "n (captured by a closure) at b.go:15:33" {
+		t.Errorf("unexpected flow %+v", entries[0])
+	}
+}
@@ -0,0 +1,47 @@
+package escape
+
+import (
+	"encoding/json"
+
+	"mcp-go-assistant/internal/codereview"
+)
+
+// EscapeParams represents the parameters for the escape-analysis tool
+type EscapeParams struct {
+	WorkingDir     string `json:"working_dir" jsonschema:"description:Module or package directory inside a registered workspace root"`
+	Package        string `json:"package,omitempty" jsonschema:"description:Optional package to analyze relative to working_dir, e.g. './internal/server' (defaults to '.')"`
+	Inlining       bool   `json:"inlining,omitempty" jsonschema:"description:Optional; also report which functions can and cannot be inlined"`
+	IdempotencyKey string `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// EscapeResult represents the compiler's escape analysis of one package
+type EscapeResult struct {
+	Summary         string             `json:"summary"`
+	Package         string             `json:"package"`
+	HeapAllocations int                `json:"heap_allocations"` // Values moved to or escaping to the heap
+	LeakingParams   int                `json:"leaking_params"`
+	Inlinable       int                `json:"inlinable"`
+	NotInlinable    int                `json:"not_inlinable"`
+	Findings        []Finding          `json:"findings"`
+	ReviewIssues    []codereview.Issue `json:"review_issues,omitempty"` // code-review performance issues in the analyzed files
+	Suggestions     []string           `json:"suggestions"`
+}
+
+// Finding is one escape or inlining decision of the compiler
+type Finding struct {
+	Kind     string   `json:"kind"`               // "moved-to-heap", "escapes-to-heap", "leaking-param", "can-inline" or "cannot-inline"
+	Variable string   `json:"variable"`           // Variable, expression or parameter; the function for inlining findings
+	Function string   `json:"function,omitempty"` // Enclosing function, e.g. "(*Server).Handle"
+	File     string   `json:"file"`               // Relative to working_dir
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Reason   string   `json:"reason"`
+	Flow     []string `json:"flow,omitempty"`    // Data flow steps reported by the compiler, innermost first
+	Related  []string `json:"related,omitempty"` // code-review performance issues in the same function
+}
+
+// String returns a formatted JSON string of the EscapeResult
+func (r *EscapeResult) String() string {
+	jsonData, _ := json.MarshalIndent(r, "", "  ")
+	return string(jsonData)
+}
@@ -0,0 +1,241 @@
+// Package gocheck type-checks generated Go code against the source it was
+// generated from, without writing files or building binaries.
+package gocheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"mcp-go-assistant/internal/logging"
+)
+
+// Package is a set of files type-checked as one package
+type Package struct {
+	Path  string            // Import path other packages in the check use
+	Files map[string]string // Source by file name
+}
+
+// Diagnostic is a parse or type error
+type Diagnostic struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+// String formats the diagnostic like the go command does
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Line, d.Column, d.Message)
+}
+
+// Result holds the outcome of a check
+type Result struct {
+	Diagnostics []Diagnostic
+	Unresolved  []string // Imports that could not be resolved; their users were not fully checked
+
+	Fset   *token.FileSet            // Positions of the checked files
+	Types  map[string]*types.Package // Checked packages by path, complete or not
+	Syntax map[string][]*ast.File    // Parsed files of the checked packages by path, with comments
+	Info   map[string]*types.Info    // Type information of the checked packages by path
+}
+
+// Errors returns the diagnostics reported for the named files
+func (r *Result) Errors(files ...string) []Diagnostic {
+	var diags []Diagnostic
+	for _, d := range r.Diagnostics {
+		for _, file := range files {
+			if d.File == file {
+				diags = append(diags, d)
+				break
+			}
+		}
+	}
+	return diags
+}
+
+// Check parses and type-checks pkgs. Packages may import each other by
+// path; other imports are resolved from the export data go list reports,
+// which covers the standard library and the modules of the build list of
+// the working directory. Modules are never downloaded.
+func Check(ctx context.Context, pkgs []Package) *Result {
+	return CheckIn(ctx, "", pkgs)
+}
+
+// CheckIn is like Check, but resolves imports outside pkgs from the build
+// list of the module containing dir
+func CheckIn(ctx context.Context, dir string, pkgs []Package) *Result {
+	fset := token.NewFileSet()
+	c := &checker{
+		fset:     fset,
+		pkgs:     make(map[string]Package),
+		checked:  make(map[string]*types.Package),
+		checking: make(map[string]bool),
+		fallback: importer.ForCompiler(fset, "gc", exportData(ctx, dir)),
+		failed:   make(map[string]bool),
+		result: &Result{
+			Fset:   fset,
+			Syntax: make(map[string][]*ast.File),
+			Info:   make(map[string]*types.Info),
+		},
+	}
+	for _, pkg := range pkgs {
+		c.pkgs[pkg.Path] = pkg
+	}
+	for _, pkg := range pkgs {
+		if _, done := c.checked[pkg.Path]; !done {
+			c.check(pkg)
+		}
+	}
+
+	for path := range c.failed {
+		c.result.Unresolved = append(c.result.Unresolved, path)
+	}
+	sort.Strings(c.result.Unresolved)
+	c.result.Types = c.checked
+	return c.result
+}
+
+// addError records a parser or type checker error
+func (r *Result) addError(err error) {
+	var typeErr types.Error
+	var scanErrs scanner.ErrorList
+	switch {
+	case errors.As(err, &typeErr):
+		msg := typeErr.Msg
+		if strings.HasPrefix(msg, "\t") && len(r.Diagnostics) > 0 {
+			// Continuation of the previous error, e.g. the other declaration
+			// of a redeclared name
+			msg = r.Diagnostics[len(r.Diagnostics)-1].Message + " (" + strings.TrimSpace(msg) + ")"
+		}
+		r.add(typeErr.Fset.Position(typeErr.Pos), msg)
+	case errors.As(err, &scanErrs):
+		for _, e := range scanErrs {
+			r.add(e.Pos, e.Msg)
+		}
+	default:
+		r.add(token.Position{}, err.Error())
+	}
+}
+
+// add records a diagnostic at pos
+func (r *Result) add(pos token.Position, msg string) {
+	r.Diagnostics = append(r.Diagnostics, Diagnostic{
+		File:    pos.Filename,
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Message: msg,
+	})
+}
+
+// checker type-checks a set of packages, checking a package of the set
+// when another one imports it
+type checker struct {
+	fset     *token.FileSet
+	pkgs     map[string]Package
+	checked  map[string]*types.Package
+	checking map[string]bool
+	fallback types.Importer
+	failed   map[string]bool // Imports that could not be resolved
+	result   *Result
+}
+
+// check parses and type-checks pkg
+func (c *checker) check(pkg Package) *types.Package {
+	c.checking[pkg.Path] = true
+	defer delete(c.checking, pkg.Path)
+
+	var files []*ast.File
+	for _, name := range sortedNames(pkg.Files) {
+		file, err := parser.ParseFile(c.fset, name, pkg.Files[name], parser.ParseComments)
+		if err != nil {
+			c.result.addError(err)
+		}
+		if file != nil {
+			files = append(files, file)
+		}
+	}
+
+	conf := types.Config{
+		Importer: c,
+		Error:    c.result.addError,
+	}
+	info := &types.Info{
+		Types:        make(map[ast.Expr]types.TypeAndValue),
+		Defs:         make(map[*ast.Ident]types.Object),
+		Uses:         make(map[*ast.Ident]types.Object),
+		Implicits:    make(map[ast.Node]types.Object),
+		Instances:    make(map[*ast.Ident]types.Instance),
+		Selections:   make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:       make(map[ast.Node]*types.Scope),
+		FileVersions: make(map[*ast.File]string),
+	}
+	checked, _ := conf.Check(pkg.Path, c.fset, files, info)
+	c.checked[pkg.Path] = checked
+	c.result.Syntax[pkg.Path] = files
+	c.result.Info[pkg.Path] = info
+	return checked
+}
+
+// Import implements types.Importer
+func (c *checker) Import(path string) (*types.Package, error) {
+	if pkg, ok := c.checked[path]; ok {
+		return pkg, nil
+	}
+	if pkg, ok := c.pkgs[path]; ok {
+		if c.checking[path] {
+			return nil, fmt.Errorf("import cycle through %s", path)
+		}
+		return c.check(pkg), nil
+	}
+
+	pkg, err := c.fallback.Import(path)
+	if err != nil {
+		c.failed[path] = true
+	}
+	return pkg, err
+}
+
+// exportData returns an importer lookup function that reads the export
+// data of a package compiled by go list in dir
+func exportData(ctx context.Context, dir string) importer.Lookup {
+	return func(path string) (io.ReadCloser, error) {
+		cmd := exec.CommandContext(ctx, "go", "list", "-export", "-f", "{{.Export}}", "--", path)
+		cmd.Dir = dir
+		cmd.Env = logging.CommandEnv(ctx, append(os.Environ(), "GOPROXY=off"))
+		out, err := cmd.Output()
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return nil, fmt.Errorf("go list %s: %s", path, strings.TrimSpace(string(exitErr.Stderr)))
+			}
+			return nil, err
+		}
+		file := strings.TrimSpace(string(out))
+		if file == "" {
+			return nil, fmt.Errorf("no export data for %s", path)
+		}
+		return os.Open(file)
+	}
+}
+
+// sortedNames returns the file names of files in order
+func sortedNames(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,126 @@
+package gocheck
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+func TestCheck(t *testing.T) {
+	source := Package{Path: "example.com/store", Files: map[string]string{
+		"store.go": "package store\n\nimport \"context\"\n\ntype Store struct{}\n\nfunc (s *Store) Get(ctx context.Context, id int) (string, error) { return \"\", nil }\n",
+	}}
+
+	tests := []struct {
+		name       string
+		pkgs       []Package
+		wantErrors []string
+	}{
+		{
+			name: "external test package",
+			pkgs: []Package{
+				{Path: "example.com/store_test", Files: map[string]string{
+					"test_code": "package store_test\n\nimport (\n\t\"context\"\n\n\t\"example.com/store\"\n)\n\nvar _ = (*store.Store).Get\nvar _ context.Context\n",
+				}},
+				source,
+			},
+		},
+		{
+			name: "type error",
+			pkgs: []Package{source, {Path: "example.com/store_test", Files: map[string]string{
+				"test_code": "package store_test\n\nimport \"example.com/store\"\n\nvar s store.Store\nvar n int = s.Get\n",
+			}}},
+			wantErrors: []string{"test_code:6:13: cannot use s.Get"},
+		},
+		{
+			name: "undefined name",
+			pkgs: []Package{{Path: "a", Files: map[string]string{
+				"a.go": "package a\n\nfunc F() { missing() }\n",
+			}}},
+			wantErrors: []string{"a.go:3:12: undefined: missing"},
+		},
+		{
+			name: "syntax error",
+			pkgs: []Package{{Path: "a", Files: map[string]string{
+				"a.go": "package a\n\nfunc F() {\n",
+			}}},
+			wantErrors: []string{"a.go:3:12: expected '}'"},
+		},
+		{
+			name: "redeclared",
+			pkgs: []Package{{Path: "a", Files: map[string]string{
+				"a.go": "package a\n\ntype T struct{}\n",
+				"b.go": "package a\n\ntype T int\n",
+			}}},
+			wantErrors: []string{
+				"b.go:3:6: T redeclared in this block",
+				"a.go:3:6: T redeclared in this block (other declaration of T)",
+			},
+		},
+		{
+			name: "import cycle",
+			pkgs: []Package{
+				{Path: "a", Files: map[string]string{"a.go": "package a\n\nimport _ \"b\"\n"}},
+				{Path: "b", Files: map[string]string{"b.go": "package b\n\nimport _ \"a\"\n"}},
+			},
+			wantErrors: []string{"import cycle through a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Check(context.Background(), tt.pkgs)
+			if len(result.Unresolved) > 0 {
+				t.Fatalf("unexpected unresolved imports %v", result.Unresolved)
+			}
+			if len(result.Diagnostics) != len(tt.wantErrors) {
+				t.Fatalf("expected %d diagnostics, got %v", len(tt.wantErrors), result.Diagnostics)
+			}
+			for i, want := range tt.wantErrors {
+				if got := result.Diagnostics[i].String(); !strings.Contains(got, want) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheck_Unresolved(t *testing.T) {
+	result := Check(context.Background(), []Package{{Path: "a", Files: map[string]string{
+		"a.go": "package a\n\nimport \"example.invalid/missing\"\n\nvar _ = missing.Value\n",
+	}}})
+	if len(result.Unresolved) != 1 || result.Unresolved[0] != "example.invalid/missing" {
+		t.Errorf("expected the missing import to be unresolved, got %v", result.Unresolved)
+	}
+}
+
+func TestResult_Errors(t *testing.T) {
+	result := &Result{Diagnostics: []Diagnostic{
+		{File: "source.go", Line: 1, Message: "in source"},
+		{File: "test_code", Line: 2, Message: "in tests"},
+	}}
+	got := result.Errors("test_code", "mock_code")
+	if len(got) != 1 || got[0].Message != "in tests" {
+		t.Errorf("Errors() = %v, want only the test_code diagnostic", got)
+	}
+}
+
+func TestCheckIn_Types(t *testing.T) {
+	dir := testutil.WriteModule(t, map[string]string{"go.mod": "module example.com/app\n\ngo 1.23\n"})
+
+	result := CheckIn(context.Background(), dir, []Package{{Path: "example.com/app/a", Files: map[string]string{
+		"a/a.go": "package a\n\nimport \"io\"\n\ntype Reader struct{ io.Reader }\n",
+	}}})
+	if len(result.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics %v", result.Diagnostics)
+	}
+	pkg := result.Types["example.com/app/a"]
+	if pkg == nil || pkg.Scope().Lookup("Reader") == nil {
+		t.Fatalf("expected the checked package with Reader, got %v", result.Types)
+	}
+	if pos := result.Fset.Position(pkg.Scope().Lookup("Reader").Pos()); pos.Filename != "a/a.go" || pos.Line != 5 {
+		t.Errorf("unexpected position %v", pos)
+	}
+}
@@ -0,0 +1,316 @@
+package godoc
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/types"
+)
+
+// APISummary is a compact listing of a package's exported API
+type APISummary struct {
+	Package    string       `json:"package"`
+	ImportPath string       `json:"import_path"`
+	Synopsis   string       `json:"synopsis,omitempty"`
+	Constants  []ValueGroup `json:"constants,omitempty"`
+	Variables  []ValueGroup `json:"variables,omitempty"`
+	Functions  []Func       `json:"functions,omitempty"`
+	Types      []Type       `json:"types,omitempty"`
+}
+
+// ValueGroup is a const or var declaration block
+type ValueGroup struct {
+	Names    []string `json:"names"`
+	Type     string   `json:"type,omitempty"` // Declared type of the first value, if any
+	Synopsis string   `json:"synopsis,omitempty"`
+}
+
+// Func is an exported function or method
+type Func struct {
+	Signature string `json:"signature"`
+	Synopsis  string `json:"synopsis,omitempty"`
+}
+
+// Type is an exported type with its associated declarations
+type Type struct {
+	Name         string       `json:"name"`
+	Decl         string       `json:"decl"` // e.g. "type Buffer struct" or "type Duration int64"
+	Synopsis     string       `json:"synopsis,omitempty"`
+	Fields       []string     `json:"fields,omitempty"` // Exported struct fields or interface methods
+	Constants    []ValueGroup `json:"constants,omitempty"`
+	Variables    []ValueGroup `json:"variables,omitempty"`
+	Constructors []Func       `json:"constructors,omitempty"`
+	Methods      []Func       `json:"methods,omitempty"`
+}
+
+// GetAPISummary builds the API summary of the requested package. When a
+// symbol is given, only that function or type is included.
+func GetAPISummary(ctx context.Context, params GoDocParams) (*APISummary, error) {
+	if params.PackagePath == "" {
+		return nil, fmt.Errorf("package_path is required")
+	}
+
+	fset, docPkg, pkg, err := loadPackageDoc(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &APISummary{
+		Package:    docPkg.Name,
+		ImportPath: pkg.ImportPath,
+		Synopsis:   docPkg.Synopsis(docPkg.Doc),
+	}
+	if params.SymbolName == "" {
+		summary.Constants = valueGroups(fset, docPkg, docPkg.Consts)
+		summary.Variables = valueGroups(fset, docPkg, docPkg.Vars)
+	}
+
+	for _, fn := range docPkg.Funcs {
+		if params.SymbolName == "" || params.SymbolName == fn.Name {
+			summary.Functions = append(summary.Functions, function(fset, docPkg, fn))
+		}
+	}
+
+	for _, typ := range docPkg.Types {
+		if params.SymbolName != "" && params.SymbolName != typ.Name {
+			continue
+		}
+		t, err := typeSummary(fset, docPkg, typ)
+		if err != nil {
+			return nil, err
+		}
+		summary.Types = append(summary.Types, t)
+	}
+
+	if params.SymbolName != "" && len(summary.Functions) == 0 && len(summary.Types) == 0 {
+		return nil, fmt.Errorf("symbol %s not found in %s", params.SymbolName, pkg.ImportPath)
+	}
+
+	return summary, nil
+}
+
+// loadPackageDoc parses the source files of the requested package and
+// reads its documentation with go/doc, with unexported declarations when
+// they are requested and allowed
+func loadPackageDoc(ctx context.Context, params GoDocParams) (*token.FileSet, *doc.Package, *listedPackage, error) {
+	pkg, err := listPackage(ctx, params)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range pkg.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, nil, nil, logging.ErrorWithRequestID(ctx, types.NewKindError(types.KindParseFailure, "failed to parse %s: %v", name, err))
+		}
+		files = append(files, file)
+	}
+
+	var mode doc.Mode
+	if params.IncludeUnexported {
+		if err := checkUnexportedAccess(ctx, params, pkg); err != nil {
+			return nil, nil, nil, err
+		}
+		mode = doc.AllDecls
+	}
+
+	docPkg, err := doc.NewFromFiles(fset, files, pkg.ImportPath, mode)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read package documentation: %v", err)
+	}
+	return fset, docPkg, pkg, nil
+}
+
+// String renders the summary as compact Go-like text
+func (s *APISummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "package %s // import %q\n", s.Package, s.ImportPath)
+	if s.Synopsis != "" {
+		fmt.Fprintf(&b, "\n// %s\n", s.Synopsis)
+	}
+
+	writeValues := func(kind string, groups []ValueGroup, indent string) {
+		for _, g := range groups {
+			fmt.Fprintf(&b, "%s%s %s", indent, kind, strings.Join(g.Names, ", "))
+			if g.Type != "" {
+				fmt.Fprintf(&b, " %s", g.Type)
+			}
+			b.WriteString("\n")
+		}
+	}
+	writeFuncs := func(funcs []Func, indent string) {
+		for _, fn := range funcs {
+			fmt.Fprintf(&b, "%s%s\n", indent, fn.Signature)
+		}
+	}
+
+	if len(s.Constants) > 0 || len(s.Variables) > 0 {
+		b.WriteString("\n")
+		writeValues("const", s.Constants, "")
+		writeValues("var", s.Variables, "")
+	}
+	if len(s.Functions) > 0 {
+		b.WriteString("\n")
+		writeFuncs(s.Functions, "")
+	}
+	for _, t := range s.Types {
+		fmt.Fprintf(&b, "\n%s\n", t.Decl)
+		for _, field := range t.Fields {
+			fmt.Fprintf(&b, "    %s\n", field)
+		}
+		writeValues("const", t.Constants, "    ")
+		writeValues("var", t.Variables, "    ")
+		writeFuncs(t.Constructors, "    ")
+		writeFuncs(t.Methods, "    ")
+	}
+
+	return b.String()
+}
+
+// valueGroups summarizes const or var declaration blocks
+func valueGroups(fset *token.FileSet, pkg *doc.Package, values []*doc.Value) []ValueGroup {
+	var groups []ValueGroup
+	for _, v := range values {
+		group := ValueGroup{Synopsis: pkg.Synopsis(v.Doc)}
+		for _, name := range v.Names {
+			if token.IsExported(name) {
+				group.Names = append(group.Names, name)
+			}
+		}
+		if len(group.Names) == 0 {
+			continue
+		}
+		for _, spec := range v.Decl.Specs {
+			if vs, ok := spec.(*ast.ValueSpec); ok && vs.Type != nil {
+				group.Type = render(fset, vs.Type)
+				break
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// function summarizes a function or method by its signature
+func function(fset *token.FileSet, pkg *doc.Package, fn *doc.Func) Func {
+	decl := *fn.Decl
+	decl.Doc = nil
+	decl.Body = nil
+	return Func{Signature: render(fset, &decl), Synopsis: pkg.Synopsis(fn.Doc)}
+}
+
+// typeSummary summarizes a type, its exported fields and its methods
+func typeSummary(fset *token.FileSet, pkg *doc.Package, typ *doc.Type) (Type, error) {
+	var spec *ast.TypeSpec
+	for _, s := range typ.Decl.Specs {
+		if ts, ok := s.(*ast.TypeSpec); ok && ts.Name.Name == typ.Name {
+			spec = ts
+			break
+		}
+	}
+	if spec == nil {
+		return Type{}, fmt.Errorf("declaration of %s not found", typ.Name)
+	}
+
+	t := Type{
+		Name:      typ.Name,
+		Synopsis:  pkg.Synopsis(typ.Doc),
+		Constants: valueGroups(fset, pkg, typ.Consts),
+		Variables: valueGroups(fset, pkg, typ.Vars),
+	}
+
+	assign := ""
+	if spec.Assign.IsValid() {
+		assign = "= "
+	}
+	switch st := spec.Type.(type) {
+	case *ast.StructType:
+		t.Decl = fmt.Sprintf("type %s %sstruct", typ.Name, assign)
+		t.Fields = fieldList(fset, st.Fields, false)
+	case *ast.InterfaceType:
+		t.Decl = fmt.Sprintf("type %s %sinterface", typ.Name, assign)
+		t.Fields = fieldList(fset, st.Methods, true)
+	default:
+		t.Decl = fmt.Sprintf("type %s %s%s", typ.Name, assign, render(fset, spec.Type))
+	}
+	if spec.TypeParams != nil {
+		t.Decl = strings.Replace(t.Decl, typ.Name, typ.Name+"["+renderFields(fset, spec.TypeParams)+"]", 1)
+	}
+
+	for _, fn := range typ.Funcs {
+		t.Constructors = append(t.Constructors, function(fset, pkg, fn))
+	}
+	for _, fn := range typ.Methods {
+		t.Methods = append(t.Methods, function(fset, pkg, fn))
+	}
+	return t, nil
+}
+
+// fieldList renders the exported struct fields or interface methods of
+// list. Embedded types are kept when exported.
+func fieldList(fset *token.FileSet, list *ast.FieldList, methods bool) []string {
+	if list == nil {
+		return nil
+	}
+
+	var fields []string
+	for _, field := range list.List {
+		if len(field.Names) == 0 {
+			embedded := render(fset, field.Type)
+			name := strings.TrimPrefix(embedded[strings.LastIndex(embedded, ".")+1:], "*")
+			if token.IsExported(name) || methods {
+				fields = append(fields, embedded)
+			}
+			continue
+		}
+
+		var names []string
+		for _, name := range field.Names {
+			if name.IsExported() {
+				names = append(names, name.Name)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		if fn, ok := field.Type.(*ast.FuncType); ok && methods {
+			// Interface methods render as Name(params) results
+			fields = append(fields, names[0]+strings.TrimPrefix(render(fset, fn), "func"))
+			continue
+		}
+		fields = append(fields, strings.Join(names, ", ")+" "+render(fset, field.Type))
+	}
+	return fields
+}
+
+// renderFields renders a type parameter list without brackets
+func renderFields(fset *token.FileSet, list *ast.FieldList) string {
+	var parts []string
+	for _, field := range list.List {
+		var names []string
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		parts = append(parts, strings.Join(names, ", ")+" "+render(fset, field.Type))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// render formats node on a single line where possible
+func render(fset *token.FileSet, node any) string {
+	code, err := formatNode(fset, node)
+	if err != nil {
+		return fmt.Sprintf("%T", node)
+	}
+	return code
+}
@@ -0,0 +1,189 @@
+package godoc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+const apiModuleSource = `// Package shapes draws shapes.
+package shapes
+
+// Color values.
+const (
+	Red Color = iota
+	Green
+	blue
+)
+
+// MaxSides is the largest polygon supported.
+const MaxSides = 12
+
+// ErrEmpty is returned for empty shapes.
+var ErrEmpty = errorString("empty")
+
+type errorString string
+
+func (e errorString) Error() string { return string(e) }
+
+// Color is a fill color.
+type Color int
+
+// Shape is anything with an area.
+type Shape interface {
+	Area() float64
+	Named
+}
+
+// Named has a name.
+type Named interface{ Name() string }
+
+// Square is a square shape.
+type Square struct {
+	Side   float64
+	X, Y   int
+	hidden bool
+	Named
+}
+
+// NewSquare creates a square.
+func NewSquare(side float64) *Square { return &Square{Side: side} }
+
+// Area returns the area.
+func (s *Square) Area() float64 { return s.Side * s.Side }
+
+func (s *Square) grow() {}
+
+// Total sums the areas. It skips nil shapes.
+func Total(shapes ...Shape) float64 { return 0 }
+
+func helper() {}
+`
+
+// writeAPIModule creates a module with a shapes package
+func writeAPIModule(t *testing.T) string {
+	t.Helper()
+	files := map[string]string{
+		"go.mod":            "module example.com/shapes\n\ngo 1.23\n",
+		"shapes.go":         apiModuleSource,
+		"shapes_test.go":    "package shapes\n\nfunc TestHidden() {}\n",
+		"ignored_other.go":  "//go:build ignore\n\npackage main\n\nfunc Ignored() {}\n",
+		"testdata/skip.txt": "",
+	}
+	return testutil.WriteModule(t, files)
+}
+
+func TestGetAPISummary(t *testing.T) {
+	dir := writeAPIModule(t)
+
+	summary, err := GetAPISummary(context.Background(), GoDocParams{
+		PackagePath: "example.com/shapes",
+		WorkingDir:  dir,
+	})
+	if err != nil {
+		t.Fatalf("GetAPISummary() error = %v", err)
+	}
+
+	if summary.Package != "shapes" || summary.Synopsis != "Package shapes draws shapes." {
+		t.Errorf("unexpected package header %q / %q", summary.Package, summary.Synopsis)
+	}
+
+	if len(summary.Constants) != 1 || strings.Join(summary.Constants[0].Names, ",") != "MaxSides" {
+		t.Errorf("expected untyped constant group MaxSides, got %+v", summary.Constants)
+	}
+	// The type of ErrEmpty is unexported, so it stays at package level
+	if len(summary.Variables) != 1 || summary.Variables[0].Names[0] != "ErrEmpty" {
+		t.Errorf("expected package variable ErrEmpty, got %+v", summary.Variables)
+	}
+
+	if len(summary.Functions) != 1 || summary.Functions[0].Signature != "func Total(shapes ...Shape) float64" {
+		t.Errorf("unexpected functions %+v", summary.Functions)
+	}
+	if summary.Functions[0].Synopsis != "Total sums the areas." {
+		t.Errorf("unexpected function synopsis %q", summary.Functions[0].Synopsis)
+	}
+
+	types := make(map[string]Type)
+	for _, typ := range summary.Types {
+		types[typ.Name] = typ
+	}
+	if len(types) != 4 {
+		t.Fatalf("expected 4 exported types, got %d", len(types))
+	}
+
+	color := types["Color"]
+	if color.Decl != "type Color int" || len(color.Constants) != 1 || strings.Join(color.Constants[0].Names, ",") != "Red,Green" {
+		t.Errorf("unexpected Color summary %+v", color)
+	}
+
+	square := types["Square"]
+	if square.Decl != "type Square struct" {
+		t.Errorf("unexpected decl %q", square.Decl)
+	}
+	if strings.Join(square.Fields, "; ") != "Side float64; X, Y int; Named" {
+		t.Errorf("expected exported fields only, got %v", square.Fields)
+	}
+	if len(square.Constructors) != 1 || square.Constructors[0].Signature != "func NewSquare(side float64) *Square" {
+		t.Errorf("unexpected constructors %+v", square.Constructors)
+	}
+	if len(square.Methods) != 1 || square.Methods[0].Signature != "func (s *Square) Area() float64" {
+		t.Errorf("expected exported methods only, got %+v", square.Methods)
+	}
+
+	shape := types["Shape"]
+	if shape.Decl != "type Shape interface" || strings.Join(shape.Fields, "; ") != "Area() float64; Named" {
+		t.Errorf("unexpected Shape summary %+v", shape)
+	}
+
+	text := summary.String()
+	for _, want := range []string{
+		`package shapes // import "example.com/shapes"`,
+		"const MaxSides",
+		"type Square struct\n    Side float64",
+		"    func (s *Square) Area() float64",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected text summary to contain %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "helper") || strings.Contains(text, "grow") || strings.Contains(text, "Ignored") {
+		t.Errorf("expected unexported and excluded declarations to be omitted, got:\n%s", text)
+	}
+}
+
+func TestGetAPISummary_Symbol(t *testing.T) {
+	dir := writeAPIModule(t)
+
+	summary, err := GetAPISummary(context.Background(), GoDocParams{
+		PackagePath: "example.com/shapes",
+		SymbolName:  "Square",
+		WorkingDir:  dir,
+	})
+	if err != nil {
+		t.Fatalf("GetAPISummary() error = %v", err)
+	}
+	if len(summary.Types) != 1 || len(summary.Functions) != 0 || len(summary.Constants) != 0 {
+		t.Errorf("expected only the Square type, got %+v", summary)
+	}
+
+	_, err = GetAPISummary(context.Background(), GoDocParams{
+		PackagePath: "example.com/shapes",
+		SymbolName:  "Missing",
+		WorkingDir:  dir,
+	})
+	if err == nil || !strings.Contains(err.Error(), "symbol Missing not found") {
+		t.Errorf("expected symbol not found error, got %v", err)
+	}
+}
+
+func TestGetAPISummary_Stdlib(t *testing.T) {
+	summary, err := GetAPISummary(context.Background(), GoDocParams{PackagePath: "sync/atomic", SymbolName: "Pointer"})
+	if err != nil {
+		t.Fatalf("GetAPISummary() error = %v", err)
+	}
+	if len(summary.Types) != 1 || summary.Types[0].Decl != "type Pointer[T any] struct" {
+		t.Errorf("expected generic type declaration, got %+v", summary.Types)
+	}
+}
@@ -0,0 +1,154 @@
+package godoc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Example is a testable ExampleXxx function from a package's test files
+type Example struct {
+	Name      string `json:"name"`                // Function name, e.g. ExampleBuffer_Write
+	Doc       string `json:"doc,omitempty"`       // Comment on the example function
+	Code      string `json:"code"`                // Runnable program, or the function body when it cannot run standalone
+	Runnable  bool   `json:"runnable"`            // Whether Code is a complete main package
+	Output    string `json:"output,omitempty"`    // Expected output from the // Output: comment
+	Unordered bool   `json:"unordered,omitempty"` // Output lines may appear in any order
+}
+
+// String formats the example for display
+func (e *Example) String() string {
+	var b strings.Builder
+	b.WriteString(e.Name)
+	b.WriteString("\n\n")
+	if e.Doc != "" {
+		b.WriteString(strings.TrimSpace(e.Doc))
+		b.WriteString("\n\n")
+	}
+	b.WriteString(e.Code)
+	if e.Output != "" {
+		if e.Unordered {
+			b.WriteString("\n\nUnordered output:\n")
+		} else {
+			b.WriteString("\n\nOutput:\n")
+		}
+		b.WriteString(strings.TrimRight(e.Output, "\n"))
+	}
+	return b.String()
+}
+
+// GetExamples returns the examples for the requested symbol, or the
+// package-level examples when no symbol is given. Methods may be named as
+// Type.Method or Type_Method.
+func GetExamples(ctx context.Context, params GoDocParams) ([]Example, error) {
+	if params.PackagePath == "" {
+		return nil, fmt.Errorf("package_path is required")
+	}
+
+	pkg, err := listPackage(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(pkg.Dir, "*_test.go"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list test files: %v", err)
+	}
+	sort.Strings(paths)
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, path := range paths {
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			continue // Skip test files that do not parse, e.g. for other build setups
+		}
+		files = append(files, file)
+	}
+
+	symbol := strings.ReplaceAll(params.SymbolName, ".", "_")
+	var examples []Example
+	for _, ex := range doc.Examples(files...) {
+		if !matchesSymbol(ex.Name, symbol) {
+			continue
+		}
+
+		example := Example{
+			Name:      "Example" + ex.Name,
+			Doc:       ex.Doc,
+			Output:    ex.Output,
+			Unordered: ex.Unordered,
+		}
+		if ex.Play != nil {
+			example.Code, err = formatNode(fset, ex.Play)
+			example.Runnable = true
+		} else {
+			example.Code, err = formatBody(fset, ex.Code)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to format %s: %v", example.Name, err)
+		}
+		examples = append(examples, example)
+	}
+
+	return examples, nil
+}
+
+// matchesSymbol reports whether the example name, as returned by
+// doc.Examples, documents symbol. Examples for a symbol may carry a
+// lowercase suffix, e.g. Buffer_reader for Buffer.
+func matchesSymbol(name, symbol string) bool {
+	if name == symbol {
+		return true
+	}
+	if symbol == "" {
+		// Package examples are named Example or Example_suffix
+		suffix, ok := strings.CutPrefix(name, "_")
+		return ok && startsLower(suffix)
+	}
+	suffix, ok := strings.CutPrefix(name, symbol+"_")
+	return ok && startsLower(suffix)
+}
+
+// startsLower reports whether s begins with a lowercase letter
+func startsLower(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsLower(r)
+}
+
+// formatNode renders node as Go source
+func formatNode(fset *token.FileSet, node any) (string, error) {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, node); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// formatBody renders an example function body without its braces
+func formatBody(fset *token.FileSet, node ast.Node) (string, error) {
+	code, err := formatNode(fset, node)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := node.(*ast.BlockStmt); !ok {
+		return code, nil
+	}
+
+	code = strings.TrimSuffix(strings.TrimPrefix(code, "{"), "}")
+	lines := strings.Split(strings.Trim(code, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "\t")
+	}
+	return strings.Join(lines, "\n"), nil
+}
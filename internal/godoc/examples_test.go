@@ -0,0 +1,152 @@
+package godoc
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+const exampleModuleTest = `package demo
+
+import "fmt"
+
+// ExampleGreet shows a greeting.
+func ExampleGreet() {
+	fmt.Println(greeting("gopher"))
+	// Output: hello, gopher
+}
+
+func ExampleGreet_loud() {
+	fmt.Println(greeting("GOPHER"))
+	// Output: hello, GOPHER
+}
+
+func ExampleCounter_Inc() {
+	var c Counter
+	c.Inc()
+	fmt.Println(c)
+	// Output: {1}
+}
+
+func Example() {
+	fmt.Println("demo")
+	// Unordered output: demo
+}
+`
+
+// writeExampleModule creates a module with a demo package and its examples
+func writeExampleModule(t *testing.T) string {
+	t.Helper()
+	files := map[string]string{
+		"go.mod":              "module example.com/demo\n\ngo 1.23\n",
+		"demo.go":             "package demo\n\nfunc greeting(name string) string { return \"hello, \" + name }\n\n// Greet is documented.\nfunc Greet() {}\n\ntype Counter struct{ N int }\n\nfunc (c *Counter) Inc() { c.N++ }\n",
+		"example_test.go":     exampleModuleTest,
+		"broken_test.go":      "package demo\n\nfunc (",
+		"not_a_test_file.txt": "ignored",
+	}
+	return testutil.WriteModule(t, files)
+}
+
+func TestGetExamples(t *testing.T) {
+	dir := writeExampleModule(t)
+
+	tests := []struct {
+		name   string
+		symbol string
+		want   []string
+	}{
+		{name: "package examples", symbol: "", want: []string{"Example"}},
+		{name: "function with suffixed example", symbol: "Greet", want: []string{"ExampleGreet", "ExampleGreet_loud"}},
+		{name: "method in go doc notation", symbol: "Counter.Inc", want: []string{"ExampleCounter_Inc"}},
+		{name: "type excludes method examples", symbol: "Counter", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			examples, err := GetExamples(ctx, GoDocParams{
+				PackagePath: "example.com/demo",
+				SymbolName:  tt.symbol,
+				WorkingDir:  dir,
+			})
+			if err != nil {
+				t.Fatalf("GetExamples() error = %v", err)
+			}
+
+			var got []string
+			for _, ex := range examples {
+				got = append(got, ex.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected examples %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetExamples_CodeAndOutput(t *testing.T) {
+	dir := writeExampleModule(t)
+
+	examples, err := GetExamples(context.Background(), GoDocParams{
+		PackagePath: "example.com/demo",
+		SymbolName:  "Greet",
+		WorkingDir:  dir,
+	})
+	if err != nil || len(examples) == 0 {
+		t.Fatalf("expected examples, got %v (err: %v)", examples, err)
+	}
+
+	ex := examples[0]
+	if ex.Doc != "ExampleGreet shows a greeting.\n" {
+		t.Errorf("unexpected doc %q", ex.Doc)
+	}
+	if ex.Output != "hello, gopher\n" {
+		t.Errorf("unexpected output %q", ex.Output)
+	}
+	// The example uses an unexported function, so only its body is returned
+	if ex.Runnable || ex.Code != `fmt.Println(greeting("gopher"))` {
+		t.Errorf("expected non-runnable body, got runnable=%v code %q", ex.Runnable, ex.Code)
+	}
+
+	text := ex.String()
+	if !strings.Contains(text, "Output:\nhello, gopher") {
+		t.Errorf("expected formatted output section, got %q", text)
+	}
+}
+
+func TestGetExamples_Stdlib(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	examples, err := GetExamples(ctx, GoDocParams{PackagePath: "strings", SymbolName: "ToUpper"})
+	if err != nil {
+		t.Fatalf("GetExamples() error = %v", err)
+	}
+	if len(examples) == 0 {
+		t.Skip("standard library test files not installed")
+	}
+
+	ex := examples[0]
+	if !ex.Runnable || !strings.Contains(ex.Code, "package main") || !strings.Contains(ex.Code, "func main()") {
+		t.Errorf("expected runnable program, got %q", ex.Code)
+	}
+	if ex.Output == "" {
+		t.Error("expected expected output to be extracted")
+	}
+}
+
+func TestGetExamples_Errors(t *testing.T) {
+	if _, err := GetExamples(context.Background(), GoDocParams{}); err == nil {
+		t.Error("expected error for empty package path")
+	}
+
+	_, err := GetExamples(context.Background(), GoDocParams{PackagePath: "invalid/nonexistent/package"})
+	if err == nil || !strings.Contains(err.Error(), "go list failed") {
+		t.Errorf("expected go list error, got %v", err)
+	}
+}
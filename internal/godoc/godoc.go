@@ -2,18 +2,55 @@ package godoc
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/types"
+)
+
+// warmConcurrency bounds the number of go doc processes run by WarmCache
+const warmConcurrency = 4
+
+// stdlibCache holds documentation for standard library lookups, which
+// cannot change while the server is running
+var stdlibCache = struct {
+	sync.RWMutex
+	docs map[string]string
+}{docs: make(map[string]string)}
+
+// Output modes of the go-doc tool
+const (
+	ModeDoc = "doc"
+	ModeAPI = "api"
 )
 
 // GoDocParams represents the parameters for the go-doc tool
 type GoDocParams struct {
-	PackagePath string `json:"package_path" jsonschema:"description:The Go package path to query documentation for"`
-	SymbolName  string `json:"symbol_name,omitempty" jsonschema:"description:Optional symbol name within the package to get specific documentation"`
-	WorkingDir  string `json:"working_dir,omitempty" jsonschema:"description:Optional working directory with go.mod file for external package access"`
+	PackagePath       string `json:"package_path" jsonschema:"description:The Go package path to query documentation for"`
+	SymbolName        string `json:"symbol_name,omitempty" jsonschema:"description:Optional symbol name within the package to get specific documentation"`
+	WorkingDir        string `json:"working_dir,omitempty" jsonschema:"description:Optional working directory with go.mod file for external package access"`
+	IncludeExamples   bool   `json:"include_examples,omitempty" jsonschema:"description:Include runnable examples and their expected output"`
+	Mode              string `json:"mode,omitempty" jsonschema:"description:Output mode: 'doc' (default) for the full documentation or 'api' for a compact API summary"`
+	GoJSON            bool   `json:"go_json,omitempty" jsonschema:"description:Read the documentation from package source with go/doc instead of running go doc, returning structured JSON whose layout does not depend on the Go version"`
+	IncludeUnexported bool   `json:"include_unexported,omitempty" jsonschema:"description:Include unexported symbols like go doc -u; only allowed for packages of the main module of working_dir"`
+	IdempotencyKey    string `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// GoDocResult is the structured go-doc response for API summaries,
+// examples and the symbols related to a looked up symbol
+type GoDocResult struct {
+	Documentation string      `json:"documentation,omitempty"`
+	Doc           *PackageDoc `json:"doc,omitempty"` // Set instead of Documentation for go_json lookups
+	API           *APISummary `json:"api,omitempty"`
+	Examples      []Example   `json:"examples,omitempty"`
+	Related       []Related   `json:"related,omitempty"`
 }
 
 // GetDocumentation executes the go doc command and returns the documentation
@@ -21,9 +58,31 @@ func GetDocumentation(ctx context.Context, params GoDocParams) (string, error) {
 	if params.PackagePath == "" {
 		return "", fmt.Errorf("package_path is required")
 	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	// Standard library documentation is served from the cache when the
+	// lookup does not depend on a working directory
+	cacheable := params.WorkingDir == "" && IsStdlib(params.PackagePath)
+	key := params.PackagePath + "." + params.SymbolName
+	if cacheable {
+		stdlibCache.RLock()
+		doc, ok := stdlibCache.docs[key]
+		stdlibCache.RUnlock()
+		if ok {
+			return doc, nil
+		}
+	}
 
 	// Build the go doc command
 	args := []string{"doc"}
+	if params.IncludeUnexported {
+		if err := checkUnexportedAccess(ctx, params, nil); err != nil {
+			return "", err
+		}
+		args = append(args, "-u")
+	}
 
 	if params.SymbolName != "" {
 		// When symbol is specified, use format: go doc package.symbol
@@ -45,19 +104,215 @@ func GetDocumentation(ctx context.Context, params GoDocParams) (string, error) {
 	if workingDir != "" {
 		cmd.Dir = workingDir
 	}
+	cmd.Env = logging.CommandEnv(ctx, nil)
 
 	output, err := cmd.CombinedOutput()
 
 	if err != nil {
 		// Include both error and output for better debugging
+		return "", packageNotFound(ctx, params, goFailure("go doc", err, strings.TrimSpace(string(output))))
+	}
+
+	doc := strings.TrimSpace(string(output))
+	if cacheable {
+		stdlibCache.Lock()
+		stdlibCache.docs[key] = doc
+		stdlibCache.Unlock()
+	}
+
+	return doc, nil
+}
+
+// listedPackage is the subset of go list output used for source lookups
+type listedPackage struct {
+	Dir        string
+	ImportPath string
+	Name       string
+	GoFiles    []string
+	Module     *struct {
+		Path string
+		Main bool
+	}
+}
+
+// checkUnexportedAccess rejects lookups of unexported symbols outside the
+// main module of the working directory, so the workspace sandbox does not
+// expose the internals of the standard library or of dependencies. pkg is
+// the listed package when the caller has it already. A rejection is a
+// forbidden error, so it is neither retried nor counted as a failure of the
+// tool.
+func checkUnexportedAccess(ctx context.Context, params GoDocParams, pkg *listedPackage) error {
+	if params.WorkingDir == "" {
+		return logging.ErrorWithRequestID(ctx, types.NewValidationError("include_unexported requires working_dir", "field", "working_dir"))
+	}
+	if pkg == nil {
+		var err error
+		if pkg, err = listPackage(ctx, params); err != nil {
+			return err
+		}
+	}
+	if pkg.Module == nil || !pkg.Module.Main {
+		return logging.ErrorWithRequestID(ctx, types.NewForbiddenError(
+			fmt.Sprintf("include_unexported is limited to packages of the module in working_dir, %s is not one", pkg.ImportPath),
+			"package", pkg.ImportPath, "symbol", params.SymbolName,
+		))
+	}
+	return nil
+}
+
+// listPackage locates the source files of the requested package
+func listPackage(ctx context.Context, params GoDocParams) (*listedPackage, error) {
+	cmd := exec.CommandContext(ctx, "go", "list", "-json", params.PackagePath)
+
+	workingDir := params.WorkingDir
+	if workingDir == "" {
+		workingDir = findGoModule()
+	}
+	if workingDir != "" {
+		cmd.Dir = workingDir
+	}
+	cmd.Env = logging.CommandEnv(ctx, nil)
+
+	output, err := cmd.Output()
+	if err != nil {
+		var stderr string
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			stderr = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return nil, packageNotFound(ctx, params, goFailure("go list", err, stderr))
+	}
+
+	var pkg listedPackage
+	if err := json.Unmarshal(output, &pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse go list output: %v", err)
+	}
+	if pkg.Dir == "" {
+		return nil, packageNotFound(ctx, params, types.NewKindError(types.KindPackageNotFound, "no source directory found for %s", params.PackagePath))
+	}
+	return &pkg, nil
+}
+
+// goFailure returns the error of a failed go command, of the kind its
+// error and output show: a missing go binary, a package that does not
+// resolve, or a module that could not be fetched
+func goFailure(command string, err error, output string) error {
+	failure := fmt.Errorf("%s failed: %v", command, err)
+	if output != "" {
+		failure = fmt.Errorf("%s failed: %v\nOutput: %s", command, err, output)
+	}
+
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return &types.KindError{Kind: types.KindToolchainMissing, Err: failure}
+	case containsAny(output, moduleDownloadFailures):
+		return &types.KindError{Kind: types.KindModuleDownloadFailed, Err: failure}
+	case containsAny(output, packageNotFoundFailures):
+		return &types.KindError{Kind: types.KindPackageNotFound, Err: failure}
+	}
+	return failure
+}
+
+// moduleDownloadFailures are the go command messages of modules that could
+// not be fetched. They are checked first, since the go command also reports
+// the package of such a module as missing.
+var moduleDownloadFailures = []string{
+	"reading https://",
+	"module lookup disabled",
+	"dial tcp",
+	"verifying module",
+	"unrecognized import path",
+	"TLS handshake timeout",
+}
+
+// packageNotFoundFailures are the go command messages of package paths
+// that do not resolve
+var packageNotFoundFailures = []string{
+	"no required module provides package",
+	"cannot find package",
+	"is not in std",
+	"is not in GOROOT",
+	"matched no packages",
+	"malformed import path",
+	"no Go files in",
+}
+
+// containsAny reports whether s contains one of the substrings
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStdlib reports whether path looks like a standard library package,
+// i.e. its first element has no dot
+func IsStdlib(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return first != "" && !strings.Contains(first, ".")
+}
+
+// WarmCache loads the documentation for the given standard library
+// packages into the cache. It returns the number of packages cached and
+// the errors for the packages that could not be loaded.
+func WarmCache(ctx context.Context, packages []string) (int, error) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		cached int
+		errs   []error
+	)
+
+	sem := make(chan struct{}, warmConcurrency)
+	for _, pkg := range packages {
+		if !IsStdlib(pkg) {
+			errs = append(errs, fmt.Errorf("%s is not a standard library package", pkg))
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(pkg string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			_, err := GetDocumentation(ctx, GoDocParams{PackagePath: pkg})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", pkg, err))
+				return
+			}
+			cached++
+		}(pkg)
+	}
+	wg.Wait()
+
+	return cached, errors.Join(errs...)
+}
+
+// Toolchain returns the version of the go command used for documentation
+// lookups, verifying that it is installed and runnable
+func Toolchain(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", "env", "GOVERSION")
+	cmd.Env = logging.CommandEnv(ctx, nil)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
 		outputStr := strings.TrimSpace(string(output))
 		if outputStr != "" {
-			return "", fmt.Errorf("go doc failed: %v\nOutput: %s", err, outputStr)
+			return "", logging.ErrorWithRequestID(ctx, types.NewKindError(types.KindToolchainMissing, "go toolchain unavailable: %v\nOutput: %s", err, outputStr))
 		}
-		return "", fmt.Errorf("go doc failed: %v", err)
+		return "", logging.ErrorWithRequestID(ctx, types.NewKindError(types.KindToolchainMissing, "go toolchain unavailable: %v", err))
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	version := strings.TrimSpace(string(output))
+	if version == "" {
+		return "", logging.ErrorWithRequestID(ctx, types.NewKindError(types.KindToolchainMissing, "go toolchain unavailable: empty GOVERSION"))
+	}
+	return version, nil
 }
 
 // findGoModule searches for a go.mod file starting from the current directory
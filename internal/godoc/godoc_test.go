@@ -2,9 +2,15 @@ package godoc
 
 import (
 	"context"
+	"errors"
+	"os/exec"
 	"strings"
 	"testing"
 	"time"
+
+	"mcp-go-assistant/internal/circuitbreaker"
+	"mcp-go-assistant/internal/retry"
+	"mcp-go-assistant/internal/types"
 )
 
 func TestGetDocumentation(t *testing.T) {
@@ -136,6 +142,63 @@ func TestGetDocumentation_WithWorkingDir(t *testing.T) {
 	}
 }
 
+func TestGetDocumentation_IncludeUnexported(t *testing.T) {
+	dir := writeAPIModule(t)
+	ctx := context.Background()
+
+	doc, err := GetDocumentation(ctx, GoDocParams{
+		PackagePath:       "example.com/shapes",
+		SymbolName:        "helper",
+		WorkingDir:        dir,
+		IncludeUnexported: true,
+	})
+	if err != nil {
+		t.Fatalf("GetDocumentation() error = %v", err)
+	}
+	if !strings.Contains(doc, "func helper()") {
+		t.Errorf("expected documentation of the unexported helper, got:\n%s", doc)
+	}
+
+	source, err := GetSourceDocumentation(ctx, GoDocParams{
+		PackagePath:       "example.com/shapes",
+		SymbolName:        "Square.grow",
+		WorkingDir:        dir,
+		IncludeUnexported: true,
+	})
+	if err != nil {
+		t.Fatalf("GetSourceDocumentation() error = %v", err)
+	}
+	if len(source.Types) != 1 || len(source.Types[0].Methods) != 1 {
+		t.Errorf("expected the unexported method grow, got %+v", source.Types)
+	}
+
+	// The standard library and dependencies are outside the sandbox
+	for _, params := range []GoDocParams{
+		{PackagePath: "strings", SymbolName: "indexFunc", WorkingDir: dir, IncludeUnexported: true},
+		{PackagePath: "example.com/shapes", SymbolName: "helper", IncludeUnexported: true},
+	} {
+		if _, err := GetDocumentation(ctx, params); err == nil || !strings.Contains(err.Error(), "include_unexported") {
+			t.Errorf("expected %s.%s to be rejected, got %v", params.PackagePath, params.SymbolName, err)
+		}
+	}
+
+	// A rejection is the caller's to fix, so it is not retried
+	_, err = GetDocumentation(ctx, GoDocParams{PackagePath: "strings", SymbolName: "indexFunc", WorkingDir: dir, IncludeUnexported: true})
+	var mcpErr types.MCPError
+	if !errors.As(err, &mcpErr) || mcpErr.Code() != "FORBIDDEN" || mcpErr.Category() != "auth" {
+		t.Fatalf("expected a forbidden error, got %v", err)
+	}
+	if symbol := mcpErr.Details()["symbol"]; symbol != "indexFunc" {
+		t.Errorf("symbol detail = %v, want indexFunc", symbol)
+	}
+	if retry.RetryableErrors("go-doc")(err) {
+		t.Error("expected the rejection not to be retried")
+	}
+	if circuitbreaker.DefaultIsFailure(err) {
+		t.Error("expected the rejection not to count against the circuit breaker")
+	}
+}
+
 func TestFindGoModule(t *testing.T) {
 	// This function searches for go.mod
 	// In a test environment, it should find the module root
@@ -331,3 +394,87 @@ func TestGoDocParams(t *testing.T) {
 		t.Errorf("expected WorkingDir '/tmp', got '%s'", params.WorkingDir)
 	}
 }
+
+func TestIsStdlib(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"fmt", true},
+		{"net/http", true},
+		{"github.com/spf13/viper", false},
+		{"example.com/x", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsStdlib(tt.path); got != tt.want {
+			t.Errorf("IsStdlib(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestWarmCache(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cached, err := WarmCache(ctx, []string{"errors", "sort", "github.com/spf13/viper"})
+	if cached != 2 {
+		t.Errorf("expected 2 packages cached, got %d (err: %v)", cached, err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "github.com/spf13/viper") {
+		t.Errorf("expected error for non-stdlib package, got %v", err)
+	}
+
+	stdlibCache.RLock()
+	_, ok := stdlibCache.docs["sort."]
+	stdlibCache.RUnlock()
+	if !ok {
+		t.Error("expected sort documentation to be cached")
+	}
+}
+
+func TestToolchain(t *testing.T) {
+	version, err := Toolchain(context.Background())
+	if err != nil {
+		t.Fatalf("Toolchain() error = %v", err)
+	}
+	if !strings.HasPrefix(version, "go") {
+		t.Errorf("unexpected toolchain version %q", version)
+	}
+}
+
+func TestGoFailure(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	tests := []struct {
+		name   string
+		err    error
+		output string
+		want   types.ErrorKind
+	}{
+		{"missing go", exec.ErrNotFound, "", types.KindToolchainMissing},
+		{"missing package", exitErr, "doc: no required module provides package example.com/nope; to add it:", types.KindPackageNotFound},
+		{"std typo", exitErr, "package fmtx is not in std", types.KindPackageNotFound},
+		{"proxy", exitErr, "go: finding module for package example.com/x\nmodule example.com/x: reading https://proxy.golang.org/example.com/x/@v/list: 403 Forbidden", types.KindModuleDownloadFailed},
+		{"symbol", exitErr, "doc: no symbol Nope in package fmt", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := goFailure("go doc", tt.err, tt.output)
+			kind, _ := types.KindOf(err)
+			if kind != tt.want {
+				t.Errorf("kind = %q, want %q (error: %v)", kind, tt.want, err)
+			}
+			if !strings.HasPrefix(err.Error(), "go doc failed: ") {
+				t.Errorf("unexpected message %q", err.Error())
+			}
+		})
+	}
+
+	// A real lookup of a package that does not exist
+	_, err := GetDocumentation(context.Background(), GoDocParams{PackagePath: "example.com/nope/pkg"})
+	if kind, ok := types.KindOf(err); !ok || kind != types.KindPackageNotFound {
+		t.Errorf("expected a package-not-found error, got %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package godoc
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// Kinds of related symbols
+const (
+	RelatedType        = "type"        // Type of a method, constructor or value, or returned by a function
+	RelatedConstructor = "constructor" // Function returning the type
+	RelatedMethod      = "method"      // Method of the type
+	RelatedReturns     = "returns"     // Other function or method with the type among its results
+)
+
+// Related is a symbol related to the one looked up, so clients can
+// navigate the API without listing the whole package
+type Related struct {
+	Name      string `json:"name"` // Name as accepted by symbol_name, e.g. "Buffer.Write"
+	Kind      string `json:"kind"`
+	Signature string `json:"signature"` // Function signature or type declaration
+}
+
+// GetRelated returns the symbols related to the requested one, read with
+// go/doc: the type it belongs to or returns, the constructors and methods
+// of that type and, for a type, the other functions returning it. Unknown
+// symbols and packages without a symbol have none.
+func GetRelated(ctx context.Context, params GoDocParams) ([]Related, error) {
+	if params.PackagePath == "" {
+		return nil, fmt.Errorf("package_path is required")
+	}
+	if params.SymbolName == "" {
+		return nil, nil
+	}
+
+	fset, docPkg, _, err := loadPackageDoc(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	typesByName := make(map[string]*doc.Type)
+	for _, typ := range docPkg.Types {
+		typesByName[typ.Name] = typ
+	}
+
+	symbol := params.SymbolName
+	var homes []*doc.Type
+	if typeName, method, ok := strings.Cut(symbol, "."); ok {
+		if typ := typesByName[typeName]; typ != nil && hasFunc(typ.Methods, method) {
+			homes = append(homes, typ)
+		}
+	} else if typ := typesByName[symbol]; typ != nil {
+		homes = append(homes, typ)
+	} else if fn := findFunc(docPkg.Funcs, symbol); fn != nil {
+		// Plain functions relate to the package types they return
+		for _, name := range resultTypes(fn.Decl) {
+			if typ := typesByName[name]; typ != nil {
+				homes = append(homes, typ)
+			}
+		}
+	} else {
+		for _, typ := range docPkg.Types {
+			if hasFunc(typ.Funcs, symbol) || hasValue(typ.Consts, symbol) || hasValue(typ.Vars, symbol) {
+				homes = append(homes, typ)
+				break
+			}
+		}
+	}
+
+	var related []Related
+	seen := map[string]bool{symbol: true}
+	add := func(r Related) {
+		if !seen[r.Name] {
+			seen[r.Name] = true
+			related = append(related, r)
+		}
+	}
+	addFunc := func(kind, name string, fn *doc.Func) {
+		add(Related{Name: name, Kind: kind, Signature: function(fset, docPkg, fn).Signature})
+	}
+
+	for _, typ := range homes {
+		decl := "type " + typ.Name
+		if t, err := typeSummary(fset, docPkg, typ); err == nil {
+			decl = t.Decl
+		}
+		add(Related{Name: typ.Name, Kind: RelatedType, Signature: decl})
+		for _, fn := range typ.Funcs {
+			addFunc(RelatedConstructor, fn.Name, fn)
+		}
+		for _, fn := range typ.Methods {
+			addFunc(RelatedMethod, typ.Name+"."+fn.Name, fn)
+		}
+	}
+
+	// Functions returning a type beyond its constructors, e.g. Time.Sub
+	// for Duration
+	if typ := typesByName[symbol]; typ != nil {
+		returns := func(name string, fn *doc.Func) {
+			for _, result := range resultTypes(fn.Decl) {
+				if result == typ.Name {
+					addFunc(RelatedReturns, name, fn)
+					return
+				}
+			}
+		}
+		for _, fn := range docPkg.Funcs {
+			returns(fn.Name, fn)
+		}
+		for _, other := range docPkg.Types {
+			if other == typ {
+				continue
+			}
+			for _, fn := range other.Funcs {
+				returns(fn.Name, fn)
+			}
+			for _, fn := range other.Methods {
+				returns(other.Name+"."+fn.Name, fn)
+			}
+		}
+	}
+
+	return related, nil
+}
+
+// SeeAlso renders related symbols as a section to append to the
+// documentation, one signature per line with its kind as a comment
+func SeeAlso(related []Related) string {
+	var b strings.Builder
+	b.WriteString("Related:\n")
+	for _, r := range related {
+		fmt.Fprintf(&b, "    %s // %s\n", r.Signature, r.Kind)
+	}
+	return b.String()
+}
+
+// findFunc returns the function named name in funcs, or nil
+func findFunc(funcs []*doc.Func, name string) *doc.Func {
+	for _, fn := range funcs {
+		if fn.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+// hasFunc reports whether funcs include one named name
+func hasFunc(funcs []*doc.Func, name string) bool {
+	return findFunc(funcs, name) != nil
+}
+
+// hasValue reports whether a const or var group of values declares name
+func hasValue(values []*doc.Value, name string) bool {
+	for _, v := range values {
+		if slices.Contains(v.Names, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// resultTypes returns the names of the package-local types mentioned in
+// the results of decl, e.g. Buffer for []*Buffer, in order
+func resultTypes(decl *ast.FuncDecl) []string {
+	if decl.Type.Results == nil {
+		return nil
+	}
+	var names []string
+	ast.Inspect(decl.Type.Results, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			// Types of other packages, e.g. io.Reader
+			return false
+		case *ast.Ident:
+			if token.IsExported(n.Name) && !slices.Contains(names, n.Name) {
+				names = append(names, n.Name)
+			}
+		}
+		return true
+	})
+	return names
+}
@@ -0,0 +1,82 @@
+package godoc
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// relatedNames formats related symbols as kind:name for comparison
+func relatedNames(related []Related) string {
+	var names []string
+	for _, r := range related {
+		names = append(names, r.Kind+":"+r.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+func TestGetRelated(t *testing.T) {
+	dir := writeAPIModule(t)
+
+	tests := []struct {
+		symbol string
+		want   string
+	}{
+		{"Square", "constructor:NewSquare method:Square.Area"},
+		{"Square.Area", "type:Square constructor:NewSquare"},
+		{"NewSquare", "type:Square method:Square.Area"},
+		{"Red", "type:Color"},
+		{"Total", ""},
+		{"Missing", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			related, err := GetRelated(context.Background(), GoDocParams{
+				PackagePath: "example.com/shapes",
+				SymbolName:  tt.symbol,
+				WorkingDir:  dir,
+			})
+			if err != nil {
+				t.Fatalf("GetRelated() error = %v", err)
+			}
+			if got := relatedNames(related); got != tt.want {
+				t.Errorf("GetRelated(%s) = %q, want %q", tt.symbol, got, tt.want)
+			}
+		})
+	}
+
+	related, err := GetRelated(context.Background(), GoDocParams{
+		PackagePath: "example.com/shapes",
+		SymbolName:  "Square.Area",
+		WorkingDir:  dir,
+	})
+	if err != nil {
+		t.Fatalf("GetRelated() error = %v", err)
+	}
+	text := SeeAlso(related)
+	for _, want := range []string{
+		"Related:\n",
+		"    type Square struct // type\n",
+		"    func NewSquare(side float64) *Square // constructor\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected see-also section to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestGetRelated_Stdlib(t *testing.T) {
+	related, err := GetRelated(context.Background(), GoDocParams{PackagePath: "time", SymbolName: "Duration"})
+	if err != nil {
+		t.Fatalf("GetRelated() error = %v", err)
+	}
+	got := relatedNames(related)
+	for _, want := range []string{"constructor:Since", "method:Duration.Seconds", "returns:Time.Sub"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %s among related symbols, got %s", want, got)
+		}
+	}
+	if strings.Contains(got, ":Duration ") {
+		t.Errorf("expected the symbol itself to be excluded, got %s", got)
+	}
+}
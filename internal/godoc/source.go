@@ -0,0 +1,193 @@
+package godoc
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/token"
+	"strings"
+)
+
+// PackageDoc is the documentation of a package read from its source with
+// go/doc. Unlike go doc output, its layout does not change between Go
+// releases.
+type PackageDoc struct {
+	Package    string    `json:"package"`
+	ImportPath string    `json:"import_path"`
+	Doc        string    `json:"doc,omitempty"`
+	Constants  []DeclDoc `json:"constants,omitempty"`
+	Variables  []DeclDoc `json:"variables,omitempty"`
+	Functions  []DeclDoc `json:"functions,omitempty"`
+	Types      []TypeDoc `json:"types,omitempty"`
+}
+
+// DeclDoc is a documented declaration
+type DeclDoc struct {
+	Names []string `json:"names"`
+	Decl  string   `json:"decl"` // Declaration source without function bodies
+	Doc   string   `json:"doc,omitempty"`
+}
+
+// TypeDoc is a documented type with its associated declarations
+type TypeDoc struct {
+	DeclDoc
+	Constants []DeclDoc `json:"constants,omitempty"`
+	Variables []DeclDoc `json:"variables,omitempty"`
+	Functions []DeclDoc `json:"functions,omitempty"` // Constructors returning the type
+	Methods   []DeclDoc `json:"methods,omitempty"`
+}
+
+// GetSourceDocumentation reads the documentation of the requested package
+// from its source instead of running go doc. A symbol selects a function,
+// a type, the const or var group declaring it, or a method as Type.Method.
+func GetSourceDocumentation(ctx context.Context, params GoDocParams) (*PackageDoc, error) {
+	if params.PackagePath == "" {
+		return nil, fmt.Errorf("package_path is required")
+	}
+
+	fset, docPkg, pkg, err := loadPackageDoc(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	typeName, method, _ := strings.Cut(params.SymbolName, ".")
+	match := func(names ...string) bool {
+		if params.SymbolName == "" {
+			return true
+		}
+		for _, name := range names {
+			if name == params.SymbolName {
+				return true
+			}
+		}
+		return false
+	}
+
+	result := &PackageDoc{
+		Package:    docPkg.Name,
+		ImportPath: pkg.ImportPath,
+	}
+	if params.SymbolName == "" {
+		result.Doc = strings.TrimSpace(docPkg.Doc)
+	}
+	result.Constants = valueDocs(fset, docPkg.Consts, match)
+	result.Variables = valueDocs(fset, docPkg.Vars, match)
+	for _, fn := range docPkg.Funcs {
+		if match(fn.Name) {
+			result.Functions = append(result.Functions, funcDoc(fset, fn))
+		}
+	}
+
+	for _, typ := range docPkg.Types {
+		t := TypeDoc{DeclDoc: genDeclDoc(fset, typ.Decl, []string{typ.Name}, typ.Doc)}
+		switch {
+		case method != "":
+			// Type.Method selects a single method
+			if typ.Name != typeName {
+				continue
+			}
+			for _, fn := range typ.Methods {
+				if fn.Name == method {
+					t.Methods = append(t.Methods, funcDoc(fset, fn))
+				}
+			}
+			if len(t.Methods) == 0 {
+				continue
+			}
+		case match(typ.Name):
+			t.Constants = valueDocs(fset, typ.Consts, matchAll)
+			t.Variables = valueDocs(fset, typ.Vars, matchAll)
+			for _, fn := range typ.Funcs {
+				t.Functions = append(t.Functions, funcDoc(fset, fn))
+			}
+			for _, fn := range typ.Methods {
+				t.Methods = append(t.Methods, funcDoc(fset, fn))
+			}
+		default:
+			// Constants, variables and constructors are listed under their
+			// type but may be looked up by their own name
+			for _, fn := range typ.Funcs {
+				if match(fn.Name) {
+					result.Functions = append(result.Functions, funcDoc(fset, fn))
+				}
+			}
+			result.Constants = append(result.Constants, valueDocs(fset, typ.Consts, match)...)
+			result.Variables = append(result.Variables, valueDocs(fset, typ.Vars, match)...)
+			continue
+		}
+		result.Types = append(result.Types, t)
+	}
+
+	if params.SymbolName != "" && result.empty() {
+		return nil, fmt.Errorf("symbol %s not found in %s", params.SymbolName, pkg.ImportPath)
+	}
+	return result, nil
+}
+
+// empty reports whether d documents no declarations
+func (d *PackageDoc) empty() bool {
+	return len(d.Constants) == 0 && len(d.Variables) == 0 && len(d.Functions) == 0 && len(d.Types) == 0
+}
+
+// String renders the documentation as text in the layout of go doc
+func (d *PackageDoc) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "package %s // import %q\n", d.Package, d.ImportPath)
+	if d.Doc != "" {
+		fmt.Fprintf(&b, "\n%s\n", d.Doc)
+	}
+
+	writeDecls := func(decls []DeclDoc) {
+		for _, decl := range decls {
+			fmt.Fprintf(&b, "\n%s\n", decl.Decl)
+			if decl.Doc != "" {
+				for _, line := range strings.Split(strings.TrimSpace(decl.Doc), "\n") {
+					b.WriteString(strings.TrimRight("    "+line, " ") + "\n")
+				}
+			}
+		}
+	}
+
+	writeDecls(d.Constants)
+	writeDecls(d.Variables)
+	writeDecls(d.Functions)
+	for _, t := range d.Types {
+		writeDecls([]DeclDoc{t.DeclDoc})
+		writeDecls(t.Constants)
+		writeDecls(t.Variables)
+		writeDecls(t.Functions)
+		writeDecls(t.Methods)
+	}
+
+	return b.String()
+}
+
+// matchAll matches any names
+func matchAll(...string) bool { return true }
+
+// valueDocs documents the const or var groups declaring a matching name
+func valueDocs(fset *token.FileSet, values []*doc.Value, match func(...string) bool) []DeclDoc {
+	var docs []DeclDoc
+	for _, v := range values {
+		if match(v.Names...) {
+			docs = append(docs, genDeclDoc(fset, v.Decl, v.Names, v.Doc))
+		}
+	}
+	return docs
+}
+
+// genDeclDoc documents a const, var or type declaration
+func genDeclDoc(fset *token.FileSet, decl *ast.GenDecl, names []string, text string) DeclDoc {
+	d := *decl
+	d.Doc = nil
+	return DeclDoc{Names: names, Decl: render(fset, &d), Doc: strings.TrimSpace(text)}
+}
+
+// funcDoc documents a function or method
+func funcDoc(fset *token.FileSet, fn *doc.Func) DeclDoc {
+	decl := *fn.Decl
+	decl.Doc = nil
+	decl.Body = nil
+	return DeclDoc{Names: []string{fn.Name}, Decl: render(fset, &decl), Doc: strings.TrimSpace(fn.Doc)}
+}
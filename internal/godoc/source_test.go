@@ -0,0 +1,117 @@
+package godoc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGetSourceDocumentation(t *testing.T) {
+	dir := writeAPIModule(t)
+
+	doc, err := GetSourceDocumentation(context.Background(), GoDocParams{
+		PackagePath: "example.com/shapes",
+		WorkingDir:  dir,
+	})
+	if err != nil {
+		t.Fatalf("GetSourceDocumentation() error = %v", err)
+	}
+
+	if doc.Package != "shapes" || doc.ImportPath != "example.com/shapes" || doc.Doc != "Package shapes draws shapes." {
+		t.Errorf("unexpected package header %+v", doc)
+	}
+	if len(doc.Constants) != 1 || doc.Constants[0].Decl != "const MaxSides = 12" || doc.Constants[0].Doc != "MaxSides is the largest polygon supported." {
+		t.Errorf("unexpected constants %+v", doc.Constants)
+	}
+	if len(doc.Functions) != 1 || doc.Functions[0].Decl != "func Total(shapes ...Shape) float64" {
+		t.Errorf("unexpected functions %+v", doc.Functions)
+	}
+
+	var square *TypeDoc
+	for i := range doc.Types {
+		if doc.Types[i].Names[0] == "Square" {
+			square = &doc.Types[i]
+		}
+	}
+	if square == nil {
+		t.Fatalf("expected type Square, got %+v", doc.Types)
+	}
+	if !strings.Contains(square.Decl, "Side float64") || strings.Contains(square.Decl, "hidden") {
+		t.Errorf("expected exported fields only, got:\n%s", square.Decl)
+	}
+	if len(square.Functions) != 1 || square.Functions[0].Names[0] != "NewSquare" {
+		t.Errorf("expected constructor NewSquare, got %+v", square.Functions)
+	}
+	if len(square.Methods) != 1 || square.Methods[0].Decl != "func (s *Square) Area() float64" {
+		t.Errorf("expected exported methods only, got %+v", square.Methods)
+	}
+
+	text := doc.String()
+	for _, want := range []string{
+		`package shapes // import "example.com/shapes"`,
+		"\nconst MaxSides = 12\n    MaxSides is the largest polygon supported.\n",
+		"\nfunc (s *Square) Area() float64\n    Area returns the area.\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected text to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"names":["Square"],"decl":"type Square struct`) {
+		t.Errorf("expected embedded declaration fields in JSON, got %s", data)
+	}
+}
+
+func TestGetSourceDocumentation_Symbol(t *testing.T) {
+	dir := writeAPIModule(t)
+
+	tests := []struct {
+		symbol    string
+		wantDecls []string
+		wantErr   string
+	}{
+		{symbol: "Total", wantDecls: []string{"func Total(shapes ...Shape) float64"}},
+		{symbol: "Square.Area", wantDecls: []string{"type Square struct", "func (s *Square) Area() float64"}},
+		{symbol: "NewSquare", wantDecls: []string{"func NewSquare(side float64) *Square"}},
+		{symbol: "Green", wantDecls: []string{"const (\n\tRed Color = iota\n\tGreen\n"}},
+		{symbol: "Square.grow", wantErr: "symbol Square.grow not found"},
+		{symbol: "Missing", wantErr: "symbol Missing not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			doc, err := GetSourceDocumentation(context.Background(), GoDocParams{
+				PackagePath: "example.com/shapes",
+				SymbolName:  tt.symbol,
+				WorkingDir:  dir,
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetSourceDocumentation() error = %v", err)
+			}
+
+			text := doc.String()
+			if doc.Doc != "" {
+				t.Errorf("expected no package doc for a symbol lookup, got %q", doc.Doc)
+			}
+			for _, want := range tt.wantDecls {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in:\n%s", want, text)
+				}
+			}
+			if strings.Contains(text, "func (s *Square) Area") != strings.Contains(tt.symbol, "Area") {
+				t.Errorf("unexpected method selection for %s:\n%s", tt.symbol, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package godoc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"mcp-go-assistant/internal/editdistance"
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/types"
+)
+
+// maxSuggestions bounds the closest matches offered for a missing package
+const maxSuggestions = 5
+
+// stdPackages holds the importable standard library packages once listed
+var stdPackages struct {
+	sync.Mutex
+	paths []string
+}
+
+// packageNotFound turns a lookup failure for a package that does not exist
+// into a NOT_FOUND error naming the attempted path and the closest known
+// packages. Other errors are returned as they are, with the request ID of
+// ctx recorded like on the NOT_FOUND error.
+func packageNotFound(ctx context.Context, params GoDocParams, err error) error {
+	if kind, ok := types.KindOf(err); !ok || kind != types.KindPackageNotFound {
+		return logging.ErrorWithRequestID(ctx, err)
+	}
+
+	suggestions := closestPackages(params.PackagePath, knownPackages(ctx, params))
+	message := fmt.Sprintf("package %s not found", params.PackagePath)
+	if len(suggestions) > 0 {
+		message += fmt.Sprintf("; did you mean %s?", strings.Join(suggestions, ", "))
+	}
+	return logging.ErrorWithRequestID(ctx, types.WrapNotFoundError(err, message,
+		"package_path", params.PackagePath,
+		"suggestions", suggestions,
+	))
+}
+
+// knownPackages returns the standard library packages and the packages the
+// module of the lookup builds or imports
+func knownPackages(ctx context.Context, params GoDocParams) []string {
+	stdPackages.Lock()
+	if stdPackages.paths == nil {
+		stdPackages.paths = goList(ctx, "", "std")
+	}
+	known := append([]string{}, stdPackages.paths...)
+	stdPackages.Unlock()
+
+	workingDir := params.WorkingDir
+	if workingDir == "" {
+		workingDir = findGoModule()
+	}
+	if workingDir != "" {
+		known = append(known, goList(ctx, workingDir, "-deps", "./...")...)
+	}
+	return known
+}
+
+// goList returns the import paths of the packages go list prints for args,
+// leaving out internal and vendored packages, which cannot be imported.
+// Failures leave the list empty; suggestions are best effort.
+func goList(ctx context.Context, dir string, args ...string) []string {
+	cmd := exec.CommandContext(ctx, "go", append([]string{"list", "-e"}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = logging.CommandEnv(ctx, append(os.Environ(), "GOPROXY=off"))
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "vendor/") || line == "internal" ||
+			strings.HasPrefix(line, "internal/") || strings.Contains(line, "/internal/") || strings.HasSuffix(line, "/internal") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	return paths
+}
+
+// closestPackages returns up to maxSuggestions of the known packages
+// closest to target: those a few edits away and those sharing its last
+// element, nearest first
+func closestPackages(target string, known []string) []string {
+	type candidate struct {
+		path string
+		dist int
+	}
+
+	base := path.Base(target)
+	maxDist := max(2, len(target)/4)
+	seen := make(map[string]bool)
+	var candidates []candidate
+	for _, p := range known {
+		if p == target || seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		dist := editdistance.Levenshtein(target, p)
+		if dist > maxDist {
+			if path.Base(p) != base && editdistance.Levenshtein(base, path.Base(p)) > 1 {
+				continue
+			}
+			// Same or nearly the same package name under another path
+			dist = maxDist + editdistance.Levenshtein(base, path.Base(p))
+		}
+		candidates = append(candidates, candidate{p, dist})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].dist != candidates[j].dist {
+			return candidates[i].dist < candidates[j].dist
+		}
+		return candidates[i].path < candidates[j].path
+	})
+	suggestions := []string{}
+	for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].path)
+	}
+	return suggestions
+}
@@ -0,0 +1,49 @@
+package godoc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"mcp-go-assistant/internal/types"
+)
+
+func TestClosestPackages(t *testing.T) {
+	known := []string{"encoding/json", "encoding/xml", "encoding/gob", "net/http", "github.com/gorilla/mux", "example.com/app/json"}
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"encoding/jsn", []string{"encoding/json", "encoding/gob", "encoding/xml", "example.com/app/json"}},
+		{"github.com/gorila/mux", []string{"github.com/gorilla/mux"}},
+		{"net/htp", []string{"net/http"}},
+		{"completely/unrelated", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := closestPackages(tt.target, known); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("closestPackages(%q) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestGetDocumentation_PackageNotFound(t *testing.T) {
+	_, err := GetDocumentation(context.Background(), GoDocParams{PackagePath: "encodng/json"})
+
+	var mcpErr types.MCPError
+	if !errors.As(err, &mcpErr) || mcpErr.Code() != "NOT_FOUND" {
+		t.Fatalf("expected a NOT_FOUND error, got %v", err)
+	}
+	details := mcpErr.Details()
+	if details["package_path"] != "encodng/json" {
+		t.Errorf("unexpected package_path detail %v", details["package_path"])
+	}
+	suggestions, _ := details["suggestions"].([]string)
+	if len(suggestions) == 0 || suggestions[0] != "encoding/json" {
+		t.Errorf("expected encoding/json as the closest match, got %v", details["suggestions"])
+	}
+	if kind, _ := types.KindOf(err); kind != types.KindPackageNotFound {
+		t.Errorf("expected the package-not-found kind to be kept, got %q", kind)
+	}
+}
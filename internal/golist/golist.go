@@ -0,0 +1,86 @@
+// Package golist lists the packages of a workspace with `go list -json`,
+// for the tools that load whole packages from disk
+package golist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"mcp-go-assistant/internal/logging"
+)
+
+// Package mirrors the fields of `go list -json` used by the tools
+type Package struct {
+	ImportPath string   `json:"ImportPath"`
+	Name       string   `json:"Name"`
+	Dir        string   `json:"Dir"`
+	GoFiles    []string `json:"GoFiles"`
+}
+
+// Options configures List
+type Options struct {
+	// AllowErrors lists packages even when their imports are missing or
+	// do not build, as go list -e does
+	AllowErrors bool
+}
+
+// List lists the packages matching pattern in dir. Modules are never
+// downloaded; dependencies must be in the module cache.
+func List(ctx context.Context, dir, pattern string, opts Options) ([]Package, error) {
+	args := []string{"list", "-json"}
+	if opts.AllowErrors {
+		args = append(args, "-e")
+	}
+	cmd := exec.CommandContext(ctx, "go", append(args, pattern)...)
+	cmd.Dir = dir
+	cmd.Env = logging.CommandEnv(ctx, append(os.Environ(), "GOPROXY=off"))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("go list failed: %v\nOutput: %s", err, msg)
+		}
+		return nil, fmt.Errorf("go list failed: %v", err)
+	}
+
+	var pkgs []Package
+	dec := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var pkg Package
+		if err := dec.Decode(&pkg); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode go list output: %v", err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// ReadFiles reads the Go files of p, keyed by their slash-separated path
+// relative to dir
+func (p Package) ReadFiles(dir string) (map[string]string, error) {
+	files := make(map[string]string, len(p.GoFiles))
+	for _, name := range p.GoFiles {
+		path := filepath.Join(p.Dir, name)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", path, err)
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		files[filepath.ToSlash(rel)] = string(content)
+	}
+	return files, nil
+}
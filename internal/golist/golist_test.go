@@ -0,0 +1,45 @@
+package golist
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+func TestList(t *testing.T) {
+	dir := testutil.WriteModule(t, map[string]string{
+		"go.mod":         "module example.com/app\n\ngo 1.23\n",
+		"main.go":        "package main\n\nimport \"example.com/app/store\"\n\nfunc main() { store.Open() }\n",
+		"store/store.go": "package store\n\n// Open opens the store\nfunc Open() {}\n",
+		"broken/dep.go":  "package broken\n\nimport \"example.com/missing\"\n\nvar _ = missing.X\n",
+	})
+	ctx := context.Background()
+
+	if _, err := List(ctx, dir, "./...", Options{}); err == nil || !strings.Contains(err.Error(), "go list failed") {
+		t.Errorf("expected the missing import to fail the listing, got %v", err)
+	}
+
+	pkgs, err := List(ctx, dir, "./...", Options{AllowErrors: true})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var store *Package
+	for i := range pkgs {
+		if pkgs[i].ImportPath == "example.com/app/store" {
+			store = &pkgs[i]
+		}
+	}
+	if len(pkgs) != 3 || store == nil || store.Name != "store" {
+		t.Fatalf("expected the three packages with store among them, got %+v", pkgs)
+	}
+
+	files, err := store.ReadFiles(dir)
+	if err != nil {
+		t.Fatalf("ReadFiles() error = %v", err)
+	}
+	if content, ok := files["store/store.go"]; !ok || !strings.Contains(content, "func Open()") {
+		t.Errorf("expected store/store.go relative to the module, got %v", files)
+	}
+}
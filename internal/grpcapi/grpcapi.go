@@ -0,0 +1,227 @@
+// Package grpcapi serves the go-doc, code-review and test-gen tools over
+// gRPC for integrations that do not speak MCP, such as CI jobs and IDE
+// plugins. Calls go through the same middleware stack as the MCP tools.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"mcp-go-assistant/internal/codereview"
+	"mcp-go-assistant/internal/godoc"
+	"mcp-go-assistant/internal/ratelimit"
+	"mcp-go-assistant/internal/testgen"
+	"mcp-go-assistant/internal/types"
+	"mcp-go-assistant/pkg/assistantv1"
+)
+
+// Handlers are the tool handlers behind the service, wrapped in the
+// middleware stack of the server
+type Handlers struct {
+	GoDoc      mcp.ToolHandlerFor[godoc.GoDocParams, any]
+	CodeReview mcp.ToolHandlerFor[codereview.CodeReviewParams, *codereview.ReviewResult]
+	TestGen    mcp.ToolHandlerFor[testgen.TestGenParams, *testgen.TestGenResult]
+}
+
+// Service implements the Assistant gRPC service with the tool handlers
+type Service struct {
+	assistantv1.UnimplementedAssistantServer
+	handlers Handlers
+}
+
+// NewServer returns a gRPC server with the Assistant service registered
+func NewServer(handlers Handlers, opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	assistantv1.RegisterAssistantServer(server, &Service{handlers: handlers})
+	return server
+}
+
+// GoDoc runs the go-doc tool
+func (s *Service) GoDoc(ctx context.Context, req *assistantv1.GoDocRequest) (*assistantv1.GoDocResponse, error) {
+	result, _, err := s.handlers.GoDoc(withClientID(ctx), nil, godoc.GoDocParams{
+		PackagePath:       req.GetPackagePath(),
+		SymbolName:        req.GetSymbolName(),
+		WorkingDir:        req.GetWorkingDir(),
+		IncludeExamples:   req.GetIncludeExamples(),
+		Mode:              req.GetMode(),
+		GoJSON:            req.GetGoJson(),
+		IncludeUnexported: req.GetIncludeUnexported(),
+		IdempotencyKey:    req.GetIdempotencyKey(),
+	})
+	text, err := resultText(result, err)
+	if err != nil {
+		return nil, err
+	}
+	return &assistantv1.GoDocResponse{Text: text}, nil
+}
+
+// CodeReview runs the code-review tool
+func (s *Service) CodeReview(ctx context.Context, req *assistantv1.CodeReviewRequest) (*assistantv1.CodeReviewResponse, error) {
+	thresholds := req.GetThresholds()
+	result, review, err := s.handlers.CodeReview(withClientID(ctx), nil, codereview.CodeReviewParams{
+		GoCode:            req.GetGoCode(),
+		GuidelinesFile:    req.GetGuidelinesFile(),
+		GuidelinesContent: req.GetGuidelinesContent(),
+		Hint:              req.GetHint(),
+		WorkingDir:        req.GetWorkingDir(),
+		PreviousCode:      req.GetPreviousCode(),
+		Language:          req.GetLanguage(),
+		ContextLines:      int(req.GetContextLines()),
+		OutputFormat:      req.GetOutputFormat(),
+		Audience:          req.GetAudience(),
+		CoverageProfile:   req.GetCoverageProfile(),
+		CoverageFile:      req.GetCoverageFile(),
+		RunCoverage:       req.GetRunCoverage(),
+		IdempotencyKey:    req.GetIdempotencyKey(),
+		Thresholds: codereview.Thresholds{
+			FunctionLines: int(thresholds.GetFunctionLines()),
+			Parameters:    int(thresholds.GetParameters()),
+			StructFields:  int(thresholds.GetStructFields()),
+			Complexity:    int(thresholds.GetComplexity()),
+		},
+	})
+	text, err := resultText(result, err)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &assistantv1.CodeReviewResponse{Text: text}
+	if review == nil {
+		return resp, nil
+	}
+	resp.Summary = review.Summary
+	resp.Score = int32(review.Score)
+	resp.Warnings = review.Warnings
+	for _, issue := range review.Issues {
+		resp.Issues = append(resp.Issues, &assistantv1.Issue{
+			Type:             issue.Type,
+			Category:         issue.Category,
+			Line:             int32(issue.Line),
+			Column:           int32(issue.Column),
+			EndLine:          int32(issue.EndLine),
+			Message:          issue.Message,
+			Suggestion:       issue.Suggestion,
+			Severity:         issue.Severity,
+			Rule:             issue.Rule,
+			Example:          issue.Example,
+			Snippet:          issue.Snippet,
+			SnippetStartLine: int32(issue.SnippetStartLine),
+			File:             issue.File,
+			Uncovered:        issue.Uncovered,
+		})
+	}
+	for _, suggestion := range review.Suggestions {
+		resp.Suggestions = append(resp.Suggestions, &assistantv1.Suggestion{
+			Category: suggestion.Category,
+			Message:  suggestion.Message,
+			Example:  suggestion.Example,
+			Impact:   suggestion.Impact,
+		})
+	}
+	return resp, nil
+}
+
+// TestGen runs the test-gen tool
+func (s *Service) TestGen(ctx context.Context, req *assistantv1.TestGenRequest) (*assistantv1.TestGenResponse, error) {
+	result, generated, err := s.handlers.TestGen(withClientID(ctx), nil, testgen.TestGenParams{
+		GoCode:              req.GetGoCode(),
+		PackageName:         req.GetPackageName(),
+		Focus:               req.GetFocus(),
+		SamePackage:         req.GetSamePackage(),
+		MockPackage:         req.GetMockPackage(),
+		ImportPath:          req.GetImportPath(),
+		ReceiverConstructor: req.GetReceiverConstructor(),
+		GoVersion:           req.GetGoVersion(),
+		ExistingTests:       req.GetExistingTests(),
+		ExistingTestsFile:   req.GetExistingTestsFile(),
+		IdempotencyKey:      req.GetIdempotencyKey(),
+	})
+	if _, err := resultText(result, err); err != nil {
+		return nil, err
+	}
+
+	resp := &assistantv1.TestGenResponse{}
+	if generated == nil {
+		return resp, nil
+	}
+	resp.Text = generated.String()
+	resp.Suggestions = generated.Suggestions
+	resp.Compiles = generated.Compiles
+	if generated.Diff == nil {
+		for _, f := range generated.Files() {
+			resp.Files = append(resp.Files, &assistantv1.GeneratedFile{Path: f.Path, Content: f.Content})
+		}
+	}
+	for _, d := range generated.Diagnostics {
+		resp.Diagnostics = append(resp.Diagnostics, d.String())
+	}
+	return resp, nil
+}
+
+// withClientID gives calls of each gRPC peer their own rate-limit identity
+func withClientID(ctx context.Context) context.Context {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return ratelimit.WithClientID(ctx, "grpc:"+p.Addr.String())
+	}
+	return ratelimit.WithClientID(ctx, "grpc")
+}
+
+// resultText returns the text content of a tool result, or the gRPC status
+// for a failed call
+func resultText(result *mcp.CallToolResult, err error) (string, error) {
+	if err != nil {
+		return "", toStatus(err)
+	}
+	var texts []string
+	if result != nil {
+		for _, c := range result.Content {
+			if text, ok := c.(*mcp.TextContent); ok {
+				texts = append(texts, text.Text)
+			}
+		}
+	}
+	text := strings.Join(texts, "\n\n")
+	if result != nil && result.IsError {
+		return "", status.Error(codes.Internal, text)
+	}
+	return text, nil
+}
+
+// toStatus converts a tool error to a gRPC status, with the code matching
+// the HTTP-like status of MCP errors
+func toStatus(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	var mcpErr types.MCPError
+	if !errors.As(err, &mcpErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	code := codes.Internal
+	switch mcpErr.StatusCode() {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		code = codes.DeadlineExceeded
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		code = codes.Unavailable
+	}
+	return status.Error(code, mcpErr.Error())
+}
@@ -0,0 +1,161 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"mcp-go-assistant/internal/codereview"
+	"mcp-go-assistant/internal/godoc"
+	"mcp-go-assistant/internal/ratelimit"
+	"mcp-go-assistant/internal/testgen"
+	"mcp-go-assistant/internal/types"
+	"mcp-go-assistant/pkg/assistantv1"
+)
+
+// newTestClient serves handlers on an in-memory listener and returns a
+// client connected to it
+func newTestClient(t *testing.T, handlers Handlers) assistantv1.AssistantClient {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	server := NewServer(handlers)
+	go func() { _ = server.Serve(ln) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return assistantv1.NewAssistantClient(conn)
+}
+
+func textResult(text string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}
+}
+
+func TestService_GoDoc(t *testing.T) {
+	var got godoc.GoDocParams
+	var clientID string
+	client := newTestClient(t, Handlers{
+		GoDoc: func(ctx context.Context, _ *mcp.CallToolRequest, in godoc.GoDocParams) (*mcp.CallToolResult, any, error) {
+			got = in
+			clientID = ratelimit.ClientIDFromContext(ctx)
+			return textResult("func Println(a ...any) (n int, err error)"), nil, nil
+		},
+	})
+
+	resp, err := client.GoDoc(context.Background(), &assistantv1.GoDocRequest{PackagePath: "fmt", SymbolName: "Println", Mode: "api"})
+	if err != nil {
+		t.Fatalf("GoDoc() error = %v", err)
+	}
+	if resp.GetText() != "func Println(a ...any) (n int, err error)" {
+		t.Errorf("unexpected text %q", resp.GetText())
+	}
+	if got.PackagePath != "fmt" || got.SymbolName != "Println" || got.Mode != "api" {
+		t.Errorf("unexpected tool parameters %+v", got)
+	}
+	if clientID == "" || clientID == "default" {
+		t.Errorf("expected a rate-limit identity for the gRPC peer, got %q", clientID)
+	}
+}
+
+func TestService_CodeReview(t *testing.T) {
+	var got codereview.CodeReviewParams
+	client := newTestClient(t, Handlers{
+		CodeReview: func(_ context.Context, _ *mcp.CallToolRequest, in codereview.CodeReviewParams) (*mcp.CallToolResult, *codereview.ReviewResult, error) {
+			got = in
+			return textResult("report"), &codereview.ReviewResult{
+				Summary:     "1 issue",
+				Score:       90,
+				Issues:      []codereview.Issue{{Rule: "unchecked-error", Severity: "high", Line: 12, Message: "Error not checked"}},
+				Suggestions: []codereview.Suggestion{{Category: "readability", Message: "Split the function"}},
+			}, nil
+		},
+	})
+
+	resp, err := client.CodeReview(context.Background(), &assistantv1.CodeReviewRequest{
+		GoCode:       "package x",
+		OutputFormat: "compact",
+		Thresholds:   &assistantv1.Thresholds{Complexity: 15},
+	})
+	if err != nil {
+		t.Fatalf("CodeReview() error = %v", err)
+	}
+	if got.GoCode != "package x" || got.OutputFormat != "compact" || got.Thresholds.Complexity != 15 {
+		t.Errorf("unexpected tool parameters %+v", got)
+	}
+	if resp.GetText() != "report" || resp.GetScore() != 90 || resp.GetSummary() != "1 issue" {
+		t.Errorf("unexpected response %v", resp)
+	}
+	if len(resp.GetIssues()) != 1 || resp.GetIssues()[0].GetRule() != "unchecked-error" || resp.GetIssues()[0].GetLine() != 12 {
+		t.Errorf("unexpected issues %v", resp.GetIssues())
+	}
+	if len(resp.GetSuggestions()) != 1 || resp.GetSuggestions()[0].GetMessage() != "Split the function" {
+		t.Errorf("unexpected suggestions %v", resp.GetSuggestions())
+	}
+}
+
+func TestService_TestGen(t *testing.T) {
+	compiles := true
+	client := newTestClient(t, Handlers{
+		TestGen: func(_ context.Context, _ *mcp.CallToolRequest, in testgen.TestGenParams) (*mcp.CallToolResult, *testgen.TestGenResult, error) {
+			return textResult("ignored"), &testgen.TestGenResult{
+				TestCode: "package x_test\n",
+				TestFile: "x_test.go",
+				MockCode: "package x_test\n// mocks\n",
+				MockFile: "mock_test.go",
+				Compiles: &compiles,
+			}, nil
+		},
+	})
+
+	resp, err := client.TestGen(context.Background(), &assistantv1.TestGenRequest{GoCode: "package x"})
+	if err != nil {
+		t.Fatalf("TestGen() error = %v", err)
+	}
+	if len(resp.GetFiles()) != 2 || resp.GetFiles()[0].GetPath() != "x_test.go" || resp.GetFiles()[1].GetPath() != "mock_test.go" {
+		t.Errorf("unexpected files %v", resp.GetFiles())
+	}
+	if resp.Compiles == nil || !resp.GetCompiles() {
+		t.Errorf("expected compiles to be set, got %v", resp.Compiles)
+	}
+}
+
+func TestService_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{name: "validation", err: types.NewValidationError("package_path is required"), want: codes.InvalidArgument},
+		{name: "rate limit", err: types.NewRateLimitError("too many requests"), want: codes.ResourceExhausted},
+		{name: "circuit breaker", err: types.NewCircuitBreakerError("circuit open"), want: codes.Unavailable},
+		{name: "timeout", err: types.NewTimeoutError("timed out"), want: codes.DeadlineExceeded},
+		{name: "internal", err: types.NewInternalError("failed"), want: codes.Internal},
+		{name: "plain error", err: context.DeadlineExceeded, want: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, Handlers{
+				GoDoc: func(context.Context, *mcp.CallToolRequest, godoc.GoDocParams) (*mcp.CallToolResult, any, error) {
+					return nil, nil, tt.err
+				},
+			})
+			_, err := client.GoDoc(context.Background(), &assistantv1.GoDocRequest{})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("status code = %v, want %v (%v)", got, tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,581 @@
+// Package grpcreview reviews the implementations of the gRPC services
+// generated by protoc-gen-go-grpc in a workspace. It works on the syntax of
+// the packages, so it needs neither the grpc module nor compiling code.
+package grpcreview
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mcp-go-assistant/internal/golist"
+	"mcp-go-assistant/internal/workspace"
+)
+
+// mustEmbedPrefix starts the unexported method generated service
+// interfaces require to force embedding the Unimplemented server type
+const mustEmbedPrefix = "mustEmbedUnimplemented"
+
+// Options controls which directories may be reviewed
+type Options struct {
+	Roots []string // Registered workspace roots; working_dir and the packages must be inside one
+}
+
+// parsedFile is a parsed Go file of a listed package
+type parsedFile struct {
+	name      string // Slash path relative to working_dir
+	syntax    *ast.File
+	imports   map[string]string // Import paths by local name
+	generated bool              // A .pb.go file
+}
+
+// parsedPackage is a listed package with its declarations indexed
+type parsedPackage struct {
+	path  string
+	files []*parsedFile
+	types map[string]*typeDecl
+	funcs map[string]bool // Top-level functions
+}
+
+// typeDecl is a declared type with the methods declared on it
+type typeDecl struct {
+	name    string
+	pkg     *parsedPackage
+	file    *parsedFile
+	spec    *ast.TypeSpec
+	methods map[string]*methodDecl
+}
+
+// methodDecl is a method declaration
+type methodDecl struct {
+	decl *ast.FuncDecl
+	file *parsedFile
+}
+
+// service is a generated service interface
+type service struct {
+	name       string // e.g. "Greeter"
+	iface      string // e.g. "GreeterServer"
+	pkg        *parsedPackage
+	decl       *typeDecl
+	methods    []Method
+	mustEmbed  bool // The interface requires embedding UnimplementedXServer
+	implsByKey map[string]*impl
+	impls      []*impl
+}
+
+// impl is a type implementing a service
+type impl struct {
+	typ          *typeDecl
+	embedsUnimpl bool
+	embedsUnsafe bool
+	pointerEmbed *ast.Field // Embedded *UnimplementedXServer
+}
+
+// reviewer holds the parsed workspace and the findings of a review
+type reviewer struct {
+	fset        *token.FileSet
+	pkgs        map[string]*parsedPackage
+	order       []string // Package paths in go list order
+	findings    []Finding
+	suggestions []string
+}
+
+// Review parses the workspace packages matching the package pattern, finds
+// the generated service interfaces and their implementations, and reviews
+// the implemented methods
+func Review(ctx context.Context, params GRPCReviewParams, opts Options) (*GRPCReviewResult, error) {
+	if params.WorkingDir == "" {
+		return nil, fmt.Errorf("working_dir parameter is required")
+	}
+	dir, err := workspace.Dir("reviewing gRPC services", params.WorkingDir, opts.Roots)
+	if err != nil {
+		return nil, err
+	}
+	pattern := params.Package
+	if pattern == "" {
+		pattern = "./..."
+	}
+	if strings.HasPrefix(pattern, "-") {
+		return nil, fmt.Errorf("package must be a package pattern: %s", pattern)
+	}
+
+	listed, err := golist.List(ctx, dir, pattern, golist.Options{AllowErrors: true})
+	if err != nil {
+		return nil, err
+	}
+	r := &reviewer{fset: token.NewFileSet(), pkgs: make(map[string]*parsedPackage)}
+	for _, lp := range listed {
+		if len(lp.GoFiles) == 0 {
+			continue
+		}
+		if !workspace.Contains(opts.Roots, lp.Dir) {
+			continue
+		}
+		if err := r.parsePackage(dir, lp); err != nil {
+			return nil, err
+		}
+	}
+	if len(r.pkgs) == 0 {
+		return nil, fmt.Errorf("no Go packages in the workspace match %s", pattern)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	services := r.services(params.Service)
+	if len(services) == 0 && params.Service != "" {
+		return nil, fmt.Errorf("service %s not found in the generated gRPC code matching %s", params.Service, pattern)
+	}
+	r.implementations(services)
+
+	result := &GRPCReviewResult{Services: []Service{}, Suggestions: []string{}}
+	impls := 0
+	for _, svc := range services {
+		result.Services = append(result.Services, r.review(svc))
+		impls += len(svc.impls)
+	}
+	sort.SliceStable(r.findings, func(i, j int) bool {
+		a, b := r.findings[i], r.findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	result.Findings = append([]Finding{}, r.findings...)
+
+	result.Suggestions = append(result.Suggestions, r.suggestions...)
+	if len(services) == 0 {
+		result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+			"No generated gRPC service interfaces found in %s; generate them with protoc-gen-go-grpc or widen package.", pattern))
+	}
+	for _, svc := range services {
+		if len(svc.impls) == 0 {
+			result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+				"No implementation of %s found: no type embeds Unimplemented%s or is passed to Register%s in the packages matching %s.",
+				svc.name, svc.iface, svc.iface, pattern))
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, f := range result.Findings {
+		counts[f.Severity]++
+	}
+	result.Summary = fmt.Sprintf("%d services with %d implementations: %d findings (%d high, %d medium, %d low)",
+		len(services), impls, len(result.Findings), counts["high"], counts["medium"], counts["low"])
+	return result, nil
+}
+
+// parsePackage parses the files of a listed package and indexes its types,
+// methods and functions
+func (r *reviewer) parsePackage(dir string, lp golist.Package) error {
+	pkg := &parsedPackage{path: lp.ImportPath, types: make(map[string]*typeDecl), funcs: make(map[string]bool)}
+	files, err := lp.ReadFiles(dir)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for rel := range files {
+		names = append(names, rel)
+	}
+	sort.Strings(names)
+	for _, rel := range names {
+		syntax, err := parser.ParseFile(r.fset, rel, files[rel], parser.SkipObjectResolution)
+		if err != nil {
+			r.suggestions = append(r.suggestions, fmt.Sprintf("%s was skipped because it does not parse: %v", rel, err))
+			continue
+		}
+
+		f := &parsedFile{name: rel, syntax: syntax, imports: make(map[string]string), generated: strings.HasSuffix(rel, ".pb.go")}
+		for _, spec := range syntax.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			local := path[strings.LastIndex(path, "/")+1:]
+			if spec.Name != nil {
+				local = spec.Name.Name
+			}
+			f.imports[local] = path
+		}
+		pkg.files = append(pkg.files, f)
+
+		for _, decl := range syntax.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						t := pkg.typeDecl(ts.Name.Name)
+						t.file, t.spec = f, ts
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					pkg.funcs[d.Name.Name] = true
+					continue
+				}
+				if name := receiverName(d.Recv.List[0].Type); name != "" {
+					pkg.typeDecl(name).methods[d.Name.Name] = &methodDecl{decl: d, file: f}
+				}
+			}
+		}
+	}
+	r.pkgs[pkg.path] = pkg
+	r.order = append(r.order, pkg.path)
+	return nil
+}
+
+// typeDecl returns the declaration of a type of the package, creating it
+// for methods seen before their type
+func (p *parsedPackage) typeDecl(name string) *typeDecl {
+	t, ok := p.types[name]
+	if !ok {
+		t = &typeDecl{name: name, pkg: p, methods: make(map[string]*methodDecl)}
+		p.types[name] = t
+	}
+	return t
+}
+
+// services finds the server interfaces of generated files that have a
+// Register function, optionally only the one named
+func (r *reviewer) services(only string) []*service {
+	var services []*service
+	for _, path := range r.order {
+		pkg := r.pkgs[path]
+		names := make([]string, 0, len(pkg.types))
+		for name := range pkg.types {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			t := pkg.types[name]
+			if t.spec == nil || !t.file.generated || !strings.HasSuffix(name, "Server") || !pkg.funcs["Register"+name] {
+				continue
+			}
+			iface, ok := t.spec.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			svc := &service{name: strings.TrimSuffix(name, "Server"), iface: name, pkg: pkg, decl: t, implsByKey: make(map[string]*impl)}
+			if only != "" && only != svc.name && only != svc.iface && only != path+"."+svc.name {
+				continue
+			}
+			for _, field := range iface.Methods.List {
+				fn, ok := field.Type.(*ast.FuncType)
+				if !ok || len(field.Names) == 0 {
+					continue
+				}
+				if strings.HasPrefix(field.Names[0].Name, mustEmbedPrefix) {
+					svc.mustEmbed = true
+					continue
+				}
+				svc.methods = append(svc.methods, Method{Name: field.Names[0].Name, Streaming: streaming(pkg, fn)})
+			}
+			services = append(services, svc)
+		}
+	}
+	return services
+}
+
+// streaming returns the kind of streaming of a service method from its
+// last parameter: a grpc generic stream type, or a generated stream
+// interface with Send and Recv methods
+func streaming(pkg *parsedPackage, fn *ast.FuncType) string {
+	params := fn.Params.List
+	if len(params) == 0 {
+		return ""
+	}
+	typ := params[len(params)-1].Type
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+
+	name := ""
+	switch t := typ.(type) {
+	case *ast.SelectorExpr:
+		name = t.Sel.Name
+	case *ast.Ident:
+		name = t.Name
+	}
+	switch name {
+	case "ServerStreamingServer":
+		return "server"
+	case "ClientStreamingServer":
+		return "client"
+	case "BidiStreamingServer":
+		return "bidi"
+	}
+
+	stream, ok := pkg.types[name]
+	if !ok || stream.spec == nil {
+		return ""
+	}
+	iface, ok := stream.spec.Type.(*ast.InterfaceType)
+	if !ok {
+		return ""
+	}
+	var send, recv bool
+	for _, field := range iface.Methods.List {
+		for _, n := range field.Names {
+			send = send || n.Name == "Send"
+			recv = recv || n.Name == "Recv"
+		}
+	}
+	switch {
+	case send && recv:
+		return "bidi"
+	case send:
+		return "server"
+	case recv:
+		return "client"
+	}
+	return ""
+}
+
+// implementations finds the types embedding the Unimplemented or Unsafe
+// server types of the services, and those passed to their Register
+// functions
+func (r *reviewer) implementations(services []*service) {
+	if len(services) == 0 {
+		return
+	}
+	for _, path := range r.order {
+		pkg := r.pkgs[path]
+		for _, f := range pkg.files {
+			if f.generated {
+				continue
+			}
+			ast.Inspect(f.syntax, func(n ast.Node) bool {
+				switch node := n.(type) {
+				case *ast.TypeSpec:
+					st, ok := node.Type.(*ast.StructType)
+					if !ok {
+						return true
+					}
+					for _, field := range st.Fields.List {
+						if len(field.Names) > 0 {
+							continue
+						}
+						typ, pointer := field.Type, false
+						if star, ok := typ.(*ast.StarExpr); ok {
+							typ, pointer = star.X, true
+						}
+						embedPath, embedName := r.resolve(pkg, f, typ)
+						for _, svc := range services {
+							if embedPath != svc.pkg.path {
+								continue
+							}
+							switch embedName {
+							case "Unimplemented" + svc.iface:
+								im := svc.add(pkg.types[node.Name.Name])
+								im.embedsUnimpl = true
+								if pointer {
+									im.pointerEmbed = field
+								}
+							case "Unsafe" + svc.iface:
+								svc.add(pkg.types[node.Name.Name]).embedsUnsafe = true
+							}
+						}
+					}
+				case *ast.CallExpr:
+					fnPath, fnName := r.resolve(pkg, f, node.Fun)
+					for _, svc := range services {
+						if fnPath != svc.pkg.path || fnName != "Register"+svc.iface || len(node.Args) != 2 {
+							continue
+						}
+						if t := r.registered(pkg, f, node.Args[1]); t != nil {
+							svc.add(t)
+						} else {
+							pos := r.fset.Position(node.Pos())
+							r.suggestions = append(r.suggestions, fmt.Sprintf(
+								"The server registered at %s:%d could not be resolved without type-checking; make sure its type is reviewed.", pos.Filename, pos.Line))
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+}
+
+// add returns the implementation of the service by t, adding it the first
+// time
+func (s *service) add(t *typeDecl) *impl {
+	key := t.pkg.path + "." + t.name
+	if im, ok := s.implsByKey[key]; ok {
+		return im
+	}
+	im := &impl{typ: t}
+	s.implsByKey[key] = im
+	s.impls = append(s.impls, im)
+	return im
+}
+
+// resolve returns the package path and name an identifier or qualified
+// identifier refers to
+func (r *reviewer) resolve(pkg *parsedPackage, f *parsedFile, expr ast.Expr) (string, string) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return pkg.path, e.Name
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok {
+			if path, ok := f.imports[x.Name]; ok {
+				return path, e.Sel.Name
+			}
+		}
+	}
+	return "", ""
+}
+
+// registered returns the declared type of the server passed to a Register
+// function when it is a composite literal or new(T)
+func (r *reviewer) registered(pkg *parsedPackage, f *parsedFile, arg ast.Expr) *typeDecl {
+	if u, ok := arg.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		arg = u.X
+	}
+	var typ ast.Expr
+	switch a := arg.(type) {
+	case *ast.CompositeLit:
+		typ = a.Type
+	case *ast.CallExpr:
+		if id, ok := a.Fun.(*ast.Ident); ok && id.Name == "new" && len(a.Args) == 1 {
+			typ = a.Args[0]
+		}
+	}
+	if typ == nil {
+		return nil
+	}
+	path, name := r.resolve(pkg, f, typ)
+	target, ok := r.pkgs[path]
+	if !ok {
+		return nil
+	}
+	if t, ok := target.types[name]; ok && t.spec != nil {
+		return t
+	}
+	return nil
+}
+
+// methods returns the methods declared on t and on the workspace types it
+// embeds, which are promoted
+func (r *reviewer) methods(t *typeDecl, visited map[*typeDecl]bool) map[string]*methodDecl {
+	methods := make(map[string]*methodDecl)
+	if visited[t] {
+		return methods
+	}
+	visited[t] = true
+
+	if st, ok := t.spec.Type.(*ast.StructType); ok {
+		for _, field := range st.Fields.List {
+			if len(field.Names) > 0 {
+				continue
+			}
+			typ := field.Type
+			if star, ok := typ.(*ast.StarExpr); ok {
+				typ = star.X
+			}
+			path, name := r.resolve(t.pkg, t.file, typ)
+			pkg, ok := r.pkgs[path]
+			if !ok || pkg.types[name] == nil || pkg.types[name].spec == nil || pkg.types[name].file.generated {
+				continue
+			}
+			for n, m := range r.methods(pkg.types[name], visited) {
+				methods[n] = m
+			}
+		}
+	}
+	for n, m := range t.methods {
+		methods[n] = m
+	}
+	return methods
+}
+
+// review checks the implementations of a service
+func (r *reviewer) review(svc *service) Service {
+	pos := r.fset.Position(svc.decl.spec.Pos())
+	result := Service{
+		Name:            svc.name,
+		Package:         svc.pkg.path,
+		File:            pos.Filename,
+		Line:            pos.Line,
+		Methods:         svc.methods,
+		Implementations: []Implementation{},
+	}
+
+	for _, im := range svc.impls {
+		t := im.typ
+		pos := r.fset.Position(t.spec.Pos())
+		typeName := t.pkg.path + "." + t.name
+		out := Implementation{
+			Type:                typeName,
+			File:                pos.Filename,
+			Line:                pos.Line,
+			Implemented:         []string{},
+			Unimplemented:       []string{},
+			EmbedsUnimplemented: im.embedsUnimpl,
+		}
+		finding := func(kind, method, severity string, node ast.Node, message, suggestion string) {
+			p := r.fset.Position(node.Pos())
+			r.findings = append(r.findings, Finding{
+				Kind: kind, Service: svc.name, Type: typeName, Method: method, File: p.Filename, Line: p.Line,
+				Severity: severity, Message: message, Suggestion: suggestion,
+			})
+		}
+
+		if svc.mustEmbed && !im.embedsUnimpl && !im.embedsUnsafe {
+			finding("missing-embed", "", "high", t.spec,
+				fmt.Sprintf("%s does not embed Unimplemented%s, so it lacks the %s%s method and does not implement %s.", t.name, svc.iface, mustEmbedPrefix, svc.iface, svc.iface),
+				fmt.Sprintf("Embed %s.Unimplemented%s by value to stay compatible when RPCs are added to the service.", svc.pkg.path[strings.LastIndex(svc.pkg.path, "/")+1:], svc.iface))
+		}
+		if im.pointerEmbed != nil {
+			finding("pointer-embed", "", "medium", im.pointerEmbed,
+				fmt.Sprintf("%s embeds *Unimplemented%s; while the pointer is nil, registering the server or calling an unimplemented RPC panics.", t.name, svc.iface),
+				fmt.Sprintf("Embed Unimplemented%s by value.", svc.iface))
+		}
+
+		methods := r.methods(t, make(map[*typeDecl]bool))
+		for _, m := range svc.methods {
+			decl, ok := methods[m.Name]
+			if !ok {
+				out.Unimplemented = append(out.Unimplemented, m.Name)
+				if im.embedsUnimpl {
+					finding("unimplemented-method", m.Name, "medium", t.spec,
+						fmt.Sprintf("%s does not implement %s; calls fail with codes.Unimplemented through the embedded Unimplemented%s.", t.name, m.Name, svc.iface),
+						fmt.Sprintf("Implement %s on %s, or remove the RPC from the service definition.", m.Name, t.name))
+				} else {
+					finding("unimplemented-method", m.Name, "high", t.spec,
+						fmt.Sprintf("%s does not implement %s, so it does not implement %s.", t.name, m.Name, svc.iface),
+						fmt.Sprintf("Implement %s on %s.", m.Name, t.name))
+				}
+				continue
+			}
+			out.Implemented = append(out.Implemented, m.Name)
+			for _, c := range checkMethod(decl, m) {
+				finding(c.kind, m.Name, c.severity, c.node, c.message, c.suggestion)
+			}
+		}
+		result.Implementations = append(result.Implementations, out)
+	}
+	return result
+}
+
+// receiverName returns the type name of a method receiver
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
@@ -0,0 +1,243 @@
+package grpcreview
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+// writeModule creates a module with generated Greeter code in api and its
+// implementation in server
+func writeModule(t *testing.T) string {
+	t.Helper()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.23\n",
+		"api/greeter.pb.go": `package api
+
+type HelloRequest struct{ Name string }
+
+func (r *HelloRequest) GetName() string { return r.Name }
+
+type HelloReply struct{ Message string }
+`,
+		"api/greeter_grpc.pb.go": `package api
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+type GreeterServer interface {
+	SayHello(context.Context, *HelloRequest) (*HelloReply, error)
+	SayGoodbye(context.Context, *HelloRequest) (*HelloReply, error)
+	Watch(*HelloRequest, grpc.ServerStreamingServer[HelloReply]) error
+	Chat(grpc.BidiStreamingServer[HelloRequest, HelloReply]) error
+	mustEmbedUnimplementedGreeterServer()
+}
+
+type UnimplementedGreeterServer struct{}
+
+func (UnimplementedGreeterServer) SayHello(context.Context, *HelloRequest) (*HelloReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SayHello not implemented")
+}
+func (UnimplementedGreeterServer) mustEmbedUnimplementedGreeterServer() {}
+
+func RegisterGreeterServer(s grpc.ServiceRegistrar, srv GreeterServer) {}
+
+type Greeter_LegacyServer interface {
+	Send(*HelloReply) error
+	grpc.ServerStream
+}
+`,
+		"server/server.go": `package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "example.com/app/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type greeter struct {
+	pb.UnimplementedGreeterServer
+	db *store
+}
+
+type store struct{}
+
+func (s *store) Lookup(ctx context.Context, name string) (string, error) { return name, nil }
+
+func (g *greeter) SayHello(_ context.Context, req *pb.HelloRequest) (*pb.HelloReply, error) {
+	if req.GetName() == "" {
+		return nil, errors.New("name is required")
+	}
+	if req.GetName() == "nobody" {
+		return nil, nil
+	}
+	msg, err := g.db.Lookup(context.Background(), req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.Unknown, fmt.Sprintf("lookup %s: %v", req.GetName(), err))
+	}
+	return &pb.HelloReply{Message: msg}, nil
+}
+
+func (g *greeter) Watch(req *pb.HelloRequest, stream grpc.ServerStreamingServer[pb.HelloReply]) error {
+	for {
+		if err := stream.Send(&pb.HelloReply{Message: req.GetName()}); err != nil {
+			return err
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func (g *greeter) Chat(stream grpc.BidiStreamingServer[pb.HelloRequest, pb.HelloReply]) error {
+	ctx, cancel := context.WithTimeout(stream.Context(), time.Minute)
+	defer cancel()
+	_, _ = context.WithCancel(ctx)
+	return status.Error(codes.OK, "done")
+}
+
+type legacy struct {
+	*pb.UnimplementedGreeterServer
+}
+
+type unsafe struct{}
+
+func (unsafe) SayHello(ctx context.Context, req *pb.HelloRequest) (*pb.HelloReply, error) {
+	return &pb.HelloReply{Message: "hi " + req.GetName()}, ctx.Err()
+}
+
+func Register(s *grpc.Server) {
+	pb.RegisterGreeterServer(s, &greeter{})
+	pb.RegisterGreeterServer(s, unsafe{})
+	pb.RegisterGreeterServer(s, newServer())
+}
+
+func newServer() pb.GreeterServer { return &legacy{} }
+`,
+	}
+	return testutil.WriteModule(t, files)
+}
+
+func TestReview(t *testing.T) {
+	dir := writeModule(t)
+	result, err := Review(context.Background(), GRPCReviewParams{WorkingDir: dir}, Options{Roots: []string{dir}})
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+
+	if len(result.Services) != 1 {
+		t.Fatalf("expected one service, got %+v", result.Services)
+	}
+	svc := result.Services[0]
+	if svc.Name != "Greeter" || svc.Package != "example.com/app/api" || svc.File != "api/greeter_grpc.pb.go" {
+		t.Errorf("unexpected service %+v", svc)
+	}
+	var methods []string
+	for _, m := range svc.Methods {
+		methods = append(methods, m.Name+":"+m.Streaming)
+	}
+	if got := strings.Join(methods, ","); got != "SayHello:,SayGoodbye:,Watch:server,Chat:bidi" {
+		t.Errorf("unexpected methods %s", got)
+	}
+
+	impls := make(map[string]Implementation)
+	for _, im := range svc.Implementations {
+		impls[im.Type] = im
+	}
+	if len(impls) != 3 {
+		t.Fatalf("expected greeter, legacy and unsafe, got %+v", svc.Implementations)
+	}
+	greeter := impls["example.com/app/server.greeter"]
+	if !greeter.EmbedsUnimplemented || strings.Join(greeter.Unimplemented, ",") != "SayGoodbye" {
+		t.Errorf("unexpected greeter implementation %+v", greeter)
+	}
+	if unsafe := impls["example.com/app/server.unsafe"]; unsafe.EmbedsUnimplemented || strings.Join(unsafe.Implemented, ",") != "SayHello" {
+		t.Errorf("unexpected unsafe implementation %+v", unsafe)
+	}
+
+	kinds := make(map[string]string)
+	for _, f := range result.Findings {
+		kinds[f.Type[strings.LastIndex(f.Type, ".")+1:]+"."+f.Method+":"+f.Kind] = f.Severity
+	}
+	for key, severity := range map[string]string{
+		"greeter.SayGoodbye:unimplemented-method": "medium",
+		"greeter.SayHello:plain-error":            "medium",
+		"greeter.SayHello:nil-response":           "medium",
+		"greeter.SayHello:background-context":     "medium",
+		"greeter.SayHello:unknown-code":           "low",
+		"greeter.SayHello:status-sprintf":         "low",
+		"greeter.SayHello:context-unused":         "low",
+		"greeter.Watch:stream-loop":               "low",
+		"greeter.Chat:cancel-discarded":           "medium",
+		"greeter.Chat:status-ok":                  "high",
+		"legacy.:pointer-embed":                   "medium",
+		"legacy.SayHello:unimplemented-method":    "medium",
+		"unsafe.:missing-embed":                   "high",
+		"unsafe.Watch:unimplemented-method":       "high",
+	} {
+		if got, ok := kinds[key]; !ok || got != severity {
+			t.Errorf("expected finding %s with severity %s, got %q", key, severity, got)
+		}
+	}
+	if _, ok := kinds["unsafe.SayHello:context-unused"]; ok {
+		t.Errorf("unexpected context-unused finding for a handler using ctx")
+	}
+
+	found := false
+	for _, s := range result.Suggestions {
+		found = found || strings.Contains(s, "could not be resolved")
+	}
+	if !found {
+		t.Errorf("expected a suggestion for the unresolved registration, got %v", result.Suggestions)
+	}
+}
+
+func TestReview_Service(t *testing.T) {
+	dir := writeModule(t)
+	result, err := Review(context.Background(), GRPCReviewParams{WorkingDir: dir, Package: "./api", Service: "GreeterServer"}, Options{Roots: []string{dir}})
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+	if len(result.Services) != 1 || len(result.Services[0].Implementations) != 0 {
+		t.Fatalf("expected the service without implementations, got %+v", result.Services)
+	}
+	if len(result.Suggestions) != 1 || !strings.Contains(result.Suggestions[0], "No implementation of Greeter found") {
+		t.Errorf("unexpected suggestions %v", result.Suggestions)
+	}
+}
+
+func TestReview_Errors(t *testing.T) {
+	dir := writeModule(t)
+	tests := []struct {
+		name    string
+		params  GRPCReviewParams
+		roots   []string
+		wantErr string
+	}{
+		{"no working dir", GRPCReviewParams{}, []string{dir}, "working_dir parameter is required"},
+		{"no roots", GRPCReviewParams{WorkingDir: dir}, nil, "requires at least one registered workspace root"},
+		{"outside roots", GRPCReviewParams{WorkingDir: dir}, []string{t.TempDir()}, "not inside a registered workspace"},
+		{"flag pattern", GRPCReviewParams{WorkingDir: dir, Package: "-toolexec=x"}, []string{dir}, "package must be a package pattern"},
+		{"unknown service", GRPCReviewParams{WorkingDir: dir, Service: "Farewell"}, []string{dir}, "service Farewell not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Review(context.Background(), tt.params, Options{Roots: tt.roots})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package grpcreview
+
+import (
+	"fmt"
+	"go/ast"
+)
+
+const (
+	contextPath = "context"
+	statusPath  = "google.golang.org/grpc/status"
+	codesPath   = "google.golang.org/grpc/codes"
+)
+
+// pureCalls are package functions that do no work worth a deadline
+var pureCalls = map[string]bool{
+	"errors": true, "fmt": true, "strings": true, "strconv": true, "unicode": true, "math": true,
+	"sort": true, "slices": true, "maps": true, "bytes": true, "time": true, "log": true, "log/slog": true,
+	statusPath: true, codesPath: true, "google.golang.org/protobuf/proto": true,
+}
+
+// check is a problem found in a handler
+type check struct {
+	kind       string
+	severity   string
+	node       ast.Node
+	message    string
+	suggestion string
+}
+
+// handler is a method implementing an RPC, with the names of its
+// parameters
+type handler struct {
+	decl   *ast.FuncDecl
+	file   *parsedFile
+	method Method
+	ctx    string // Context parameter of unary RPCs; "" when unnamed
+	req    string // Request parameter of unary and server-streaming RPCs
+	stream string // Stream parameter of streaming RPCs
+	checks []check
+}
+
+// checkMethod reviews the context handling and errors of a handler
+func checkMethod(m *methodDecl, method Method) []check {
+	h := &handler{decl: m.decl, file: m.file, method: method}
+	if m.decl.Body == nil {
+		return nil
+	}
+	var params []string
+	for _, field := range m.decl.Type.Params.List {
+		if len(field.Names) == 0 {
+			params = append(params, "")
+		}
+		for _, n := range field.Names {
+			params = append(params, n.Name)
+		}
+	}
+	if method.Streaming == "" {
+		if len(params) > 0 {
+			h.ctx = params[0]
+		}
+		if len(params) > 1 {
+			h.req = params[1]
+		}
+	} else if len(params) > 0 {
+		h.stream = params[len(params)-1]
+		if len(params) > 1 {
+			h.req = params[0]
+		}
+	}
+
+	h.contexts()
+	h.errors()
+	if method.Streaming == "" {
+		h.unusedContext()
+	} else {
+		h.streamLoops()
+	}
+	return h.checks
+}
+
+// add records a problem of the handler
+func (h *handler) add(kind, severity string, node ast.Node, message, suggestion string) {
+	h.checks = append(h.checks, check{kind: kind, severity: severity, node: node, message: message, suggestion: suggestion})
+}
+
+// pkgCall returns the name of the function of the package path called, or
+// ""
+func (h *handler) pkgCall(call *ast.CallExpr, path string) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	if x, ok := sel.X.(*ast.Ident); ok && h.file.imports[x.Name] == path {
+		return sel.Sel.Name
+	}
+	return ""
+}
+
+// isCode reports whether expr is the named code of the codes package
+func (h *handler) isCode(expr ast.Expr, code string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != code {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && h.file.imports[x.Name] == codesPath
+}
+
+// contexts flags handlers that start from a fresh context or drop the
+// cancel function of a derived one
+func (h *handler) contexts() {
+	ast.Inspect(h.decl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if name := h.pkgCall(node, contextPath); name == "Background" || name == "TODO" {
+				source := "ctx"
+				if h.method.Streaming != "" {
+					source = h.stream + ".Context()"
+				}
+				h.add("background-context", "medium", node,
+					fmt.Sprintf("%s uses context.%s instead of the request context, so the client's deadline and cancellation do not reach the work done with it.", h.decl.Name.Name, name),
+					fmt.Sprintf("Derive contexts from %s, e.g. context.WithTimeout(%s, d).", source, source))
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) != 2 || len(node.Rhs) != 1 {
+				return true
+			}
+			call, ok := node.Rhs[0].(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			switch name := h.pkgCall(call, contextPath); name {
+			case "WithTimeout", "WithDeadline", "WithCancel", "WithTimeoutCause", "WithDeadlineCause", "WithCancelCause":
+				if id, ok := node.Lhs[1].(*ast.Ident); ok && id.Name == "_" {
+					h.add("cancel-discarded", "medium", node,
+						fmt.Sprintf("%s discards the cancel function of context.%s, leaking the context's timer and goroutine until the parent is done.", h.decl.Name.Name, name),
+						"Keep the cancel function and defer cancel() right after creating the context.")
+				}
+			}
+		}
+		return true
+	})
+}
+
+// errors flags returned errors clients receive as codes.Unknown, and
+// status errors with misleading codes
+func (h *handler) errors() {
+	ast.Inspect(h.decl.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			// Closures return their own errors
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.ReturnStmt:
+			if len(node.Results) == 0 {
+				return true
+			}
+			if h.method.Streaming == "" && len(node.Results) == 2 && isNil(node.Results[0]) && isNil(node.Results[1]) {
+				h.add("nil-response", "medium", node,
+					fmt.Sprintf("%s returns a nil response with a nil error; clients receive an empty message that hides the missing result.", h.decl.Name.Name),
+					"Return an explicitly built response, or a status error such as status.Error(codes.NotFound, ...).")
+			}
+			call, ok := node.Results[len(node.Results)-1].(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			fn := ""
+			if name := h.pkgCall(call, "errors"); name == "New" {
+				fn = "errors.New"
+			} else if name := h.pkgCall(call, "fmt"); name == "Errorf" {
+				fn = "fmt.Errorf"
+			}
+			if fn != "" {
+				h.add("plain-error", "medium", node,
+					fmt.Sprintf("%s returns an error built with %s, which clients receive as codes.Unknown.", h.decl.Name.Name, fn),
+					"Return status.Error or status.Errorf with the code that fits, e.g. codes.InvalidArgument or codes.NotFound.")
+			}
+		case *ast.CallExpr:
+			name := h.pkgCall(node, statusPath)
+			if (name != "Error" && name != "Errorf" && name != "New" && name != "Newf") || len(node.Args) < 2 {
+				return true
+			}
+			switch {
+			case h.isCode(node.Args[0], "OK"):
+				h.add("status-ok", "high", node,
+					fmt.Sprintf("%s calls status.%s with codes.OK, which makes a nil error, so the failure is reported to the client as success.", h.decl.Name.Name, name),
+					"Use the code describing the failure, e.g. codes.Internal or codes.FailedPrecondition.")
+			case h.isCode(node.Args[0], "Unknown"):
+				h.add("unknown-code", "low", node,
+					fmt.Sprintf("%s returns codes.Unknown, which tells clients nothing they can act on.", h.decl.Name.Name),
+					"Use a specific code such as codes.Internal, codes.Unavailable or codes.InvalidArgument.")
+			}
+			if msg, ok := node.Args[1].(*ast.CallExpr); ok && len(node.Args) == 2 && h.pkgCall(msg, "fmt") == "Sprintf" && (name == "Error" || name == "New") {
+				h.add("status-sprintf", "low", node,
+					fmt.Sprintf("%s formats the message of status.%s with fmt.Sprintf.", h.decl.Name.Name, name),
+					fmt.Sprintf("Call status.%sf(code, format, args...) instead.", name))
+			}
+		}
+		return true
+	})
+}
+
+// unusedContext flags unary handlers that never use their context although
+// they call functions that may block
+func (h *handler) unusedContext() {
+	if h.ctx != "" && h.ctx != "_" && references(h.decl.Body, h.ctx) {
+		return
+	}
+	var blocking ast.Node
+	ast.Inspect(h.decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || blocking != nil {
+			return blocking == nil
+		}
+		switch fn := call.Fun.(type) {
+		case *ast.Ident:
+			// Builtins and conversions do not block; local functions may
+			if fn.Name == "len" || fn.Name == "cap" || fn.Name == "append" || fn.Name == "make" || fn.Name == "new" ||
+				fn.Name == "copy" || fn.Name == "delete" || fn.Name == "panic" || fn.Name == "string" || fn.Name == "min" || fn.Name == "max" {
+				return true
+			}
+		case *ast.SelectorExpr:
+			if x, ok := fn.X.(*ast.Ident); ok {
+				if path, ok := h.file.imports[x.Name]; ok && pureCalls[path] {
+					return true
+				}
+				// Getters of the request message
+				if x.Name == h.req {
+					return true
+				}
+			}
+		case *ast.ParenExpr, *ast.ArrayType, *ast.MapType, *ast.StarExpr:
+			return true
+		}
+		blocking = call
+		return false
+	})
+	if blocking == nil {
+		return
+	}
+	h.add("context-unused", "low", blocking,
+		fmt.Sprintf("%s never uses its context, so the calls it makes keep running after the client's deadline passes or the call is cancelled.", h.decl.Name.Name),
+		"Name the context parameter and pass it to the calls that do I/O, or check ctx.Err() in long-running work.")
+}
+
+// streamLoops flags loops sending on a stream that never look at the
+// stream's context, so they run on after the client is gone
+func (h *handler) streamLoops() {
+	if h.stream == "" || h.stream == "_" || h.method.Streaming == "client" {
+		return
+	}
+	usesContext := false
+	ast.Inspect(h.decl.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && isMethodCall(call, h.stream, "Context") {
+			usesContext = true
+		}
+		return !usesContext
+	})
+	if usesContext {
+		return
+	}
+
+	ast.Inspect(h.decl.Body, func(n ast.Node) bool {
+		var body *ast.BlockStmt
+		switch loop := n.(type) {
+		case *ast.ForStmt:
+			body = loop.Body
+		case *ast.RangeStmt:
+			body = loop.Body
+		default:
+			return true
+		}
+		var sends, recvs bool
+		ast.Inspect(body, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				sends = sends || isMethodCall(call, h.stream, "Send") || isMethodCall(call, h.stream, "SendMsg")
+				recvs = recvs || isMethodCall(call, h.stream, "Recv") || isMethodCall(call, h.stream, "RecvMsg")
+			}
+			return true
+		})
+		if !sends || recvs {
+			return true
+		}
+		h.add("stream-loop", "low", n,
+			fmt.Sprintf("%s sends in a loop without checking %s.Context(), so it keeps producing messages after the client cancels until Send fails.", h.decl.Name.Name, h.stream),
+			fmt.Sprintf("Select on %s.Context().Done() in the loop and return its error, or pass the context to the work producing the messages.", h.stream))
+		return false
+	})
+}
+
+// isMethodCall reports whether call is recv.name(...)
+func isMethodCall(call *ast.CallExpr, recv, name string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == recv
+}
+
+// references reports whether the identifier is used in node
+func references(node ast.Node, name string) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Name == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// isNil reports whether expr is the predeclared nil
+func isNil(expr ast.Expr) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && id.Name == "nil"
+}
@@ -0,0 +1,66 @@
+package grpcreview
+
+import "encoding/json"
+
+// GRPCReviewParams represents the parameters for the grpc-review tool
+type GRPCReviewParams struct {
+	WorkingDir     string `json:"working_dir" jsonschema:"description:Module or package directory inside a registered workspace root"`
+	Package        string `json:"package,omitempty" jsonschema:"description:Optional package pattern relative to working_dir holding the generated .pb.go files and the service implementations (defaults to './...')"`
+	Service        string `json:"service,omitempty" jsonschema:"description:Optional service to review, e.g. 'Greeter' (defaults to every service)"`
+	IdempotencyKey string `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// GRPCReviewResult holds the gRPC services of a workspace, their
+// implementations and the problems found in them
+type GRPCReviewResult struct {
+	Summary     string    `json:"summary"`
+	Services    []Service `json:"services"`
+	Findings    []Finding `json:"findings"`
+	Suggestions []string  `json:"suggestions"`
+}
+
+// Service is a service interface generated by protoc-gen-go-grpc
+type Service struct {
+	Name            string           `json:"name"`    // e.g. "Greeter"
+	Package         string           `json:"package"` // Import path of the generated code
+	File            string           `json:"file"`    // Relative to working_dir
+	Line            int              `json:"line"`
+	Methods         []Method         `json:"methods"`
+	Implementations []Implementation `json:"implementations"`
+}
+
+// Method is an RPC of a service
+type Method struct {
+	Name      string `json:"name"`
+	Streaming string `json:"streaming,omitempty"` // "client", "server" or "bidi"; empty for unary RPCs
+}
+
+// Implementation is a type embedding the service's Unimplemented or Unsafe
+// server type, or registered as its server
+type Implementation struct {
+	Type                string   `json:"type"` // Package-qualified
+	File                string   `json:"file"`
+	Line                int      `json:"line"`
+	Implemented         []string `json:"implemented"`
+	Unimplemented       []string `json:"unimplemented"`
+	EmbedsUnimplemented bool     `json:"embeds_unimplemented"` // Unimplemented RPCs fail with codes.Unimplemented instead of breaking the build
+}
+
+// Finding represents a problem in a service implementation
+type Finding struct {
+	Kind       string `json:"kind"` // e.g. "unimplemented-method", "plain-error", "background-context"
+	Service    string `json:"service"`
+	Type       string `json:"type"`
+	Method     string `json:"method,omitempty"`
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Severity   string `json:"severity"` // "low", "medium", "high"
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
+
+// String returns a formatted JSON string of the GRPCReviewResult
+func (r *GRPCReviewResult) String() string {
+	jsonData, _ := json.MarshalIndent(r, "", "  ")
+	return string(jsonData)
+}
@@ -36,6 +36,9 @@ type Check struct {
 	Timestamp time.Time     `json:"timestamp"`
 }
 
+// HealthParams represents the parameters for the health tool, which takes none
+type HealthParams struct{}
+
 // Checker defines the interface for health checks
 type Checker interface {
 	Check() Check
@@ -44,6 +47,7 @@ type Checker interface {
 // HealthChecker performs health checks
 type HealthChecker struct {
 	checkers map[string]Checker
+	metadata map[string]string // Reported with every check, such as the config profile
 	mu       sync.RWMutex
 	version  string
 }
@@ -52,6 +56,7 @@ type HealthChecker struct {
 func New(version string) *HealthChecker {
 	return &HealthChecker{
 		checkers: make(map[string]Checker),
+		metadata: make(map[string]string),
 		version:  version,
 	}
 }
@@ -63,6 +68,13 @@ func (h *HealthChecker) RegisterChecker(name string, checker Checker) {
 	h.checkers[name] = checker
 }
 
+// SetMetadata adds a key to the metadata of every health report
+func (h *HealthChecker) SetMetadata(key, value string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.metadata[key] = value
+}
+
 // Check performs all health checks and returns the overall health
 func (h *HealthChecker) Check() Health {
 	h.mu.RLock()
@@ -70,6 +82,15 @@ func (h *HealthChecker) Check() Health {
 	for name, checker := range h.checkers {
 		checkers[name] = checker
 	}
+	metadata := map[string]string{
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"goroutines": fmt.Sprintf("%d", runtime.NumGoroutine()),
+	}
+	for key, value := range h.metadata {
+		metadata[key] = value
+	}
 	h.mu.RUnlock()
 
 	checks := make(map[string]Check)
@@ -98,12 +119,7 @@ func (h *HealthChecker) Check() Health {
 		Timestamp: time.Now().UTC(),
 		Version:   h.version,
 		Checks:    checks,
-		Metadata: map[string]string{
-			"go_version": runtime.Version(),
-			"os":         runtime.GOOS,
-			"arch":       runtime.GOARCH,
-			"goroutines": fmt.Sprintf("%d", runtime.NumGoroutine()),
-		},
+		Metadata:  metadata,
 	}
 
 	return health
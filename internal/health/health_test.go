@@ -172,6 +172,21 @@ func TestHealthChecker_Check_Metadata(t *testing.T) {
 	}
 }
 
+func TestHealthChecker_SetMetadata(t *testing.T) {
+	hc := New("1.2.3")
+	hc.SetMetadata("profile", "prod")
+
+	health := hc.Check()
+
+	if health.Metadata["profile"] != "prod" {
+		t.Errorf("expected profile 'prod' in metadata, got '%s'", health.Metadata["profile"])
+	}
+
+	if _, ok := health.Metadata["go_version"]; !ok {
+		t.Error("expected go_version in metadata")
+	}
+}
+
 func TestHealthChecker_Check_Timestamp(t *testing.T) {
 	hc := New("1.0.0")
 
@@ -0,0 +1,318 @@
+// Package history keeps the score snapshots of workspace reviews on disk, so
+// later calls can tell whether a codebase is getting better: how its scores
+// moved and which issues appeared or were fixed between runs
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"mcp-go-assistant/internal/cache"
+)
+
+// DefaultLimit is the number of snapshots kept per workspace
+const DefaultLimit = 50
+
+// DefaultRuns is the number of recent runs a trend covers when the request
+// leaves it unset
+const DefaultRuns = 10
+
+// Trend directions
+const (
+	DirectionImproving = "improving"
+	DirectionDeclining = "declining"
+	DirectionSteady    = "steady"
+)
+
+// Issue is an issue found by a review run
+type Issue struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// key identifies the issue across runs; lines shift as code is edited, so
+// they are left out
+func (i Issue) key() string {
+	return i.File + "\x00" + i.Rule + "\x00" + i.Message
+}
+
+// PackageScore is the score of a package in a review run
+type PackageScore struct {
+	Path   string `json:"path"`
+	Score  int    `json:"score"`
+	Issues int    `json:"issues"`
+}
+
+// Snapshot is the outcome of a review run of a workspace
+type Snapshot struct {
+	Time     time.Time      `json:"time"`
+	Score    int            `json:"score"`
+	Files    int            `json:"files"`
+	Packages []PackageScore `json:"packages"`
+	Issues   []Issue        `json:"issues"`
+}
+
+// workspaceFile is the content of the history file of a workspace
+type workspaceFile struct {
+	Root      string     `json:"root"`
+	Snapshots []Snapshot `json:"snapshots"` // Oldest first
+}
+
+// TrendParams represents the parameters for the review-trend tool
+type TrendParams struct {
+	WorkingDir string `json:"working_dir" jsonschema:"description:Workspace directory reviewed earlier by code-review with working_dir"`
+	Runs       int    `json:"runs,omitempty" jsonschema:"description:Number of most recent review runs to compare (default 10)"`
+}
+
+// Run summarizes a review run within a trend
+type Run struct {
+	Time        time.Time `json:"time"`
+	Score       int       `json:"score"`
+	ScoreChange int       `json:"score_change"` // Against the previous run; 0 for the first
+	Files       int       `json:"files"`
+	Issues      int       `json:"issues"`
+	NewIssues   int       `json:"new_issues"`   // Not found by the previous run
+	FixedIssues int       `json:"fixed_issues"` // Found by the previous run but not this one
+}
+
+// PackageTrend is the score history of a package of the latest run
+type PackageTrend struct {
+	Path   string `json:"path"`
+	Scores []int  `json:"scores"` // Oldest first, over the runs that reviewed the package
+	Score  int    `json:"score"`
+	Change int    `json:"change"` // Latest score minus the first one
+}
+
+// Trend describes how the reviews of a workspace changed over recent runs
+type Trend struct {
+	Root        string         `json:"root"`
+	Summary     string         `json:"summary"`
+	Direction   string         `json:"direction"` // improving, declining or steady
+	ScoreChange int            `json:"score_change"`
+	Runs        []Run          `json:"runs"` // Oldest first
+	Packages    []PackageTrend `json:"packages"`
+	NewIssues   []Issue        `json:"new_issues"`   // Found by the latest run but not the one before
+	FixedIssues []Issue        `json:"fixed_issues"` // Found by the run before the latest but not the latest
+}
+
+// String returns a formatted JSON string of the Trend
+func (t *Trend) String() string {
+	jsonData, _ := json.MarshalIndent(t, "", "  ")
+	return string(jsonData)
+}
+
+// Store keeps the snapshots of each workspace in a JSON file of its own
+// under a directory
+type Store struct {
+	dir   string
+	limit int
+	now   func() time.Time
+
+	mu sync.Mutex
+}
+
+// NewStore creates a store under dir keeping the last limit snapshots of
+// each workspace, or DefaultLimit when limit is not positive
+func NewStore(dir string, limit int) *Store {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	return &Store{dir: dir, limit: limit, now: time.Now}
+}
+
+// Record appends a snapshot to the history of the workspace at root,
+// dropping the oldest snapshots beyond the limit. A zero snapshot time is
+// set to the current time.
+func (s *Store) Record(root string, snapshot Snapshot) error {
+	if snapshot.Time.IsZero() {
+		snapshot.Time = s.now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history, err := s.load(root)
+	if err != nil {
+		return err
+	}
+	history.Snapshots = append(history.Snapshots, snapshot)
+	if n := len(history.Snapshots); n > s.limit {
+		history.Snapshots = history.Snapshots[n-s.limit:]
+	}
+	return s.save(history)
+}
+
+// Trend compares the last runs recorded for the workspace at root. It fails
+// when the workspace has no history yet.
+func (s *Store) Trend(root string, runs int) (*Trend, error) {
+	if runs <= 0 {
+		runs = DefaultRuns
+	}
+
+	s.mu.Lock()
+	history, err := s.load(root)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if len(history.Snapshots) == 0 {
+		return nil, fmt.Errorf("no review history for %s; review it with code-review and working_dir first", root)
+	}
+
+	snapshots := history.Snapshots
+	if len(snapshots) > runs {
+		snapshots = snapshots[len(snapshots)-runs:]
+	}
+	return newTrend(root, snapshots), nil
+}
+
+// newTrend builds the trend of snapshots, oldest first
+func newTrend(root string, snapshots []Snapshot) *Trend {
+	trend := &Trend{Root: root, NewIssues: []Issue{}, FixedIssues: []Issue{}}
+	for i, snap := range snapshots {
+		run := Run{Time: snap.Time, Score: snap.Score, Files: snap.Files, Issues: len(snap.Issues)}
+		if i > 0 {
+			prev := snapshots[i-1]
+			added, fixed := diffIssues(prev.Issues, snap.Issues)
+			run.ScoreChange = snap.Score - prev.Score
+			run.NewIssues, run.FixedIssues = len(added), len(fixed)
+			if i == len(snapshots)-1 {
+				trend.NewIssues, trend.FixedIssues = added, fixed
+			}
+		}
+		trend.Runs = append(trend.Runs, run)
+	}
+
+	first, latest := snapshots[0], snapshots[len(snapshots)-1]
+	trend.ScoreChange = latest.Score - first.Score
+	switch {
+	case trend.ScoreChange > 0:
+		trend.Direction = DirectionImproving
+	case trend.ScoreChange < 0:
+		trend.Direction = DirectionDeclining
+	default:
+		trend.Direction = DirectionSteady
+	}
+	trend.Packages = packageTrends(snapshots)
+	trend.Summary = summarize(trend, first, latest)
+	return trend
+}
+
+// packageTrends returns the score histories of the packages of the latest
+// snapshot, in its order
+func packageTrends(snapshots []Snapshot) []PackageTrend {
+	latest := snapshots[len(snapshots)-1]
+	trends := make([]PackageTrend, 0, len(latest.Packages))
+	for _, pkg := range latest.Packages {
+		pt := PackageTrend{Path: pkg.Path, Score: pkg.Score}
+		for _, snap := range snapshots {
+			for _, p := range snap.Packages {
+				if p.Path == pkg.Path {
+					pt.Scores = append(pt.Scores, p.Score)
+					break
+				}
+			}
+		}
+		pt.Change = pt.Score - pt.Scores[0]
+		trends = append(trends, pt)
+	}
+	return trends
+}
+
+// diffIssues returns the issues of current missing from previous and the
+// issues of previous missing from current. Identical issues are matched one
+// to one, so a second copy of an issue counts as new.
+func diffIssues(previous, current []Issue) (added, fixed []Issue) {
+	remaining := make(map[string]int)
+	for _, issue := range previous {
+		remaining[issue.key()]++
+	}
+	added = []Issue{}
+	for _, issue := range current {
+		if remaining[issue.key()] > 0 {
+			remaining[issue.key()]--
+			continue
+		}
+		added = append(added, issue)
+	}
+
+	fixed = []Issue{}
+	for _, issue := range previous {
+		if remaining[issue.key()] > 0 {
+			remaining[issue.key()]--
+			fixed = append(fixed, issue)
+		}
+	}
+	return added, fixed
+}
+
+// summarize describes the trend in one sentence
+func summarize(t *Trend, first, latest Snapshot) string {
+	if len(t.Runs) == 1 {
+		return fmt.Sprintf("One review run so far, scoring %d/100 with %d issues", latest.Score, len(latest.Issues))
+	}
+	return fmt.Sprintf("Score went from %d to %d over %d runs since %s (%s); the latest run found %d new issues and fixed %d",
+		first.Score, latest.Score, len(t.Runs), first.Time.Format(time.DateOnly), t.Direction, len(t.NewIssues), len(t.FixedIssues))
+}
+
+// path returns the history file of the workspace at root, named after a
+// hash of the root so any path maps to a valid file name
+func (s *Store) path(root string) string {
+	return filepath.Join(s.dir, cache.Hash(root)[:16]+".json")
+}
+
+// load reads the history of the workspace at root; a missing file is an
+// empty history
+func (s *Store) load(root string) (*workspaceFile, error) {
+	history := &workspaceFile{Root: root}
+	data, err := os.ReadFile(s.path(root))
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read review history: %w", err)
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("failed to parse review history %s: %w", s.path(root), err)
+	}
+	return history, nil
+}
+
+// save writes the history of a workspace atomically by renaming a
+// temporary file
+func (s *Store) save(history *workspaceFile) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal review history: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create review history directory: %w", err)
+	}
+
+	path := s.path(history.Root)
+	tmp, err := os.CreateTemp(s.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write review history: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write review history: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write review history: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write review history: %w", err)
+	}
+	return nil
+}
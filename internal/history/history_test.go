@@ -0,0 +1,131 @@
+package history
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStore_Trend(t *testing.T) {
+	store := NewStore(t.TempDir(), 3)
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	runs := []Snapshot{
+		{Score: 50, Files: 2, Packages: []PackageScore{{Path: "api", Score: 50, Issues: 3}}},
+		{
+			Score: 70, Files: 2,
+			Packages: []PackageScore{{Path: "api", Score: 60, Issues: 2}},
+			Issues: []Issue{
+				{File: "api/a.go", Line: 3, Rule: "ignored-error", Message: "Error is being ignored"},
+				{File: "api/a.go", Line: 9, Rule: "ignored-error", Message: "Error is being ignored"},
+				{File: "api/b.go", Line: 4, Rule: "camel-case", Message: "Use camelCase"},
+			},
+		},
+		{
+			Score: 80, Files: 3,
+			Packages: []PackageScore{{Path: "api", Score: 80, Issues: 1}, {Path: "store", Score: 90, Issues: 1}},
+			Issues: []Issue{
+				// Moved lines still match the previous run
+				{File: "api/a.go", Line: 5, Rule: "ignored-error", Message: "Error is being ignored"},
+				{File: "store/s.go", Line: 2, Rule: "unused-symbol", Message: "Unused"},
+			},
+		},
+		{
+			Score: 75, Files: 3,
+			Packages: []PackageScore{{Path: "api", Score: 70, Issues: 2}, {Path: "store", Score: 90, Issues: 1}},
+			Issues: []Issue{
+				{File: "api/a.go", Line: 5, Rule: "ignored-error", Message: "Error is being ignored"},
+				{File: "api/a.go", Line: 7, Rule: "ignored-error", Message: "Error is being ignored"},
+				{File: "store/s.go", Line: 2, Rule: "unused-symbol", Message: "Unused"},
+			},
+		},
+	}
+	for i, snap := range runs {
+		snap.Time = start.Add(time.Duration(i) * time.Hour)
+		if err := store.Record("/src/app", snap); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	// The oldest run is dropped beyond the limit of 3
+	trend, err := store.Trend("/src/app", 10)
+	if err != nil {
+		t.Fatalf("Trend() error = %v", err)
+	}
+	wantRuns := []Run{
+		{Time: start.Add(time.Hour), Score: 70, Files: 2, Issues: 3},
+		{Time: start.Add(2 * time.Hour), Score: 80, ScoreChange: 10, Files: 3, Issues: 2, NewIssues: 1, FixedIssues: 2},
+		{Time: start.Add(3 * time.Hour), Score: 75, ScoreChange: -5, Files: 3, Issues: 3, NewIssues: 1},
+	}
+	if !reflect.DeepEqual(trend.Runs, wantRuns) {
+		t.Errorf("runs =\n%+v\nwant\n%+v", trend.Runs, wantRuns)
+	}
+	if trend.Direction != DirectionImproving || trend.ScoreChange != 5 {
+		t.Errorf("direction = %s with change %d, want improving by 5", trend.Direction, trend.ScoreChange)
+	}
+	wantPackages := []PackageTrend{
+		{Path: "api", Scores: []int{60, 80, 70}, Score: 70, Change: 10},
+		{Path: "store", Scores: []int{90, 90}, Score: 90},
+	}
+	if !reflect.DeepEqual(trend.Packages, wantPackages) {
+		t.Errorf("packages = %+v, want %+v", trend.Packages, wantPackages)
+	}
+	wantNew := []Issue{{File: "api/a.go", Line: 7, Rule: "ignored-error", Message: "Error is being ignored"}}
+	if !reflect.DeepEqual(trend.NewIssues, wantNew) || len(trend.FixedIssues) != 0 {
+		t.Errorf("new issues = %+v, fixed = %+v", trend.NewIssues, trend.FixedIssues)
+	}
+	wantSummary := "Score went from 70 to 75 over 3 runs since 2026-03-01 (improving); the latest run found 1 new issues and fixed 0"
+	if trend.Summary != wantSummary {
+		t.Errorf("summary = %q, want %q", trend.Summary, wantSummary)
+	}
+
+	// Fewer runs compare only the latest ones
+	trend, err = store.Trend("/src/app", 2)
+	if err != nil {
+		t.Fatalf("Trend() error = %v", err)
+	}
+	if len(trend.Runs) != 2 || trend.Direction != DirectionDeclining || trend.Runs[0].ScoreChange != 0 {
+		t.Errorf("unexpected trend of 2 runs: %+v", trend)
+	}
+}
+
+func TestStore_TrendErrors(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir, 0)
+	if _, err := store.Trend("/src/app", 0); err == nil {
+		t.Error("expected an error without history")
+	}
+
+	if err := store.Record("/src/app", Snapshot{Score: 90}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	trend, err := store.Trend("/src/app", 0)
+	if err != nil {
+		t.Fatalf("Trend() error = %v", err)
+	}
+	if trend.Runs[0].Time.IsZero() || trend.Direction != DirectionSteady {
+		t.Errorf("unexpected trend of one run: %+v", trend)
+	}
+	if _, err := store.Trend("/src/other", 0); err == nil {
+		t.Error("expected an error for a workspace without history")
+	}
+
+	if err := os.WriteFile(store.path("/src/app"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Trend("/src/app", 0); err == nil {
+		t.Error("expected an error for a corrupt history file")
+	}
+	if err := store.Record("/src/app", Snapshot{}); err == nil {
+		t.Error("expected Record to keep a corrupt history file")
+	}
+}
+
+func TestDiffIssues(t *testing.T) {
+	a := Issue{File: "a.go", Rule: "r", Message: "m"}
+	b := Issue{File: "b.go", Rule: "r", Message: "m"}
+	added, fixed := diffIssues([]Issue{a, a, b}, []Issue{a, b, b})
+	if !reflect.DeepEqual(added, []Issue{b}) || !reflect.DeepEqual(fixed, []Issue{a}) {
+		t.Errorf("added = %+v, fixed = %+v", added, fixed)
+	}
+}
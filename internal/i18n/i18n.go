@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Supported languages
+const (
+	English  = "en"
+	Japanese = "ja"
+	Spanish  = "es"
+
+	// DefaultLanguage is used when no language is requested and as the
+	// fallback for keys missing from another language
+	DefaultLanguage = English
+)
+
+// Catalog holds translated message formats keyed by language and message key
+type Catalog struct {
+	mu       sync.RWMutex
+	messages map[string]map[string]string
+}
+
+// NewCatalog creates an empty message catalog
+func NewCatalog() *Catalog {
+	return &Catalog{
+		messages: make(map[string]map[string]string),
+	}
+}
+
+// Register adds message formats for a language, replacing existing keys
+func (c *Catalog) Register(lang string, messages map[string]string) {
+	lang = Normalize(lang)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.messages[lang] == nil {
+		c.messages[lang] = make(map[string]string, len(messages))
+	}
+	for key, format := range messages {
+		c.messages[lang][key] = format
+	}
+}
+
+// Translate returns the message for key in lang formatted with args.
+// Missing translations fall back to DefaultLanguage, then to the key itself.
+func (c *Catalog) Translate(lang, key string, args ...interface{}) string {
+	lang = Normalize(lang)
+
+	c.mu.RLock()
+	format, ok := c.messages[lang][key]
+	if !ok {
+		format, ok = c.messages[DefaultLanguage][key]
+	}
+	c.mu.RUnlock()
+
+	if !ok {
+		format = key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// HasLanguage reports whether any messages are registered for lang
+func (c *Catalog) HasLanguage(lang string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.messages[Normalize(lang)]
+	return ok
+}
+
+// Languages returns the registered languages in sorted order
+func (c *Catalog) Languages() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	langs := make([]string, 0, len(c.messages))
+	for lang := range c.messages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Normalize converts a language tag such as "ja-JP" or "ES" to its base
+// language code, defaulting to DefaultLanguage when empty
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	if lang == "" {
+		return DefaultLanguage
+	}
+	return lang
+}
+
+// IsSupported reports whether lang is one of the built-in languages
+func IsSupported(lang string) bool {
+	switch Normalize(lang) {
+	case English, Japanese, Spanish:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCatalogTranslate(t *testing.T) {
+	c := NewCatalog()
+	c.Register(English, map[string]string{
+		"greeting": "Hello, %s",
+		"farewell": "Goodbye",
+	})
+	c.Register(Japanese, map[string]string{
+		"greeting": "こんにちは、%s",
+	})
+
+	tests := []struct {
+		name string
+		lang string
+		key  string
+		args []interface{}
+		want string
+	}{
+		{"english with args", English, "greeting", []interface{}{"Gopher"}, "Hello, Gopher"},
+		{"japanese with args", Japanese, "greeting", []interface{}{"Gopher"}, "こんにちは、Gopher"},
+		{"region tag", "ja-JP", "greeting", []interface{}{"Gopher"}, "こんにちは、Gopher"},
+		{"fallback to english", Japanese, "farewell", nil, "Goodbye"},
+		{"empty language", "", "farewell", nil, "Goodbye"},
+		{"unknown key", English, "missing", nil, "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.Translate(tt.lang, tt.key, tt.args...)
+			if got != tt.want {
+				t.Errorf("expected '%s', got '%s'", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCatalogLanguages(t *testing.T) {
+	c := NewCatalog()
+	c.Register(Spanish, map[string]string{"a": "a"})
+	c.Register(English, map[string]string{"a": "a"})
+
+	if !c.HasLanguage("ES") {
+		t.Error("expected catalog to have Spanish")
+	}
+	if c.HasLanguage(Japanese) {
+		t.Error("expected catalog not to have Japanese")
+	}
+
+	want := []string{English, Spanish}
+	if got := c.Languages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestIsSupported(t *testing.T) {
+	for _, lang := range []string{"en", "ja", "es", "en-US", "", "JA"} {
+		if !IsSupported(lang) {
+			t.Errorf("expected '%s' to be supported", lang)
+		}
+	}
+	for _, lang := range []string{"fr", "de"} {
+		if IsSupported(lang) {
+			t.Errorf("expected '%s' to be unsupported", lang)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+// Package implements finds the types of a workspace that implement an
+// interface, and those that nearly do, by type-checking the workspace.
+package implements
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mcp-go-assistant/internal/gocheck"
+	"mcp-go-assistant/internal/golist"
+	"mcp-go-assistant/internal/workspace"
+)
+
+const (
+	// defaultMaxMissing is the number of missing or mismatched methods up
+	// to which a type is a near miss unless the request sets max_missing
+	defaultMaxMissing = 2
+	// maxMatches caps the implementations and near misses listed
+	maxMatches = 100
+	// pastedPath is the import path interface_code is checked as
+	pastedPath = "pasted"
+	// pastedFile is the file name interface_code is checked as
+	pastedFile = "interface_code.go"
+)
+
+// Options controls which directories may be type-checked
+type Options struct {
+	Roots []string // Registered workspace roots; working_dir and the packages must be inside one
+}
+
+// target is the interface types are matched against
+type target struct {
+	name  string // Package-qualified name
+	iface *types.Interface
+}
+
+// Find type-checks the workspace packages matching the package pattern and
+// returns the types implementing the requested interface, with near misses
+// that lack or mismatch at most max_missing of its methods
+func Find(ctx context.Context, params ImplementsParams, opts Options) (*ImplementsResult, error) {
+	if params.WorkingDir == "" {
+		return nil, fmt.Errorf("working_dir parameter is required")
+	}
+	if params.Interface == "" && params.InterfaceCode == "" {
+		return nil, fmt.Errorf("interface or interface_code parameter is required")
+	}
+	if params.MaxMissing < 0 {
+		return nil, fmt.Errorf("max_missing must not be negative: %d", params.MaxMissing)
+	}
+	maxMissing := params.MaxMissing
+	if maxMissing == 0 {
+		maxMissing = defaultMaxMissing
+	}
+
+	dir, err := workspace.Dir("matching implementations", params.WorkingDir, opts.Roots)
+	if err != nil {
+		return nil, err
+	}
+	pattern := params.Package
+	if pattern == "" {
+		pattern = "./..."
+	}
+	if strings.HasPrefix(pattern, "-") {
+		return nil, fmt.Errorf("package must be a package pattern: %s", pattern)
+	}
+
+	listed, err := golist.List(ctx, dir, pattern, golist.Options{})
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []gocheck.Package
+	var local []string
+	for _, lp := range listed {
+		if len(lp.GoFiles) == 0 {
+			continue
+		}
+		if !workspace.Contains(opts.Roots, lp.Dir) {
+			continue
+		}
+		files, err := lp.ReadFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, gocheck.Package{Path: lp.ImportPath, Files: files})
+		local = append(local, lp.ImportPath)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no Go packages in the workspace match %s", pattern)
+	}
+
+	// Interfaces of packages outside the pattern, e.g. io.Reader, are
+	// loaded through an import
+	qualifier, _ := splitName(params.Interface)
+	switch {
+	case params.InterfaceCode != "":
+		pkgs = append(pkgs, gocheck.Package{Path: pastedPath, Files: map[string]string{pastedFile: pastedSource(params.InterfaceCode)}})
+	case qualifier != "" && !listedAs(listed, qualifier):
+		pkgs = append(pkgs, gocheck.Package{Path: pastedPath, Files: map[string]string{
+			pastedFile: fmt.Sprintf("package pasted\n\nimport _ %s\n", strconv.Quote(qualifier)),
+		}})
+	}
+
+	checked := gocheck.CheckIn(ctx, dir, pkgs)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if diags := checked.Errors(pastedFile); len(diags) > 0 {
+		if params.InterfaceCode != "" {
+			return nil, fmt.Errorf("interface_code does not compile: %s", diags[0].Message)
+		}
+		return nil, fmt.Errorf("package %s of interface %s could not be loaded", qualifier, params.Interface)
+	}
+
+	t, err := lookupInterface(checked, local, params)
+	if err != nil {
+		return nil, err
+	}
+	return match(checked, local, t, maxMissing), nil
+}
+
+// match checks the named types of the workspace packages against t
+func match(checked *gocheck.Result, workspace []string, t *target, maxMissing int) *ImplementsResult {
+	result := &ImplementsResult{
+		Interface:       t.name,
+		Methods:         []string{},
+		Implementations: []Match{},
+		NearMisses:      []NearMiss{},
+		Suggestions:     []string{},
+	}
+	for i := 0; i < t.iface.NumMethods(); i++ {
+		result.Methods = append(result.Methods, signature(t.iface.Method(i)))
+	}
+
+	candidates, generic := 0, 0
+	for _, path := range workspace {
+		pkg := checked.Types[path]
+		if pkg == nil {
+			continue
+		}
+		scope := pkg.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || tn.IsAlias() {
+				continue
+			}
+			named, ok := tn.Type().(*types.Named)
+			if !ok || types.IsInterface(named) || named.Underlying() == types.Typ[types.Invalid] {
+				continue
+			}
+			if named.TypeParams().Len() > 0 {
+				// Generic types implement interfaces only once instantiated
+				generic++
+				continue
+			}
+			candidates++
+
+			pos := checked.Fset.Position(tn.Pos())
+			switch {
+			case types.Implements(named, t.iface):
+				result.Implementations = append(result.Implementations, Match{Type: name, Package: path, File: pos.Filename, Line: pos.Line})
+			case types.Implements(types.NewPointer(named), t.iface):
+				result.Implementations = append(result.Implementations, Match{Type: name, Package: path, File: pos.Filename, Line: pos.Line, Pointer: true})
+			default:
+				if miss, ok := nearMiss(named, t.iface, maxMissing); ok {
+					miss.Type, miss.Package, miss.File, miss.Line = name, path, pos.Filename, pos.Line
+					result.NearMisses = append(result.NearMisses, miss)
+				}
+			}
+		}
+	}
+
+	// Closest near misses first
+	sort.SliceStable(result.NearMisses, func(i, j int) bool {
+		a, b := result.NearMisses[i], result.NearMisses[j]
+		return len(a.Missing)+len(a.Mismatched) < len(b.Missing)+len(b.Mismatched)
+	})
+
+	addSuggestions(result, checked, t, generic)
+	implementations, nearMisses := len(result.Implementations), len(result.NearMisses)
+	if implementations > maxMatches {
+		result.Implementations = result.Implementations[:maxMatches]
+		result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+			"Showing %d of %d implementations; narrow package to see the others.", maxMatches, implementations))
+	}
+	if nearMisses > maxMatches {
+		result.NearMisses = result.NearMisses[:maxMatches]
+		result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+			"Showing %d of %d near misses; lower max_missing or narrow package to see the closest ones.", maxMatches, nearMisses))
+	}
+	result.Summary = fmt.Sprintf("%d of %d types in %d packages implement %s, %d near misses",
+		implementations, candidates, len(workspace), t.name, nearMisses)
+	return result
+}
+
+// nearMiss reports the methods of iface that named lacks or declares with
+// another signature, if it has at least one of them and differs in at most
+// maxMissing. Methods of the pointer type count, as callers can take the
+// address of a value to satisfy the interface.
+func nearMiss(named *types.Named, iface *types.Interface, maxMissing int) (NearMiss, bool) {
+	var miss NearMiss
+	ptr := types.NewPointer(named)
+	matched := 0
+	for i := 0; i < iface.NumMethods(); i++ {
+		want := iface.Method(i)
+		obj, _, _ := types.LookupFieldOrMethod(ptr, false, want.Pkg(), want.Name())
+		got, ok := obj.(*types.Func)
+		switch {
+		case !ok:
+			miss.Missing = append(miss.Missing, signature(want))
+		case types.Identical(got.Type(), want.Type()):
+			matched++
+		default:
+			miss.Mismatched = append(miss.Mismatched, Mismatch{Method: want.Name(), Want: signature(want), Got: signature(got)})
+		}
+	}
+	differences := len(miss.Missing) + len(miss.Mismatched)
+	return miss, matched > 0 && differences > 0 && differences <= maxMissing
+}
+
+// addSuggestions explains the matches and what limited them
+func addSuggestions(result *ImplementsResult, checked *gocheck.Result, t *target, generic int) {
+	add := func(format string, args ...any) {
+		result.Suggestions = append(result.Suggestions, fmt.Sprintf(format, args...))
+	}
+
+	if t.iface.NumMethods() == 0 {
+		add("%s has no methods, so every type implements it.", t.name)
+	}
+	for _, m := range result.Implementations {
+		if m.Pointer {
+			add("Types marked pointer implement %s through pointer receivers only; use *T, not T, where the interface is expected.", t.name)
+			break
+		}
+	}
+	if len(result.NearMisses) > 0 {
+		add("Near misses list the methods to add or fix for a type to implement %s.", t.name)
+	}
+	if len(result.Implementations) == 0 && len(result.NearMisses) == 0 {
+		add("No type implements %s or has any of its methods; check the interface name or widen package.", t.name)
+	}
+	if generic > 0 {
+		add("%d generic types were not matched; they implement interfaces only once instantiated.", generic)
+	}
+	if n := len(checked.Diagnostics); n > 0 {
+		d := checked.Diagnostics[0]
+		add("The workspace has %d type errors, e.g. %s; types of packages with errors may be missing or incomplete.", n, d.String())
+	}
+	if len(checked.Unresolved) > 0 {
+		add("Imports could not be resolved without downloading modules: %s; run go mod download in working_dir.", strings.Join(checked.Unresolved, ", "))
+	}
+}
+
+// lookupInterface finds the interface the request names among the checked
+// packages, or the one declared in interface_code
+func lookupInterface(checked *gocheck.Result, workspace []string, params ImplementsParams) (*target, error) {
+	qualifier, name := splitName(params.Interface)
+
+	var found []*types.TypeName
+	if params.InterfaceCode != "" {
+		scope := checked.Types[pastedPath].Scope()
+		for _, n := range scope.Names() {
+			if tn, ok := scope.Lookup(n).(*types.TypeName); ok && types.IsInterface(tn.Type()) && (name == "" || n == name) {
+				found = append(found, tn)
+			}
+		}
+		switch {
+		case len(found) == 0 && name != "":
+			return nil, fmt.Errorf("interface_code does not declare the interface %s", name)
+		case len(found) == 0:
+			return nil, fmt.Errorf("interface_code declares no interface")
+		case len(found) > 1:
+			return nil, fmt.Errorf("interface_code declares several interfaces (%s); choose one with interface", typeNames(found))
+		}
+	} else {
+		for _, pkg := range searched(checked, workspace, qualifier) {
+			if tn, ok := pkg.Scope().Lookup(name).(*types.TypeName); ok && types.IsInterface(tn.Type()) {
+				found = append(found, tn)
+			}
+		}
+		switch {
+		case len(found) == 0:
+			return nil, fmt.Errorf("interface %s not found in the workspace or its imports", params.Interface)
+		case len(found) > 1:
+			return nil, fmt.Errorf("interface %s is ambiguous (%s); qualify it with the package path", params.Interface, typeNames(found))
+		}
+	}
+
+	tn := found[0]
+	if named, ok := tn.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+		return nil, fmt.Errorf("interface %s is generic; paste an interface embedding an instantiation of it as interface_code", tn.Name())
+	}
+	iface := tn.Type().Underlying().(*types.Interface)
+	if !iface.IsMethodSet() {
+		return nil, fmt.Errorf("interface %s is a type constraint, not a method set", tn.Name())
+	}
+
+	t := &target{name: tn.Name(), iface: iface}
+	if tn.Pkg().Path() != pastedPath {
+		t.name = tn.Pkg().Path() + "." + tn.Name()
+	}
+	return t, nil
+}
+
+// searched returns the packages an interface with the given qualifier may
+// be declared in: the workspace packages when it is unqualified, otherwise
+// the checked packages and their imports with that path, or else that name
+func searched(checked *gocheck.Result, workspace []string, qualifier string) []*types.Package {
+	if qualifier == "" {
+		var pkgs []*types.Package
+		for _, path := range workspace {
+			if pkg := checked.Types[path]; pkg != nil {
+				pkgs = append(pkgs, pkg)
+			}
+		}
+		return pkgs
+	}
+
+	seen := make(map[*types.Package]bool)
+	var all []*types.Package
+	var visit func(pkg *types.Package)
+	visit = func(pkg *types.Package) {
+		if pkg == nil || seen[pkg] {
+			return
+		}
+		seen[pkg] = true
+		all = append(all, pkg)
+		for _, imp := range pkg.Imports() {
+			visit(imp)
+		}
+	}
+	paths := make([]string, 0, len(checked.Types))
+	for path := range checked.Types {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		visit(checked.Types[path])
+	}
+
+	var byPath, byName []*types.Package
+	for _, pkg := range all {
+		switch {
+		case pkg.Path() == pastedPath:
+		case pkg.Path() == qualifier:
+			byPath = append(byPath, pkg)
+		case pkg.Name() == qualifier:
+			byName = append(byName, pkg)
+		}
+	}
+	if len(byPath) > 0 {
+		return byPath
+	}
+	return byName
+}
+
+// signature formats a method as declared in an interface, e.g.
+// "Get(key string) (string, error)"
+func signature(fn *types.Func) string {
+	sig := types.TypeString(fn.Type(), func(p *types.Package) string { return p.Name() })
+	return fn.Name() + strings.TrimPrefix(sig, "func")
+}
+
+// typeNames formats the package-qualified names of types
+func typeNames(tns []*types.TypeName) string {
+	names := make([]string, len(tns))
+	for i, tn := range tns {
+		names[i] = tn.Pkg().Path() + "." + tn.Name()
+	}
+	return strings.Join(names, ", ")
+}
+
+// splitName splits an interface name such as "example.com/app/store.Store"
+// into the package qualifier and the type name
+func splitName(name string) (qualifier, typeName string) {
+	i := strings.LastIndex(name, ".")
+	if i < 0 || i < strings.LastIndex(name, "/") {
+		return "", name
+	}
+	return name[:i], name[i+1:]
+}
+
+// listedAs reports whether a listed package has the import path or name
+// qualifier
+func listedAs(listed []golist.Package, qualifier string) bool {
+	for _, lp := range listed {
+		if lp.ImportPath == qualifier || lp.Name == qualifier {
+			return true
+		}
+	}
+	return false
+}
+
+// pastedSource returns interface_code as a file, adding a package clause
+// when the code is only declarations
+func pastedSource(code string) string {
+	if _, err := parser.ParseFile(token.NewFileSet(), "", code, parser.PackageClauseOnly); err == nil {
+		return code
+	}
+	return "package pasted\n\n" + code
+}
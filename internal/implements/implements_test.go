@@ -0,0 +1,211 @@
+package implements
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+// writeModule creates a module with a store package declaring an interface
+// and a cache package implementing it
+func writeModule(t *testing.T) string {
+	t.Helper()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.23\n",
+		"store/store.go": `package store
+
+import "io"
+
+type Store interface {
+	Get(key string) (string, error)
+	Set(key, value string) error
+}
+
+type MemStore struct{ m map[string]string }
+
+func (s *MemStore) Get(key string) (string, error) { return s.m[key], nil }
+func (s *MemStore) Set(key, value string) error  { s.m[key] = value; return nil }
+
+type ReadOnly struct{}
+
+func (ReadOnly) Get(key string) (string, error) { return "", nil }
+
+type Broken struct{}
+
+func (Broken) Get(key string) string        { return "" }
+func (Broken) Set(key, value string) error { return nil }
+
+type File struct{}
+
+func (File) Read(p []byte) (int, error) { return 0, io.EOF }
+func (File) String() string             { return "file" }
+
+type List[T any] struct{ items []T }
+`,
+		"cache/cache.go": `package cache
+
+import "example.com/app/store"
+
+type Cache struct{}
+
+func (Cache) Get(key string) (string, error) { return "", nil }
+func (Cache) Set(key, value string) error  { return nil }
+
+var _ store.Store = Cache{}
+`,
+	}
+	return testutil.WriteModule(t, files)
+}
+
+// matchNames formats matches as "package.Type", with a * for pointer matches
+func matchNames(matches []Match) []string {
+	var names []string
+	for _, m := range matches {
+		name := m.Package + "." + m.Type
+		if m.Pointer {
+			name = "*" + name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestFind(t *testing.T) {
+	dir := writeModule(t)
+	opts := Options{Roots: []string{dir}}
+
+	result, err := Find(context.Background(), ImplementsParams{WorkingDir: dir, Interface: "store.Store"}, opts)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	if result.Interface != "example.com/app/store.Store" {
+		t.Errorf("unexpected interface %q", result.Interface)
+	}
+	wantMethods := []string{"Get(key string) (string, error)", "Set(key string, value string) error"}
+	if strings.Join(result.Methods, "|") != strings.Join(wantMethods, "|") {
+		t.Errorf("expected methods %v, got %v", wantMethods, result.Methods)
+	}
+
+	got := strings.Join(matchNames(result.Implementations), ", ")
+	if want := "example.com/app/cache.Cache, *example.com/app/store.MemStore"; got != want {
+		t.Errorf("expected implementations %s, got %s", want, got)
+	}
+	if m := result.Implementations[1]; m.File != "store/store.go" || m.Line != 10 {
+		t.Errorf("unexpected position %+v", m)
+	}
+
+	if len(result.NearMisses) != 2 {
+		t.Fatalf("expected 2 near misses, got %+v", result.NearMisses)
+	}
+	for _, miss := range result.NearMisses {
+		switch miss.Type {
+		case "Broken":
+			if len(miss.Missing) != 0 || len(miss.Mismatched) != 1 || miss.Mismatched[0].Got != "Get(key string) string" {
+				t.Errorf("unexpected Broken near miss %+v", miss)
+			}
+		case "ReadOnly":
+			if len(miss.Mismatched) != 0 || len(miss.Missing) != 1 || miss.Missing[0] != "Set(key string, value string) error" {
+				t.Errorf("unexpected ReadOnly near miss %+v", miss)
+			}
+		default:
+			t.Errorf("unexpected near miss %+v", miss)
+		}
+	}
+
+	if !strings.HasPrefix(result.Summary, "2 of 5 types in 2 packages implement") {
+		t.Errorf("unexpected summary %q", result.Summary)
+	}
+	var pointer, generic bool
+	for _, s := range result.Suggestions {
+		pointer = pointer || strings.Contains(s, "pointer receivers")
+		generic = generic || strings.HasPrefix(s, "1 generic types")
+	}
+	if !pointer || !generic {
+		t.Errorf("expected pointer and generic suggestions, got %v", result.Suggestions)
+	}
+}
+
+func TestFind_Interfaces(t *testing.T) {
+	dir := writeModule(t)
+	opts := Options{Roots: []string{dir}}
+
+	tests := []struct {
+		name   string
+		params ImplementsParams
+		want   string
+	}{
+		{"unqualified", ImplementsParams{Interface: "Store", Package: "./store"}, "*example.com/app/store.MemStore"},
+		{"imported", ImplementsParams{Interface: "io.Reader"}, "example.com/app/store.File"},
+		{"not imported", ImplementsParams{Interface: "fmt.Stringer"}, "example.com/app/store.File"},
+		{"pasted", ImplementsParams{InterfaceCode: "type Getter interface { Get(key string) (string, error) }"},
+			"example.com/app/cache.Cache, *example.com/app/store.MemStore, example.com/app/store.ReadOnly"},
+		{"pasted with imports", ImplementsParams{Interface: "Named", InterfaceCode: "package x\n\nimport \"fmt\"\n\ntype Named interface { fmt.Stringer }\n\ntype Other interface{ Other() }\n"},
+			"example.com/app/store.File"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.WorkingDir = dir
+			result, err := Find(context.Background(), tt.params, opts)
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+			if got := strings.Join(matchNames(result.Implementations), ", "); got != tt.want {
+				t.Errorf("expected implementations %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFind_Errors(t *testing.T) {
+	dir := writeModule(t)
+	roots := Options{Roots: []string{dir}}
+
+	tests := []struct {
+		name    string
+		params  ImplementsParams
+		opts    Options
+		wantErr string
+	}{
+		{"no working dir", ImplementsParams{Interface: "Store"}, roots, "working_dir parameter is required"},
+		{"no interface", ImplementsParams{WorkingDir: dir}, roots, "interface or interface_code parameter is required"},
+		{"no roots", ImplementsParams{WorkingDir: dir, Interface: "Store"}, Options{}, "requires at least one registered workspace root"},
+		{"outside roots", ImplementsParams{WorkingDir: dir, Interface: "Store"}, Options{Roots: []string{t.TempDir()}}, "not inside a registered workspace"},
+		{"flag", ImplementsParams{WorkingDir: dir, Interface: "Store", Package: "-toolexec=x"}, roots, "must be a package pattern"},
+		{"not found", ImplementsParams{WorkingDir: dir, Interface: "Missing"}, roots, "interface Missing not found"},
+		{"not an interface", ImplementsParams{WorkingDir: dir, Interface: "MemStore"}, roots, "interface MemStore not found"},
+		{"missing package", ImplementsParams{WorkingDir: dir, Interface: "example.invalid/missing.Store"}, roots, "could not be loaded"},
+		{"pasted without interface", ImplementsParams{WorkingDir: dir, InterfaceCode: "type T struct{}"}, roots, "declares no interface"},
+		{"pasted several", ImplementsParams{WorkingDir: dir, InterfaceCode: "type A interface{ A() }\ntype B interface{ B() }"}, roots, "several interfaces (pasted.A, pasted.B)"},
+		{"pasted invalid", ImplementsParams{WorkingDir: dir, InterfaceCode: "type A interface{ A() Missing }"}, roots, "interface_code does not compile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Find(context.Background(), tt.params, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSplitName(t *testing.T) {
+	tests := []struct {
+		name, qualifier, typeName string
+	}{
+		{"Store", "", "Store"},
+		{"io.Reader", "io", "Reader"},
+		{"example.com/app/store.Store", "example.com/app/store", "Store"},
+		{"gopkg.in/yaml.v3.Marshaler", "gopkg.in/yaml.v3", "Marshaler"},
+	}
+	for _, tt := range tests {
+		qualifier, typeName := splitName(tt.name)
+		if qualifier != tt.qualifier || typeName != tt.typeName {
+			t.Errorf("splitName(%q) = %q, %q, want %q, %q", tt.name, qualifier, typeName, tt.qualifier, tt.typeName)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package implements
+
+import (
+	"encoding/json"
+)
+
+// ImplementsParams represents the parameters for the implementations tool
+type ImplementsParams struct {
+	WorkingDir     string `json:"working_dir" jsonschema:"description:Module or package directory inside a registered workspace root"`
+	Interface      string `json:"interface,omitempty" jsonschema:"description:Interface to match, e.g. 'Store', 'store.Store', 'io.Reader' or 'example.com/app/store.Store'; selects the interface in interface_code when both are given"`
+	InterfaceCode  string `json:"interface_code,omitempty" jsonschema:"description:Optional pasted interface declaration to match instead of one declared in the workspace, e.g. 'type Getter interface { Get(key string) (string, error) }'"`
+	Package        string `json:"package,omitempty" jsonschema:"description:Optional package pattern relative to working_dir whose types are matched (defaults to './...')"`
+	MaxMissing     int    `json:"max_missing,omitempty" jsonschema:"description:Optional number of missing or mismatched methods up to which a type is reported as a near miss (defaults to 2)"`
+	IdempotencyKey string `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// ImplementsResult lists the workspace types implementing an interface
+type ImplementsResult struct {
+	Summary         string     `json:"summary"`
+	Interface       string     `json:"interface"` // Package-qualified, e.g. "io.Reader"
+	Methods         []string   `json:"methods"`
+	Implementations []Match    `json:"implementations"`
+	NearMisses      []NearMiss `json:"near_misses"`
+	Suggestions     []string   `json:"suggestions"`
+}
+
+// Match is a type implementing the interface
+type Match struct {
+	Type    string `json:"type"`
+	Package string `json:"package"`
+	File    string `json:"file"` // Relative to working_dir
+	Line    int    `json:"line"`
+	Pointer bool   `json:"pointer,omitempty"` // Only the pointer type implements the interface
+}
+
+// NearMiss is a type with some of the interface's methods
+type NearMiss struct {
+	Type       string     `json:"type"`
+	Package    string     `json:"package"`
+	File       string     `json:"file"` // Relative to working_dir
+	Line       int        `json:"line"`
+	Missing    []string   `json:"missing,omitempty"` // Interface methods the type does not declare
+	Mismatched []Mismatch `json:"mismatched,omitempty"`
+}
+
+// Mismatch is a method declared with a different signature than the
+// interface requires
+type Mismatch struct {
+	Method string `json:"method"`
+	Want   string `json:"want"`
+	Got    string `json:"got"`
+}
+
+// String returns a formatted JSON string of the ImplementsResult
+func (r *ImplementsResult) String() string {
+	jsonData, _ := json.MarshalIndent(r, "", "  ")
+	return string(jsonData)
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/google/uuid"
+
+	"mcp-go-assistant/internal/types"
+)
+
+// RequestIDEnv is the environment variable carrying the request ID into
+// subprocesses run for a request
+const RequestIDEnv = "MCP_REQUEST_ID"
+
+// requestIDKey is the context key of the request ID
+type requestIDKey struct{}
+
+// NewRequestID generates a request ID
+func NewRequestID() string {
+	return uuid.New().String()
+}
+
+// ContextWithRequestID returns a context carrying the ID of the request it
+// serves, so work done for the request can be correlated with its logs
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or "" outside
+// of a request
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// WithContext returns a logger with the request ID of ctx, or l itself
+// outside of a request
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		return l.WithRequestID(requestID)
+	}
+	return l
+}
+
+// CommandEnv returns env with the request ID of ctx added as RequestIDEnv,
+// for the environment of a subprocess run for the request. A nil env stands
+// for the environment of the current process, as in exec.Cmd.
+func CommandEnv(ctx context.Context, env []string) []string {
+	requestID := RequestIDFromContext(ctx)
+	if requestID == "" {
+		return env
+	}
+	if env == nil {
+		env = os.Environ()
+	}
+	return append(env[:len(env):len(env)], RequestIDEnv+"="+requestID)
+}
+
+// ErrorWithRequestID records the request ID of ctx on err, in the details of
+// an MCPError or on the KindError of its chain, so errors raised by the
+// tools carry the ID of the request they failed. err is returned unchanged
+// outside of a request.
+func ErrorWithRequestID(ctx context.Context, err error) error {
+	requestID := RequestIDFromContext(ctx)
+	if err == nil || requestID == "" {
+		return err
+	}
+	var mcpErr types.MCPError
+	if errors.As(err, &mcpErr) {
+		types.AddDetail(mcpErr, "request_id", requestID)
+	}
+	var kindErr *types.KindError
+	if errors.As(err, &kindErr) && kindErr.RequestID == "" {
+		kindErr.RequestID = requestID
+	}
+	return err
+}
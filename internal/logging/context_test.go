@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"mcp-go-assistant/internal/types"
+)
+
+func TestRequestIDContext(t *testing.T) {
+	ctx := context.Background()
+	if id := RequestIDFromContext(ctx); id != "" {
+		t.Errorf("expected no request ID outside of a request, got %q", id)
+	}
+
+	id := NewRequestID()
+	if id == "" || id == NewRequestID() {
+		t.Fatalf("expected unique request IDs, got %q", id)
+	}
+	ctx = ContextWithRequestID(ctx, id)
+	if got := RequestIDFromContext(ctx); got != id {
+		t.Errorf("RequestIDFromContext() = %q, want %q", got, id)
+	}
+}
+
+func TestCommandEnv(t *testing.T) {
+	env := []string{"GOPROXY=off"}
+	if got := CommandEnv(context.Background(), env); !slices.Equal(got, env) {
+		t.Errorf("expected env unchanged outside of a request, got %v", got)
+	}
+	if got := CommandEnv(context.Background(), nil); got != nil {
+		t.Errorf("expected nil env to inherit the process environment, got %v", got)
+	}
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	got := CommandEnv(ctx, env)
+	if !slices.Equal(got, []string{"GOPROXY=off", "MCP_REQUEST_ID=req-1"}) {
+		t.Errorf("CommandEnv() = %v", got)
+	}
+	if len(env) != 1 {
+		t.Errorf("expected the given env to be left alone, got %v", env)
+	}
+
+	got = CommandEnv(ctx, nil)
+	if len(got) == 0 || got[len(got)-1] != "MCP_REQUEST_ID=req-1" {
+		t.Errorf("expected the process environment with the request ID, got %d entries", len(got))
+	}
+}
+
+func TestErrorWithRequestID(t *testing.T) {
+	plain := errors.New("go list failed")
+	if got := ErrorWithRequestID(context.Background(), plain); got != plain {
+		t.Errorf("expected the error unchanged outside of a request, got %v", got)
+	}
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	mcpErr := ErrorWithRequestID(ctx, types.NewForbiddenError("rejected")).(types.MCPError)
+	if got := mcpErr.Details()["request_id"]; got != "req-1" {
+		t.Errorf("request_id detail = %v, want req-1", got)
+	}
+
+	kindErr := fmt.Errorf("lookup: %w", types.NewKindError(types.KindPackageNotFound, "go doc failed"))
+	var kerr *types.KindError
+	if !errors.As(ErrorWithRequestID(ctx, kindErr), &kerr) || kerr.RequestID != "req-1" {
+		t.Errorf("expected the kind error to carry the request ID, got %+v", kerr)
+	}
+	if kindErr.Error() != "lookup: go doc failed" {
+		t.Errorf("expected the message unchanged, got %q", kindErr.Error())
+	}
+}
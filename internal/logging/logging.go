@@ -7,7 +7,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 
 	"mcp-go-assistant/internal/types"
@@ -96,10 +95,7 @@ func (l *Logger) WithRequestID(requestID string) *Logger {
 
 // WithNewRequestID generates and adds a new request ID
 func (l *Logger) WithNewRequestID() *Logger {
-	requestID := uuid.New().String()
-	return &Logger{
-		logger: l.logger.With().Str("request_id", requestID).Logger(),
-	}
+	return l.WithRequestID(NewRequestID())
 }
 
 // WithField returns a logger with an additional field
@@ -279,6 +275,30 @@ func (l *Logger) LogValidationError(field, rule, value, tool string) {
 		Msg("validation failed")
 }
 
+// LogValidationWarning logs a value a rule reported without rejecting it
+func (l *Logger) LogValidationWarning(field, rule, message, tool string) {
+	l.logger.Warn().
+		Str("field", field).
+		Str("rule", rule).
+		Str("tool", tool).
+		Msg(message)
+}
+
+// LogWriteAttempt writes the audit record of a filesystem write a tool
+// asked to perform; reason is empty when the write was allowed
+func (l *Logger) LogWriteAttempt(tool, path, reason string) {
+	event := l.logger.Info()
+	if reason != "" {
+		event = l.logger.Warn().Str("reason", reason)
+	}
+	event.
+		Bool("audit", true).
+		Str("tool", tool).
+		Str("path", path).
+		Bool("allowed", reason == "").
+		Msg("write attempt")
+}
+
 // LogMCPError logs an MCPError with structured fields
 func (l *Logger) LogMCPError(err error, msg string) {
 	if err == nil {
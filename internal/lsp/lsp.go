@@ -0,0 +1,387 @@
+// Package lsp serves review diagnostics to editors over a minimal Language
+// Server Protocol: it reviews Go documents when they are opened or saved
+// and publishes their issues as diagnostics, so editors surface the rules
+// of the code-review tool as you work
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"mcp-go-assistant/internal/codereview"
+	"mcp-go-assistant/internal/logging"
+)
+
+// Source names the server in published diagnostics
+const Source = "mcp-go-assistant"
+
+// ReviewFunc reviews the Go code of a document
+type ReviewFunc func(ctx context.Context, params codereview.CodeReviewParams) (*codereview.ReviewResult, error)
+
+// Options configures a server
+type Options struct {
+	Review  ReviewFunc
+	Logger  *logging.Logger
+	Version string // Reported to the editor on initialization
+}
+
+// Server is a language server publishing review diagnostics for the Go
+// documents an editor opens
+type Server struct {
+	opts Options
+
+	writeMu sync.Mutex
+	out     io.Writer
+
+	mu          sync.Mutex
+	initialized bool
+	shutdown    bool
+	documents   map[string]*document
+	reviews     sync.WaitGroup
+}
+
+// document is an open document and the review running on it
+type document struct {
+	version int
+	text    string
+	cancel  context.CancelFunc // Cancels the running review; nil when none runs
+}
+
+// New returns a server for opts
+func New(opts Options) (*Server, error) {
+	if opts.Review == nil {
+		return nil, fmt.Errorf("review function is required")
+	}
+	if opts.Logger == nil {
+		return nil, fmt.Errorf("logger is required")
+	}
+	return &Server{opts: opts, documents: map[string]*document{}}, nil
+}
+
+// Serve reads messages from in and writes responses and diagnostics to out
+// until the editor sends exit, in is closed or ctx is done. It returns nil
+// after an exit that followed shutdown.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		s.reviews.Wait()
+	}()
+
+	messages := make(chan *message)
+	errs := make(chan error, 1)
+	go func() {
+		r := bufio.NewReader(in)
+		for {
+			msg, err := readMessage(r)
+			var parseErr *responseError
+			if errors.As(err, &parseErr) {
+				s.opts.Logger.WarnEvent().Err(err).Msg("ignoring malformed LSP message")
+				continue
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case messages <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case msg := <-messages:
+			if msg.Method == "exit" {
+				s.mu.Lock()
+				shutdown := s.shutdown
+				s.mu.Unlock()
+				if !shutdown {
+					return fmt.Errorf("exit before shutdown")
+				}
+				return nil
+			}
+			s.handle(ctx, msg)
+		}
+	}
+}
+
+// handle dispatches a request or notification
+func (s *Server) handle(ctx context.Context, msg *message) {
+	if msg.Method == "" {
+		// Responses to requests the server never sends
+		return
+	}
+
+	s.mu.Lock()
+	initialized := s.initialized
+	s.mu.Unlock()
+	if !initialized && msg.Method != "initialize" {
+		if msg.ID != nil {
+			s.respondError(msg.ID, codeNotInitialized, "server not initialized")
+		}
+		return
+	}
+
+	var err error
+	switch msg.Method {
+	case "initialize":
+		s.mu.Lock()
+		s.initialized = true
+		s.mu.Unlock()
+		s.respond(msg.ID, initializeResult{
+			Capabilities: serverCapabilities{TextDocumentSync: textDocumentSyncOptions{
+				OpenClose: true,
+				Change:    textDocumentSyncFull,
+				Save:      saveOptions{IncludeText: true},
+			}},
+			ServerInfo: serverInfo{Name: Source, Version: s.opts.Version},
+		})
+	case "shutdown":
+		s.mu.Lock()
+		s.shutdown = true
+		s.mu.Unlock()
+		s.respond(msg.ID, nil)
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			s.open(ctx, params)
+		}
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			s.change(params)
+		}
+	case "textDocument/didSave":
+		var params didSaveParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			s.save(ctx, params)
+		}
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			s.close(params)
+		}
+	default:
+		// Notifications the server does not handle, such as initialized
+		// and $/cancelRequest, need no answer
+		if msg.ID != nil {
+			s.respondError(msg.ID, codeMethodNotFound, "method not found: "+msg.Method)
+		}
+	}
+	if err != nil {
+		s.opts.Logger.WarnEvent().Err(err).Str("method", msg.Method).Msg("invalid LSP params")
+		if msg.ID != nil {
+			s.respondError(msg.ID, codeInvalidParams, err.Error())
+		}
+	}
+}
+
+// open tracks a document the editor opened and reviews it when it is Go
+func (s *Server) open(ctx context.Context, params didOpenParams) {
+	td := params.TextDocument
+	if td.LanguageID != "go" && !strings.HasSuffix(td.URI, ".go") {
+		return
+	}
+	s.mu.Lock()
+	s.documents[td.URI] = &document{version: td.Version, text: td.Text}
+	s.mu.Unlock()
+	s.review(ctx, td.URI)
+}
+
+// change keeps the latest text of a document for its next review
+func (s *Server) change(params didChangeParams) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok || len(params.ContentChanges) == 0 {
+		return
+	}
+	doc.version = params.TextDocument.Version
+	doc.text = params.ContentChanges[len(params.ContentChanges)-1].Text
+}
+
+// save reviews a document again after the editor saved it
+func (s *Server) save(ctx context.Context, params didSaveParams) {
+	s.mu.Lock()
+	doc, ok := s.documents[params.TextDocument.URI]
+	if ok && params.Text != nil {
+		doc.text = *params.Text
+	}
+	s.mu.Unlock()
+	if ok {
+		s.review(ctx, params.TextDocument.URI)
+	}
+}
+
+// close stops tracking a document and clears its diagnostics
+func (s *Server) close(params didCloseParams) {
+	uri := params.TextDocument.URI
+	s.mu.Lock()
+	doc, ok := s.documents[uri]
+	if ok && doc.cancel != nil {
+		doc.cancel()
+	}
+	delete(s.documents, uri)
+	s.mu.Unlock()
+	if ok {
+		s.publish(uri, nil, []Diagnostic{})
+	}
+}
+
+// review reviews the current text of the document at uri in the
+// background, replacing a review still running on an older text, and
+// publishes the diagnostics of the reviewed version
+func (s *Server) review(ctx context.Context, uri string) {
+	s.mu.Lock()
+	doc, ok := s.documents[uri]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	if doc.cancel != nil {
+		doc.cancel()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	doc.cancel = cancel
+	version, text := doc.version, doc.text
+	s.mu.Unlock()
+
+	s.reviews.Add(1)
+	go func() {
+		defer s.reviews.Done()
+		defer cancel()
+
+		result, err := s.opts.Review(ctx, codereview.CodeReviewParams{GoCode: text})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			// Code that does not parse yet keeps its last diagnostics
+			s.opts.Logger.WarnEvent().Err(err).Str("uri", uri).Msg("failed to review document")
+			return
+		}
+
+		// A newer review or closing the document cancels ctx under the
+		// lock, so holding it keeps their diagnostics from being replaced
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		s.publish(uri, &version, Diagnostics(result.Issues, text))
+	}()
+}
+
+// publish sends the diagnostics of a document version to the editor
+func (s *Server) publish(uri string, version *int, diagnostics []Diagnostic) {
+	params, err := json.Marshal(publishDiagnosticsParams{URI: uri, Version: version, Diagnostics: diagnostics})
+	if err != nil {
+		s.opts.Logger.ErrorEvent().Err(err).Msg("failed to encode diagnostics")
+		return
+	}
+	s.write(&message{Method: "textDocument/publishDiagnostics", Params: params})
+}
+
+// respond answers a request with its result
+func (s *Server) respond(id *json.RawMessage, result any) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		s.respondError(id, codeInvalidRequest, err.Error())
+		return
+	}
+	s.write(&message{ID: id, Result: data})
+}
+
+// respondError answers a request with an error
+func (s *Server) respondError(id *json.RawMessage, code int, text string) {
+	s.write(&message{ID: id, Error: &responseError{Code: code, Message: text}})
+}
+
+// write sends a message to the editor, one message at a time
+func (s *Server) write(msg *message) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := writeMessage(s.out, msg); err != nil {
+		s.opts.Logger.ErrorEvent().Err(err).Msg("failed to write LSP message")
+	}
+}
+
+// Diagnostics converts review issues of text to diagnostics. Issues span
+// from their column, a byte offset, to the end of their last line; issues
+// without a line mark the start of the document.
+func Diagnostics(issues []codereview.Issue, text string) []Diagnostic {
+	lines := strings.Split(text, "\n")
+	diagnostics := make([]Diagnostic, 0, len(issues))
+	for _, issue := range issues {
+		start := Position{}
+		if issue.Line > 0 && issue.Line <= len(lines) {
+			line := strings.TrimSuffix(lines[issue.Line-1], "\r")
+			column := min(max(issue.Column-1, 0), len(line))
+			start = Position{Line: issue.Line - 1, Character: utf16Len(line[:column])}
+		}
+		endLine := max(issue.EndLine, issue.Line) - 1
+		end := start
+		if endLine >= 0 && endLine < len(lines) {
+			end = Position{Line: endLine, Character: utf16Len(strings.TrimSuffix(lines[endLine], "\r"))}
+		}
+		if end.Line == start.Line && end.Character < start.Character {
+			end.Character = start.Character
+		}
+
+		message := issue.Message
+		if issue.Suggestion != "" {
+			message += "\n" + issue.Suggestion
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    Range{Start: start, End: end},
+			Severity: severity(issue.Severity),
+			Code:     issue.Rule,
+			Source:   Source,
+			Message:  message,
+		})
+	}
+	return diagnostics
+}
+
+// severity maps an issue severity to a diagnostic severity
+func severity(issueSeverity string) int {
+	switch strings.ToLower(issueSeverity) {
+	case "critical", "high":
+		return SeverityError
+	case "medium":
+		return SeverityWarning
+	default:
+		return SeverityInformation
+	}
+}
+
+// utf16Len returns the length of s in UTF-16 code units, the unit of LSP
+// character offsets
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,237 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-go-assistant/internal/codereview"
+	"mcp-go-assistant/internal/logging"
+)
+
+// testClient is an editor talking to a server over pipes
+type testClient struct {
+	t    *testing.T
+	in   *io.PipeWriter
+	out  *bufio.Reader
+	done chan error
+}
+
+// newTestClient starts a server reviewing with review and returns a client
+// connected to it
+func newTestClient(t *testing.T, review ReviewFunc) *testClient {
+	t.Helper()
+	log, err := logging.New("fatal", "json", "stderr", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := New(Options{Review: review, Logger: log, Version: "v1.0.0"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &testClient{t: t, in: inW, out: bufio.NewReader(outR), done: make(chan error, 1)}
+	go func() {
+		c.done <- server.Serve(context.Background(), inR, outW)
+		outW.Close()
+	}()
+	t.Cleanup(func() { inW.Close() })
+	return c
+}
+
+// send writes a request, or a notification when id is 0
+func (c *testClient) send(id int, method string, params any) {
+	c.t.Helper()
+	msg := &message{Method: method}
+	if id != 0 {
+		id := mustJSON(c.t, id)
+		msg.ID = &id
+	}
+	if params != nil {
+		msg.Params = mustJSON(c.t, params)
+	}
+	if err := writeMessage(c.in, msg); err != nil {
+		c.t.Fatalf("failed to send %s: %v", method, err)
+	}
+}
+
+// receive reads the next message from the server
+func (c *testClient) receive() *message {
+	c.t.Helper()
+	received := make(chan *message, 1)
+	go func() {
+		msg, err := readMessage(c.out)
+		if err != nil {
+			c.t.Errorf("failed to read message: %v", err)
+		}
+		received <- msg
+	}()
+	select {
+	case msg := <-received:
+		if msg == nil {
+			c.t.FailNow()
+		}
+		return msg
+	case <-time.After(5 * time.Second):
+		c.t.Fatal("no message from the server")
+		return nil
+	}
+}
+
+// diagnostics reads the next message, which must publish diagnostics
+func (c *testClient) diagnostics() publishDiagnosticsParams {
+	c.t.Helper()
+	msg := c.receive()
+	if msg.Method != "textDocument/publishDiagnostics" {
+		c.t.Fatalf("expected diagnostics, got %+v", msg)
+	}
+	var params publishDiagnosticsParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		c.t.Fatal(err)
+	}
+	return params
+}
+
+func mustJSON(t *testing.T, v any) json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// reviewLines reports an issue on each line of the code starting with
+// "func", high for exported functions and low otherwise
+func reviewLines(_ context.Context, params codereview.CodeReviewParams) (*codereview.ReviewResult, error) {
+	result := &codereview.ReviewResult{}
+	for i, line := range strings.Split(params.GoCode, "\n") {
+		if !strings.HasPrefix(line, "func ") {
+			continue
+		}
+		severity := "low"
+		if strings.ToUpper(line[5:6]) == line[5:6] {
+			severity = "high"
+		}
+		result.Issues = append(result.Issues, codereview.Issue{Line: i + 1, Column: 6, Severity: severity, Rule: "func", Message: "A function", Suggestion: "Keep it"})
+	}
+	return result, nil
+}
+
+func TestServer_Session(t *testing.T) {
+	c := newTestClient(t, reviewLines)
+
+	c.send(1, "initialize", map[string]any{"capabilities": map[string]any{}})
+	resp := c.receive()
+	var init initializeResult
+	if err := json.Unmarshal(resp.Result, &init); err != nil || resp.Error != nil {
+		t.Fatalf("unexpected initialize response %+v", resp)
+	}
+	if sync := init.Capabilities.TextDocumentSync; !sync.OpenClose || sync.Change != textDocumentSyncFull || !sync.Save.IncludeText {
+		t.Errorf("unexpected sync capabilities %+v", sync)
+	}
+	if init.ServerInfo.Name != Source || init.ServerInfo.Version != "v1.0.0" {
+		t.Errorf("unexpected server info %+v", init.ServerInfo)
+	}
+	c.send(0, "initialized", map[string]any{})
+
+	// Documents in other languages are not reviewed
+	c.send(0, "textDocument/didOpen", map[string]any{"textDocument": map[string]any{
+		"uri": "file:///src/README.md", "languageId": "markdown", "version": 1, "text": "func A() {}",
+	}})
+	c.send(0, "textDocument/didOpen", map[string]any{"textDocument": map[string]any{
+		"uri": "file:///src/a.go", "languageId": "go", "version": 1, "text": "package a\n\nfunc A() {}\n",
+	}})
+	opened := c.diagnostics()
+	if opened.URI != "file:///src/a.go" || opened.Version == nil || *opened.Version != 1 || len(opened.Diagnostics) != 1 {
+		t.Fatalf("unexpected diagnostics %+v", opened)
+	}
+	d := opened.Diagnostics[0]
+	if d.Severity != SeverityError || d.Code != "func" || d.Source != Source || d.Message != "A function\nKeep it" ||
+		d.Range != (Range{Start: Position{Line: 2, Character: 5}, End: Position{Line: 2, Character: 11}}) {
+		t.Errorf("unexpected diagnostic %+v", d)
+	}
+
+	// Changes are reviewed when the document is saved
+	c.send(0, "textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": "file:///src/a.go", "version": 2},
+		"contentChanges": []map[string]any{{"text": "package a\n\nfunc a() {}\nfunc b() {}\n"}},
+	})
+	c.send(0, "textDocument/didSave", map[string]any{"textDocument": map[string]any{"uri": "file:///src/a.go"}})
+	saved := c.diagnostics()
+	if *saved.Version != 2 || len(saved.Diagnostics) != 2 || saved.Diagnostics[1].Severity != SeverityInformation {
+		t.Errorf("unexpected diagnostics after save %+v", saved)
+	}
+
+	c.send(0, "textDocument/didClose", map[string]any{"textDocument": map[string]any{"uri": "file:///src/a.go"}})
+	if closed := c.diagnostics(); closed.Version != nil || len(closed.Diagnostics) != 0 {
+		t.Errorf("expected the diagnostics to be cleared, got %+v", closed)
+	}
+
+	c.send(2, "shutdown", nil)
+	if resp := c.receive(); string(resp.Result) != "null" || resp.Error != nil {
+		t.Errorf("unexpected shutdown response %+v", resp)
+	}
+	c.send(0, "exit", nil)
+	select {
+	case err := <-c.done:
+		if err != nil {
+			t.Errorf("Serve() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server did not exit")
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	c := newTestClient(t, reviewLines)
+
+	c.send(1, "textDocument/hover", map[string]any{})
+	if resp := c.receive(); resp.Error == nil || resp.Error.Code != codeNotInitialized {
+		t.Errorf("expected a not initialized error, got %+v", resp)
+	}
+
+	c.send(2, "initialize", map[string]any{})
+	c.receive()
+	c.send(3, "textDocument/hover", map[string]any{})
+	if resp := c.receive(); resp.Error == nil || resp.Error.Code != codeMethodNotFound {
+		t.Errorf("expected a method not found error, got %+v", resp)
+	}
+
+	c.send(0, "exit", nil)
+	if err := <-c.done; err == nil {
+		t.Error("expected an error for exit without shutdown")
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	text := "package a\n\n// ✓ 𝔾o\r\nvar x = 1\n"
+	issues := []codereview.Issue{
+		{Line: 3, Column: 7, Severity: "medium", Rule: "r"},
+		{Line: 3, EndLine: 4, Column: 1, Severity: "critical", Rule: "r"},
+		{Severity: "low", Rule: "file"},
+		{Line: 99, Column: 3, Severity: "low", Rule: "beyond"},
+	}
+	got := Diagnostics(issues, text)
+	want := []Range{
+		// The check mark is 3 bytes and 1 UTF-16 unit; the G is 2 units
+		{Start: Position{Line: 2, Character: 4}, End: Position{Line: 2, Character: 8}},
+		{Start: Position{Line: 2, Character: 0}, End: Position{Line: 3, Character: 9}},
+		{},
+		{},
+	}
+	for i, d := range got {
+		if d.Range != want[i] {
+			t.Errorf("issue %d: range = %+v, want %+v", i, d.Range, want[i])
+		}
+	}
+	if got[0].Severity != SeverityWarning || got[1].Severity != SeverityError || got[2].Severity != SeverityInformation {
+		t.Errorf("unexpected severities %+v", got)
+	}
+}
@@ -22,10 +22,25 @@ type Metrics struct {
 	toolDuration   *prometheus.HistogramVec
 	toolErrors     *prometheus.CounterVec
 
+	// Latency budget metrics
+	latencyBudget         *prometheus.GaugeVec
+	latencyBudgetExceeded *prometheus.CounterVec
+
 	// Validation metrics
 	validationAttempts *prometheus.CounterVec
 	validationFailures *prometheus.CounterVec
 
+	// Response cache metrics
+	cacheLookups *prometheus.CounterVec
+
+	// Idempotency metrics
+	idempotentReplays *prometheus.CounterVec
+
+	// Request queue metrics
+	queueDepth      *prometheus.GaugeVec
+	queueWait       *prometheus.HistogramVec
+	queueRejections *prometheus.CounterVec
+
 	// Error metrics
 	errorsTotal *prometheus.CounterVec
 
@@ -112,6 +127,23 @@ func newWithRegistry(reg prometheus.Registerer) *Metrics {
 		[]string{"tool", "error_type"},
 	)
 
+	// Initialize latency budget metrics
+	m.latencyBudget = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mcp_tool_latency_budget_seconds",
+			Help: "Target latency of successful tool calls",
+		},
+		[]string{"tool"},
+	)
+
+	m.latencyBudgetExceeded = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mcp_tool_latency_budget_exceeded_total",
+			Help: "Total number of successful tool calls slower than their latency budget",
+		},
+		[]string{"tool"},
+	)
+
 	// Initialize validation metrics
 	m.validationAttempts = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -129,6 +161,50 @@ func newWithRegistry(reg prometheus.Registerer) *Metrics {
 		[]string{"rule", "tool"},
 	)
 
+	// Initialize response cache metrics
+	m.cacheLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mcp_cache_lookups_total",
+			Help: "Total number of response cache lookups",
+		},
+		[]string{"tool", "result"},
+	)
+
+	// Initialize idempotency metrics
+	m.idempotentReplays = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mcp_idempotent_replays_total",
+			Help: "Total number of calls answered with the stored result of an earlier call with the same idempotency key",
+		},
+		[]string{"tool"},
+	)
+
+	// Initialize request queue metrics
+	m.queueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mcp_queue_depth",
+			Help: "Number of requests waiting for a concurrency slot",
+		},
+		[]string{"tool"},
+	)
+
+	m.queueWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "mcp_queue_wait_seconds",
+			Help:    "Time requests spent waiting for a concurrency slot",
+			Buckets: []float64{0.001, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30},
+		},
+		[]string{"tool"},
+	)
+
+	m.queueRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mcp_queue_rejections_total",
+			Help: "Total number of requests rejected because the server was busy",
+		},
+		[]string{"tool", "reason"},
+	)
+
 	// Initialize error metrics
 	m.errorsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -155,8 +231,15 @@ func newWithRegistry(reg prometheus.Registerer) *Metrics {
 		m.toolCallsTotal,
 		m.toolDuration,
 		m.toolErrors,
+		m.latencyBudget,
+		m.latencyBudgetExceeded,
 		m.validationAttempts,
 		m.validationFailures,
+		m.cacheLookups,
+		m.idempotentReplays,
+		m.queueDepth,
+		m.queueWait,
+		m.queueRejections,
 		m.errorsTotal,
 		m.uptime,
 	)
@@ -227,6 +310,23 @@ func (m *Metrics) RecordToolError(tool, errorType string) {
 	m.toolErrors.WithLabelValues(tool, errorType).Inc()
 }
 
+// SetLatencyBudget records the target latency of a tool
+func (m *Metrics) SetLatencyBudget(tool string, budget time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.latencyBudget.WithLabelValues(tool).Set(budget.Seconds())
+}
+
+// RecordLatencyBudgetExceeded records a successful call slower than its
+// tool's latency budget
+func (m *Metrics) RecordLatencyBudgetExceeded(tool string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.latencyBudgetExceeded.WithLabelValues(tool).Inc()
+}
+
 // Handler returns an HTTP handler for serving metrics
 func (m *Metrics) Handler() http.Handler {
 	return promhttp.Handler()
@@ -248,6 +348,50 @@ func (m *Metrics) RecordValidationFailure(rule, tool string) {
 	m.validationFailures.WithLabelValues(rule, tool).Inc()
 }
 
+// RecordCacheLookup records a response cache hit or miss
+func (m *Metrics) RecordCacheLookup(tool string, hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(tool, result).Inc()
+}
+
+// RecordIdempotentReplay records a call answered with a stored result
+func (m *Metrics) RecordIdempotentReplay(tool string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.idempotentReplays.WithLabelValues(tool).Inc()
+}
+
+// SetQueueDepth records the number of requests waiting for a tool
+func (m *Metrics) SetQueueDepth(tool string, depth int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queueDepth.WithLabelValues(tool).Set(float64(depth))
+}
+
+// RecordQueueWait records how long a request waited for a slot
+func (m *Metrics) RecordQueueWait(tool string, wait time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queueWait.WithLabelValues(tool).Observe(wait.Seconds())
+}
+
+// RecordQueueRejection records a request rejected as busy
+func (m *Metrics) RecordQueueRejection(tool, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queueRejections.WithLabelValues(tool, reason).Inc()
+}
+
 // RecordError records an error by category and code
 func (m *Metrics) RecordError(category, code, tool string) {
 	m.mu.Lock()
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestNew(t *testing.T) {
@@ -105,6 +106,61 @@ func TestMetrics_RecordValidation(t *testing.T) {
 	}
 }
 
+func TestMetrics_RecordCacheLookup(t *testing.T) {
+	m := newTestMetrics(t)
+
+	m.RecordCacheLookup("code-review", true)
+	m.RecordCacheLookup("code-review", false)
+	m.RecordCacheLookup("test-gen", false)
+
+	if got := testutil.ToFloat64(m.cacheLookups.WithLabelValues("code-review", "hit")); got != 1 {
+		t.Errorf("expected 1 code-review hit, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.cacheLookups.WithLabelValues("test-gen", "miss")); got != 1 {
+		t.Errorf("expected 1 test-gen miss, got %v", got)
+	}
+}
+
+func TestMetrics_LatencyBudget(t *testing.T) {
+	m := newTestMetrics(t)
+
+	m.SetLatencyBudget("code-review", 1500*time.Millisecond)
+	m.RecordLatencyBudgetExceeded("code-review")
+
+	if got := testutil.ToFloat64(m.latencyBudget.WithLabelValues("code-review")); got != 1.5 {
+		t.Errorf("expected a 1.5s budget, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.latencyBudgetExceeded.WithLabelValues("code-review")); got != 1 {
+		t.Errorf("expected 1 budget overrun, got %v", got)
+	}
+}
+
+func TestMetrics_RecordIdempotentReplay(t *testing.T) {
+	m := newTestMetrics(t)
+
+	m.RecordIdempotentReplay("scaffold")
+	m.RecordIdempotentReplay("scaffold")
+
+	if got := testutil.ToFloat64(m.idempotentReplays.WithLabelValues("scaffold")); got != 2 {
+		t.Errorf("expected 2 scaffold replays, got %v", got)
+	}
+}
+
+func TestMetrics_Queue(t *testing.T) {
+	m := newTestMetrics(t)
+
+	m.SetQueueDepth("code-review", 3)
+	m.RecordQueueWait("code-review", 50*time.Millisecond)
+	m.RecordQueueRejection("code-review", "queue_full")
+
+	if got := testutil.ToFloat64(m.queueDepth.WithLabelValues("code-review")); got != 3 {
+		t.Errorf("expected queue depth 3, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.queueRejections.WithLabelValues("code-review", "queue_full")); got != 1 {
+		t.Errorf("expected 1 rejection, got %v", got)
+	}
+}
+
 func TestMetrics_RecordError(t *testing.T) {
 	m := newTestMetrics(t)
 
@@ -0,0 +1,251 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// SnapshotVersion is the format version written to snapshots
+const SnapshotVersion = 1
+
+// Snapshot holds the cumulative counter values of a Metrics collector.
+// Histograms and gauges describe the running process and are not included.
+type Snapshot struct {
+	Version   int                        `json:"version"`
+	Timestamp time.Time                  `json:"timestamp"`
+	Counters  map[string][]CounterSample `json:"counters"` // Keyed by metric name
+}
+
+// CounterSample is the value of one labelled counter series
+type CounterSample struct {
+	Labels map[string]string `json:"labels"`
+	Value  float64           `json:"value"`
+}
+
+// Sink receives metric snapshots, e.g. a file or a remote exporter
+type Sink interface {
+	Export(ctx context.Context, snapshot *Snapshot) error
+}
+
+// counters returns the persisted counter vectors keyed by metric name
+func (m *Metrics) counters() map[string]*prometheus.CounterVec {
+	return map[string]*prometheus.CounterVec{
+		"mcp_requests_total":                     m.requestsTotal,
+		"mcp_request_errors_total":               m.requestErrors,
+		"mcp_tool_calls_total":                   m.toolCallsTotal,
+		"mcp_tool_errors_total":                  m.toolErrors,
+		"mcp_tool_latency_budget_exceeded_total": m.latencyBudgetExceeded,
+		"mcp_validation_attempts_total":          m.validationAttempts,
+		"mcp_validation_failures_total":          m.validationFailures,
+		"mcp_cache_lookups_total":                m.cacheLookups,
+		"mcp_idempotent_replays_total":           m.idempotentReplays,
+		"mcp_queue_rejections_total":             m.queueRejections,
+		"mcp_errors_total":                       m.errorsTotal,
+	}
+}
+
+// Snapshot captures the current counter values
+func (m *Metrics) Snapshot() *Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := &Snapshot{
+		Version:   SnapshotVersion,
+		Timestamp: time.Now().UTC(),
+		Counters:  make(map[string][]CounterSample),
+	}
+
+	for name, vec := range m.counters() {
+		ch := make(chan prometheus.Metric)
+		go func() {
+			vec.Collect(ch)
+			close(ch)
+		}()
+
+		var samples []CounterSample
+		for metric := range ch {
+			var pb dto.Metric
+			if err := metric.Write(&pb); err != nil || pb.Counter == nil {
+				continue
+			}
+
+			labels := make(map[string]string, len(pb.Label))
+			for _, lp := range pb.Label {
+				labels[lp.GetName()] = lp.GetValue()
+			}
+			samples = append(samples, CounterSample{Labels: labels, Value: pb.Counter.GetValue()})
+		}
+
+		if len(samples) > 0 {
+			sort.Slice(samples, func(i, j int) bool {
+				return fmt.Sprint(samples[i].Labels) < fmt.Sprint(samples[j].Labels)
+			})
+			snapshot.Counters[name] = samples
+		}
+	}
+
+	return snapshot
+}
+
+// Restore adds the counter values from snapshot to the collector. It is
+// meant to be called once at startup, before any requests are served.
+func (m *Metrics) Restore(snapshot *Snapshot) error {
+	if snapshot.Version != SnapshotVersion {
+		return fmt.Errorf("unsupported metrics snapshot version: %d", snapshot.Version)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counters := m.counters()
+	for name, samples := range snapshot.Counters {
+		vec, ok := counters[name]
+		if !ok {
+			continue // Metric removed since the snapshot was taken
+		}
+
+		for _, sample := range samples {
+			counter, err := vec.GetMetricWith(sample.Labels)
+			if err != nil {
+				return fmt.Errorf("invalid labels for %s: %w", name, err)
+			}
+			if sample.Value > 0 {
+				counter.Add(sample.Value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// FileSink writes snapshots as JSON to a file on disk
+type FileSink struct {
+	Path string
+}
+
+// NewFileSink creates a sink writing to path
+func NewFileSink(path string) *FileSink {
+	return &FileSink{Path: path}
+}
+
+// Export writes the snapshot atomically by renaming a temporary file
+func (s *FileSink) Export(_ context.Context, snapshot *Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics snapshot: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return fmt.Errorf("failed to create metrics snapshot directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.Path); err != nil {
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load reads the snapshot written by Export. A missing file yields a nil
+// snapshot and no error.
+func (s *FileSink) Load() (*Snapshot, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics snapshot: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse metrics snapshot %s: %w", s.Path, err)
+	}
+	return &snapshot, nil
+}
+
+// Persister periodically exports snapshots of a collector to a sink
+type Persister struct {
+	metrics  *Metrics
+	sink     Sink
+	interval time.Duration
+	onError  func(error)
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewPersister creates a persister exporting every interval. onError, if
+// set, is called for failed exports.
+func NewPersister(m *Metrics, sink Sink, interval time.Duration, onError func(error)) *Persister {
+	return &Persister{
+		metrics:  m,
+		sink:     sink,
+		interval: interval,
+		onError:  onError,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start begins periodic exports in the background
+func (p *Persister) Start() {
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				p.export()
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends periodic exports and writes a final snapshot
+func (p *Persister) Stop() {
+	p.once.Do(func() {
+		close(p.stop)
+		<-p.done
+		p.export()
+	})
+}
+
+// export writes one snapshot, reporting failures to onError
+func (p *Persister) export() {
+	ctx, cancel := context.WithTimeout(context.Background(), p.interval)
+	defer cancel()
+
+	if err := p.sink.Export(ctx, p.metrics.Snapshot()); err != nil && p.onError != nil {
+		p.onError(err)
+	}
+}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics_SnapshotRestore(t *testing.T) {
+	m := newTestMetrics(t)
+	m.RecordToolCall("code-review", "success", time.Millisecond)
+	m.RecordToolCall("code-review", "success", time.Millisecond)
+	m.RecordToolCall("test-gen", "error", time.Millisecond)
+	m.RecordValidationFailure("code_safety", "code-review")
+
+	snapshot := m.Snapshot()
+	if got := len(snapshot.Counters["mcp_tool_calls_total"]); got != 2 {
+		t.Fatalf("expected 2 tool call series, got %d", got)
+	}
+
+	restored := newTestMetrics(t)
+	restored.RecordToolCall("code-review", "success", time.Millisecond)
+	if err := restored.Restore(snapshot); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	if got := testutil.ToFloat64(restored.toolCallsTotal.WithLabelValues("code-review", "success")); got != 3 {
+		t.Errorf("expected restored count to add to the current count, got %v", got)
+	}
+	if got := testutil.ToFloat64(restored.validationFailures.WithLabelValues("code_safety", "code-review")); got != 1 {
+		t.Errorf("expected restored validation failure, got %v", got)
+	}
+}
+
+func TestMetrics_RestoreErrors(t *testing.T) {
+	m := newTestMetrics(t)
+
+	if err := m.Restore(&Snapshot{Version: 99}); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+
+	bad := &Snapshot{
+		Version: SnapshotVersion,
+		Counters: map[string][]CounterSample{
+			"mcp_tool_calls_total": {{Labels: map[string]string{"unknown": "x"}, Value: 1}},
+		},
+	}
+	if err := m.Restore(bad); err == nil {
+		t.Error("expected error for mismatched labels")
+	}
+
+	removed := &Snapshot{
+		Version:  SnapshotVersion,
+		Counters: map[string][]CounterSample{"mcp_removed_total": {{Value: 1}}},
+	}
+	if err := m.Restore(removed); err != nil {
+		t.Errorf("expected unknown metrics to be skipped, got %v", err)
+	}
+}
+
+func TestFileSink_ExportLoad(t *testing.T) {
+	sink := NewFileSink(filepath.Join(t.TempDir(), "state", "metrics.json"))
+
+	snapshot, err := sink.Load()
+	if err != nil || snapshot != nil {
+		t.Fatalf("expected no snapshot and no error for missing file, got %v, %v", snapshot, err)
+	}
+
+	m := newTestMetrics(t)
+	m.RecordCacheLookup("test-gen", true)
+	if err := sink.Export(context.Background(), m.Snapshot()); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	snapshot, err = sink.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	samples := snapshot.Counters["mcp_cache_lookups_total"]
+	if len(samples) != 1 || samples[0].Value != 1 || samples[0].Labels["result"] != "hit" {
+		t.Errorf("unexpected samples %+v", samples)
+	}
+
+	if err := os.WriteFile(sink.Path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sink.Load(); err == nil {
+		t.Error("expected error for corrupt snapshot")
+	}
+}
+
+// recordingSink collects exported snapshots
+type recordingSink struct {
+	mu        sync.Mutex
+	snapshots []*Snapshot
+	err       error
+}
+
+func (s *recordingSink) Export(_ context.Context, snapshot *Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots = append(s.snapshots, snapshot)
+	return s.err
+}
+
+func (s *recordingSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.snapshots)
+}
+
+func TestPersister(t *testing.T) {
+	m := newTestMetrics(t)
+	sink := &recordingSink{err: errors.New("disk full")}
+
+	var errs int
+	var mu sync.Mutex
+	p := NewPersister(m, sink, 5*time.Millisecond, func(error) {
+		mu.Lock()
+		errs++
+		mu.Unlock()
+	})
+	p.Start()
+
+	deadline := time.Now().Add(time.Second)
+	for sink.count() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	p.Stop()
+	p.Stop() // Safe to call twice
+
+	exported := sink.count()
+	if exported < 3 {
+		t.Errorf("expected periodic exports plus a final one, got %d", exported)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if errs != exported {
+		t.Errorf("expected every failed export to be reported, got %d of %d", errs, exported)
+	}
+}
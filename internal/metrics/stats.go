@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// StatsParams represents the parameters for the server-stats tool, which
+// takes none
+type StatsParams struct{}
+
+// Stats summarizes the collected metrics for clients without a Prometheus
+// scraper. Counters restored from a snapshot are included; uptime is that
+// of the running process.
+type Stats struct {
+	StartTime       time.Time             `json:"start_time"`
+	Uptime          string                `json:"uptime"`
+	UptimeSeconds   float64               `json:"uptime_seconds"`
+	TotalCalls      int                   `json:"total_calls"`
+	Errors          int                   `json:"errors"`
+	ErrorRate       float64               `json:"error_rate"`     // Errors per call, 0 without calls
+	CacheHitRate    float64               `json:"cache_hit_rate"` // Hits per lookup, 0 without lookups
+	ActiveRequests  int                   `json:"active_requests"`
+	Tools           map[string]*ToolStats `json:"tools"`
+	CircuitBreakers map[string]string     `json:"circuit_breakers,omitempty"` // State by breaker name, set by the caller
+}
+
+// ToolStats summarizes the calls of one tool
+type ToolStats struct {
+	Calls        int     `json:"calls"`
+	Errors       int     `json:"errors"`
+	ErrorRate    float64 `json:"error_rate"`
+	CacheHits    int     `json:"cache_hits,omitempty"`
+	CacheMisses  int     `json:"cache_misses,omitempty"`
+	CacheHitRate float64 `json:"cache_hit_rate,omitempty"`
+	Active       int     `json:"active"`
+}
+
+// Stats returns the current call, error, cache and active request totals,
+// overall and per tool
+func (m *Metrics) Stats() *Stats {
+	snapshot := m.Snapshot()
+	uptime := time.Since(m.startTime)
+	stats := &Stats{
+		StartTime:     m.startTime.UTC(),
+		Uptime:        uptime.Round(time.Second).String(),
+		UptimeSeconds: uptime.Seconds(),
+		Tools:         make(map[string]*ToolStats),
+	}
+
+	tool := func(name string) *ToolStats {
+		ts, ok := stats.Tools[name]
+		if !ok {
+			ts = &ToolStats{}
+			stats.Tools[name] = ts
+		}
+		return ts
+	}
+
+	for _, sample := range snapshot.Counters["mcp_tool_calls_total"] {
+		ts := tool(sample.Labels["tool"])
+		ts.Calls += int(sample.Value)
+		if sample.Labels["status"] == "error" {
+			ts.Errors += int(sample.Value)
+		}
+	}
+	for _, sample := range snapshot.Counters["mcp_cache_lookups_total"] {
+		ts := tool(sample.Labels["tool"])
+		if sample.Labels["result"] == "hit" {
+			ts.CacheHits += int(sample.Value)
+		} else {
+			ts.CacheMisses += int(sample.Value)
+		}
+	}
+	for name, active := range m.activeRequests() {
+		if active > 0 {
+			tool(name).Active = active
+		}
+	}
+
+	hits, lookups := 0, 0
+	for _, ts := range stats.Tools {
+		ts.ErrorRate = rate(ts.Errors, ts.Calls)
+		ts.CacheHitRate = rate(ts.CacheHits, ts.CacheHits+ts.CacheMisses)
+		stats.TotalCalls += ts.Calls
+		stats.Errors += ts.Errors
+		stats.ActiveRequests += ts.Active
+		hits += ts.CacheHits
+		lookups += ts.CacheHits + ts.CacheMisses
+	}
+	stats.ErrorRate = rate(stats.Errors, stats.TotalCalls)
+	stats.CacheHitRate = rate(hits, lookups)
+
+	return stats
+}
+
+// activeRequests returns the in-flight requests by tool
+func (m *Metrics) activeRequests() map[string]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		m.requestActive.Collect(ch)
+		close(ch)
+	}()
+
+	active := make(map[string]int)
+	for metric := range ch {
+		var pb dto.Metric
+		if err := metric.Write(&pb); err != nil || pb.Gauge == nil {
+			continue
+		}
+		for _, lp := range pb.Label {
+			if lp.GetName() == "tool" {
+				active[lp.GetValue()] = int(pb.Gauge.GetValue())
+			}
+		}
+	}
+	return active
+}
+
+// rate returns n/total, or 0 when total is 0
+func rate(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// JSON returns the stats as indented JSON
+func (s *Stats) JSON() (string, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal server stats: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMetrics_Stats(t *testing.T) {
+	m := newTestMetrics(t)
+
+	m.RecordToolCall("code-review", "success", time.Millisecond)
+	m.RecordToolCall("code-review", "success", time.Millisecond)
+	m.RecordToolCall("code-review", "error", time.Millisecond)
+	m.RecordToolCall("go-doc", "success", time.Millisecond)
+	m.RecordCacheLookup("code-review", true)
+	m.RecordCacheLookup("code-review", false)
+	m.RecordCacheLookup("code-review", false)
+	m.RecordCacheLookup("code-review", false)
+	m.IncrementActiveRequest("go-doc")
+	m.IncrementActiveRequest("test-gen")
+	m.DecrementActiveRequest("test-gen")
+
+	stats := m.Stats()
+
+	if stats.TotalCalls != 4 || stats.Errors != 1 || stats.ErrorRate != 0.25 {
+		t.Errorf("unexpected totals: calls %d, errors %d, rate %v", stats.TotalCalls, stats.Errors, stats.ErrorRate)
+	}
+	if stats.CacheHitRate != 0.25 {
+		t.Errorf("expected cache hit rate 0.25, got %v", stats.CacheHitRate)
+	}
+	if stats.ActiveRequests != 1 {
+		t.Errorf("expected 1 active request, got %d", stats.ActiveRequests)
+	}
+
+	review := stats.Tools["code-review"]
+	if review == nil || review.Calls != 3 || review.Errors != 1 || review.CacheHits != 1 || review.CacheMisses != 3 {
+		t.Fatalf("unexpected code-review stats %+v", review)
+	}
+	if doc := stats.Tools["go-doc"]; doc == nil || doc.Active != 1 || doc.ErrorRate != 0 {
+		t.Errorf("unexpected go-doc stats %+v", doc)
+	}
+	if _, ok := stats.Tools["test-gen"]; ok {
+		t.Error("expected tools without calls or active requests to be omitted")
+	}
+}
+
+func TestStats_JSON(t *testing.T) {
+	m := newTestMetrics(t)
+	stats := m.Stats()
+	stats.CircuitBreakers = map[string]string{"godoc": "closed"}
+
+	text, err := stats.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	for _, key := range []string{"uptime", "total_calls", "error_rate", "cache_hit_rate", "active_requests", "tools", "circuit_breakers"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("expected key %q in %s", key, text)
+		}
+	}
+}
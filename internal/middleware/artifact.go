@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ArtifactView is the full content of a tool output, stored as an artifact
+// when it is large, and the summary returned in its place
+type ArtifactView[Out any] struct {
+	Content  string // Full output kept in the artifact
+	MIMEType string
+	Summary  string // Short text returned instead of the content
+	Out      Out    // Structured output returned instead, without the bulky fields
+}
+
+// Artifacts stores outputs larger than the threshold of the artifact store
+// and answers with their summary and artifact ID, keeping responses small.
+// Content blocks other than text, such as links to written files, are
+// kept. Cached and replayed responses pass through again and get the same
+// ID, since it is derived from the content.
+func Artifacts[In, Out any](deps *Dependencies, tool string, view func(in In, out Out) ArtifactView[Out]) Middleware[In, Out] {
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			result, out, err := next(ctx, req, in)
+			if err != nil || result == nil || result.IsError {
+				return result, out, err
+			}
+
+			v := view(in, out)
+			if len(v.Content) <= deps.Artifacts.Threshold() {
+				return result, out, err
+			}
+
+			a := deps.Artifacts.Put(tool, v.MIMEType, v.Content)
+			deps.logger(ctx).InfoEvent().Str("tool", tool).Str("artifact_id", a.ID).Int("size", a.Size).Msgf("%s output stored as an artifact", tool)
+
+			text := fmt.Sprintf("%s\n\nThe full output (%d bytes) is stored as artifact %s until %s; fetch it with get-artifact or read %s.",
+				v.Summary, a.Size, a.ID, a.Expires.UTC().Format("2006-01-02T15:04:05Z"), a.URI)
+			content := []mcp.Content{&mcp.TextContent{Text: text}}
+			for _, c := range result.Content {
+				if _, ok := c.(*mcp.TextContent); !ok {
+					content = append(content, c)
+				}
+			}
+			size := int64(a.Size)
+			content = append(content, &mcp.ResourceLink{URI: a.URI, Name: a.ID, MIMEType: a.MIMEType, Size: &size})
+
+			meta := maps.Clone(result.Meta)
+			if meta == nil {
+				meta = mcp.Meta{}
+			}
+			meta["artifact_id"] = a.ID
+			return &mcp.CallToolResult{Content: content, Meta: meta}, v.Out, nil
+		}
+	}
+}
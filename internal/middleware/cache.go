@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-go-assistant/internal/cache"
+)
+
+// CachedResponse is a successful tool response stored in a response cache
+type CachedResponse[Out any] struct {
+	Result mcp.CallToolResult
+	Out    Out
+}
+
+// ResponseCache caches successful responses for inputs that key returns a
+// key for. Inputs for which key reports false, such as those that read
+// files from disk, bypass the cache.
+func ResponseCache[In, Out any](deps *Dependencies, tool string, c *cache.Cache[CachedResponse[Out]], key func(in In) (string, bool)) Middleware[In, Out] {
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			k, ok := key(in)
+			if !ok {
+				return next(ctx, req, in)
+			}
+
+			if cached, hit := c.Get(k); hit {
+				deps.Metrics.RecordCacheLookup(tool, true)
+				deps.logger(ctx).DebugEvent().Str("tool", tool).Msg("serving cached response")
+
+				// The SDK fills in fields of the returned result, so every
+				// caller gets its own copy
+				result := cached.Result
+				return &result, cached.Out, nil
+			}
+			deps.Metrics.RecordCacheLookup(tool, false)
+
+			result, out, err := next(ctx, req, in)
+			if err == nil && result != nil && !result.IsError {
+				c.Set(k, CachedResponse[Out]{Result: *result, Out: out})
+			}
+			return result, out, err
+		}
+	}
+}
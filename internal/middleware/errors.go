@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"fmt"
+
+	"mcp-go-assistant/internal/circuitbreaker"
+	"mcp-go-assistant/internal/queue"
+	"mcp-go-assistant/internal/ratelimit"
+	"mcp-go-assistant/internal/types"
+	"mcp-go-assistant/internal/validations"
+)
+
+// WrapValidationError wraps validation errors as MCPError
+func WrapValidationError(err error, tool string) error {
+	if err == nil {
+		return nil
+	}
+
+	if verr, ok := err.(*validations.ValidationError); ok {
+		mcpErr := verr.ToMCPError()
+		if tool != "" {
+			mcpErr = types.AddDetail(mcpErr, "tool", tool)
+		}
+		return mcpErr
+	}
+
+	return types.WrapValidationError(err, fmt.Sprintf("%s validation failed", tool))
+}
+
+// WrapRateLimitError wraps rate limit errors as MCPError
+func WrapRateLimitError(err error, tool string) error {
+	if err == nil {
+		return nil
+	}
+
+	if rerr, ok := err.(*ratelimit.RateLimitError); ok {
+		mcpErr := rerr.ToMCPError()
+		if tool != "" {
+			mcpErr = types.AddDetail(mcpErr, "tool", tool)
+		}
+		return mcpErr
+	}
+
+	return types.WrapRateLimitError(err, fmt.Sprintf("%s rate limit exceeded", tool))
+}
+
+// WrapCircuitBreakerError wraps circuit breaker errors as MCPError
+func WrapCircuitBreakerError(err error, tool string) error {
+	if err == nil {
+		return nil
+	}
+
+	if cerr, ok := err.(*circuitbreaker.CircuitBreakerError); ok {
+		mcpErr := cerr.ToMCPError()
+		if tool != "" {
+			mcpErr = types.AddDetail(mcpErr, "tool", tool)
+		}
+		return mcpErr
+	}
+
+	return types.WrapCircuitBreakerError(err, fmt.Sprintf("%s circuit breaker open", tool))
+}
+
+// WrapServerBusyError wraps concurrency queue rejections as MCPError
+func WrapServerBusyError(err error, tool string) error {
+	if err == nil {
+		return nil
+	}
+
+	if berr, ok := err.(*queue.BusyError); ok {
+		mcpErr := berr.ToMCPError()
+		if tool != "" {
+			mcpErr = types.AddDetail(mcpErr, "tool", tool)
+		}
+		return mcpErr
+	}
+
+	return types.NewServerBusyError(fmt.Sprintf("%s is busy", tool), "tool", tool)
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-go-assistant/internal/cache"
+	"mcp-go-assistant/internal/ratelimit"
+	"mcp-go-assistant/internal/types"
+)
+
+// IdempotencyStore keeps the results of calls made with an idempotency key
+// so that a client retrying a call it gave up on gets the original result
+// instead of running the tool again. It is shared by all tools.
+type IdempotencyStore struct {
+	results *cache.Cache[idempotentResult]
+
+	mu       sync.Mutex
+	inFlight map[string]chan struct{} // Closed when the call holding the key finishes
+}
+
+// idempotentResult is a stored successful response and a fingerprint of the
+// parameters that produced it
+type idempotentResult struct {
+	fingerprint string
+	result      mcp.CallToolResult
+	out         any
+}
+
+// NewIdempotencyStore creates a store keeping at most maxEntries results for
+// ttl each
+func NewIdempotencyStore(ttl time.Duration, maxEntries int) *IdempotencyStore {
+	return &IdempotencyStore{
+		results:  cache.New[idempotentResult](ttl, maxEntries),
+		inFlight: make(map[string]chan struct{}),
+	}
+}
+
+// acquire returns the stored result for key, or claims the key for the
+// caller, who must then call release. Callers using a key that is still
+// claimed wait for its holder to finish.
+func (s *IdempotencyStore) acquire(ctx context.Context, key string) (idempotentResult, bool, error) {
+	for {
+		s.mu.Lock()
+		if stored, ok := s.results.Get(key); ok {
+			s.mu.Unlock()
+			return stored, true, nil
+		}
+		done, busy := s.inFlight[key]
+		if !busy {
+			s.inFlight[key] = make(chan struct{})
+			s.mu.Unlock()
+			return idempotentResult{}, false, nil
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return idempotentResult{}, false, ctx.Err()
+		}
+	}
+}
+
+// release stores stored under key, if not nil, and wakes callers waiting
+// for the key. A failed call stores nothing, so the next caller runs it again.
+func (s *IdempotencyStore) release(key string, stored *idempotentResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored != nil {
+		s.results.Set(key, *stored)
+	}
+	close(s.inFlight[key])
+	delete(s.inFlight, key)
+}
+
+// Idempotency answers calls carrying an idempotency key that already
+// succeeded with the stored result. Keys are scoped to the client and
+// session making the call, so clients picking the same key never receive
+// each other's results. Reusing a key with different parameters is rejected
+// as a validation error.
+func Idempotency[In, Out any](deps *Dependencies, tool string, key func(in In) string) Middleware[In, Out] {
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			var zero Out
+			idempotencyKey := key(in)
+			if idempotencyKey == "" {
+				return next(ctx, req, in)
+			}
+
+			k := idempotencyScope(ctx, req) + "\x00" + tool + "\x00" + idempotencyKey
+			fingerprint := cache.Key(tool, in)
+			stored, found, err := deps.Idempotency.acquire(ctx, k)
+			if err != nil {
+				return nil, zero, deps.fail(ctx, types.WrapError(err, "waiting for the call with the same idempotency key failed", "tool", tool), tool)
+			}
+
+			if found {
+				if stored.fingerprint != fingerprint {
+					return nil, zero, deps.fail(ctx, types.NewValidationError(
+						"idempotency_key was already used with different parameters",
+						"tool", tool,
+						"field", "idempotency_key",
+					), tool)
+				}
+				out, _ := stored.out.(Out)
+				deps.Metrics.RecordIdempotentReplay(tool)
+				deps.logger(ctx).InfoEvent().Str("tool", tool).Msgf("%s request answered with the stored result for its idempotency key", tool)
+
+				// The SDK fills in fields of the returned result, so every
+				// caller gets its own copy
+				result := stored.result
+				return &result, out, nil
+			}
+
+			var succeeded *idempotentResult
+			defer func() { deps.Idempotency.release(k, succeeded) }()
+
+			result, out, err := next(ctx, req, in)
+			if err == nil && result != nil && !result.IsError {
+				succeeded = &idempotentResult{fingerprint: fingerprint, result: *result, out: out}
+			}
+			return result, out, err
+		}
+	}
+}
+
+// idempotencyScope identifies the caller owning an idempotency key: the
+// client of the connection, as set by the daemon, and the session on
+// transports that assign session IDs
+func idempotencyScope(ctx context.Context, req *mcp.CallToolRequest) string {
+	scope := ratelimit.ClientIDFromContext(ctx)
+	if req != nil && req.Session != nil {
+		scope += "\x00" + req.Session.ID()
+	}
+	return scope
+}
@@ -0,0 +1,359 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog"
+
+	"mcp-go-assistant/internal/artifact"
+	"mcp-go-assistant/internal/cache"
+	"mcp-go-assistant/internal/circuitbreaker"
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/metrics"
+	"mcp-go-assistant/internal/queue"
+	"mcp-go-assistant/internal/ratelimit"
+	"mcp-go-assistant/internal/retry"
+	"mcp-go-assistant/internal/session"
+	"mcp-go-assistant/internal/types"
+	"mcp-go-assistant/internal/validations"
+)
+
+// ToolFunc is a typed MCP tool handler
+type ToolFunc[In, Out any] func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error)
+
+// Middleware decorates a ToolFunc with additional behavior
+type Middleware[In, Out any] func(next ToolFunc[In, Out]) ToolFunc[In, Out]
+
+// ErrorHandler logs and records a failed tool call and returns the error
+type ErrorHandler func(log *logging.Logger, err error, tool string, duration time.Duration) error
+
+// Dependencies are the shared services used by the middleware stack
+type Dependencies struct {
+	Logger      *logging.Logger
+	Metrics     *metrics.Metrics
+	RateLimiter *ratelimit.Middleware  // Optional; rate limiting is skipped when nil
+	HandleError ErrorHandler           // Optional; errors are returned unlogged when nil
+	Validator   *validations.Validator // Resolves the rule names in validation specs
+	Idempotency *IdempotencyStore      // Optional; idempotency keys are ignored when nil
+	Sessions    *session.Store         // Optional; session defaults are not applied when nil
+	Artifacts   *artifact.Store        // Optional; large outputs are returned inline when nil
+
+	// Optional target latency per tool; successful calls over budget are
+	// counted and logged
+	LatencyBudgets map[string]time.Duration
+
+	// Inputs larger than this many bytes are spooled to temporary files
+	// for tools that declare a LargeInput; zero disables spooling
+	LargeInputThreshold int
+}
+
+// ToolSpec describes the resilience stack applied to a tool by Wrap
+type ToolSpec[In, Out any] struct {
+	Name            string
+	RateClass       ratelimit.ToolClass                            // Optional; class whose rate limits apply without tool limits
+	FailureMessage  func(in In) string                             // Message used to wrap execution errors
+	RequestFields   func(e *zerolog.Event, in In) *zerolog.Event   // Optional fields for the request log line
+	ResultFields    func(e *zerolog.Event, out Out) *zerolog.Event // Optional fields for the completion log line
+	Validation      ValidationSpec                                 // Parameter rules run before execution
+	Cache           *cache.Cache[CachedResponse[Out]]              // Optional; requires CacheKey
+	CacheKey        func(in In) (string, bool)                     // Cache key for the input, or false to bypass the cache
+	IdempotencyKey  func(in In) string                             // Optional; the client-supplied idempotency key, or "" for none
+	LargeInput      func(in *In) LargeInput                        // Optional; the parameter spooled to a file when large
+	SessionDefaults func(in *In, d session.Defaults)               // Optional; fills the parameters the call leaves unset
+	Artifact        func(in In, out Out) ArtifactView[Out]         // Optional; stores large outputs as artifacts
+	Queue           *queue.Limiter                                 // Optional; bounds concurrent executions
+	CircuitBreaker  *circuitbreaker.CircuitBreaker                 // Optional
+	Timeout         func(in In) time.Duration                      // Optional; a zero duration disables the timeout
+	Retry           *retry.RetryWrapper                            // Optional
+}
+
+// RequestInfo is per-invocation state shared by the middleware stack
+type RequestInfo struct {
+	Tool      string
+	RequestID string // Also in the context, see logging.RequestIDFromContext
+	Log       *logging.Logger
+	Start     time.Time
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a context carrying info
+func WithRequestInfo(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom returns the request info stored in ctx, or nil
+func RequestInfoFrom(ctx context.Context) *RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info
+}
+
+// Chain wraps h with mws so that the first middleware is the outermost
+func Chain[In, Out any](h ToolFunc[In, Out], mws ...Middleware[In, Out]) ToolFunc[In, Out] {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// Wrap applies the full resilience stack described by spec to h: request
+// logging, rate limiting, active request tracking, session defaults,
+// validation, artifact storage, idempotency, outcome metrics, response
+// caching, large input spooling, concurrency queueing, circuit breaking,
+// timeout and retry, in that order
+func Wrap[In, Out any](deps *Dependencies, spec ToolSpec[In, Out], h ToolFunc[In, Out]) mcp.ToolHandlerFor[In, Out] {
+	mws := []Middleware[In, Out]{
+		RequestLogging(deps, spec),
+		RateLimit[In, Out](deps, spec.Name, spec.RateClass),
+		ActiveRequests[In, Out](deps, spec.Name),
+	}
+	if deps.Sessions != nil && spec.SessionDefaults != nil {
+		mws = append(mws, SessionDefaults[In, Out](deps.Sessions, spec.SessionDefaults))
+	}
+	mws = append(mws, Validate[In, Out](deps, spec.Name, spec.Validation))
+	if deps.Artifacts != nil && spec.Artifact != nil {
+		mws = append(mws, Artifacts[In, Out](deps, spec.Name, spec.Artifact))
+	}
+	if deps.Idempotency != nil && spec.IdempotencyKey != nil {
+		mws = append(mws, Idempotency[In, Out](deps, spec.Name, spec.IdempotencyKey))
+	}
+	mws = append(mws, Outcome(deps, spec))
+	if spec.Cache != nil && spec.CacheKey != nil {
+		mws = append(mws, ResponseCache[In, Out](deps, spec.Name, spec.Cache, spec.CacheKey))
+	}
+	if spec.LargeInput != nil {
+		mws = append(mws, SpoolLargeInput[In, Out](deps, spec.Name, spec.LargeInput))
+	}
+	if spec.Queue != nil {
+		mws = append(mws, Queue[In, Out](deps, spec.Name, spec.Queue))
+	}
+	if spec.CircuitBreaker != nil {
+		mws = append(mws, CircuitBreaker[In, Out](spec.CircuitBreaker))
+	}
+	if spec.Timeout != nil {
+		mws = append(mws, Timeout[In, Out](spec.Timeout))
+	}
+	if spec.Retry != nil {
+		mws = append(mws, Retry[In, Out](spec.Retry))
+	}
+
+	return mcp.ToolHandlerFor[In, Out](Chain(h, mws...))
+}
+
+// RequestLogging creates a request ID and a logger carrying it, stores both
+// in the context and logs the incoming request. Tools read the ID back from
+// the context to correlate their errors and subprocesses with the request.
+func RequestLogging[In, Out any](deps *Dependencies, spec ToolSpec[In, Out]) Middleware[In, Out] {
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			requestID := logging.NewRequestID()
+			info := &RequestInfo{
+				Tool:      spec.Name,
+				RequestID: requestID,
+				Log:       deps.Logger.WithRequestID(requestID),
+				Start:     time.Now(),
+			}
+
+			event := info.Log.InfoEvent().Str("tool", spec.Name)
+			if spec.RequestFields != nil {
+				event = spec.RequestFields(event, in)
+			}
+			event.Msgf("processing %s request", spec.Name)
+
+			ctx = logging.ContextWithRequestID(ctx, requestID)
+			return next(WithRequestInfo(ctx, info), req, in)
+		}
+	}
+}
+
+// RateLimit rejects calls that exceed the tool's rate limit, or holds them
+// until they fit in it when the limiter waits. A tool given a class gets the
+// class's limits unless it has limits of its own.
+func RateLimit[In, Out any](deps *Dependencies, tool string, class ratelimit.ToolClass) Middleware[In, Out] {
+	if deps.RateLimiter != nil && class != "" {
+		deps.RateLimiter.SetToolClass(tool, class)
+	}
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			if deps.RateLimiter != nil {
+				if err := deps.RateLimiter.Acquire(ctx, tool, ratelimit.ClientIDFromContext(ctx)); err != nil {
+					var zero Out
+					return nil, zero, deps.fail(ctx, WrapRateLimitError(err, tool), tool)
+				}
+			}
+			return next(ctx, req, in)
+		}
+	}
+}
+
+// SessionDefaults fills the parameters a call leaves unset from the
+// defaults of the client's session. They are applied before validation, so
+// they pass the same rules as parameters of the call.
+func SessionDefaults[In, Out any](sessions *session.Store, apply func(in *In, d session.Defaults)) Middleware[In, Out] {
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			if req != nil && req.Session != nil {
+				if d, ok := sessions.Get(req.Session); ok {
+					apply(&in, d)
+				}
+			}
+			return next(ctx, req, in)
+		}
+	}
+}
+
+// ActiveRequests tracks the number of in-flight calls for the tool
+func ActiveRequests[In, Out any](deps *Dependencies, tool string) Middleware[In, Out] {
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			deps.Metrics.IncrementActiveRequest(tool)
+			defer deps.Metrics.DecrementActiveRequest(tool)
+			return next(ctx, req, in)
+		}
+	}
+}
+
+// Outcome records tool call metrics and converts execution errors into
+// MCP errors. Open circuit breaker and server busy errors are reported
+// without counting as a failed call.
+func Outcome[In, Out any](deps *Dependencies, spec ToolSpec[In, Out]) Middleware[In, Out] {
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			result, out, err := next(ctx, req, in)
+			duration := deps.elapsed(ctx)
+
+			if err != nil {
+				var zero Out
+
+				// Check if error is circuit breaker open
+				var cbOpenErr *circuitbreaker.CircuitBreakerError
+				if errors.As(err, &cbOpenErr) && errors.Is(err, circuitbreaker.ErrCircuitBreakerOpen) {
+					return nil, zero, deps.fail(ctx, WrapCircuitBreakerError(err, spec.Name), spec.Name)
+				}
+
+				// Check if the request was turned away by the concurrency queue
+				var busyErr *queue.BusyError
+				if errors.As(err, &busyErr) {
+					deps.Metrics.RecordQueueRejection(spec.Name, busyErr.Reason)
+					return nil, zero, deps.fail(ctx, WrapServerBusyError(err, spec.Name), spec.Name)
+				}
+
+				// Tool execution error - wrap as internal error
+				message := spec.Name + " failed"
+				if spec.FailureMessage != nil {
+					message = spec.FailureMessage(in)
+				}
+				mcpErr := types.WrapError(err, message)
+				if kind, ok := types.KindOf(err); ok {
+					types.AddDetail(mcpErr, "kind", string(kind))
+				}
+				if requestID := logging.RequestIDFromContext(ctx); requestID != "" {
+					types.AddDetail(mcpErr, "request_id", requestID)
+				}
+
+				// Tell clients how hard the call was retried before failing
+				var retryErr *retry.RetryError
+				if errors.As(err, &retryErr) {
+					types.AddDetail(mcpErr, "attempts", retryErr.Attempts)
+					types.AddDetail(mcpErr, "total_delay_ms", retryErr.TotalDelay.Milliseconds())
+				}
+
+				deps.Metrics.RecordToolCall(spec.Name, "error", duration)
+				return nil, zero, deps.fail(ctx, mcpErr, spec.Name)
+			}
+
+			deps.Metrics.RecordToolCall(spec.Name, "success", duration)
+			if budget := deps.LatencyBudgets[spec.Name]; budget > 0 && duration > budget {
+				deps.Metrics.RecordLatencyBudgetExceeded(spec.Name)
+				deps.logger(ctx).WarnEvent().
+					Dur("duration_ms", duration).
+					Dur("budget_ms", budget).
+					Msgf("%s request exceeded its latency budget", spec.Name)
+			}
+			event := deps.logger(ctx).InfoEvent().Dur("duration_ms", duration)
+			if spec.ResultFields != nil {
+				event = spec.ResultFields(event, out)
+			}
+			event.Msgf("%s request completed", spec.Name)
+
+			return result, out, nil
+		}
+	}
+}
+
+// CircuitBreaker runs the call through cb
+func CircuitBreaker[In, Out any](cb *circuitbreaker.CircuitBreaker) Middleware[In, Out] {
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			var result *mcp.CallToolResult
+			var out Out
+			cbErr := cb.CallContext(ctx, func(ctx context.Context) error {
+				var err error
+				result, out, err = next(ctx, req, in)
+				return err
+			})
+			return result, out, cbErr
+		}
+	}
+}
+
+// Timeout bounds the call by the duration returned for the input
+func Timeout[In, Out any](timeout func(in In) time.Duration) Middleware[In, Out] {
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			if d := timeout(in); d > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, d)
+				defer cancel()
+			}
+			return next(ctx, req, in)
+		}
+	}
+}
+
+// FixedTimeout returns a timeout function that always yields d
+func FixedTimeout[In any](d time.Duration) func(In) time.Duration {
+	return func(In) time.Duration { return d }
+}
+
+// Retry retries failed calls using wrapper
+func Retry[In, Out any](wrapper *retry.RetryWrapper) Middleware[In, Out] {
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			var result *mcp.CallToolResult
+			out, err := retry.DoWithData(ctx, wrapper, func(_ uint) (Out, error) {
+				r, out, err := next(ctx, req, in)
+				result = r
+				return out, err
+			})
+			return result, out, err
+		}
+	}
+}
+
+// logger returns the request-scoped logger, falling back to the base logger
+func (d *Dependencies) logger(ctx context.Context) *logging.Logger {
+	if info := RequestInfoFrom(ctx); info != nil {
+		return info.Log
+	}
+	return d.Logger
+}
+
+// elapsed returns the time since the request started
+func (d *Dependencies) elapsed(ctx context.Context) time.Duration {
+	if info := RequestInfoFrom(ctx); info != nil {
+		return time.Since(info.Start)
+	}
+	return 0
+}
+
+// fail passes err to the configured error handler
+func (d *Dependencies) fail(ctx context.Context, err error, tool string) error {
+	if d.HandleError == nil {
+		return err
+	}
+	return d.HandleError(d.logger(ctx), err, tool, d.elapsed(ctx))
+}
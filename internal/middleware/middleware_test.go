@@ -0,0 +1,769 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"mcp-go-assistant/internal/artifact"
+	"mcp-go-assistant/internal/cache"
+	"mcp-go-assistant/internal/circuitbreaker"
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/metrics"
+	"mcp-go-assistant/internal/queue"
+	"mcp-go-assistant/internal/ratelimit"
+	"mcp-go-assistant/internal/retry"
+	"mcp-go-assistant/internal/session"
+	"mcp-go-assistant/internal/types"
+	"mcp-go-assistant/internal/validations"
+)
+
+type testParams struct {
+	Name  string     `json:"name"`
+	Items []testItem `json:"items,omitempty"`
+	Key   string     `json:"key,omitempty"`
+	File  string     `json:"-"`
+}
+
+type testItem struct {
+	Code string `json:"code"`
+}
+
+func newTestDeps(t *testing.T) (*Dependencies, *[]string) {
+	t.Helper()
+	log, err := logging.New("error", "json", "stderr", true)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	handled := &[]string{}
+	return &Dependencies{
+		Logger:    log,
+		Metrics:   metrics.NewWithRegistry(prometheus.NewRegistry()),
+		Validator: validations.NewValidator(),
+		HandleError: func(_ *logging.Logger, err error, tool string, _ time.Duration) error {
+			*handled = append(*handled, tool+": "+err.Error())
+			return err
+		},
+	}, handled
+}
+
+func okHandler(calls *int) ToolFunc[testParams, string] {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, in testParams) (*mcp.CallToolResult, string, error) {
+		*calls++
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: in.Name}}}, "ok:" + in.Name, nil
+	}
+}
+
+func TestChain_Order(t *testing.T) {
+	var order []string
+	mark := func(name string) Middleware[testParams, string] {
+		return func(next ToolFunc[testParams, string]) ToolFunc[testParams, string] {
+			return func(ctx context.Context, req *mcp.CallToolRequest, in testParams) (*mcp.CallToolResult, string, error) {
+				order = append(order, name)
+				return next(ctx, req, in)
+			}
+		}
+	}
+
+	calls := 0
+	h := Chain(okHandler(&calls), mark("outer"), mark("inner"))
+	if _, _, err := h(context.Background(), nil, testParams{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(order, ",") != "outer,inner" || calls != 1 {
+		t.Errorf("unexpected order %v or calls %d", order, calls)
+	}
+}
+
+func TestWrap_Success(t *testing.T) {
+	deps, handled := newTestDeps(t)
+	calls := 0
+
+	var sawInfo bool
+	h := Wrap(deps, ToolSpec[testParams, string]{Name: "demo"},
+		func(ctx context.Context, req *mcp.CallToolRequest, in testParams) (*mcp.CallToolResult, string, error) {
+			sawInfo = RequestInfoFrom(ctx) != nil
+			return okHandler(&calls)(ctx, req, in)
+		})
+
+	result, out, err := h(context.Background(), nil, testParams{Name: "x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result == nil || out != "ok:x" {
+		t.Errorf("unexpected result %v / %q", result, out)
+	}
+	if !sawInfo {
+		t.Error("expected request info in handler context")
+	}
+	if len(*handled) != 0 {
+		t.Errorf("expected no handled errors, got %v", *handled)
+	}
+}
+
+func TestWrap_Validation(t *testing.T) {
+	deps, handled := newTestDeps(t)
+	deps.Validator.AddValidator("not_bad", func(v interface{}) error {
+		if v == "bad" {
+			return fmt.Errorf("bad value")
+		}
+		return nil
+	})
+	calls := 0
+
+	spec := ToolSpec[testParams, string]{
+		Name: "demo",
+		Validation: ValidationSpec{
+			{Field: "name", Rules: []string{"not_bad"}, Optional: true},
+			{Field: "items.code", Rules: []string{"not_empty"}, Sensitive: true},
+		},
+	}
+	h := Wrap(deps, spec, okHandler(&calls))
+
+	// Optional empty values are skipped
+	if _, _, err := h(context.Background(), nil, testParams{}); err != nil {
+		t.Fatalf("unexpected error for empty optional value: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		params testParams
+		field  string
+	}{
+		{name: "top-level field", params: testParams{Name: "bad"}, field: "name"},
+		{name: "nested list field", params: testParams{Items: []testItem{{Code: "x"}, {}}}, field: "items.code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := h(context.Background(), nil, tt.params)
+			if err == nil {
+				t.Fatal("expected validation error")
+			}
+			if !types.IsMCPError(err) || err.(types.MCPError).Category() != "validation" {
+				t.Errorf("expected validation MCPError, got %v", err)
+			}
+			details, _ := types.GetErrorDetails(err)["validation_error"].(map[string]interface{})
+			if got := details["field"]; got != tt.field {
+				t.Errorf("expected error for field %s, got %v", tt.field, got)
+			}
+		})
+	}
+
+	if calls != 1 {
+		t.Errorf("handler should not run after a validation failure, calls = %d", calls)
+	}
+	if len(*handled) != 2 {
+		t.Errorf("expected two handled errors, got %v", *handled)
+	}
+}
+
+func TestWrap_InvalidValidationSpec(t *testing.T) {
+	tests := []struct {
+		name string
+		spec ValidationSpec
+	}{
+		{name: "unknown field", spec: ValidationSpec{{Field: "nope", Rules: []string{"not_empty"}}}},
+		{name: "non-string field", spec: ValidationSpec{{Field: "items", Rules: []string{"not_empty"}}}},
+		{name: "unknown rule", spec: ValidationSpec{{Field: "name", Rules: []string{"no_such_rule"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps, _ := newTestDeps(t)
+			defer func() {
+				if recover() == nil {
+					t.Error("expected Wrap to panic")
+				}
+			}()
+			Wrap(deps, ToolSpec[testParams, string]{Name: "demo", Validation: tt.spec}, okHandler(new(int)))
+		})
+	}
+}
+
+func TestValidationSpec_WithOverrides(t *testing.T) {
+	spec := ValidationSpec{
+		{Field: "name", Rules: []string{"not_empty", "symbol_name"}},
+		{Field: "path", Rules: []string{"file_path"}, Optional: true},
+	}
+
+	got := spec.WithOverrides(map[string][]string{"path": {"not_empty", "file_path"}}, []string{"symbol_name"})
+
+	if strings.Join(got[0].Rules, ",") != "not_empty" {
+		t.Errorf("expected disabled rule to be dropped, got %v", got[0].Rules)
+	}
+	if strings.Join(got[1].Rules, ",") != "not_empty,file_path" || !got[1].Optional {
+		t.Errorf("expected overridden rules with flags kept, got %+v", got[1])
+	}
+	if strings.Join(spec[0].Rules, ",") != "not_empty,symbol_name" {
+		t.Errorf("original spec was modified: %v", spec[0].Rules)
+	}
+}
+
+func TestWrap_LatencyBudget(t *testing.T) {
+	deps, _ := newTestDeps(t)
+	deps.LatencyBudgets = map[string]time.Duration{"slow": time.Millisecond, "fast": time.Minute}
+
+	slow := func(context.Context, *mcp.CallToolRequest, testParams) (*mcp.CallToolResult, string, error) {
+		time.Sleep(5 * time.Millisecond)
+		return &mcp.CallToolResult{}, "", nil
+	}
+	for _, tool := range []string{"slow", "fast", "unbudgeted"} {
+		if _, _, err := Wrap(deps, ToolSpec[testParams, string]{Name: tool}, slow)(context.Background(), nil, testParams{}); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tool, err)
+		}
+	}
+
+	snapshot := deps.Metrics.Snapshot()
+	samples := snapshot.Counters["mcp_tool_latency_budget_exceeded_total"]
+	if len(samples) != 1 || samples[0].Labels["tool"] != "slow" || samples[0].Value != 1 {
+		t.Errorf("expected one overrun for the slow tool, got %+v", samples)
+	}
+}
+
+func TestWrap_ExecutionError(t *testing.T) {
+	deps, handled := newTestDeps(t)
+
+	spec := ToolSpec[testParams, string]{
+		Name:           "demo",
+		FailureMessage: func(p testParams) string { return "failed for " + p.Name },
+	}
+	var requestID string
+	h := Wrap(deps, spec, func(ctx context.Context, _ *mcp.CallToolRequest, _ testParams) (*mcp.CallToolResult, string, error) {
+		requestID = logging.RequestIDFromContext(ctx)
+		return nil, "partial", errors.New("boom")
+	})
+
+	result, out, err := h(context.Background(), nil, testParams{Name: "x"})
+	if err == nil || !strings.Contains(err.Error(), "failed for x") {
+		t.Fatalf("expected wrapped execution error, got %v", err)
+	}
+	if requestID == "" || types.GetErrorDetails(err)["request_id"] != requestID {
+		t.Errorf("expected the error to carry the request ID %q of the context, got %v", requestID, types.GetErrorDetails(err))
+	}
+	if result != nil || out != "" {
+		t.Errorf("expected zero results on error, got %v / %q", result, out)
+	}
+	if len(*handled) != 1 {
+		t.Errorf("expected one handled error, got %v", *handled)
+	}
+}
+
+func TestWrap_ResponseCache(t *testing.T) {
+	deps, _ := newTestDeps(t)
+	calls := 0
+
+	spec := ToolSpec[testParams, string]{
+		Name:  "demo",
+		Cache: cache.New[CachedResponse[string]](time.Minute, 10),
+		CacheKey: func(p testParams) (string, bool) {
+			return cache.Key(p.Name), p.Name != "uncached"
+		},
+	}
+	h := Wrap(deps, spec, okHandler(&calls))
+
+	first, _, err := h(context.Background(), nil, testParams{Name: "x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, out, err := h(context.Background(), nil, testParams{Name: "x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 || out != "ok:x" {
+		t.Errorf("expected cached response, got %q after %d calls", out, calls)
+	}
+	if first == second {
+		t.Error("expected each caller to receive its own result copy")
+	}
+
+	_, _, _ = h(context.Background(), nil, testParams{Name: "y"})
+	_, _, _ = h(context.Background(), nil, testParams{Name: "uncached"})
+	_, _, _ = h(context.Background(), nil, testParams{Name: "uncached"})
+	if calls != 4 {
+		t.Errorf("expected new and bypassed inputs to run the handler, calls = %d", calls)
+	}
+}
+
+func TestWrap_ResponseCacheSkipsErrors(t *testing.T) {
+	deps, _ := newTestDeps(t)
+	calls := 0
+
+	spec := ToolSpec[testParams, string]{
+		Name:     "demo",
+		Cache:    cache.New[CachedResponse[string]](time.Minute, 10),
+		CacheKey: func(p testParams) (string, bool) { return p.Name, true },
+	}
+	h := Wrap(deps, spec, func(context.Context, *mcp.CallToolRequest, testParams) (*mcp.CallToolResult, string, error) {
+		calls++
+		return nil, "", errors.New("boom")
+	})
+
+	_, _, _ = h(context.Background(), nil, testParams{Name: "x"})
+	_, _, _ = h(context.Background(), nil, testParams{Name: "x"})
+	if calls != 2 {
+		t.Errorf("expected failed responses not to be cached, calls = %d", calls)
+	}
+}
+
+func idempotentSpec() ToolSpec[testParams, string] {
+	return ToolSpec[testParams, string]{
+		Name:           "demo",
+		IdempotencyKey: func(p testParams) string { return p.Key },
+	}
+}
+
+func TestWrap_Idempotency(t *testing.T) {
+	deps, handled := newTestDeps(t)
+	deps.Idempotency = NewIdempotencyStore(time.Minute, 10)
+	calls := 0
+	h := Wrap(deps, idempotentSpec(), okHandler(&calls))
+
+	first, _, err := h(context.Background(), nil, testParams{Name: "x", Key: "k1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, out, err := h(context.Background(), nil, testParams{Name: "x", Key: "k1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 || out != "ok:x" {
+		t.Errorf("expected the stored result, got %q after %d calls", out, calls)
+	}
+	if first == second {
+		t.Error("expected each caller to receive its own result copy")
+	}
+
+	_, _, _ = h(context.Background(), nil, testParams{Name: "x", Key: "k2"})
+	_, _, _ = h(context.Background(), nil, testParams{Name: "x"})
+	_, _, _ = h(context.Background(), nil, testParams{Name: "x"})
+	if calls != 4 {
+		t.Errorf("expected new keys and calls without a key to run the handler, calls = %d", calls)
+	}
+
+	_, _, err = h(context.Background(), nil, testParams{Name: "y", Key: "k1"})
+	if types.GetErrorCode(err) != types.NewValidationError("").Code() {
+		t.Fatalf("expected a validation error for a reused key, got %v", err)
+	}
+	if calls != 4 || len(*handled) != 1 {
+		t.Errorf("expected the reused key to be rejected before running, calls = %d, handled = %v", calls, *handled)
+	}
+}
+
+func TestWrap_IdempotencyScopedToClient(t *testing.T) {
+	deps, _ := newTestDeps(t)
+	deps.Idempotency = NewIdempotencyStore(time.Minute, 10)
+	calls := 0
+	h := Wrap(deps, idempotentSpec(), okHandler(&calls))
+
+	first := ratelimit.WithClientID(context.Background(), "conn-1")
+	second := ratelimit.WithClientID(context.Background(), "conn-2")
+	if _, out, err := h(first, nil, testParams{Name: "x", Key: "k"}); err != nil || out != "ok:x" {
+		t.Fatalf("unexpected result %q, %v", out, err)
+	}
+
+	// The same key from another client is a different call, with its own
+	// parameters
+	if _, out, err := h(second, nil, testParams{Name: "y", Key: "k"}); err != nil || out != "ok:y" {
+		t.Fatalf("expected the second client's own result, got %q, %v", out, err)
+	}
+	if calls != 2 {
+		t.Errorf("expected each client to run the handler, calls = %d", calls)
+	}
+
+	if _, out, _ := h(first, nil, testParams{Name: "x", Key: "k"}); out != "ok:x" || calls != 2 {
+		t.Errorf("expected the first client's stored result, got %q after %d calls", out, calls)
+	}
+	if _, out, _ := h(second, nil, testParams{Name: "y", Key: "k"}); out != "ok:y" || calls != 2 {
+		t.Errorf("expected the second client's stored result, got %q after %d calls", out, calls)
+	}
+}
+
+func TestWrap_IdempotencySkipsErrors(t *testing.T) {
+	deps, _ := newTestDeps(t)
+	deps.Idempotency = NewIdempotencyStore(time.Minute, 10)
+	calls := 0
+	h := Wrap(deps, idempotentSpec(), func(context.Context, *mcp.CallToolRequest, testParams) (*mcp.CallToolResult, string, error) {
+		calls++
+		if calls == 1 {
+			return nil, "", errors.New("boom")
+		}
+		return &mcp.CallToolResult{}, "ok", nil
+	})
+
+	if _, _, err := h(context.Background(), nil, testParams{Key: "k"}); err == nil {
+		t.Fatal("expected the first call to fail")
+	}
+	if _, out, err := h(context.Background(), nil, testParams{Key: "k"}); err != nil || out != "ok" {
+		t.Fatalf("expected a failed call not to be stored, got %q, %v", out, err)
+	}
+	if _, _, _ = h(context.Background(), nil, testParams{Key: "k"}); calls != 2 {
+		t.Errorf("expected the successful result to be stored, calls = %d", calls)
+	}
+}
+
+func TestWrap_IdempotencyWaitsForInFlightCall(t *testing.T) {
+	deps, _ := newTestDeps(t)
+	deps.Idempotency = NewIdempotencyStore(time.Minute, 10)
+
+	started, finish := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+	h := Wrap(deps, idempotentSpec(), func(context.Context, *mcp.CallToolRequest, testParams) (*mcp.CallToolResult, string, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-finish
+		}
+		return &mcp.CallToolResult{}, "done", nil
+	})
+
+	go func() { _, _, _ = h(context.Background(), nil, testParams{Key: "k"}) }()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := h(ctx, nil, testParams{Key: "k"}); err == nil {
+		t.Fatal("expected the duplicate call to give up waiting when its context ends")
+	}
+
+	time.AfterFunc(10*time.Millisecond, func() { close(finish) })
+	_, out, err := h(context.Background(), nil, testParams{Key: "k"})
+	if err != nil || out != "done" {
+		t.Fatalf("expected the in-flight result, got %q, %v", out, err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected the handler to run once, ran %d times", calls.Load())
+	}
+}
+
+func TestWrap_SpoolLargeInput(t *testing.T) {
+	deps, _ := newTestDeps(t)
+	deps.LargeInputThreshold = 8
+
+	spec := ToolSpec[testParams, string]{
+		Name: "demo",
+		LargeInput: func(p *testParams) LargeInput {
+			return LargeInput{Content: &p.Name, File: &p.File, Name: "input.go"}
+		},
+	}
+	var seen testParams
+	var content string
+	h := Wrap(deps, spec, func(_ context.Context, _ *mcp.CallToolRequest, in testParams) (*mcp.CallToolResult, string, error) {
+		seen = in
+		if in.File != "" {
+			data, err := os.ReadFile(in.File)
+			if err != nil {
+				return nil, "", err
+			}
+			content = string(data)
+		}
+		return &mcp.CallToolResult{}, "", nil
+	})
+
+	if _, _, err := h(context.Background(), nil, testParams{Name: "short"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seen.Name != "short" || seen.File != "" {
+		t.Errorf("expected a small input to stay in memory, got %+v", seen)
+	}
+
+	if _, _, err := h(context.Background(), nil, testParams{Name: "package large"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seen.Name != "" || content != "package large" {
+		t.Errorf("expected the large input to be read from its file, got %+v with content %q", seen, content)
+	}
+	if _, err := os.Stat(seen.File); !os.IsNotExist(err) {
+		t.Errorf("expected the spooled file to be removed after the call, got %v", err)
+	}
+}
+
+func TestWrap_CircuitBreakerOpen(t *testing.T) {
+	deps, _ := newTestDeps(t)
+	cb := circuitbreaker.NewCircuitBreaker("demo", &circuitbreaker.Config{
+		MaxFailures:         1,
+		Timeout:             time.Minute,
+		MaxHalfOpenRequests: 1,
+	})
+
+	calls := 0
+	h := Wrap(deps, ToolSpec[testParams, string]{Name: "demo", CircuitBreaker: cb},
+		func(context.Context, *mcp.CallToolRequest, testParams) (*mcp.CallToolResult, string, error) {
+			calls++
+			return nil, "", errors.New("boom")
+		})
+
+	_, _, _ = h(context.Background(), nil, testParams{})
+	_, _, err := h(context.Background(), nil, testParams{})
+	if err == nil || !types.IsMCPError(err) || err.(types.MCPError).Category() != "circuit_breaker" {
+		t.Fatalf("expected circuit breaker MCPError, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("handler should not run while the circuit is open, calls = %d", calls)
+	}
+}
+
+func TestWrap_CircuitBreakerIgnoresInputErrors(t *testing.T) {
+	deps, _ := newTestDeps(t)
+	cb := circuitbreaker.NewCircuitBreaker("demo", &circuitbreaker.Config{
+		MaxFailures:         1,
+		Timeout:             time.Minute,
+		MaxHalfOpenRequests: 1,
+	})
+
+	calls := 0
+	h := Wrap(deps, ToolSpec[testParams, string]{Name: "demo", CircuitBreaker: cb},
+		func(context.Context, *mcp.CallToolRequest, testParams) (*mcp.CallToolResult, string, error) {
+			calls++
+			return nil, "", types.NewKindError(types.KindParseFailure, "failed to parse Go code: expected 'package'")
+		})
+
+	for range 3 {
+		if _, _, err := h(context.Background(), nil, testParams{}); types.GetErrorCode(err) == "CIRCUIT_BREAKER_OPEN" {
+			t.Fatalf("expected malformed input not to open the circuit, got %v", err)
+		}
+	}
+	if calls != 3 || !cb.IsClosed() {
+		t.Errorf("expected every call to run with the circuit closed, calls = %d, state = %s", calls, cb.State())
+	}
+}
+
+func TestWrap_QueueBusy(t *testing.T) {
+	deps, handled := newTestDeps(t)
+	limiter, err := queue.NewLimiter("demo", queue.Config{MaxConcurrent: 1})
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v", err)
+	}
+
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	h := Wrap(deps, ToolSpec[testParams, string]{Name: "demo", Queue: limiter},
+		func(context.Context, *mcp.CallToolRequest, testParams) (*mcp.CallToolResult, string, error) {
+			close(entered)
+			<-unblock
+			return &mcp.CallToolResult{}, "done", nil
+		})
+
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := h(context.Background(), nil, testParams{})
+		done <- err
+	}()
+	<-entered
+
+	_, _, err = h(context.Background(), nil, testParams{})
+	mcpErr, ok := err.(types.MCPError)
+	if !ok || mcpErr.Code() != "SERVER_BUSY" {
+		t.Fatalf("expected SERVER_BUSY MCPError, got %v", err)
+	}
+	if mcpErr.Details()["retry_after_ms"] == nil || mcpErr.Details()["tool"] != "demo" {
+		t.Errorf("expected retry_after_ms and tool details, got %v", mcpErr.Details())
+	}
+	if len(*handled) != 1 {
+		t.Errorf("expected the rejection to reach the error handler, got %v", *handled)
+	}
+
+	close(unblock)
+	if err := <-done; err != nil {
+		t.Errorf("expected the running call to succeed, got %v", err)
+	}
+	if limiter.Active() != 0 {
+		t.Errorf("expected the slot to be released, got %d active", limiter.Active())
+	}
+}
+
+func TestWrap_TimeoutAndRetry(t *testing.T) {
+	deps, _ := newTestDeps(t)
+	retryer := retry.NewRetryer(&retry.Config{
+		MaxAttempts:  3,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     time.Millisecond,
+		Multiplier:   1,
+		Strategy:     "constant",
+	})
+
+	calls := 0
+	var hadDeadline bool
+	spec := ToolSpec[testParams, string]{
+		Name:    "demo",
+		Timeout: FixedTimeout[testParams](time.Second),
+		Retry:   retry.NewRetryWrapper("demo", retryer, deps.Logger),
+	}
+	h := Wrap(deps, spec, func(ctx context.Context, _ *mcp.CallToolRequest, _ testParams) (*mcp.CallToolResult, string, error) {
+		calls++
+		_, hadDeadline = ctx.Deadline()
+		if calls < 3 {
+			return nil, "", errors.New("transient")
+		}
+		return &mcp.CallToolResult{}, "done", nil
+	})
+
+	_, out, err := h(context.Background(), nil, testParams{})
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if out != "done" || calls != 3 {
+		t.Errorf("unexpected out %q after %d calls", out, calls)
+	}
+	if !hadDeadline {
+		t.Error("expected handler context to carry a deadline")
+	}
+}
+
+func TestWrap_RetryExhaustedDetails(t *testing.T) {
+	deps, _ := newTestDeps(t)
+	retryer := retry.NewRetryer(&retry.Config{
+		MaxAttempts:  3,
+		InitialDelay: 5 * time.Millisecond,
+		MaxDelay:     5 * time.Millisecond,
+		Multiplier:   1,
+		Strategy:     "constant",
+	})
+
+	spec := ToolSpec[testParams, string]{
+		Name:  "demo",
+		Retry: retry.NewRetryWrapper("demo", retryer, deps.Logger),
+	}
+	h := Wrap(deps, spec, func(context.Context, *mcp.CallToolRequest, testParams) (*mcp.CallToolResult, string, error) {
+		return nil, "", errors.New("still failing")
+	})
+
+	_, _, err := h(context.Background(), nil, testParams{})
+	details := types.GetErrorDetails(err)
+	if details == nil {
+		t.Fatalf("expected an MCP error, got %v", err)
+	}
+	if details["attempts"] != uint(3) || details["total_delay_ms"] != int64(10) {
+		t.Errorf("unexpected retry details %v", details)
+	}
+}
+
+// newTestSession connects an in-memory client to a new server and returns
+// the server side of the session
+func newTestSession(t *testing.T) *mcp.ServerSession {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v0.0.1"}, nil)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, nil)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+
+	ss, err := server.Connect(context.Background(), serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server Connect() error = %v", err)
+	}
+	cs, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client Connect() error = %v", err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return ss
+}
+
+func TestWrap_SessionDefaults(t *testing.T) {
+	deps, _ := newTestDeps(t)
+	deps.Sessions = session.NewStore()
+	deps.Validator.AddValidator("not_bad", func(v interface{}) error {
+		if v == "bad" {
+			return fmt.Errorf("bad value")
+		}
+		return nil
+	})
+	calls := 0
+	spec := ToolSpec[testParams, string]{
+		Name:       "demo",
+		Validation: ValidationSpec{{Field: "name", Rules: []string{"not_bad"}}},
+		SessionDefaults: func(in *testParams, d session.Defaults) {
+			if in.Name == "" {
+				in.Name = d.Language
+			}
+		},
+	}
+	h := Wrap(deps, spec, okHandler(&calls))
+
+	configured := newTestSession(t)
+	other := newTestSession(t)
+	deps.Sessions.Configure(configured, session.ConfigureParams{Language: "es"})
+
+	_, out, err := h(context.Background(), &mcp.CallToolRequest{Session: configured}, testParams{})
+	if err != nil || out != "ok:es" {
+		t.Errorf("expected the session default to fill the call, got %q, %v", out, err)
+	}
+	_, out, _ = h(context.Background(), &mcp.CallToolRequest{Session: configured}, testParams{Name: "en"})
+	if out != "ok:en" {
+		t.Errorf("expected parameters of the call to win over defaults, got %q", out)
+	}
+	_, out, _ = h(context.Background(), &mcp.CallToolRequest{Session: other}, testParams{})
+	if out != "ok:" {
+		t.Errorf("expected no defaults for another session, got %q", out)
+	}
+
+	// Defaults pass the same validation as parameters of the call
+	deps.Sessions.Configure(configured, session.ConfigureParams{Language: "bad"})
+	if _, _, err := h(context.Background(), &mcp.CallToolRequest{Session: configured}, testParams{}); err == nil {
+		t.Error("expected a validation error for an invalid default")
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestWrap_Artifacts(t *testing.T) {
+	deps, _ := newTestDeps(t)
+	deps.Artifacts = artifact.NewStore(time.Minute, 10, 8)
+	calls := 0
+	spec := ToolSpec[testParams, string]{
+		Name: "demo",
+		Artifact: func(in testParams, out string) ArtifactView[string] {
+			return ArtifactView[string]{Content: out, MIMEType: "text/plain", Summary: "summary of " + in.Name, Out: "short"}
+		},
+	}
+	h := Wrap(deps, spec, func(ctx context.Context, req *mcp.CallToolRequest, in testParams) (*mcp.CallToolResult, string, error) {
+		calls++
+		return &mcp.CallToolResult{Content: []mcp.Content{
+			&mcp.TextContent{Text: in.Name},
+			&mcp.ResourceLink{URI: "file:///tmp/x_test.go", Name: "x_test.go"},
+		}}, "ok:" + in.Name, nil
+	})
+
+	// Small outputs are returned inline
+	result, out, err := h(context.Background(), nil, testParams{Name: "x"})
+	if err != nil || out != "ok:x" || len(result.Content) != 2 {
+		t.Fatalf("expected the inline result, got %v, %q, %v", result, out, err)
+	}
+
+	result, out, err = h(context.Background(), nil, testParams{Name: "a long output"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	id, _ := result.Meta["artifact_id"].(string)
+	a, ok := deps.Artifacts.Get(id)
+	if !ok || a.Content != "ok:a long output" || a.Tool != "demo" {
+		t.Fatalf("expected the full output in artifact %q, got %+v", id, a)
+	}
+	if out != "short" {
+		t.Errorf("expected the summary output, got %q", out)
+	}
+	if len(result.Content) != 3 {
+		t.Fatalf("expected the summary, the file link and the artifact link, got %d blocks", len(result.Content))
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.HasPrefix(text, "summary of a long output") || !strings.Contains(text, id) {
+		t.Errorf("unexpected summary text %q", text)
+	}
+	if link, ok := result.Content[1].(*mcp.ResourceLink); !ok || link.Name != "x_test.go" {
+		t.Errorf("expected the file link to be kept, got %#v", result.Content[1])
+	}
+	if link, ok := result.Content[2].(*mcp.ResourceLink); !ok || link.URI != artifact.URI(id) {
+		t.Errorf("expected a link to the artifact, got %#v", result.Content[2])
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
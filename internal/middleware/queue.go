@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-go-assistant/internal/queue"
+)
+
+// Queue bounds concurrent executions of the tool with l. Calls beyond the
+// limit wait in l's queue and fail with a queue.BusyError when it is full or
+// the wait exceeds its maximum.
+func Queue[In, Out any](deps *Dependencies, tool string, l *queue.Limiter) Middleware[In, Out] {
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			start := time.Now()
+			release, err := l.Acquire(ctx)
+			if err != nil {
+				var zero Out
+				return nil, zero, err
+			}
+			defer release()
+
+			deps.Metrics.RecordQueueWait(tool, time.Since(start))
+			return next(ctx, req, in)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-go-assistant/internal/spool"
+)
+
+// LargeInput points at a tool's potentially large string parameter and the
+// parameter that receives the path of its spooled copy
+type LargeInput struct {
+	Content *string
+	File    *string
+	Name    string // File name used for the spooled copy, e.g. "input.go"
+}
+
+// SpoolLargeInput writes the parameter located by locate to a temporary file
+// when it exceeds the configured threshold, replacing it with the file's
+// path for the rest of the call. The file is removed when the call returns.
+// Inputs are kept in memory if spooling fails.
+func SpoolLargeInput[In, Out any](deps *Dependencies, tool string, locate func(in *In) LargeInput) Middleware[In, Out] {
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			input := locate(&in)
+			size := len(*input.Content)
+			if deps.LargeInputThreshold <= 0 || size <= deps.LargeInputThreshold {
+				return next(ctx, req, in)
+			}
+
+			dir, err := spool.New()
+			if err != nil {
+				deps.logger(ctx).WarnEvent().Err(err).Str("tool", tool).Msg("keeping large input in memory")
+				return next(ctx, req, in)
+			}
+			defer func() { _ = dir.Remove() }()
+
+			path, err := dir.Write(input.Name, *input.Content)
+			if err != nil {
+				deps.logger(ctx).WarnEvent().Err(err).Str("tool", tool).Msg("keeping large input in memory")
+				return next(ctx, req, in)
+			}
+			*input.File, *input.Content = path, ""
+			deps.logger(ctx).DebugEvent().Str("tool", tool).Int("bytes", size).Msg("spooled large input to a temporary file")
+
+			return next(ctx, req, in)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-go-assistant/internal/validations"
+)
+
+// FieldRules declares the validation rules applied to one input field
+type FieldRules struct {
+	Field     string   // JSON field name; fields inside lists use dots, e.g. "items.go_code"
+	Rules     []string // Rule names resolved by the validator, applied in order
+	Optional  bool     // Skip empty values
+	Sensitive bool     // Log the field name instead of the value, e.g. for source code
+}
+
+// ValidationSpec is the ordered list of field rules for a tool
+type ValidationSpec []FieldRules
+
+// WithOverrides returns a copy of the spec where fields present in
+// overrides use the configured rules and disabled rules are dropped
+func (s ValidationSpec) WithOverrides(overrides map[string][]string, disabled []string) ValidationSpec {
+	isDisabled := make(map[string]bool, len(disabled))
+	for _, rule := range disabled {
+		isDisabled[rule] = true
+	}
+
+	out := make(ValidationSpec, 0, len(s))
+	for _, fr := range s {
+		rules := fr.Rules
+		if override, ok := overrides[fr.Field]; ok {
+			rules = override
+		}
+
+		kept := make([]string, 0, len(rules))
+		for _, rule := range rules {
+			if !isDisabled[rule] {
+				kept = append(kept, rule)
+			}
+		}
+		fr.Rules = kept
+		out = append(out, fr)
+	}
+	return out
+}
+
+// Rules returns every rule name referenced by the spec
+func (s ValidationSpec) Rules() []string {
+	seen := make(map[string]bool)
+	var rules []string
+	for _, fr := range s {
+		for _, rule := range fr.Rules {
+			if !seen[rule] {
+				seen[rule] = true
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules
+}
+
+// Check reports fields in the spec that do not exist on the input type and
+// rules that v does not know
+func (s ValidationSpec) Check(inType reflect.Type, v *validations.Validator) error {
+	for _, fr := range s {
+		if _, err := fieldPath(inType, fr.Field); err != nil {
+			return err
+		}
+	}
+	for _, rule := range s.Rules() {
+		if v == nil || !v.HasRule(rule) {
+			return fmt.Errorf("unknown validation rule %q", rule)
+		}
+	}
+	return nil
+}
+
+// Validate runs the spec against the input before calling the next handler.
+// Every rule is logged and recorded separately so metrics are per rule.
+// An invalid spec is a programming error and panics at registration.
+func Validate[In, Out any](deps *Dependencies, tool string, spec ValidationSpec) Middleware[In, Out] {
+	if err := spec.Check(reflect.TypeFor[In](), deps.Validator); err != nil {
+		panic(fmt.Sprintf("invalid validation spec for %s: %v", tool, err))
+	}
+
+	return func(next ToolFunc[In, Out]) ToolFunc[In, Out] {
+		return func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+			for _, fr := range spec {
+				if err := deps.validateField(ctx, tool, fr, fieldValues(reflect.ValueOf(in), fr.Field)); err != nil {
+					var zero Out
+					return nil, zero, err
+				}
+			}
+			return next(ctx, req, in)
+		}
+	}
+}
+
+// validateField applies each rule to each value, emitting the validation
+// log and metric events, and returns a wrapped error on the first failure
+func (d *Dependencies) validateField(ctx context.Context, tool string, fr FieldRules, values []string) error {
+	log := d.logger(ctx)
+	for _, value := range values {
+		if fr.Optional && value == "" {
+			continue
+		}
+
+		for _, rule := range fr.Rules {
+			log.LogValidationAttempt(fr.Field, rule, tool)
+			d.Metrics.RecordValidationAttempt(rule, tool)
+			err := d.Validator.ValidateToolField(tool, fr.Field, value, rule)
+			if validations.IsWarning(err) {
+				log.LogValidationWarning(fr.Field, rule, err.(*validations.ValidationError).Message, tool)
+				continue
+			}
+			if err != nil {
+				logged := value
+				if fr.Sensitive {
+					logged = fr.Field
+				}
+				log.LogValidationError(fr.Field, rule, logged, tool)
+				d.Metrics.RecordValidationFailure(rule, tool)
+				return d.fail(ctx, WrapValidationError(err, tool), tool)
+			}
+			log.LogValidationSuccess(fr.Field, rule, tool)
+		}
+	}
+	return nil
+}
+
+// fieldPath resolves a dotted JSON field path to struct field indexes,
+// descending into slice elements
+func fieldPath(t reflect.Type, path string) ([][]int, error) {
+	var indexes [][]int
+	for _, name := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("field %q: %s is not a struct", path, t)
+		}
+
+		field, ok := fieldByJSONName(t, name)
+		if !ok {
+			return nil, fmt.Errorf("field %q: %s has no field %q", path, t, name)
+		}
+		indexes = append(indexes, field.Index)
+		t = field.Type
+	}
+
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.String {
+		return nil, fmt.Errorf("field %q is %s, not a string", path, t)
+	}
+	return indexes, nil
+}
+
+// fieldByJSONName finds the struct field whose JSON name is name
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == name || (tag == "" && strings.EqualFold(f.Name, name)) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// fieldValues collects the string values at a dotted JSON field path,
+// flattening slices along the way
+func fieldValues(v reflect.Value, path string) []string {
+	values := []reflect.Value{v}
+	for _, name := range strings.Split(path, ".") {
+		var next []reflect.Value
+		for _, cur := range flatten(values) {
+			if cur.Kind() != reflect.Struct {
+				continue
+			}
+			if field, ok := fieldByJSONName(cur.Type(), name); ok {
+				next = append(next, cur.FieldByIndex(field.Index))
+			}
+		}
+		values = next
+	}
+
+	var out []string
+	for _, cur := range flatten(values) {
+		if cur.Kind() == reflect.String {
+			out = append(out, cur.String())
+		}
+	}
+	return out
+}
+
+// flatten dereferences pointers and expands slices into their elements
+func flatten(values []reflect.Value) []reflect.Value {
+	var out []reflect.Value
+	for _, v := range values {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				break
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Slice:
+			for i := 0; i < v.Len(); i++ {
+				out = append(out, flatten([]reflect.Value{v.Index(i)})...)
+			}
+		case reflect.Pointer:
+			// Nil pointer; nothing to validate
+		default:
+			out = append(out, v)
+		}
+	}
+	return out
+}
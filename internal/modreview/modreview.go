@@ -0,0 +1,373 @@
+package modreview
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mcp-go-assistant/internal/logging"
+)
+
+// modFile mirrors the JSON emitted by `go mod edit -json`
+type modFile struct {
+	Module struct {
+		Path string `json:"Path"`
+	} `json:"Module"`
+	Go        string       `json:"Go"`
+	Toolchain string       `json:"Toolchain"`
+	Require   []modRequire `json:"Require"`
+	Replace   []modReplace `json:"Replace"`
+}
+
+type modRequire struct {
+	Path     string `json:"Path"`
+	Version  string `json:"Version"`
+	Indirect bool   `json:"Indirect"`
+}
+
+type modVersion struct {
+	Path    string `json:"Path"`
+	Version string `json:"Version"`
+}
+
+type modReplace struct {
+	Old modVersion `json:"Old"`
+	New modVersion `json:"New"`
+}
+
+// listedModule mirrors the JSON emitted by `go list -m -json -retracted`
+type listedModule struct {
+	Path      string   `json:"Path"`
+	Version   string   `json:"Version"`
+	Retracted []string `json:"Retracted"`
+	Main      bool     `json:"Main"`
+}
+
+// majorSuffixRegex matches a /vN major version suffix on a module path
+var majorSuffixRegex = regexp.MustCompile(`/v[0-9]+$`)
+
+// ReviewModule analyzes go.mod and go.sum in the working directory
+func ReviewModule(ctx context.Context, params ModReviewParams) (*ModReviewResult, error) {
+	if params.WorkingDir == "" {
+		return nil, fmt.Errorf("working_dir parameter is required")
+	}
+
+	goModPath := filepath.Join(params.WorkingDir, "go.mod")
+	if _, err := os.Stat(goModPath); err != nil {
+		return nil, fmt.Errorf("go.mod not found in %s", params.WorkingDir)
+	}
+
+	output, err := runGo(ctx, params.WorkingDir, "mod", "edit", "-json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod: %v", err)
+	}
+
+	var mod modFile
+	if err := json.Unmarshal(output, &mod); err != nil {
+		return nil, fmt.Errorf("failed to decode go.mod: %v", err)
+	}
+
+	result := &ModReviewResult{
+		Module:    mod.Module.Path,
+		GoVersion: mod.Go,
+		Toolchain: mod.Toolchain,
+		Requires:  len(mod.Require),
+		Findings:  []Finding{},
+	}
+
+	localVersion := ""
+	if out, err := runGo(ctx, params.WorkingDir, "env", "GOVERSION"); err == nil {
+		localVersion = strings.TrimSpace(string(out))
+	}
+
+	checkToolchain(mod, localVersion, result)
+	checkLocalReplaces(mod, result)
+	checkDuplicateMajors(mod, result)
+	checkGoSum(params.WorkingDir, mod, result)
+
+	if !params.SkipNetwork {
+		checkRetracted(ctx, params.WorkingDir, result)
+		checkTidy(ctx, params.WorkingDir, result)
+	}
+
+	sortFindings(result.Findings)
+	result.Summary = summarize(result)
+
+	return result, nil
+}
+
+// checkToolchain reports go directive and toolchain version mismatches
+func checkToolchain(mod modFile, localVersion string, result *ModReviewResult) {
+	if mod.Go == "" {
+		result.Findings = append(result.Findings, Finding{
+			Check:      "go-directive",
+			Severity:   "medium",
+			Message:    "go.mod has no go directive",
+			Suggestion: "Add a go directive declaring the minimum Go version, e.g. `go mod edit -go=1.23`",
+		})
+		return
+	}
+
+	if mod.Toolchain != "" && compareGoVersions(mod.Toolchain, mod.Go) < 0 {
+		result.Findings = append(result.Findings, Finding{
+			Check:      "toolchain-mismatch",
+			Severity:   "high",
+			Message:    fmt.Sprintf("toolchain %s is older than the go directive %s", mod.Toolchain, mod.Go),
+			Suggestion: fmt.Sprintf("Raise the toolchain to at least go%s or remove the toolchain line", mod.Go),
+		})
+	}
+
+	if localVersion != "" && compareGoVersions(localVersion, mod.Go) < 0 {
+		result.Findings = append(result.Findings, Finding{
+			Check:      "toolchain-mismatch",
+			Severity:   "low",
+			Message:    fmt.Sprintf("local Go %s is older than the go directive %s", localVersion, mod.Go),
+			Suggestion: "Upgrade the local toolchain or allow GOTOOLCHAIN to download a newer one",
+		})
+	}
+}
+
+// checkLocalReplaces reports replace directives that point at filesystem paths
+func checkLocalReplaces(mod modFile, result *ModReviewResult) {
+	for _, r := range mod.Replace {
+		if !isLocalPath(r.New.Path) {
+			continue
+		}
+		result.Findings = append(result.Findings, Finding{
+			Check:      "local-replace",
+			Severity:   "high",
+			Module:     r.Old.Path,
+			Message:    fmt.Sprintf("replace directive points %s at local path %s", r.Old.Path, r.New.Path),
+			Suggestion: "Remove the replace before releasing, or use a go.work file for local development",
+		})
+	}
+}
+
+// checkDuplicateMajors reports modules required at more than one major version
+func checkDuplicateMajors(mod modFile, result *ModReviewResult) {
+	majors := make(map[string][]string)
+	for _, req := range mod.Require {
+		base := majorSuffixRegex.ReplaceAllString(req.Path, "")
+		majors[base] = append(majors[base], req.Path)
+	}
+
+	for base, paths := range majors {
+		if len(paths) < 2 {
+			continue
+		}
+		sort.Strings(paths)
+		result.Findings = append(result.Findings, Finding{
+			Check:      "duplicate-major",
+			Severity:   "medium",
+			Module:     base,
+			Message:    fmt.Sprintf("multiple major versions required: %s", strings.Join(paths, ", ")),
+			Suggestion: "Migrate to a single major version to avoid duplicate state and larger binaries",
+		})
+	}
+}
+
+// checkGoSum reports a missing go.sum when the module has requirements
+func checkGoSum(dir string, mod modFile, result *ModReviewResult) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.sum"))
+	if err != nil {
+		if len(mod.Require) > 0 {
+			result.Findings = append(result.Findings, Finding{
+				Check:      "go-sum",
+				Severity:   "high",
+				Message:    "go.sum is missing while go.mod has requirements",
+				Suggestion: "Run `go mod tidy` and commit go.sum",
+			})
+		}
+		return
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			result.GoSumEntries++
+		}
+	}
+}
+
+// checkRetracted reports required module versions retracted by their authors
+func checkRetracted(ctx context.Context, dir string, result *ModReviewResult) {
+	output, err := runGo(ctx, dir, "list", "-m", "-json", "-retracted", "all")
+	if err != nil {
+		result.Notes = append(result.Notes, "retraction check skipped: "+firstLine(err.Error()))
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for decoder.More() {
+		var m listedModule
+		if err := decoder.Decode(&m); err != nil {
+			result.Notes = append(result.Notes, "retraction check incomplete: "+err.Error())
+			return
+		}
+		if m.Main || len(m.Retracted) == 0 {
+			continue
+		}
+		result.Findings = append(result.Findings, Finding{
+			Check:      "retracted-version",
+			Severity:   "high",
+			Module:     m.Path,
+			Message:    fmt.Sprintf("%s@%s is retracted: %s", m.Path, m.Version, strings.Join(m.Retracted, "; ")),
+			Suggestion: fmt.Sprintf("Upgrade with `go get %s@latest`", m.Path),
+		})
+	}
+}
+
+// checkTidy reports requirements that `go mod tidy` would remove or add
+func checkTidy(ctx context.Context, dir string, result *ModReviewResult) {
+	cmd := exec.CommandContext(ctx, "go", "mod", "tidy", "-diff")
+	cmd.Dir = dir
+	cmd.Env = logging.CommandEnv(ctx, nil)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return
+	}
+	if !strings.Contains(string(output), "diff current/go.mod") {
+		result.Notes = append(result.Notes, "tidy check skipped: "+firstLine(strings.TrimSpace(string(output))))
+		return
+	}
+
+	removed, added := parseTidyDiff(string(output))
+	for _, path := range removed {
+		result.Findings = append(result.Findings, Finding{
+			Check:      "unused-require",
+			Severity:   "medium",
+			Module:     path,
+			Message:    fmt.Sprintf("%s is required but not used", path),
+			Suggestion: "Run `go mod tidy` to remove unused requirements",
+		})
+	}
+	for _, path := range added {
+		result.Findings = append(result.Findings, Finding{
+			Check:      "missing-require",
+			Severity:   "high",
+			Module:     path,
+			Message:    fmt.Sprintf("%s is used but not required", path),
+			Suggestion: "Run `go mod tidy` to add missing requirements",
+		})
+	}
+}
+
+// parseTidyDiff extracts module paths removed from and added to go.mod in
+// the unified diff printed by `go mod tidy -diff`
+func parseTidyDiff(diff string) (removed, added []string) {
+	inGoMod := false
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff ") {
+			inGoMod = strings.HasSuffix(line, "/go.mod")
+			continue
+		}
+		if !inGoMod || strings.HasPrefix(line, "---") || strings.HasPrefix(line, "+++") {
+			continue
+		}
+		if len(line) == 0 || (line[0] != '-' && line[0] != '+') {
+			continue
+		}
+
+		fields := strings.Fields(strings.TrimPrefix(line[1:], "require"))
+		if len(fields) < 2 || !strings.HasPrefix(fields[1], "v") {
+			continue
+		}
+		if line[0] == '-' {
+			removed = append(removed, fields[0])
+		} else {
+			added = append(added, fields[0])
+		}
+	}
+	return removed, added
+}
+
+// isLocalPath reports whether a replacement target is a filesystem path
+func isLocalPath(path string) bool {
+	return strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../") ||
+		filepath.IsAbs(path) || path == "." || path == ".."
+}
+
+// compareGoVersions compares Go versions such as "1.23", "1.23.0" or
+// "go1.22.1", returning -1, 0 or 1. Pre-release suffixes are ignored.
+func compareGoVersions(a, b string) int {
+	pa, pb := parseGoVersion(a), parseGoVersion(b)
+	for i := 0; i < 3; i++ {
+		if pa[i] != pb[i] {
+			if pa[i] < pb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// parseGoVersion splits a Go version into major, minor and patch numbers
+func parseGoVersion(v string) [3]int {
+	var parts [3]int
+	v = strings.TrimPrefix(v, "go")
+	for i, field := range strings.SplitN(v, ".", 3) {
+		// Drop suffixes such as "rc1" or "-X:nodwarf5"
+		end := 0
+		for end < len(field) && field[end] >= '0' && field[end] <= '9' {
+			end++
+		}
+		parts[i], _ = strconv.Atoi(field[:end])
+	}
+	return parts
+}
+
+// sortFindings orders findings by severity, then check, then module
+func sortFindings(findings []Finding) {
+	rank := map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if rank[findings[i].Severity] != rank[findings[j].Severity] {
+			return rank[findings[i].Severity] < rank[findings[j].Severity]
+		}
+		if findings[i].Check != findings[j].Check {
+			return findings[i].Check < findings[j].Check
+		}
+		return findings[i].Module < findings[j].Module
+	})
+}
+
+// summarize builds a one-line summary of the review
+func summarize(result *ModReviewResult) string {
+	if len(result.Findings) == 0 {
+		return fmt.Sprintf("%s: go.mod looks healthy (%d requirements)", result.Module, result.Requires)
+	}
+	return fmt.Sprintf("%s: found %d issues in go.mod (%d requirements)", result.Module, len(result.Findings), result.Requires)
+}
+
+// runGo runs a go subcommand in dir and returns its standard output
+func runGo(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	cmd.Env = logging.CommandEnv(ctx, nil)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("go %s failed: %v\nOutput: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("go %s failed: %v", args[0], err)
+	}
+	return output, nil
+}
+
+// firstLine returns the first line of s
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
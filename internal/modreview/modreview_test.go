@@ -0,0 +1,159 @@
+package modreview
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+func writeGoMod(t *testing.T, content string) string {
+	t.Helper()
+	return testutil.WriteModule(t, map[string]string{"go.mod": content})
+}
+
+func hasFinding(result *ModReviewResult, check, module string) bool {
+	for _, f := range result.Findings {
+		if f.Check == check && (module == "" || f.Module == module) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestReviewModule(t *testing.T) {
+	tests := []struct {
+		name      string
+		goMod     string
+		wantCheck string
+		wantMod   string
+		wantNone  bool
+	}{
+		{
+			name:     "healthy module",
+			goMod:    "module example.com/ok\n\ngo 1.21\n",
+			wantNone: true,
+		},
+		{
+			name:      "toolchain older than go directive",
+			goMod:     "module example.com/tc\n\ngo 1.22.0\n\ntoolchain go1.21.5\n",
+			wantCheck: "toolchain-mismatch",
+		},
+		{
+			name:      "local replace",
+			goMod:     "module example.com/rep\n\ngo 1.21\n\nrequire example.com/dep v1.0.0\n\nreplace example.com/dep => ../dep\n",
+			wantCheck: "local-replace",
+			wantMod:   "example.com/dep",
+		},
+		{
+			name:      "duplicate major versions",
+			goMod:     "module example.com/dup\n\ngo 1.21\n\nrequire (\n\texample.com/lib v1.2.0\n\texample.com/lib/v2 v2.0.1\n)\n",
+			wantCheck: "duplicate-major",
+			wantMod:   "example.com/lib",
+		},
+		{
+			name:      "missing go.sum",
+			goMod:     "module example.com/sum\n\ngo 1.21\n\nrequire example.com/dep v1.0.0\n",
+			wantCheck: "go-sum",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeGoMod(t, tt.goMod)
+			result, err := ReviewModule(context.Background(), ModReviewParams{WorkingDir: dir, SkipNetwork: true})
+			if err != nil {
+				t.Fatalf("ReviewModule() error = %v", err)
+			}
+			if tt.wantNone {
+				if len(result.Findings) != 0 {
+					t.Errorf("expected no findings, got %+v", result.Findings)
+				}
+				return
+			}
+			if !hasFinding(result, tt.wantCheck, tt.wantMod) {
+				t.Errorf("expected %s finding for %q, got %+v", tt.wantCheck, tt.wantMod, result.Findings)
+			}
+		})
+	}
+}
+
+func TestReviewModule_Errors(t *testing.T) {
+	if _, err := ReviewModule(context.Background(), ModReviewParams{}); err == nil {
+		t.Error("expected error for empty working_dir")
+	}
+
+	_, err := ReviewModule(context.Background(), ModReviewParams{WorkingDir: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "go.mod not found") {
+		t.Errorf("expected go.mod not found error, got %v", err)
+	}
+}
+
+func TestReviewModule_Tidy(t *testing.T) {
+	// The unused requirement has no imports, so tidy can compute the diff
+	// without downloading it
+	dir := writeGoMod(t, "module example.com/tidy\n\ngo 1.21\n\nrequire example.com/unused v1.0.0\n")
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatalf("failed to write main.go: %v", err)
+	}
+
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOPROXY", "off")
+
+	result, err := ReviewModule(context.Background(), ModReviewParams{WorkingDir: dir})
+	if err != nil {
+		t.Fatalf("ReviewModule() error = %v", err)
+	}
+	if !hasFinding(result, "unused-require", "example.com/unused") {
+		t.Skipf("tidy check unavailable in this environment: findings=%+v notes=%v", result.Findings, result.Notes)
+	}
+}
+
+func TestParseTidyDiff(t *testing.T) {
+	diff := `diff current/go.mod tidy/go.mod
+--- current/go.mod
++++ tidy/go.mod
+@@ -2,6 +2,5 @@
+ go 1.21
+
+ require (
+-	github.com/a/b v1.0.0
++	github.com/c/d v0.2.0
+ )
+-require github.com/e/f v1.1.0 // indirect
+diff current/go.sum tidy/go.sum
+--- current/go.sum
++++ tidy/go.sum
+-github.com/a/b v1.0.0 h1:abc=
+`
+	removed, added := parseTidyDiff(diff)
+	if want := []string{"github.com/a/b", "github.com/e/f"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+	if want := []string{"github.com/c/d"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added = %v, want %v", added, want)
+	}
+}
+
+func TestCompareGoVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.21", "1.21.0", 0},
+		{"go1.21.5", "1.22", -1},
+		{"go1.23.0", "1.22.4", 1},
+		{"go1.22rc1", "1.22", 0},
+		{"1.9", "1.10", -1},
+	}
+
+	for _, tt := range tests {
+		if got := compareGoVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareGoVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
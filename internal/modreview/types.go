@@ -0,0 +1,37 @@
+package modreview
+
+import "encoding/json"
+
+// ModReviewParams represents the parameters for the mod-review tool
+type ModReviewParams struct {
+	WorkingDir     string `json:"working_dir" jsonschema:"description:Directory containing the go.mod file to analyze"`
+	SkipNetwork    bool   `json:"skip_network,omitempty" jsonschema:"description:Skip checks that may contact the module proxy (retractions and go mod tidy)"`
+	IdempotencyKey string `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// ModReviewResult represents the result of a go.mod analysis
+type ModReviewResult struct {
+	Summary      string    `json:"summary"`
+	Module       string    `json:"module"`
+	GoVersion    string    `json:"go_version"`
+	Toolchain    string    `json:"toolchain,omitempty"`
+	Requires     int       `json:"requires"`
+	GoSumEntries int       `json:"go_sum_entries"`
+	Findings     []Finding `json:"findings"`
+	Notes        []string  `json:"notes,omitempty"` // Checks that were skipped or incomplete
+}
+
+// Finding represents a single go.mod issue
+type Finding struct {
+	Check      string `json:"check"`            // "toolchain-mismatch", "local-replace", "retracted-version", etc.
+	Severity   string `json:"severity"`         // "low", "medium", "high"
+	Module     string `json:"module,omitempty"` // Affected module path
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
+
+// String returns a formatted JSON string of the ModReviewResult
+func (r *ModReviewResult) String() string {
+	jsonData, _ := json.MarshalIndent(r, "", "  ")
+	return string(jsonData)
+}
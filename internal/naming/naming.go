@@ -0,0 +1,97 @@
+// Package naming turns keys and column names into Go identifiers, for the
+// tools that generate Go types from JSON, YAML and SQL
+package naming
+
+import (
+	"strings"
+	"unicode"
+)
+
+// initialisms are the words upper-cased in Go names, as golint does
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "LHS": true, "QPS": true, "RAM": true, "RHS": true,
+	"RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true, "XMPP": true,
+	"XSRF": true, "XSS": true,
+}
+
+// Exported returns the exported Go name of a key, capitalizing each of its
+// words. With upperInitialisms, words such as ID and URL, and their plurals
+// such as URLs, are upper-cased as a whole. It returns "" for a key without
+// letters or digits.
+func Exported(key string, upperInitialisms bool) string {
+	var b strings.Builder
+	for _, word := range Words(key) {
+		upper := strings.ToUpper(word)
+		if upperInitialisms && initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		if upperInitialisms && strings.HasSuffix(word, "s") && initialisms[strings.TrimSuffix(upper, "S")] {
+			b.WriteString(strings.TrimSuffix(upper, "S") + "s")
+			continue
+		}
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	name := b.String()
+	if name != "" && !unicode.IsUpper([]rune(name)[0]) {
+		// Digits and uncased letters cannot start an exported name
+		name = "X" + name
+	}
+	return name
+}
+
+// Words splits a key at separators and camel case boundaries
+func Words(key string) []string {
+	var out []string
+	var word []rune
+	runes := []rune(key)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				out = append(out, string(word))
+				word = nil
+			}
+			continue
+		}
+		if len(word) > 0 && unicode.IsUpper(r) {
+			prev := word[len(word)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if nextLower && runes[i+1] == 's' && (i+2 == len(runes) || !unicode.IsLower(runes[i+2])) {
+				// A plural initialism: URLs
+				nextLower = false
+			}
+			// userName -> user Name; HTTPServer -> HTTP Server
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextLower {
+				out = append(out, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		out = append(out, string(word))
+	}
+	return out
+}
+
+// Singular returns the singular of a plural name, and false when name does
+// not look plural
+func Singular(name string) (string, bool) {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return strings.TrimSuffix(name, "ies") + "y", true
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "ches"),
+		strings.HasSuffix(name, "shes"), strings.HasSuffix(name, "uses"):
+		return strings.TrimSuffix(name, "es"), true
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && len(name) > 1:
+		return strings.TrimSuffix(name, "s"), true
+	}
+	return name, false
+}
@@ -0,0 +1,67 @@
+package naming
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"user_id", []string{"user", "id"}},
+		{"userName", []string{"user", "Name"}},
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"apiURLs", []string{"api", "URLs"}},
+		{"size2Bytes", []string{"size2", "Bytes"}},
+		{"---", nil},
+	}
+	for _, tt := range tests {
+		if got := Words(tt.key); !slices.Equal(got, tt.want) {
+			t.Errorf("Words(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestExported(t *testing.T) {
+	tests := []struct {
+		key        string
+		initialism bool
+		want       string
+	}{
+		{"user_id", true, "UserID"},
+		{"user_id", false, "UserId"},
+		{"HTTPServer", true, "HTTPServer"},
+		{"HTTPServer", false, "HttpServer"},
+		{"urls", true, "URLs"},
+		{"2fa", true, "X2fa"},
+		{"---", true, ""},
+	}
+	for _, tt := range tests {
+		if got := Exported(tt.key, tt.initialism); got != tt.want {
+			t.Errorf("Exported(%q, %v) = %q, want %q", tt.key, tt.initialism, got, tt.want)
+		}
+	}
+}
+
+func TestSingular(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		plural bool
+	}{
+		{"Categories", "Category", true},
+		{"Addresses", "Address", true},
+		{"Boxes", "Box", true},
+		{"Statuses", "Status", true},
+		{"Users", "User", true},
+		{"Class", "Class", false},
+		{"People", "People", false},
+	}
+	for _, tt := range tests {
+		if got, plural := Singular(tt.name); got != tt.want || plural != tt.plural {
+			t.Errorf("Singular(%q) = %q, %v, want %q, %v", tt.name, got, plural, tt.want, tt.plural)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package policy decides which filesystem writes tools may perform. Every
+// tool that writes consults the same policy, and every attempt is written
+// to the audit log whether it is allowed or not.
+package policy
+
+import (
+	"fmt"
+
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/types"
+	"mcp-go-assistant/internal/validations"
+)
+
+// Reasons a write is denied, reported in the audit log and error details
+const (
+	ReasonReadOnly             = "read_only"
+	ReasonPathNotAllowed       = "path_not_allowed"
+	ReasonConfirmationRequired = "confirmation_required"
+)
+
+// Config holds the write policy settings
+type Config struct {
+	ReadOnly            bool     // Reject every write
+	AllowPaths          []string // Directories writes must resolve into; empty allows any path
+	RequireConfirmation bool     // Writes must be confirmed by the caller
+}
+
+// Write is a filesystem write a tool asks to perform
+type Write struct {
+	Tool      string
+	Path      string
+	Confirmed bool // The caller confirmed the write, e.g. with a confirm argument
+}
+
+// Policy checks writes against the configured settings
+type Policy struct {
+	config Config
+	allow  *validations.AllowedRootsRule
+	logger *logging.Logger
+}
+
+// New creates a write policy that audits to log
+func New(cfg Config, log *logging.Logger) *Policy {
+	return &Policy{
+		config: cfg,
+		allow:  validations.NewAllowedRootsRule(cfg.AllowPaths),
+		logger: log,
+	}
+}
+
+// ReadOnly reports whether the policy rejects every write
+func (p *Policy) ReadOnly() bool {
+	return p.config.ReadOnly
+}
+
+// CheckWrite returns a FORBIDDEN error when the policy does not allow w,
+// auditing the decision either way
+func (p *Policy) CheckWrite(w Write) error {
+	reason := p.deny(w)
+	if p.logger != nil {
+		p.logger.LogWriteAttempt(w.Tool, w.Path, reason)
+	}
+	if reason == "" {
+		return nil
+	}
+
+	var message string
+	switch reason {
+	case ReasonReadOnly:
+		message = "the server is read-only; writes are disabled by the write policy"
+	case ReasonPathNotAllowed:
+		message = fmt.Sprintf("writing %s is not allowed by the write policy", w.Path)
+	case ReasonConfirmationRequired:
+		message = fmt.Sprintf("writing %s requires confirmation; repeat the call with confirm set to true", w.Path)
+	}
+	return types.NewForbiddenError(message, "tool", w.Tool, "path", w.Path, "reason", reason)
+}
+
+// deny returns the reason w is denied, or "" when it is allowed
+func (p *Policy) deny(w Write) string {
+	switch {
+	case p.config.ReadOnly:
+		return ReasonReadOnly
+	case p.allow.Validate(w.Path) != nil:
+		return ReasonPathNotAllowed
+	case p.config.RequireConfirmation && !w.Confirmed:
+		return ReasonConfirmationRequired
+	}
+	return ""
+}
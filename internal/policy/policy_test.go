@@ -0,0 +1,78 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/types"
+)
+
+func TestCheckWrite(t *testing.T) {
+	allowed := t.TempDir()
+	inside := filepath.Join(allowed, "store_test.go")
+	outside := filepath.Join(t.TempDir(), "store_test.go")
+
+	tests := []struct {
+		name       string
+		config     Config
+		write      Write
+		wantReason string
+	}{
+		{"default allows", Config{}, Write{Path: outside}, ""},
+		{"read only", Config{ReadOnly: true, AllowPaths: []string{allowed}}, Write{Path: inside, Confirmed: true}, ReasonReadOnly},
+		{"inside allowed path", Config{AllowPaths: []string{allowed}}, Write{Path: inside}, ""},
+		{"outside allowed paths", Config{AllowPaths: []string{allowed}}, Write{Path: outside}, ReasonPathNotAllowed},
+		{"unconfirmed", Config{RequireConfirmation: true}, Write{Path: inside}, ReasonConfirmationRequired},
+		{"confirmed", Config{RequireConfirmation: true}, Write{Path: inside, Confirmed: true}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.write.Tool = "test-gen"
+			err := New(tt.config, nil).CheckWrite(tt.write)
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			mcpErr, ok := err.(types.MCPError)
+			if !ok {
+				t.Fatalf("expected MCPError, got %v", err)
+			}
+			if mcpErr.Code() != "FORBIDDEN" || mcpErr.Details()["reason"] != tt.wantReason {
+				t.Errorf("expected FORBIDDEN with reason %s, got %s %v", tt.wantReason, mcpErr.Code(), mcpErr.Details())
+			}
+		})
+	}
+}
+
+func TestCheckWrite_Audit(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	log, err := logging.New("info", "json", logPath, true)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	p := New(Config{RequireConfirmation: true}, log)
+	_ = p.CheckWrite(Write{Tool: "test-gen", Path: "/work/a_test.go"})
+	_ = p.CheckWrite(Write{Tool: "test-gen", Path: "/work/b_test.go", Confirmed: true})
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 audit records, got %d:\n%s", len(lines), data)
+	}
+	for i, want := range []string{`"reason":"confirmation_required"`, `"allowed":true`} {
+		if !strings.Contains(lines[i], `"audit":true`) || !strings.Contains(lines[i], want) {
+			t.Errorf("expected audit record %d to contain %s, got %s", i, want, lines[i])
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"mcp-go-assistant/internal/logging"
+)
+
+// Container deployment modes
+const (
+	ContainerAuto = "auto" // Detect a container at startup
+	ContainerOn   = "on"   // Always apply the container checks
+	ContainerOff  = "off"  // Never apply the container checks
+)
+
+// Files and cgroup entries that indicate the process runs in a container.
+// Variables so tests can point them at temporary files.
+var (
+	containerMarkers = []string{"/.dockerenv", "/run/.containerenv"}
+	cgroupFile       = "/proc/1/cgroup"
+	cgroupRuntimes   = []string{"docker", "kubepods", "containerd", "libpod", "lxc"}
+	shellPath        = "/bin/sh"
+)
+
+// goDirNames are the go env variables checked for a usable toolchain.
+// GOROOT only needs to be readable; the others are written by go builds.
+var goDirNames = []string{"GOROOT", "GOPATH", "GOMODCACHE", "GOCACHE"}
+
+// DetectContainer reports whether the process runs in a container and what
+// gave it away
+func DetectContainer() (bool, string) {
+	for _, marker := range containerMarkers {
+		if _, err := os.Stat(marker); err == nil {
+			return true, marker
+		}
+	}
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return true, "KUBERNETES_SERVICE_HOST"
+	}
+	if data, err := os.ReadFile(cgroupFile); err == nil {
+		for _, rt := range cgroupRuntimes {
+			if strings.Contains(string(data), rt) {
+				return true, cgroupFile + " (" + rt + ")"
+			}
+		}
+	}
+	return false, ""
+}
+
+// InContainer resolves a container mode to whether the container checks
+// apply
+func InContainer(mode string) bool {
+	switch mode {
+	case ContainerOn:
+		return true
+	case ContainerOff:
+		return false
+	default:
+		in, _ := DetectContainer()
+		return in
+	}
+}
+
+// Minimal reports whether the image lacks a shell, as distroless and
+// scratch images do
+func Minimal() bool {
+	if runtime.GOOS == "windows" {
+		return false
+	}
+	_, err := os.Stat(shellPath)
+	return err != nil
+}
+
+// GoDir is a go env directory and whether it is usable
+type GoDir struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Writable bool   `json:"writable"`
+	Error    string `json:"error,omitempty"`
+}
+
+// GoDirs returns GOROOT, GOPATH, GOMODCACHE and GOCACHE as reported by the
+// go command, checking that GOROOT is readable and the others writable
+func GoDirs(ctx context.Context) ([]GoDir, error) {
+	cmd := exec.CommandContext(ctx, "go", append([]string{"env", "-json"}, goDirNames...)...)
+	cmd.Env = logging.CommandEnv(ctx, nil)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go env failed: %v; install Go or add it to PATH", err)
+	}
+	var env map[string]string
+	if err := json.Unmarshal(output, &env); err != nil {
+		return nil, fmt.Errorf("failed to parse go env output: %v", err)
+	}
+
+	dirs := make([]GoDir, 0, len(goDirNames))
+	for _, name := range goDirNames {
+		dirs = append(dirs, checkGoDir(name, env[name]))
+	}
+	return dirs, nil
+}
+
+// checkGoDir checks a single go env directory
+func checkGoDir(name, path string) GoDir {
+	dir := GoDir{Name: name, Path: path}
+	if path == "" || path == "off" {
+		dir.Error = fmt.Sprintf("%s is not set; set it to a writable directory, e.g. %s=/tmp/%s", name, name, strings.ToLower(name))
+		return dir
+	}
+
+	if name == "GOROOT" {
+		if _, err := os.Stat(filepath.Join(path, "src")); err != nil {
+			dir.Error = fmt.Sprintf("GOROOT %s has no standard library sources: %v; copy the full Go distribution into the image", path, err)
+		}
+		return dir
+	}
+
+	if err := writable(path); err != nil {
+		dir.Error = fmt.Sprintf("%s %s is not writable: %v; set %s to a writable directory or mount a volume there", name, path, err, name)
+		return dir
+	}
+	dir.Writable = true
+	return dir
+}
+
+// writable checks that a file can be created in dir, or in its nearest
+// existing parent when dir does not exist yet, since go creates it on
+// first use
+func writable(dir string) error {
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			break
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return err
+		}
+		dir = parent
+	}
+
+	f, err := os.CreateTemp(dir, ".mcp-write-check-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// GoEnvironmentCheck returns a startup check that fails when a go env
+// directory is unusable, naming each one with a fix
+func GoEnvironmentCheck() Check {
+	return Check{
+		Name: "go_environment",
+		Run: func(ctx context.Context) (string, error) {
+			dirs, err := GoDirs(ctx)
+			if err != nil {
+				return "", err
+			}
+			var problems []string
+			for _, dir := range dirs {
+				if dir.Error != "" {
+					problems = append(problems, dir.Error)
+				}
+			}
+			if len(problems) > 0 {
+				return "", errors.New(strings.Join(problems, "; "))
+			}
+			return "GOPATH, GOMODCACHE and GOCACHE writable", nil
+		},
+	}
+}
+
+// Diagnosis is a full description of the runtime environment
+type Diagnosis struct {
+	Platform  string   `json:"platform"`
+	Container bool     `json:"container"`
+	Detected  string   `json:"detected,omitempty"` // What indicated the container
+	Minimal   bool     `json:"minimal"`            // No shell, e.g. a distroless image
+	Toolchain string   `json:"toolchain,omitempty"`
+	GoDirs    []GoDir  `json:"go_dirs,omitempty"`
+	Problems  []string `json:"problems,omitempty"`
+}
+
+// Diagnose inspects the platform, container and go toolchain.
+// toolchain reports the go version, such as godoc.Toolchain.
+func Diagnose(ctx context.Context, toolchain func(context.Context) (string, error)) *Diagnosis {
+	d := &Diagnosis{Platform: runtime.GOOS + "/" + runtime.GOARCH, Minimal: Minimal()}
+	d.Container, d.Detected = DetectContainer()
+
+	version, err := toolchain(ctx)
+	if err != nil {
+		d.Problems = append(d.Problems, err.Error())
+		return d
+	}
+	d.Toolchain = version
+
+	dirs, err := GoDirs(ctx)
+	if err != nil {
+		d.Problems = append(d.Problems, err.Error())
+		return d
+	}
+	d.GoDirs = dirs
+	for _, dir := range dirs {
+		if dir.Error != "" {
+			d.Problems = append(d.Problems, dir.Error)
+		}
+	}
+	return d
+}
+
+// Write prints the diagnosis as a human-readable report
+func (d *Diagnosis) Write(w io.Writer) {
+	fmt.Fprintf(w, "platform:   %s\n", d.Platform)
+	if d.Container {
+		fmt.Fprintf(w, "container:  yes (%s)\n", d.Detected)
+	} else {
+		fmt.Fprintf(w, "container:  no\n")
+	}
+	if d.Minimal {
+		fmt.Fprintf(w, "image:      minimal (no %s)\n", shellPath)
+	}
+	if d.Toolchain != "" {
+		fmt.Fprintf(w, "toolchain:  %s\n", d.Toolchain)
+	}
+	for _, dir := range d.GoDirs {
+		status := "readable"
+		if dir.Writable {
+			status = "writable"
+		}
+		if dir.Error != "" {
+			status = "problem"
+		}
+		fmt.Fprintf(w, "%-11s %s (%s)\n", dir.Name+":", dir.Path, status)
+	}
+
+	if len(d.Problems) == 0 {
+		fmt.Fprintln(w, "\nno problems found")
+		return
+	}
+	fmt.Fprintf(w, "\n%d problem(s) found:\n", len(d.Problems))
+	for _, problem := range d.Problems {
+		fmt.Fprintf(w, "  - %s\n", problem)
+	}
+}
@@ -0,0 +1,114 @@
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectContainer(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, ".dockerenv")
+	cgroup := filepath.Join(dir, "cgroup")
+
+	oldMarkers, oldCgroup := containerMarkers, cgroupFile
+	t.Cleanup(func() { containerMarkers, cgroupFile = oldMarkers, oldCgroup })
+	containerMarkers, cgroupFile = []string{marker}, cgroup
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	if in, _ := DetectContainer(); in {
+		t.Error("expected no container without markers")
+	}
+	if InContainer(ContainerAuto) || !InContainer(ContainerOn) {
+		t.Error("expected auto to follow detection and on to force the checks")
+	}
+
+	if err := os.WriteFile(cgroup, []byte("0::/kubepods/besteffort/pod1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if in, detected := DetectContainer(); !in || !strings.Contains(detected, "kubepods") {
+		t.Errorf("expected cgroup detection, got %v %q", in, detected)
+	}
+
+	if err := os.WriteFile(marker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if in, detected := DetectContainer(); !in || detected != marker {
+		t.Errorf("expected marker detection, got %v %q", in, detected)
+	}
+	if InContainer(ContainerOff) {
+		t.Error("expected off to disable the checks")
+	}
+}
+
+func TestCheckGoDir(t *testing.T) {
+	dir := t.TempDir()
+
+	if got := checkGoDir("GOMODCACHE", filepath.Join(dir, "missing", "mod")); !got.Writable || got.Error != "" {
+		t.Errorf("expected a missing directory under a writable parent to be usable, got %+v", got)
+	}
+
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := checkGoDir("GOCACHE", file); got.Writable || !strings.Contains(got.Error, "set GOCACHE to a writable directory") {
+		t.Errorf("expected actionable error for a file, got %+v", got)
+	}
+
+	if got := checkGoDir("GOCACHE", "off"); !strings.Contains(got.Error, "GOCACHE is not set") {
+		t.Errorf("expected error for disabled cache, got %+v", got)
+	}
+
+	if got := checkGoDir("GOROOT", dir); !strings.Contains(got.Error, "no standard library sources") {
+		t.Errorf("expected error for GOROOT without src, got %+v", got)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "src"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := checkGoDir("GOROOT", dir); got.Error != "" {
+		t.Errorf("unexpected GOROOT error %q", got.Error)
+	}
+}
+
+func TestDiagnose_ToolchainMissing(t *testing.T) {
+	missing := func(context.Context) (string, error) { return "", errors.New("go toolchain unavailable") }
+
+	d := Diagnose(context.Background(), missing)
+	if len(d.Problems) != 1 || d.Toolchain != "" || d.GoDirs != nil {
+		t.Fatalf("unexpected diagnosis %+v", d)
+	}
+
+	var buf bytes.Buffer
+	d.Write(&buf)
+	out := buf.String()
+	if !strings.Contains(out, "platform:") || !strings.Contains(out, "1 problem(s) found") || !strings.Contains(out, "go toolchain unavailable") {
+		t.Errorf("unexpected report:\n%s", out)
+	}
+}
+
+func TestWrite_NoProblems(t *testing.T) {
+	d := &Diagnosis{
+		Platform:  "linux/amd64",
+		Container: true,
+		Detected:  "/.dockerenv",
+		Toolchain: "go1.23.0",
+		GoDirs: []GoDir{
+			{Name: "GOROOT", Path: "/usr/local/go"},
+			{Name: "GOCACHE", Path: "/tmp/cache", Writable: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	d.Write(&buf)
+	out := buf.String()
+	for _, want := range []string{"container:  yes (/.dockerenv)", "GOROOT:     /usr/local/go (readable)", "GOCACHE:    /tmp/cache (writable)", "no problems found"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in report:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"mcp-go-assistant/internal/health"
+)
+
+// Failure modes for preflight checks
+const (
+	OnFailureFail    = "fail"    // Refuse to start when a check fails
+	OnFailureDegrade = "degrade" // Start and report degraded health
+)
+
+// Check is a single startup check. Run returns a short description of the
+// verified state on success.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) (string, error)
+}
+
+// Result is the outcome of a single check
+type Result struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Message  string        `json:"message,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Report collects the results of a preflight run. It implements
+// health.Checker so the results appear in every health check.
+type Report struct {
+	Results   []Result  `json:"results"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Run executes checks in order and returns their results. A failing check
+// does not stop the remaining checks.
+func Run(ctx context.Context, checks []Check) *Report {
+	report := &Report{
+		Results:   make([]Result, 0, len(checks)),
+		Timestamp: time.Now().UTC(),
+	}
+
+	for _, check := range checks {
+		start := time.Now()
+		message, err := check.Run(ctx)
+
+		result := Result{
+			Name:     check.Name,
+			Passed:   err == nil,
+			Message:  message,
+			Duration: time.Since(start),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+// Failed returns the results of the checks that did not pass
+func (r *Report) Failed() []Result {
+	var failed []Result
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Err returns an error describing the failed checks, or nil if all passed
+func (r *Report) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, len(failed))
+	for i, result := range failed {
+		msgs[i] = fmt.Sprintf("%s: %s", result.Name, result.Error)
+	}
+	return fmt.Errorf("preflight failed: %s", strings.Join(msgs, "; "))
+}
+
+// Check reports the preflight results as a health check. Failed checks mark
+// the server as degraded.
+func (r *Report) Check() health.Check {
+	failed := r.Failed()
+	check := health.Check{
+		Name:      "preflight",
+		Status:    health.StatusHealthy,
+		Message:   fmt.Sprintf("%d/%d startup checks passed", len(r.Results)-len(failed), len(r.Results)),
+		Timestamp: r.Timestamp,
+	}
+	for _, result := range r.Results {
+		check.Duration += result.Duration
+	}
+
+	if len(failed) > 0 {
+		check.Status = health.StatusDegraded
+		check.Error = r.Err().Error()
+	}
+
+	lines := make([]string, 0, len(r.Results))
+	for _, result := range r.Results {
+		if result.Passed {
+			lines = append(lines, fmt.Sprintf("%s: ok (%s)", result.Name, result.Message))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s: failed", result.Name))
+		}
+	}
+	if len(lines) > 0 {
+		check.Message += "; " + strings.Join(lines, "; ")
+	}
+
+	return check
+}
@@ -0,0 +1,84 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/health"
+)
+
+func passing(name, message string) Check {
+	return Check{Name: name, Run: func(context.Context) (string, error) { return message, nil }}
+}
+
+func failing(name string) Check {
+	return Check{Name: name, Run: func(context.Context) (string, error) { return "", errors.New("unavailable") }}
+}
+
+func TestRun_AllPassed(t *testing.T) {
+	report := Run(context.Background(), []Check{passing("toolchain", "go1.23.0"), passing("store", "memory")})
+
+	if err := report.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(report.Results))
+	}
+
+	check := report.Check()
+	if check.Status != health.StatusHealthy {
+		t.Errorf("expected healthy status, got %s", check.Status)
+	}
+	if !strings.Contains(check.Message, "2/2 startup checks passed") || !strings.Contains(check.Message, "toolchain: ok (go1.23.0)") {
+		t.Errorf("unexpected message %q", check.Message)
+	}
+}
+
+func TestRun_Failure(t *testing.T) {
+	var ran []string
+	record := func(c Check) Check {
+		run := c.Run
+		c.Run = func(ctx context.Context) (string, error) {
+			ran = append(ran, c.Name)
+			return run(ctx)
+		}
+		return c
+	}
+
+	report := Run(context.Background(), []Check{record(failing("toolchain")), record(passing("store", "memory"))})
+
+	if strings.Join(ran, ",") != "toolchain,store" {
+		t.Errorf("expected every check to run, ran %v", ran)
+	}
+	if failed := report.Failed(); len(failed) != 1 || failed[0].Name != "toolchain" {
+		t.Errorf("unexpected failed checks %+v", failed)
+	}
+
+	err := report.Err()
+	if err == nil || !strings.Contains(err.Error(), "toolchain: unavailable") {
+		t.Errorf("expected error naming the failed check, got %v", err)
+	}
+
+	check := report.Check()
+	if check.Status != health.StatusDegraded {
+		t.Errorf("expected degraded status, got %s", check.Status)
+	}
+	if check.Error == "" || !strings.Contains(check.Message, "1/2 startup checks passed") {
+		t.Errorf("unexpected check %+v", check)
+	}
+}
+
+func TestReport_RegisteredWithHealthChecker(t *testing.T) {
+	hc := health.New("test")
+	hc.RegisterChecker("preflight", Run(context.Background(), []Check{failing("store")}))
+
+	h := hc.Check()
+	if h.Status != health.StatusDegraded {
+		t.Errorf("expected degraded overall status, got %s", h.Status)
+	}
+	if _, ok := h.Checks["preflight"]; !ok {
+		t.Error("expected preflight check in health report")
+	}
+}
@@ -0,0 +1,245 @@
+package profiling
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/pprof/profile"
+
+	"mcp-go-assistant/internal/workspace"
+)
+
+// Summary limits and defaults
+const (
+	DefaultTop     = 10
+	MaxTop         = 100
+	MaxProfileSize = 64 << 20
+)
+
+// Sort orders of the summary
+const (
+	SortFlat = "flat"
+	SortCum  = "cum"
+)
+
+// Options controls which files a summary may read
+type Options struct {
+	Roots []string // Registered workspace roots; profile_file must be inside one
+}
+
+// Summarize parses the requested profile and returns its top functions
+func Summarize(params ProfileParams, opts Options) (*ProfileSummary, error) {
+	sortBy := strings.ToLower(params.SortBy)
+	if sortBy == "" {
+		sortBy = SortFlat
+	}
+	if sortBy != SortFlat && sortBy != SortCum {
+		return nil, fmt.Errorf("unsupported sort_by: %s (supported: flat, cum)", params.SortBy)
+	}
+	top := params.Top
+	if top == 0 {
+		top = DefaultTop
+	}
+	if top < 0 || top > MaxTop {
+		return nil, fmt.Errorf("top must be between 1 and %d", MaxTop)
+	}
+
+	data, err := readProfile(params, opts)
+	if err != nil {
+		return nil, err
+	}
+	p, err := profile.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profile: %v", err)
+	}
+
+	index, err := sampleIndex(p, params.SampleType)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ProfileSummary{
+		SampleType:  p.SampleType[index].Type,
+		Unit:        p.SampleType[index].Unit,
+		SortBy:      sortBy,
+		SampleTypes: make([]string, 0, len(p.SampleType)),
+		Top:         []FunctionStats{},
+	}
+	for _, st := range p.SampleType {
+		summary.SampleTypes = append(summary.SampleTypes, st.Type)
+	}
+	if p.DurationNanos > 0 {
+		summary.Duration = time.Duration(p.DurationNanos).String()
+	}
+
+	stats, total := aggregate(p, index)
+	summary.Total = total
+	summary.Functions = len(stats)
+
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if sortBy == SortCum && a.Cum != b.Cum {
+			return a.Cum > b.Cum
+		}
+		if a.Flat != b.Flat {
+			return a.Flat > b.Flat
+		}
+		if a.Cum != b.Cum {
+			return a.Cum > b.Cum
+		}
+		return a.Name < b.Name
+	})
+	for _, fn := range stats[:min(top, len(stats))] {
+		fn.FlatPercent = percent(fn.Flat, summary.Total)
+		fn.CumPercent = percent(fn.Cum, summary.Total)
+		summary.Top = append(summary.Top, *fn)
+	}
+
+	return summary, nil
+}
+
+// readProfile returns the raw profile from the base64 parameter or the
+// workspace file
+func readProfile(params ProfileParams, opts Options) ([]byte, error) {
+	switch {
+	case params.Profile != "" && params.ProfileFile != "":
+		return nil, fmt.Errorf("profile and profile_file are mutually exclusive")
+	case params.Profile != "":
+		encoded := strings.Join(strings.Fields(params.Profile), "")
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("profile is not valid base64: %v", err)
+		}
+		if len(data) > MaxProfileSize {
+			return nil, fmt.Errorf("profile exceeds %d bytes", MaxProfileSize)
+		}
+		return data, nil
+	case params.ProfileFile != "":
+		path, err := workspace.File("profile_file", params.ProfileFile, opts.Roots)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("profile file not found: %s", params.ProfileFile)
+		}
+		if info.Size() > MaxProfileSize {
+			return nil, fmt.Errorf("profile file exceeds %d bytes", MaxProfileSize)
+		}
+		return os.ReadFile(path)
+	default:
+		return nil, fmt.Errorf("profile or profile_file parameter is required")
+	}
+}
+
+// sampleIndex returns the index of the requested sample type, or of the
+// profile's default type when none is requested
+func sampleIndex(p *profile.Profile, sampleType string) (int, error) {
+	if len(p.SampleType) == 0 {
+		return 0, fmt.Errorf("profile has no sample types")
+	}
+	if sampleType == "" {
+		sampleType = p.DefaultSampleType
+	}
+	if sampleType == "" {
+		// pprof shows the last sample type by default
+		return len(p.SampleType) - 1, nil
+	}
+
+	var types []string
+	for i, st := range p.SampleType {
+		if st.Type == sampleType {
+			return i, nil
+		}
+		types = append(types, st.Type)
+	}
+	return 0, fmt.Errorf("sample type %s not found (available: %s)", sampleType, strings.Join(types, ", "))
+}
+
+// aggregate attributes the sample values at index to functions and
+// returns them with the total. Flat values go to the innermost function of
+// each sample, including inlined ones; cum values go once to every
+// function on the stack. Unsymbolized locations are named by address.
+func aggregate(p *profile.Profile, index int) ([]*FunctionStats, int64) {
+	byName := make(map[string]*FunctionStats)
+	var total int64
+
+	for _, sample := range p.Sample {
+		value := sample.Value[index]
+		if value == 0 {
+			continue
+		}
+		total += value
+
+		seen := make(map[string]bool)
+		leaf := true
+		add := func(name, file string) {
+			s, ok := byName[name]
+			if !ok {
+				s = &FunctionStats{Name: name, File: file}
+				byName[name] = s
+			}
+			if leaf {
+				s.Flat += value
+				leaf = false
+			}
+			if !seen[name] {
+				s.Cum += value
+				seen[name] = true
+			}
+		}
+
+		for _, loc := range sample.Location {
+			if len(loc.Line) == 0 {
+				add(fmt.Sprintf("0x%x", loc.Address), "")
+			}
+			// Lines of a location are ordered from the innermost inlined call
+			for _, line := range loc.Line {
+				if line.Function != nil {
+					add(line.Function.Name, line.Function.Filename)
+				}
+			}
+		}
+	}
+
+	stats := make([]*FunctionStats, 0, len(byName))
+	for _, s := range byName {
+		stats = append(stats, s)
+	}
+	return stats, total
+}
+
+// percent returns value as a percentage of total rounded to 0.01
+func percent(value, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(value)*10000/float64(total)) / 100
+}
+
+// formatValue renders a sample value in its unit
+func formatValue(value int64, unit string) string {
+	switch unit {
+	case "nanoseconds":
+		return time.Duration(value).Round(10 * time.Microsecond).String()
+	case "bytes":
+		const k = 1024
+		v := float64(value)
+		for _, suffix := range []string{"B", "kB", "MB", "GB"} {
+			if math.Abs(v) < k || suffix == "GB" {
+				if suffix == "B" {
+					return fmt.Sprintf("%d%s", value, suffix)
+				}
+				return fmt.Sprintf("%.2f%s", v, suffix)
+			}
+			v /= k
+		}
+	}
+	return fmt.Sprintf("%d", value)
+}
@@ -0,0 +1,204 @@
+package profiling
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/pprof/profile"
+)
+
+// testProfile builds a CPU profile where main.main calls main.work, which
+// calls the inlined main.hash, and main.main also does work itself
+func testProfile(t *testing.T) *profile.Profile {
+	t.Helper()
+	mainFn := &profile.Function{ID: 1, Name: "main.main", Filename: "/app/main.go"}
+	workFn := &profile.Function{ID: 2, Name: "main.work", Filename: "/app/work.go"}
+	hashFn := &profile.Function{ID: 3, Name: "main.hash", Filename: "/app/work.go"}
+
+	mainLoc := &profile.Location{ID: 1, Address: 0x1000, Line: []profile.Line{{Function: mainFn, Line: 10}}}
+	// main.hash is inlined into main.work
+	workLoc := &profile.Location{ID: 2, Address: 0x2000, Line: []profile.Line{{Function: hashFn, Line: 30}, {Function: workFn, Line: 20}}}
+	rawLoc := &profile.Location{ID: 3, Address: 0xdead}
+
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "samples", Unit: "count"},
+			{Type: "cpu", Unit: "nanoseconds"},
+		},
+		PeriodType:    &profile.ValueType{Type: "cpu", Unit: "nanoseconds"},
+		Period:        10000000,
+		DurationNanos: 2e9,
+		Function:      []*profile.Function{mainFn, workFn, hashFn},
+		Location:      []*profile.Location{mainLoc, workLoc, rawLoc},
+		Sample: []*profile.Sample{
+			{Location: []*profile.Location{workLoc, mainLoc}, Value: []int64{6, 60000000}},
+			{Location: []*profile.Location{mainLoc}, Value: []int64{3, 30000000}},
+			{Location: []*profile.Location{rawLoc, mainLoc}, Value: []int64{1, 10000000}},
+		},
+	}
+	if err := p.CheckValid(); err != nil {
+		t.Fatalf("invalid test profile: %v", err)
+	}
+	return p
+}
+
+// encode returns the gzipped profile encoded as base64
+func encode(t *testing.T, p *profile.Profile) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := p.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestSummarize(t *testing.T) {
+	encoded := encode(t, testProfile(t))
+
+	tests := []struct {
+		name      string
+		params    ProfileParams
+		wantType  string
+		wantTotal int64
+		wantTop   []string // name:flat:cum
+	}{
+		{
+			name:      "default flat cpu",
+			params:    ProfileParams{Profile: encoded},
+			wantType:  "cpu",
+			wantTotal: 100000000,
+			wantTop:   []string{"main.hash:60000000:60000000", "main.main:30000000:100000000", "0xdead:10000000:10000000", "main.work:0:60000000"},
+		},
+		{
+			name:      "cum with top",
+			params:    ProfileParams{Profile: encoded, SortBy: "cum", Top: 2},
+			wantType:  "cpu",
+			wantTotal: 100000000,
+			wantTop:   []string{"main.main:30000000:100000000", "main.hash:60000000:60000000"},
+		},
+		{
+			name:      "sample type",
+			params:    ProfileParams{Profile: encoded, SampleType: "samples", Top: 1},
+			wantType:  "samples",
+			wantTotal: 10,
+			wantTop:   []string{"main.hash:6:6"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := Summarize(tt.params, Options{})
+			if err != nil {
+				t.Fatalf("Summarize() error = %v", err)
+			}
+			if summary.SampleType != tt.wantType || summary.Total != tt.wantTotal || summary.Functions != 4 {
+				t.Errorf("unexpected summary header %+v", summary)
+			}
+
+			var got []string
+			for _, fn := range summary.Top {
+				got = append(got, fmt.Sprintf("%s:%d:%d", fn.Name, fn.Flat, fn.Cum))
+			}
+			if strings.Join(got, " ") != strings.Join(tt.wantTop, " ") {
+				t.Errorf("top = %v, want %v", got, tt.wantTop)
+			}
+		})
+	}
+}
+
+func TestSummarize_Text(t *testing.T) {
+	summary, err := Summarize(ProfileParams{Profile: encode(t, testProfile(t)), Top: 2}, Options{})
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if summary.Top[0].FlatPercent != 60 || summary.Top[1].CumPercent != 100 {
+		t.Errorf("unexpected percentages %+v", summary.Top)
+	}
+
+	text := summary.String()
+	for _, want := range []string{
+		"Type: cpu\nDuration: 2s\n",
+		"Showing top 2 of 4 functions by flat, total 100ms",
+		"      60ms  60.0%       60ms  60.0%  main.hash",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestSummarize_ProfileFile(t *testing.T) {
+	root := t.TempDir()
+	var buf bytes.Buffer
+	if err := testProfile(t).Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(root, "cpu.pprof")
+	if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := Summarize(ProfileParams{ProfileFile: file}, Options{Roots: []string{root}})
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if summary.Total != 100000000 {
+		t.Errorf("unexpected total %d", summary.Total)
+	}
+
+	_, err = Summarize(ProfileParams{ProfileFile: file}, Options{Roots: []string{t.TempDir()}})
+	if err == nil || !strings.Contains(err.Error(), "not inside a registered workspace") {
+		t.Errorf("expected workspace error, got %v", err)
+	}
+}
+
+func TestSummarize_Errors(t *testing.T) {
+	encoded := encode(t, testProfile(t))
+
+	tests := []struct {
+		name    string
+		params  ProfileParams
+		wantErr string
+	}{
+		{"no profile", ProfileParams{}, "profile or profile_file parameter is required"},
+		{"both", ProfileParams{Profile: encoded, ProfileFile: "cpu.pprof"}, "mutually exclusive"},
+		{"bad base64", ProfileParams{Profile: "not base64!"}, "not valid base64"},
+		{"not a profile", ProfileParams{Profile: base64.StdEncoding.EncodeToString([]byte("hello"))}, "failed to parse profile"},
+		{"unknown sample type", ProfileParams{Profile: encoded, SampleType: "alloc_space"}, "sample type alloc_space not found (available: samples, cpu)"},
+		{"bad sort", ProfileParams{Profile: encoded, SortBy: "name"}, "unsupported sort_by"},
+		{"bad top", ProfileParams{Profile: encoded, Top: 500}, "top must be between 1 and 100"},
+		{"no roots", ProfileParams{ProfileFile: "cpu.pprof"}, "requires at least one registered workspace root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Summarize(tt.params, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		value int64
+		unit  string
+		want  string
+	}{
+		{1500000000, "nanoseconds", "1.5s"},
+		{512, "bytes", "512B"},
+		{3 << 20, "bytes", "3.00MB"},
+		{42, "count", "42"},
+	}
+	for _, tt := range tests {
+		if got := formatValue(tt.value, tt.unit); got != tt.want {
+			t.Errorf("formatValue(%d, %q) = %q, want %q", tt.value, tt.unit, got, tt.want)
+		}
+	}
+}
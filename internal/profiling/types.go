@@ -0,0 +1,55 @@
+package profiling
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProfileParams represents the parameters for the profile-summary tool
+type ProfileParams struct {
+	Profile        string `json:"profile,omitempty" jsonschema:"description:Base64-encoded pprof profile, gzipped or not; either profile or profile_file is required"`
+	ProfileFile    string `json:"profile_file,omitempty" jsonschema:"description:Path of a pprof profile inside a registered workspace root, e.g. written by go test -cpuprofile"`
+	SampleType     string `json:"sample_type,omitempty" jsonschema:"description:Optional sample type to summarize, e.g. 'cpu', 'alloc_space', 'alloc_objects' or 'inuse_space' (defaults to the profile's default type)"`
+	SortBy         string `json:"sort_by,omitempty" jsonschema:"description:Optional order of the functions: 'flat' (default) for time or bytes in the function itself or 'cum' to include its callees"`
+	Top            int    `json:"top,omitempty" jsonschema:"description:Optional number of functions to return (default 10, at most 100)"`
+	IdempotencyKey string `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// ProfileSummary is the top functions of a profile for one sample type
+type ProfileSummary struct {
+	SampleType  string          `json:"sample_type"`
+	Unit        string          `json:"unit"` // e.g. "nanoseconds", "bytes" or "count"
+	SampleTypes []string        `json:"sample_types"`
+	SortBy      string          `json:"sort_by"`
+	Total       int64           `json:"total"`
+	Duration    string          `json:"duration,omitempty"` // Wall time covered by the profile, if recorded
+	Functions   int             `json:"functions"`          // Number of functions with samples
+	Top         []FunctionStats `json:"top"`
+}
+
+// FunctionStats holds the sample values attributed to one function
+type FunctionStats struct {
+	Name        string  `json:"name"`
+	File        string  `json:"file,omitempty"`
+	Flat        int64   `json:"flat"` // Value of samples whose innermost frame is the function
+	FlatPercent float64 `json:"flat_percent"`
+	Cum         int64   `json:"cum"` // Value of samples with the function anywhere on the stack
+	CumPercent  float64 `json:"cum_percent"`
+}
+
+// String renders the summary as a table in the layout of pprof -top
+func (s *ProfileSummary) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Type: %s\n", s.SampleType)
+	if s.Duration != "" {
+		fmt.Fprintf(&sb, "Duration: %s\n", s.Duration)
+	}
+	fmt.Fprintf(&sb, "Showing top %d of %d functions by %s, total %s\n",
+		len(s.Top), s.Functions, s.SortBy, formatValue(s.Total, s.Unit))
+	fmt.Fprintf(&sb, "%10s %6s %10s %6s\n", "flat", "flat%", "cum", "cum%")
+	for _, fn := range s.Top {
+		fmt.Fprintf(&sb, "%10s %5.1f%% %10s %5.1f%%  %s\n",
+			formatValue(fn.Flat, s.Unit), fn.FlatPercent, formatValue(fn.Cum, s.Unit), fn.CumPercent, fn.Name)
+	}
+	return sb.String()
+}
@@ -0,0 +1,315 @@
+// Package bitbucket connects the pull request reviewer to Bitbucket Cloud:
+// it receives pull request webhooks and reports reviews as Code Insights
+// reports with annotations on the head commit
+package bitbucket
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"mcp-go-assistant/internal/prreview"
+)
+
+// DefaultAPIURL is the REST API of Bitbucket Cloud
+const DefaultAPIURL = "https://api.bitbucket.org/2.0"
+
+// DefaultReportID is the ID of the reports created by the provider
+const DefaultReportID = "mcp-go-assistant-review"
+
+// Limits of the Code Insights API
+const (
+	maxAnnotationsPerRequest = 100
+	maxAnnotations           = 1000
+	maxDetails               = 2000
+	maxSummary               = 450
+)
+
+// reviewedEvents are the webhook event keys that trigger a review
+var reviewedEvents = map[string]bool{
+	"pullrequest:created": true,
+	"pullrequest:updated": true,
+}
+
+// Options configures the provider
+type Options struct {
+	APIURL        string // REST API base URL; empty uses DefaultAPIURL
+	Token         string // Repository or workspace access token reading pull requests and writing reports
+	WebhookSecret string
+	ReportID      string // Empty uses DefaultReportID
+	HTTPClient    *http.Client
+}
+
+// Provider calls the Bitbucket Cloud REST API with an access token
+type Provider struct {
+	opts Options
+}
+
+// pullRequestEvent is the part of a pull request webhook payload the
+// provider uses
+type pullRequestEvent struct {
+	PullRequest struct {
+		ID     int  `json:"id"`
+		Draft  bool `json:"draft"`
+		Source struct {
+			Commit struct {
+				Hash string `json:"hash"`
+			} `json:"commit"`
+		} `json:"source"`
+		Destination struct {
+			Commit struct {
+				Hash string `json:"hash"`
+			} `json:"commit"`
+		} `json:"destination"`
+	} `json:"pullrequest"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// diffStat is a file changed by a pull request
+type diffStat struct {
+	Status string `json:"status"` // "added", "removed", "modified", "renamed", ...
+	Old    *struct {
+		Path string `json:"path"`
+	} `json:"old"`
+	New *struct {
+		Path string `json:"path"`
+	} `json:"new"`
+}
+
+// New returns a provider for opts
+func New(opts Options) (*Provider, error) {
+	if opts.Token == "" {
+		return nil, fmt.Errorf("token is required")
+	}
+	if opts.WebhookSecret == "" {
+		return nil, fmt.Errorf("webhook_secret is required")
+	}
+	if opts.APIURL == "" {
+		opts.APIURL = DefaultAPIURL
+	}
+	opts.APIURL = strings.TrimSuffix(opts.APIURL, "/")
+	if opts.ReportID == "" {
+		opts.ReportID = DefaultReportID
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	return &Provider{opts: opts}, nil
+}
+
+// Name returns "bitbucket"
+func (p *Provider) Name() string {
+	return "bitbucket"
+}
+
+// ParseWebhook verifies the X-Hub-Signature header of a delivery and
+// returns the pull request of events that create or update a pull request
+// that is not a draft
+func (p *Provider) ParseWebhook(r *http.Request, body []byte) (*prreview.PullRequest, error) {
+	if !p.validSignature(body, r.Header.Get("X-Hub-Signature")) {
+		return nil, prreview.ErrInvalidSignature
+	}
+	key := r.Header.Get("X-Event-Key")
+	if !reviewedEvents[key] {
+		return nil, nil
+	}
+
+	var event pullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	if event.PullRequest.Draft {
+		return nil, nil
+	}
+	return &prreview.PullRequest{
+		Repository: event.Repository.FullName,
+		Number:     event.PullRequest.ID,
+		HeadSHA:    event.PullRequest.Source.Commit.Hash,
+		BaseSHA:    event.PullRequest.Destination.Commit.Hash,
+		Action:     strings.TrimPrefix(key, "pullrequest:"),
+	}, nil
+}
+
+// validSignature reports whether signature, the X-Hub-Signature header, is
+// the HMAC of body with the webhook secret
+func (p *Provider) validSignature(body []byte, signature string) bool {
+	hexSum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	sum, err := hex.DecodeString(hexSum)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(p.opts.WebhookSecret))
+	mac.Write(body)
+	return hmac.Equal(sum, mac.Sum(nil))
+}
+
+// Changes lists the files changed by pr with their part of the pull
+// request diff, compared to the destination commit of the webhook
+func (p *Provider) Changes(ctx context.Context, pr *prreview.PullRequest) (*prreview.Changes, error) {
+	var diff []byte
+	if err := p.do(ctx, http.MethodGet, p.pullRequestURL(pr)+"/diff", nil, &diff); err != nil {
+		return nil, fmt.Errorf("failed to get pull request diff: %w", err)
+	}
+	patches := splitDiff(string(diff))
+
+	changes := &prreview.Changes{BaseSHA: pr.BaseSHA}
+	next := p.pullRequestURL(pr) + "/diffstat?pagelen=100"
+	for next != "" {
+		var page struct {
+			Values []diffStat `json:"values"`
+			Next   string     `json:"next"`
+		}
+		if err := p.do(ctx, http.MethodGet, next, nil, &page); err != nil {
+			return nil, fmt.Errorf("failed to list pull request files: %w", err)
+		}
+		for _, stat := range page.Values {
+			changes.Files = append(changes.Files, toFile(stat, patches))
+		}
+		next = page.Next
+	}
+	return changes, nil
+}
+
+// toFile returns the changed file of stat with its patch
+func toFile(stat diffStat, patches map[string]string) prreview.File {
+	var f prreview.File
+	var oldPath string
+	if stat.New != nil {
+		f.Path = stat.New.Path
+	}
+	if stat.Old != nil {
+		oldPath = stat.Old.Path
+	}
+	switch stat.Status {
+	case "added":
+		f.Status = prreview.FileAdded
+	case "removed":
+		f.Status = prreview.FileRemoved
+		f.Path = oldPath
+	case "renamed":
+		f.Status = prreview.FileRenamed
+		f.PreviousPath = oldPath
+	default:
+		f.Status = prreview.FileModified
+	}
+	f.Patch = patches[f.Path]
+	return f
+}
+
+// splitDiff splits a git diff into the patches of each file, keyed by the
+// path of the new file
+func splitDiff(diff string) map[string]string {
+	patches := map[string]string{}
+	var path string
+	var patch strings.Builder
+	flush := func() {
+		if path != "" {
+			patches[path] = patch.String()
+		}
+		path = ""
+		patch.Reset()
+	}
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+		}
+		if name, ok := strings.CutPrefix(strings.TrimRight(line, "\n"), "+++ b/"); ok && path == "" {
+			path = name
+		}
+		patch.WriteString(line)
+	}
+	flush()
+	return patches
+}
+
+// FileContent returns the content of path in the repository of pr at ref
+func (p *Provider) FileContent(ctx context.Context, pr *prreview.PullRequest, path, ref string) (string, error) {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	var content []byte
+	apiURL := fmt.Sprintf("%s/repositories/%s/src/%s/%s", p.opts.APIURL, pr.Repository, url.PathEscape(ref), strings.Join(segments, "/"))
+	if err := p.do(ctx, http.MethodGet, apiURL, nil, &content); err != nil {
+		return "", fmt.Errorf("failed to get %s at %s: %w", path, ref, err)
+	}
+	return string(content), nil
+}
+
+// Publish reports a review as a Code Insights report on the head commit
+// of pr, failed when the review failed and passed otherwise, with the
+// annotations added in the batches the API accepts
+func (p *Provider) Publish(ctx context.Context, pr *prreview.PullRequest, report *prreview.Report) error {
+	result := "PASSED"
+	if report.Conclusion == prreview.ConclusionFailure {
+		result = "FAILED"
+	}
+	reportURL := fmt.Sprintf("%s/repositories/%s/commit/%s/reports/%s", p.opts.APIURL, pr.Repository, pr.HeadSHA, p.opts.ReportID)
+	body := map[string]string{
+		"title":       report.Title,
+		"details":     truncate(report.Summary, maxDetails),
+		"report_type": "BUG",
+		"reporter":    "mcp-go-assistant",
+		"result":      result,
+	}
+	if err := p.do(ctx, http.MethodPut, reportURL, body, nil); err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+
+	annotations := report.Annotations[:min(len(report.Annotations), maxAnnotations)]
+	for start := 0; start < len(annotations); start += maxAnnotationsPerRequest {
+		batch := annotations[start:min(start+maxAnnotationsPerRequest, len(annotations))]
+		body := make([]map[string]any, 0, len(batch))
+		for i, a := range batch {
+			body = append(body, map[string]any{
+				"external_id":     fmt.Sprintf("%s-%d", p.opts.ReportID, start+i),
+				"annotation_type": "CODE_SMELL",
+				"summary":         truncate(fmt.Sprintf("%s: %s", a.Rule, a.Message), maxSummary),
+				"details":         a.Suggestion,
+				"path":            a.Path,
+				"line":            a.StartLine,
+				"severity":        strings.ToUpper(a.Severity),
+			})
+		}
+		if err := p.do(ctx, http.MethodPost, reportURL+"/annotations", body, nil); err != nil {
+			return fmt.Errorf("failed to add annotations: %w", err)
+		}
+	}
+	return nil
+}
+
+// truncate shortens s to at most n bytes
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
+
+// pullRequestURL returns the API URL of pr
+func (p *Provider) pullRequestURL(pr *prreview.PullRequest) string {
+	return fmt.Sprintf("%s/repositories/%s/pullrequests/%d", p.opts.APIURL, pr.Repository, pr.Number)
+}
+
+// do sends a request to apiURL and decodes the JSON response into out, or
+// stores the raw body when out is a *[]byte. Unlike the other providers it
+// takes full URLs, as the API pages with absolute next links.
+func (p *Provider) do(ctx context.Context, method, apiURL string, body, out any) error {
+	req, err := prreview.NewRequest(ctx, method, apiURL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.opts.Token)
+	return prreview.Do(p.opts.HTTPClient, req, out)
+}
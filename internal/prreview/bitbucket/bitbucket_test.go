@@ -0,0 +1,212 @@
+package bitbucket
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"mcp-go-assistant/internal/prreview"
+)
+
+const testSecret = "webhook-secret"
+
+const testDiff = `diff --git a/a.go b/a.go
+index 1111111..2222222 100644
+--- a/a.go
++++ b/a.go
+@@ -1 +1,2 @@
+ package a
++func A() {}
+diff --git a/gone.go b/gone.go
+deleted file mode 100644
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package gone
+diff --git a/old.go b/pkg/new.go
+similarity index 90%
+rename from old.go
+rename to pkg/new.go
+--- a/old.go
++++ b/pkg/new.go
+@@ -2 +2 @@
+-func Old() {}
++func New() {}
+`
+
+// fakeBitbucket is an API server with pull request 4 of ws/app
+type fakeBitbucket struct {
+	t   *testing.T
+	url string
+
+	mu          sync.Mutex
+	report      map[string]string
+	annotations [][]map[string]any
+}
+
+func (f *fakeBitbucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		http.Error(w, `{"type":"error","error":{"message":"Unauthorized"}}`, http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/repositories/ws/app/pullrequests/4/diff":
+		fmt.Fprint(w, testDiff)
+	case r.Method == http.MethodGet && r.URL.Path == "/repositories/ws/app/pullrequests/4/diffstat" && r.URL.Query().Get("page") == "":
+		fmt.Fprintf(w, `{"values":[
+			{"status":"modified","old":{"path":"a.go"},"new":{"path":"a.go"}},
+			{"status":"removed","old":{"path":"gone.go"},"new":null}
+		],"next":%q}`, f.url+"/repositories/ws/app/pullrequests/4/diffstat?pagelen=100&page=2")
+	case r.Method == http.MethodGet && r.URL.Path == "/repositories/ws/app/pullrequests/4/diffstat":
+		fmt.Fprint(w, `{"values":[{"status":"renamed","old":{"path":"old.go"},"new":{"path":"pkg/new.go"}}]}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/repositories/ws/app/src/abc123/pkg/new.go":
+		fmt.Fprint(w, "package pkg")
+	case r.Method == http.MethodPut && r.URL.Path == "/repositories/ws/app/commit/abc123/reports/"+DefaultReportID:
+		_ = json.NewDecoder(r.Body).Decode(&f.report)
+		fmt.Fprint(w, `{}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/repositories/ws/app/commit/abc123/reports/"+DefaultReportID+"/annotations":
+		var batch []map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&batch)
+		f.annotations = append(f.annotations, batch)
+		fmt.Fprint(w, `[]`)
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		http.NotFound(w, r)
+	}
+}
+
+// newTestProvider returns a provider talking to a fake API server and the
+// server
+func newTestProvider(t *testing.T) (*Provider, *fakeBitbucket) {
+	t.Helper()
+	fake := &fakeBitbucket{t: t}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	fake.url = server.URL
+
+	p, err := New(Options{APIURL: server.URL, Token: "token", WebhookSecret: testSecret})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return p, fake
+}
+
+func pullRequestPayload(draft bool) map[string]any {
+	return map[string]any{
+		"pullrequest": map[string]any{
+			"id":          4,
+			"draft":       draft,
+			"source":      map[string]any{"commit": map[string]any{"hash": "abc123"}},
+			"destination": map[string]any{"commit": map[string]any{"hash": "def456"}},
+		},
+		"repository": map[string]any{"full_name": "ws/app"},
+	}
+}
+
+func TestProvider_ParseWebhook(t *testing.T) {
+	p, _ := newTestProvider(t)
+
+	tests := []struct {
+		name    string
+		event   string
+		payload any
+		secret  string
+		wantErr error
+		wantPR  bool
+	}{
+		{name: "bad signature", event: "pullrequest:created", payload: pullRequestPayload(false), secret: "other", wantErr: prreview.ErrInvalidSignature},
+		{name: "push", event: "repo:push", payload: map[string]any{}, secret: testSecret},
+		{name: "merged", event: "pullrequest:fulfilled", payload: pullRequestPayload(false), secret: testSecret},
+		{name: "draft", event: "pullrequest:created", payload: pullRequestPayload(true), secret: testSecret},
+		{name: "created", event: "pullrequest:created", payload: pullRequestPayload(false), secret: testSecret, wantPR: true},
+		{name: "updated", event: "pullrequest:updated", payload: pullRequestPayload(false), secret: testSecret, wantPR: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.payload)
+			mac := hmac.New(sha256.New, []byte(tt.secret))
+			mac.Write(body)
+			req := httptest.NewRequest(http.MethodPost, "/webhook/bitbucket", strings.NewReader(string(body)))
+			req.Header.Set("X-Event-Key", tt.event)
+			req.Header.Set("X-Hub-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+			pr, err := p.ParseWebhook(req, body)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseWebhook() error = %v, want %v", err, tt.wantErr)
+			}
+			if (pr != nil) != tt.wantPR {
+				t.Fatalf("ParseWebhook() = %+v, want a pull request: %v", pr, tt.wantPR)
+			}
+			if pr != nil && (pr.Repository != "ws/app" || pr.Number != 4 || pr.HeadSHA != "abc123" || pr.BaseSHA != "def456") {
+				t.Errorf("unexpected pull request %+v", pr)
+			}
+		})
+	}
+}
+
+func TestProvider_ChangesAndContent(t *testing.T) {
+	p, _ := newTestProvider(t)
+	pr := &prreview.PullRequest{Repository: "ws/app", Number: 4, HeadSHA: "abc123", BaseSHA: "def456"}
+
+	changes, err := p.Changes(context.Background(), pr)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if changes.BaseSHA != "def456" || len(changes.Files) != 3 {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	if f := changes.Files[0]; f.Path != "a.go" || f.Status != prreview.FileModified || !prreview.AddedLines(f.Patch)[2] {
+		t.Errorf("unexpected file %+v", f)
+	}
+	if f := changes.Files[1]; f.Path != "gone.go" || f.Status != prreview.FileRemoved {
+		t.Errorf("unexpected file %+v", f)
+	}
+	f := changes.Files[2]
+	if f.Path != "pkg/new.go" || f.PreviousPath != "old.go" || f.Status != prreview.FileRenamed {
+		t.Errorf("unexpected file %+v", f)
+	}
+	if added := prreview.AddedLines(f.Patch); len(added) != 1 || !added[2] {
+		t.Errorf("expected line 2 added to pkg/new.go, got %v from %q", added, f.Patch)
+	}
+
+	content, err := p.FileContent(context.Background(), pr, "pkg/new.go", "abc123")
+	if err != nil || content != "package pkg" {
+		t.Errorf("FileContent() = %q, %v", content, err)
+	}
+}
+
+func TestProvider_Publish(t *testing.T) {
+	p, fake := newTestProvider(t)
+	pr := &prreview.PullRequest{Repository: "ws/app", Number: 4, HeadSHA: "abc123"}
+
+	report := &prreview.Report{Conclusion: prreview.ConclusionFailure, Title: "150 issue(s)", Summary: strings.Repeat("x", 3000)}
+	for i := range 150 {
+		report.Annotations = append(report.Annotations, prreview.Annotation{Path: "a.go", StartLine: i + 1, Severity: "high", Rule: "naming", Message: "Rename it", Suggestion: "Use camelCase"})
+	}
+	if err := p.Publish(context.Background(), pr, report); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if fake.report["result"] != "FAILED" || fake.report["title"] != "150 issue(s)" || len(fake.report["details"]) != maxDetails {
+		t.Errorf("unexpected report %v", fake.report["result"])
+	}
+	if len(fake.annotations) != 2 || len(fake.annotations[0]) != maxAnnotationsPerRequest || len(fake.annotations[1]) != 50 {
+		t.Fatalf("expected annotations in batches of %d, got %d batches", maxAnnotationsPerRequest, len(fake.annotations))
+	}
+	a := fake.annotations[1][49]
+	if a["external_id"] != DefaultReportID+"-149" || a["severity"] != "HIGH" || a["line"] != float64(150) || a["summary"] != "naming: Rename it" || a["details"] != "Use camelCase" {
+		t.Errorf("unexpected annotation %v", a)
+	}
+}
@@ -0,0 +1,92 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// installationToken is an access token of one installation of the app
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// parsePrivateKey parses the PEM private key of the app, in PKCS #1 as
+// GitHub generates it or PKCS #8
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// appJWT returns a JSON Web Token authenticating the app itself, valid for
+// ten minutes with a minute of allowance for clock drift
+func (p *Provider) appJWT() (string, error) {
+	now := p.now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]int64{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": p.opts.AppID,
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(nil, p.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign app token: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// installationToken returns an access token for installation, reusing the
+// previous one until shortly before it expires
+func (p *Provider) installationToken(ctx context.Context, installation int64) (string, error) {
+	p.mu.Lock()
+	cached, ok := p.tokens[installation]
+	p.mu.Unlock()
+	if ok && p.now().Add(time.Minute).Before(cached.ExpiresAt) {
+		return cached.Token, nil
+	}
+
+	jwt, err := p.appJWT()
+	if err != nil {
+		return "", err
+	}
+	var token installationToken
+	path := "/app/installations/" + strconv.FormatInt(installation, 10) + "/access_tokens"
+	if err := p.do(ctx, jwt, http.MethodPost, path, "", nil, &token); err != nil {
+		return "", fmt.Errorf("failed to get installation token: %w", err)
+	}
+
+	p.mu.Lock()
+	p.tokens[installation] = token
+	p.mu.Unlock()
+	return token.Token, nil
+}
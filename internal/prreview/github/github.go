@@ -0,0 +1,345 @@
+// Package github connects the pull request reviewer to GitHub as a GitHub
+// App: it receives pull_request webhooks and reports reviews as check runs
+// with annotations through the Checks API
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mcp-go-assistant/internal/codereview"
+	"mcp-go-assistant/internal/prreview"
+)
+
+// DefaultAPIURL is the REST API of github.com
+const DefaultAPIURL = "https://api.github.com"
+
+// DefaultCheckName is the name of the check runs created by the app
+const DefaultCheckName = "mcp-go-assistant review"
+
+// filesPerPage is the page size used to list the files of a pull request,
+// the largest the API allows
+const filesPerPage = 100
+
+// maxAnnotations is the most annotations the Checks API accepts per request
+const maxAnnotations = 50
+
+// reviewedActions are the pull_request actions that trigger a review
+var reviewedActions = map[string]bool{
+	"opened":           true,
+	"reopened":         true,
+	"synchronize":      true,
+	"ready_for_review": true,
+}
+
+// Options configures the provider
+type Options struct {
+	APIURL        string // REST API base URL; empty uses DefaultAPIURL
+	AppID         int64
+	PrivateKey    []byte // PEM private key of the app
+	WebhookSecret string
+	CheckName     string       // Empty uses DefaultCheckName
+	HTTPClient    *http.Client // Empty uses http.DefaultClient
+}
+
+// Provider calls the REST API as a GitHub App, authenticating each
+// installation with a token obtained with the JWT of the app
+type Provider struct {
+	opts Options
+	key  *rsa.PrivateKey
+	now  func() time.Time
+
+	mu     sync.Mutex
+	tokens map[int64]installationToken
+}
+
+// pullRequestEvent is the part of a pull_request webhook payload the
+// provider uses
+type pullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Draft bool `json:"draft"`
+		Head  struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+		Base struct {
+			SHA string `json:"sha"`
+		} `json:"base"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Installation struct {
+		ID int64 `json:"id"`
+	} `json:"installation"`
+}
+
+// prFile is a file changed by a pull request
+type prFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename"`
+	Status           string `json:"status"` // "added", "removed", "modified", "renamed", ...
+	Patch            string `json:"patch"`
+}
+
+// checkRun is the body of check run requests
+type checkRun struct {
+	Name        string       `json:"name,omitempty"`
+	HeadSHA     string       `json:"head_sha,omitempty"`
+	Status      string       `json:"status,omitempty"`
+	Conclusion  string       `json:"conclusion,omitempty"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+	Output      *checkOutput `json:"output,omitempty"`
+}
+
+// checkOutput is the report of a check run; annotations of later updates
+// are added to those already sent
+type checkOutput struct {
+	Title       string       `json:"title"`
+	Summary     string       `json:"summary"`
+	Annotations []annotation `json:"annotations,omitempty"`
+}
+
+// annotation marks lines of a file in the pull request
+type annotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"` // "notice", "warning" or "failure"
+	Title           string `json:"title,omitempty"`
+	Message         string `json:"message"`
+}
+
+// New returns a provider for opts
+func New(opts Options) (*Provider, error) {
+	if opts.AppID <= 0 {
+		return nil, fmt.Errorf("app_id is required")
+	}
+	if opts.WebhookSecret == "" {
+		return nil, fmt.Errorf("webhook_secret is required")
+	}
+	key, err := parsePrivateKey(opts.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.APIURL == "" {
+		opts.APIURL = DefaultAPIURL
+	}
+	opts.APIURL = strings.TrimSuffix(opts.APIURL, "/")
+	if opts.CheckName == "" {
+		opts.CheckName = DefaultCheckName
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	return &Provider{opts: opts, key: key, now: time.Now, tokens: map[int64]installationToken{}}, nil
+}
+
+// Name returns "github"
+func (p *Provider) Name() string {
+	return "github"
+}
+
+// ParseWebhook verifies the X-Hub-Signature-256 header of a delivery and
+// returns the pull request of pull_request events that update a pull
+// request ready for review
+func (p *Provider) ParseWebhook(r *http.Request, body []byte) (*prreview.PullRequest, error) {
+	if !p.validSignature(body, r.Header.Get("X-Hub-Signature-256")) {
+		return nil, prreview.ErrInvalidSignature
+	}
+	if r.Header.Get("X-GitHub-Event") != "pull_request" {
+		return nil, nil
+	}
+
+	var event pullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	if !reviewedActions[event.Action] || event.PullRequest.Draft {
+		return nil, nil
+	}
+	return &prreview.PullRequest{
+		Repository: event.Repository.FullName,
+		Number:     event.Number,
+		HeadSHA:    event.PullRequest.Head.SHA,
+		BaseSHA:    event.PullRequest.Base.SHA,
+		Action:     event.Action,
+		ProviderID: event.Installation.ID,
+	}, nil
+}
+
+// validSignature reports whether signature, the X-Hub-Signature-256
+// header, is the HMAC of body with the webhook secret
+func (p *Provider) validSignature(body []byte, signature string) bool {
+	hexSum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	sum, err := hex.DecodeString(hexSum)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(p.opts.WebhookSecret))
+	mac.Write(body)
+	return hmac.Equal(sum, mac.Sum(nil))
+}
+
+// Changes lists the files changed by pr, compared to the base commit of
+// the webhook
+func (p *Provider) Changes(ctx context.Context, pr *prreview.PullRequest) (*prreview.Changes, error) {
+	token, err := p.installationToken(ctx, pr.ProviderID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &prreview.Changes{BaseSHA: pr.BaseSHA}
+	for page := 1; ; page++ {
+		var batch []prFile
+		path := fmt.Sprintf("/repos/%s/pulls/%d/files?per_page=%d&page=%d", pr.Repository, pr.Number, filesPerPage, page)
+		if err := p.do(ctx, token, http.MethodGet, path, "", nil, &batch); err != nil {
+			return nil, fmt.Errorf("failed to list pull request files: %w", err)
+		}
+		for _, f := range batch {
+			status := f.Status
+			switch status {
+			case prreview.FileAdded, prreview.FileRemoved, prreview.FileRenamed:
+			default:
+				// "changed", "copied" and "unchanged" read like modifications
+				status = prreview.FileModified
+			}
+			changes.Files = append(changes.Files, prreview.File{
+				Path:         f.Filename,
+				PreviousPath: f.PreviousFilename,
+				Status:       status,
+				Patch:        f.Patch,
+			})
+		}
+		if len(batch) < filesPerPage {
+			return changes, nil
+		}
+	}
+}
+
+// FileContent returns the content of path in the repository of pr at ref
+func (p *Provider) FileContent(ctx context.Context, pr *prreview.PullRequest, path, ref string) (string, error) {
+	token, err := p.installationToken(ctx, pr.ProviderID)
+	if err != nil {
+		return "", err
+	}
+
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	var content []byte
+	apiPath := fmt.Sprintf("/repos/%s/contents/%s?ref=%s", pr.Repository, strings.Join(segments, "/"), url.QueryEscape(ref))
+	if err := p.do(ctx, token, http.MethodGet, apiPath, "application/vnd.github.raw+json", nil, &content); err != nil {
+		return "", fmt.Errorf("failed to get %s at %s: %w", path, ref, err)
+	}
+	return string(content), nil
+}
+
+// Publish reports a review as a check run on the head commit of pr. The
+// annotations are sent in the batches the Checks API accepts, the first
+// creating the check run and the last completing it.
+func (p *Provider) Publish(ctx context.Context, pr *prreview.PullRequest, report *prreview.Report) error {
+	token, err := p.installationToken(ctx, pr.ProviderID)
+	if err != nil {
+		return err
+	}
+
+	annotations := make([]annotation, 0, len(report.Annotations))
+	for _, a := range report.Annotations {
+		annotations = append(annotations, toAnnotation(a))
+	}
+
+	var id int64
+	for {
+		batch := annotations[:min(len(annotations), maxAnnotations)]
+		annotations = annotations[len(batch):]
+
+		run := checkRun{Output: &checkOutput{Title: report.Title, Summary: report.Summary, Annotations: batch}}
+		if len(annotations) == 0 {
+			completed := p.now()
+			run.Status = "completed"
+			run.Conclusion = report.Conclusion
+			run.CompletedAt = &completed
+		} else {
+			run.Status = "in_progress"
+		}
+
+		if id == 0 {
+			run.Name = p.opts.CheckName
+			run.HeadSHA = pr.HeadSHA
+			var created struct {
+				ID int64 `json:"id"`
+			}
+			if err := p.do(ctx, token, http.MethodPost, "/repos/"+pr.Repository+"/check-runs", "", run, &created); err != nil {
+				return fmt.Errorf("failed to create check run: %w", err)
+			}
+			id = created.ID
+		} else {
+			path := "/repos/" + pr.Repository + "/check-runs/" + strconv.FormatInt(id, 10)
+			if err := p.do(ctx, token, http.MethodPatch, path, "", run, nil); err != nil {
+				return fmt.Errorf("failed to update check run: %w", err)
+			}
+		}
+		if len(annotations) == 0 {
+			return nil
+		}
+	}
+}
+
+// toAnnotation returns the check run annotation of a, with issues of high
+// and critical severity as failures
+func toAnnotation(a prreview.Annotation) annotation {
+	level := "notice"
+	switch {
+	case codereview.SeverityAtLeast(a.Severity, "high"):
+		level = "failure"
+	case a.Severity == "medium":
+		level = "warning"
+	}
+	message := a.Message
+	if a.Suggestion != "" {
+		message += "\n\n" + a.Suggestion
+	}
+	return annotation{
+		Path:            a.Path,
+		StartLine:       a.StartLine,
+		EndLine:         a.EndLine,
+		AnnotationLevel: level,
+		Title:           fmt.Sprintf("%s (%s)", a.Rule, a.Severity),
+		Message:         message,
+	}
+}
+
+// do sends a request to the API authenticated with token and decodes the
+// JSON response into out, or stores the raw body when out is a *[]byte. An
+// empty accept asks for JSON.
+func (p *Provider) do(ctx context.Context, token, method, path, accept string, body, out any) error {
+	req, err := prreview.NewRequest(ctx, method, p.opts.APIURL+path, body)
+	if err != nil {
+		return err
+	}
+	if accept == "" {
+		accept = "application/vnd.github+json"
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	return prreview.Do(p.opts.HTTPClient, req, out)
+}
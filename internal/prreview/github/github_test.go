@@ -0,0 +1,254 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mcp-go-assistant/internal/prreview"
+)
+
+const testSecret = "webhook-secret"
+
+// fakeGitHub is an API server with pull request 12 of octo/app, installed
+// as installation 7
+type fakeGitHub struct {
+	t   *testing.T
+	key *rsa.PrivateKey
+
+	mu         sync.Mutex
+	tokenCalls int
+	runs       []checkRun // Created check run followed by its updates
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/app/installations/7/access_tokens":
+		f.tokenCalls++
+		f.verifyJWT(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		fmt.Fprintf(w, `{"token":"installation-token","expires_at":%q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	case r.Header.Get("Authorization") != "Bearer installation-token":
+		http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/octo/app/pulls/12/files":
+		_ = json.NewEncoder(w).Encode([]prFile{
+			{Filename: "a.go", Status: "modified", Patch: "@@ -1 +1,2 @@\n package a\n+func A() {}"},
+			{Filename: "b.go", PreviousFilename: "old/b.go", Status: "renamed"},
+			{Filename: "c.go", Status: "copied"},
+		})
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/octo/app/contents/dir/a b.go":
+		if r.Header.Get("Accept") != "application/vnd.github.raw+json" {
+			f.t.Errorf("unexpected Accept %q", r.Header.Get("Accept"))
+		}
+		fmt.Fprintf(w, "package a // %s", r.URL.Query().Get("ref"))
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/octo/app/check-runs":
+		var run checkRun
+		_ = json.NewDecoder(r.Body).Decode(&run)
+		f.runs = append(f.runs, run)
+		fmt.Fprint(w, `{"id":99}`)
+	case r.Method == http.MethodPatch && r.URL.Path == "/repos/octo/app/check-runs/99":
+		var run checkRun
+		_ = json.NewDecoder(r.Body).Decode(&run)
+		f.runs = append(f.runs, run)
+		fmt.Fprint(w, `{"id":99}`)
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		http.NotFound(w, r)
+	}
+}
+
+// verifyJWT checks the app token is signed with the key of the app
+func (f *fakeGitHub) verifyJWT(token string) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		f.t.Errorf("malformed app token %q", token)
+		return
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&f.key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		f.t.Errorf("app token signature: %v", err)
+	}
+	claims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	if !strings.Contains(string(claims), `"iss":42`) {
+		f.t.Errorf("unexpected claims %s", claims)
+	}
+}
+
+// newTestProvider returns a provider talking to a fake API server and the
+// server
+func newTestProvider(t *testing.T) (*Provider, *fakeGitHub) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeGitHub{t: t, key: key}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	p, err := New(Options{
+		APIURL:        server.URL,
+		AppID:         42,
+		PrivateKey:    pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		WebhookSecret: testSecret,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return p, fake
+}
+
+// webhook returns a delivery of event signed with secret
+func webhook(event string, payload any, secret string) (*http.Request, []byte) {
+	body, _ := json.Marshal(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook/github", strings.NewReader(string(body)))
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req, body
+}
+
+func pullRequestPayload(action string, draft bool) map[string]any {
+	return map[string]any{
+		"action": action,
+		"number": 12,
+		"pull_request": map[string]any{
+			"draft": draft,
+			"head":  map[string]any{"sha": "head-sha"},
+			"base":  map[string]any{"sha": "base-sha"},
+		},
+		"repository":   map[string]any{"full_name": "octo/app"},
+		"installation": map[string]any{"id": 7},
+	}
+}
+
+func TestProvider_ParseWebhook(t *testing.T) {
+	p, _ := newTestProvider(t)
+
+	tests := []struct {
+		name    string
+		event   string
+		payload any
+		secret  string
+		wantErr error
+		wantPR  bool
+	}{
+		{name: "bad signature", event: "pull_request", payload: pullRequestPayload("opened", false), secret: "other", wantErr: prreview.ErrInvalidSignature},
+		{name: "ping", event: "ping", payload: map[string]any{"zen": "hi"}, secret: testSecret},
+		{name: "closed", event: "pull_request", payload: pullRequestPayload("closed", false), secret: testSecret},
+		{name: "draft", event: "pull_request", payload: pullRequestPayload("opened", true), secret: testSecret},
+		{name: "synchronize", event: "pull_request", payload: pullRequestPayload("synchronize", false), secret: testSecret, wantPR: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr, err := p.ParseWebhook(webhook(tt.event, tt.payload, tt.secret))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseWebhook() error = %v, want %v", err, tt.wantErr)
+			}
+			if (pr != nil) != tt.wantPR {
+				t.Fatalf("ParseWebhook() = %+v, want a pull request: %v", pr, tt.wantPR)
+			}
+			if pr != nil && (pr.Repository != "octo/app" || pr.Number != 12 || pr.HeadSHA != "head-sha" || pr.BaseSHA != "base-sha" || pr.ProviderID != 7) {
+				t.Errorf("unexpected pull request %+v", pr)
+			}
+		})
+	}
+}
+
+func TestProvider_ChangesAndContent(t *testing.T) {
+	p, fake := newTestProvider(t)
+	pr := &prreview.PullRequest{Repository: "octo/app", Number: 12, BaseSHA: "base-sha", ProviderID: 7}
+
+	changes, err := p.Changes(context.Background(), pr)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	want := []prreview.File{
+		{Path: "a.go", Status: prreview.FileModified, Patch: "@@ -1 +1,2 @@\n package a\n+func A() {}"},
+		{Path: "b.go", PreviousPath: "old/b.go", Status: prreview.FileRenamed},
+		{Path: "c.go", Status: prreview.FileModified},
+	}
+	if changes.BaseSHA != "base-sha" || fmt.Sprint(changes.Files) != fmt.Sprint(want) {
+		t.Errorf("Changes() = %+v, want %+v", changes, want)
+	}
+
+	content, err := p.FileContent(context.Background(), pr, "dir/a b.go", "head-sha")
+	if err != nil || content != "package a // head-sha" {
+		t.Errorf("FileContent() = %q, %v", content, err)
+	}
+	if fake.tokenCalls != 1 {
+		t.Errorf("expected the installation token to be reused, got %d token requests", fake.tokenCalls)
+	}
+}
+
+func TestProvider_Publish(t *testing.T) {
+	p, fake := newTestProvider(t)
+	pr := &prreview.PullRequest{Repository: "octo/app", Number: 12, HeadSHA: "head-sha", ProviderID: 7}
+
+	// 60 annotations need a second request after the one creating the run
+	report := &prreview.Report{Conclusion: prreview.ConclusionFailure, Title: "61 issue(s)", Summary: "Reviewed 1 file"}
+	for range 60 {
+		report.Annotations = append(report.Annotations, prreview.Annotation{Path: "a.go", StartLine: 3, EndLine: 3, Severity: "medium", Rule: "naming", Message: "Rename it"})
+	}
+	report.Annotations = append(report.Annotations, prreview.Annotation{Path: "a.go", StartLine: 4, EndLine: 6, Severity: "high", Rule: "unchecked-error", Message: "Error not checked", Suggestion: "Check it"})
+
+	if err := p.Publish(context.Background(), pr, report); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(fake.runs) != 2 {
+		t.Fatalf("expected a created and an updated check run, got %d requests", len(fake.runs))
+	}
+	created, updated := fake.runs[0], fake.runs[1]
+	if created.Name != DefaultCheckName || created.HeadSHA != "head-sha" || created.Status != "in_progress" || len(created.Output.Annotations) != maxAnnotations {
+		t.Errorf("unexpected created check run %+v", created)
+	}
+	if updated.Status != "completed" || updated.Conclusion != "failure" || len(updated.Output.Annotations) != 11 {
+		t.Errorf("unexpected check run update %+v", updated)
+	}
+	last := updated.Output.Annotations[10]
+	if last.StartLine != 4 || last.EndLine != 6 || last.AnnotationLevel != "failure" || last.Message != "Error not checked\n\nCheck it" {
+		t.Errorf("unexpected annotation %+v", last)
+	}
+	if level := created.Output.Annotations[0].AnnotationLevel; level != "warning" {
+		t.Errorf("medium issues should be warnings, got %q", level)
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{name: "no app id", opts: Options{WebhookSecret: "s", PrivateKey: []byte("x")}},
+		{name: "no secret", opts: Options{AppID: 1, PrivateKey: []byte("x")}},
+		{name: "bad key", opts: Options{AppID: 1, WebhookSecret: "s", PrivateKey: []byte("not pem")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.opts); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+// Package gitlab connects the pull request reviewer to GitLab: it receives
+// merge request webhooks and reports reviews as discussions on the changed
+// lines, a summary note and a commit status
+package gitlab
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"mcp-go-assistant/internal/prreview"
+)
+
+// DefaultURL is gitlab.com
+const DefaultURL = "https://gitlab.com"
+
+// DefaultStatusName is the name of the commit statuses set by the provider
+const DefaultStatusName = "mcp-go-assistant review"
+
+// diffsPerPage is the page size used to list the diffs of a merge request
+const diffsPerPage = 100
+
+// maxDiscussions bounds the discussions opened per review; further issues
+// are listed in the summary note
+const maxDiscussions = 50
+
+// reviewedActions are the merge request actions that trigger a review;
+// updates only when they push commits
+var reviewedActions = map[string]bool{
+	"open":   true,
+	"reopen": true,
+	"update": true,
+}
+
+// Options configures the provider
+type Options struct {
+	URL           string // Instance URL; empty uses DefaultURL
+	Token         string // Access token with the api scope
+	WebhookSecret string // Secret token of the webhook
+	StatusName    string // Empty uses DefaultStatusName
+	HTTPClient    *http.Client
+}
+
+// Provider calls the REST API of a GitLab instance with an access token
+type Provider struct {
+	opts Options
+}
+
+// mergeRequestEvent is the part of a merge request webhook payload the
+// provider uses
+type mergeRequestEvent struct {
+	Project struct {
+		ID                int64  `json:"id"`
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID        int    `json:"iid"`
+		Action     string `json:"action"`
+		Draft      bool   `json:"draft"`
+		OldRev     string `json:"oldrev"` // Set on updates that push commits
+		LastCommit struct {
+			ID string `json:"id"`
+		} `json:"last_commit"`
+	} `json:"object_attributes"`
+}
+
+// diffRefs are the commits a merge request diff is computed from
+type diffRefs struct {
+	BaseSHA  string `json:"base_sha"`
+	StartSHA string `json:"start_sha"`
+	HeadSHA  string `json:"head_sha"`
+}
+
+// mrDiff is a file changed by a merge request
+type mrDiff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+	Diff        string `json:"diff"`
+}
+
+// New returns a provider for opts
+func New(opts Options) (*Provider, error) {
+	if opts.Token == "" {
+		return nil, fmt.Errorf("token is required")
+	}
+	if opts.WebhookSecret == "" {
+		return nil, fmt.Errorf("webhook_secret is required")
+	}
+	if opts.URL == "" {
+		opts.URL = DefaultURL
+	}
+	opts.URL = strings.TrimSuffix(opts.URL, "/")
+	if opts.StatusName == "" {
+		opts.StatusName = DefaultStatusName
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	return &Provider{opts: opts}, nil
+}
+
+// Name returns "gitlab"
+func (p *Provider) Name() string {
+	return "gitlab"
+}
+
+// ParseWebhook verifies the X-Gitlab-Token header of a delivery and
+// returns the merge request of merge request events that open it or push
+// commits to it while it is not a draft
+func (p *Provider) ParseWebhook(r *http.Request, body []byte) (*prreview.PullRequest, error) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Gitlab-Token")), []byte(p.opts.WebhookSecret)) != 1 {
+		return nil, prreview.ErrInvalidSignature
+	}
+	if r.Header.Get("X-Gitlab-Event") != "Merge Request Hook" {
+		return nil, nil
+	}
+
+	var event mergeRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	attrs := event.ObjectAttributes
+	if !reviewedActions[attrs.Action] || (attrs.Action == "update" && attrs.OldRev == "") || attrs.Draft {
+		return nil, nil
+	}
+	return &prreview.PullRequest{
+		Repository: event.Project.PathWithNamespace,
+		Number:     attrs.IID,
+		HeadSHA:    attrs.LastCommit.ID,
+		Action:     attrs.Action,
+		ProviderID: event.Project.ID,
+	}, nil
+}
+
+// Changes lists the files changed by pr, compared to the merge base of
+// the merge request
+func (p *Provider) Changes(ctx context.Context, pr *prreview.PullRequest) (*prreview.Changes, error) {
+	refs, err := p.diffRefs(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &prreview.Changes{BaseSHA: refs.BaseSHA}
+	for page := 1; ; page++ {
+		var batch []mrDiff
+		path := fmt.Sprintf("%s/diffs?per_page=%d&page=%d", p.mergeRequestPath(pr), diffsPerPage, page)
+		if err := p.do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return nil, fmt.Errorf("failed to list merge request diffs: %w", err)
+		}
+		for _, d := range batch {
+			f := prreview.File{Path: d.NewPath, Status: prreview.FileModified, Patch: d.Diff}
+			switch {
+			case d.NewFile:
+				f.Status = prreview.FileAdded
+			case d.DeletedFile:
+				f.Status = prreview.FileRemoved
+			case d.RenamedFile:
+				f.Status = prreview.FileRenamed
+				f.PreviousPath = d.OldPath
+			}
+			changes.Files = append(changes.Files, f)
+		}
+		if len(batch) < diffsPerPage {
+			return changes, nil
+		}
+	}
+}
+
+// FileContent returns the content of path in the project of pr at ref
+func (p *Provider) FileContent(ctx context.Context, pr *prreview.PullRequest, path, ref string) (string, error) {
+	var content []byte
+	apiPath := fmt.Sprintf("/projects/%d/repository/files/%s/raw?ref=%s", pr.ProviderID, url.PathEscape(path), url.QueryEscape(ref))
+	if err := p.do(ctx, http.MethodGet, apiPath, nil, &content); err != nil {
+		return "", fmt.Errorf("failed to get %s at %s: %w", path, ref, err)
+	}
+	return string(content), nil
+}
+
+// Publish opens a discussion on the changed line of each annotation, adds
+// a note with the summary and sets a commit status on the head commit.
+// Annotations beyond maxDiscussions, or that GitLab cannot place on the
+// diff, are listed in the note instead.
+func (p *Provider) Publish(ctx context.Context, pr *prreview.PullRequest, report *prreview.Report) error {
+	refs, err := p.diffRefs(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	var unplaced []string
+	for i, a := range report.Annotations {
+		if i < maxDiscussions {
+			discussion := map[string]any{
+				"body": annotationBody(a),
+				"position": map[string]any{
+					"position_type": "text",
+					"base_sha":      refs.BaseSHA,
+					"start_sha":     refs.StartSHA,
+					"head_sha":      refs.HeadSHA,
+					"old_path":      a.Path,
+					"new_path":      a.Path,
+					"new_line":      a.StartLine,
+				},
+			}
+			if err := p.do(ctx, http.MethodPost, p.mergeRequestPath(pr)+"/discussions", discussion, nil); err == nil {
+				continue
+			}
+		}
+		unplaced = append(unplaced, fmt.Sprintf("- `%s:%d` **%s** (%s): %s", a.Path, a.StartLine, a.Rule, a.Severity, a.Message))
+	}
+
+	var note strings.Builder
+	fmt.Fprintf(&note, "#### %s: %s\n\n**%s**\n\n%s", p.opts.StatusName, report.Conclusion, report.Title, report.Summary)
+	if len(unplaced) > 0 {
+		fmt.Fprintf(&note, "\n\n### Other issues\n\n%s", strings.Join(unplaced, "\n"))
+	}
+	if err := p.do(ctx, http.MethodPost, p.mergeRequestPath(pr)+"/notes", map[string]string{"body": note.String()}, nil); err != nil {
+		return fmt.Errorf("failed to add note: %w", err)
+	}
+
+	state := "success"
+	if report.Conclusion == prreview.ConclusionFailure {
+		state = "failed"
+	}
+	status := map[string]string{"state": state, "name": p.opts.StatusName, "description": report.Title}
+	if err := p.do(ctx, http.MethodPost, fmt.Sprintf("/projects/%d/statuses/%s", pr.ProviderID, pr.HeadSHA), status, nil); err != nil {
+		return fmt.Errorf("failed to set commit status: %w", err)
+	}
+	return nil
+}
+
+// annotationBody returns the Markdown of the discussion of a
+func annotationBody(a prreview.Annotation) string {
+	body := fmt.Sprintf("**%s** (%s): %s", a.Rule, a.Severity, a.Message)
+	if a.Suggestion != "" {
+		body += "\n\n" + a.Suggestion
+	}
+	return body
+}
+
+// diffRefs returns the commits the diff of pr is computed from
+func (p *Provider) diffRefs(ctx context.Context, pr *prreview.PullRequest) (*diffRefs, error) {
+	var mr struct {
+		DiffRefs diffRefs `json:"diff_refs"`
+	}
+	if err := p.do(ctx, http.MethodGet, p.mergeRequestPath(pr), nil, &mr); err != nil {
+		return nil, fmt.Errorf("failed to get merge request: %w", err)
+	}
+	return &mr.DiffRefs, nil
+}
+
+// mergeRequestPath returns the API path of pr
+func (p *Provider) mergeRequestPath(pr *prreview.PullRequest) string {
+	return fmt.Sprintf("/projects/%d/merge_requests/%d", pr.ProviderID, pr.Number)
+}
+
+// do sends a request to the API and decodes the JSON response into out,
+// or stores the raw body when out is a *[]byte
+func (p *Provider) do(ctx context.Context, method, path string, body, out any) error {
+	req, err := prreview.NewRequest(ctx, method, p.opts.URL+"/api/v4"+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("PRIVATE-TOKEN", p.opts.Token)
+	return prreview.Do(p.opts.HTTPClient, req, out)
+}
@@ -0,0 +1,203 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"mcp-go-assistant/internal/prreview"
+)
+
+const testSecret = "webhook-secret"
+
+// fakeGitLab is an API server with merge request 3 of project 5
+type fakeGitLab struct {
+	t *testing.T
+
+	mu          sync.Mutex
+	discussions []map[string]any
+	notes       []string
+	statuses    []map[string]string
+}
+
+func (f *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("PRIVATE-TOKEN") != "token" {
+		http.Error(w, `{"message":"401 Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/5/merge_requests/3":
+		fmt.Fprint(w, `{"diff_refs":{"base_sha":"base","start_sha":"start","head_sha":"head"}}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/5/merge_requests/3/diffs":
+		_ = json.NewEncoder(w).Encode([]mrDiff{
+			{OldPath: "a.go", NewPath: "a.go", Diff: "@@ -1 +1,2 @@\n package a\n+func A() {}\n"},
+			{OldPath: "old.go", NewPath: "new.go", RenamedFile: true},
+			{OldPath: "c.go", NewPath: "c.go", NewFile: true},
+			{OldPath: "d.go", NewPath: "d.go", DeletedFile: true},
+		})
+	case r.Method == http.MethodGet && r.URL.EscapedPath() == "/api/v4/projects/5/repository/files/pkg%2Fa.go/raw":
+		fmt.Fprintf(w, "package a // %s", r.URL.Query().Get("ref"))
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects/5/merge_requests/3/discussions":
+		var discussion map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&discussion)
+		// Lines outside the diff cannot hold a discussion
+		if discussion["position"].(map[string]any)["new_line"].(float64) > 100 {
+			http.Error(w, `{"message":"400 Bad request - Note {:line_code=>[\"can't be blank\"]}"}`, http.StatusBadRequest)
+			return
+		}
+		f.discussions = append(f.discussions, discussion)
+		fmt.Fprint(w, `{"id":"d1"}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects/5/merge_requests/3/notes":
+		var note struct {
+			Body string `json:"body"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&note)
+		f.notes = append(f.notes, note.Body)
+		fmt.Fprint(w, `{"id":1}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects/5/statuses/head":
+		var status map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&status)
+		f.statuses = append(f.statuses, status)
+		fmt.Fprint(w, `{"id":1}`)
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		http.NotFound(w, r)
+	}
+}
+
+// newTestProvider returns a provider talking to a fake API server and the
+// server
+func newTestProvider(t *testing.T) (*Provider, *fakeGitLab) {
+	t.Helper()
+	fake := &fakeGitLab{t: t}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	p, err := New(Options{URL: server.URL + "/", Token: "token", WebhookSecret: testSecret})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return p, fake
+}
+
+func mergeRequestPayload(action, oldrev string, draft bool) map[string]any {
+	return map[string]any{
+		"object_kind": "merge_request",
+		"project":     map[string]any{"id": 5, "path_with_namespace": "group/app"},
+		"object_attributes": map[string]any{
+			"iid":         3,
+			"action":      action,
+			"draft":       draft,
+			"oldrev":      oldrev,
+			"last_commit": map[string]any{"id": "head"},
+		},
+	}
+}
+
+func TestProvider_ParseWebhook(t *testing.T) {
+	p, _ := newTestProvider(t)
+
+	tests := []struct {
+		name    string
+		event   string
+		payload any
+		secret  string
+		wantErr error
+		wantPR  bool
+	}{
+		{name: "wrong token", event: "Merge Request Hook", payload: mergeRequestPayload("open", "", false), secret: "other", wantErr: prreview.ErrInvalidSignature},
+		{name: "push event", event: "Push Hook", payload: map[string]any{"object_kind": "push"}, secret: testSecret},
+		{name: "draft", event: "Merge Request Hook", payload: mergeRequestPayload("open", "", true), secret: testSecret},
+		{name: "update without commits", event: "Merge Request Hook", payload: mergeRequestPayload("update", "", false), secret: testSecret},
+		{name: "merge", event: "Merge Request Hook", payload: mergeRequestPayload("merge", "", false), secret: testSecret},
+		{name: "open", event: "Merge Request Hook", payload: mergeRequestPayload("open", "", false), secret: testSecret, wantPR: true},
+		{name: "push to merge request", event: "Merge Request Hook", payload: mergeRequestPayload("update", "old", false), secret: testSecret, wantPR: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.payload)
+			req := httptest.NewRequest(http.MethodPost, "/webhook/gitlab", strings.NewReader(string(body)))
+			req.Header.Set("X-Gitlab-Event", tt.event)
+			req.Header.Set("X-Gitlab-Token", tt.secret)
+
+			pr, err := p.ParseWebhook(req, body)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseWebhook() error = %v, want %v", err, tt.wantErr)
+			}
+			if (pr != nil) != tt.wantPR {
+				t.Fatalf("ParseWebhook() = %+v, want a merge request: %v", pr, tt.wantPR)
+			}
+			if pr != nil && (pr.Repository != "group/app" || pr.Number != 3 || pr.HeadSHA != "head" || pr.ProviderID != 5) {
+				t.Errorf("unexpected merge request %+v", pr)
+			}
+		})
+	}
+}
+
+func TestProvider_ChangesAndContent(t *testing.T) {
+	p, _ := newTestProvider(t)
+	pr := &prreview.PullRequest{Repository: "group/app", Number: 3, HeadSHA: "head", ProviderID: 5}
+
+	changes, err := p.Changes(context.Background(), pr)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	want := []prreview.File{
+		{Path: "a.go", Status: prreview.FileModified, Patch: "@@ -1 +1,2 @@\n package a\n+func A() {}\n"},
+		{Path: "new.go", PreviousPath: "old.go", Status: prreview.FileRenamed},
+		{Path: "c.go", Status: prreview.FileAdded},
+		{Path: "d.go", Status: prreview.FileRemoved},
+	}
+	if changes.BaseSHA != "base" || fmt.Sprint(changes.Files) != fmt.Sprint(want) {
+		t.Errorf("Changes() = %+v, want %+v", changes, want)
+	}
+
+	content, err := p.FileContent(context.Background(), pr, "pkg/a.go", "head")
+	if err != nil || content != "package a // head" {
+		t.Errorf("FileContent() = %q, %v", content, err)
+	}
+}
+
+func TestProvider_Publish(t *testing.T) {
+	p, fake := newTestProvider(t)
+	pr := &prreview.PullRequest{Repository: "group/app", Number: 3, HeadSHA: "head", ProviderID: 5}
+
+	report := &prreview.Report{
+		Conclusion: prreview.ConclusionFailure,
+		Title:      "2 issue(s) on changed lines in 1 Go file(s)",
+		Summary:    "Reviewed 1 changed Go file(s)",
+		Annotations: []prreview.Annotation{
+			{Path: "a.go", StartLine: 2, EndLine: 2, Severity: "high", Rule: "unchecked-error", Message: "Error not checked", Suggestion: "Check it"},
+			{Path: "a.go", StartLine: 500, EndLine: 500, Severity: "low", Rule: "naming", Message: "Rename it"},
+		},
+	}
+	if err := p.Publish(context.Background(), pr, report); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if len(fake.discussions) != 1 {
+		t.Fatalf("expected 1 discussion, got %+v", fake.discussions)
+	}
+	d := fake.discussions[0]
+	position := d["position"].(map[string]any)
+	if d["body"] != "**unchecked-error** (high): Error not checked\n\nCheck it" ||
+		position["base_sha"] != "base" || position["start_sha"] != "start" || position["head_sha"] != "head" ||
+		position["new_path"] != "a.go" || position["new_line"] != float64(2) {
+		t.Errorf("unexpected discussion %+v", d)
+	}
+	if len(fake.notes) != 1 || !strings.Contains(fake.notes[0], "`a.go:500` **naming** (low): Rename it") {
+		t.Errorf("expected the unplaced issue in the note, got %q", fake.notes)
+	}
+	if len(fake.statuses) != 1 || fake.statuses[0]["state"] != "failed" || fake.statuses[0]["name"] != DefaultStatusName {
+		t.Errorf("unexpected commit statuses %+v", fake.statuses)
+	}
+}
@@ -0,0 +1,45 @@
+package prreview
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AddedLines returns the lines of the new file that a unified diff patch
+// of one file adds or changes. Lines before the first hunk header, such as
+// the "---" and "+++" file headers, are skipped.
+func AddedLines(patch string) map[int]bool {
+	added := map[int]bool{}
+	line := 0
+	for _, text := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(text, "@@"):
+			line = hunkStart(text)
+		case line == 0:
+			// Outside a hunk
+		case strings.HasPrefix(text, "+"):
+			added[line] = true
+			line++
+		case strings.HasPrefix(text, " "), text == "":
+			line++
+		}
+		// Removed lines and "\ No newline at end of file" leave the new
+		// file untouched
+	}
+	return added
+}
+
+// hunkStart returns the first line in the new file of a hunk header such as
+// "@@ -10,6 +12,8 @@ func main() {", or 0 when the header is malformed
+func hunkStart(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0
+	}
+	start, _, _ := strings.Cut(fields[2][1:], ",")
+	n, err := strconv.Atoi(start)
+	if err != nil {
+		return 0
+	}
+	return n
+}
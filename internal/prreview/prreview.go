@@ -0,0 +1,366 @@
+// Package prreview reviews pull requests of code hosts such as GitHub,
+// GitLab and Bitbucket: it receives their webhooks, reviews the Go files
+// each pull request changes against their base version and reports the
+// issues on changed lines through a Provider of the host
+package prreview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"mcp-go-assistant/internal/codereview"
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/ratelimit"
+)
+
+// Statuses of changed files
+const (
+	FileAdded    = "added"
+	FileModified = "modified"
+	FileRenamed  = "renamed"
+	FileRemoved  = "removed"
+)
+
+// Conclusions of a review
+const (
+	ConclusionSuccess = "success" // No issues on changed lines
+	ConclusionNeutral = "neutral" // Issues below the fail_on severity, or files that could not be reviewed
+	ConclusionFailure = "failure" // Issues at or above the fail_on severity
+)
+
+// maxPayloadSize bounds the webhook payloads read, the largest GitHub
+// delivers
+const maxPayloadSize = 25 << 20
+
+// maxFiles bounds the Go files reviewed per pull request
+const maxFiles = 300
+
+// ErrInvalidSignature is returned by providers for webhook deliveries that
+// do not carry the configured secret
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// PullRequest is a pull or merge request to review
+type PullRequest struct {
+	Repository string // Full name of the repository, e.g. "owner/name"
+	Number     int    // Number of the pull request, or IID of a GitLab merge request
+	HeadSHA    string
+	BaseSHA    string // Base commit named by the webhook, if any; Changes returns the one to compare to
+	Action     string // Webhook action that asked for the review, for logs
+	ProviderID int64  // Provider-specific ID, such as the installation of a GitHub App
+}
+
+// File is a file changed by a pull request
+type File struct {
+	Path         string
+	PreviousPath string // Path in the base commit of renamed files
+	Status       string // FileAdded, FileModified, FileRenamed or FileRemoved
+	Patch        string // Unified diff of the file; empty when the host omits large diffs
+}
+
+// Changes are the files changed by a pull request
+type Changes struct {
+	BaseSHA string // Base commit the files are compared to
+	Files   []File
+}
+
+// Annotation is an issue on changed lines of a file
+type Annotation struct {
+	Path       string
+	StartLine  int
+	EndLine    int
+	Severity   string // Issue severity: "critical", "high", "medium" or "low"
+	Rule       string
+	Message    string
+	Suggestion string
+}
+
+// Report is the outcome of a pull request review
+type Report struct {
+	Conclusion  string
+	Title       string
+	Summary     string // Markdown
+	Annotations []Annotation
+}
+
+// Provider connects the reviewer to a code host
+type Provider interface {
+	// Name identifies the provider in logs and rate-limit identities
+	Name() string
+	// ParseWebhook verifies a delivery and returns the pull request it asks
+	// to review, or nil for deliveries that need no review. Deliveries
+	// without the configured secret return ErrInvalidSignature.
+	ParseWebhook(r *http.Request, body []byte) (*PullRequest, error)
+	// Changes lists the files changed by pr
+	Changes(ctx context.Context, pr *PullRequest) (*Changes, error)
+	// FileContent returns the content of path at commit ref
+	FileContent(ctx context.Context, pr *PullRequest, path, ref string) (string, error)
+	// Publish reports a review on pr
+	Publish(ctx context.Context, pr *PullRequest, report *Report) error
+}
+
+// ReviewFunc reviews the Go code of one file
+type ReviewFunc func(ctx context.Context, params codereview.CodeReviewParams) (*codereview.ReviewResult, error)
+
+// Options configures the reviewer
+type Options struct {
+	FailOn    string // Lowest issue severity that fails the review; empty never fails it
+	Workers   int    // Pull requests reviewed at once; at least 1
+	QueueSize int    // Pull requests waiting for a worker before deliveries are refused
+	Review    ReviewFunc
+	Logger    *logging.Logger
+}
+
+// Reviewer reviews the pull requests named by webhook deliveries in the
+// background
+type Reviewer struct {
+	opts Options
+	jobs chan job
+}
+
+// job is a queued pull request of a provider
+type job struct {
+	provider Provider
+	pr       *PullRequest
+}
+
+// New returns a reviewer for opts; Run must be called for queued pull
+// requests to be reviewed
+func New(opts Options) (*Reviewer, error) {
+	if opts.Review == nil {
+		return nil, fmt.Errorf("review function is required")
+	}
+	if opts.FailOn != "" && !codereview.IsValidSeverity(opts.FailOn) {
+		return nil, fmt.Errorf("invalid fail_on severity: %s", opts.FailOn)
+	}
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+	return &Reviewer{opts: opts, jobs: make(chan job, opts.QueueSize)}, nil
+}
+
+// Run reviews queued pull requests with the configured number of workers
+// until ctx is done, then waits for the reviews in progress
+func (rv *Reviewer) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range rv.opts.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case j := <-rv.jobs:
+					rv.reviewPullRequest(ctx, j.provider, j.pr)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Handler returns the webhook handler of provider. Pull requests are
+// queued for review and acknowledged at once, as code hosts give up on
+// slow deliveries; deliveries that need no review are acknowledged with
+// no content.
+func (rv *Reviewer) Handler(provider Provider) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+		if err != nil {
+			http.Error(w, "failed to read payload", http.StatusBadRequest)
+			return
+		}
+
+		pr, err := provider.ParseWebhook(r, body)
+		switch {
+		case errors.Is(err, ErrInvalidSignature):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case pr == nil:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		select {
+		case rv.jobs <- job{provider: provider, pr: pr}:
+			rv.opts.Logger.InfoEvent().
+				Str("provider", provider.Name()).
+				Str("repository", pr.Repository).
+				Int("pull_request", pr.Number).
+				Str("action", pr.Action).
+				Msg("pull request queued for review")
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			rv.opts.Logger.WarnEvent().
+				Str("provider", provider.Name()).
+				Str("repository", pr.Repository).
+				Int("pull_request", pr.Number).
+				Msg("review queue full, delivery refused")
+			http.Error(w, "review queue full", http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// reviewPullRequest reviews a pull request and publishes the report
+func (rv *Reviewer) reviewPullRequest(ctx context.Context, provider Provider, pr *PullRequest) {
+	log := rv.opts.Logger.WithFields(map[string]interface{}{
+		"provider":     provider.Name(),
+		"repository":   pr.Repository,
+		"pull_request": pr.Number,
+		"head_sha":     pr.HeadSHA,
+	})
+
+	// Each repository gets its own rate-limit identity
+	ctx = ratelimit.WithClientID(ctx, provider.Name()+":"+pr.Repository)
+	report, err := rv.review(ctx, provider, pr)
+	if err != nil {
+		log.ErrorEvent().Err(err).Msg("pull request review failed")
+		report = &Report{
+			Conclusion: ConclusionNeutral,
+			Title:      "Review failed",
+			Summary:    fmt.Sprintf("The pull request could not be reviewed: %v", err),
+		}
+	}
+	if err := provider.Publish(ctx, pr, report); err != nil {
+		log.ErrorEvent().Err(err).Msg("failed to publish pull request review")
+		return
+	}
+	log.InfoEvent().
+		Int("annotations", len(report.Annotations)).
+		Str("conclusion", report.Conclusion).
+		Msg("pull request reviewed")
+}
+
+// review reviews the Go files changed by a pull request and keeps the
+// issues on lines the pull request adds or changes
+func (rv *Reviewer) review(ctx context.Context, provider Provider, pr *PullRequest) (*Report, error) {
+	changes, err := provider.Changes(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviewed, skipped int
+	var failures, breaking []string
+	var annotations []Annotation
+	failed := false
+	for _, f := range changes.Files {
+		if !reviewable(f) {
+			continue
+		}
+		if reviewed == maxFiles {
+			skipped++
+			continue
+		}
+		reviewed++
+
+		result, err := rv.reviewFile(ctx, provider, pr, changes.BaseSHA, f)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("- `%s`: %v", f.Path, err))
+			continue
+		}
+
+		// Without a patch the host found the diff too large to show, so
+		// every issue of the file is reported
+		changed := AddedLines(f.Patch)
+		onChange := func(line int) bool { return line > 0 && (f.Patch == "" || changed[line]) }
+		for _, issue := range result.Issues {
+			if !onChange(issue.Line) {
+				continue
+			}
+			annotations = append(annotations, Annotation{
+				Path:       f.Path,
+				StartLine:  issue.Line,
+				EndLine:    max(issue.EndLine, issue.Line),
+				Severity:   issue.Severity,
+				Rule:       issue.Rule,
+				Message:    issue.Message,
+				Suggestion: issue.Suggestion,
+			})
+			if rv.opts.FailOn != "" && codereview.SeverityAtLeast(issue.Severity, rv.opts.FailOn) {
+				failed = true
+			}
+		}
+		for _, change := range result.APIChanges {
+			if !change.Breaking {
+				continue
+			}
+			breaking = append(breaking, fmt.Sprintf("- `%s`: %s %s %s", f.Path, change.Kind, change.Symbol, change.Change))
+			if onChange(change.Line) {
+				annotations = append(annotations, Annotation{
+					Path:      f.Path,
+					StartLine: change.Line,
+					EndLine:   change.Line,
+					Severity:  "medium",
+					Rule:      "breaking-api-change",
+					Message:   fmt.Sprintf("Breaking change of exported %s %s: %s -> %s", change.Kind, change.Symbol, change.Old, change.New),
+				})
+			}
+		}
+	}
+
+	report := &Report{Annotations: annotations, Conclusion: ConclusionSuccess}
+	switch {
+	case failed:
+		report.Conclusion = ConclusionFailure
+	case len(annotations) > 0 || len(failures) > 0:
+		report.Conclusion = ConclusionNeutral
+	}
+	report.Title = fmt.Sprintf("%d issue(s) on changed lines in %d Go file(s)", len(annotations), reviewed)
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Reviewed %d changed Go file(s) and found %d issue(s) on changed lines.", reviewed, len(annotations))
+	if skipped > 0 {
+		fmt.Fprintf(&summary, " %d more file(s) were not reviewed; at most %d are reviewed per pull request.", skipped, maxFiles)
+	}
+	if len(breaking) > 0 {
+		fmt.Fprintf(&summary, "\n\n### Breaking API changes\n\n%s", strings.Join(breaking, "\n"))
+	}
+	if len(failures) > 0 {
+		fmt.Fprintf(&summary, "\n\n### Files that could not be reviewed\n\n%s", strings.Join(failures, "\n"))
+	}
+	report.Summary = summary.String()
+	return report, nil
+}
+
+// reviewable reports whether f is a Go file left in the pull request
+// outside vendored code
+func reviewable(f File) bool {
+	return path.Ext(f.Path) == ".go" &&
+		f.Status != FileRemoved &&
+		!strings.HasPrefix(f.Path, "vendor/") && !strings.Contains(f.Path, "/vendor/")
+}
+
+// reviewFile reviews the head version of f, with its base version for the
+// exported API changes
+func (rv *Reviewer) reviewFile(ctx context.Context, provider Provider, pr *PullRequest, baseSHA string, f File) (*codereview.ReviewResult, error) {
+	code, err := provider.FileContent(ctx, pr, f.Path, pr.HeadSHA)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous string
+	if (f.Status == FileModified || f.Status == FileRenamed) && baseSHA != "" {
+		base := f.Path
+		if f.PreviousPath != "" {
+			base = f.PreviousPath
+		}
+		if previous, err = provider.FileContent(ctx, pr, base, baseSHA); err != nil {
+			return nil, err
+		}
+	}
+
+	return rv.opts.Review(ctx, codereview.CodeReviewParams{GoCode: code, PreviousCode: previous})
+}
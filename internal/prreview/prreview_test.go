@@ -0,0 +1,203 @@
+package prreview
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mcp-go-assistant/internal/codereview"
+	"mcp-go-assistant/internal/logging"
+)
+
+// fakeProvider serves a pull request changing a.go, which adds lines 3
+// and 4, and removing gone.go; deliveries carry their signature in the
+// X-Secret header
+type fakeProvider struct {
+	mu        sync.Mutex
+	published *Report
+	done      chan struct{}
+}
+
+func (f *fakeProvider) Name() string { return "fake" }
+
+func (f *fakeProvider) ParseWebhook(r *http.Request, body []byte) (*PullRequest, error) {
+	if r.Header.Get("X-Secret") != "secret" {
+		return nil, ErrInvalidSignature
+	}
+	switch string(body) {
+	case "review":
+		return &PullRequest{Repository: "octo/app", Number: 12, HeadSHA: "head", BaseSHA: "base", Action: "opened"}, nil
+	case "invalid":
+		return nil, fmt.Errorf("invalid payload")
+	}
+	return nil, nil
+}
+
+func (f *fakeProvider) Changes(context.Context, *PullRequest) (*Changes, error) {
+	return &Changes{BaseSHA: "merge-base", Files: []File{
+		{Path: "a.go", Status: FileModified, Patch: "@@ -1,2 +1,4 @@\n package a\n \n+func A() {}\n+func B() {}"},
+		{Path: "gone.go", Status: FileRemoved},
+		{Path: "vendor/x/x.go", Status: FileAdded},
+		{Path: "README.md", Status: FileModified},
+	}}, nil
+}
+
+func (f *fakeProvider) FileContent(_ context.Context, _ *PullRequest, path, ref string) (string, error) {
+	return fmt.Sprintf("package a // %s at %s", path, ref), nil
+}
+
+func (f *fakeProvider) Publish(_ context.Context, _ *PullRequest, report *Report) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.published = report
+	close(f.done)
+	return nil
+}
+
+// newTestReviewer returns a running reviewer failing on high issues
+func newTestReviewer(t *testing.T, review ReviewFunc) *Reviewer {
+	t.Helper()
+	log, err := logging.New("fatal", "json", "stderr", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rv, err := New(Options{FailOn: "high", QueueSize: 1, Review: review, Logger: log})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return rv
+}
+
+// deliver sends a webhook delivery to handler and returns the status
+func deliver(handler http.Handler, body, secret string) int {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("X-Secret", secret)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+// publishedReport runs rv until provider publishes a report for a
+// delivered pull request and returns it
+func publishedReport(t *testing.T, rv *Reviewer, provider *fakeProvider) *Report {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go rv.Run(ctx)
+
+	if got := deliver(rv.Handler(provider), "review", "secret"); got != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", got, http.StatusAccepted)
+	}
+	select {
+	case <-provider.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no report was published")
+	}
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	return provider.published
+}
+
+func TestAddedLines(t *testing.T) {
+	patch := "--- a/a.go\n+++ b/a.go\n@@ -1,4 +1,5 @@\n package a\n-func old() {}\n+func New() {}\n+func Other() {}\n \n@@ -20,2 +21,2 @@ func x() {\n \treturn\n-}\n+} // end\n\\ No newline at end of file"
+	got := AddedLines(patch)
+	want := map[int]bool{2: true, 3: true, 22: true}
+	if len(got) != len(want) {
+		t.Fatalf("AddedLines() = %v, want %v", got, want)
+	}
+	for line := range want {
+		if !got[line] {
+			t.Errorf("AddedLines() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestReviewer_Handler(t *testing.T) {
+	rv := newTestReviewer(t, func(context.Context, codereview.CodeReviewParams) (*codereview.ReviewResult, error) {
+		return &codereview.ReviewResult{}, nil
+	})
+	handler := rv.Handler(&fakeProvider{done: make(chan struct{})})
+
+	tests := []struct {
+		name   string
+		body   string
+		secret string
+		want   int
+	}{
+		{name: "bad signature", body: "review", secret: "other", want: http.StatusUnauthorized},
+		{name: "invalid payload", body: "invalid", secret: "secret", want: http.StatusBadRequest},
+		{name: "ignored", body: "ping", secret: "secret", want: http.StatusNoContent},
+		{name: "queued", body: "review", secret: "secret", want: http.StatusAccepted},
+		{name: "queue full", body: "review", secret: "secret", want: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deliver(handler, tt.body, tt.secret); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewer_ReviewsChangedLines(t *testing.T) {
+	var mu sync.Mutex
+	var reviewed []codereview.CodeReviewParams
+	rv := newTestReviewer(t, func(_ context.Context, params codereview.CodeReviewParams) (*codereview.ReviewResult, error) {
+		mu.Lock()
+		reviewed = append(reviewed, params)
+		mu.Unlock()
+		return &codereview.ReviewResult{
+			Issues: []codereview.Issue{
+				{Line: 3, Severity: "medium", Rule: "naming", Message: "Rename it"},
+				{Line: 1, Severity: "critical", Rule: "unchanged", Message: "Not on a changed line"},
+				{Line: 4, EndLine: 6, Severity: "high", Rule: "unchecked-error", Message: "Error not checked", Suggestion: "Check it"},
+			},
+			APIChanges: []codereview.APIChange{
+				{Symbol: "Old", Kind: "function", Change: "removed", Breaking: true},
+			},
+		}, nil
+	})
+
+	report := publishedReport(t, rv, &fakeProvider{done: make(chan struct{})})
+	if len(reviewed) != 1 || reviewed[0].GoCode != "package a // a.go at head" || reviewed[0].PreviousCode != "package a // a.go at merge-base" {
+		t.Errorf("unexpected reviews %+v", reviewed)
+	}
+	if len(report.Annotations) != 2 {
+		t.Fatalf("expected the 2 issues on changed lines, got %+v", report.Annotations)
+	}
+	if a := report.Annotations[1]; a.Path != "a.go" || a.StartLine != 4 || a.EndLine != 6 || a.Suggestion != "Check it" {
+		t.Errorf("unexpected annotation %+v", a)
+	}
+	if report.Conclusion != ConclusionFailure {
+		t.Errorf("Conclusion = %q, want %q", report.Conclusion, ConclusionFailure)
+	}
+	if !strings.Contains(report.Summary, "`a.go`: function Old removed") {
+		t.Errorf("expected the breaking change in the summary, got %q", report.Summary)
+	}
+}
+
+func TestReviewer_ReviewError(t *testing.T) {
+	rv := newTestReviewer(t, func(context.Context, codereview.CodeReviewParams) (*codereview.ReviewResult, error) {
+		return nil, fmt.Errorf("failed to parse Go code")
+	})
+
+	report := publishedReport(t, rv, &fakeProvider{done: make(chan struct{})})
+	if report.Conclusion != ConclusionNeutral || !strings.Contains(report.Summary, "`a.go`: failed to parse Go code") {
+		t.Errorf("unexpected report %q: %s", report.Conclusion, report.Summary)
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	review := func(context.Context, codereview.CodeReviewParams) (*codereview.ReviewResult, error) { return nil, nil }
+	if _, err := New(Options{}); err == nil {
+		t.Error("expected an error without a review function")
+	}
+	if _, err := New(Options{Review: review, FailOn: "severe"}); err == nil {
+		t.Error("expected an error for an invalid fail_on")
+	}
+}
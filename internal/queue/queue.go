@@ -0,0 +1,226 @@
+package queue
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"mcp-go-assistant/internal/types"
+)
+
+// Rejection reasons reported by BusyError
+const (
+	ReasonQueueFull    = "queue_full"
+	ReasonQueueTimeout = "queue_timeout"
+)
+
+// minRetryAfter is the smallest retry_after suggested to clients
+const minRetryAfter = time.Second
+
+// Config bounds the concurrent calls of a tool and the calls waiting for a slot
+type Config struct {
+	MaxConcurrent int           // Calls executing at once
+	QueueSize     int           // Calls waiting for a slot; 0 rejects immediately when all slots are busy
+	MaxWait       time.Duration // Longest a call waits in the queue
+}
+
+// Validate checks the configuration
+func (c *Config) Validate() error {
+	if c.MaxConcurrent <= 0 {
+		return fmt.Errorf("max_concurrent must be positive")
+	}
+	if c.QueueSize < 0 {
+		return fmt.Errorf("queue_size must not be negative")
+	}
+	if c.QueueSize > 0 && c.MaxWait <= 0 {
+		return fmt.Errorf("max_wait must be positive when queueing is enabled")
+	}
+	return nil
+}
+
+// BusyError is returned when a call cannot get a slot
+type BusyError struct {
+	Tool       string        `json:"tool"`
+	Reason     string        `json:"reason"`
+	QueueDepth int           `json:"queue_depth"`
+	RetryAfter time.Duration `json:"retry_after"`
+}
+
+// Error implements the error interface
+func (e *BusyError) Error() string {
+	switch e.Reason {
+	case ReasonQueueTimeout:
+		return fmt.Sprintf("%s is busy: no slot became available in time, retry after %v", e.Tool, e.RetryAfter)
+	default:
+		return fmt.Sprintf("%s is busy: request queue is full (%d waiting), retry after %v", e.Tool, e.QueueDepth, e.RetryAfter)
+	}
+}
+
+// ToMCPError converts a BusyError to an MCPError
+func (e *BusyError) ToMCPError() types.MCPError {
+	return types.NewServerBusyError(e.Error(),
+		"tool", e.Tool,
+		"reason", e.Reason,
+		"queue_depth", e.QueueDepth,
+		"retry_after", e.RetryAfter.String(),
+		"retry_after_ms", e.RetryAfter.Milliseconds(),
+	)
+}
+
+// Limiter bounds concurrent calls and queues the excess in FIFO order
+type Limiter struct {
+	name   string
+	config Config
+
+	// OnDepthChange, if set, is called with the queue depth whenever it changes
+	OnDepthChange func(depth int)
+
+	mu      sync.Mutex
+	active  int
+	waiters *list.List // of chan struct{}, closed when a slot is handed over
+	avgHold time.Duration
+}
+
+// NewLimiter creates a limiter for the named tool
+func NewLimiter(name string, cfg Config) (*Limiter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid queue config for %s: %w", name, err)
+	}
+	return &Limiter{
+		name:    name,
+		config:  cfg,
+		waiters: list.New(),
+	}, nil
+}
+
+// Acquire waits for a slot and returns a function that releases it. The
+// wait ends early with a BusyError when the queue is full or MaxWait passes,
+// and with the context error when ctx is done.
+func (l *Limiter) Acquire(ctx context.Context) (func(), error) {
+	l.mu.Lock()
+	if l.active < l.config.MaxConcurrent && l.waiters.Len() == 0 {
+		l.active++
+		l.mu.Unlock()
+		return l.releaser(), nil
+	}
+
+	if l.waiters.Len() >= l.config.QueueSize {
+		err := l.busy(ReasonQueueFull)
+		l.mu.Unlock()
+		return nil, err
+	}
+
+	ready := make(chan struct{})
+	elem := l.waiters.PushBack(ready)
+	l.depthChanged()
+	l.mu.Unlock()
+
+	timer := time.NewTimer(l.config.MaxWait)
+	defer timer.Stop()
+
+	select {
+	case <-ready:
+		return l.releaser(), nil
+	case <-timer.C:
+		return nil, l.abandon(elem, ready, l.busy)
+	case <-ctx.Done():
+		return nil, l.abandon(elem, ready, func(string) error { return ctx.Err() })
+	}
+}
+
+// abandon removes a waiter that gave up. If a slot was handed over in the
+// meantime it is passed on, and the call is still rejected.
+func (l *Limiter) abandon(elem *list.Element, ready chan struct{}, reject func(reason string) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	select {
+	case <-ready:
+		l.handOff()
+	default:
+		l.waiters.Remove(elem)
+		l.depthChanged()
+	}
+	return reject(ReasonQueueTimeout)
+}
+
+// releaser returns a function that frees the slot exactly once
+func (l *Limiter) releaser() func() {
+	start := time.Now()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.recordHold(time.Since(start))
+			l.handOff()
+		})
+	}
+}
+
+// handOff gives a freed slot to the oldest waiter, or returns it to the
+// pool; callers must hold l.mu
+func (l *Limiter) handOff() {
+	if front := l.waiters.Front(); front != nil {
+		l.waiters.Remove(front)
+		close(front.Value.(chan struct{}))
+		l.depthChanged()
+		return
+	}
+	l.active--
+}
+
+// recordHold updates the moving average of slot hold times; callers must
+// hold l.mu
+func (l *Limiter) recordHold(d time.Duration) {
+	if l.avgHold == 0 {
+		l.avgHold = d
+		return
+	}
+	l.avgHold = (l.avgHold*4 + d) / 5
+}
+
+// busy builds a BusyError with a retry_after estimated from the queue depth
+// and the average hold time; callers must hold l.mu
+func (l *Limiter) busy(reason string) error {
+	depth := l.waiters.Len()
+
+	retryAfter := l.config.MaxWait
+	if l.avgHold > 0 {
+		rounds := depth/l.config.MaxConcurrent + 1
+		retryAfter = l.avgHold * time.Duration(rounds)
+	}
+	if retryAfter < minRetryAfter {
+		retryAfter = minRetryAfter
+	}
+
+	return &BusyError{
+		Tool:       l.name,
+		Reason:     reason,
+		QueueDepth: depth,
+		RetryAfter: retryAfter.Round(100 * time.Millisecond),
+	}
+}
+
+// depthChanged reports the queue depth; callers must hold l.mu
+func (l *Limiter) depthChanged() {
+	if l.OnDepthChange != nil {
+		l.OnDepthChange(l.waiters.Len())
+	}
+}
+
+// Depth returns the number of calls waiting for a slot
+func (l *Limiter) Depth() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.waiters.Len()
+}
+
+// Active returns the number of calls holding a slot
+func (l *Limiter) Active() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.active
+}
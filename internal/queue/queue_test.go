@@ -0,0 +1,176 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"mcp-go-assistant/internal/types"
+)
+
+func newTestLimiter(t *testing.T, cfg Config) *Limiter {
+	t.Helper()
+	l, err := NewLimiter("demo", cfg)
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v", err)
+	}
+	return l
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "valid", cfg: Config{MaxConcurrent: 2, QueueSize: 4, MaxWait: time.Second}},
+		{name: "no queue", cfg: Config{MaxConcurrent: 2}},
+		{name: "zero concurrency", cfg: Config{MaxConcurrent: 0}, wantErr: true},
+		{name: "negative queue", cfg: Config{MaxConcurrent: 1, QueueSize: -1}, wantErr: true},
+		{name: "queue without wait", cfg: Config{MaxConcurrent: 1, QueueSize: 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLimiter_QueueFull(t *testing.T) {
+	l := newTestLimiter(t, Config{MaxConcurrent: 1})
+
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = l.Acquire(context.Background())
+	var busy *BusyError
+	if !errors.As(err, &busy) || busy.Reason != ReasonQueueFull {
+		t.Fatalf("expected queue full BusyError, got %v", err)
+	}
+	if busy.RetryAfter < minRetryAfter {
+		t.Errorf("expected retry_after of at least %v, got %v", minRetryAfter, busy.RetryAfter)
+	}
+
+	mcpErr := busy.ToMCPError()
+	if mcpErr.Code() != "SERVER_BUSY" || mcpErr.Details()["retry_after"] == nil {
+		t.Errorf("unexpected MCP error %v with details %v", mcpErr, mcpErr.Details())
+	}
+	if !types.IsMCPError(mcpErr) {
+		t.Error("expected MCPError")
+	}
+
+	release()
+	release() // Releasing twice must not free a second slot
+	if l.Active() != 0 {
+		t.Errorf("expected no active slots, got %d", l.Active())
+	}
+}
+
+func TestLimiter_FIFO(t *testing.T) {
+	l := newTestLimiter(t, Config{MaxConcurrent: 1, QueueSize: 3, MaxWait: time.Second})
+
+	release, _ := l.Acquire(context.Background())
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r, err := l.Acquire(context.Background())
+			if err != nil {
+				t.Errorf("waiter %d: unexpected error: %v", i, err)
+				return
+			}
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			r()
+		}(i)
+
+		// Wait until the waiter is queued so arrival order is deterministic
+		for l.Depth() != i+1 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	release()
+	wg.Wait()
+
+	if len(order) != 3 || order[0] != 0 || order[1] != 1 || order[2] != 2 {
+		t.Errorf("expected FIFO order [0 1 2], got %v", order)
+	}
+	if l.Active() != 0 || l.Depth() != 0 {
+		t.Errorf("expected idle limiter, got %d active and %d queued", l.Active(), l.Depth())
+	}
+}
+
+func TestLimiter_QueueTimeout(t *testing.T) {
+	l := newTestLimiter(t, Config{MaxConcurrent: 1, QueueSize: 1, MaxWait: 10 * time.Millisecond})
+
+	var depths []int
+	l.OnDepthChange = func(depth int) { depths = append(depths, depth) }
+
+	release, _ := l.Acquire(context.Background())
+	defer release()
+
+	_, err := l.Acquire(context.Background())
+	var busy *BusyError
+	if !errors.As(err, &busy) || busy.Reason != ReasonQueueTimeout {
+		t.Fatalf("expected queue timeout BusyError, got %v", err)
+	}
+	if l.Depth() != 0 {
+		t.Errorf("expected timed out waiter to leave the queue, depth %d", l.Depth())
+	}
+	if len(depths) != 2 || depths[0] != 1 || depths[1] != 0 {
+		t.Errorf("expected depth changes [1 0], got %v", depths)
+	}
+}
+
+func TestLimiter_ContextCanceled(t *testing.T) {
+	l := newTestLimiter(t, Config{MaxConcurrent: 1, QueueSize: 1, MaxWait: time.Minute})
+
+	release, _ := l.Acquire(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := l.Acquire(ctx)
+		errCh <- err
+	}()
+	for l.Depth() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+
+	release()
+	if l.Active() != 0 {
+		t.Errorf("expected slot to be returned, got %d active", l.Active())
+	}
+}
+
+func TestLimiter_RetryAfterEstimate(t *testing.T) {
+	l := newTestLimiter(t, Config{MaxConcurrent: 1})
+	l.avgHold = 3 * time.Second
+
+	release, _ := l.Acquire(context.Background())
+	defer release()
+
+	_, err := l.Acquire(context.Background())
+	var busy *BusyError
+	if !errors.As(err, &busy) || busy.RetryAfter != 3*time.Second {
+		t.Errorf("expected retry_after from average hold time, got %v", err)
+	}
+}
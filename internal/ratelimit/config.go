@@ -22,6 +22,15 @@ type Config struct {
 	StoreType StoreType `mapstructure:"store_type"`
 	// KeyPrefix is the prefix for all rate limit keys
 	KeyPrefix string `mapstructure:"key_prefix"`
+	// MaxKeys bounds the keys of the memory store, evicting the least
+	// recently used; zero means unbounded
+	MaxKeys int `mapstructure:"max_keys"`
+	// Behavior determines whether calls over the limit are rejected or wait
+	// (reject, wait); empty means reject
+	Behavior Behavior `mapstructure:"behavior"`
+	// MaxWait is the longest a call waits for the limit in wait behavior,
+	// further bounded by the call's deadline
+	MaxWait time.Duration `mapstructure:"max_wait"`
 }
 
 // ToolConfig holds tool-specific rate limiting configuration
@@ -65,6 +74,21 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid store type: %s (valid: memory, noop)", c.StoreType)
 	}
 
+	if c.MaxKeys < 0 {
+		return fmt.Errorf("rate limit max keys cannot be negative: %d", c.MaxKeys)
+	}
+
+	switch c.Behavior {
+	case "", BehaviorReject:
+		// Valid behaviors
+	case BehaviorWait:
+		if c.MaxWait <= 0 {
+			return fmt.Errorf("rate limit max wait must be positive in wait behavior: %v", c.MaxWait)
+		}
+	default:
+		return fmt.Errorf("invalid rate limit behavior: %s (valid: reject, wait)", c.Behavior)
+	}
+
 	return nil
 }
 
@@ -78,6 +102,9 @@ func DefaultConfig() *Config {
 		Algorithm: AlgorithmTokenBucket,
 		StoreType: StoreMemory,
 		KeyPrefix: "mcp",
+		MaxKeys:   10000,
+		Behavior:  BehaviorReject,
+		MaxWait:   10 * time.Second,
 	}
 }
 
@@ -138,6 +165,30 @@ func LoadFromEnv() *Config {
 		cfg.KeyPrefix = val
 	}
 
+	// MCP_RATELIMIT_MAX_KEYS
+	if val := os.Getenv("MCP_RATELIMIT_MAX_KEYS"); val != "" {
+		var maxKeys int
+		if _, err := fmt.Sscanf(val, "%d", &maxKeys); err == nil && maxKeys >= 0 {
+			cfg.MaxKeys = maxKeys
+		}
+	}
+
+	// MCP_RATELIMIT_BEHAVIOR
+	if val := os.Getenv("MCP_RATELIMIT_BEHAVIOR"); val != "" {
+		behavior := Behavior(val)
+		switch behavior {
+		case BehaviorReject, BehaviorWait:
+			cfg.Behavior = behavior
+		}
+	}
+
+	// MCP_RATELIMIT_MAX_WAIT
+	if val := os.Getenv("MCP_RATELIMIT_MAX_WAIT"); val != "" {
+		if maxWait, err := time.ParseDuration(val); err == nil && maxWait > 0 {
+			cfg.MaxWait = maxWait
+		}
+	}
+
 	return cfg
 }
 
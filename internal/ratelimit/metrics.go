@@ -16,6 +16,12 @@ type Metrics struct {
 	currentCount *prometheus.GaugeVec
 	// limitExceeded tracks rate limit exceeded events
 	limitExceeded *prometheus.CounterVec
+	// storeKeys tracks the number of keys in the memory store
+	storeKeys prometheus.Gauge
+	// evictionsTotal counts keys removed from the memory store by reason
+	evictionsTotal *prometheus.CounterVec
+	// cleanupsTotal counts periodic cleanups of the memory store
+	cleanupsTotal prometheus.Counter
 
 	mu sync.Mutex
 }
@@ -57,12 +63,37 @@ func NewMetrics() *Metrics {
 		[]string{"tool", "mode"},
 	)
 
+	m.storeKeys = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "mcp_ratelimit_store_keys",
+			Help: "Current number of keys in the rate limit memory store",
+		},
+	)
+
+	m.evictionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mcp_ratelimit_store_evictions_total",
+			Help: "Total number of keys removed from the rate limit memory store",
+		},
+		[]string{"reason"},
+	)
+
+	m.cleanupsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "mcp_ratelimit_store_cleanups_total",
+			Help: "Total number of periodic cleanups of the rate limit memory store",
+		},
+	)
+
 	// Register metrics with default registry
 	prometheus.MustRegister(
 		m.allowedTotal,
 		m.rejectedTotal,
 		m.currentCount,
 		m.limitExceeded,
+		m.storeKeys,
+		m.evictionsTotal,
+		m.cleanupsTotal,
 	)
 
 	return m
@@ -93,11 +124,44 @@ func (m *Metrics) SetCurrent(toolName, mode string, count int) {
 	m.currentCount.WithLabelValues(toolName, mode).Set(float64(count))
 }
 
+// SetStoreKeys sets the number of keys in the memory store
+func (m *Metrics) SetStoreKeys(keys int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.storeKeys.Set(float64(keys))
+}
+
+// RecordEvictions records keys removed from the memory store
+func (m *Metrics) RecordEvictions(reason string, count int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evictionsTotal.WithLabelValues(reason).Add(float64(count))
+}
+
+// RecordCleanup records a periodic cleanup that removed expired keys
+func (m *Metrics) RecordCleanup(removed int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cleanupsTotal.Inc()
+	m.evictionsTotal.WithLabelValues(EvictionExpired).Add(float64(removed))
+}
+
 // Stop cleans up metrics resources
 func (m *Metrics) Stop() {
 	// Metrics are automatically unregistered when the application stops
 }
 
+// Reasons for removing keys from the memory store
+const (
+	// EvictionLRU is the removal of the least recently used key of a full store
+	EvictionLRU = "lru"
+	// EvictionExpired is the removal of an idle key by the periodic cleanup
+	EvictionExpired = "expired"
+)
+
 // Global rate limit metrics instance
 var globalMetrics *Metrics
 
@@ -133,3 +197,27 @@ func SetCurrent(toolName, mode string, count int) {
 		globalMetrics.SetCurrent(toolName, mode, count)
 	}
 }
+
+// SetStoreKeys sets the number of keys in the memory store using the global
+// metrics
+func SetStoreKeys(keys int) {
+	if globalMetrics != nil {
+		globalMetrics.SetStoreKeys(keys)
+	}
+}
+
+// RecordEvictions records keys removed from the memory store using the
+// global metrics
+func RecordEvictions(reason string, count int) {
+	if globalMetrics != nil {
+		globalMetrics.RecordEvictions(reason, count)
+	}
+}
+
+// RecordCleanup records a periodic cleanup of the memory store using the
+// global metrics
+func RecordCleanup(removed int) {
+	if globalMetrics != nil {
+		globalMetrics.RecordCleanup(removed)
+	}
+}
@@ -3,6 +3,7 @@ package ratelimit
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"mcp-go-assistant/internal/logging"
 )
@@ -23,6 +24,9 @@ func NewMiddleware(limiter *Limiter, log *logging.Logger) *Middleware {
 	}
 }
 
+// minWaitInterval is the shortest time between polls of a waiting call
+const minWaitInterval = 10 * time.Millisecond
+
 // Handler wraps a tool handler with rate limiting
 type Handler func(ctx context.Context, request interface{}) (interface{}, error)
 
@@ -92,18 +96,122 @@ func (m *Middleware) CheckRateLimit(toolName, clientID string) error {
 	return nil
 }
 
+// Acquire admits a call of a tool by a client. Over the limit, it fails with
+// a RateLimitError right away in reject behavior; in wait behavior it blocks
+// until the call fits in the limit, failing once the max wait or the
+// deadline of ctx would pass first.
+func (m *Middleware) Acquire(ctx context.Context, toolName, clientID string) error {
+	if m.limiter.config.Behavior != BehaviorWait {
+		return m.CheckRateLimit(toolName, clientID)
+	}
+	return m.wait(ctx, toolName, clientID)
+}
+
+// wait admits a call of a tool and client once it fits in the limit,
+// polling every window divided by the limit. Polls only peek at the limit;
+// the call is counted when it is admitted, and the rejection is recorded
+// only if the wait gives up.
+func (m *Middleware) wait(ctx context.Context, toolName, clientID string) error {
+	key := m.limiter.GenerateKey(toolName, clientID)
+	log := m.logger.WithContext(ctx)
+
+	allowed, err := m.limiter.allow(key, false)
+	if err != nil {
+		log.ErrorEvent().
+			Str("tool", toolName).
+			Str("key", key).
+			Err(err).
+			Msg("rate limit check failed, allowing request (fail open)")
+		return nil
+	}
+	if allowed {
+		return nil
+	}
+
+	start := time.Now()
+	deadline := start.Add(m.limiter.config.MaxWait)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	stats, _ := m.limiter.Stats(key)
+	interval := minWaitInterval
+	if stats.Limit > 0 && stats.Window/time.Duration(stats.Limit) > interval {
+		interval = stats.Window / time.Duration(stats.Limit)
+	}
+
+	log.DebugEvent().
+		Str("tool", toolName).
+		Dur("max_wait", time.Until(deadline)).
+		Msg("rate limit exceeded, waiting")
+
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			stats, _ := m.limiter.Stats(key)
+			m.limiter.recordRejected(key, stats.Current)
+			return &RateLimitError{
+				Key:        key,
+				Limit:      stats.Limit,
+				Window:     stats.Window,
+				RetryAfter: stats.Window,
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(min(interval, remaining)):
+		}
+
+		if free, err := m.limiter.Peek(key); err == nil && !free {
+			continue
+		}
+		// Another call may take the room first, in which case the wait
+		// goes on
+		if allowed, err := m.limiter.allow(key, false); err != nil || allowed {
+			log.DebugEvent().
+				Str("tool", toolName).
+				Dur("waited", time.Since(start)).
+				Msg("rate limit wait finished")
+			return nil
+		}
+	}
+}
+
+// SetToolClass assigns a tool to a class, whose limits apply to the tool
+// unless it has limits of its own
+func (m *Middleware) SetToolClass(toolName string, class ToolClass) {
+	m.limiter.SetToolClass(toolName, class)
+}
+
 // GetRateLimitInfo returns rate limit information for a tool and client
 func (m *Middleware) GetRateLimitInfo(toolName, clientID string) (Stats, error) {
 	key := m.limiter.GenerateKey(toolName, clientID)
 	return m.limiter.Stats(key)
 }
 
+// clientIDKey is the context key of the client identity
+type clientIDKey struct{}
+
+// WithClientID returns a context carrying the rate-limit identity of the
+// client making the calls, e.g. one of several daemon clients
+func WithClientID(ctx context.Context, clientID string) context.Context {
+	return context.WithValue(ctx, clientIDKey{}, clientID)
+}
+
+// ClientIDFromContext returns the client identity stored in ctx, or
+// "default" for the single client of a stdio server
+func ClientIDFromContext(ctx context.Context) string {
+	if clientID, ok := ctx.Value(clientIDKey{}).(string); ok && clientID != "" {
+		return clientID
+	}
+	return "default"
+}
+
 // extractClientID extracts a client identifier from the context
-// In a real MCP implementation, this would come from the request metadata
 func (m *Middleware) extractClientID(ctx context.Context) string {
-	// TODO: Extract client ID from MCP request context
-	// For now, return a default identifier
-	return "default"
+	return ClientIDFromContext(ctx)
 }
 
 // ExtractClientID is a helper function to extract client ID from MCP request
@@ -33,11 +33,15 @@ type Limiter struct {
 	store Store
 	// toolConfigs holds tool-specific configurations
 	toolConfigs map[string]*ToolConfig
+	// classConfigs holds the configurations of tool classes
+	classConfigs map[ToolClass]*ToolConfig
+	// toolClasses maps tool names to their classes
+	toolClasses map[string]ToolClass
 	// metrics is the metrics collector
 	metrics *metrics.Metrics
 	// logger is the logger
 	logger *logging.Logger
-	// mutex provides thread-safe access to tool configs
+	// mutex provides thread-safe access to tool and class configs
 	mutex sync.RWMutex
 }
 
@@ -48,38 +52,31 @@ func NewLimiter(cfg *Config, store Store, m *metrics.Metrics, log *logging.Logge
 	}
 
 	return &Limiter{
-		config:      cfg,
-		store:       store,
-		toolConfigs: make(map[string]*ToolConfig),
-		metrics:     m,
-		logger:      log,
+		config:       cfg,
+		store:        store,
+		toolConfigs:  make(map[string]*ToolConfig),
+		classConfigs: make(map[ToolClass]*ToolConfig),
+		toolClasses:  make(map[string]ToolClass),
+		metrics:      m,
+		logger:       log,
 	}
 }
 
 // Allow checks if a request is allowed for the given key
 func (l *Limiter) Allow(key string) (bool, error) {
+	return l.allow(key, true)
+}
+
+// allow counts a request for key and reports whether it is within the
+// limit. A rejection is recorded and logged only when record is set, so
+// that a waiting call can retry without reporting each attempt.
+func (l *Limiter) allow(key string, record bool) (bool, error) {
 	if !l.config.Enabled {
 		return true, nil
 	}
 
 	// Determine the limit to use
-	limit := l.config.Limit
-	window := l.config.Window
-
-	// Check if key is for a specific tool
-	if l.config.Mode == ModePerTool {
-		toolName := l.extractToolName(key)
-		if toolName != "" {
-			l.mutex.RLock()
-			toolCfg := l.toolConfigs[toolName]
-			l.mutex.RUnlock()
-
-			if toolCfg != nil && toolCfg.Enabled {
-				limit = toolCfg.Limit
-				window = toolCfg.Window
-			}
-		}
-	}
+	limit, window := l.limitFor(key)
 
 	// Increment counter
 	count, err := l.store.Increment(key, window)
@@ -110,16 +107,8 @@ func (l *Limiter) Allow(key string) (bool, error) {
 			Int("count", count).
 			Int("limit", limit).
 			Msg("rate limit check passed")
-	} else {
-		RecordRejected(toolName, mode)
-		l.logger.WarnEvent().
-			Str("key", key).
-			Str("tool", toolName).
-			Str("mode", mode).
-			Int("count", count).
-			Int("limit", limit).
-			Dur("window", window).
-			Msg("rate limit exceeded")
+	} else if record {
+		l.recordRejected(key, count)
 	}
 
 	// Update current count gauge
@@ -128,6 +117,42 @@ func (l *Limiter) Allow(key string) (bool, error) {
 	return allowed, nil
 }
 
+// recordRejected records and logs the rejection of a request for key,
+// made when the key had count requests in its window
+func (l *Limiter) recordRejected(key string, count int) {
+	limit, window := l.limitFor(key)
+	toolName := l.extractToolName(key)
+	if toolName == "" {
+		toolName = "unknown"
+	}
+	mode := string(l.config.Mode)
+
+	RecordRejected(toolName, mode)
+	l.logger.WarnEvent().
+		Str("key", key).
+		Str("tool", toolName).
+		Str("mode", mode).
+		Int("count", count).
+		Int("limit", limit).
+		Dur("window", window).
+		Msg("rate limit exceeded")
+}
+
+// Peek reports whether a request for key would be allowed now, without
+// counting it or recording anything
+func (l *Limiter) Peek(key string) (bool, error) {
+	if !l.config.Enabled {
+		return true, nil
+	}
+
+	limit, window := l.limitFor(key)
+	count, err := l.store.Peek(key, window)
+	if err != nil {
+		return false, fmt.Errorf("failed to get rate limit stats: %w", err)
+	}
+	return count < limit, nil
+}
+
 // Reset resets the counter for a key
 func (l *Limiter) Reset(key string) error {
 	if err := l.store.Reset(key); err != nil {
@@ -144,23 +169,7 @@ func (l *Limiter) Reset(key string) error {
 // Stats returns statistics for a key
 func (l *Limiter) Stats(key string) (Stats, error) {
 	// Determine the limit to use
-	limit := l.config.Limit
-	window := l.config.Window
-
-	// Check if key is for a specific tool
-	if l.config.Mode == ModePerTool {
-		toolName := l.extractToolName(key)
-		if toolName != "" {
-			l.mutex.RLock()
-			toolCfg := l.toolConfigs[toolName]
-			l.mutex.RUnlock()
-
-			if toolCfg != nil && toolCfg.Enabled {
-				limit = toolCfg.Limit
-				window = toolCfg.Window
-			}
-		}
-	}
+	limit, window := l.limitFor(key)
 
 	count, err := l.store.Get(key)
 	if err != nil {
@@ -177,6 +186,26 @@ func (l *Limiter) Stats(key string) (Stats, error) {
 	}, nil
 }
 
+// limitFor returns the limit and window of a key: those of its tool when
+// the tool has a configuration, else those of the tool's class, else the
+// defaults. Only per-tool keys name a tool.
+func (l *Limiter) limitFor(key string) (int, time.Duration) {
+	if l.config.Mode == ModePerTool {
+		if toolName := l.extractToolName(key); toolName != "" {
+			l.mutex.RLock()
+			defer l.mutex.RUnlock()
+
+			if toolCfg := l.toolConfigs[toolName]; toolCfg != nil && toolCfg.Enabled {
+				return toolCfg.Limit, toolCfg.Window
+			}
+			if classCfg := l.classConfigs[l.toolClasses[toolName]]; classCfg != nil && classCfg.Enabled {
+				return classCfg.Limit, classCfg.Window
+			}
+		}
+	}
+	return l.config.Limit, l.config.Window
+}
+
 // SetToolConfig sets the configuration for a specific tool
 func (l *Limiter) SetToolConfig(toolName string, cfg *ToolConfig) error {
 	if err := cfg.Validate(); err != nil {
@@ -197,6 +226,35 @@ func (l *Limiter) SetToolConfig(toolName string, cfg *ToolConfig) error {
 	return nil
 }
 
+// SetClassConfig sets the configuration shared by the tools of a class
+// without a configuration of their own
+func (l *Limiter) SetClassConfig(class ToolClass, cfg *ToolConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid class config: %w", err)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.classConfigs[class] = cfg
+
+	l.logger.DebugEvent().
+		Str("class", string(class)).
+		Int("limit", cfg.Limit).
+		Dur("window", cfg.Window).
+		Msg("tool class rate limit config updated")
+
+	return nil
+}
+
+// SetToolClass assigns a tool to a class
+func (l *Limiter) SetToolClass(toolName string, class ToolClass) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.toolClasses[toolName] = class
+}
+
 // GetToolConfig returns the configuration for a specific tool
 func (l *Limiter) GetToolConfig(toolName string) *ToolConfig {
 	l.mutex.RLock()
@@ -258,6 +316,26 @@ func (l *Limiter) extractToolName(key string) string {
 	return ""
 }
 
+// CheckStore verifies that the store is reachable by writing, reading and
+// deleting a probe key
+func (l *Limiter) CheckStore() error {
+	if l.store == nil {
+		return fmt.Errorf("rate limit store is not configured")
+	}
+
+	key := l.GenerateKey("", "") + "preflight"
+	if _, err := l.store.Increment(key, time.Second); err != nil {
+		return fmt.Errorf("rate limit store write failed: %w", err)
+	}
+	if _, err := l.store.Get(key); err != nil {
+		return fmt.Errorf("rate limit store read failed: %w", err)
+	}
+	if err := l.store.Delete(key); err != nil {
+		return fmt.Errorf("rate limit store delete failed: %w", err)
+	}
+	return nil
+}
+
 // Close cleans up resources
 func (l *Limiter) Close() error {
 	if memStore, ok := l.store.(*MemoryStore); ok {
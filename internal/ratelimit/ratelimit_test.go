@@ -1,10 +1,16 @@
 package ratelimit
 
 import (
+	"context"
+	"errors"
 	"os"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"mcp-go-assistant/internal/logging"
 )
 
 // TestDefaultConfig tests default configuration
@@ -105,6 +111,30 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid behavior",
+			cfg: &Config{
+				Limit:     100,
+				Window:    1 * time.Minute,
+				Mode:      ModePerTool,
+				Algorithm: AlgorithmTokenBucket,
+				StoreType: StoreMemory,
+				Behavior:  Behavior("queue"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "wait behavior without max wait",
+			cfg: &Config{
+				Limit:     100,
+				Window:    1 * time.Minute,
+				Mode:      ModePerTool,
+				Algorithm: AlgorithmTokenBucket,
+				StoreType: StoreMemory,
+				Behavior:  BehaviorWait,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -257,6 +287,75 @@ func TestMemoryStoreWindowReset(t *testing.T) {
 	}
 }
 
+// TestMemoryStorePeek tests reading the count of the current window
+func TestMemoryStorePeek(t *testing.T) {
+	store := NewMemoryStore()
+	defer store.Stop()
+
+	window := 50 * time.Millisecond
+	store.Increment("key", window)
+	store.Increment("key", window)
+
+	for range 2 {
+		if count, err := store.Peek("key", window); err != nil || count != 2 {
+			t.Errorf("Peek() = %d, %v, want 2", count, err)
+		}
+	}
+	if count, _ := store.Peek("missing", window); count != 0 {
+		t.Errorf("Peek() of a missing key = %d, want 0", count)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if count, _ := store.Peek("key", window); count != 0 {
+		t.Errorf("Peek() after the window = %d, want 0", count)
+	}
+}
+
+// TestMemoryStoreMaxKeys tests evicting the least recently used keys
+func TestMemoryStoreMaxKeys(t *testing.T) {
+	store := NewBoundedMemoryStore(2)
+	defer store.Stop()
+
+	window := 1 * time.Minute
+	_, _ = store.Increment("a", window)
+	_, _ = store.Increment("b", window)
+	_, _ = store.Increment("a", window) // a is now the most recently used
+	_, _ = store.Increment("c", window)
+
+	if store.Len() != 2 {
+		t.Errorf("Expected 2 keys, got %d", store.Len())
+	}
+	if count, _ := store.Get("b"); count != 0 {
+		t.Errorf("Expected b to be evicted, got count=%d", count)
+	}
+	if count, _ := store.Get("a"); count != 2 {
+		t.Errorf("Expected a to be kept with count=2, got %d", count)
+	}
+	if count, _ := store.Get("c"); count != 1 {
+		t.Errorf("Expected c to be kept with count=1, got %d", count)
+	}
+}
+
+// TestMemoryStoreCleanup tests removing idle keys
+func TestMemoryStoreCleanup(t *testing.T) {
+	store := NewMemoryStore()
+	defer store.Stop()
+
+	window := 1 * time.Minute
+	_, _ = store.Increment("idle", window)
+	_, _ = store.Increment("active", window)
+	store.buckets["idle"].Value.(*memoryEntry).bucket.LastUpdate = time.Now().Add(-time.Hour)
+
+	store.cleanup()
+
+	if store.Len() != 1 {
+		t.Errorf("Expected 1 key after cleanup, got %d", store.Len())
+	}
+	if count, _ := store.Get("active"); count != 1 {
+		t.Errorf("Expected active key to be kept, got count=%d", count)
+	}
+}
+
 // TestNoOpStore tests no-op store
 func TestNoOpStore(t *testing.T) {
 	store := NewNoOpStore()
@@ -456,6 +555,206 @@ func TestExtractToolName(t *testing.T) {
 	}
 }
 
+// failingStore is a Store whose writes always fail
+type failingStore struct{ NoOpStore }
+
+func (*failingStore) Increment(string, time.Duration) (int, error) {
+	return 0, errors.New("connection refused")
+}
+
+// TestLimiterCheckStore tests the store connectivity probe
+func TestLimiterCheckStore(t *testing.T) {
+	cfg := &Config{KeyPrefix: "mcp", Mode: ModePerTool}
+
+	store := NewMemoryStore()
+	defer store.Stop()
+
+	limiter := &Limiter{config: cfg, store: store}
+	if err := limiter.CheckStore(); err != nil {
+		t.Fatalf("CheckStore() error = %v", err)
+	}
+	if len(store.buckets) != 0 {
+		t.Errorf("expected probe key to be deleted, got %d buckets", len(store.buckets))
+	}
+
+	limiter = &Limiter{config: cfg, store: &failingStore{}}
+	if err := limiter.CheckStore(); err == nil {
+		t.Error("expected error from failing store")
+	}
+
+	limiter = &Limiter{config: cfg}
+	if err := limiter.CheckStore(); err == nil {
+		t.Error("expected error without a store")
+	}
+}
+
+// newTestMiddleware returns a middleware allowing one call per window
+func newTestMiddleware(t *testing.T, behavior Behavior, window, maxWait time.Duration) *Middleware {
+	t.Helper()
+	log, err := logging.New("fatal", "json", "stderr", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewMemoryStore()
+	t.Cleanup(store.Stop)
+
+	cfg := DefaultConfig()
+	cfg.Limit = 1
+	cfg.Window = window
+	cfg.Behavior = behavior
+	cfg.MaxWait = maxWait
+	return NewMiddleware(NewLimiter(cfg, store, nil, log), log)
+}
+
+// TestMiddlewareAcquire tests rejecting and waiting for calls over the limit
+func TestMiddlewareAcquire(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reject", func(t *testing.T) {
+		m := newTestMiddleware(t, BehaviorReject, time.Minute, time.Second)
+		if err := m.Acquire(ctx, "godoc", "default"); err != nil {
+			t.Fatalf("first Acquire() error = %v", err)
+		}
+		if err := m.Acquire(ctx, "godoc", "default"); !IsRateLimitError(err) {
+			t.Errorf("expected RateLimitError, got %v", err)
+		}
+	})
+
+	t.Run("wait until the window resets", func(t *testing.T) {
+		m := newTestMiddleware(t, BehaviorWait, 50*time.Millisecond, time.Second)
+		if err := m.Acquire(ctx, "godoc", "default"); err != nil {
+			t.Fatalf("first Acquire() error = %v", err)
+		}
+		start := time.Now()
+		if err := m.Acquire(ctx, "godoc", "default"); err != nil {
+			t.Fatalf("second Acquire() error = %v", err)
+		}
+		if waited := time.Since(start); waited < 40*time.Millisecond {
+			t.Errorf("expected the call to wait for the window, waited %v", waited)
+		}
+	})
+
+	t.Run("max wait exceeded", func(t *testing.T) {
+		m := newTestMiddleware(t, BehaviorWait, time.Minute, 30*time.Millisecond)
+		_ = m.Acquire(ctx, "godoc", "default")
+		if err := m.Acquire(ctx, "godoc", "default"); !IsRateLimitError(err) {
+			t.Errorf("expected RateLimitError, got %v", err)
+		}
+	})
+
+	t.Run("deadline bounds the wait", func(t *testing.T) {
+		m := newTestMiddleware(t, BehaviorWait, time.Minute, time.Minute)
+		_ = m.Acquire(ctx, "godoc", "default")
+
+		dctx, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := m.Acquire(dctx, "godoc", "default")
+		if err == nil {
+			t.Fatal("expected an error once the deadline passed")
+		}
+		if waited := time.Since(start); waited > time.Second {
+			t.Errorf("expected the deadline to end the wait, waited %v", waited)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		m := newTestMiddleware(t, BehaviorWait, time.Minute, time.Minute)
+		_ = m.Acquire(ctx, "godoc", "default")
+
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		if err := m.Acquire(cctx, "godoc", "default"); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+// TestMiddlewareWaitRecordsOneRejection tests that polls of a waiting call
+// leave the limit alone and that only a wait that gives up is recorded as
+// a rejection
+func TestMiddlewareWaitRecordsOneRejection(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMiddleware(t, BehaviorWait, time.Minute, 100*time.Millisecond)
+	key := m.limiter.GenerateKey("wait-tool", "default")
+	toolName := m.limiter.extractToolName(key)
+	if toolName == "" {
+		toolName = "unknown"
+	}
+	rejected := GetMetrics().rejectedTotal.WithLabelValues(toolName, string(m.limiter.config.Mode))
+	before := testutil.ToFloat64(rejected)
+
+	if err := m.Acquire(ctx, "wait-tool", "default"); err != nil {
+		t.Fatalf("first Acquire() error = %v", err)
+	}
+	if err := m.Acquire(ctx, "wait-tool", "default"); !IsRateLimitError(err) {
+		t.Fatalf("expected RateLimitError, got %v", err)
+	}
+
+	if got := testutil.ToFloat64(rejected) - before; got != 1 {
+		t.Errorf("recorded %v rejections, want 1", got)
+	}
+	// The admitted call and the first attempt of the waiting one
+	if stats, _ := m.limiter.Stats(key); stats.Current != 2 {
+		t.Errorf("counted %d calls, want 2", stats.Current)
+	}
+}
+
+// TestLimiterClassLimits tests that tools get the limits of their class
+// unless they have their own
+func TestLimiterClassLimits(t *testing.T) {
+	log, err := logging.New("fatal", "json", "stderr", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewMemoryStore()
+	defer store.Stop()
+
+	limiter := NewLimiter(DefaultConfig(), store, nil, log)
+	if err := limiter.SetClassConfig(ClassAnalyze, &ToolConfig{Enabled: true, Limit: 2, Window: time.Minute}); err != nil {
+		t.Fatalf("SetClassConfig() error = %v", err)
+	}
+	if err := limiter.SetToolConfig("code-review", &ToolConfig{Enabled: true, Limit: 5, Window: time.Minute}); err != nil {
+		t.Fatalf("SetToolConfig() error = %v", err)
+	}
+	limiter.SetToolClass("call-graph", ClassAnalyze)
+	limiter.SetToolClass("code-review", ClassAnalyze)
+	limiter.SetToolClass("health", ClassRead)
+
+	tests := []struct {
+		tool string
+		want int
+	}{
+		{"call-graph", 2},  // Class limit
+		{"code-review", 5}, // Tool limit over the class limit
+		{"health", 100},    // Class without limits
+		{"godoc", 100},     // Tool without class
+	}
+	for _, tt := range tests {
+		stats, err := limiter.Stats(limiter.GenerateKey(tt.tool, "default"))
+		if err != nil {
+			t.Fatalf("Stats() error = %v", err)
+		}
+		if stats.Limit != tt.want {
+			t.Errorf("limit of %s = %d, want %d", tt.tool, stats.Limit, tt.want)
+		}
+	}
+
+	key := limiter.GenerateKey("call-graph", "default")
+	for i := 0; i < 2; i++ {
+		if allowed, _ := limiter.Allow(key); !allowed {
+			t.Fatalf("call %d rejected under the class limit", i+1)
+		}
+	}
+	if allowed, _ := limiter.Allow(key); allowed {
+		t.Error("expected the class limit to reject the third call")
+	}
+
+	if err := limiter.SetClassConfig(ClassRead, &ToolConfig{Enabled: true}); err == nil {
+		t.Error("expected an error for a class config without limit")
+	}
+}
+
 // TestLoadFromEnv tests loading config from environment
 func TestLoadFromEnv(t *testing.T) {
 	// Save original environment values
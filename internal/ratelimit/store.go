@@ -1,6 +1,7 @@
 package ratelimit
 
 import (
+	"container/list"
 	"sync"
 	"time"
 )
@@ -11,16 +12,25 @@ type Store interface {
 	Increment(key string, window time.Duration) (int, error)
 	// Get retrieves the current count for a key
 	Get(key string) (int, error)
+	// Peek returns the count of a key in its current window, zero once the
+	// window has passed, without changing it
+	Peek(key string, window time.Duration) (int, error)
 	// Reset resets the counter for a key
 	Reset(key string) error
 	// Delete removes a key from storage
 	Delete(key string) error
 }
 
-// MemoryStore implements an in-memory rate limiting store with automatic cleanup
+// MemoryStore implements an in-memory rate limiting store with automatic
+// cleanup. A bounded store evicts its least recently incremented keys to
+// stay within its maximum number of keys.
 type MemoryStore struct {
-	// buckets holds the rate limit buckets indexed by key
-	buckets map[string]*Bucket
+	// buckets holds the elements of lru indexed by key
+	buckets map[string]*list.Element
+	// lru holds the entries, most recently incremented first
+	lru *list.List
+	// maxKeys is the maximum number of keys kept; zero means unbounded
+	maxKeys int
 	// mutex provides thread-safe access
 	mutex sync.RWMutex
 	// cleanupInterval is how often to check for expired entries
@@ -29,10 +39,24 @@ type MemoryStore struct {
 	done chan struct{}
 }
 
-// NewMemoryStore creates a new in-memory store with periodic cleanup
+// memoryEntry is an element of the LRU list of a MemoryStore
+type memoryEntry struct {
+	key    string
+	bucket *Bucket
+}
+
+// NewMemoryStore creates a new unbounded in-memory store with periodic cleanup
 func NewMemoryStore() *MemoryStore {
+	return NewBoundedMemoryStore(0)
+}
+
+// NewBoundedMemoryStore creates a new in-memory store with periodic cleanup
+// keeping at most maxKeys keys, or any number when maxKeys is not positive
+func NewBoundedMemoryStore(maxKeys int) *MemoryStore {
 	store := &MemoryStore{
-		buckets:         make(map[string]*Bucket),
+		buckets:         make(map[string]*list.Element),
+		lru:             list.New(),
+		maxKeys:         max(0, maxKeys),
 		cleanupInterval: 5 * time.Minute,
 		done:            make(chan struct{}),
 	}
@@ -49,19 +73,30 @@ func (s *MemoryStore) Increment(key string, window time.Duration) (int, error) {
 	defer s.mutex.Unlock()
 
 	now := time.Now()
-	bucket, exists := s.buckets[key]
+	elem, exists := s.buckets[key]
 
 	if !exists {
-		// Create new bucket
-		bucket = &Bucket{
-			Count:       1,
-			LastUpdate:  now,
-			WindowStart: now,
+		// Make room for the new bucket
+		if s.maxKeys > 0 && s.lru.Len() >= s.maxKeys {
+			s.evictOldest()
 		}
-		s.buckets[key] = bucket
+
+		// Create new bucket
+		s.buckets[key] = s.lru.PushFront(&memoryEntry{
+			key: key,
+			bucket: &Bucket{
+				Count:       1,
+				LastUpdate:  now,
+				WindowStart: now,
+			},
+		})
+		SetStoreKeys(s.lru.Len())
 		return 1, nil
 	}
 
+	s.lru.MoveToFront(elem)
+	bucket := elem.Value.(*memoryEntry).bucket
+
 	// Check if the window has expired
 	if now.Sub(bucket.WindowStart) >= window {
 		// Reset bucket for new window
@@ -81,21 +116,36 @@ func (s *MemoryStore) Get(key string) (int, error) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	bucket, exists := s.buckets[key]
+	elem, exists := s.buckets[key]
 	if !exists {
 		return 0, nil
 	}
 
+	return elem.Value.(*memoryEntry).bucket.Count, nil
+}
+
+// Peek returns the count of a key in its current window, or zero once the
+// window has passed. Unlike Increment, it leaves the key's place in the
+// LRU list as is.
+func (s *MemoryStore) Peek(key string, window time.Duration) (int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	elem, exists := s.buckets[key]
+	if !exists {
+		return 0, nil
+	}
+
+	bucket := elem.Value.(*memoryEntry).bucket
+	if time.Since(bucket.WindowStart) >= window {
+		return 0, nil
+	}
 	return bucket.Count, nil
 }
 
 // Reset resets the counter for a key
 func (s *MemoryStore) Reset(key string) error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-
-	delete(s.buckets, key)
-	return nil
+	return s.Delete(key)
 }
 
 // Delete removes a key from storage
@@ -103,10 +153,37 @@ func (s *MemoryStore) Delete(key string) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	delete(s.buckets, key)
+	if elem, exists := s.buckets[key]; exists {
+		s.remove(elem)
+		SetStoreKeys(s.lru.Len())
+	}
 	return nil
 }
 
+// Len returns the number of keys in the store
+func (s *MemoryStore) Len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.lru.Len()
+}
+
+// evictOldest removes the least recently incremented key. The caller holds
+// the write lock.
+func (s *MemoryStore) evictOldest() {
+	if elem := s.lru.Back(); elem != nil {
+		s.remove(elem)
+		RecordEvictions(EvictionLRU, 1)
+	}
+}
+
+// remove deletes an element from the map and the LRU list. The caller holds
+// the write lock.
+func (s *MemoryStore) remove(elem *list.Element) {
+	s.lru.Remove(elem)
+	delete(s.buckets, elem.Value.(*memoryEntry).key)
+}
+
 // cleanupLoop periodically removes expired entries
 func (s *MemoryStore) cleanupLoop() {
 	ticker := time.NewTicker(s.cleanupInterval)
@@ -122,7 +199,8 @@ func (s *MemoryStore) cleanupLoop() {
 	}
 }
 
-// cleanup removes expired entries older than 10 minutes
+// cleanup removes expired entries older than 10 minutes. Entries are
+// ordered by last increment, so it stops at the first recent one.
 func (s *MemoryStore) cleanup() {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -130,11 +208,17 @@ func (s *MemoryStore) cleanup() {
 	now := time.Now()
 	expiry := 10 * time.Minute
 
-	for key, bucket := range s.buckets {
-		if now.Sub(bucket.LastUpdate) > expiry {
-			delete(s.buckets, key)
+	removed := 0
+	for elem := s.lru.Back(); elem != nil; elem = s.lru.Back() {
+		if now.Sub(elem.Value.(*memoryEntry).bucket.LastUpdate) <= expiry {
+			break
 		}
+		s.remove(elem)
+		removed++
 	}
+
+	RecordCleanup(removed)
+	SetStoreKeys(s.lru.Len())
 }
 
 // Stop stops the cleanup goroutine
@@ -160,6 +244,11 @@ func (s *NoOpStore) Get(key string) (int, error) {
 	return 0, nil
 }
 
+// Peek returns 0
+func (s *NoOpStore) Peek(key string, window time.Duration) (int, error) {
+	return 0, nil
+}
+
 // Reset does nothing
 func (s *NoOpStore) Reset(key string) error {
 	return nil
@@ -84,6 +84,29 @@ const (
 	AlgorithmSlidingWindow Algorithm = "sliding-window"
 )
 
+// ToolClass groups tools by cost, so tools without limits of their own get
+// the limits of their class
+type ToolClass string
+
+const (
+	// ClassRead is for cheap tools that read or transform their input
+	ClassRead ToolClass = "read"
+	// ClassAnalyze is for expensive tools that load packages or run the Go
+	// toolchain
+	ClassAnalyze ToolClass = "analyze"
+)
+
+// Behavior represents what happens to calls over the limit
+type Behavior string
+
+const (
+	// BehaviorReject fails calls over the limit immediately
+	BehaviorReject Behavior = "reject"
+	// BehaviorWait blocks calls over the limit until they fit in it, up to
+	// a maximum wait
+	BehaviorWait Behavior = "wait"
+)
+
 // StoreType represents the storage backend type
 type StoreType string
 
@@ -15,10 +15,23 @@ type RetryMetrics struct {
 	retryAttempts *prometheus.HistogramVec
 	// retryDelaySeconds is a histogram for distribution of retry delays
 	retryDelaySeconds *prometheus.HistogramVec
+	// callAttempts is a histogram for the attempts made by calls that retried or gave up
+	callAttempts *prometheus.HistogramVec
+	// totalDelaySeconds is a histogram for the total delay of calls that retried or gave up
+	totalDelaySeconds *prometheus.HistogramVec
+	// giveUpsTotal is a counter for calls that failed by tool and reason
+	giveUpsTotal *prometheus.CounterVec
 
 	mu sync.Mutex
 }
 
+// Reasons a call gave up, used as the reason label of mcp_retry_give_ups_total
+const (
+	GiveUpExhausted    = "exhausted"     // All attempts failed
+	GiveUpNotRetryable = "not_retryable" // The retry condition rejected the error
+	GiveUpCancelled    = "cancelled"     // The context was cancelled between attempts
+)
+
 var (
 	// globalMetrics is the singleton metrics instance
 	globalMetrics *RetryMetrics
@@ -59,11 +72,43 @@ func initMetrics() {
 		[]string{"tool"},
 	)
 
+	// Initialize per-call attempts histogram
+	globalMetrics.callAttempts = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "mcp_retry_call_attempts",
+			Help:    "Distribution of attempts made by calls that retried or gave up",
+			Buckets: []float64{1, 2, 3, 4, 5, 10, 20},
+		},
+		[]string{"tool"},
+	)
+
+	// Initialize per-call total delay histogram
+	globalMetrics.totalDelaySeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "mcp_retry_total_delay_seconds",
+			Help:    "Distribution of the total retry delay of calls that retried or gave up",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 10),
+		},
+		[]string{"tool"},
+	)
+
+	// Initialize give-ups counter
+	globalMetrics.giveUpsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mcp_retry_give_ups_total",
+			Help: "Total number of calls that failed per tool and reason",
+		},
+		[]string{"tool", "reason"}, // reason: exhausted, not_retryable, cancelled
+	)
+
 	// Register metrics with default registry
 	prometheus.MustRegister(
 		globalMetrics.retriesTotal,
 		globalMetrics.retryAttempts,
 		globalMetrics.retryDelaySeconds,
+		globalMetrics.callAttempts,
+		globalMetrics.totalDelaySeconds,
+		globalMetrics.giveUpsTotal,
 	)
 }
 
@@ -117,3 +162,23 @@ func RecordRetryExhausted(tool string) {
 
 	metrics.retriesTotal.WithLabelValues(tool, "exhausted").Inc()
 }
+
+// RecordRetryCall records the attempts and total delay of a call that
+// retried or gave up
+func RecordRetryCall(tool string, attempts uint, totalDelay time.Duration) {
+	metrics := getMetrics()
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	metrics.callAttempts.WithLabelValues(tool).Observe(float64(attempts))
+	metrics.totalDelaySeconds.WithLabelValues(tool).Observe(totalDelay.Seconds())
+}
+
+// RecordRetryGiveUp records that a call failed for the given reason
+func RecordRetryGiveUp(tool, reason string) {
+	metrics := getMetrics()
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	metrics.giveUpsTotal.WithLabelValues(tool, reason).Inc()
+}
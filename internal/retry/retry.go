@@ -12,7 +12,7 @@ type RetryableFunc func(attempt uint) error
 
 // RetryableFuncWithData is a function that can be retried and returns data
 // attempt is the current attempt number (starting at 0 for the first call)
-type RetryableFuncWithData func(attempt uint) (interface{}, error)
+type RetryableFuncWithData[T any] func(attempt uint) (T, error)
 
 // OnRetryFunc is called after each failed attempt
 // attempt is the attempt number that just failed
@@ -25,13 +25,11 @@ type OnRetryFunc func(attempt uint, err error, delay time.Duration)
 // returns true if the error should be retried, false otherwise
 type RetryIfFunc func(err error) bool
 
-// Retryer defines the interface for retry operations
+// Retryer defines the interface for retry operations. DoWithData adds
+// typed results to any Retryer.
 type Retryer interface {
 	// Do executes the function with retry logic
 	Do(ctx context.Context, fn RetryableFunc) error
-
-	// DoWithData executes the function with retry logic and returns data
-	DoWithData(ctx context.Context, fn RetryableFuncWithData) (interface{}, error)
 }
 
 // Retry implements the Retryer interface
@@ -82,6 +80,13 @@ func NewRetryer(config *Config) Retryer {
 
 // Do executes the function with retry logic
 func (r *Retry) Do(ctx context.Context, fn RetryableFunc) error {
+	return r.DoNotify(ctx, fn, nil)
+}
+
+// DoNotify is Do with notify called on each retry of this call, after the
+// callback set with WithOnRetry. Unlike that callback, notify is not shared
+// with concurrent calls.
+func (r *Retry) DoNotify(ctx context.Context, fn RetryableFunc, notify OnRetryFunc) error {
 	var lastErr error
 	var totalDelay time.Duration
 	var lastDelay time.Duration
@@ -119,6 +124,9 @@ func (r *Retry) Do(ctx context.Context, fn RetryableFunc) error {
 		if r.onRetry != nil {
 			r.onRetry(attempt, lastErr, nextDelay)
 		}
+		if notify != nil {
+			notify(attempt, lastErr, nextDelay)
+		}
 
 		// Wait for delay or context cancellation
 		if nextDelay > 0 {
@@ -140,65 +148,16 @@ func (r *Retry) Do(ctx context.Context, fn RetryableFunc) error {
 	}
 }
 
-// DoWithData executes the function with retry logic and returns data
-func (r *Retry) DoWithData(ctx context.Context, fn RetryableFuncWithData) (interface{}, error) {
-	var result interface{}
-	var lastErr error
-	var totalDelay time.Duration
-	var lastDelay time.Duration
-
-	for attempt := uint(0); attempt < r.maxAttempts; attempt++ {
-		// Check if context is cancelled before executing
-		if err := ctx.Err(); err != nil {
-			return nil, fmt.Errorf("%w: %v", ErrContextCancelled, err)
-		}
-
-		// Execute the function
-		result, lastErr = fn(attempt)
-
-		// If no error, return success
-		if lastErr == nil {
-			return result, nil
-		}
-
-		// Check if we should retry based on the error
-		if r.retryIf != nil && !r.retryIf(lastErr) {
-			return nil, lastErr
-		}
-
-		// If this was the last attempt, return the error
-		if attempt == r.maxAttempts-1 {
-			break
-		}
-
-		// Calculate delay for next attempt
-		nextDelay := r.strategy.NextDelay(attempt)
-		lastDelay = nextDelay
-		totalDelay += nextDelay
-
-		// Call onRetry callback if set
-		if r.onRetry != nil {
-			r.onRetry(attempt, lastErr, nextDelay)
-		}
-
-		// Wait for delay or context cancellation
-		if nextDelay > 0 {
-			select {
-			case <-time.After(nextDelay):
-				// Delay elapsed, continue to next attempt
-			case <-ctx.Done():
-				return nil, fmt.Errorf("%w: %v", ErrContextCancelled, ctx.Err())
-			}
-		}
-	}
-
-	// All attempts exhausted
-	return result, &RetryError{
-		OriginalError: lastErr,
-		Attempts:      r.maxAttempts,
-		LastDelay:     lastDelay,
-		TotalDelay:    totalDelay,
-	}
+// DoWithData executes fn with the retry logic of r and returns the data of
+// the last attempt, along with the error when no attempt succeeded
+func DoWithData[T any](ctx context.Context, r Retryer, fn RetryableFuncWithData[T]) (T, error) {
+	var result T
+	err := r.Do(ctx, func(attempt uint) error {
+		var err error
+		result, err = fn(attempt)
+		return err
+	})
+	return result, err
 }
 
 // WithMaxAttempts sets the maximum number of retry attempts
@@ -7,6 +7,12 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"mcp-go-assistant/internal/types"
 )
 
 // TestRetryError tests the RetryError implementation
@@ -571,13 +577,13 @@ func TestRetryWithDataSuccess(t *testing.T) {
 	retryer := NewRetryer(config)
 
 	callCount := 0
-	fn := func(attempt uint) (interface{}, error) {
+	fn := func(attempt uint) (string, error) {
 		callCount++
 		return "success", nil
 	}
 
 	ctx := context.Background()
-	result, err := retryer.DoWithData(ctx, fn)
+	result, err := DoWithData(ctx, retryer, fn)
 
 	if err != nil {
 		t.Errorf("DoWithData() error = %v, want nil", err)
@@ -599,16 +605,16 @@ func TestRetryWithDataRetry(t *testing.T) {
 	retryer := NewRetryer(config)
 
 	callCount := 0
-	fn := func(attempt uint) (interface{}, error) {
+	fn := func(attempt uint) (string, error) {
 		callCount++
 		if callCount < 2 {
-			return nil, errors.New("test error")
+			return "", errors.New("test error")
 		}
 		return "success", nil
 	}
 
 	ctx := context.Background()
-	result, err := retryer.DoWithData(ctx, fn)
+	result, err := DoWithData(ctx, retryer, fn)
 
 	if err != nil {
 		t.Errorf("DoWithData() error = %v, want nil", err)
@@ -623,6 +629,28 @@ func TestRetryWithDataRetry(t *testing.T) {
 	}
 }
 
+// TestDoWithDataWrapper tests typed data returned through a retry wrapper
+func TestDoWithDataWrapper(t *testing.T) {
+	wrapper := NewRetryWrapper("data-wrapper", NewRetryer(&Config{
+		MaxAttempts:  2,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     time.Millisecond,
+		Multiplier:   1,
+		Strategy:     "constant",
+	}), nil)
+
+	// The data of the last attempt comes with the error
+	result, err := DoWithData(context.Background(), wrapper, func(attempt uint) (int, error) {
+		return int(attempt) + 1, errors.New("test error")
+	})
+	if !IsRetryError(err) {
+		t.Errorf("DoWithData() error = %v, want a RetryError", err)
+	}
+	if result != 2 {
+		t.Errorf("DoWithData() result = %d, want 2", result)
+	}
+}
+
 // TestRetryChainMethods tests retry chain methods
 func TestRetryChainMethods(t *testing.T) {
 	config := DefaultConfig()
@@ -724,3 +752,143 @@ func TestNewRetryerNilConfig(t *testing.T) {
 		t.Error("NewRetryer(nil) = nil, want non-nil retryer")
 	}
 }
+
+func TestRetryWrapper_Metrics(t *testing.T) {
+	newWrapper := func(tool string) *RetryWrapper {
+		return NewRetryWrapper(tool, NewRetryer(&Config{
+			MaxAttempts:  3,
+			InitialDelay: time.Millisecond,
+			MaxDelay:     time.Millisecond,
+			Multiplier:   1,
+			Strategy:     "constant",
+		}), nil)
+	}
+	transient := errors.New("transient")
+
+	// Recovers on the second attempt
+	calls := 0
+	if err := newWrapper("metrics-flaky").Do(context.Background(), func(uint) error {
+		calls++
+		if calls < 2 {
+			return transient
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	// Fails every attempt
+	if err := newWrapper("metrics-hard").Do(context.Background(), func(uint) error {
+		return transient
+	}); !IsRetryError(err) {
+		t.Fatalf("expected a RetryError, got %v", err)
+	}
+
+	// Rejected by the retry condition
+	skip := newWrapper("metrics-skip")
+	skip.SetRetryIf(func(error) bool { return false })
+	if err := skip.Do(context.Background(), func(uint) error { return transient }); err != transient {
+		t.Fatalf("expected the original error, got %v", err)
+	}
+
+	m := getMetrics()
+	tests := []struct {
+		tool     string
+		reason   string
+		giveUps  float64
+		attempts float64 // Sum of observed attempts
+	}{
+		{"metrics-flaky", GiveUpExhausted, 0, 2},
+		{"metrics-hard", GiveUpExhausted, 1, 3},
+		{"metrics-skip", GiveUpNotRetryable, 1, 1},
+	}
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(m.giveUpsTotal.WithLabelValues(tt.tool, tt.reason)); got != tt.giveUps {
+			t.Errorf("%s: give-ups(%s) = %v, want %v", tt.tool, tt.reason, got, tt.giveUps)
+		}
+		if got := histogramSum(t, m.callAttempts.WithLabelValues(tt.tool)); got != tt.attempts {
+			t.Errorf("%s: attempts sum = %v, want %v", tt.tool, got, tt.attempts)
+		}
+	}
+	if got := testutil.ToFloat64(m.retriesTotal.WithLabelValues("metrics-flaky", "success")); got != 1 {
+		t.Errorf("expected the recovered call to count as a retry success, got %v", got)
+	}
+}
+
+func TestRetryWrapper_ConcurrentCalls(t *testing.T) {
+	w := NewRetryWrapper("metrics-concurrent", NewRetryer(&Config{
+		MaxAttempts:  3,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     time.Millisecond,
+		Multiplier:   1,
+		Strategy:     "constant",
+	}), nil)
+
+	// The first call retries once, after a second call ran to completion
+	// on the same wrapper
+	secondDone := make(chan struct{})
+	firstErr := make(chan error, 1)
+	started := make(chan struct{})
+	go func() {
+		firstErr <- w.Do(context.Background(), func(attempt uint) error {
+			if attempt == 0 {
+				close(started)
+				<-secondDone
+				return errors.New("transient")
+			}
+			return nil
+		})
+	}()
+	<-started
+	if err := w.Do(context.Background(), func(uint) error { return nil }); err != nil {
+		t.Fatalf("second call error = %v", err)
+	}
+	close(secondDone)
+	if err := <-firstErr; err != nil {
+		t.Fatalf("first call error = %v", err)
+	}
+
+	// Only the first call retried, and it made two attempts
+	h := getMetrics().callAttempts.WithLabelValues("metrics-concurrent").(prometheus.Metric)
+	var metric dto.Metric
+	if err := h.Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	if got := metric.GetHistogram(); got.GetSampleCount() != 1 || got.GetSampleSum() != 2 {
+		t.Errorf("expected one call with 2 attempts, got %d calls with %v attempts", got.GetSampleCount(), got.GetSampleSum())
+	}
+}
+
+func TestRetryableErrors_Kinds(t *testing.T) {
+	retryIf := RetryableErrors("go-doc")
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		// Would match the "exit status" pattern without its kind
+		{"package not found", types.NewKindError(types.KindPackageNotFound, "go doc failed: exit status 1"), false},
+		{"parse failure", types.NewKindError(types.KindParseFailure, "failed to parse Go code"), false},
+		{"toolchain missing", types.NewKindError(types.KindToolchainMissing, "go doc failed: command not found"), false},
+		{"module download", fmt.Errorf("lookup: %w", types.NewKindError(types.KindModuleDownloadFailed, "go list failed")), true},
+		{"untyped", errors.New("go doc failed: exit status 1"), true},
+		{"forbidden", types.NewForbiddenError("include_unexported is limited to packages of the module in working_dir"), false},
+		{"validation", types.NewValidationError("include_unexported requires working_dir"), false},
+		{"internal", types.NewInternalError("go doc failed: exit status 1"), true},
+	}
+	for _, tt := range tests {
+		if got := retryIf(tt.err); got != tt.want {
+			t.Errorf("%s: retry = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// histogramSum returns the sum of the values observed by h
+func histogramSum(t *testing.T, h prometheus.Observer) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := h.(prometheus.Metric).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetHistogram().GetSampleSum()
+}
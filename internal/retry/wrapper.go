@@ -2,10 +2,12 @@ package retry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/types"
 )
 
 // RetryWrapper wraps retry operations with metrics and logging
@@ -24,34 +26,42 @@ func NewRetryWrapper(tool string, retryer Retryer, logger *logging.Logger) *Retr
 	}
 }
 
-// Do executes a function with retry logic, metrics, and logging
+// Do executes a function with retry logic, metrics, and logging. Pass the
+// wrapper to DoWithData for functions returning data.
 func (w *RetryWrapper) Do(ctx context.Context, fn RetryableFunc) error {
 	startTime := time.Now()
-	var lastAttempt uint
+	var retries uint
+	var totalDelay time.Duration
 
-	// Configure onRetry callback to log and record metrics
-	if r, ok := w.retryer.(*Retry); ok {
-		r.WithOnRetry(func(attempt uint, err error, delay time.Duration) {
-			lastAttempt = attempt
+	// Count, log and record the retries of this call only; the retryer is
+	// shared by concurrent calls
+	onRetry := func(attempt uint, err error, delay time.Duration) {
+		retries++
+		totalDelay += delay
 
-			// Log retry attempt
-			w.logger.LogRetryAttempt(w.tool, attempt, err, delay)
+		// Log retry attempt
+		w.logger.LogRetryAttempt(w.tool, attempt, err, delay)
 
-			// Record metrics
-			RecordRetryAttempt(w.tool, attempt)
-			RecordRetryDelay(w.tool, delay)
-		})
+		// Record metrics
+		RecordRetryAttempt(w.tool, attempt)
+		RecordRetryDelay(w.tool, delay)
 	}
 
 	// Execute with retry
-	err := w.retryer.Do(ctx, fn)
+	var err error
+	if r, ok := w.retryer.(*Retry); ok {
+		err = r.DoNotify(ctx, fn, onRetry)
+	} else {
+		err = w.retryer.Do(ctx, fn)
+	}
 	totalDuration := time.Since(startTime)
 
 	if err == nil {
 		// Success
-		if lastAttempt > 0 {
-			w.logger.LogRetrySuccess(w.tool, lastAttempt+1, totalDuration)
+		if retries > 0 {
+			w.logger.LogRetrySuccess(w.tool, retries+1, totalDuration)
 			RecordRetrySuccess(w.tool)
+			RecordRetryCall(w.tool, retries+1, totalDelay)
 		}
 		return nil
 	}
@@ -61,71 +71,27 @@ func (w *RetryWrapper) Do(ctx context.Context, fn RetryableFunc) error {
 		// All attempts exhausted
 		w.logger.LogRetryExhausted(w.tool, retryErr.Attempts, retryErr.TotalDelay, retryErr.OriginalError)
 		RecordRetryExhausted(w.tool)
+		RecordRetryGiveUp(w.tool, GiveUpExhausted)
+		RecordRetryCall(w.tool, retryErr.Attempts, retryErr.TotalDelay)
 		return err
 	}
 
 	if IsContextCancelledError(err) {
 		// Context cancelled
-		w.logger.LogRetryCancelled(w.tool, lastAttempt+1)
+		w.logger.LogRetryCancelled(w.tool, retries+1)
+		RecordRetryGiveUp(w.tool, GiveUpCancelled)
+		RecordRetryCall(w.tool, retries+1, totalDelay)
 		return err
 	}
 
-	// Other error
+	// Non-retryable error
+	w.logger.LogRetrySkipped(w.tool, retries, err)
 	RecordRetryFailed(w.tool)
+	RecordRetryGiveUp(w.tool, GiveUpNotRetryable)
+	RecordRetryCall(w.tool, retries+1, totalDelay)
 	return err
 }
 
-// DoWithData executes a function with retry logic, metrics, and logging, returning data
-func (w *RetryWrapper) DoWithData(ctx context.Context, fn RetryableFuncWithData) (interface{}, error) {
-	startTime := time.Now()
-	var lastAttempt uint
-
-	// Configure onRetry callback to log and record metrics
-	if r, ok := w.retryer.(*Retry); ok {
-		r.WithOnRetry(func(attempt uint, err error, delay time.Duration) {
-			lastAttempt = attempt
-
-			// Log retry attempt
-			w.logger.LogRetryAttempt(w.tool, attempt, err, delay)
-
-			// Record metrics
-			RecordRetryAttempt(w.tool, attempt)
-			RecordRetryDelay(w.tool, delay)
-		})
-	}
-
-	// Execute with retry
-	result, err := w.retryer.DoWithData(ctx, fn)
-	totalDuration := time.Since(startTime)
-
-	if err == nil {
-		// Success
-		if lastAttempt > 0 {
-			w.logger.LogRetrySuccess(w.tool, lastAttempt+1, totalDuration)
-			RecordRetrySuccess(w.tool)
-		}
-		return result, nil
-	}
-
-	// Check error type
-	if retryErr, ok := err.(*RetryError); ok {
-		// All attempts exhausted
-		w.logger.LogRetryExhausted(w.tool, retryErr.Attempts, retryErr.TotalDelay, retryErr.OriginalError)
-		RecordRetryExhausted(w.tool)
-		return result, err
-	}
-
-	if IsContextCancelledError(err) {
-		// Context cancelled
-		w.logger.LogRetryCancelled(w.tool, lastAttempt+1)
-		return result, err
-	}
-
-	// Other error
-	RecordRetryFailed(w.tool)
-	return result, err
-}
-
 // GetRetryer returns underlying retryer
 func (w *RetryWrapper) GetRetryer() Retryer {
 	return w.retryer
@@ -153,6 +119,20 @@ func RetryableErrors(tool string) RetryIfFunc {
 			return false
 		}
 
+		// Tool-domain failures say for themselves whether a retry can help
+		if kind, ok := types.KindOf(err); ok {
+			return kind.Retryable()
+		}
+
+		// Rejected requests fail the same way on every attempt
+		var mcpErr types.MCPError
+		if errors.As(err, &mcpErr) {
+			switch mcpErr.Category() {
+			case "validation", "not_found", "auth", "client":
+				return false
+			}
+		}
+
 		// Tool-specific error patterns
 		errStr := fmt.Sprintf("%v", err)
 
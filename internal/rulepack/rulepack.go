@@ -0,0 +1,295 @@
+// Package rulepack installs and verifies versioned rule packs: directories
+// holding a manifest and the wasm review rules it lists, fetched from a git
+// repository or an OCI registry. An installed pack is identified by the
+// SHA-256 digest of its files, which the configuration records so every
+// server runs exactly the rules that were reviewed when the pack was added.
+package rulepack
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"mcp-go-assistant/internal/codereview"
+	"mcp-go-assistant/internal/logging"
+)
+
+// ManifestFile is the name of the manifest at the root of every pack
+const ManifestFile = "rulepack.yaml"
+
+// ociScheme prefixes sources pulled from an OCI registry with oras; any
+// other source is cloned with git
+const ociScheme = "oci://"
+
+// namePattern restricts pack names, which become directory names
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Manifest describes a rule pack
+type Manifest struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description"`
+	Rules       []Rule `yaml:"rules"`
+}
+
+// Rule is a wasm review rule shipped in a pack
+type Rule struct {
+	Name   string         `yaml:"name"`
+	Wasm   string         `yaml:"wasm"`   // Module path relative to the pack root
+	Config map[string]any `yaml:"config"` // Passed to the module with every file
+}
+
+// Ref is a pack recorded in the configuration
+type Ref struct {
+	Name    string
+	Version string
+	Source  string // Git URL or oci:// reference the pack is installed from
+	SHA256  string // Expected digest of the installed files
+}
+
+// Pack is an installed rule pack
+type Pack struct {
+	Dir      string
+	Manifest Manifest
+	Digest   string // Hex SHA-256 digest of the pack's files
+}
+
+// Dir returns the directory a pack version is installed to under root
+func Dir(root, name, version string) string {
+	return filepath.Join(root, name, version)
+}
+
+// Load reads and validates the pack in dir and computes its digest
+func Load(dir string) (*Pack, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule pack manifest: %w", err)
+	}
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid rule pack manifest %s: %w", filepath.Join(dir, ManifestFile), err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("invalid rule pack manifest %s: %w", filepath.Join(dir, ManifestFile), err)
+	}
+
+	digest, err := Digest(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Pack{Dir: dir, Manifest: m, Digest: digest}, nil
+}
+
+// validate checks the manifest fields and that rule modules stay inside
+// the pack
+func (m *Manifest) validate() error {
+	if !namePattern.MatchString(m.Name) {
+		return fmt.Errorf("name %q must be lowercase letters, digits, '.', '_' or '-'", m.Name)
+	}
+	if m.Version == "" || !filepath.IsLocal(m.Version) || strings.ContainsAny(m.Version, `/\`) {
+		return fmt.Errorf("invalid version %q", m.Version)
+	}
+	if len(m.Rules) == 0 {
+		return errors.New("no rules")
+	}
+	seen := make(map[string]bool)
+	for _, r := range m.Rules {
+		if r.Name == "" {
+			return errors.New("rule without a name")
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("rule %q is listed more than once", r.Name)
+		}
+		seen[r.Name] = true
+		if !filepath.IsLocal(r.Wasm) {
+			return fmt.Errorf("rule %q: wasm path %q must be relative to the pack", r.Name, r.Wasm)
+		}
+	}
+	return nil
+}
+
+// Digest returns the hex SHA-256 digest of the files under dir, ignoring
+// git metadata. It covers every file's path and content, so any change to
+// a pack changes its digest. Symbolic links are refused.
+func Digest(dir string) (string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir() && d.Name() == ".git":
+			return filepath.SkipDir
+		case d.IsDir():
+			return nil
+		case !d.Type().IsRegular():
+			return fmt.Errorf("rule pack file %s is not a regular file", path)
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to digest rule pack: %w", err)
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, file := range files {
+		sum, err := fileDigest(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			return "", fmt.Errorf("failed to digest rule pack: %w", err)
+		}
+		fmt.Fprintf(h, "%s\x00%s\n", file, sum)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// fileDigest returns the hex SHA-256 digest of a file's content
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Verify loads the installed pack ref names under root and checks its
+// name, version and digest against ref
+func Verify(root string, ref Ref) (*Pack, error) {
+	pack, err := Load(Dir(root, ref.Name, ref.Version))
+	if err != nil {
+		return nil, fmt.Errorf("rule pack %s %s: %w", ref.Name, ref.Version, err)
+	}
+	if pack.Manifest.Name != ref.Name || pack.Manifest.Version != ref.Version {
+		return nil, fmt.Errorf("rule pack %s %s: manifest is for %s %s", ref.Name, ref.Version, pack.Manifest.Name, pack.Manifest.Version)
+	}
+	if !strings.EqualFold(pack.Digest, ref.SHA256) {
+		return nil, fmt.Errorf("rule pack %s %s: digest %s does not match the configured sha256 %s", ref.Name, ref.Version, pack.Digest, ref.SHA256)
+	}
+	return pack, nil
+}
+
+// Require returns the verified pack ref names under root, installing it
+// from ref.Source first when it is missing
+func Require(ctx context.Context, root string, ref Ref) (*Pack, error) {
+	_, err := os.Stat(filepath.Join(Dir(root, ref.Name, ref.Version), ManifestFile))
+	if errors.Is(err, fs.ErrNotExist) && ref.Source != "" {
+		if _, err := Install(ctx, root, ref.Source, ref.Version); err != nil {
+			return nil, err
+		}
+	}
+	return Verify(root, ref)
+}
+
+// Install fetches version of the pack at source and installs it under
+// root, returning the installed pack. The fetched manifest must declare
+// that version. Installing a version already present succeeds only when
+// the files are identical.
+func Install(ctx context.Context, root, source, version string) (*Pack, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create rule pack directory: %w", err)
+	}
+	tmp, err := os.MkdirTemp(root, ".fetch-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rule pack directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	fetched := filepath.Join(tmp, "pack")
+	if err := Fetch(ctx, source, version, fetched); err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(filepath.Join(fetched, ".git")); err != nil {
+		return nil, fmt.Errorf("failed to remove git metadata: %w", err)
+	}
+	pack, err := Load(fetched)
+	if err != nil {
+		return nil, fmt.Errorf("rule pack %s: %w", source, err)
+	}
+	if pack.Manifest.Version != version {
+		return nil, fmt.Errorf("rule pack %s: version %s declares version %s in its manifest", source, version, pack.Manifest.Version)
+	}
+
+	dir := Dir(root, pack.Manifest.Name, version)
+	if existing, err := Load(dir); err == nil {
+		if existing.Digest != pack.Digest {
+			return nil, fmt.Errorf("rule pack %s %s is already installed with different content", pack.Manifest.Name, version)
+		}
+		return existing, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create rule pack directory: %w", err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to replace rule pack %s: %w", dir, err)
+	}
+	if err := os.Rename(fetched, dir); err != nil {
+		return nil, fmt.Errorf("failed to install rule pack: %w", err)
+	}
+	pack.Dir = dir
+	return pack, nil
+}
+
+// Fetch downloads version of the pack at source into dest, pulling
+// oci:// references with oras and cloning anything else with git, where
+// version is a tag or branch
+func Fetch(ctx context.Context, source, version, dest string) error {
+	var cmd *exec.Cmd
+	if ref, ok := strings.CutPrefix(source, ociScheme); ok {
+		cmd = exec.CommandContext(ctx, "oras", "pull", "--output", dest, ref+":"+version)
+	} else {
+		cmd = exec.CommandContext(ctx, "git", "clone", "--quiet", "--depth", "1", "--branch", version, "--", source, dest)
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	}
+	cmd.Env = logging.CommandEnv(ctx, cmd.Env)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("failed to fetch rule pack %s %s: %w: %s", source, version, err, msg)
+		}
+		return fmt.Errorf("failed to fetch rule pack %s %s: %w", source, version, err)
+	}
+	return nil
+}
+
+// WasmModules returns the pack's rules as wasm modules to load, each with
+// its configuration encoded as JSON
+func (p *Pack) WasmModules() ([]codereview.WasmModule, error) {
+	modules := make([]codereview.WasmModule, 0, len(p.Manifest.Rules))
+	for _, r := range p.Manifest.Rules {
+		module := codereview.WasmModule{Path: filepath.Join(p.Dir, filepath.FromSlash(r.Wasm)), Name: r.Name}
+		if r.Config != nil {
+			config, err := json.Marshal(r.Config)
+			if err != nil {
+				return nil, fmt.Errorf("rule pack %s: rule %q has invalid config: %w", p.Manifest.Name, r.Name, err)
+			}
+			module.Config = config
+		}
+		modules = append(modules, module)
+	}
+	return modules, nil
+}
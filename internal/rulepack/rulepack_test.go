@@ -0,0 +1,203 @@
+package rulepack
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+const testManifest = `name: acme-go
+version: v1.2.0
+description: ACME review rules
+rules:
+  - name: acme-no-todo
+    wasm: rules/no-todo.wasm
+    config:
+      max: 3
+  - name: acme-no-fmt
+    wasm: rules/no-fmt.wasm
+`
+
+// writePack creates a pack with the given manifest and rule modules in a
+// new directory
+func writePack(t *testing.T, manifest string) string {
+	t.Helper()
+	files := map[string]string{
+		ManifestFile:         manifest,
+		"rules/no-todo.wasm": "\x00asm todo",
+		"rules/no-fmt.wasm":  "\x00asm fmt",
+	}
+	return testutil.WriteModule(t, files)
+}
+
+func TestLoad(t *testing.T) {
+	dir := writePack(t, testManifest)
+	pack, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if pack.Manifest.Name != "acme-go" || pack.Manifest.Version != "v1.2.0" || len(pack.Manifest.Rules) != 2 {
+		t.Errorf("unexpected manifest %+v", pack.Manifest)
+	}
+	if len(pack.Digest) != 64 {
+		t.Errorf("expected a hex sha256 digest, got %q", pack.Digest)
+	}
+
+	modules, err := pack.WasmModules()
+	if err != nil {
+		t.Fatalf("WasmModules() error = %v", err)
+	}
+	if len(modules) != 2 {
+		t.Fatalf("expected 2 modules, got %d", len(modules))
+	}
+	if modules[0].Name != "acme-no-todo" || modules[0].Path != filepath.Join(dir, "rules", "no-todo.wasm") || string(modules[0].Config) != `{"max":3}` {
+		t.Errorf("unexpected module %+v", modules[0])
+	}
+	if modules[1].Config != nil {
+		t.Errorf("expected no config for a rule without one, got %s", modules[1].Config)
+	}
+}
+
+func TestLoad_InvalidManifest(t *testing.T) {
+	tests := map[string]string{
+		"bad name":        "name: Acme Go\nversion: v1\nrules:\n  - name: r\n    wasm: r.wasm\n",
+		"path version":    "name: acme\nversion: ../v1\nrules:\n  - name: r\n    wasm: r.wasm\n",
+		"no rules":        "name: acme\nversion: v1\n",
+		"duplicate rule":  "name: acme\nversion: v1\nrules:\n  - name: r\n    wasm: a.wasm\n  - name: r\n    wasm: b.wasm\n",
+		"escaping module": "name: acme\nversion: v1\nrules:\n  - name: r\n    wasm: ../r.wasm\n",
+		"not yaml":        "name: [acme\n",
+	}
+	for name, manifest := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writePack(t, manifest)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	if _, err := Load(t.TempDir()); err == nil {
+		t.Error("expected an error for a directory without a manifest")
+	}
+}
+
+func TestDigest(t *testing.T) {
+	a := writePack(t, testManifest)
+	b := writePack(t, testManifest)
+	digestA, err := Digest(a)
+	if err != nil {
+		t.Fatalf("Digest() error = %v", err)
+	}
+	if digestB, _ := Digest(b); digestA != digestB {
+		t.Errorf("expected identical packs to have the same digest, got %s and %s", digestA, digestB)
+	}
+
+	// Git metadata is not part of the pack
+	if err := os.MkdirAll(filepath.Join(b, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(b, ".git", "HEAD"), []byte("ref"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if digestB, _ := Digest(b); digestA != digestB {
+		t.Error("expected .git to be ignored")
+	}
+
+	if err := os.WriteFile(filepath.Join(b, "rules", "no-fmt.wasm"), []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if digestB, _ := Digest(b); digestA == digestB {
+		t.Error("expected a changed module to change the digest")
+	}
+
+	if err := os.Rename(filepath.Join(a, "rules", "no-fmt.wasm"), filepath.Join(a, "rules", "other.wasm")); err != nil {
+		t.Fatal(err)
+	}
+	if renamed, _ := Digest(a); renamed == digestA {
+		t.Error("expected a renamed file to change the digest")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	root := t.TempDir()
+	src := writePack(t, testManifest)
+	dir := Dir(root, "acme-go", "v1.2.0")
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(src, dir); err != nil {
+		t.Fatal(err)
+	}
+	digest, err := Digest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref := Ref{Name: "acme-go", Version: "v1.2.0", SHA256: strings.ToUpper(digest)}
+	if _, err := Verify(root, ref); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	ref.SHA256 = strings.Repeat("0", 64)
+	if _, err := Verify(root, ref); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected a digest mismatch, got %v", err)
+	}
+
+	if _, err := Verify(root, Ref{Name: "acme-go", Version: "v2.0.0", SHA256: digest}); err == nil {
+		t.Error("expected an error for a version that is not installed")
+	}
+}
+
+func TestInstall_Git(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := writePack(t, testManifest)
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "rules"},
+		{"tag", "v1.2.0"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	want, err := Digest(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	pack, err := Install(context.Background(), root, repo, "v1.2.0")
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if pack.Dir != Dir(root, "acme-go", "v1.2.0") || pack.Digest != want {
+		t.Errorf("unexpected pack %s with digest %s, want digest %s", pack.Dir, pack.Digest, want)
+	}
+	if _, err := os.Stat(filepath.Join(pack.Dir, ".git")); !os.IsNotExist(err) {
+		t.Error("expected git metadata to be removed")
+	}
+
+	// Installing the same version again is a no-op
+	if _, err := Install(context.Background(), root, repo, "v1.2.0"); err != nil {
+		t.Errorf("reinstall error = %v", err)
+	}
+	if _, err := Install(context.Background(), root, repo, "v9.9.9"); err == nil {
+		t.Error("expected an error for a missing tag")
+	}
+
+	// Require installs a missing pack from its source
+	other := t.TempDir()
+	if _, err := Require(context.Background(), other, Ref{Name: "acme-go", Version: "v1.2.0", Source: repo, SHA256: want}); err != nil {
+		t.Errorf("Require() error = %v", err)
+	}
+}
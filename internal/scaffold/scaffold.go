@@ -0,0 +1,310 @@
+package scaffold
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"mcp-go-assistant/internal/buildgen"
+	"mcp-go-assistant/internal/gocheck"
+)
+
+// Scaffold limits and defaults
+const (
+	DefaultGoVersion = "1.23"
+	MaxPackages      = 20
+)
+
+// Placeholders recognized in template file paths
+const (
+	namePlaceholder    = "_name_"
+	packagePlaceholder = "_package_"
+	dotPrefix          = "dot_"
+	templateSuffix     = ".tmpl"
+)
+
+//go:embed all:templates
+var defaultTemplates embed.FS
+
+var (
+	modulePathRegex  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._~-]*(/[A-Za-z0-9._~-]+)*$`)
+	nameRegex        = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+	packageNameRegex = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+	goVersionRegex   = regexp.MustCompile(`^1\.[0-9]+(\.[0-9]+)?$`)
+	envPrefixRegex   = regexp.MustCompile(`[^A-Z0-9]+`)
+)
+
+// reservedPackages are generated by the default templates
+var reservedPackages = []string{"config", "logging"}
+
+// Options controls template selection for scaffold generation
+type Options struct {
+	TemplateDir string // Optional directory whose templates override or extend the built-in set
+	GoVersion   string // Default go directive when params.GoVersion is empty
+}
+
+// templateData is the data passed to every template
+type templateData struct {
+	ModulePath string
+	Name       string
+	GoVersion  string
+	EnvPrefix  string
+	Packages   []string
+	Package    string // Set for templates whose path contains _package_
+}
+
+// Generate renders a new Go project layout as a map of file paths to contents
+func Generate(ctx context.Context, params ScaffoldParams, opts Options) (*ScaffoldResult, error) {
+	data, err := newTemplateData(params, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := loadTemplates(opts.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string)
+	for tmplPath, content := range templates {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if !strings.Contains(tmplPath, packagePlaceholder) {
+			if err := renderFile(files, tmplPath, content, data); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		for _, pkg := range data.Packages {
+			pkgData := data
+			pkgData.Package = pkg
+			if err := renderFile(files, tmplPath, content, pkgData); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// The Makefile comes from the generate-makefile renderer unless a
+	// template provides one
+	if _, ok := files["Makefile"]; !ok {
+		files["Makefile"] = buildgen.RenderMakefile(&buildgen.ProjectLayout{
+			ModulePath: data.ModulePath,
+			GoVersion:  data.GoVersion,
+			Binaries:   []buildgen.Binary{{Name: data.Name, Path: "./cmd/" + data.Name}},
+			HasTests:   true,
+		})
+	}
+
+	result := &ScaffoldResult{
+		ModulePath: data.ModulePath,
+		Name:       data.Name,
+		Files:      files,
+		NextSteps: []string{
+			"Write the files to a new directory",
+			"Run `go mod tidy` to resolve dependencies and create go.sum",
+			fmt.Sprintf("Run `make build` and `./bin/%s -version`", data.Name),
+		},
+	}
+	verifyFiles(ctx, result)
+	return result, nil
+}
+
+// verifyFiles type-checks the generated Go files and records whether they
+// compile. The result is left unknown when dependencies of the project
+// cannot be resolved.
+func verifyFiles(ctx context.Context, result *ScaffoldResult) {
+	pkgs := make(map[string]*gocheck.Package)
+	var paths []string
+	for name, content := range result.Files {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), name, content, parser.PackageClauseOnly)
+		if err != nil {
+			// Reported with the other diagnostics of the package
+			file = &ast.File{Name: ast.NewIdent("")}
+		}
+
+		pkgPath := path.Join(result.ModulePath, path.Dir(name))
+		if strings.HasSuffix(file.Name.Name, "_test") {
+			pkgPath += "_test"
+		}
+		if pkgs[pkgPath] == nil {
+			pkgs[pkgPath] = &gocheck.Package{Path: pkgPath, Files: make(map[string]string)}
+			paths = append(paths, pkgPath)
+		}
+		pkgs[pkgPath].Files[name] = content
+	}
+	if len(paths) == 0 {
+		return
+	}
+
+	sort.Strings(paths)
+	checkPkgs := make([]gocheck.Package, 0, len(paths))
+	for _, p := range paths {
+		checkPkgs = append(checkPkgs, *pkgs[p])
+	}
+
+	check := gocheck.Check(ctx, checkPkgs)
+	if len(check.Unresolved) > 0 {
+		return
+	}
+	compiles := len(check.Diagnostics) == 0
+	result.Compiles = &compiles
+	result.Diagnostics = check.Diagnostics
+}
+
+// newTemplateData validates params and fills in defaults
+func newTemplateData(params ScaffoldParams, opts Options) (templateData, error) {
+	if params.ModulePath == "" {
+		return templateData{}, fmt.Errorf("module_path parameter is required")
+	}
+	if !modulePathRegex.MatchString(params.ModulePath) || strings.Contains(params.ModulePath, "..") {
+		return templateData{}, fmt.Errorf("invalid module path: %s", params.ModulePath)
+	}
+
+	name := params.Name
+	if name == "" {
+		name = strings.ToLower(path.Base(params.ModulePath))
+	}
+	if !nameRegex.MatchString(name) {
+		return templateData{}, fmt.Errorf("invalid name: %s (use lowercase letters, digits, '-' or '_')", name)
+	}
+
+	goVersion := params.GoVersion
+	if goVersion == "" {
+		goVersion = opts.GoVersion
+	}
+	if goVersion == "" {
+		goVersion = DefaultGoVersion
+	}
+	if !goVersionRegex.MatchString(goVersion) {
+		return templateData{}, fmt.Errorf("invalid go version: %s", goVersion)
+	}
+
+	if len(params.Packages) > MaxPackages {
+		return templateData{}, fmt.Errorf("too many packages: %d (max %d)", len(params.Packages), MaxPackages)
+	}
+	seen := make(map[string]bool)
+	for _, pkg := range params.Packages {
+		if !packageNameRegex.MatchString(pkg) {
+			return templateData{}, fmt.Errorf("invalid package name: %s", pkg)
+		}
+		for _, reserved := range reservedPackages {
+			if pkg == reserved {
+				return templateData{}, fmt.Errorf("package %s is generated by default", pkg)
+			}
+		}
+		if seen[pkg] {
+			return templateData{}, fmt.Errorf("duplicate package: %s", pkg)
+		}
+		seen[pkg] = true
+	}
+
+	return templateData{
+		ModulePath: params.ModulePath,
+		Name:       name,
+		GoVersion:  goVersion,
+		EnvPrefix:  strings.Trim(envPrefixRegex.ReplaceAllString(strings.ToUpper(name), "_"), "_"),
+		Packages:   params.Packages,
+	}, nil
+}
+
+// loadTemplates returns the built-in templates keyed by relative path,
+// overridden or extended by templates found in templateDir
+func loadTemplates(templateDir string) (map[string]string, error) {
+	builtin, err := fs.Sub(defaultTemplates, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load built-in templates: %v", err)
+	}
+
+	templates := make(map[string]string)
+	if err := readTemplates(builtin, templates); err != nil {
+		return nil, fmt.Errorf("failed to load built-in templates: %v", err)
+	}
+
+	if templateDir != "" {
+		info, err := os.Stat(templateDir)
+		if err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("template directory not found: %s", templateDir)
+		}
+		if err := readTemplates(os.DirFS(templateDir), templates); err != nil {
+			return nil, fmt.Errorf("failed to load templates from %s: %v", templateDir, err)
+		}
+	}
+
+	return templates, nil
+}
+
+// readTemplates adds every file in fsys to templates, keyed by path with
+// the .tmpl suffix removed
+func readTemplates(fsys fs.FS, templates map[string]string) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		templates[strings.TrimSuffix(p, templateSuffix)] = string(content)
+		return nil
+	})
+}
+
+// renderFile expands the template path and content and stores the result
+// in files. Go sources are formatted so template whitespace does not leak.
+func renderFile(files map[string]string, tmplPath, content string, data templateData) error {
+	outPath := expandPath(tmplPath, data)
+
+	tmpl, err := template.New(tmplPath).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return fmt.Errorf("invalid template %s: %v", tmplPath, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to render %s: %v", tmplPath, err)
+	}
+
+	output := buf.Bytes()
+	if strings.HasSuffix(outPath, ".go") {
+		formatted, err := format.Source(output)
+		if err != nil {
+			return fmt.Errorf("template %s produced invalid Go code: %v", tmplPath, err)
+		}
+		output = formatted
+	}
+
+	files[outPath] = string(output)
+	return nil
+}
+
+// expandPath replaces path placeholders and dot_ prefixes in a template path
+func expandPath(tmplPath string, data templateData) string {
+	parts := strings.Split(tmplPath, "/")
+	for i, part := range parts {
+		part = strings.ReplaceAll(part, namePlaceholder, data.Name)
+		part = strings.ReplaceAll(part, packagePlaceholder, data.Package)
+		if strings.HasPrefix(part, dotPrefix) {
+			part = "." + strings.TrimPrefix(part, dotPrefix)
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, "/")
+}
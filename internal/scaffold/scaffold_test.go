@@ -0,0 +1,194 @@
+package scaffold
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+func TestGenerate(t *testing.T) {
+	result, err := Generate(context.Background(), ScaffoldParams{
+		ModulePath: "github.com/acme/widget-server",
+		Packages:   []string{"store", "api"},
+	}, Options{})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if result.Name != "widget-server" {
+		t.Errorf("expected name widget-server, got %s", result.Name)
+	}
+
+	wantFiles := []string{
+		"go.mod",
+		".gitignore",
+		"Makefile",
+		"README.md",
+		"config.example.yaml",
+		"cmd/widget-server/main.go",
+		"internal/config/config.go",
+		"internal/config/config_test.go",
+		"internal/logging/logging.go",
+		"internal/store/store.go",
+		"internal/store/store_test.go",
+		"internal/api/api.go",
+	}
+	for _, f := range wantFiles {
+		if _, ok := result.Files[f]; !ok {
+			t.Errorf("expected file %s to be generated", f)
+		}
+	}
+
+	if !strings.HasPrefix(result.Files["go.mod"], "module github.com/acme/widget-server\n\ngo 1.23\n") {
+		t.Errorf("unexpected go.mod:\n%s", result.Files["go.mod"])
+	}
+	if !strings.Contains(result.Files["cmd/widget-server/main.go"], `"github.com/acme/widget-server/internal/config"`) {
+		t.Error("expected main.go to import the generated config package")
+	}
+	if !strings.Contains(result.Files["internal/config/config.go"], `"WIDGET_SERVER_LOG_LEVEL"`) {
+		t.Error("expected env prefix derived from the name")
+	}
+	if !strings.Contains(result.Files["Makefile"], "-o $(BIN_DIR)/widget-server ./cmd/widget-server") {
+		t.Errorf("unexpected Makefile:\n%s", result.Files["Makefile"])
+	}
+	if !strings.HasPrefix(result.Files["internal/api/api.go"], "// Package api") {
+		t.Errorf("unexpected package file:\n%s", result.Files["internal/api/api.go"])
+	}
+}
+
+func TestGenerate_Compiles(t *testing.T) {
+	result, err := Generate(context.Background(), ScaffoldParams{
+		ModulePath: "github.com/acme/widget-server",
+		Packages:   []string{"store"},
+	}, Options{})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	// Compiles is unknown when zerolog and viper are missing from the build cache
+	if result.Compiles != nil && (!*result.Compiles || len(result.Diagnostics) > 0) {
+		t.Errorf("expected the built-in templates to compile, got %v", result.Diagnostics)
+	}
+
+	dir := t.TempDir()
+	broken := "package {{.Package}}\n\nfunc Broken() int { return \"\" }\n"
+	if err := os.MkdirAll(filepath.Join(dir, "internal", "_package_"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "internal", "_package_", "broken.go.tmpl"), []byte(broken), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = Generate(context.Background(), ScaffoldParams{
+		ModulePath: "example.com/tool",
+		Packages:   []string{"core"},
+	}, Options{TemplateDir: dir})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if result.Compiles == nil || *result.Compiles {
+		t.Fatalf("expected the broken template not to compile, got compiles=%v", result.Compiles)
+	}
+	found := false
+	for _, d := range result.Diagnostics {
+		found = found || (d.File == "internal/core/broken.go" && d.Line == 3)
+	}
+	if !found {
+		t.Errorf("expected a diagnostic in internal/core/broken.go, got %v", result.Diagnostics)
+	}
+}
+
+func TestGenerate_TemplateDir(t *testing.T) {
+	files := map[string]string{
+		"README.md.tmpl":         "# {{.Name}} (custom)\n",
+		"dot_editorconfig":       "root = true\n",
+		"internal/_package_/doc": "{{.Package}} of {{.ModulePath}}\n",
+	}
+	dir := testutil.WriteModule(t, files)
+
+	result, err := Generate(context.Background(), ScaffoldParams{
+		ModulePath: "example.com/tool",
+		Packages:   []string{"core"},
+	}, Options{TemplateDir: dir, GoVersion: "1.22"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if got := result.Files["README.md"]; got != "# tool (custom)\n" {
+		t.Errorf("expected README override, got %q", got)
+	}
+	if got := result.Files[".editorconfig"]; got != "root = true\n" {
+		t.Errorf("expected .editorconfig, got %q", got)
+	}
+	if got := result.Files["internal/core/doc"]; got != "core of example.com/tool\n" {
+		t.Errorf("expected per-package template, got %q", got)
+	}
+	if !strings.Contains(result.Files["go.mod"], "go 1.22\n") {
+		t.Errorf("expected go version from options, got:\n%s", result.Files["go.mod"])
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		params ScaffoldParams
+		opts   Options
+	}{
+		{name: "missing module path", params: ScaffoldParams{}},
+		{name: "invalid module path", params: ScaffoldParams{ModulePath: "bad path"}},
+		{name: "invalid name", params: ScaffoldParams{ModulePath: "example.com/x", Name: "Bad/Name"}},
+		{name: "invalid package", params: ScaffoldParams{ModulePath: "example.com/x", Packages: []string{"my-pkg"}}},
+		{name: "reserved package", params: ScaffoldParams{ModulePath: "example.com/x", Packages: []string{"config"}}},
+		{name: "duplicate package", params: ScaffoldParams{ModulePath: "example.com/x", Packages: []string{"a", "a"}}},
+		{name: "invalid go version", params: ScaffoldParams{ModulePath: "example.com/x", GoVersion: "2"}},
+		{name: "missing template dir", params: ScaffoldParams{ModulePath: "example.com/x"}, opts: Options{TemplateDir: "/nonexistent/templates"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Generate(context.Background(), tt.params, tt.opts); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestGenerate_Builds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping build of generated project in short mode")
+	}
+
+	result, err := Generate(context.Background(), ScaffoldParams{
+		ModulePath: "example.com/scaffolded",
+		Packages:   []string{"worker"},
+	}, Options{})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	dir := testutil.WriteModule(t, result.Files)
+
+	// Resolve dependencies from the local module cache only
+	env := append(os.Environ(), "GOPROXY=off", "GOFLAGS=-mod=mod")
+	if out, err := runIn(dir, env, "go", "mod", "tidy"); err != nil {
+		t.Skipf("dependencies unavailable offline: %s", out)
+	}
+	if out, err := runIn(dir, env, "go", "vet", "./..."); err != nil {
+		t.Fatalf("generated project does not vet: %v\n%s", err, out)
+	}
+	if out, err := runIn(dir, env, "go", "test", "./..."); err != nil {
+		t.Fatalf("generated project tests fail: %v\n%s", err, out)
+	}
+}
+
+func runIn(dir string, env []string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
@@ -0,0 +1,33 @@
+package scaffold
+
+import (
+	"encoding/json"
+
+	"mcp-go-assistant/internal/gocheck"
+)
+
+// ScaffoldParams represents the parameters for the scaffold tool
+type ScaffoldParams struct {
+	ModulePath     string   `json:"module_path" jsonschema:"description:Module path of the new project, e.g. github.com/acme/tool"`
+	Name           string   `json:"name,omitempty" jsonschema:"description:Optional binary name used for cmd/<name> (defaults to the last element of the module path)"`
+	Packages       []string `json:"packages,omitempty" jsonschema:"description:Optional internal packages to create under internal/"`
+	GoVersion      string   `json:"go_version,omitempty" jsonschema:"description:Optional go directive for go.mod (defaults to server setting)"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// ScaffoldResult represents a generated project layout
+type ScaffoldResult struct {
+	ModulePath string            `json:"module_path"`
+	Name       string            `json:"name"`
+	Files      map[string]string `json:"files"` // Relative file path to file contents
+	NextSteps  []string          `json:"next_steps"`
+
+	Compiles    *bool                `json:"compiles,omitempty"`    // Whether the generated Go files type-check; unset when dependencies could not be resolved
+	Diagnostics []gocheck.Diagnostic `json:"diagnostics,omitempty"` // Compilation errors in the generated Go files
+}
+
+// String returns a formatted JSON string of the ScaffoldResult
+func (r *ScaffoldResult) String() string {
+	jsonData, _ := json.MarshalIndent(r, "", "  ")
+	return string(jsonData)
+}
@@ -0,0 +1,614 @@
+// Package schemagen describes Go types as JSON Schema or OpenAPI component
+// schemas of their encoding/json representation.
+package schemagen
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"mcp-go-assistant/internal/gocheck"
+)
+
+const (
+	// dialect is the JSON Schema version of generated documents
+	dialect = "https://json-schema.org/draft/2020-12/schema"
+	// pastedPath is the import path go_code is checked as
+	pastedPath = "pasted"
+	// pastedFile is the file name go_code is checked as
+	pastedFile = "input.go"
+)
+
+// Generate type-checks go_code and returns the schemas of the requested
+// types and of every type they refer to
+func Generate(ctx context.Context, params SchemaParams) (*SchemaResult, error) {
+	if strings.TrimSpace(params.GoCode) == "" {
+		return nil, fmt.Errorf("go_code parameter is required")
+	}
+	format := strings.ToLower(params.Format)
+	switch format {
+	case "":
+		format = "jsonschema"
+	case "jsonschema", "openapi":
+	default:
+		return nil, fmt.Errorf("format must be one of: jsonschema, openapi (got: %s)", params.Format)
+	}
+
+	code := params.GoCode
+	if _, err := parser.ParseFile(token.NewFileSet(), "", code, parser.PackageClauseOnly); err != nil {
+		code = "package models\n\n" + code
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), pastedFile, code, 0); err != nil {
+		return nil, fmt.Errorf("failed to parse go_code: %w", err)
+	}
+	checked := gocheck.Check(ctx, []gocheck.Package{{Path: pastedPath, Files: map[string]string{pastedFile: code}}})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	pkg := checked.Types[pastedPath]
+	if pkg == nil {
+		return nil, fmt.Errorf("failed to check go_code")
+	}
+
+	roots, err := rootTypes(pkg, params.Types)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		pkg:     pkg,
+		docs:    docComments(checked.Syntax[pastedPath]),
+		enums:   enumValues(pkg),
+		defs:    make(map[string]*Schema),
+		names:   make(map[string]string),
+		pending: make(map[string]*types.Named),
+		prefix:  "#/$defs/",
+	}
+	if format == "openapi" {
+		g.prefix = "#/components/schemas/"
+	}
+	// Requested types are named first so they lead the list
+	for _, named := range roots {
+		g.name(named)
+	}
+	for _, named := range roots {
+		g.define(named)
+	}
+
+	result := &SchemaResult{
+		Format:      format,
+		Types:       g.order,
+		Document:    &Document{},
+		Suggestions: []string{},
+	}
+	if format == "openapi" {
+		result.Document.Components = &Components{Schemas: g.defs}
+	} else {
+		result.Document.Schema = dialect
+		result.Document.Defs = g.defs
+		if len(roots) == 1 {
+			result.Document.Ref = g.prefix + g.names[types.TypeString(roots[0], nil)]
+		}
+	}
+
+	result.Suggestions = append(result.Suggestions, g.suggestions...)
+	if g.required {
+		result.Suggestions = append(result.Suggestions,
+			"Fields without omitempty are required, as encoding/json always writes them; add omitempty to fields that may be left out.")
+	}
+	if len(checked.Unresolved) > 0 {
+		result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+			"Imports could not be resolved: %s; fields of their types are described by empty schemas.", strings.Join(checked.Unresolved, ", ")))
+	} else if n := len(checked.Diagnostics); n > 0 {
+		result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+			"go_code has %d type errors, e.g. %s; fields with invalid types are described by empty schemas.", n, checked.Diagnostics[0]))
+	}
+	result.Summary = fmt.Sprintf("%d schemas for %d requested types as %s", len(g.order), len(roots), format)
+	return result, nil
+}
+
+// rootTypes returns the named types to describe: those requested, or every
+// exported non-generic struct type in declaration order
+func rootTypes(pkg *types.Package, requested []string) ([]*types.Named, error) {
+	var roots []*types.Named
+	if len(requested) > 0 {
+		for _, name := range requested {
+			tn, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+			if !ok {
+				return nil, fmt.Errorf("type %s is not declared in go_code", name)
+			}
+			named, ok := tn.Type().(*types.Named)
+			if !ok {
+				return nil, fmt.Errorf("type %s is an alias; name the aliased type instead", name)
+			}
+			if named.TypeParams().Len() > 0 {
+				return nil, fmt.Errorf("type %s is generic; declare a struct with a field of an instantiation of it, e.g. %s[string]", name, name)
+			}
+			roots = append(roots, named)
+		}
+		return roots, nil
+	}
+
+	for _, name := range pkg.Scope().Names() {
+		tn, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok || !tn.Exported() || tn.IsAlias() {
+			continue
+		}
+		named, ok := tn.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue
+		}
+		if _, ok := named.Underlying().(*types.Struct); ok {
+			roots = append(roots, named)
+		}
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("go_code declares no exported struct types; name the types to describe with types")
+	}
+	sort.Slice(roots, func(i, j int) bool { return roots[i].Obj().Pos() < roots[j].Obj().Pos() })
+	return roots, nil
+}
+
+// generator builds the schemas of named types as definitions referred to
+// by $ref
+type generator struct {
+	pkg     *types.Package
+	docs    map[token.Pos]string      // Doc comments by the position of the declared name
+	enums   map[*types.TypeName][]any // Values of the constants of local types
+	defs    map[string]*Schema        // Definitions by schema name
+	names   map[string]string         // Schema names by type string
+	pending map[string]*types.Named   // Named types with a name but no definition yet
+	order   []string                  // Schema names in the order they were named
+	prefix  string                    // Prefix of $ref values
+	seen    map[string]bool           // Suggestions already made
+	unsup   map[*types.Var]bool       // Fields already reported as unsupported
+
+	required    bool // Set once a property is listed as required
+	suggestions []string
+}
+
+// suggest adds a suggestion once
+func (g *generator) suggest(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if g.seen == nil {
+		g.seen = make(map[string]bool)
+	}
+	if !g.seen[msg] {
+		g.seen[msg] = true
+		g.suggestions = append(g.suggestions, msg)
+	}
+}
+
+// name returns the schema name of named, reserving a unique one the first
+// time
+func (g *generator) name(named *types.Named) string {
+	key := types.TypeString(named, nil)
+	if name, ok := g.names[key]; ok {
+		return name
+	}
+
+	obj := named.Obj()
+	base := obj.Name()
+	if obj.Pkg() != nil && obj.Pkg() != g.pkg {
+		base = obj.Pkg().Name() + "." + base
+	}
+	if args := named.TypeArgs(); args.Len() > 0 {
+		for i := 0; i < args.Len(); i++ {
+			base += "_" + sanitize(types.TypeString(args.At(i), func(*types.Package) string { return "" }))
+		}
+	}
+	name := base
+	for i := 2; g.taken(name); i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+
+	g.names[key] = name
+	g.pending[name] = named
+	g.order = append(g.order, name)
+	return name
+}
+
+// taken reports whether a schema name is in use
+func (g *generator) taken(name string) bool {
+	for _, used := range g.names {
+		if used == name {
+			return true
+		}
+	}
+	return false
+}
+
+// define names named and builds its definition unless it exists
+func (g *generator) define(named *types.Named) string {
+	name := g.name(named)
+	if _, ok := g.pending[name]; !ok {
+		return name
+	}
+	delete(g.pending, name)
+
+	s := g.schema(named.Underlying())
+	if s == nil {
+		s = &Schema{}
+	}
+	if values := g.enums[named.Obj()]; len(values) > 0 {
+		s.Enum = values
+	}
+	if doc := g.docs[named.Obj().Pos()]; doc != "" {
+		s.Description = doc
+	}
+	g.defs[name] = s
+	return name
+}
+
+// schema returns the schema of values of t, or nil when encoding/json
+// cannot encode them
+func (g *generator) schema(t types.Type) *Schema {
+	switch t := types.Unalias(t).(type) {
+	case *types.Named:
+		if s, ok := g.wellKnown(t); ok {
+			return s
+		}
+		return &Schema{Ref: g.prefix + g.define(t)}
+	case *types.Pointer:
+		return g.schema(t.Elem())
+	case *types.Basic:
+		return basic(t)
+	case *types.Slice:
+		if b, ok := types.Unalias(t.Elem()).(*types.Basic); ok && b.Kind() == types.Byte {
+			// encoding/json writes byte slices as base64 strings
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		return g.array(t.Elem())
+	case *types.Array:
+		return g.array(t.Elem())
+	case *types.Map:
+		elem := g.schema(t.Elem())
+		if elem == nil {
+			return nil
+		}
+		return &Schema{Type: "object", AdditionalProperties: elem}
+	case *types.Struct:
+		return g.object(t)
+	case *types.Interface, *types.TypeParam:
+		// Any value
+		return &Schema{}
+	}
+	return nil
+}
+
+// array returns the schema of arrays of elem
+func (g *generator) array(elem types.Type) *Schema {
+	items := g.schema(elem)
+	if items == nil {
+		return nil
+	}
+	return &Schema{Type: "array", Items: items}
+}
+
+// wellKnown returns the schema of named types with a known or custom
+// encoding, which are described inline
+func (g *generator) wellKnown(named *types.Named) (*Schema, bool) {
+	obj := named.Obj()
+	if obj.Pkg() != nil {
+		switch obj.Pkg().Path() + "." + obj.Name() {
+		case "time.Time":
+			return &Schema{Type: "string", Format: "date-time"}, true
+		case "time.Duration":
+			return &Schema{Type: "integer", Format: "int64", Description: "Duration in nanoseconds"}, true
+		case "encoding/json.RawMessage":
+			return &Schema{}, true
+		case "encoding/json.Number":
+			return &Schema{Type: "number"}, true
+		}
+	}
+	if named.Underlying() == types.Typ[types.Invalid] {
+		return &Schema{}, true
+	}
+
+	switch {
+	case hasMethod(named, "MarshalJSON"):
+		g.suggest("%s has a MarshalJSON method, so its schema is empty; describe its custom encoding by hand.", obj.Name())
+		return &Schema{Description: fmt.Sprintf("Encoded by the MarshalJSON method of %s", obj.Name())}, true
+	case hasMethod(named, "MarshalText"):
+		return &Schema{Type: "string", Description: fmt.Sprintf("Encoded by the MarshalText method of %s", obj.Name())}, true
+	}
+	return nil, false
+}
+
+// object returns the schema of a struct, with the fields of embedded
+// structs promoted as encoding/json does
+func (g *generator) object(st *types.Struct) *Schema {
+	s := &Schema{Type: "object"}
+	for _, f := range dominantFields(collectFields(st, 0, make(map[*types.Struct]bool))) {
+		prop := g.schema(f.v.Type())
+		if prop == nil {
+			if g.unsup == nil {
+				g.unsup = make(map[*types.Var]bool)
+			}
+			if !g.unsup[f.v] {
+				g.unsup[f.v] = true
+				g.suggest("Field %s has type %s, which encoding/json cannot encode; tag it json:\"-\".",
+					f.v.Name(), types.TypeString(f.v.Type(), (*types.Package).Name))
+			}
+			continue
+		}
+		if f.asString && isScalar(f.v.Type()) {
+			prop = &Schema{Type: "string"}
+		}
+		if _, ptr := types.Unalias(f.v.Type()).(*types.Pointer); ptr && !f.omitempty {
+			prop = nullable(prop)
+		}
+		if doc := g.docs[f.v.Pos()]; doc != "" {
+			prop.Description = doc
+		}
+
+		if s.Properties == nil {
+			s.Properties = make(map[string]*Schema)
+		}
+		s.Properties[f.name] = prop
+		if !f.omitempty {
+			s.Required = append(s.Required, f.name)
+			g.required = true
+		}
+	}
+	return s
+}
+
+// jsonField is a struct field as encoding/json sees it
+type jsonField struct {
+	name      string
+	v         *types.Var
+	tagged    bool // Named by its json tag
+	omitempty bool
+	asString  bool // The ",string" option
+	depth     int  // Embedding depth
+}
+
+// collectFields returns the encoded fields of st and of the structs it
+// embeds without a json name, in field order
+func collectFields(st *types.Struct, depth int, visiting map[*types.Struct]bool) []jsonField {
+	visiting[st] = true
+	defer delete(visiting, st)
+
+	var fields []jsonField
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i)).Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if v.Embedded() && name == "" {
+			t := types.Unalias(v.Type())
+			if ptr, ok := t.(*types.Pointer); ok {
+				t = ptr.Elem()
+			}
+			if inner, ok := t.Underlying().(*types.Struct); ok {
+				if !visiting[inner] {
+					fields = append(fields, collectFields(inner, depth+1, visiting)...)
+				}
+				continue
+			}
+		}
+		if !v.Exported() {
+			continue
+		}
+
+		f := jsonField{name: name, v: v, tagged: name != "", depth: depth}
+		if name == "" {
+			f.name = v.Name()
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty", "omitzero":
+				f.omitempty = true
+			case "string":
+				f.asString = true
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// dominantFields resolves fields with the same name as encoding/json does:
+// the shallowest wins, then the only tagged one; others are dropped
+func dominantFields(fields []jsonField) []jsonField {
+	byName := make(map[string][]jsonField)
+	var order []string
+	for _, f := range fields {
+		if _, ok := byName[f.name]; !ok {
+			order = append(order, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	var out []jsonField
+	for _, name := range order {
+		candidates := byName[name]
+		minDepth := candidates[0].depth
+		for _, f := range candidates {
+			minDepth = min(minDepth, f.depth)
+		}
+		var shallow, tagged []jsonField
+		for _, f := range candidates {
+			if f.depth == minDepth {
+				shallow = append(shallow, f)
+				if f.tagged {
+					tagged = append(tagged, f)
+				}
+			}
+		}
+		switch {
+		case len(shallow) == 1:
+			out = append(out, shallow[0])
+		case len(tagged) == 1:
+			out = append(out, tagged[0])
+		}
+	}
+	return out
+}
+
+// basic returns the schema of a basic type, or nil for complex numbers
+// and unsafe pointers
+func basic(t *types.Basic) *Schema {
+	zero := 0
+	switch t.Kind() {
+	case types.Bool, types.UntypedBool:
+		return &Schema{Type: "boolean"}
+	case types.Int, types.Int64, types.UntypedInt, types.UntypedRune:
+		return &Schema{Type: "integer", Format: "int64"}
+	case types.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case types.Int8, types.Int16:
+		return &Schema{Type: "integer"}
+	case types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64, types.Uintptr:
+		return &Schema{Type: "integer", Minimum: &zero}
+	case types.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case types.Float64, types.UntypedFloat:
+		return &Schema{Type: "number", Format: "double"}
+	case types.String, types.UntypedString:
+		return &Schema{Type: "string"}
+	case types.Invalid:
+		return &Schema{}
+	}
+	return nil
+}
+
+// nullable returns s allowing null as well
+func nullable(s *Schema) *Schema {
+	switch typ := s.Type.(type) {
+	case string:
+		if s.Ref == "" && len(s.Enum) == 0 {
+			s.Type = []string{typ, "null"}
+			return s
+		}
+	case nil:
+		if s.Ref == "" && len(s.Properties) == 0 {
+			// Empty schemas allow null already
+			return s
+		}
+	}
+	return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+}
+
+// isScalar reports whether the ",string" option applies to values of t
+func isScalar(t types.Type) bool {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) != 0
+}
+
+// hasMethod reports whether values of named or pointers to them have the
+// method name
+func hasMethod(named *types.Named, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), false, named.Obj().Pkg(), name)
+	_, ok := obj.(*types.Func)
+	return ok
+}
+
+// enumValues returns the values of the constants declared with each local
+// named type, in declaration order
+func enumValues(pkg *types.Package) map[*types.TypeName][]any {
+	var consts []*types.Const
+	for _, name := range pkg.Scope().Names() {
+		c, ok := pkg.Scope().Lookup(name).(*types.Const)
+		if !ok {
+			continue
+		}
+		if named, ok := c.Type().(*types.Named); ok && named.Obj().Pkg() == pkg {
+			consts = append(consts, c)
+		}
+	}
+	sort.Slice(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+
+	enums := make(map[*types.TypeName][]any)
+	for _, c := range consts {
+		obj := c.Type().(*types.Named).Obj()
+		if value := constantValue(c.Val()); value != nil {
+			enums[obj] = append(enums[obj], value)
+		}
+	}
+	return enums
+}
+
+// constantValue converts a constant to its JSON value
+func constantValue(v constant.Value) any {
+	switch v.Kind() {
+	case constant.String:
+		return constant.StringVal(v)
+	case constant.Bool:
+		return constant.BoolVal(v)
+	case constant.Int:
+		if i, ok := constant.Int64Val(v); ok {
+			return i
+		}
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return f
+	}
+	return nil
+}
+
+// docComments returns the doc or line comments of type declarations and
+// struct fields by the position of the declared name
+func docComments(files []*ast.File) map[token.Pos]string {
+	docs := make(map[token.Pos]string)
+	text := func(groups ...*ast.CommentGroup) string {
+		for _, cg := range groups {
+			if cg != nil {
+				return strings.Join(strings.Fields(cg.Text()), " ")
+			}
+		}
+		return ""
+	}
+
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.GenDecl:
+				for _, spec := range node.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						var declDoc *ast.CommentGroup
+						if len(node.Specs) == 1 {
+							declDoc = node.Doc
+						}
+						if doc := text(ts.Doc, declDoc, ts.Comment); doc != "" {
+							docs[ts.Name.Pos()] = doc
+						}
+					}
+				}
+			case *ast.Field:
+				if doc := text(node.Doc, node.Comment); doc != "" {
+					for _, name := range node.Names {
+						docs[name.Pos()] = doc
+					}
+				}
+			}
+			return true
+		})
+	}
+	return docs
+}
+
+// sanitize replaces the characters of a type string that schema names
+// cannot contain
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '.' || r == '-' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return '_'
+	}, s)
+}
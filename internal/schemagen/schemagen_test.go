@@ -0,0 +1,208 @@
+package schemagen
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const models = `package models
+
+import "time"
+
+// Status is the state of an order
+type Status string
+
+const (
+	StatusOpen   Status = "open"
+	StatusClosed Status = "closed"
+)
+
+type Base struct {
+	ID      int64     ` + "`json:\"id\"`" + `
+	Created time.Time ` + "`json:\"created_at\"`" + `
+}
+
+// Order is a customer order
+type Order struct {
+	Base
+	Status   Status            ` + "`json:\"status\"`" + `
+	Items    []Item            ` + "`json:\"items\"`" + `
+	Note     *string           ` + "`json:\"note\"`" + `
+	Coupon   *Item             ` + "`json:\"coupon,omitempty\"`" + `
+	Labels   map[string]string ` + "`json:\"labels,omitempty\"`" + `
+	Total    float64           ` + "`json:\"total,string\"`" + `
+	Secret   string            ` + "`json:\"-\"`" + `
+	internal int
+}
+
+type Item struct {
+	SKU      string // Stock keeping unit
+	Quantity uint32 ` + "`json:\"qty\"`" + `
+	Raw      []byte ` + "`json:\"raw,omitempty\"`" + `
+}
+`
+
+// marshal returns the JSON of a schema for comparison
+func marshal(t *testing.T, s *Schema) string {
+	t.Helper()
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestGenerate(t *testing.T) {
+	result, err := Generate(context.Background(), SchemaParams{GoCode: models})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if got := strings.Join(result.Types, ","); got != "Base,Order,Item,Status" {
+		t.Errorf("unexpected types %s", got)
+	}
+	doc := result.Document
+	if doc.Schema != dialect || doc.Ref != "" || doc.Components != nil {
+		t.Errorf("unexpected document header %+v", doc)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Status", `{"type":"string","description":"Status is the state of an order","enum":["open","closed"]}`},
+		{"Base", `{"type":"object","properties":{"created_at":{"type":"string","format":"date-time"},"id":{"type":"integer","format":"int64"}},"required":["id","created_at"]}`},
+		{"Item", `{"type":"object","properties":{"SKU":{"type":"string","description":"Stock keeping unit"},"qty":{"type":"integer","minimum":0},"raw":{"type":"string","contentEncoding":"base64"}},"required":["SKU","qty"]}`},
+		{"Order", `{"type":"object","description":"Order is a customer order",` +
+			`"properties":{"coupon":{"$ref":"#/$defs/Item"},"created_at":{"type":"string","format":"date-time"},"id":{"type":"integer","format":"int64"},` +
+			`"items":{"type":"array","items":{"$ref":"#/$defs/Item"}},"labels":{"type":"object","additionalProperties":{"type":"string"}},` +
+			`"note":{"type":["string","null"]},"status":{"$ref":"#/$defs/Status"},"total":{"type":"string"}},` +
+			`"required":["id","created_at","status","items","note","total"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := marshal(t, doc.Defs[tt.name]); got != tt.want {
+				t.Errorf("unexpected schema:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_Options(t *testing.T) {
+	code := `type Node struct {
+	Name     string  ` + "`json:\"name\"`" + `
+	Parent   *Node   ` + "`json:\"parent\"`" + `
+	Children []*Node ` + "`json:\"children,omitempty\"`" + `
+	Page     Page[string] ` + "`json:\"page\"`" + `
+	Done     chan bool
+}
+
+type Page[T any] struct {
+	Items []T ` + "`json:\"items\"`" + `
+}
+`
+	result, err := Generate(context.Background(), SchemaParams{GoCode: code, Types: []string{"Node"}, Format: "openapi"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if got := strings.Join(result.Types, ","); got != "Node,Page_string" {
+		t.Errorf("unexpected types %s", got)
+	}
+	schemas := result.Document.Components.Schemas
+	want := `{"type":"object","properties":{"children":{"type":"array","items":{"$ref":"#/components/schemas/Node"}},` +
+		`"name":{"type":"string"},"page":{"$ref":"#/components/schemas/Page_string"},` +
+		`"parent":{"anyOf":[{"$ref":"#/components/schemas/Node"},{"type":"null"}]}},"required":["name","parent","page"]}`
+	if got := marshal(t, schemas["Node"]); got != want {
+		t.Errorf("unexpected schema:\n%s\nwant:\n%s", got, want)
+	}
+	if got := marshal(t, schemas["Page_string"]); got != `{"type":"object","properties":{"items":{"type":"array","items":{"type":"string"}}},"required":["items"]}` {
+		t.Errorf("unexpected instantiation schema %s", got)
+	}
+	if result.Document.Schema != "" || result.Document.Defs != nil {
+		t.Errorf("expected only components for openapi, got %+v", result.Document)
+	}
+
+	found := false
+	for _, s := range result.Suggestions {
+		found = found || strings.Contains(s, "Field Done has type chan bool")
+	}
+	if !found {
+		t.Errorf("expected a suggestion on the channel field, got %v", result.Suggestions)
+	}
+}
+
+func TestGenerate_Embedding(t *testing.T) {
+	code := `type A struct {
+	Name string
+	ID   int
+}
+
+type B struct {
+	Name string
+	Key  string ` + "`json:\"ID\"`" + `
+}
+
+type C struct {
+	A
+	*B
+	Inner A ` + "`json:\"inner\"`" + `
+}
+
+type Custom struct{}
+
+func (Custom) MarshalJSON() ([]byte, error) { return nil, nil }
+
+type D struct {
+	C
+	Name   bool   ` + "`json:\"Name\"`" + `
+	Custom Custom ` + "`json:\"custom\"`" + `
+}
+`
+	result, err := Generate(context.Background(), SchemaParams{GoCode: code, Types: []string{"C", "D"}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	defs := result.Document.Defs
+	// Name conflicts at the same depth and is dropped; the tagged ID wins
+	if got := marshal(t, defs["C"]); got != `{"type":"object","properties":{"ID":{"type":"string"},"inner":{"$ref":"#/$defs/A"}},"required":["ID","inner"]}` {
+		t.Errorf("unexpected schema for C: %s", got)
+	}
+	// The shallower Name wins
+	want := `{"type":"object","properties":{"ID":{"type":"string"},"Name":{"type":"boolean"},` +
+		`"custom":{"description":"Encoded by the MarshalJSON method of Custom"},"inner":{"$ref":"#/$defs/A"}},"required":["Name","ID","inner","custom"]}`
+	if got := marshal(t, defs["D"]); got != want {
+		t.Errorf("unexpected schema for D:\n%s\nwant:\n%s", got, want)
+	}
+	if _, ok := defs["Custom"]; ok {
+		t.Errorf("expected Custom to be described inline")
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  SchemaParams
+		wantErr string
+	}{
+		{"no code", SchemaParams{}, "go_code parameter is required"},
+		{"format", SchemaParams{GoCode: models, Format: "avro"}, "format must be one of"},
+		{"syntax", SchemaParams{GoCode: "type T struct {"}, "failed to parse go_code"},
+		{"unknown type", SchemaParams{GoCode: models, Types: []string{"Missing"}}, "type Missing is not declared in go_code"},
+		{"not a type", SchemaParams{GoCode: models, Types: []string{"StatusOpen"}}, "type StatusOpen is not declared in go_code"},
+		{"generic", SchemaParams{GoCode: "type Page[T any] struct{ Items []T }", Types: []string{"Page"}}, "type Page is generic"},
+		{"no structs", SchemaParams{GoCode: "type id string"}, "go_code declares no exported struct types"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(context.Background(), tt.params)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
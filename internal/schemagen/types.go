@@ -0,0 +1,58 @@
+package schemagen
+
+import (
+	"encoding/json"
+)
+
+// SchemaParams represents the parameters for the struct-schema tool
+type SchemaParams struct {
+	GoCode         string   `json:"go_code" jsonschema:"description:Go type declarations to describe, with or without a package clause"`
+	Types          []string `json:"types,omitempty" jsonschema:"description:Optional names of the types to emit schemas for (defaults to every exported struct type); the types they refer to are always included"`
+	Format         string   `json:"format,omitempty" jsonschema:"description:Optional output format: 'jsonschema' for a JSON Schema 2020-12 document with $defs or 'openapi' for OpenAPI 3.1 component schemas (defaults to 'jsonschema')"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// SchemaResult holds the schemas of the requested Go types
+type SchemaResult struct {
+	Summary     string    `json:"summary"`
+	Format      string    `json:"format"`
+	Types       []string  `json:"types"` // Schema names in declaration order, requested types first
+	Document    *Document `json:"document"`
+	Suggestions []string  `json:"suggestions"`
+}
+
+// Document is a JSON Schema document or the components section of an
+// OpenAPI document
+type Document struct {
+	Schema     string             `json:"$schema,omitempty"`
+	Ref        string             `json:"$ref,omitempty"` // The requested type when only one was requested
+	Defs       map[string]*Schema `json:"$defs,omitempty"`
+	Components *Components        `json:"components,omitempty"`
+}
+
+// Components is the components section of an OpenAPI document
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is a JSON Schema 2020-12 schema, which OpenAPI 3.1 uses as is
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"` // A type name, or a list of them for nullable values
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+// String returns a formatted JSON string of the SchemaResult
+func (r *SchemaResult) String() string {
+	jsonData, _ := json.MarshalIndent(r, "", "  ")
+	return string(jsonData)
+}
@@ -0,0 +1,142 @@
+// Package session keeps the defaults a client sets for the rest of its MCP
+// session, so later calls can leave those parameters out
+package session
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"mcp-go-assistant/internal/codereview"
+)
+
+// Defaults are the parameters applied to the calls of a session that leave
+// them unset
+type Defaults struct {
+	WorkingDir     string                `json:"working_dir,omitempty"`
+	GuidelinesFile string                `json:"guidelines_file,omitempty"`
+	Language       string                `json:"language,omitempty"`
+	Audience       string                `json:"audience,omitempty"`
+	OutputFormat   string                `json:"output_format,omitempty"`
+	Thresholds     codereview.Thresholds `json:"thresholds,omitempty"`
+}
+
+// ConfigureParams represents the parameters for the configure-session tool
+type ConfigureParams struct {
+	WorkingDir     string                `json:"working_dir,omitempty" jsonschema:"description:Optional directory used by the tools taking a working_dir when a call leaves it out; code-review uses it only for calls without go_code"`
+	GuidelinesFile string                `json:"guidelines_file,omitempty" jsonschema:"description:Optional markdown guidelines file for code-review and code-review-batch calls without guidelines"`
+	Language       string                `json:"language,omitempty" jsonschema:"description:Optional review language: 'en', 'ja' or 'es'"`
+	Audience       string                `json:"audience,omitempty" jsonschema:"description:Optional code-review audience: 'human' or 'llm'"`
+	OutputFormat   string                `json:"output_format,omitempty" jsonschema:"description:Optional code-review output format: 'text', 'json', 'compact' or 'markdown'"`
+	Thresholds     codereview.Thresholds `json:"thresholds,omitempty" jsonschema:"description:Optional review limits for function length, parameter count, struct fields and cyclomatic complexity"`
+	Reset          bool                  `json:"reset,omitempty" jsonschema:"description:Clear the session defaults before applying the parameters of this call"`
+}
+
+// Validate checks the review options of the parameters
+func (p ConfigureParams) Validate() error {
+	return codereview.ValidateOptions(p.Language, p.OutputFormat, p.Audience, p.Thresholds)
+}
+
+// String renders the defaults as one key: value line per set parameter
+func (d Defaults) String() string {
+	var b strings.Builder
+	for _, field := range []struct{ name, value string }{
+		{"working_dir", d.WorkingDir},
+		{"guidelines_file", d.GuidelinesFile},
+		{"language", d.Language},
+		{"audience", d.Audience},
+		{"output_format", d.OutputFormat},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", field.name, field.value)
+		}
+	}
+	for _, field := range []struct {
+		name  string
+		value int
+	}{
+		{"thresholds.function_lines", d.Thresholds.FunctionLines},
+		{"thresholds.parameters", d.Thresholds.Parameters},
+		{"thresholds.struct_fields", d.Thresholds.StructFields},
+		{"thresholds.complexity", d.Thresholds.Complexity},
+	} {
+		if field.value != 0 {
+			fmt.Fprintf(&b, "%s: %d\n", field.name, field.value)
+		}
+	}
+	if b.Len() == 0 {
+		return "No session defaults set"
+	}
+	return "Session defaults:\n" + b.String()
+}
+
+// Session is a client session whose defaults are dropped when it ends
+type Session interface {
+	Wait() error
+}
+
+// Store holds the defaults of the open sessions
+type Store struct {
+	mu       sync.Mutex
+	defaults map[Session]Defaults
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{defaults: make(map[Session]Defaults)}
+}
+
+// Get returns the defaults of ss, or false when it has none
+func (s *Store) Get(ss Session) (Defaults, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.defaults[ss]
+	return d, ok
+}
+
+// Configure sets the non-empty parameters of p as defaults of ss, after
+// clearing the previous defaults for a reset, and returns the defaults now
+// in effect
+func (s *Store) Configure(ss Session, p ConfigureParams) Defaults {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, known := s.defaults[ss]
+	if p.Reset {
+		d = Defaults{}
+	}
+	for _, field := range []struct {
+		dst   *string
+		value string
+	}{
+		{&d.WorkingDir, p.WorkingDir},
+		{&d.GuidelinesFile, p.GuidelinesFile},
+		{&d.Language, p.Language},
+		{&d.Audience, p.Audience},
+		{&d.OutputFormat, p.OutputFormat},
+	} {
+		if field.value != "" {
+			*field.dst = field.value
+		}
+	}
+	d.Thresholds = p.Thresholds.WithDefaults(d.Thresholds)
+	s.defaults[ss] = d
+
+	if !known {
+		// Forget the defaults when the session ends
+		go func() {
+			_ = ss.Wait()
+			s.mu.Lock()
+			delete(s.defaults, ss)
+			s.mu.Unlock()
+		}()
+	}
+	return d
+}
+
+// Len returns the number of sessions with defaults
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.defaults)
+}
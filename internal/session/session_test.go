@@ -0,0 +1,125 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-go-assistant/internal/codereview"
+)
+
+// fakeSession is a session that ends when its done channel is closed
+type fakeSession struct {
+	done chan struct{}
+}
+
+func newFakeSession() *fakeSession {
+	return &fakeSession{done: make(chan struct{})}
+}
+
+func (s *fakeSession) Wait() error {
+	<-s.done
+	return nil
+}
+
+func TestStore_Configure(t *testing.T) {
+	store := NewStore()
+	ss := newFakeSession()
+	defer close(ss.done)
+
+	if _, ok := store.Get(ss); ok {
+		t.Fatal("expected no defaults before configure")
+	}
+
+	store.Configure(ss, ConfigureParams{
+		WorkingDir: "/src/app",
+		Language:   "ja",
+		Thresholds: codereview.Thresholds{FunctionLines: 80},
+	})
+	d := store.Configure(ss, ConfigureParams{
+		Audience:   "llm",
+		Thresholds: codereview.Thresholds{Complexity: 15},
+	})
+	want := Defaults{
+		WorkingDir: "/src/app",
+		Language:   "ja",
+		Audience:   "llm",
+		Thresholds: codereview.Thresholds{FunctionLines: 80, Complexity: 15},
+	}
+	if d != want {
+		t.Errorf("Configure() = %+v, want %+v", d, want)
+	}
+	if got, _ := store.Get(ss); got != want {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+
+	// Sessions do not see the defaults of others
+	other := newFakeSession()
+	defer close(other.done)
+	store.Configure(other, ConfigureParams{Language: "es"})
+	if got, _ := store.Get(ss); got.Language != "ja" {
+		t.Errorf("expected the first session to keep its language, got %q", got.Language)
+	}
+
+	d = store.Configure(ss, ConfigureParams{Reset: true, OutputFormat: "json"})
+	if d != (Defaults{OutputFormat: "json"}) {
+		t.Errorf("expected a reset to clear the previous defaults, got %+v", d)
+	}
+}
+
+func TestStore_ForgetsEndedSessions(t *testing.T) {
+	store := NewStore()
+	ss := newFakeSession()
+	store.Configure(ss, ConfigureParams{Language: "es"})
+	store.Configure(ss, ConfigureParams{Audience: "llm"})
+	if store.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", store.Len())
+	}
+
+	close(ss.done)
+	deadline := time.Now().Add(5 * time.Second)
+	for store.Len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the defaults to be dropped when the session ends")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestConfigureParams_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  ConfigureParams
+		wantErr bool
+	}{
+		{name: "empty", params: ConfigureParams{}},
+		{name: "valid", params: ConfigureParams{Language: "en", Audience: "human", OutputFormat: "markdown"}},
+		{name: "unknown language", params: ConfigureParams{Language: "fr"}, wantErr: true},
+		{name: "unknown audience", params: ConfigureParams{Audience: "robot"}, wantErr: true},
+		{name: "unknown output format", params: ConfigureParams{OutputFormat: "xml"}, wantErr: true},
+		{name: "negative threshold", params: ConfigureParams{Thresholds: codereview.Thresholds{Parameters: -1}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.params.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaults_String(t *testing.T) {
+	if got := (Defaults{}).String(); got != "No session defaults set" {
+		t.Errorf("String() = %q for empty defaults", got)
+	}
+
+	got := Defaults{WorkingDir: "/src/app", Thresholds: codereview.Thresholds{Parameters: 4}}.String()
+	for _, want := range []string{"Session defaults:", "working_dir: /src/app", "thresholds.parameters: 4"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String() = %q, missing %q", got, want)
+		}
+	}
+	if strings.Contains(got, "language") {
+		t.Errorf("String() = %q, expected unset fields to be left out", got)
+	}
+}
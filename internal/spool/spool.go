@@ -0,0 +1,43 @@
+// Package spool moves large tool inputs out of request parameters into
+// private temporary files, so retries and error paths carry a short path
+// instead of a multi-megabyte string.
+package spool
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Dir is a private temporary directory holding one request's spooled inputs
+type Dir struct {
+	path string
+}
+
+// New creates a spool directory that only the server user can read
+func New() (*Dir, error) {
+	path, err := os.MkdirTemp("", "mcp-spool-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %v", err)
+	}
+	return &Dir{path: path}, nil
+}
+
+// Write stores content in the file name and returns its path
+func (d *Dir) Write(name, content string) (string, error) {
+	path := filepath.Join(d.path, filepath.Base(name))
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return "", fmt.Errorf("failed to spool %s: %v", name, err)
+	}
+	return path, nil
+}
+
+// Path returns the directory path
+func (d *Dir) Path() string {
+	return d.path
+}
+
+// Remove deletes the directory and the files in it
+func (d *Dir) Remove() error {
+	return os.RemoveAll(d.path)
+}
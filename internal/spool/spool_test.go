@@ -0,0 +1,40 @@
+package spool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDir(t *testing.T) {
+	dir, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	path, err := dir.Write("../escape/main.go", "package main\n")
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if filepath.Dir(path) != dir.Path() {
+		t.Errorf("expected the file inside the spool directory, got %s", path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("spooled file missing: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected a private file, got mode %v", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "package main\n" {
+		t.Errorf("unexpected content %q", data)
+	}
+
+	if err := dir.Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(dir.Path()); !os.IsNotExist(err) {
+		t.Errorf("expected the directory to be removed, got %v", err)
+	}
+}
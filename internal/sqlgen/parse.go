@@ -0,0 +1,441 @@
+package sqlgen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind is the kind of a SQL token
+type tokenKind int
+
+const (
+	tokWord   tokenKind = iota // Keyword or unquoted identifier
+	tokQuoted                  // Quoted identifier
+	tokNumber
+	tokString
+	tokPunct
+)
+
+// sqlToken is a token of SQL source
+type sqlToken struct {
+	kind  tokenKind
+	text  string // As written
+	value string // Unquoted identifier or string content
+}
+
+// is reports whether the token is one of the keywords
+func (t sqlToken) is(keywords ...string) bool {
+	if t.kind != tokWord {
+		return false
+	}
+	for _, kw := range keywords {
+		if strings.EqualFold(t.text, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenize splits SQL source into tokens, dropping comments
+func tokenize(src string) ([]sqlToken, error) {
+	var toks []sqlToken
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := i + 2
+			for end+1 < len(runes) && !(runes[end] == '*' && runes[end+1] == '/') {
+				end++
+			}
+			if end+1 >= len(runes) {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i = end + 2
+		case r == '\'' || r == '"' || r == '`':
+			value, n, ok := quoted(runes[i:], r)
+			if !ok {
+				return nil, fmt.Errorf("unterminated %c quote", r)
+			}
+			kind := tokQuoted
+			if r == '\'' {
+				kind = tokString
+			}
+			toks = append(toks, sqlToken{kind: kind, text: string(runes[i : i+n]), value: value})
+			i += n
+		case r == '$' && dollarTag(runes[i:]) != "":
+			// PostgreSQL dollar-quoted string, e.g. a function body
+			tag := dollarTag(runes[i:])
+			rest := string(runes[i+len([]rune(tag)):])
+			end := strings.Index(rest, tag)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated %s quote", tag)
+			}
+			n := len([]rune(tag)) + len([]rune(rest[:end])) + len([]rune(tag))
+			toks = append(toks, sqlToken{kind: tokString, text: string(runes[i : i+n]), value: rest[:end]})
+			i += n
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			word := string(runes[start:i])
+			toks = append(toks, sqlToken{kind: tokWord, text: word, value: word})
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			toks = append(toks, sqlToken{kind: tokNumber, text: string(runes[start:i])})
+		default:
+			toks = append(toks, sqlToken{kind: tokPunct, text: string(r)})
+			i++
+		}
+	}
+	return toks, nil
+}
+
+// quoted returns the content of the quoted token at the start of runes,
+// where doubled quotes stand for one, and its length
+func quoted(runes []rune, quote rune) (string, int, bool) {
+	var b strings.Builder
+	for i := 1; i < len(runes); i++ {
+		if runes[i] != quote {
+			b.WriteRune(runes[i])
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == quote {
+			b.WriteRune(quote)
+			i++
+			continue
+		}
+		return b.String(), i + 1, true
+	}
+	return "", 0, false
+}
+
+// dollarTag returns the $tag$ opening a dollar-quoted string at the start
+// of runes, or ""
+func dollarTag(runes []rune) string {
+	for i := 1; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '$':
+			return string(runes[:i+1])
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_':
+			return ""
+		}
+	}
+	return ""
+}
+
+// schema is the parsed table and enum definitions
+type schema struct {
+	tables  []*table
+	enums   []*enum
+	skipped int // Statements other than table and enum definitions
+}
+
+// table is a CREATE TABLE statement
+type table struct {
+	name    string // Unqualified, unquoted
+	sqlName string // As written
+	columns []*column
+}
+
+// column is a column definition
+type column struct {
+	name    string // Unquoted
+	sqlName string // As written
+	typ     string // Lower-cased type as written, e.g. "varchar(255)" or "integer[]"
+	notNull bool
+	pk      bool
+	auto    bool // Generated by the database: serial, identity, auto-increment or generated
+}
+
+// enum is a CREATE TYPE ... AS ENUM statement
+type enum struct {
+	name   string
+	values []string
+}
+
+// parseSchema parses the table and enum definitions of SQL source
+func parseSchema(src, dialect string) (*schema, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &schema{}
+	var stmt []sqlToken
+	for i := 0; i <= len(toks); i++ {
+		if i < len(toks) && !(toks[i].kind == tokPunct && toks[i].text == ";") {
+			stmt = append(stmt, toks[i])
+			continue
+		}
+		if len(stmt) > 0 {
+			if err := s.statement(stmt, dialect); err != nil {
+				return nil, err
+			}
+		}
+		stmt = nil
+	}
+	return s, nil
+}
+
+// statement adds the definition of one statement
+func (s *schema) statement(toks []sqlToken, dialect string) error {
+	p := &cursor{toks: toks}
+	if !p.accept("CREATE") {
+		s.skipped++
+		return nil
+	}
+	p.accept("OR")
+	p.accept("REPLACE")
+	p.accept("GLOBAL", "LOCAL")
+	p.accept("TEMP", "TEMPORARY", "UNLOGGED")
+
+	switch {
+	case p.accept("TABLE"):
+		if p.accept("IF") {
+			p.accept("NOT")
+			p.accept("EXISTS")
+		}
+		name, sqlName, ok := p.qualifiedName()
+		if !ok {
+			return fmt.Errorf("expected a table name after CREATE TABLE")
+		}
+		items, ok := p.list()
+		if !ok {
+			// CREATE TABLE ... AS SELECT and the like
+			s.skipped++
+			return nil
+		}
+		t, err := parseTable(name, sqlName, items, dialect)
+		if err != nil {
+			return err
+		}
+		s.tables = append(s.tables, t)
+	case p.accept("TYPE"):
+		name, _, ok := p.qualifiedName()
+		if !ok || !p.accept("AS") || !p.accept("ENUM") {
+			s.skipped++
+			return nil
+		}
+		items, ok := p.list()
+		if !ok {
+			return fmt.Errorf("expected the values of enum %s", name)
+		}
+		e := &enum{name: name}
+		for _, item := range items {
+			if len(item) == 1 && item[0].kind == tokString {
+				e.values = append(e.values, item[0].value)
+			}
+		}
+		s.enums = append(s.enums, e)
+	default:
+		s.skipped++
+	}
+	return nil
+}
+
+// tableConstraints start table constraints rather than columns
+var tableConstraints = []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "KEY", "INDEX", "EXCLUDE", "FULLTEXT", "SPATIAL"}
+
+// columnConstraints end the type of a column definition
+var columnConstraints = []string{"NOT", "NULL", "PRIMARY", "DEFAULT", "REFERENCES", "UNIQUE", "CHECK", "CONSTRAINT",
+	"GENERATED", "AUTO_INCREMENT", "AUTOINCREMENT", "COLLATE", "COMMENT", "IDENTITY", "ON", "AS", "CHARACTER", "CHARSET"}
+
+// parseTable parses the column and constraint definitions of a table
+func parseTable(name, sqlName string, items [][]sqlToken, dialect string) (*table, error) {
+	t := &table{name: name, sqlName: sqlName}
+	var pk []string
+	for _, item := range items {
+		if len(item) == 0 {
+			continue
+		}
+		if item[0].is(tableConstraints...) {
+			p := &cursor{toks: item}
+			if p.accept("CONSTRAINT") && !p.done() {
+				p.next()
+			}
+			if p.accept("PRIMARY") && p.accept("KEY") {
+				cols, _ := p.list()
+				for _, col := range cols {
+					if len(col) > 0 {
+						pk = append(pk, col[0].value)
+					}
+				}
+			}
+			continue
+		}
+		if item[0].kind != tokWord && item[0].kind != tokQuoted {
+			return nil, fmt.Errorf("unexpected %s in table %s", item[0].text, name)
+		}
+
+		c := &column{name: item[0].value, sqlName: item[0].text}
+		i := 1
+		var typ strings.Builder
+		depth := 0
+		for ; i < len(item); i++ {
+			tok := item[i]
+			if depth == 0 && tok.is(columnConstraints...) && !(tok.is("CHARACTER") && typ.Len() == 0) {
+				break
+			}
+			switch tok.text {
+			case "(", "[":
+				depth++
+			case ")", "]":
+				depth--
+			}
+			if typ.Len() > 0 && tok.kind != tokPunct && !strings.HasSuffix(typ.String(), "(") && !strings.HasSuffix(typ.String(), ",") {
+				typ.WriteByte(' ')
+			}
+			typ.WriteString(strings.ToLower(tok.text))
+		}
+		c.typ = typ.String()
+		if c.typ == "" {
+			return nil, fmt.Errorf("column %s of table %s has no type", c.name, name)
+		}
+
+		p := &cursor{toks: item[i:]}
+		for !p.done() {
+			switch {
+			case p.accept("NOT"):
+				if p.accept("NULL") {
+					c.notNull = true
+				}
+			case p.accept("PRIMARY"):
+				p.accept("KEY")
+				c.pk = true
+			case p.accept("IDENTITY", "AUTO_INCREMENT", "AUTOINCREMENT"):
+				c.auto = true
+				c.notNull = true
+			case p.accept("GENERATED"):
+				c.auto = true
+			default:
+				p.next()
+			}
+		}
+		if strings.Contains(baseType(c.typ), "serial") {
+			c.auto = true
+			c.notNull = true
+		}
+		if c.pk && dialect == "sqlite" && baseType(c.typ) == "integer" {
+			// INTEGER PRIMARY KEY aliases the rowid
+			c.auto = true
+		}
+		t.columns = append(t.columns, c)
+	}
+
+	for _, name := range pk {
+		for _, c := range t.columns {
+			if strings.EqualFold(c.name, name) {
+				c.pk = true
+			}
+		}
+	}
+	for _, c := range t.columns {
+		if c.pk {
+			c.notNull = true
+		}
+	}
+	return t, nil
+}
+
+// baseType returns a column type without arguments, array suffixes and
+// sign modifiers
+func baseType(typ string) string {
+	if i := strings.IndexAny(typ, "(["); i >= 0 {
+		typ = typ[:i]
+	}
+	typ = strings.TrimSuffix(strings.TrimSpace(typ), " array")
+	typ = strings.TrimSuffix(typ, " unsigned")
+	typ = strings.TrimSuffix(typ, " signed")
+	return strings.TrimSpace(typ)
+}
+
+// cursor walks the tokens of a statement
+type cursor struct {
+	toks []sqlToken
+	i    int
+}
+
+func (p *cursor) done() bool { return p.i >= len(p.toks) }
+
+func (p *cursor) next() sqlToken {
+	tok := p.toks[p.i]
+	p.i++
+	return tok
+}
+
+// accept consumes the next token if it is one of the keywords
+func (p *cursor) accept(keywords ...string) bool {
+	if !p.done() && p.toks[p.i].is(keywords...) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+// qualifiedName consumes a possibly schema-qualified name and returns its
+// last part and the name as written
+func (p *cursor) qualifiedName() (string, string, bool) {
+	var name string
+	var text strings.Builder
+	for !p.done() {
+		tok := p.toks[p.i]
+		if tok.kind != tokWord && tok.kind != tokQuoted {
+			break
+		}
+		p.i++
+		name = tok.value
+		text.WriteString(tok.text)
+		if p.done() || p.toks[p.i].text != "." {
+			break
+		}
+		p.i++
+		text.WriteString(".")
+	}
+	return name, text.String(), name != ""
+}
+
+// list consumes a parenthesized list and returns its comma-separated items
+func (p *cursor) list() ([][]sqlToken, bool) {
+	if p.done() || p.toks[p.i].text != "(" {
+		return nil, false
+	}
+	p.i++
+
+	var items [][]sqlToken
+	var item []sqlToken
+	depth := 0
+	for !p.done() {
+		tok := p.next()
+		if tok.kind == tokPunct {
+			switch tok.text {
+			case "(":
+				depth++
+			case ")":
+				if depth == 0 {
+					return append(items, item), true
+				}
+				depth--
+			case ",":
+				if depth == 0 {
+					items = append(items, item)
+					item = nil
+					continue
+				}
+			}
+		}
+		item = append(item, tok)
+	}
+	return nil, false
+}
@@ -0,0 +1,574 @@
+// Package sqlgen generates Go models and sqlc-style query methods from SQL
+// table definitions.
+package sqlgen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"go/token"
+	"slices"
+	"strings"
+	"unicode"
+
+	"mcp-go-assistant/internal/naming"
+)
+
+// tagKeys are the struct tag keys the tool can emit
+var tagKeys = []string{"db", "json", "yaml"}
+
+// nullTypes are the database/sql types of nullable columns by Go type
+var nullTypes = map[string]string{
+	"bool":      "sql.NullBool",
+	"int8":      "sql.NullInt16",
+	"int16":     "sql.NullInt16",
+	"int32":     "sql.NullInt32",
+	"int64":     "sql.NullInt64",
+	"float32":   "sql.NullFloat64",
+	"float64":   "sql.NullFloat64",
+	"string":    "sql.NullString",
+	"time.Time": "sql.NullTime",
+}
+
+// Generate parses the table definitions and returns their Go models, and
+// query methods when requested
+func Generate(ctx context.Context, params SQLGenParams) (*SQLGenResult, error) {
+	if strings.TrimSpace(params.SQL) == "" {
+		return nil, fmt.Errorf("sql parameter is required")
+	}
+	if err := normalize(&params); err != nil {
+		return nil, err
+	}
+
+	s, err := parseSchema(params.SQL, params.Dialect)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sql: %w", err)
+	}
+	if len(s.tables) == 0 {
+		return nil, fmt.Errorf("sql declares no tables; expected CREATE TABLE statements")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g := newGenerator(params, s)
+	result := &SQLGenResult{Tables: []Table{}, Suggestions: []string{}}
+	result.Code, err = g.models()
+	if err != nil {
+		return nil, err
+	}
+	if params.Queries {
+		result.QueriesCode, err = g.queries()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, m := range g.structs {
+		t := Table{Name: m.table.name, Type: m.name, Columns: len(m.fields)}
+		for _, f := range m.fields {
+			if f.column.pk {
+				t.PrimaryKey = append(t.PrimaryKey, f.column.name)
+			}
+		}
+		result.Tables = append(result.Tables, t)
+	}
+	for _, e := range s.enums {
+		result.Enums = append(result.Enums, g.enums[strings.ToLower(e.name)])
+	}
+
+	if s.skipped > 0 {
+		g.suggest("%d statements other than CREATE TABLE and CREATE TYPE ... AS ENUM were ignored.", s.skipped)
+	}
+	if g.nullJSON {
+		g.suggest("sql.Null types encode to JSON as objects with a Valid field; use nullable 'pointer' when the models are also JSON payloads.")
+	}
+	result.Suggestions = append(result.Suggestions, g.suggestions...)
+
+	result.Summary = fmt.Sprintf("%d models", len(result.Tables))
+	if len(result.Enums) > 0 {
+		result.Summary += fmt.Sprintf(" and %d enum types", len(result.Enums))
+	}
+	if params.Queries {
+		result.Summary += fmt.Sprintf(" with %d query methods", g.methods)
+	}
+	result.Summary += " generated for " + params.Dialect
+	return result, nil
+}
+
+// normalize validates the parameters and fills in their defaults
+func normalize(params *SQLGenParams) error {
+	params.Dialect = strings.ToLower(params.Dialect)
+	switch params.Dialect {
+	case "":
+		params.Dialect = "postgres"
+	case "postgres", "mysql", "sqlite":
+	default:
+		return fmt.Errorf("dialect must be one of: postgres, mysql, sqlite (got: %s)", params.Dialect)
+	}
+
+	if params.PackageName == "" {
+		params.PackageName = "models"
+	}
+	if !token.IsIdentifier(params.PackageName) {
+		return fmt.Errorf("package_name must be a Go identifier (got: %s)", params.PackageName)
+	}
+
+	switch params.Nullable {
+	case "":
+		params.Nullable = "sql"
+	case "sql", "pointer":
+	default:
+		return fmt.Errorf("nullable must be one of: sql, pointer (got: %s)", params.Nullable)
+	}
+
+	if len(params.Tags) == 0 {
+		params.Tags = []string{"db", "json"}
+	}
+	var tags []string
+	for _, tag := range params.Tags {
+		if !slices.Contains(tagKeys, tag) {
+			return fmt.Errorf("tags must be among: %s (got: %s)", strings.Join(tagKeys, ", "), tag)
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	params.Tags = tags
+	return nil
+}
+
+// model is the struct type generated for a table
+type model struct {
+	name   string
+	table  *table
+	fields []modelField
+}
+
+// modelField is a struct field generated for a column
+type modelField struct {
+	name   string
+	typ    string
+	imp    string // Import path the type needs, if any
+	column *column
+}
+
+// generator renders a schema as Go code
+type generator struct {
+	params      SQLGenParams
+	schema      *schema
+	enums       map[string]string // Go type names by lower-cased enum name
+	structs     []*model          // Models in table order
+	names       map[string]bool   // Declared type names
+	nullJSON    bool              // A sql.Null type has a json tag
+	methods     int               // Query methods generated
+	seen        map[string]bool
+	suggestions []string
+}
+
+// newGenerator names the types of the schema and its columns
+func newGenerator(params SQLGenParams, s *schema) *generator {
+	g := &generator{params: params, schema: s, enums: make(map[string]string), names: make(map[string]bool)}
+	if params.Queries {
+		for _, name := range []string{"DBTX", "Queries", "New"} {
+			g.names[name] = true
+		}
+	}
+	for _, e := range s.enums {
+		g.enums[strings.ToLower(e.name)] = g.typeName(identifier(e.name))
+	}
+	for _, t := range s.tables {
+		m := &model{name: g.typeName(singular(identifier(t.name))), table: t}
+		fieldNames := make(map[string]bool)
+		for _, c := range t.columns {
+			name := identifier(c.name)
+			for i := 2; fieldNames[name]; i++ {
+				name = fmt.Sprintf("%s%d", identifier(c.name), i)
+			}
+			fieldNames[name] = true
+			typ, imp := g.columnType(t, c)
+			m.fields = append(m.fields, modelField{name: name, typ: typ, imp: imp, column: c})
+		}
+		g.structs = append(g.structs, m)
+	}
+	return g
+}
+
+// suggest adds a suggestion once
+func (g *generator) suggest(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if g.seen == nil {
+		g.seen = make(map[string]bool)
+	}
+	if !g.seen[msg] {
+		g.seen[msg] = true
+		g.suggestions = append(g.suggestions, msg)
+	}
+}
+
+// typeName returns an unused type name based on name
+func (g *generator) typeName(name string) string {
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// columnType returns the Go type of a column and the import it needs
+func (g *generator) columnType(t *table, c *column) (string, string) {
+	typ, imp := g.scalarType(t, c)
+	if strings.HasSuffix(c.typ, "]") || strings.HasSuffix(c.typ, " array") {
+		g.suggest("Array columns need a driver-specific scanner such as pgtype or pq.Array when used with database/sql.")
+		return "[]" + typ, imp
+	}
+	if c.notNull || typ == "any" || strings.HasPrefix(typ, "[]") || typ == "json.RawMessage" {
+		return typ, imp
+	}
+
+	if g.params.Nullable == "pointer" {
+		return "*" + typ, imp
+	}
+	if slices.Contains(g.params.Tags, "json") {
+		g.nullJSON = true
+	}
+	if null, ok := nullTypes[typ]; ok {
+		return null, "database/sql"
+	}
+	// Enum types
+	return "sql.Null[" + typ + "]", "database/sql"
+}
+
+// scalarType returns the Go type of the values of a column, ignoring
+// nullability and arrays, and the import it needs
+func (g *generator) scalarType(t *table, c *column) (string, string) {
+	base := baseType(c.typ)
+	switch base {
+	case "bool", "boolean":
+		return "bool", ""
+	case "tinyint":
+		if g.params.Dialect == "mysql" && strings.HasPrefix(c.typ, "tinyint(1)") {
+			return "bool", ""
+		}
+		return "int8", ""
+	case "smallint", "int2", "smallserial", "serial2", "year":
+		return "int16", ""
+	case "integer", "int", "int4", "mediumint", "serial", "serial4":
+		if g.params.Dialect == "sqlite" {
+			// SQLite integers are 64-bit
+			return "int64", ""
+		}
+		return "int32", ""
+	case "bigint", "int8", "bigserial", "serial8":
+		return "int64", ""
+	case "real", "float4":
+		return "float32", ""
+	case "float":
+		if g.params.Dialect == "mysql" {
+			return "float32", ""
+		}
+		return "float64", ""
+	case "double", "double precision", "float8":
+		return "float64", ""
+	case "numeric", "decimal", "money":
+		g.suggest("Numeric columns are strings to keep their precision; parse them with a decimal package if needed.")
+		return "string", ""
+	case "text", "varchar", "character varying", "char", "character", "nvarchar", "nchar", "citext", "uuid",
+		"tinytext", "mediumtext", "longtext", "enum", "set", "inet", "cidr", "macaddr", "xml", "interval", "clob":
+		return "string", ""
+	case "json", "jsonb":
+		return "json.RawMessage", "encoding/json"
+	case "bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary":
+		return "[]byte", ""
+	case "timestamp", "timestamptz", "timestamp with time zone", "timestamp without time zone", "datetime",
+		"date", "time", "timetz", "time with time zone", "time without time zone":
+		return "time.Time", "time"
+	}
+	if name, ok := g.enums[base]; ok {
+		return name, ""
+	}
+	if i := strings.LastIndex(base, "."); i >= 0 {
+		if name, ok := g.enums[base[i+1:]]; ok {
+			return name, ""
+		}
+	}
+	g.suggest("Column %s.%s has type %s, which has no known Go type, so it is any.", t.name, c.name, c.typ)
+	return "any", ""
+}
+
+// tag returns the struct tag of a column
+func (g *generator) tag(c *column) string {
+	tags := make([]string, len(g.params.Tags))
+	for i, key := range g.params.Tags {
+		tags[i] = fmt.Sprintf("%s:%q", key, c.name)
+	}
+	return strings.Join(tags, " ")
+}
+
+// models renders the enum and model types
+func (g *generator) models() (string, error) {
+	imports := make(map[string]bool)
+	var body bytes.Buffer
+	for _, e := range g.schema.enums {
+		name := g.enums[strings.ToLower(e.name)]
+		fmt.Fprintf(&body, "type %s string\n\n", name)
+		if len(e.values) == 0 {
+			continue
+		}
+		body.WriteString("const (\n")
+		consts := make(map[string]bool)
+		for i, v := range e.values {
+			constName := name + identifier(v)
+			if constName == name || consts[constName] || g.names[constName] {
+				constName = fmt.Sprintf("%s%d", name, i)
+			}
+			consts[constName] = true
+			fmt.Fprintf(&body, "\t%s %s = %q\n", constName, name, v)
+		}
+		body.WriteString(")\n\n")
+	}
+
+	for _, m := range g.structs {
+		fmt.Fprintf(&body, "type %s struct {\n", m.name)
+		for _, f := range m.fields {
+			if f.imp != "" {
+				imports[f.imp] = true
+			}
+			fmt.Fprintf(&body, "\t%s %s `%s`\n", f.name, f.typ, g.tag(f.column))
+		}
+		body.WriteString("}\n\n")
+	}
+	return render(g.params.PackageName, imports, body.Bytes())
+}
+
+// queries renders sqlc-style query methods for every model
+func (g *generator) queries() (string, error) {
+	imports := map[string]bool{"context": true, "database/sql": true}
+	var body bytes.Buffer
+	body.WriteString(`// DBTX is the subset of *sql.DB and *sql.Tx the queries use
+type DBTX interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}
+
+// Queries runs the queries of the models
+type Queries struct {
+	db DBTX
+}
+
+// New returns Queries using db, a database or a transaction
+func New(db DBTX) *Queries {
+	return &Queries{db: db}
+}
+
+`)
+
+	for _, m := range g.structs {
+		var columns, scans []string
+		var keys []modelField
+		var inserts []modelField
+		for _, f := range m.fields {
+			columns = append(columns, f.column.sqlName)
+			scans = append(scans, "&i."+f.name)
+			if f.column.pk {
+				keys = append(keys, f)
+			}
+			if !f.column.auto {
+				inserts = append(inserts, f)
+			}
+		}
+		selectList := strings.Join(columns, ", ")
+		scanList := strings.Join(scans, ", ")
+
+		if len(keys) > 0 {
+			where, args, params := g.keyClause(keys, imports)
+			g.query(&body, "Get"+m.name, "one", fmt.Sprintf("SELECT %s FROM %s\nWHERE %s", selectList, m.table.sqlName, where))
+			fmt.Fprintf(&body, `func (q *Queries) Get%[1]s(ctx context.Context, %[2]s) (%[1]s, error) {
+	row := q.db.QueryRowContext(ctx, get%[1]s, %[3]s)
+	var i %[1]s
+	err := row.Scan(%[4]s)
+	return i, err
+}
+
+`, m.name, params, args, scanList)
+		} else {
+			g.suggest("Table %s has no primary key, so only its List and Create queries were generated.", m.table.name)
+		}
+
+		list := "List" + plural(m.name)
+		order := ""
+		if len(keys) > 0 {
+			var names []string
+			for _, k := range keys {
+				names = append(names, k.column.sqlName)
+			}
+			order = "\nORDER BY " + strings.Join(names, ", ")
+		}
+		g.query(&body, list, "many", fmt.Sprintf("SELECT %s FROM %s%s", selectList, m.table.sqlName, order))
+		fmt.Fprintf(&body, `func (q *Queries) %[1]s(ctx context.Context) ([]%[2]s, error) {
+	rows, err := q.db.QueryContext(ctx, %[3]s)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []%[2]s
+	for rows.Next() {
+		var i %[2]s
+		if err := rows.Scan(%[4]s); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+`, list, m.name, lowerFirst(list), scanList)
+
+		if len(inserts) > 0 {
+			var names, holders, args []string
+			fmt.Fprintf(&body, "// Create%sParams holds the columns Create%s inserts\ntype Create%sParams struct {\n", m.name, m.name, m.name)
+			for i, f := range inserts {
+				names = append(names, f.column.sqlName)
+				holders = append(holders, g.placeholder(i+1))
+				args = append(args, "arg."+f.name)
+				if f.imp != "" {
+					imports[f.imp] = true
+				}
+				fmt.Fprintf(&body, "\t%s %s `%s`\n", f.name, f.typ, g.tag(f.column))
+			}
+			body.WriteString("}\n\n")
+			g.query(&body, "Create"+m.name, "exec", fmt.Sprintf("INSERT INTO %s (%s)\nVALUES (%s)",
+				m.table.sqlName, strings.Join(names, ", "), strings.Join(holders, ", ")))
+			fmt.Fprintf(&body, `func (q *Queries) Create%[1]s(ctx context.Context, arg Create%[1]sParams) error {
+	_, err := q.db.ExecContext(ctx, create%[1]s, %[2]s)
+	return err
+}
+
+`, m.name, strings.Join(args, ", "))
+		}
+
+		if len(keys) > 0 {
+			where, args, params := g.keyClause(keys, imports)
+			g.query(&body, "Delete"+m.name, "exec", fmt.Sprintf("DELETE FROM %s\nWHERE %s", m.table.sqlName, where))
+			fmt.Fprintf(&body, `func (q *Queries) Delete%[1]s(ctx context.Context, %[2]s) error {
+	_, err := q.db.ExecContext(ctx, delete%[1]s, %[3]s)
+	return err
+}
+
+`, m.name, params, args)
+		}
+	}
+	return render(g.params.PackageName, imports, body.Bytes())
+}
+
+// query writes the constant holding the SQL of a query method, annotated
+// as sqlc annotates queries
+func (g *generator) query(b *bytes.Buffer, method, kind, sql string) {
+	g.methods++
+	fmt.Fprintf(b, "const %s = `-- name: %s :%s\n%s\n`\n\n", lowerFirst(method), method, kind, sql)
+}
+
+// keyClause returns the WHERE condition on the primary key columns, the
+// arguments of the query and the parameters of the method
+func (g *generator) keyClause(keys []modelField, imports map[string]bool) (string, string, string) {
+	var conds, args, params []string
+	for i, k := range keys {
+		conds = append(conds, fmt.Sprintf("%s = %s", k.column.sqlName, g.placeholder(i+1)))
+		name := lowerFirst(k.name)
+		if token.IsKeyword(name) || slices.Contains([]string{"ctx", "q", "row", "rows", "err", "i", "items", "arg"}, name) {
+			name += "Param"
+		}
+		args = append(args, name)
+		params = append(params, name+" "+k.typ)
+		if k.imp != "" {
+			imports[k.imp] = true
+		}
+	}
+	return strings.Join(conds, " AND "), strings.Join(args, ", "), strings.Join(params, ", ")
+}
+
+// placeholder returns the n-th query parameter placeholder of the dialect
+func (g *generator) placeholder(n int) string {
+	if g.params.Dialect == "postgres" {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// render formats a Go file with the package clause and imports
+func render(pkg string, imports map[string]bool, body []byte) (string, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		slices.Sort(paths)
+		if len(paths) == 1 {
+			fmt.Fprintf(&b, "import %q\n\n", paths[0])
+		} else {
+			b.WriteString("import (\n")
+			for _, path := range paths {
+				fmt.Fprintf(&b, "\t%q\n", path)
+			}
+			b.WriteString(")\n\n")
+		}
+	}
+	b.Write(body)
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return string(src), nil
+}
+
+// identifier returns the exported Go name of a SQL name
+func identifier(name string) string {
+	if id := naming.Exported(name, true); id != "" {
+		return id
+	}
+	return "X"
+}
+
+// lowerFirst returns a Go name starting in lower case, lowering a leading
+// initialism as a whole
+func lowerFirst(name string) string {
+	runes := []rune(name)
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	if n > 1 && n < len(runes) {
+		// URLPath -> urlPath
+		n--
+	}
+	for i := range n {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// singular returns the singular of a plural table name
+func singular(name string) string {
+	one, _ := naming.Singular(name)
+	return one
+}
+
+// plural returns the plural of a type name
+func plural(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return strings.TrimSuffix(name, "y") + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
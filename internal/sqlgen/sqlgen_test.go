@@ -0,0 +1,212 @@
+package sqlgen
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const schemaSQL = `
+-- Accounts and their orders
+CREATE TYPE mood AS ENUM ('happy', 'sad');
+
+CREATE TABLE IF NOT EXISTS public.users (
+	id BIGSERIAL PRIMARY KEY,
+	email VARCHAR(255) NOT NULL UNIQUE,
+	display_name TEXT,
+	mood mood,
+	balance NUMERIC(10, 2) NOT NULL DEFAULT 0,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	tags TEXT[],
+	settings JSONB
+);
+
+CREATE INDEX users_email ON users (email);
+
+CREATE TABLE order_items (
+	order_id INTEGER NOT NULL REFERENCES orders (id),
+	line INTEGER NOT NULL,
+	quantity SMALLINT, /* nullable */
+	CONSTRAINT order_items_pk PRIMARY KEY (order_id, line)
+);
+`
+
+func TestGenerate(t *testing.T) {
+	result, err := Generate(context.Background(), SQLGenParams{SQL: schemaSQL})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := `package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+type Mood string
+
+const (
+	MoodHappy Mood = "happy"
+	MoodSad   Mood = "sad"
+)
+
+type User struct {
+	ID          int64          ` + "`db:\"id\" json:\"id\"`" + `
+	Email       string         ` + "`db:\"email\" json:\"email\"`" + `
+	DisplayName sql.NullString ` + "`db:\"display_name\" json:\"display_name\"`" + `
+	Mood        sql.Null[Mood] ` + "`db:\"mood\" json:\"mood\"`" + `
+	Balance     string         ` + "`db:\"balance\" json:\"balance\"`" + `
+	CreatedAt   time.Time      ` + "`db:\"created_at\" json:\"created_at\"`" + `
+	Tags        []string       ` + "`db:\"tags\" json:\"tags\"`" + `
+	Settings    json.RawMessage ` + "`db:\"settings\" json:\"settings\"`" + `
+}
+
+type OrderItem struct {
+	OrderID  int32           ` + "`db:\"order_id\" json:\"order_id\"`" + `
+	Line     int32           ` + "`db:\"line\" json:\"line\"`" + `
+	Quantity sql.NullInt16   ` + "`db:\"quantity\" json:\"quantity\"`" + `
+}
+`
+	if got, w := normalizeSpace(result.Code), normalizeSpace(want); got != w {
+		t.Errorf("unexpected code:\n%s\nwant:\n%s", result.Code, want)
+	}
+	if result.QueriesCode != "" {
+		t.Errorf("expected no queries unless requested")
+	}
+
+	if len(result.Tables) != 2 || result.Tables[1].Type != "OrderItem" || strings.Join(result.Tables[1].PrimaryKey, ",") != "order_id,line" {
+		t.Errorf("unexpected tables %+v", result.Tables)
+	}
+	if strings.Join(result.Enums, ",") != "Mood" {
+		t.Errorf("unexpected enums %v", result.Enums)
+	}
+	for _, s := range []string{"1 statements other than CREATE TABLE", "encode to JSON as objects", "Array columns"} {
+		found := false
+		for _, got := range result.Suggestions {
+			found = found || strings.Contains(got, s)
+		}
+		if !found {
+			t.Errorf("expected a suggestion containing %q, got %v", s, result.Suggestions)
+		}
+	}
+}
+
+func TestGenerate_Pointers(t *testing.T) {
+	sql := "CREATE TABLE `accounts` (`id` INT AUTO_INCREMENT, `active` TINYINT(1), `score` FLOAT, `born` DATE, PRIMARY KEY (`id`)) ENGINE=InnoDB;"
+	result, err := Generate(context.Background(), SQLGenParams{SQL: sql, Dialect: "mysql", Nullable: "pointer", Tags: []string{"db"}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := "type Account struct {\n" +
+		"\tID     int32      `db:\"id\"`\n" +
+		"\tActive *bool      `db:\"active\"`\n" +
+		"\tScore  *float32   `db:\"score\"`\n" +
+		"\tBorn   *time.Time `db:\"born\"`\n" +
+		"}\n"
+	if !strings.HasSuffix(result.Code, want) {
+		t.Errorf("unexpected code:\n%s\nwant suffix:\n%s", result.Code, want)
+	}
+	if !strings.Contains(result.Code, "import \"time\"\n") {
+		t.Errorf("expected a single import of time, got:\n%s", result.Code)
+	}
+}
+
+func TestGenerate_Queries(t *testing.T) {
+	result, err := Generate(context.Background(), SQLGenParams{SQL: schemaSQL, Queries: true, Tags: []string{"db"}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	code := result.QueriesCode
+	for _, want := range []string{
+		"type DBTX interface {",
+		"func New(db DBTX) *Queries {",
+		"const getUser = `-- name: GetUser :one\nSELECT id, email, display_name, mood, balance, created_at, tags, settings FROM public.users\nWHERE id = $1\n`",
+		"func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {",
+		"err := row.Scan(&i.ID, &i.Email, &i.DisplayName, &i.Mood, &i.Balance, &i.CreatedAt, &i.Tags, &i.Settings)",
+		"func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {",
+		// The serial id is left to the database
+		"INSERT INTO public.users (email, display_name, mood, balance, created_at, tags, settings)\nVALUES ($1, $2, $3, $4, $5, $6, $7)",
+		"type CreateUserParams struct {",
+		"func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) error {",
+		"DELETE FROM order_items\nWHERE order_id = $1 AND line = $2",
+		"func (q *Queries) DeleteOrderItem(ctx context.Context, orderID int32, line int32) error {",
+		"const listOrderItems = `-- name: ListOrderItems :many\nSELECT order_id, line, quantity FROM order_items\nORDER BY order_id, line\n`",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("expected queries to contain:\n%s\ngot:\n%s", want, code)
+		}
+	}
+	if !strings.HasSuffix(result.Summary, "with 8 query methods generated for postgres") {
+		t.Errorf("unexpected summary %q", result.Summary)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  SQLGenParams
+		wantErr string
+	}{
+		{"no sql", SQLGenParams{}, "sql parameter is required"},
+		{"dialect", SQLGenParams{SQL: "CREATE TABLE t (id int)", Dialect: "oracle"}, "dialect must be one of"},
+		{"nullable", SQLGenParams{SQL: "CREATE TABLE t (id int)", Nullable: "zero"}, "nullable must be one of"},
+		{"tags", SQLGenParams{SQL: "CREATE TABLE t (id int)", Tags: []string{"xml"}}, "tags must be among"},
+		{"package", SQLGenParams{SQL: "CREATE TABLE t (id int)", PackageName: "my-models"}, "package_name must be a Go identifier"},
+		{"no tables", SQLGenParams{SQL: "SELECT 1;"}, "sql declares no tables"},
+		{"unterminated", SQLGenParams{SQL: "CREATE TABLE t (name text DEFAULT 'x)"}, "unterminated ' quote"},
+		{"no type", SQLGenParams{SQL: "CREATE TABLE t (id NOT NULL)"}, "column id of table t has no type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(context.Background(), tt.params)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		fn   func(string) string
+		in   string
+		want string
+	}{
+		{identifier, "user_id", "UserID"},
+		{identifier, "createdAt", "CreatedAt"},
+		{identifier, "2fa", "X2fa"},
+		{identifier, "HTTPServer", "HTTPServer"},
+		{identifier, "avatar_urls", "AvatarURLs"},
+		{singular, "Categories", "Category"},
+		{singular, "Addresses", "Address"},
+		{singular, "Statuses", "Status"},
+		{singular, "People", "People"},
+		{plural, "Category", "Categories"},
+		{plural, "Day", "Days"},
+		{plural, "Address", "Addresses"},
+		{lowerFirst, "URLPath", "urlPath"},
+		{lowerFirst, "ID", "id"},
+		{lowerFirst, "OrderID", "orderID"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.fn(tt.in); got != tt.want {
+			t.Errorf("%q -> %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// normalizeSpace collapses runs of blanks so comparisons ignore gofmt
+// alignment
+func normalizeSpace(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
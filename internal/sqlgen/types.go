@@ -0,0 +1,40 @@
+package sqlgen
+
+import (
+	"encoding/json"
+)
+
+// SQLGenParams represents the parameters for the sql-to-go tool
+type SQLGenParams struct {
+	SQL            string   `json:"sql" jsonschema:"description:CREATE TABLE statements, and CREATE TYPE ... AS ENUM statements for PostgreSQL; other statements are ignored"`
+	Dialect        string   `json:"dialect,omitempty" jsonschema:"description:Optional SQL dialect: 'postgres', 'mysql' or 'sqlite' (defaults to 'postgres')"`
+	PackageName    string   `json:"package_name,omitempty" jsonschema:"description:Optional package clause of the generated code (defaults to 'models')"`
+	Nullable       string   `json:"nullable,omitempty" jsonschema:"description:Optional representation of nullable columns: 'sql' for database/sql Null types or 'pointer' (defaults to 'sql')"`
+	Tags           []string `json:"tags,omitempty" jsonschema:"description:Optional struct tag keys to emit with the column names, among db, json and yaml (defaults to db and json)"`
+	Queries        bool     `json:"queries,omitempty" jsonschema:"description:Optional; also generate sqlc-style Get, List, Create and Delete query methods on a Queries type"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// SQLGenResult holds the Go models generated from SQL table definitions
+type SQLGenResult struct {
+	Summary     string   `json:"summary"`
+	Code        string   `json:"code"`                   // A formatted Go file with the models
+	QueriesCode string   `json:"queries_code,omitempty"` // A formatted Go file with the query methods, when requested
+	Tables      []Table  `json:"tables"`
+	Enums       []string `json:"enums,omitempty"` // Go types generated for enum types
+	Suggestions []string `json:"suggestions"`
+}
+
+// Table is a table and the struct type generated for it
+type Table struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Columns    int      `json:"columns"`
+	PrimaryKey []string `json:"primary_key,omitempty"`
+}
+
+// String returns a formatted JSON string of the SQLGenResult
+func (r *SQLGenResult) String() string {
+	jsonData, _ := json.MarshalIndent(r, "", "  ")
+	return string(jsonData)
+}
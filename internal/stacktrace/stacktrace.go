@@ -0,0 +1,330 @@
+package stacktrace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxOthers bounds the number of other goroutines reported
+const maxOthers = 10
+
+var (
+	goroutineRegex = regexp.MustCompile(`^goroutine (\d+)(?: [^\[]*)? \[([^\]]*)\]:$`)
+	fileLineRegex  = regexp.MustCompile(`^(.+):(\d+)(?: \+0x[0-9a-fA-F]+)?$`)
+)
+
+// causes maps fragments of panic and fatal error messages to their likely
+// cause, checked in order
+var causes = []struct {
+	fragment    string
+	kind        string
+	description string
+	suggestion  string
+}{
+	{"nil pointer dereference", "nil-dereference", "A nil pointer was dereferenced",
+		"Check that the pointer, map, interface or function value used at %s is initialized, and return an error instead of a nil value where it can be missing."},
+	{"index out of range", "index-out-of-range", "A slice, array or string was indexed past its length",
+		"Check the index against len() before indexing at %s, and make sure loops stop at len()-1."},
+	{"slice bounds out of range", "slice-bounds-out-of-range", "A slice expression exceeded the length or capacity of its operand",
+		"Check both slice bounds against len() before slicing at %s."},
+	{"concurrent map", "concurrent-map-access", "A map was written while another goroutine read or wrote it",
+		"Guard the map used at %s with a sync.Mutex or sync.RWMutex, or use sync.Map; run the tests with -race to find every unsynchronized access."},
+	{"assignment to entry in nil map", "nil-map-write", "A value was stored in a nil map",
+		"Initialize the map with make() before writing to it at %s, e.g. in the constructor."},
+	{"interface conversion", "failed-type-assertion", "A type assertion failed",
+		"Use the two-value form v, ok := x.(T) at %s and handle the case where the dynamic type differs."},
+	{"integer divide by zero", "divide-by-zero", "An integer was divided by zero",
+		"Check the divisor for zero before dividing at %s."},
+	{"send on closed channel", "closed-channel", "A value was sent on a closed channel",
+		"Make the sending side own the channel at %s so it is closed only after all sends complete."},
+	{"close of closed channel", "closed-channel", "A channel was closed twice",
+		"Close the channel at %s from a single owner, or guard the close with sync.Once."},
+	{"close of nil channel", "closed-channel", "A nil channel was closed",
+		"Create the channel with make() before closing it at %s."},
+	{"all goroutines are asleep", "deadlock", "Every goroutine is blocked waiting on another",
+		"Check the channel operations and locks in the listed goroutines for a missing send, receive, close or unlock, starting at %s."},
+	{"stack overflow", "stack-overflow", "The goroutine stack exceeded its limit, usually through unbounded recursion",
+		"Add a base case to the recursion through %s, or check for methods such as String() that call themselves."},
+	{"stack exceeds", "stack-overflow", "The goroutine stack exceeded its limit, usually through unbounded recursion",
+		"Add a base case to the recursion through %s, or check for methods such as String() that call themselves."},
+	{"out of memory", "out-of-memory", "The program ran out of memory",
+		"Profile the allocations around %s with pprof and bound the size of buffers and caches."},
+}
+
+// AnalyzeStackTrace parses a Go panic or fatal error with its goroutine
+// stack traces and reports the likely cause
+func AnalyzeStackTrace(params StackTraceParams) (*StackTraceResult, error) {
+	if strings.TrimSpace(params.Trace) == "" {
+		return nil, fmt.Errorf("trace parameter is required")
+	}
+
+	result := &StackTraceResult{Suggestions: []string{}}
+	a := &analyzer{dir: params.WorkingDir, sources: make(map[string][]string)}
+	if a.dir != "" {
+		module, err := readModulePath(a.dir)
+		if err != nil {
+			return nil, err
+		}
+		a.module = module
+		result.Module = module
+	}
+
+	goroutines := a.parse(params.Trace, result)
+	if len(goroutines) == 0 && result.Message == "" {
+		return nil, fmt.Errorf("no Go panic or goroutine stack trace found")
+	}
+	result.Goroutines = len(goroutines)
+
+	// The runtime prints the goroutine that panicked first; fatal errors
+	// may list it after others, so prefer the first running goroutine
+	panicking := -1
+	for i, g := range goroutines {
+		if strings.HasPrefix(g.State, "running") {
+			panicking = i
+			break
+		}
+	}
+	if panicking < 0 && len(goroutines) > 0 {
+		panicking = 0
+	}
+	if panicking >= 0 {
+		result.Goroutine = &goroutines[panicking]
+		result.Origin = origin(result.Goroutine)
+	}
+
+	for i, g := range goroutines {
+		if i != panicking && len(result.Others) < maxOthers && origin(&g) != nil {
+			result.Others = append(result.Others, g)
+		}
+	}
+
+	location := "the origin frame"
+	if result.Origin != nil {
+		location = result.Origin.location()
+	}
+	result.Cause = Cause{Kind: "panic", Description: "The program called panic with the value above"}
+	if result.Fatal {
+		result.Cause = Cause{Kind: "fatal-error", Description: "The runtime stopped the program with an unrecoverable error"}
+	}
+	suggestion := "Find why the value passed to panic at %s was produced, and return an error instead of panicking where the caller can handle it."
+	for _, c := range causes {
+		if strings.Contains(result.Message, c.fragment) {
+			result.Cause = Cause{Kind: c.kind, Description: c.description}
+			suggestion = c.suggestion
+			break
+		}
+	}
+	result.Suggestions = append(result.Suggestions, fmt.Sprintf(suggestion, location))
+
+	switch {
+	case result.Goroutine != nil && result.Origin == nil:
+		result.Suggestions = append(result.Suggestions,
+			"No frame of the panicking goroutine is in your code; look at the callers of the outermost standard library frame or set working_dir to identify your module.")
+	case result.Cause.Kind == "concurrent-map-access" && len(result.Others) == 0:
+		result.Suggestions = append(result.Suggestions,
+			"Only the panicking goroutine was printed; rerun with GOTRACEBACK=all to see the goroutine accessing the map concurrently.")
+	}
+	if a.module == "" {
+		result.Suggestions = append(result.Suggestions,
+			"Set working_dir to the module root to separate your frames from dependencies and include their source lines.")
+	}
+
+	result.Summary = summarize(result)
+	return result, nil
+}
+
+// analyzer parses a trace and resolves its frames against the module in
+// dir
+type analyzer struct {
+	dir     string
+	module  string
+	sources map[string][]string // Lines of the module files read so far
+}
+
+// parse records the panic message in result and returns the goroutines
+func (a *analyzer) parse(trace string, result *StackTraceResult) []Goroutine {
+	var goroutines []Goroutine
+	var current *Goroutine
+	var function string // Function line awaiting its file line
+	createdBy := false
+
+	for _, raw := range strings.Split(strings.ReplaceAll(trace, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+
+		switch {
+		case line == "":
+			current, function = nil, ""
+		case strings.HasPrefix(line, "panic: ") && result.Message == "":
+			result.Message = strings.TrimSuffix(strings.TrimPrefix(line, "panic: "), " [recovered]")
+		case strings.HasPrefix(line, "fatal error: ") && result.Message == "":
+			result.Message = strings.TrimPrefix(line, "fatal error: ")
+			result.Fatal = true
+		case goroutineRegex.MatchString(line):
+			m := goroutineRegex.FindStringSubmatch(line)
+			id, _ := strconv.Atoi(m[1])
+			goroutines = append(goroutines, Goroutine{ID: id, State: m[2], Frames: []Frame{}})
+			current, function = &goroutines[len(goroutines)-1], ""
+		case current == nil:
+			// Output outside goroutine blocks, e.g. [signal ...] or exit status
+		case function != "" && fileLineRegex.MatchString(line):
+			m := fileLineRegex.FindStringSubmatch(line)
+			lineNo, _ := strconv.Atoi(m[2])
+			frame := a.frame(function, m[1], lineNo)
+			if createdBy {
+				current.CreatedBy = &frame
+			} else {
+				current.Frames = append(current.Frames, frame)
+			}
+			function = ""
+		case strings.HasPrefix(line, "created by "):
+			name, _, _ := strings.Cut(strings.TrimPrefix(line, "created by "), " in goroutine ")
+			function, createdBy = name, true
+		case strings.HasPrefix(line, "..."):
+			// ...additional frames elided...
+		default:
+			function, createdBy = functionName(line), false
+		}
+	}
+	return goroutines
+}
+
+// frame builds a frame, resolving files in the module to their source
+func (a *analyzer) frame(function, file string, line int) Frame {
+	f := Frame{Function: function, Package: packageName(function), File: file, Line: line}
+
+	if a.module != "" {
+		if rel, ok := a.relPath(file); ok {
+			f.User, f.RelPath = true, rel
+			f.Source = a.sourceLine(rel, line)
+		} else {
+			f.User = f.Package == a.module || strings.HasPrefix(f.Package, a.module+"/")
+		}
+	} else {
+		f.User = !isStdlib(f.Package)
+	}
+	return f
+}
+
+// relPath returns file relative to the module root for absolute paths
+// under working_dir and paths trimmed to the module path by -trimpath
+func (a *analyzer) relPath(file string) (string, bool) {
+	if rel, err := filepath.Rel(a.dir, file); err == nil && filepath.IsAbs(file) && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel), true
+	}
+	if rel, ok := strings.CutPrefix(file, a.module+"/"); ok {
+		return rel, true
+	}
+	return "", false
+}
+
+// sourceLine returns the trimmed source line of the module file rel, or
+// an empty string when it cannot be read
+func (a *analyzer) sourceLine(rel string, line int) string {
+	lines, ok := a.sources[rel]
+	if !ok {
+		if data, err := os.ReadFile(filepath.Join(a.dir, filepath.FromSlash(rel))); err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+		a.sources[rel] = lines
+	}
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[line-1])
+}
+
+// location formats the frame position for messages
+func (f *Frame) location() string {
+	file := f.RelPath
+	if file == "" {
+		file = filepath.Base(f.File)
+	}
+	return fmt.Sprintf("%s:%d in %s", file, f.Line, f.Function)
+}
+
+// origin returns the innermost frame of g in user code, falling back to
+// the function that created g
+func origin(g *Goroutine) *Frame {
+	for i := range g.Frames {
+		if g.Frames[i].User {
+			return &g.Frames[i]
+		}
+	}
+	if g.CreatedBy != nil && g.CreatedBy.User {
+		return g.CreatedBy
+	}
+	return nil
+}
+
+// functionName strips the argument list from a function line such as
+// main.(*Server).handle(0x0, {0x1, 0x2})
+func functionName(line string) string {
+	if !strings.HasSuffix(line, ")") {
+		return line
+	}
+	depth := 0
+	for i := len(line) - 1; i >= 0; i-- {
+		switch line[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+			if depth == 0 {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// packageName returns the import path of the package declaring function
+func packageName(function string) string {
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		// Builtins such as panic are printed without a package
+		return "runtime"
+	}
+	return function[:slash+1+dot]
+}
+
+// isStdlib reports whether pkg looks like a standard library package
+func isStdlib(pkg string) bool {
+	first, _, _ := strings.Cut(pkg, "/")
+	return pkg != "main" && !strings.Contains(first, ".")
+}
+
+// readModulePath returns the module path declared in dir/go.mod
+func readModulePath(dir string) (string, error) {
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("go.mod not found in %s", dir)
+	}
+	for _, line := range strings.Split(string(goMod), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), nil
+		}
+	}
+	return "", fmt.Errorf("go.mod in %s has no module directive", dir)
+}
+
+// summarize describes the result in one sentence
+func summarize(r *StackTraceResult) string {
+	var sb strings.Builder
+	sb.WriteString(r.Cause.Description)
+	if r.Goroutine != nil {
+		fmt.Fprintf(&sb, " in goroutine %d", r.Goroutine.ID)
+	}
+	if r.Origin != nil {
+		fmt.Fprintf(&sb, " at %s", r.Origin.location())
+	}
+	if r.Message != "" {
+		fmt.Fprintf(&sb, ": %s", r.Message)
+	}
+	return sb.String()
+}
@@ -0,0 +1,192 @@
+package stacktrace
+
+import (
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+const nilTrace = `panic: runtime error: invalid memory address or nil pointer dereference
+[signal SIGSEGV: segmentation violation code=0x1 addr=0x0 pc=0x482ef0]
+
+goroutine 1 [running]:
+example.com/app/internal/server.(*Server).Name(...)
+	%[1]s/internal/server/server.go:8
+main.main()
+	%[1]s/main.go:22 +0x30
+exit status 2
+`
+
+const mapTrace = `fatal error: concurrent map writes
+
+goroutine 7 [running]:
+internal/runtime/maps.fatal({0x4b3c1e?, 0x0?})
+	/usr/local/go/src/runtime/panic.go:1058 +0x18
+example.com/app/cache.(*Cache).Put(0xc000010018, {0x4b1f0a, 0x1}, 0x1)
+	example.com/app/cache/cache.go:31 +0x5e
+created by main.main in goroutine 1
+	example.com/app/main.go:14 +0x4f
+
+goroutine 1 [chan receive]:
+main.main()
+	example.com/app/main.go:18 +0x8a
+
+goroutine 8 [runnable]:
+example.com/app/cache.(*Cache).Put(0xc000010018, {0x4b1f0a, 0x1}, 0x2)
+	example.com/app/cache/cache.go:31 +0x5e
+created by main.main in goroutine 1
+	example.com/app/main.go:14 +0x4f
+`
+
+const testTrace = `--- FAIL: TestLookup (0.00s)
+panic: runtime error: index out of range [5] with length 3 [recovered]
+	panic: runtime error: index out of range [5] with length 3
+
+goroutine 21 [running]:
+testing.tRunner.func1.2({0x5d0e40, 0xc00001e0d8})
+	/usr/local/go/src/testing/testing.go:1632 +0x230
+panic({0x5d0e40?, 0xc00001e0d8?})
+	/usr/local/go/src/runtime/panic.go:785 +0x132
+github.com/acme/lib/table.Lookup(...)
+	/home/dev/lib/table/table.go:12
+github.com/acme/lib/table.TestLookup(0xc000130340?)
+	/home/dev/lib/table/table_test.go:9 +0x1d
+testing.tRunner(0xc000130340, 0x60b0a8)
+	/usr/local/go/src/testing/testing.go:1690 +0xf4
+created by testing.(*T).Run in goroutine 1
+	/usr/local/go/src/testing/testing.go:1743 +0x390
+`
+
+// writeModule creates a module matching the traces above
+func writeModule(t *testing.T) string {
+	t.Helper()
+	files := map[string]string{
+		"go.mod":                    "module example.com/app\n\ngo 1.23\n",
+		"internal/server/server.go": "package server\n\ntype Config struct{ Name string }\n\ntype Server struct{ cfg *Config }\n\nfunc (s *Server) Name() string {\n\treturn s.cfg.Name\n}\n",
+		"cache/cache.go":            strings.Repeat("\n", 30) + "\tc.items[key] = value\n",
+	}
+	return testutil.WriteModule(t, files)
+}
+
+func TestAnalyzeStackTrace_NilDereference(t *testing.T) {
+	dir := writeModule(t)
+
+	result, err := AnalyzeStackTrace(StackTraceParams{
+		Trace:      strings.ReplaceAll(nilTrace, "%[1]s", dir),
+		WorkingDir: dir,
+	})
+	if err != nil {
+		t.Fatalf("AnalyzeStackTrace() error = %v", err)
+	}
+
+	if result.Cause.Kind != "nil-dereference" || result.Fatal || result.Module != "example.com/app" {
+		t.Errorf("unexpected cause %+v (fatal %v, module %q)", result.Cause, result.Fatal, result.Module)
+	}
+	if result.Goroutine == nil || result.Goroutine.ID != 1 || len(result.Goroutine.Frames) != 2 {
+		t.Fatalf("expected goroutine 1 with 2 frames, got %+v", result.Goroutine)
+	}
+
+	frame := result.Goroutine.Frames[0]
+	if frame.Function != "example.com/app/internal/server.(*Server).Name" || frame.Package != "example.com/app/internal/server" || frame.Line != 8 {
+		t.Errorf("unexpected frame %+v", frame)
+	}
+	if result.Origin == nil || result.Origin.RelPath != "internal/server/server.go" || result.Origin.Source != "return s.cfg.Name" {
+		t.Errorf("expected origin in server.go with its source line, got %+v", result.Origin)
+	}
+	if !strings.Contains(result.Summary, "goroutine 1 at internal/server/server.go:8") {
+		t.Errorf("unexpected summary %q", result.Summary)
+	}
+	if len(result.Suggestions) != 1 || !strings.Contains(result.Suggestions[0], "internal/server/server.go:8") {
+		t.Errorf("expected one suggestion naming the origin, got %v", result.Suggestions)
+	}
+}
+
+func TestAnalyzeStackTrace_ConcurrentMapWrites(t *testing.T) {
+	dir := writeModule(t)
+
+	result, err := AnalyzeStackTrace(StackTraceParams{Trace: mapTrace, WorkingDir: dir})
+	if err != nil {
+		t.Fatalf("AnalyzeStackTrace() error = %v", err)
+	}
+
+	if result.Cause.Kind != "concurrent-map-access" || !result.Fatal || result.Goroutines != 3 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if result.Goroutine == nil || result.Goroutine.ID != 7 || result.Goroutine.CreatedBy == nil || result.Goroutine.CreatedBy.Function != "main.main" {
+		t.Fatalf("expected running goroutine 7 created by main.main, got %+v", result.Goroutine)
+	}
+	// -trimpath file names resolve against the module path
+	if result.Origin == nil || result.Origin.RelPath != "cache/cache.go" || result.Origin.Source != "c.items[key] = value" {
+		t.Errorf("unexpected origin %+v", result.Origin)
+	}
+	if result.Goroutine.Frames[0].User {
+		t.Errorf("expected the runtime frame not to be user code")
+	}
+	if len(result.Others) != 2 || result.Others[0].ID != 1 || result.Others[1].ID != 8 {
+		t.Errorf("expected the other goroutines running user code, got %+v", result.Others)
+	}
+}
+
+func TestAnalyzeStackTrace_WithoutModule(t *testing.T) {
+	result, err := AnalyzeStackTrace(StackTraceParams{Trace: testTrace})
+	if err != nil {
+		t.Fatalf("AnalyzeStackTrace() error = %v", err)
+	}
+
+	if result.Message != "runtime error: index out of range [5] with length 3" {
+		t.Errorf("unexpected message %q", result.Message)
+	}
+	if result.Cause.Kind != "index-out-of-range" {
+		t.Errorf("unexpected cause %+v", result.Cause)
+	}
+	if result.Origin == nil || result.Origin.Function != "github.com/acme/lib/table.Lookup" || result.Origin.RelPath != "" {
+		t.Errorf("expected the first non-standard frame as origin, got %+v", result.Origin)
+	}
+	if frame := result.Goroutine.Frames[1]; frame.Function != "panic" || frame.Package != "runtime" || frame.User {
+		t.Errorf("unexpected builtin frame %+v", frame)
+	}
+	found := false
+	for _, s := range result.Suggestions {
+		found = found || strings.Contains(s, "working_dir")
+	}
+	if !found {
+		t.Errorf("expected a suggestion to set working_dir, got %v", result.Suggestions)
+	}
+}
+
+func TestAnalyzeStackTrace_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  StackTraceParams
+		wantErr string
+	}{
+		{"empty trace", StackTraceParams{Trace: "  \n"}, "trace parameter is required"},
+		{"no trace", StackTraceParams{Trace: "build failed: exit status 1"}, "no Go panic or goroutine stack trace found"},
+		{"no go.mod", StackTraceParams{Trace: testTrace, WorkingDir: t.TempDir()}, "go.mod not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := AnalyzeStackTrace(tt.params)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFunctionName(t *testing.T) {
+	tests := map[string]string{
+		"main.main()": "main.main",
+		"example.com/app/internal/server.(*Server).Name(...)":            "example.com/app/internal/server.(*Server).Name",
+		"main.process[...](0xc000012345, {0x1, 0x2})":                    "main.process[...]",
+		"example.com/app.Run.func1.2()":                                  "example.com/app.Run.func1.2",
+		"example.com/app/worker.(*Pool).run(0xc0000a4000, {0x0?, 0x0?})": "example.com/app/worker.(*Pool).run",
+	}
+	for line, want := range tests {
+		if got := functionName(line); got != want {
+			t.Errorf("functionName(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package stacktrace
+
+import "encoding/json"
+
+// StackTraceParams represents the parameters for the stack-trace tool
+type StackTraceParams struct {
+	Trace          string `json:"trace" jsonschema:"description:The Go panic or fatal error output including the goroutine stack traces"`
+	WorkingDir     string `json:"working_dir,omitempty" jsonschema:"description:Optional module root used to identify frames in your code and show their source lines"`
+	IdempotencyKey string `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// StackTraceResult represents the analysis of a panic or fatal error
+type StackTraceResult struct {
+	Summary     string      `json:"summary"`
+	Message     string      `json:"message"`          // Panic value or fatal error message
+	Fatal       bool        `json:"fatal,omitempty"`  // Unrecoverable runtime error such as concurrent map writes
+	Module      string      `json:"module,omitempty"` // Module path read from working_dir
+	Cause       Cause       `json:"cause"`
+	Goroutine   *Goroutine  `json:"goroutine,omitempty"` // The goroutine that panicked
+	Origin      *Frame      `json:"origin,omitempty"`    // Innermost frame in user code of the panicking goroutine
+	Goroutines  int         `json:"goroutines"`          // Number of goroutines in the trace
+	Others      []Goroutine `json:"others,omitempty"`    // Other goroutines running user code, e.g. the other side of a data race
+	Suggestions []string    `json:"suggestions"`
+}
+
+// Cause is the likely cause of the panic
+type Cause struct {
+	Kind        string `json:"kind"` // "nil-dereference", "index-out-of-range", "concurrent-map-access", etc.
+	Description string `json:"description"`
+}
+
+// Goroutine is one goroutine of the trace
+type Goroutine struct {
+	ID        int     `json:"id"`
+	State     string  `json:"state"` // e.g. "running" or "chan receive, 2 minutes"
+	Frames    []Frame `json:"frames"`
+	CreatedBy *Frame  `json:"created_by,omitempty"`
+}
+
+// Frame is one call in a goroutine stack
+type Frame struct {
+	Function string `json:"function"` // e.g. main.(*Server).handle
+	Package  string `json:"package"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	User     bool   `json:"user"`               // In the module of working_dir, or outside the standard library when no module is known
+	RelPath  string `json:"rel_path,omitempty"` // File relative to working_dir
+	Source   string `json:"source,omitempty"`   // Source line read from working_dir
+}
+
+// String returns a formatted JSON string of the StackTraceResult
+func (r *StackTraceResult) String() string {
+	jsonData, _ := json.MarshalIndent(r, "", "  ")
+	return string(jsonData)
+}
@@ -0,0 +1,251 @@
+package structgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxDepth bounds the nesting of sample values
+const maxDepth = 64
+
+// kind is the inferred kind of sample values
+type kind int
+
+const (
+	kindBool kind = iota
+	kindInt
+	kindFloat
+	kindString
+	kindTime
+	kindObject
+	kindArray
+	kindAny // Values of different kinds
+)
+
+// shape is the inferred type of the values found at one place of a sample;
+// nil stands for values that were all null
+type shape struct {
+	kind    kind
+	big     bool     // Integers beyond the int32 range
+	fields  []*field // Object keys in order of first appearance
+	objects int      // Objects merged into the shape
+	elem    *shape   // Array elements; nil when every array was empty
+}
+
+// field is a key of the objects merged into a shape
+type field struct {
+	key   string
+	shape *shape
+	seen  int  // Objects holding the key
+	null  bool // A null value was seen
+}
+
+// optional reports whether the key was null or missing from some objects
+func (s *shape) optional(f *field) bool {
+	return f.null || f.seen < s.objects
+}
+
+// set adds a key of a single object
+func (s *shape) set(key string, value *shape) {
+	for _, f := range s.fields {
+		if f.key == key {
+			// Duplicate keys: the last value wins when decoding, but
+			// both describe the field
+			f.shape = merge(f.shape, value)
+			f.null = f.null || value == nil
+			return
+		}
+	}
+	s.fields = append(s.fields, &field{key: key, shape: value, seen: 1, null: value == nil})
+}
+
+// merge returns the shape describing values of both a and b, reusing them
+func merge(a, b *shape) *shape {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	switch {
+	case a.kind == b.kind:
+		switch a.kind {
+		case kindInt:
+			a.big = a.big || b.big
+		case kindObject:
+			for _, bf := range b.fields {
+				merged := false
+				for _, af := range a.fields {
+					if af.key == bf.key {
+						af.shape = merge(af.shape, bf.shape)
+						af.seen += bf.seen
+						af.null = af.null || bf.null
+						merged = true
+						break
+					}
+				}
+				if !merged {
+					a.fields = append(a.fields, bf)
+				}
+			}
+			a.objects += b.objects
+		case kindArray:
+			a.elem = merge(a.elem, b.elem)
+		}
+		return a
+	case isNumber(a.kind) && isNumber(b.kind):
+		return &shape{kind: kindFloat}
+	case isText(a.kind) && isText(b.kind):
+		return &shape{kind: kindString}
+	}
+	return &shape{kind: kindAny}
+}
+
+func isNumber(k kind) bool { return k == kindInt || k == kindFloat }
+func isText(k kind) bool   { return k == kindString || k == kindTime }
+
+// stringShape returns the shape of a string, detecting the RFC 3339
+// timestamps encoding/json parses into time.Time
+func stringShape(s string) *shape {
+	if _, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return &shape{kind: kindTime}
+	}
+	return &shape{kind: kindString}
+}
+
+// intShape returns the shape of an integer
+func intShape(i int64) *shape {
+	return &shape{kind: kindInt, big: i < math.MinInt32 || i > math.MaxInt32}
+}
+
+// jsonShape infers the shape of the next JSON value of dec
+func jsonShape(dec *json.Decoder, depth int) (*shape, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("sample nests values deeper than %d levels", maxDepth)
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			s := &shape{kind: kindObject, objects: 1}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := jsonShape(dec, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				s.set(key.(string), value)
+			}
+			_, err := dec.Token()
+			return s, err
+		}
+		s := &shape{kind: kindArray}
+		for dec.More() {
+			value, err := jsonShape(dec, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			s.elem = merge(s.elem, value)
+		}
+		_, err := dec.Token()
+		return s, err
+	case bool:
+		return &shape{kind: kindBool}, nil
+	case json.Number:
+		if !strings.ContainsAny(t.String(), ".eE") {
+			if i, err := t.Int64(); err == nil {
+				return intShape(i), nil
+			}
+		}
+		return &shape{kind: kindFloat}, nil
+	case string:
+		return stringShape(t), nil
+	}
+	return nil, nil
+}
+
+// yamlShape infers the shape of a YAML node
+func yamlShape(n *yaml.Node, depth int) (*shape, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("sample nests values deeper than %d levels", maxDepth)
+	}
+
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return yamlShape(n.Content[0], depth)
+	case yaml.AliasNode:
+		return yamlShape(n.Alias, depth+1)
+	case yaml.MappingNode:
+		s := &shape{kind: kindObject, objects: 1}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if key.Value == "<<" && key.ShortTag() == "!!merge" {
+				// Merge keys copy the keys of other mappings
+				merged, err := yamlShape(value, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				if merged != nil && merged.kind == kindArray {
+					merged = merged.elem
+				}
+				if merged != nil && merged.kind == kindObject {
+					for _, f := range merged.fields {
+						s.set(f.key, f.shape)
+					}
+				}
+				continue
+			}
+			v, err := yamlShape(value, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			s.set(key.Value, v)
+		}
+		return s, nil
+	case yaml.SequenceNode:
+		s := &shape{kind: kindArray}
+		for _, item := range n.Content {
+			v, err := yamlShape(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			s.elem = merge(s.elem, v)
+		}
+		return s, nil
+	}
+
+	switch n.ShortTag() {
+	case "!!null":
+		return nil, nil
+	case "!!bool":
+		return &shape{kind: kindBool}, nil
+	case "!!int":
+		i, err := strconv.ParseInt(strings.ReplaceAll(n.Value, "_", ""), 0, 64)
+		if err != nil {
+			return &shape{kind: kindFloat}, nil
+		}
+		return intShape(i), nil
+	case "!!float":
+		return &shape{kind: kindFloat}, nil
+	case "!!timestamp":
+		return &shape{kind: kindTime}, nil
+	}
+	return stringShape(n.Value), nil
+}
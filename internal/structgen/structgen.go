@@ -0,0 +1,361 @@
+// Package structgen infers Go type definitions from JSON and YAML samples.
+package structgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"mcp-go-assistant/internal/naming"
+)
+
+// tagKeys are the struct tag keys the tool can emit
+var tagKeys = []string{"json", "yaml", "toml", "mapstructure"}
+
+// Generate infers Go types from the sample and returns them as a
+// formatted Go file
+func Generate(ctx context.Context, params StructGenParams) (*StructGenResult, error) {
+	if strings.TrimSpace(params.Sample) == "" {
+		return nil, fmt.Errorf("sample parameter is required")
+	}
+	if err := normalize(&params); err != nil {
+		return nil, err
+	}
+
+	root, err := parse(params.Sample, params.Format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s sample: %w", params.Format, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		params: params,
+		names:  make(map[string]bool),
+		bodies: make(map[string]string),
+	}
+	switch {
+	case root != nil && root.kind == kindObject:
+	case root != nil && root.kind == kindArray && root.elem != nil && root.elem.kind == kindObject:
+		root = root.elem
+		g.suggest("The sample is an array; decode it into []%s.", params.TypeName)
+	default:
+		return nil, fmt.Errorf("sample must be an object or an array of objects")
+	}
+	g.names[params.TypeName] = true
+	g.structType(params.TypeName, "", root)
+
+	code, err := g.file()
+	if err != nil {
+		return nil, err
+	}
+	if g.optional > 0 {
+		if params.Optional == "pointer" {
+			g.suggest("%d fields were null or missing from some array elements; they are pointers with omitempty so absent values differ from zero values.", g.optional)
+		} else {
+			g.suggest("%d fields were null or missing from some array elements; they have omitempty, so absent values decode as zero values.", g.optional)
+		}
+	}
+
+	types := make([]string, len(g.decls))
+	for i, d := range g.decls {
+		types[i] = d.name
+	}
+	return &StructGenResult{
+		Summary:     fmt.Sprintf("%d types inferred from a %s sample", len(types), params.Format),
+		Format:      params.Format,
+		Code:        code,
+		Types:       types,
+		Suggestions: append([]string{}, g.suggestions...),
+	}, nil
+}
+
+// normalize validates the parameters and fills in their defaults
+func normalize(params *StructGenParams) error {
+	params.Format = strings.ToLower(params.Format)
+	switch params.Format {
+	case "", "auto":
+		params.Format = "yaml"
+		if trimmed := strings.TrimSpace(params.Sample); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			params.Format = "json"
+		}
+	case "json", "yaml":
+	default:
+		return fmt.Errorf("format must be one of: auto, json, yaml (got: %s)", params.Format)
+	}
+
+	if params.TypeName == "" {
+		params.TypeName = "Root"
+	}
+	if !token.IsIdentifier(params.TypeName) {
+		return fmt.Errorf("type_name must be a Go identifier (got: %s)", params.TypeName)
+	}
+	if params.PackageName == "" {
+		params.PackageName = "models"
+	}
+	if !token.IsIdentifier(params.PackageName) {
+		return fmt.Errorf("package_name must be a Go identifier (got: %s)", params.PackageName)
+	}
+
+	switch params.Naming {
+	case "":
+		params.Naming = "go"
+	case "go", "camel":
+	default:
+		return fmt.Errorf("naming must be one of: go, camel (got: %s)", params.Naming)
+	}
+	switch params.Optional {
+	case "":
+		params.Optional = "pointer"
+	case "pointer", "value":
+	default:
+		return fmt.Errorf("optional must be one of: pointer, value (got: %s)", params.Optional)
+	}
+
+	if len(params.Tags) == 0 {
+		params.Tags = []string{params.Format}
+	}
+	var tags []string
+	for _, tag := range params.Tags {
+		if !slices.Contains(tagKeys, tag) {
+			return fmt.Errorf("tags must be among: %s (got: %s)", strings.Join(tagKeys, ", "), tag)
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	params.Tags = tags
+	return nil
+}
+
+// parse infers the shape of a sample document
+func parse(sample, format string) (*shape, error) {
+	if format == "yaml" {
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(sample), &doc); err != nil {
+			return nil, err
+		}
+		return yamlShape(&doc, 0)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(sample))
+	dec.UseNumber()
+	root, err := jsonShape(dec, 0)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("data after the first value")
+	}
+	return root, nil
+}
+
+// decl is a generated struct type
+type decl struct {
+	name string
+	body string
+}
+
+// generator renders shapes as Go struct types
+type generator struct {
+	params      StructGenParams
+	decls       []decl            // In order of reservation, the top-level type first
+	names       map[string]bool   // Reserved type names
+	bodies      map[string]string // Type names by struct body, to reuse identical types
+	usesTime    bool
+	optional    int // Optional fields generated
+	seen        map[string]bool
+	suggestions []string
+}
+
+// suggest adds a suggestion once
+func (g *generator) suggest(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if g.seen == nil {
+		g.seen = make(map[string]bool)
+	}
+	if !g.seen[msg] {
+		g.seen[msg] = true
+		g.suggestions = append(g.suggestions, msg)
+	}
+}
+
+// structType renders an object shape as the struct type name, which must
+// be reserved, and returns the name of the type to use: name, or an
+// identical type declared before
+func (g *generator) structType(name, parent string, s *shape) string {
+	index := len(g.decls)
+	g.decls = append(g.decls, decl{name: name})
+
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	fieldNames := make(map[string]bool)
+	for _, f := range s.fields {
+		if !validTagName(f.key) {
+			g.suggest("Key %q of %s cannot be named in a struct tag and was left out; decode the object into a map[string]any to read it.", f.key, name)
+			continue
+		}
+		fieldName := g.identifier(f.key)
+		for i := 2; fieldNames[fieldName]; i++ {
+			fieldName = fmt.Sprintf("%s%d", g.identifier(f.key), i)
+		}
+		fieldNames[fieldName] = true
+
+		optional := s.optional(f)
+		typ := g.goType(f.shape, name, fieldName, optional)
+		fmt.Fprintf(&b, "\t%s %s `%s`\n", fieldName, typ, g.tag(f.key, optional))
+	}
+	b.WriteString("}")
+	body := b.String()
+
+	// Nested types were reserved after this one; an identical type can
+	// only have been declared before it
+	if existing, ok := g.bodies[body]; ok && parent != "" {
+		g.decls = slices.Delete(g.decls, index, index+1)
+		delete(g.names, name)
+		return existing
+	}
+	g.bodies[body] = name
+	g.decls[index].body = body
+	return name
+}
+
+// goType returns the Go type of values of s, declaring the struct types of
+// objects
+func (g *generator) goType(s *shape, parent, fieldName string, optional bool) string {
+	if s == nil {
+		g.suggest("Field %s.%s was null in every sample, so its type is any; add a value to the sample to infer it.", parent, fieldName)
+		return "any"
+	}
+
+	var typ string
+	switch s.kind {
+	case kindBool:
+		typ = "bool"
+	case kindInt:
+		typ = "int"
+		if s.big {
+			typ = "int64"
+		}
+	case kindFloat:
+		typ = "float64"
+	case kindString:
+		typ = "string"
+	case kindTime:
+		typ = "time.Time"
+		g.usesTime = true
+	case kindObject:
+		if len(s.fields) == 0 {
+			return "map[string]any"
+		}
+		typ = g.structType(g.reserve(parent, fieldName), parent, s)
+	case kindArray:
+		if s.elem == nil {
+			g.suggest("Field %s.%s was an empty array in every sample, so its type is []any; add an element to the sample to infer it.", parent, fieldName)
+			return "[]any"
+		}
+		return "[]" + g.goType(s.elem, parent, singular(fieldName), false)
+	default:
+		g.suggest("Field %s.%s holds values of different types, so its type is any.", parent, fieldName)
+		return "any"
+	}
+
+	if optional {
+		g.optional++
+		if g.params.Optional == "pointer" {
+			return "*" + typ
+		}
+	}
+	return typ
+}
+
+// reserve returns an unused type name for an object field, prefixing the
+// parent type name when the field name is taken
+func (g *generator) reserve(parent, fieldName string) string {
+	name := fieldName
+	if g.names[name] {
+		name = parent + fieldName
+	}
+	base := name
+	for i := 2; g.names[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.names[name] = true
+	return name
+}
+
+// tag returns the struct tag of a field
+func (g *generator) tag(key string, optional bool) string {
+	value := key
+	if optional {
+		value += ",omitempty"
+	}
+	tags := make([]string, len(g.params.Tags))
+	for i, t := range g.params.Tags {
+		tags[i] = t + ":" + strconv.Quote(value)
+	}
+	return strings.Join(tags, " ")
+}
+
+// identifier returns the exported Go name of a sample key under the
+// naming strategy
+func (g *generator) identifier(key string) string {
+	if name := naming.Exported(key, g.params.Naming == "go"); name != "" {
+		return name
+	}
+	return "Field"
+}
+
+// singular returns the name of the elements of an array field
+func singular(name string) string {
+	if one, ok := naming.Singular(name); ok {
+		return one
+	}
+	return name + "Item"
+}
+
+// validTagName reports whether encoding/json accepts key as a tag name
+func validTagName(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", r):
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// file renders the declarations as a formatted Go file
+func (g *generator) file() (string, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "package %s\n\n", g.params.PackageName)
+	if g.usesTime {
+		b.WriteString("import \"time\"\n\n")
+	}
+	for _, d := range g.decls {
+		fmt.Fprintf(&b, "type %s %s\n\n", d.name, d.body)
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return string(src), nil
+}
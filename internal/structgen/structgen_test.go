@@ -0,0 +1,212 @@
+package structgen
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	sample := `{
+  "id": 42,
+  "userName": "gopher",
+  "homepageURL": "https://go.dev",
+  "created_at": "2024-05-01T12:00:00Z",
+  "score": 9.5,
+  "big": 10000000000,
+  "tags": ["a", "b"],
+  "address": {"street": "Main", "zip": "12345"},
+  "billing": {"street": "Side", "zip": "54321"},
+  "orders": [
+    {"id": 1, "total": 10, "note": null},
+    {"id": 2, "total": 12.5, "coupon": "SAVE"}
+  ],
+  "meta": {},
+  "extra": null,
+  "empty": []
+}`
+	result, err := Generate(context.Background(), StructGenParams{Sample: sample, TypeName: "User"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := `package models
+
+import "time"
+
+type User struct {
+	ID          int            ` + "`json:\"id\"`" + `
+	UserName    string         ` + "`json:\"userName\"`" + `
+	HomepageURL string         ` + "`json:\"homepageURL\"`" + `
+	CreatedAt   time.Time      ` + "`json:\"created_at\"`" + `
+	Score       float64        ` + "`json:\"score\"`" + `
+	Big         int64          ` + "`json:\"big\"`" + `
+	Tags        []string       ` + "`json:\"tags\"`" + `
+	Address     Address        ` + "`json:\"address\"`" + `
+	Billing     Address        ` + "`json:\"billing\"`" + `
+	Orders      []Order        ` + "`json:\"orders\"`" + `
+	Meta        map[string]any ` + "`json:\"meta\"`" + `
+	Extra       any            ` + "`json:\"extra,omitempty\"`" + `
+	Empty       []any          ` + "`json:\"empty\"`" + `
+}
+
+type Address struct {
+	Street string ` + "`json:\"street\"`" + `
+	Zip    string ` + "`json:\"zip\"`" + `
+}
+
+type Order struct {
+	ID     int     ` + "`json:\"id\"`" + `
+	Total  float64 ` + "`json:\"total\"`" + `
+	Note   any     ` + "`json:\"note,omitempty\"`" + `
+	Coupon *string ` + "`json:\"coupon,omitempty\"`" + `
+}
+`
+	if result.Code != want {
+		t.Errorf("unexpected code:\n%s\nwant:\n%s", result.Code, want)
+	}
+	if got := strings.Join(result.Types, ","); got != "User,Address,Order" {
+		t.Errorf("unexpected types %s", got)
+	}
+	if result.Format != "json" {
+		t.Errorf("expected the json format to be detected, got %s", result.Format)
+	}
+
+	for _, s := range []string{"Field User.Extra was null", "Field User.Empty was an empty array", "1 fields were null or missing"} {
+		found := false
+		for _, got := range result.Suggestions {
+			found = found || strings.Contains(got, s)
+		}
+		if !found {
+			t.Errorf("expected a suggestion containing %q, got %v", s, result.Suggestions)
+		}
+	}
+}
+
+func TestGenerate_YAML(t *testing.T) {
+	sample := `
+defaults: &defaults
+  timeout: 30
+  retry_count: 3
+servers:
+  - name: api
+    api_key: abc
+    started: 2024-05-01
+    <<: *defaults
+  - name: web
+    port: 8080
+    <<: *defaults
+`
+	result, err := Generate(context.Background(), StructGenParams{
+		Sample:      sample,
+		TypeName:    "Config",
+		PackageName: "config",
+		Naming:      "camel",
+		Tags:        []string{"yaml", "json"},
+		Optional:    "value",
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := `package config
+
+import "time"
+
+type Config struct {
+	Defaults Defaults ` + "`yaml:\"defaults\" json:\"defaults\"`" + `
+	Servers  []Server ` + "`yaml:\"servers\" json:\"servers\"`" + `
+}
+
+type Defaults struct {
+	Timeout    int ` + "`yaml:\"timeout\" json:\"timeout\"`" + `
+	RetryCount int ` + "`yaml:\"retry_count\" json:\"retry_count\"`" + `
+}
+
+type Server struct {
+	Name       string    ` + "`yaml:\"name\" json:\"name\"`" + `
+	ApiKey     string    ` + "`yaml:\"api_key,omitempty\" json:\"api_key,omitempty\"`" + `
+	Started    time.Time ` + "`yaml:\"started,omitempty\" json:\"started,omitempty\"`" + `
+	Timeout    int       ` + "`yaml:\"timeout\" json:\"timeout\"`" + `
+	RetryCount int       ` + "`yaml:\"retry_count\" json:\"retry_count\"`" + `
+	Port       int       ` + "`yaml:\"port,omitempty\" json:\"port,omitempty\"`" + `
+}
+`
+	if result.Code != want {
+		t.Errorf("unexpected code:\n%s\nwant:\n%s", result.Code, want)
+	}
+	if result.Format != "yaml" {
+		t.Errorf("expected the yaml format to be detected, got %s", result.Format)
+	}
+}
+
+func TestGenerate_RootArray(t *testing.T) {
+	result, err := Generate(context.Background(), StructGenParams{Sample: `[{"a": 1}, {"a": 2.5, "b": [1, "x"]}]`})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := "type Root struct {\n\tA float64 `json:\"a\"`\n\tB []any   `json:\"b,omitempty\"`\n}\n"
+	if !strings.HasSuffix(result.Code, want) {
+		t.Errorf("unexpected code:\n%s\nwant suffix:\n%s", result.Code, want)
+	}
+	if len(result.Suggestions) == 0 || !strings.Contains(result.Suggestions[0], "decode it into []Root") {
+		t.Errorf("expected a suggestion on the root array, got %v", result.Suggestions)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		key    string
+		naming string
+		want   string
+	}{
+		{"user_id", "go", "UserID"},
+		{"user_id", "camel", "UserId"},
+		{"HTTPServer", "go", "HTTPServer"},
+		{"HTTPServer", "camel", "HttpServer"},
+		{"apiURLs", "go", "APIURLs"},
+		{"apiURLs", "camel", "ApiUrls"},
+		{"first-name", "go", "FirstName"},
+		{"2fa", "go", "X2fa"},
+		{"---", "go", "Field"},
+		{"size_v2", "go", "SizeV2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"/"+tt.naming, func(t *testing.T) {
+			g := &generator{params: StructGenParams{Naming: tt.naming}}
+			if got := g.identifier(tt.key); got != tt.want {
+				t.Errorf("identifier(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  StructGenParams
+		wantErr string
+	}{
+		{"no sample", StructGenParams{}, "sample parameter is required"},
+		{"format", StructGenParams{Sample: "{}", Format: "xml"}, "format must be one of"},
+		{"type name", StructGenParams{Sample: "{}", TypeName: "my type"}, "type_name must be a Go identifier"},
+		{"naming", StructGenParams{Sample: "{}", Naming: "snake"}, "naming must be one of"},
+		{"optional", StructGenParams{Sample: "{}", Optional: "null"}, "optional must be one of"},
+		{"tags", StructGenParams{Sample: "{}", Tags: []string{"xml"}}, "tags must be among"},
+		{"invalid json", StructGenParams{Sample: `{"a": }`}, "failed to parse json sample"},
+		{"trailing data", StructGenParams{Sample: `{} {}`}, "data after the first value"},
+		{"scalar", StructGenParams{Sample: `42`, Format: "json"}, "sample must be an object or an array of objects"},
+		{"scalar array", StructGenParams{Sample: `[1, 2]`}, "sample must be an object or an array of objects"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(context.Background(), tt.params)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
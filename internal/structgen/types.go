@@ -0,0 +1,32 @@
+package structgen
+
+import (
+	"encoding/json"
+)
+
+// StructGenParams represents the parameters for the sample-to-struct tool
+type StructGenParams struct {
+	Sample         string   `json:"sample" jsonschema:"description:JSON or YAML sample document, an object or an array of objects"`
+	Format         string   `json:"format,omitempty" jsonschema:"description:Optional sample format: 'json', 'yaml' or 'auto' to detect it (defaults to 'auto')"`
+	TypeName       string   `json:"type_name,omitempty" jsonschema:"description:Optional name of the top-level type (defaults to 'Root')"`
+	PackageName    string   `json:"package_name,omitempty" jsonschema:"description:Optional package clause of the generated code (defaults to 'models')"`
+	Naming         string   `json:"naming,omitempty" jsonschema:"description:Optional field naming strategy: 'go' upper-cases initialisms such as ID and URL, 'camel' capitalizes only the first letter of each word (defaults to 'go')"`
+	Tags           []string `json:"tags,omitempty" jsonschema:"description:Optional struct tag keys to emit with the sample keys, among json, yaml, toml and mapstructure (defaults to the sample format)"`
+	Optional       string   `json:"optional,omitempty" jsonschema:"description:Optional representation of fields that are null or missing from some array elements: 'pointer' or 'value', both with omitempty (defaults to 'pointer')"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+}
+
+// StructGenResult holds the Go type definitions inferred from a sample
+type StructGenResult struct {
+	Summary     string   `json:"summary"`
+	Format      string   `json:"format"` // The sample format, detected or given
+	Code        string   `json:"code"`   // A formatted Go file
+	Types       []string `json:"types"`  // Declared type names, the top-level type first
+	Suggestions []string `json:"suggestions"`
+}
+
+// String returns a formatted JSON string of the StructGenResult
+func (r *StructGenResult) String() string {
+	jsonData, _ := json.MarshalIndent(r, "", "  ")
+	return string(jsonData)
+}
@@ -0,0 +1,30 @@
+package testgen
+
+import (
+	"context"
+	"testing"
+
+	"mcp-go-assistant/internal/testutil"
+)
+
+func BenchmarkGenerateTests(b *testing.B) {
+	for _, corpus := range []struct {
+		name      string
+		functions int
+	}{
+		{"small", testutil.CorpusSmall},
+		{"medium", testutil.CorpusMedium},
+	} {
+		code := testutil.GoSource(corpus.functions)
+		for _, focus := range []string{"unit", "table", "interfaces"} {
+			b.Run(corpus.name+"/"+focus, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := GenerateTests(context.Background(), TestGenParams{GoCode: code, Focus: focus}); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
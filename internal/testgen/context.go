@@ -0,0 +1,126 @@
+package testgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/version"
+	"strings"
+)
+
+// contextGoVersion is the first Go version with testing.T.Context
+const contextGoVersion = "go1.24"
+
+// isContext reports whether expr is the type Context of the context
+// package, under whatever name the source file imports it as
+func (f *typeFormatter) isContext(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Context" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	spec, imported := f.imports[pkg.Name]
+	if !imported {
+		// Packages the source does not import are assumed to be in the
+		// standard library, as in use
+		return pkg.Name == "context"
+	}
+	return strings.HasSuffix(spec, `"context"`)
+}
+
+// takesContext reports whether the parameters fl include a context.Context
+func (f *typeFormatter) takesContext(fl *ast.FieldList) bool {
+	for _, field := range fieldsOf(fl) {
+		if f.isContext(field.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// baseContext returns the context generated tests derive theirs from:
+// t.Context() when the target Go version has it, else context.Background()
+func (l *layout) baseContext(f *typeFormatter) string {
+	if l.goVersion != "" && version.Compare(l.goVersion, contextGoVersion) >= 0 {
+		return "t.Context()"
+	}
+	f.addImport(`"context"`)
+	return "context.Background()"
+}
+
+// writeTimeoutContext writes the declaration of ctx, the context with a
+// timeout that tests pass to functions accepting one
+func (l *layout) writeTimeoutContext(sb *strings.Builder, indent string, f *typeFormatter) {
+	base := l.baseContext(f)
+	f.addImport(`"context"`)
+	f.addImport(`"time"`)
+	sb.WriteString(fmt.Sprintf("%sctx, cancel := context.WithTimeout(%s, 5*time.Second)\n", indent, base))
+	sb.WriteString(indent + "defer cancel()\n")
+}
+
+// writeCanceledTest writes a test calling fd with a canceled context and
+// checking that it returns context.Canceled. Functions without a single
+// error result get a TODO instead of the call.
+func (l *layout) writeCanceledTest(sb *strings.Builder, fd *ast.FuncDecl, recv *receiver, f *typeFormatter) {
+	sb.WriteString(fmt.Sprintf("func %s_ContextCanceled(t *testing.T) {\n", testName(fd, l.style.Naming)))
+	recvName := ""
+	if recv != nil {
+		recvName = l.writeReceiver(sb, "\t", fd, recv, f)
+	}
+	base := l.baseContext(f)
+	f.addImport(`"context"`)
+	sb.WriteString(fmt.Sprintf("\tctx, cancel := context.WithCancel(%s)\n", base))
+	sb.WriteString("\tcancel()\n\n")
+
+	var results []string
+	errs := 0
+	for _, field := range fieldsOf(fd.Type.Results) {
+		isErr := f.typ(field.Type) == "error"
+		for i := 0; i < max(1, len(field.Names)); i++ {
+			if isErr {
+				errs++
+				results = append(results, "err")
+			} else {
+				results = append(results, "_")
+			}
+		}
+	}
+	if errs != 1 {
+		sb.WriteString(fmt.Sprintf("\t// TODO: Call %s with ctx and verify that it stops early\n", fd.Name.Name))
+		sb.WriteString("\t_ = ctx\n")
+		if recvName != "" {
+			sb.WriteString(fmt.Sprintf("\t_ = %s\n", recvName))
+		}
+		sb.WriteString("}\n\n")
+		return
+	}
+
+	fn := recvName + "." + fd.Name.Name
+	if fd.Type.TypeParams != nil {
+		tparams := l.typeParams(fd.Type.TypeParams, f)
+		sb.WriteString(instantiationHint("\t", fd.Name.Name, tparams))
+		fn = instantiation(f.function(fd.Name.Name), tparams)
+		f.typeParams = substitutions(tparams)
+	} else if recv != nil {
+		f.typeParams = recv.substitutions(fd)
+	} else {
+		fn = f.function(fd.Name.Name)
+	}
+	args := writeArgs(sb, "\t", fd.Name.Name, fd.Type.Params, recvName, "ctx", f)
+	f.typeParams = nil
+
+	sb.WriteString(fmt.Sprintf("\t%s := %s(%s)\n", strings.Join(results, ", "), fn, strings.Join(args, ", ")))
+	if l.style.Assert == AssertTestify {
+		f.addImport(testifyRequire)
+		sb.WriteString("\trequire.ErrorIs(t, err, context.Canceled)\n")
+	} else {
+		name, _, _ := strings.Cut(fn, "[")
+		f.addImport(`"errors"`)
+		sb.WriteString("\tif !errors.Is(err, context.Canceled) {\n")
+		sb.WriteString(fmt.Sprintf("\t\tt.Errorf(\"%s() error = %%v, want %%v\", err, context.Canceled)\n", name))
+		sb.WriteString("\t}\n")
+	}
+	sb.WriteString("}\n\n")
+}
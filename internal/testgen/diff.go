@@ -0,0 +1,239 @@
+package testgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mcp-go-assistant/internal/types"
+)
+
+// diffContextLines is the number of unchanged lines around each hunk
+const diffContextLines = 3
+
+// TestDiff describes what generated code adds to existing tests
+type TestDiff struct {
+	Added   []string `json:"added"`             // Generated declarations missing from the existing tests
+	Skipped []string `json:"skipped,omitempty"` // Generated declarations the existing tests already define
+	Imports []string `json:"imports,omitempty"` // Import paths missing from the existing tests
+	Patch   string   `json:"patch,omitempty"`   // Unified diff adding the missing code; empty when nothing is missing
+}
+
+// diffExistingTests compares the generated code in result with existing
+// test code and returns the additions as a unified diff against file
+func diffExistingTests(existing, file string, result *TestGenResult) (*TestDiff, error) {
+	fset := token.NewFileSet()
+	existingFile, err := parser.ParseFile(fset, "", existing, parser.ParseComments)
+	if err != nil {
+		return nil, types.NewKindError(types.KindParseFailure, "failed to parse existing_tests: %v", err)
+	}
+
+	defined := make(map[string]bool)
+	imported := make(map[string]bool)
+	for _, decl := range existingFile.Decls {
+		for _, name := range declNames(decl) {
+			defined[name] = true
+		}
+	}
+	for _, spec := range existingFile.Imports {
+		imported[importPath(spec)] = true
+	}
+
+	diff := &TestDiff{Added: []string{}}
+	var additions []string
+	for _, src := range []string{result.TestCode, result.MockCode} {
+		if src == "" {
+			continue
+		}
+		genFset := token.NewFileSet()
+		generated, err := parser.ParseFile(genFset, "", src, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse generated code: %v", err)
+		}
+
+		for _, spec := range generated.Imports {
+			if path := importPath(spec); !imported[path] {
+				imported[path] = true
+				diff.Imports = append(diff.Imports, path)
+			}
+		}
+
+		for _, decl := range generated.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+				continue
+			}
+			names := declNames(decl)
+			if allDefined(names, defined) {
+				diff.Skipped = append(diff.Skipped, names...)
+				continue
+			}
+			diff.Added = append(diff.Added, names...)
+			additions = append(additions, declSource(genFset, src, decl))
+		}
+	}
+
+	if len(additions) == 0 && len(diff.Imports) == 0 {
+		return diff, nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(existing, "\n"), "\n")
+	inserts := importInserts(fset, existingFile, diff.Imports)
+	for _, code := range additions {
+		inserts[len(lines)] = append(inserts[len(lines)], "")
+		inserts[len(lines)] = append(inserts[len(lines)], strings.Split(code, "\n")...)
+	}
+	diff.Patch = unifiedDiff(file, lines, inserts)
+
+	return diff, nil
+}
+
+// declNames returns the names a top-level declaration defines. Methods are
+// named Type.Method.
+func declNames(decl ast.Decl) []string {
+	var names []string
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		name := d.Name.Name
+		if d.Recv != nil && len(d.Recv.List) > 0 {
+			name = getReceiverTypeName(d.Recv.List[0].Type) + "." + name
+		}
+		names = append(names, name)
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, s.Name.Name)
+			case *ast.ValueSpec:
+				for _, ident := range s.Names {
+					names = append(names, ident.Name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// allDefined reports whether every name is already defined
+func allDefined(names []string, defined map[string]bool) bool {
+	for _, name := range names {
+		if !defined[name] {
+			return false
+		}
+	}
+	return len(names) > 0
+}
+
+// declSource returns the source of decl including its doc comment
+func declSource(fset *token.FileSet, src string, decl ast.Decl) string {
+	start := decl.Pos()
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Doc != nil {
+			start = d.Doc.Pos()
+		}
+	case *ast.GenDecl:
+		if d.Doc != nil {
+			start = d.Doc.Pos()
+		}
+	}
+	return src[fset.Position(start).Offset:fset.Position(decl.End()).Offset]
+}
+
+// importPath returns the unquoted path of an import spec
+func importPath(spec *ast.ImportSpec) string {
+	path, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		return spec.Path.Value
+	}
+	return path
+}
+
+// importInserts returns the lines to insert into the existing file for the
+// missing imports, keyed by the zero-based line they are inserted before
+func importInserts(fset *token.FileSet, file *ast.File, paths []string) map[int][]string {
+	inserts := make(map[int][]string)
+	if len(paths) == 0 {
+		return inserts
+	}
+
+	var last *ast.GenDecl
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			last = gen
+		}
+	}
+
+	switch {
+	case last != nil && last.Lparen.IsValid():
+		// Add to the import block before its closing parenthesis
+		line := fset.Position(last.Rparen).Line - 1
+		for _, path := range paths {
+			inserts[line] = append(inserts[line], "\t"+strconv.Quote(path))
+		}
+	case last != nil:
+		line := fset.Position(last.End()).Line
+		for _, path := range paths {
+			inserts[line] = append(inserts[line], "import "+strconv.Quote(path))
+		}
+	default:
+		line := fset.Position(file.Name.End()).Line
+		inserts[line] = append(inserts[line], "", "import (")
+		for _, path := range paths {
+			inserts[line] = append(inserts[line], "\t"+strconv.Quote(path))
+		}
+		inserts[line] = append(inserts[line], ")")
+	}
+	return inserts
+}
+
+// unifiedDiff renders the insertion of lines into old as a unified diff.
+// inserts maps a zero-based line index to the lines inserted before it; an
+// index of len(old) appends to the end.
+func unifiedDiff(file string, old []string, inserts map[int][]string) string {
+	points := make([]int, 0, len(inserts))
+	for p, lines := range inserts {
+		if len(lines) > 0 {
+			points = append(points, p)
+		}
+	}
+	if len(points) == 0 {
+		return ""
+	}
+	sort.Ints(points)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", file, file)
+
+	added := 0 // Lines inserted by previous hunks
+	for i := 0; i < len(points); {
+		// Merge insertion points whose context overlaps into one hunk
+		j := i
+		for j+1 < len(points) && points[j+1]-diffContextLines <= points[j]+diffContextLines {
+			j++
+		}
+		start := max(0, points[i]-diffContextLines)
+		end := min(len(old), points[j]+diffContextLines)
+
+		var body strings.Builder
+		inserted := 0
+		for line := start; line <= end; line++ {
+			for _, l := range inserts[line] {
+				body.WriteString("+" + l + "\n")
+				inserted++
+			}
+			if line < end {
+				body.WriteString(" " + old[line] + "\n")
+			}
+		}
+
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", start+1, end-start, start+1+added, end-start+inserted)
+		sb.WriteString(body.String())
+		added += inserted
+		i = j + 1
+	}
+	return sb.String()
+}
@@ -0,0 +1,189 @@
+package testgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// maxConstraintDepth bounds how far named constraints are followed when
+// choosing type arguments, so invalid cyclic constraints terminate
+const maxConstraintDepth = 8
+
+// typeParam is a type parameter of generic source code and the type
+// argument generated tests instantiate it with
+type typeParam struct {
+	name       string
+	constraint ast.Expr
+	arg        string // Type argument satisfying the constraint
+	certain    bool   // Whether arg is known to satisfy the constraint
+}
+
+// typeParams returns the type parameters declared by fl with the type
+// arguments tests use for them, formatted with f
+func (l *layout) typeParams(fl *ast.FieldList, f *typeFormatter) []typeParam {
+	var params []typeParam
+	for _, field := range fieldsOf(fl) {
+		arg, certain := l.typeArg(field.Type, f, 0)
+		for _, name := range field.Names {
+			params = append(params, typeParam{name: name.Name, constraint: field.Type, arg: arg, certain: certain})
+		}
+	}
+	return params
+}
+
+// typeArg returns a type satisfying constraint. Unions yield their first
+// term, well-known constraints a matching predeclared type, and everything
+// else int, which certain reports may not satisfy the constraint.
+func (l *layout) typeArg(constraint ast.Expr, f *typeFormatter, depth int) (arg string, certain bool) {
+	if depth > maxConstraintDepth {
+		return "int", false
+	}
+
+	switch t := constraint.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "any", "comparable":
+			return "int", true
+		}
+		if named, ok := l.constraints[t.Name]; ok {
+			return l.typeArg(named, f, depth+1)
+		}
+		if _, predeclared := types.Universe.Lookup(t.Name).(*types.TypeName); predeclared || l.local[t.Name] {
+			// A non-interface type is its own only type argument
+			return f.typ(t), true
+		}
+		// Constraints declared in other files of the package are unknown
+		return "int", false
+	case *ast.SelectorExpr:
+		// cmp.Ordered and the golang.org/x/exp/constraints package
+		switch t.Sel.Name {
+		case "Ordered", "Integer", "Signed":
+			return "int", true
+		case "Unsigned":
+			return "uint", true
+		case "Float":
+			return "float64", true
+		case "Complex":
+			return "complex128", true
+		}
+		return "int", false
+	case *ast.ParenExpr:
+		return l.typeArg(t.X, f, depth+1)
+	case *ast.UnaryExpr:
+		if t.Op == token.TILDE {
+			return f.typ(t.X), true
+		}
+	case *ast.BinaryExpr:
+		if t.Op == token.OR {
+			return l.typeArg(t.X, f, depth+1)
+		}
+	case *ast.InterfaceType:
+		arg, certain = "int", true
+		for _, field := range t.Methods.List {
+			if len(field.Names) > 0 {
+				// Method sets are not satisfied by predeclared types
+				certain = false
+				continue
+			}
+			if embedded, ok := l.typeArg(field.Type, f, depth+1); ok {
+				arg = embedded
+			} else {
+				certain = false
+			}
+		}
+		return arg, certain
+	}
+	return "int", false
+}
+
+// receiverTypeParams maps the type parameter names of a method receiver
+// such as Pair[A, B] to the names declared by the receiver's type
+func receiverTypeParams(recv ast.Expr, declared []typeParam) map[string]string {
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+
+	var indices []ast.Expr
+	switch t := recv.(type) {
+	case *ast.IndexExpr:
+		indices = []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		indices = t.Indices
+	default:
+		return nil
+	}
+
+	renames := make(map[string]string, len(indices))
+	for i, index := range indices {
+		if name, ok := index.(*ast.Ident); ok && i < len(declared) {
+			renames[name.Name] = declared[i].name
+		}
+	}
+	return renames
+}
+
+// substitutions maps each type parameter name to its type argument
+func substitutions(params []typeParam) map[string]string {
+	subst := make(map[string]string, len(params))
+	for _, p := range params {
+		subst[p.name] = p.arg
+	}
+	return subst
+}
+
+// declaration formats a type parameter list, e.g. "[K comparable, V any]",
+// with f
+func declaration(params []typeParam, f *typeFormatter) string {
+	if len(params) == 0 {
+		return ""
+	}
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = p.name + " " + f.typ(p.constraint)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// names formats the type parameter names as type arguments, e.g. "[K, V]"
+func names(params []typeParam) string {
+	if len(params) == 0 {
+		return ""
+	}
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = p.name
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// instantiation formats name instantiated with the type arguments tests
+// use, e.g. "Map[string, int]"
+func instantiation(name string, params []typeParam) string {
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = p.arg
+	}
+	return name + "[" + strings.Join(parts, ", ") + "]"
+}
+
+// instantiationHint returns a TODO comment line on instantiating the
+// generic declaration name, naming the type arguments tests use and the
+// constraints any others must satisfy
+func instantiationHint(indent, name string, params []typeParam) string {
+	constraints := make([]string, len(params))
+	certain := true
+	for i, p := range params {
+		constraints[i] = p.name + " " + types.ExprString(p.constraint)
+		certain = certain && p.certain
+	}
+
+	if certain {
+		return fmt.Sprintf("%s// TODO: Instantiate as %s or with other type arguments satisfying [%s]\n",
+			indent, instantiation(name, params), strings.Join(constraints, ", "))
+	}
+	return fmt.Sprintf("%s// TODO: Instantiate with type arguments satisfying [%s]; %s is a placeholder\n",
+		indent, strings.Join(constraints, ", "), instantiation(name, params))
+}
@@ -0,0 +1,347 @@
+package testgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// layout describes the packages generated code is emitted into
+type layout struct {
+	source        string              // Source package name
+	importPath    string              // Import path of the source package, if known
+	testPkg       string              // Package of the generated tests
+	mockPkg       string              // Package of the generated mocks
+	local         map[string]bool     // Types declared in the source package
+	constraints   map[string]ast.Expr // Interface types declared in the source package, usable as constraints
+	imports       map[string]string   // Import specs of the source file by package name
+	importAssumed bool                // Whether the source was imported without a known import path
+
+	receiverConstructor string // Constructor for method receivers named by the caller
+	style               Style  // Naming, layout and assertion style, with defaults applied
+	goVersion           string // Target Go version in go/version form, e.g. "go1.24", if known
+}
+
+// newLayout returns the layout for generating tests into testPkg
+func newLayout(file *ast.File, params TestGenParams, testPkg string) *layout {
+	l := &layout{
+		source:      file.Name.Name,
+		importPath:  params.ImportPath,
+		testPkg:     testPkg,
+		mockPkg:     testPkg,
+		local:       make(map[string]bool),
+		constraints: make(map[string]ast.Expr),
+		imports:     make(map[string]string),
+	}
+	if params.MockPackage != "" {
+		l.mockPkg = params.MockPackage
+	}
+	l.receiverConstructor = params.ReceiverConstructor
+	l.style = params.Style.WithDefaults(DefaultStyle())
+	if params.GoVersion != "" {
+		l.goVersion = "go" + strings.TrimPrefix(params.GoVersion, "go")
+	}
+
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		switch {
+		case spec.Name == nil:
+			l.imports[importName(importPath)] = spec.Path.Value
+		case spec.Name.Name != "_" && spec.Name.Name != ".":
+			l.imports[spec.Name.Name] = spec.Name.Name + " " + spec.Path.Value
+		}
+	}
+
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok {
+			for _, spec := range gen.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					l.local[ts.Name.Name] = true
+					if iface, ok := ts.Type.(*ast.InterfaceType); ok {
+						l.constraints[ts.Name.Name] = iface
+					}
+				}
+			}
+		}
+	}
+	return l
+}
+
+// formatter returns a type formatter for code in pkg, qualifying the source
+// package's types unless pkg is the source package
+func (l *layout) formatter(pkg string) *typeFormatter {
+	if pkg == l.source {
+		return &typeFormatter{imports: l.imports}
+	}
+	return &typeFormatter{qualifier: l.source, local: l.local, imports: l.imports}
+}
+
+// header returns the package clause and import block of a generated file in
+// pkg. The source package is imported when types qualified any of its types.
+func (l *layout) header(pkg string, types *typeFormatter, imports ...string) string {
+	if types.qualified {
+		imports = append(imports, l.sourceImport())
+	}
+	for spec := range types.used {
+		imports = append(imports, spec)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("package %s\n\n", pkg))
+	if len(imports) == 0 {
+		return sb.String()
+	}
+
+	// Sort by path as gofmt does, ignoring import names
+	sort.Slice(imports, func(i, j int) bool {
+		return imports[i][strings.Index(imports[i], `"`):] < imports[j][strings.Index(imports[j], `"`):]
+	})
+	sb.WriteString("import (\n")
+	for i, spec := range imports {
+		if i > 0 && spec == imports[i-1] {
+			continue
+		}
+		sb.WriteString("\t" + spec + "\n")
+	}
+	sb.WriteString(")\n\n")
+	return sb.String()
+}
+
+// sourceImport returns the import spec of the source package, naming it
+// when the last path element differs from the package name
+func (l *layout) sourceImport() string {
+	importPath := l.importPath
+	if importPath == "" {
+		importPath = l.source
+		l.importAssumed = true
+	}
+	if path.Base(importPath) == l.source {
+		return strconv.Quote(importPath)
+	}
+	return l.source + " " + strconv.Quote(importPath)
+}
+
+// typeFormatter formats types from the source file. For code generated in
+// another package, types declared in the source package are qualified with
+// its name. The imports of referenced packages are recorded for the header.
+type typeFormatter struct {
+	qualifier string            // Source package name; empty within the source package
+	local     map[string]bool   // Types declared in the source package
+	imports   map[string]string // Import specs of the source file by package name
+	used      map[string]bool   // Import specs of packages referenced by formatted types
+	qualified bool              // Whether a qualified type was formatted
+
+	// Type parameter names and the types or names substituted for them
+	typeParams map[string]string
+}
+
+// fieldList formats a parameter or result list
+func (f *typeFormatter) fieldList(fl *ast.FieldList) string {
+	if fl == nil || len(fl.List) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, field := range fl.List {
+		typeStr := f.typ(field.Type)
+		if len(field.Names) == 0 {
+			parts = append(parts, typeStr)
+		} else {
+			for _, name := range field.Names {
+				parts = append(parts, fmt.Sprintf("%s %s", name.Name, typeStr))
+			}
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// params formats a parameter list for a method that forwards its arguments,
+// naming unnamed and blank parameters. It returns the parameter
+// declarations and the arguments to forward them with.
+func (f *typeFormatter) params(fl *ast.FieldList) (decl, args string) {
+	if fl == nil {
+		return "", ""
+	}
+
+	var decls, names []string
+	for _, field := range fl.List {
+		typeStr := f.typ(field.Type)
+		fieldNames := field.Names
+		if len(fieldNames) == 0 {
+			fieldNames = []*ast.Ident{{Name: "_"}}
+		}
+		for _, ident := range fieldNames {
+			name := ident.Name
+			if name == "_" {
+				name = fmt.Sprintf("p%d", len(names))
+			}
+			decls = append(decls, name+" "+typeStr)
+			if _, variadic := field.Type.(*ast.Ellipsis); variadic {
+				name += "..."
+			}
+			names = append(names, name)
+		}
+	}
+	return strings.Join(decls, ", "), strings.Join(names, ", ")
+}
+
+// typ formats a type expression
+func (f *typeFormatter) typ(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if arg, ok := f.typeParams[t.Name]; ok {
+			return arg
+		}
+		if f.qualifier != "" && f.local[t.Name] {
+			f.qualified = true
+			return f.qualifier + "." + t.Name
+		}
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + f.typ(t.X)
+	case *ast.ParenExpr:
+		return "(" + f.typ(t.X) + ")"
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			f.use(pkg.Name)
+			return pkg.Name + "." + t.Sel.Name
+		}
+		return f.typ(t.X) + "." + t.Sel.Name
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "[]" + f.typ(t.Elt)
+		}
+		return "[" + types.ExprString(t.Len) + "]" + f.typ(t.Elt)
+	case *ast.Ellipsis:
+		return "..." + f.typ(t.Elt)
+	case *ast.MapType:
+		return fmt.Sprintf("map[%s]%s", f.typ(t.Key), f.typ(t.Value))
+	case *ast.FuncType:
+		results := f.fieldList(t.Results)
+		if results != "" {
+			results = " " + resultList(results)
+		}
+		return "func(" + f.fieldList(t.Params) + ")" + results
+	case *ast.ChanType:
+		switch t.Dir {
+		case ast.SEND:
+			return "chan<- " + f.typ(t.Value)
+		case ast.RECV:
+			return "<-chan " + f.typ(t.Value)
+		}
+		return "chan " + f.typ(t.Value)
+	case *ast.IndexExpr:
+		return f.typ(t.X) + "[" + f.typ(t.Index) + "]"
+	case *ast.IndexListExpr:
+		args := make([]string, len(t.Indices))
+		for i, index := range t.Indices {
+			args[i] = f.typ(index)
+		}
+		return f.typ(t.X) + "[" + strings.Join(args, ", ") + "]"
+	case *ast.BinaryExpr:
+		// Union of constraint terms
+		return f.typ(t.X) + " " + t.Op.String() + " " + f.typ(t.Y)
+	case *ast.UnaryExpr:
+		// Underlying type term of a constraint
+		return t.Op.String() + f.typ(t.X)
+	default:
+		// Struct and interface literals are rendered as written
+		ast.Inspect(expr, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if pkg, ok := sel.X.(*ast.Ident); ok {
+					f.use(pkg.Name)
+				}
+			}
+			return true
+		})
+		return types.ExprString(expr)
+	}
+}
+
+// function formats a reference to a function declared in the source
+// package
+func (f *typeFormatter) function(name string) string {
+	if f.qualifier == "" {
+		return name
+	}
+	f.qualified = true
+	return f.qualifier + "." + name
+}
+
+// use records the import of the package the source file refers to as name.
+// Packages the source does not import are assumed to be in the standard
+// library.
+func (f *typeFormatter) use(name string) {
+	spec, ok := f.imports[name]
+	if !ok {
+		importPath, known := stdlibPaths[name]
+		if !known {
+			importPath = name
+		}
+		spec = strconv.Quote(importPath)
+	}
+	f.addImport(spec)
+}
+
+// addImport records an import spec needed by generated code other than
+// types, such as an assertion package
+func (f *typeFormatter) addImport(spec string) {
+	if f.used == nil {
+		f.used = make(map[string]bool)
+	}
+	f.used[spec] = true
+}
+
+// stdlibPaths maps standard library package names to their import paths
+// where the two differ
+var stdlibPaths = map[string]string{
+	"atomic":   "sync/atomic",
+	"base64":   "encoding/base64",
+	"big":      "math/big",
+	"bits":     "math/bits",
+	"csv":      "encoding/csv",
+	"debug":    "runtime/debug",
+	"exec":     "os/exec",
+	"filepath": "path/filepath",
+	"fs":       "io/fs",
+	"heap":     "container/heap",
+	"hex":      "encoding/hex",
+	"http":     "net/http",
+	"httptest": "net/http/httptest",
+	"ioutil":   "io/ioutil",
+	"json":     "encoding/json",
+	"list":     "container/list",
+	"rand":     "math/rand",
+	"sha256":   "crypto/sha256",
+	"signal":   "os/signal",
+	"slog":     "log/slog",
+	"sql":      "database/sql",
+	"template": "text/template",
+	"tls":      "crypto/tls",
+	"url":      "net/url",
+	"utf8":     "unicode/utf8",
+	"xml":      "encoding/xml",
+}
+
+// importName returns the package name an import path conventionally
+// declares, e.g. yaml for gopkg.in/yaml.v3 and isatty for
+// github.com/mattn/go-isatty
+func importName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	if i := strings.Index(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
@@ -0,0 +1,281 @@
+package testgen
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// reservedNames are the identifiers generated tests declare themselves
+var reservedNames = map[string]bool{"t": true, "tt": true, "tests": true, "err": true, "ctx": true, "cancel": true}
+
+// receiver describes how method tests obtain a value of the type declaring
+// the methods: from a constructor, or as the zero value
+type receiver struct {
+	typeName string
+	params   []typeParam   // Type parameters of a generic type
+	ctor     *ast.FuncDecl // Constructor, or nil for the zero value
+	helper   bool          // Whether a test obtains the receiver from its helper
+}
+
+// receivers returns the receivers of the source types with exported
+// methods that generated tests can construct. The receiver_constructor
+// hint takes precedence over detected New<Type> constructors.
+func (l *layout) receivers(file *ast.File, f *typeFormatter, result *TestGenResult) map[string]*receiver {
+	typeSpecs := make(map[string]*ast.TypeSpec)
+	funcs := make(map[string]*ast.FuncDecl)
+	var methodTypes []string
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					typeSpecs[ts.Name.Name] = ts
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				funcs[d.Name.Name] = d
+			} else if len(d.Recv.List) > 0 && d.Name.IsExported() {
+				methodTypes = append(methodTypes, getReceiverTypeName(d.Recv.List[0].Type))
+			}
+		}
+	}
+
+	recvs := make(map[string]*receiver)
+	for _, typeName := range methodTypes {
+		ts, ok := typeSpecs[typeName]
+		if !ok || recvs[typeName] != nil {
+			continue
+		}
+		recv := &receiver{typeName: typeName, params: l.typeParams(ts.TypeParams, f)}
+		if ctor := funcs["New"+upperFirst(typeName)]; ctor != nil && constructedType(ctor) == typeName && l.callable(ctor) {
+			recv.ctor = ctor
+		}
+		recvs[typeName] = recv
+	}
+
+	if l.receiverConstructor != "" {
+		ctor := funcs[l.receiverConstructor]
+		recv := recvs[constructedType(ctor)]
+		switch {
+		case ctor == nil:
+			result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+				"receiver_constructor %s is not declared in the source; receivers without a detected constructor use zero values.", l.receiverConstructor))
+		case recv == nil || !l.callable(ctor):
+			result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+				"receiver_constructor %s does not construct a type with exported methods that the tests can call it for; it must return the type or a pointer to it, optionally with an error.", l.receiverConstructor))
+		default:
+			recv.ctor = ctor
+		}
+	}
+
+	// Types the test package cannot name need a constructor
+	for typeName, recv := range recvs {
+		if recv.ctor == nil && !l.callable(typeSpecs[typeName]) {
+			delete(recvs, typeName)
+		}
+	}
+	return recvs
+}
+
+// callable reports whether generated tests can refer to the declaration
+// node, which must be a *ast.FuncDecl or *ast.TypeSpec
+func (l *layout) callable(node ast.Node) bool {
+	if l.testPkg == l.source {
+		return true
+	}
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		return n.Name.IsExported()
+	case *ast.TypeSpec:
+		return n.Name.IsExported()
+	}
+	return false
+}
+
+// receiverOf returns the receiver of method fd, or nil if fd is not a
+// method of a type in recvs
+func receiverOf(recvs map[string]*receiver, fd *ast.FuncDecl) *receiver {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return nil
+	}
+	return recvs[getReceiverTypeName(fd.Recv.List[0].Type)]
+}
+
+// constructedType returns the type a constructor returns, or "" if fd is
+// not a function returning a type or a pointer to it, optionally followed
+// by an error
+func constructedType(fd *ast.FuncDecl) string {
+	if fd == nil || fd.Recv != nil || fd.Type.Results == nil {
+		return ""
+	}
+
+	var results []ast.Expr
+	for _, field := range fd.Type.Results.List {
+		for i := 0; i < max(1, len(field.Names)); i++ {
+			results = append(results, field.Type)
+		}
+	}
+	switch len(results) {
+	case 1:
+	case 2:
+		if ident, ok := results[1].(*ast.Ident); !ok || ident.Name != "error" {
+			return ""
+		}
+	default:
+		return ""
+	}
+	return getReceiverTypeName(results[0])
+}
+
+// substitutions maps the type parameter names of method fd's receiver to
+// the type arguments the receiver is instantiated with
+func (r *receiver) substitutions(fd *ast.FuncDecl) map[string]string {
+	if len(r.params) == 0 {
+		return nil
+	}
+	args := substitutions(r.params)
+	subst := make(map[string]string)
+	for name, declared := range receiverTypeParams(fd.Recv.List[0].Type, r.params) {
+		subst[name] = args[declared]
+	}
+	return subst
+}
+
+// writeReceiver writes the statements declaring the receiver of method fd
+// at indent and returns the receiver's variable name. Receivers with a
+// constructor are obtained from their test helper.
+func (l *layout) writeReceiver(sb *strings.Builder, indent string, fd *ast.FuncDecl, recv *receiver, f *typeFormatter) string {
+	name := receiverName(fd, recv.typeName)
+
+	if recv.ctor == nil {
+		if len(recv.params) > 0 {
+			sb.WriteString(instantiationHint(indent, recv.typeName, recv.params))
+		}
+		typeStr := f.typ(ast.NewIdent(recv.typeName))
+		if len(recv.params) > 0 {
+			typeStr = instantiation(typeStr, recv.params)
+		}
+		sb.WriteString(fmt.Sprintf("%svar %s %s\n", indent, name, typeStr))
+		return name
+	}
+
+	recv.helper = true
+	sb.WriteString(fmt.Sprintf("%s%s := %s(t)\n", indent, name, recv.helperName()))
+	return name
+}
+
+// writeConstructor writes the statements constructing the receiver recv
+// into the variable name at indent and returns the type the constructor
+// returns. Constructor arguments are declared as zero-valued variables for
+// the test to set up; contexts are the test's base context.
+func (l *layout) writeConstructor(sb *strings.Builder, indent, name string, recv *receiver, f *typeFormatter) string {
+	ctor := recv.ctor
+	call := f.function(ctor.Name.Name)
+	if ctor.Type.TypeParams != nil {
+		ctorParams := l.typeParams(ctor.Type.TypeParams, f)
+		call = instantiation(call, ctorParams)
+		f.typeParams = substitutions(ctorParams)
+		defer func() { f.typeParams = nil }()
+	}
+
+	ctx := ""
+	if f.takesContext(ctor.Type.Params) {
+		ctx = l.baseContext(f)
+	}
+	args := writeArgs(sb, indent, ctor.Name.Name, ctor.Type.Params, name, ctx, f)
+
+	resultType := f.typ(ctor.Type.Results.List[0].Type)
+	call = fmt.Sprintf("%s(%s)", call, strings.Join(args, ", "))
+	if ctor.Type.Results.NumFields() == 1 {
+		sb.WriteString(fmt.Sprintf("%s%s := %s\n", indent, name, call))
+		return resultType
+	}
+	sb.WriteString(fmt.Sprintf("%s%s, err := %s\n", indent, name, call))
+	writeFatalErr(sb, indent, ctor.Name.Name, l.style.Assert, f)
+	return resultType
+}
+
+// writeArgs writes zero-valued variables at indent for the test to set up
+// as the arguments of the parameters fl of fn, and returns the arguments
+// of the call. Context parameters are passed ctx instead. Variables that
+// would clash with avoid or the test's own identifiers are renamed.
+func writeArgs(sb *strings.Builder, indent, fn string, fl *ast.FieldList, avoid, ctx string, f *typeFormatter) []string {
+	var vars, args []string
+	for i, field := range fieldsOf(fl) {
+		argNames := field.Names
+		if len(argNames) == 0 {
+			argNames = []*ast.Ident{{Name: "_"}}
+		}
+		if f.isContext(field.Type) {
+			for range argNames {
+				args = append(args, ctx)
+			}
+			continue
+		}
+
+		typeStr := f.typ(field.Type)
+		variadic := false
+		if ellipsis, ok := field.Type.(*ast.Ellipsis); ok {
+			typeStr = "[]" + f.typ(ellipsis.Elt)
+			variadic = true
+		}
+		for _, ident := range argNames {
+			arg := ident.Name
+			if arg == "_" {
+				arg = fmt.Sprintf("p%d", i)
+			}
+			if arg == avoid || reservedNames[arg] {
+				arg += "Arg"
+			}
+			vars = append(vars, arg+" "+typeStr)
+			if variadic {
+				arg += "..."
+			}
+			args = append(args, arg)
+		}
+	}
+	switch len(vars) {
+	case 0:
+	case 1:
+		sb.WriteString(fmt.Sprintf("%s// TODO: Set up the %s arguments\n", indent, fn))
+		sb.WriteString(fmt.Sprintf("%svar %s\n", indent, vars[0]))
+	default:
+		sb.WriteString(fmt.Sprintf("%s// TODO: Set up the %s arguments\n", indent, fn))
+		sb.WriteString(indent + "var (\n")
+		for _, v := range vars {
+			sb.WriteString(indent + "\t" + v + "\n")
+		}
+		sb.WriteString(indent + ")\n")
+	}
+	return args
+}
+
+// receiverName returns the variable name of the receiver of fd in its
+// test: the source's receiver name unless it would clash with the test's
+// own identifiers
+func receiverName(fd *ast.FuncDecl, typeName string) string {
+	if names := fd.Recv.List[0].Names; len(names) > 0 && names[0].Name != "_" && !reservedNames[names[0].Name] {
+		return names[0].Name
+	}
+	return typeVarName(typeName)
+}
+
+// typeVarName returns a variable name for a value of typeName: its first
+// letter in lower case unless that clashes with the test's own identifiers
+func typeVarName(typeName string) string {
+	r, _ := utf8.DecodeRuneInString(typeName)
+	if name := string(unicode.ToLower(r)); !reservedNames[name] {
+		return name
+	}
+	return "recv"
+}
+
+// upperFirst returns s with its first letter in upper case
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
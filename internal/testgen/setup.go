@@ -0,0 +1,194 @@
+package testgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// clientPrefixes start the names of functions whose results are treated as
+// clients of external services when they initialize package variables
+var clientPrefixes = []string{"New", "Open", "Dial", "Connect"}
+
+// dependencies are the package-level dependencies of the source that a
+// TestMain sets up and tears down
+type dependencies struct {
+	env     []string // Environment variables read, in source order
+	clients []string // Package variables holding clients, e.g. "db (*sql.DB)" or "db (from sql.Open)"
+}
+
+// packageDependencies returns the environment variables file reads with
+// os.Getenv or os.LookupEnv and the package variables it initializes with
+// clients: values of pointer types from other packages, or results of
+// New*, Open*, Dial* and Connect* functions
+func packageDependencies(file *ast.File) dependencies {
+	var deps dependencies
+	osName := ""
+	for _, spec := range file.Imports {
+		if importPath(spec) != "os" {
+			continue
+		}
+		osName = "os"
+		if spec.Name != nil {
+			osName = spec.Name.Name
+		}
+	}
+
+	seen := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || osName == "" || len(call.Args) == 0 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "Getenv" && sel.Sel.Name != "LookupEnv") {
+			return true
+		}
+		if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != osName {
+			return true
+		}
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if key, err := strconv.Unquote(lit.Value); err == nil && !seen[key] {
+				seen[key] = true
+				deps.env = append(deps.env, key)
+			}
+		}
+		return true
+	})
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if name.Name == "_" {
+					continue
+				}
+				var value ast.Expr
+				switch {
+				case len(vs.Values) == len(vs.Names):
+					value = vs.Values[i]
+				case len(vs.Values) == 1 && i == 0:
+					// The first result of a multi-value call, e.g. sql.Open
+					value = vs.Values[0]
+				}
+				if typ := clientType(vs.Type, value); typ != "" {
+					deps.clients = append(deps.clients, fmt.Sprintf("%s (%s)", name.Name, typ))
+				}
+			}
+		}
+	}
+	return deps
+}
+
+// clientType returns how a package variable of type typ initialized with
+// value is described when it holds a client, or "" when it does not
+func clientType(typ, value ast.Expr) string {
+	if star, ok := typ.(*ast.StarExpr); ok {
+		if _, ok := star.X.(*ast.SelectorExpr); ok {
+			return formatType(typ)
+		}
+	}
+
+	call, ok := value.(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	var fn string
+	switch f := call.Fun.(type) {
+	case *ast.Ident:
+		fn = f.Name
+	case *ast.SelectorExpr:
+		// Sentinel errors are not clients
+		if pkg, ok := f.X.(*ast.Ident); ok && (pkg.Name == "errors" || pkg.Name == "fmt") {
+			return ""
+		}
+		fn = f.Sel.Name
+	default:
+		return ""
+	}
+	for _, prefix := range clientPrefixes {
+		if strings.HasPrefix(fn, prefix) {
+			if typ != nil {
+				return formatType(typ)
+			}
+			return "from " + formatType(call.Fun)
+		}
+	}
+	return ""
+}
+
+// testMain returns a TestMain with setup and teardown stubs for the
+// package-level dependencies of file, or "" when it has none or declares a
+// TestMain itself
+func (l *layout) testMain(file *ast.File, types *typeFormatter) string {
+	for _, decl := range file.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.Name == "TestMain" {
+			return ""
+		}
+	}
+	deps := packageDependencies(file)
+	if len(deps.env) == 0 && len(deps.clients) == 0 {
+		return ""
+	}
+
+	types.addImport(`"os"`)
+	var sb strings.Builder
+	sb.WriteString("func TestMain(m *testing.M) {\n")
+	sb.WriteString("\t// Setup\n")
+	if len(deps.env) > 0 {
+		sb.WriteString("\t// TODO: Set the environment variables the package reads\n")
+		for _, key := range deps.env {
+			sb.WriteString(fmt.Sprintf("\t// os.Setenv(%q, \"\")\n", key))
+		}
+	}
+	if len(deps.clients) > 0 {
+		sb.WriteString("\t// TODO: Start or fake the services of the package clients\n")
+		for _, client := range deps.clients {
+			sb.WriteString("\t// - " + client + "\n")
+		}
+	}
+	sb.WriteString("\n\tcode := m.Run()\n\n")
+	sb.WriteString("\t// Teardown\n")
+	sb.WriteString("\t// TODO: Close clients and remove test data\n\n")
+	sb.WriteString("\tos.Exit(code)\n")
+	sb.WriteString("}\n\n")
+	return sb.String()
+}
+
+// helperName returns the name of the test helper constructing r, e.g.
+// newTestStore
+func (r *receiver) helperName() string {
+	return "newTest" + upperFirst(r.typeName)
+}
+
+// writeHelpers writes the test helpers of the receivers in recvs that the
+// tests obtain from their constructors, in order of type name
+func (l *layout) writeHelpers(sb *strings.Builder, recvs map[string]*receiver, f *typeFormatter) {
+	var typeNames []string
+	for typeName, recv := range recvs {
+		if recv.helper {
+			typeNames = append(typeNames, typeName)
+		}
+	}
+	sort.Strings(typeNames)
+
+	for _, typeName := range typeNames {
+		recv := recvs[typeName]
+		name := typeVarName(typeName)
+		var body strings.Builder
+		resultType := l.writeConstructor(&body, "\t", name, recv, f)
+		sb.WriteString(fmt.Sprintf("// %s returns a %s for tests, built with %s\n", recv.helperName(), typeName, recv.ctor.Name.Name))
+		sb.WriteString(fmt.Sprintf("func %s(t *testing.T) %s {\n", recv.helperName(), resultType))
+		sb.WriteString("\tt.Helper()\n")
+		sb.WriteString(body.String())
+		sb.WriteString(fmt.Sprintf("\treturn %s\n", name))
+		sb.WriteString("}\n\n")
+	}
+}
@@ -0,0 +1,356 @@
+package testgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Test naming styles
+const (
+	NamingStandard   = "standard"   // TestParse, TestStore_Get
+	NamingUnderscore = "underscore" // Test_Parse, Test_Store_Get
+)
+
+// Test file layouts
+const (
+	LayoutSingle      = "single"       // All tests in <package>_test.go
+	LayoutPerFunction = "per-function" // One file per tested function, e.g. store_get_test.go
+)
+
+// Assertion styles of table-driven tests
+const (
+	AssertNone    = "none"    // TODO placeholders instead of calls and assertions
+	AssertStdlib  = "stdlib"  // if statements with t.Errorf and t.Fatalf
+	AssertTestify = "testify" // github.com/stretchr/testify/require
+)
+
+// testifyRequire is the import spec of testify's require package
+const testifyRequire = `"github.com/stretchr/testify/require"`
+
+// Style is the naming, layout and assertion convention of generated tests.
+// Empty fields fall back to the server settings.
+type Style struct {
+	Naming string `json:"naming,omitempty" jsonschema:"description:Optional test naming: 'standard' for TestFunc and TestType_Method or 'underscore' for Test_Func and Test_Type_Method"`
+	Layout string `json:"layout,omitempty" jsonschema:"description:Optional file layout: 'single' for one <package>_test.go or 'per-function' for one file per tested function; interface mocks always use a single file"`
+	Assert string `json:"assert,omitempty" jsonschema:"description:Optional assertions in table-driven tests: 'none' for TODO placeholders or 'stdlib' for if statements with t.Errorf or 'testify' for require"`
+}
+
+// DefaultStyle returns the style used when neither the request nor the
+// server sets one
+func DefaultStyle() Style {
+	return Style{Naming: NamingStandard, Layout: LayoutSingle, Assert: AssertNone}
+}
+
+// WithDefaults returns s with its empty fields taken from defaults
+func (s Style) WithDefaults(defaults Style) Style {
+	if s.Naming == "" {
+		s.Naming = defaults.Naming
+	}
+	if s.Layout == "" {
+		s.Layout = defaults.Layout
+	}
+	if s.Assert == "" {
+		s.Assert = defaults.Assert
+	}
+	return s
+}
+
+// Validate rejects unknown styles; empty fields are allowed
+func (s Style) Validate() error {
+	options := []struct {
+		name    string
+		value   string
+		allowed []string
+	}{
+		{"naming", s.Naming, []string{NamingStandard, NamingUnderscore}},
+		{"layout", s.Layout, []string{LayoutSingle, LayoutPerFunction}},
+		{"assert", s.Assert, []string{AssertNone, AssertStdlib, AssertTestify}},
+	}
+	for _, o := range options {
+		if o.value == "" || contains(o.allowed, o.value) {
+			continue
+		}
+		return fmt.Errorf("style.%s must be one of %s, got %q", o.name, strings.Join(o.allowed, ", "), o.value)
+	}
+	return nil
+}
+
+// contains reports whether list contains s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// testName returns the name of the test for fd in the naming style, e.g.
+// TestStore_Get or Test_Store_Get for the method Get of Store
+func testName(fd *ast.FuncDecl, naming string) string {
+	name := fd.Name.Name
+	if fd.Recv != nil && len(fd.Recv.List) > 0 {
+		name = upperFirst(getReceiverTypeName(fd.Recv.List[0].Type)) + "_" + name
+	}
+	if naming == NamingUnderscore {
+		return "Test_" + name
+	}
+	return "Test" + name
+}
+
+// call is a call of a tested function in a table-driven test
+type call struct {
+	args    string   // Arguments from the table, e.g. "tt.key"
+	expr    string   // The call, e.g. "s.Get(tt.key)"
+	name    string   // How failures name the function, e.g. "s.Get"
+	results []string // Variables receiving the results: got, got1, ... and err
+}
+
+// tableCall returns the call of fd with the arguments of the test table
+// and the test's ctx for contexts, or false when a parameter has no table field to pass or more than one
+// error is returned
+func tableCall(fd *ast.FuncDecl, types *typeFormatter) (call, bool) {
+	var args []string
+	for _, field := range fieldsOf(fd.Type.Params) {
+		if types.isContext(field.Type) {
+			for i := 0; i < max(1, len(field.Names)); i++ {
+				args = append(args, "ctx")
+			}
+			continue
+		}
+		if len(field.Names) == 0 {
+			return call{}, false
+		}
+		for _, name := range field.Names {
+			if name.Name == "_" {
+				return call{}, false
+			}
+			arg := "tt." + name.Name
+			if _, variadic := field.Type.(*ast.Ellipsis); variadic {
+				arg += "..."
+			}
+			args = append(args, arg)
+		}
+	}
+
+	c := call{args: strings.Join(args, ", ")}
+	got, errs := 0, 0
+	for _, field := range fieldsOf(fd.Type.Results) {
+		isErr := types.typ(field.Type) == "error"
+		for i := 0; i < max(1, len(field.Names)); i++ {
+			switch {
+			case isErr:
+				errs++
+				c.results = append(c.results, "err")
+			case got == 0:
+				got++
+				c.results = append(c.results, "got")
+			default:
+				c.results = append(c.results, fmt.Sprintf("got%d", got))
+				got++
+			}
+		}
+	}
+	return c, errs <= 1
+}
+
+// of returns c calling the function expression fn, e.g. "s.Get" or
+// "Map[int, string]"
+func (c call) of(fn string) call {
+	c.expr = fn + "(" + c.args + ")"
+	c.name, _, _ = strings.Cut(fn, "[")
+	return c
+}
+
+// writeAssertions writes the call of a table-driven test and the checks of
+// its results against the table in the assertion style, recording the
+// imports they need in types
+func writeAssertions(sb *strings.Builder, indent string, c call, assert string, types *typeFormatter) {
+	hasErr := contains(c.results, "err")
+	if len(c.results) == 0 {
+		sb.WriteString(indent + c.expr + "\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("%s%s := %s\n", indent, strings.Join(c.results, ", "), c.expr))
+	}
+
+	if assert == AssertTestify {
+		types.addImport(testifyRequire)
+		if hasErr {
+			sb.WriteString(indent + "if tt.wantErr {\n")
+			sb.WriteString(indent + "\trequire.Error(t, err)\n")
+			sb.WriteString(indent + "\treturn\n")
+			sb.WriteString(indent + "}\n")
+			sb.WriteString(indent + "require.NoError(t, err)\n")
+		}
+		for _, got := range c.results {
+			if got != "err" {
+				sb.WriteString(fmt.Sprintf("%srequire.Equal(t, tt.%s, %s)\n", indent, wantField(got), got))
+			}
+		}
+		return
+	}
+
+	if hasErr {
+		sb.WriteString(indent + "if (err != nil) != tt.wantErr {\n")
+		sb.WriteString(fmt.Sprintf("%s\tt.Fatalf(\"%s() error = %%v, wantErr %%v\", err, tt.wantErr)\n", indent, c.name))
+		sb.WriteString(indent + "}\n")
+		sb.WriteString(indent + "if tt.wantErr {\n")
+		sb.WriteString(indent + "\treturn\n")
+		sb.WriteString(indent + "}\n")
+	}
+	for _, got := range c.results {
+		if got == "err" {
+			continue
+		}
+		label := c.name + "()"
+		if got != "got" {
+			label += " " + got
+		}
+		types.addImport(`"reflect"`)
+		sb.WriteString(fmt.Sprintf("%sif !reflect.DeepEqual(%s, tt.%s) {\n", indent, got, wantField(got)))
+		sb.WriteString(fmt.Sprintf("%s\tt.Errorf(\"%s = %%v, want %%v\", %s, tt.%s)\n", indent, label, got, wantField(got)))
+		sb.WriteString(indent + "}\n")
+	}
+}
+
+// wantField returns the table field holding the expected value of the
+// result variable got, e.g. want1 for got1
+func wantField(got string) string {
+	return "want" + strings.TrimPrefix(got, "got")
+}
+
+// writeFatalErr writes the check of err after calling fn in the assertion
+// style
+func writeFatalErr(sb *strings.Builder, indent, fn, assert string, types *typeFormatter) {
+	if assert == AssertTestify {
+		types.addImport(testifyRequire)
+		sb.WriteString(fmt.Sprintf("%srequire.NoError(t, err, %q)\n", indent, fn))
+		return
+	}
+	sb.WriteString(fmt.Sprintf("%sif err != nil {\n", indent))
+	sb.WriteString(fmt.Sprintf("%s\tt.Fatalf(\"%s: %%v\", err)\n", indent, fn))
+	sb.WriteString(indent + "}\n")
+}
+
+// splitTests splits formatted test code into one file per test function,
+// each with the imports it uses. Test helpers share helpers_test.go. Code
+// without test functions is returned as the single file name.
+func splitTests(code, name string) ([]GeneratedFile, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", code, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("generated code is invalid: %v", err)
+	}
+
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		text := spec.Path.Value
+		pkg := importName(importPath)
+		if spec.Name != nil {
+			pkg = spec.Name.Name
+			text = pkg + " " + text
+		}
+		imports[pkg] = text
+	}
+
+	// Group the functions by file, in order of first appearance
+	var paths []string
+	funcs := make(map[string][]*ast.FuncDecl)
+	seen := make(map[string]int)
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		path := "helpers_test.go"
+		if strings.HasPrefix(fd.Name.Name, "Test") {
+			path = testFileName(fd.Name.Name)
+			if n := seen[path]; n > 0 {
+				path = fmt.Sprintf("%s_%d_test.go", strings.TrimSuffix(path, "_test.go"), n+1)
+			}
+			seen[testFileName(fd.Name.Name)]++
+		}
+		if funcs[path] == nil {
+			paths = append(paths, path)
+		}
+		funcs[path] = append(funcs[path], fd)
+	}
+
+	var files []GeneratedFile
+	for _, path := range paths {
+		var used []string
+		for pkg, spec := range imports {
+			for _, fd := range funcs[path] {
+				if usesPackage(fd, pkg) {
+					used = append(used, spec)
+					break
+				}
+			}
+		}
+		sort.Slice(used, func(i, j int) bool {
+			return used[i][strings.Index(used[i], `"`):] < used[j][strings.Index(used[j], `"`):]
+		})
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("package %s\n\n", file.Name.Name))
+		if len(used) > 0 {
+			sb.WriteString("import (\n\t" + strings.Join(used, "\n\t") + "\n)\n\n")
+		}
+		for _, fd := range funcs[path] {
+			start := fd.Pos()
+			if fd.Doc != nil {
+				start = fd.Doc.Pos()
+			}
+			sb.WriteString(code[fset.Position(start).Offset:fset.Position(fd.End()).Offset] + "\n\n")
+		}
+		formatted, err := format.Source([]byte(sb.String()))
+		if err != nil {
+			return nil, fmt.Errorf("generated code is invalid: %v", err)
+		}
+		files = append(files, GeneratedFile{Path: path, Content: string(formatted)})
+	}
+
+	if len(files) == 0 {
+		return []GeneratedFile{{Path: name, Content: code}}, nil
+	}
+	return files, nil
+}
+
+// usesPackage reports whether node refers to the package imported as pkg
+func usesPackage(node ast.Node, pkg string) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == pkg {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// testFileName returns the file of a test in the per-function layout, e.g.
+// store_get_test.go for TestStore_Get and parse_url_test.go for
+// Test_ParseURL
+func testFileName(test string) string {
+	name := strings.TrimLeft(strings.TrimPrefix(test, "Test"), "_")
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && runes[i-1] != '_' &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			sb.WriteByte('_')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String() + "_test.go"
+}
@@ -4,27 +4,51 @@ import (
 	"context"
 	"fmt"
 	"go/ast"
+	"go/format"
 	"go/parser"
 	"go/token"
+	"os"
+	"path"
 	"strings"
+
+	"mcp-go-assistant/internal/cache"
+	"mcp-go-assistant/internal/logging"
+	"mcp-go-assistant/internal/types"
 )
 
 // GenerateTests analyzes Go code and generates test scaffolding
 func GenerateTests(ctx context.Context, params TestGenParams) (*TestGenResult, error) {
+	// Read spooled code back only now that it is needed
+	if params.GoCode == "" && params.GoCodeFile != "" {
+		code, err := os.ReadFile(params.GoCodeFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spooled go_code: %v", err)
+		}
+		params.GoCode = string(code)
+	}
+
 	if params.GoCode == "" {
 		return nil, fmt.Errorf("go_code parameter is required")
 	}
 
+	if err := params.Style.Validate(); err != nil {
+		return nil, err
+	}
+
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, "", params.GoCode, parser.ParseComments)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse Go code: %v", err)
+		return nil, logging.ErrorWithRequestID(ctx, types.NewKindError(types.KindParseFailure, "failed to parse Go code: %v", err))
 	}
 
 	pkgName := params.PackageName
 	if pkgName == "" {
 		pkgName = file.Name.Name + "_test"
+		if params.SamePackage {
+			pkgName = file.Name.Name
+		}
 	}
+	l := newLayout(file, params, pkgName)
 
 	result := &TestGenResult{
 		Interfaces:  []Interface{},
@@ -35,34 +59,71 @@ func GenerateTests(ctx context.Context, params TestGenParams) (*TestGenResult, e
 
 	switch focus {
 	case "interfaces", "interface", "mock", "mocks":
-		generateInterfacesAndMocks(file, pkgName, result)
+		generateInterfacesAndMocks(file, l, result)
+		result.MockFile = "mocks_test.go"
+		if params.MockPackage != "" {
+			result.MockFile = path.Join(params.MockPackage, file.Name.Name+"_mock.go")
+		}
 	case "table", "table-driven":
-		generateTableDrivenTests(file, pkgName, result)
+		generateTableDrivenTests(file, l, result)
 	default:
-		generateUnitTests(file, pkgName, result)
+		generateUnitTests(file, l, result)
+	}
+
+	if err := formatGenerated(result); err != nil {
+		return nil, err
+	}
+	verifyGenerated(ctx, params.GoCode, l, result)
+
+	if l.importAssumed {
+		result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+			"Set import_path to the import path of package %s; the generated code imports it as %q.", l.source, l.source))
+	}
+
+	result.TestFile = params.ExistingTestsFile
+	if result.TestFile == "" {
+		result.TestFile = file.Name.Name + "_test.go"
+	}
+	if l.style.Layout == LayoutPerFunction && result.MockFile == "" {
+		result.TestFiles, err = splitTests(result.TestCode, result.TestFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if params.ExistingTests != "" {
+		result.Diff, err = diffExistingTests(params.ExistingTests, result.TestFile, result)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return result, nil
 }
 
 // generateInterfacesAndMocks extracts interfaces from concrete types and generates mocks
-func generateInterfacesAndMocks(file *ast.File, pkgName string, result *TestGenResult) {
+func generateInterfacesAndMocks(file *ast.File, l *layout, result *TestGenResult) {
 	var testCode strings.Builder
 	var mockCode strings.Builder
 
-	testCode.WriteString(fmt.Sprintf("package %s\n\n", pkgName))
-	mockCode.WriteString(fmt.Sprintf("package %s\n\n", pkgName))
+	testTypes := l.formatter(l.testPkg)
+	mockTypes := l.formatter(l.mockPkg)
 
 	// Find struct types and their methods
-	typesMethods := make(map[string][]Method)
+	var typeNames []string
+	typesMethods := make(map[string][]*ast.FuncDecl)
 	structTypes := make(map[string]bool)
+	typeParams := make(map[string]*ast.FieldList)
 
-	// First pass: identify struct types
+	// First pass: identify struct types and the type parameters of generic types
 	ast.Inspect(file, func(n ast.Node) bool {
 		if ts, ok := n.(*ast.TypeSpec); ok {
 			if _, isStruct := ts.Type.(*ast.StructType); isStruct {
 				structTypes[ts.Name.Name] = true
 			}
+			if ts.TypeParams != nil {
+				typeParams[ts.Name.Name] = ts.TypeParams
+			}
 		}
 		return true
 	})
@@ -73,12 +134,10 @@ func generateInterfacesAndMocks(file *ast.File, pkgName string, result *TestGenR
 			if fd.Recv != nil && len(fd.Recv.List) > 0 {
 				recvType := getReceiverTypeName(fd.Recv.List[0].Type)
 				if recvType != "" && fd.Name.IsExported() {
-					method := Method{
-						Name:    fd.Name.Name,
-						Params:  formatFieldList(fd.Type.Params),
-						Returns: formatFieldList(fd.Type.Results),
+					if _, seen := typesMethods[recvType]; !seen {
+						typeNames = append(typeNames, recvType)
 					}
-					typesMethods[recvType] = append(typesMethods[recvType], method)
+					typesMethods[recvType] = append(typesMethods[recvType], fd)
 				}
 			}
 		}
@@ -86,62 +145,84 @@ func generateInterfacesAndMocks(file *ast.File, pkgName string, result *TestGenR
 	})
 
 	// Generate interfaces and mocks for types with methods
-	for typeName, methods := range typesMethods {
-		if len(methods) == 0 {
-			continue
-		}
+	for _, typeName := range typeNames {
+		decls := typesMethods[typeName]
 
 		ifaceName := typeName + "er"
 		if strings.HasSuffix(typeName, "er") {
 			ifaceName = typeName + "Interface"
 		}
 
+		// Generic types yield generic interfaces and mocks with the same
+		// type parameters
+		tparams := l.typeParams(typeParams[typeName], testTypes)
+
 		iface := Interface{
-			Name:    ifaceName,
-			Methods: methods,
-			ForType: typeName,
+			Name:       ifaceName,
+			ForType:    typeName,
+			TypeParams: declaration(tparams, new(typeFormatter)),
+		}
+		for _, fd := range decls {
+			renames := receiverTypeParams(fd.Recv.List[0].Type, tparams)
+			iface.Methods = append(iface.Methods, Method{
+				Name:    fd.Name.Name,
+				Params:  (&typeFormatter{typeParams: renames}).fieldList(fd.Type.Params),
+				Returns: (&typeFormatter{typeParams: renames}).fieldList(fd.Type.Results),
+			})
 		}
 		result.Interfaces = append(result.Interfaces, iface)
 
 		// Generate interface definition
 		testCode.WriteString(fmt.Sprintf("// %s defines the interface for %s\n", ifaceName, typeName))
-		testCode.WriteString(fmt.Sprintf("type %s interface {\n", ifaceName))
-		for _, m := range methods {
-			if m.Returns != "" {
-				testCode.WriteString(fmt.Sprintf("\t%s(%s) %s\n", m.Name, m.Params, m.Returns))
+		if len(tparams) > 0 {
+			testCode.WriteString(instantiationHint("", ifaceName, tparams))
+		}
+		testCode.WriteString(fmt.Sprintf("type %s%s interface {\n", ifaceName, declaration(tparams, testTypes)))
+		for _, fd := range decls {
+			testTypes.typeParams = receiverTypeParams(fd.Recv.List[0].Type, tparams)
+			params, returns := testTypes.fieldList(fd.Type.Params), testTypes.fieldList(fd.Type.Results)
+			if returns != "" {
+				testCode.WriteString(fmt.Sprintf("\t%s(%s) %s\n", fd.Name.Name, params, resultList(returns)))
 			} else {
-				testCode.WriteString(fmt.Sprintf("\t%s(%s)\n", m.Name, m.Params))
+				testCode.WriteString(fmt.Sprintf("\t%s(%s)\n", fd.Name.Name, params))
 			}
 		}
+		testTypes.typeParams = nil
 		testCode.WriteString("}\n\n")
 
 		// Generate mock implementation
 		mockName := "Mock" + typeName
 		mockCode.WriteString(fmt.Sprintf("// %s is a mock implementation of %s\n", mockName, ifaceName))
-		mockCode.WriteString(fmt.Sprintf("type %s struct {\n", mockName))
-		for _, m := range methods {
-			mockCode.WriteString(fmt.Sprintf("\t%sFunc func(%s)", m.Name, m.Params))
-			if m.Returns != "" {
-				mockCode.WriteString(fmt.Sprintf(" %s", m.Returns))
+		mockCode.WriteString(fmt.Sprintf("type %s%s struct {\n", mockName, declaration(tparams, mockTypes)))
+		for _, fd := range decls {
+			mockTypes.typeParams = receiverTypeParams(fd.Recv.List[0].Type, tparams)
+			params, returns := mockTypes.fieldList(fd.Type.Params), mockTypes.fieldList(fd.Type.Results)
+			mockCode.WriteString(fmt.Sprintf("\t%sFunc func(%s)", fd.Name.Name, params))
+			if returns != "" {
+				mockCode.WriteString(fmt.Sprintf(" %s", resultList(returns)))
 			}
 			mockCode.WriteString("\n")
 		}
 		mockCode.WriteString("}\n\n")
 
 		// Generate mock method implementations
-		for _, m := range methods {
-			mockCode.WriteString(fmt.Sprintf("func (m *%s) %s(%s)", mockName, m.Name, m.Params))
-			if m.Returns != "" {
-				mockCode.WriteString(fmt.Sprintf(" %s", m.Returns))
+		for _, fd := range decls {
+			mockTypes.typeParams = receiverTypeParams(fd.Recv.List[0].Type, tparams)
+			params, args := mockTypes.params(fd.Type.Params)
+			returns := mockTypes.fieldList(fd.Type.Results)
+			mockCode.WriteString(fmt.Sprintf("func (m *%s%s) %s(%s)", mockName, names(tparams), fd.Name.Name, params))
+			if returns != "" {
+				mockCode.WriteString(fmt.Sprintf(" %s", resultList(returns)))
 			}
 			mockCode.WriteString(" {\n")
-			if m.Returns != "" {
-				mockCode.WriteString(fmt.Sprintf("\treturn m.%sFunc(%s)\n", m.Name, extractParamNames(m.Params)))
+			if returns != "" {
+				mockCode.WriteString(fmt.Sprintf("\treturn m.%sFunc(%s)\n", fd.Name.Name, args))
 			} else {
-				mockCode.WriteString(fmt.Sprintf("\tm.%sFunc(%s)\n", m.Name, extractParamNames(m.Params)))
+				mockCode.WriteString(fmt.Sprintf("\tm.%sFunc(%s)\n", fd.Name.Name, args))
 			}
 			mockCode.WriteString("}\n\n")
 		}
+		mockTypes.typeParams = nil
 	}
 
 	if len(typesMethods) == 0 {
@@ -150,76 +231,134 @@ func generateInterfacesAndMocks(file *ast.File, pkgName string, result *TestGenR
 		testCode.WriteString("// Ensure your code has struct types with exported methods.\n")
 	}
 
-	result.TestCode = testCode.String()
-	result.MockCode = mockCode.String()
+	result.TestCode = l.header(l.testPkg, testTypes) + testCode.String()
+	result.MockCode = l.header(l.mockPkg, mockTypes) + mockCode.String()
 }
 
 // generateUnitTests generates basic unit test scaffolding
-func generateUnitTests(file *ast.File, pkgName string, result *TestGenResult) {
+func generateUnitTests(file *ast.File, l *layout, result *TestGenResult) {
 	var testCode strings.Builder
+	types := l.formatter(l.testPkg)
 
-	testCode.WriteString(fmt.Sprintf("package %s\n\n", pkgName))
-	testCode.WriteString("import (\n\t\"testing\"\n)\n\n")
+	recvs := l.receivers(file, types, result)
 
 	funcCount := 0
 	ast.Inspect(file, func(n ast.Node) bool {
 		if fd, ok := n.(*ast.FuncDecl); ok {
-			if fd.Name.IsExported() && fd.Recv == nil {
+			recv := receiverOf(recvs, fd)
+			if fd.Name.IsExported() && (fd.Recv == nil || recv != nil) {
 				funcCount++
-				testCode.WriteString(fmt.Sprintf("func Test%s(t *testing.T) {\n", fd.Name.Name))
+				testCode.WriteString(fmt.Sprintf("func %s(t *testing.T) {\n", testName(fd, l.style.Naming)))
 				testCode.WriteString("\t// Arrange\n")
+				recvName := ""
+				if recv != nil {
+					recvName = l.writeReceiver(&testCode, "\t", fd, recv, types)
+				}
+				hasCtx := types.takesContext(fd.Type.Params)
+				if hasCtx {
+					l.writeTimeoutContext(&testCode, "\t", types)
+				}
 				testCode.WriteString("\t// TODO: Set up test inputs\n\n")
 				testCode.WriteString("\t// Act\n")
-				testCode.WriteString(fmt.Sprintf("\t// TODO: Call %s with inputs\n\n", fd.Name.Name))
+				if fd.Type.TypeParams != nil {
+					testCode.WriteString(instantiationHint("\t", fd.Name.Name, l.typeParams(fd.Type.TypeParams, types)))
+				}
+				with := "inputs"
+				if hasCtx {
+					with = "ctx and inputs"
+				}
+				if recv != nil {
+					testCode.WriteString(fmt.Sprintf("\t// TODO: Call %s.%s with %s\n", recvName, fd.Name.Name, with))
+					testCode.WriteString(fmt.Sprintf("\t_ = %s\n", recvName))
+				} else {
+					testCode.WriteString(fmt.Sprintf("\t// TODO: Call %s with %s\n", fd.Name.Name, with))
+				}
+				if hasCtx {
+					testCode.WriteString("\t_ = ctx\n")
+				}
+				testCode.WriteString("\n")
 				testCode.WriteString("\t// Assert\n")
 				testCode.WriteString("\t// TODO: Verify expected outcomes\n")
 				testCode.WriteString("}\n\n")
+				if hasCtx {
+					l.writeCanceledTest(&testCode, fd, recv, types)
+				}
 			}
 		}
 		return true
 	})
 
 	if funcCount == 0 {
-		result.Suggestions = append(result.Suggestions, "No exported functions found. Tests are typically written for exported functions.")
-		testCode.WriteString("// No exported functions found to generate tests for.\n")
+		result.Suggestions = append(result.Suggestions, "No exported functions or methods found. Tests are typically written for exported functions and methods.")
+		testCode.WriteString("// No exported functions or methods found to generate tests for.\n")
 	}
 
-	result.TestCode = testCode.String()
+	main := l.testMain(file, types)
+	l.writeHelpers(&testCode, recvs, types)
+	result.TestCode = l.header(l.testPkg, types, `"testing"`) + main + testCode.String()
 }
 
 // generateTableDrivenTests generates table-driven test scaffolding
-func generateTableDrivenTests(file *ast.File, pkgName string, result *TestGenResult) {
+func generateTableDrivenTests(file *ast.File, l *layout, result *TestGenResult) {
 	var testCode strings.Builder
+	types := l.formatter(l.testPkg)
 
-	testCode.WriteString(fmt.Sprintf("package %s\n\n", pkgName))
-	testCode.WriteString("import (\n\t\"testing\"\n)\n\n")
+	recvs := l.receivers(file, types, result)
 
 	funcCount := 0
 	ast.Inspect(file, func(n ast.Node) bool {
 		if fd, ok := n.(*ast.FuncDecl); ok {
-			if fd.Name.IsExported() && fd.Recv == nil {
+			recv := receiverOf(recvs, fd)
+			if fd.Name.IsExported() && (fd.Recv == nil || recv != nil) {
 				funcCount++
-				params := formatFieldList(fd.Type.Params)
-				returns := formatFieldList(fd.Type.Results)
-
-				testCode.WriteString(fmt.Sprintf("func Test%s(t *testing.T) {\n", fd.Name.Name))
+				testCode.WriteString(fmt.Sprintf("func %s(t *testing.T) {\n", testName(fd, l.style.Naming)))
+
+				// Generic functions are tested with concrete type arguments,
+				// methods of generic types with those of their receiver
+				if fd.Type.TypeParams != nil {
+					tparams := l.typeParams(fd.Type.TypeParams, types)
+					testCode.WriteString(instantiationHint("\t", fd.Name.Name, tparams))
+					types.typeParams = substitutions(tparams)
+				} else if recv != nil {
+					types.typeParams = recv.substitutions(fd)
+				}
 				testCode.WriteString("\ttests := []struct {\n")
 				testCode.WriteString("\t\tname string\n")
 
-				// Add input fields based on function params
-				if params != "" {
-					for _, p := range strings.Split(params, ", ") {
-						parts := strings.Fields(p)
-						if len(parts) >= 2 {
-							testCode.WriteString(fmt.Sprintf("\t\t%s %s\n", parts[0], parts[1]))
+				// Add input fields based on function params; contexts are
+				// created by the test
+				for _, field := range fieldsOf(fd.Type.Params) {
+					if types.isContext(field.Type) {
+						continue
+					}
+					typeStr := types.typ(field.Type)
+					if ellipsis, ok := field.Type.(*ast.Ellipsis); ok {
+						typeStr = "[]" + types.typ(ellipsis.Elt)
+					}
+					for _, name := range field.Names {
+						if name.Name != "_" {
+							testCode.WriteString(fmt.Sprintf("\t\t%s %s\n", name.Name, typeStr))
 						}
 					}
 				}
 
-				// Add expected output fields
-				if returns != "" {
-					testCode.WriteString(fmt.Sprintf("\t\twant %s\n", returns))
+				// Add expected output fields; errors are covered by wantErr
+				want := 0
+				for _, field := range fieldsOf(fd.Type.Results) {
+					typeStr := types.typ(field.Type)
+					if typeStr == "error" {
+						continue
+					}
+					for i := 0; i < max(1, len(field.Names)); i++ {
+						name := "want"
+						if want > 0 {
+							name = fmt.Sprintf("want%d", want)
+						}
+						want++
+						testCode.WriteString(fmt.Sprintf("\t\t%s %s\n", name, typeStr))
+					}
 				}
+				types.typeParams = nil
 				testCode.WriteString("\t\twantErr bool\n")
 				testCode.WriteString("\t}{\n")
 				testCode.WriteString("\t\t{\n")
@@ -234,21 +373,58 @@ func generateTableDrivenTests(file *ast.File, pkgName string, result *TestGenRes
 				testCode.WriteString("\t}\n\n")
 				testCode.WriteString("\tfor _, tt := range tests {\n")
 				testCode.WriteString("\t\tt.Run(tt.name, func(t *testing.T) {\n")
-				testCode.WriteString(fmt.Sprintf("\t\t\t// TODO: Call %s and verify results\n", fd.Name.Name))
+				recvName := ""
+				if recv != nil {
+					recvName = l.writeReceiver(&testCode, "\t\t\t", fd, recv, types)
+				}
+				hasCtx := types.takesContext(fd.Type.Params)
+				if hasCtx {
+					l.writeTimeoutContext(&testCode, "\t\t\t", types)
+				}
+				if c, ok := tableCall(fd, types); ok && l.style.Assert != AssertNone {
+					fn := recvName + "." + fd.Name.Name
+					if recv == nil {
+						fn = types.function(fd.Name.Name)
+						if fd.Type.TypeParams != nil {
+							fn = instantiation(fn, l.typeParams(fd.Type.TypeParams, types))
+						}
+					}
+					writeAssertions(&testCode, "\t\t\t", c.of(fn), l.style.Assert, types)
+				} else {
+					call := fd.Name.Name
+					if recv != nil {
+						call = recvName + "." + call
+					}
+					if hasCtx {
+						call += " with ctx"
+					}
+					testCode.WriteString(fmt.Sprintf("\t\t\t// TODO: Call %s and verify results\n", call))
+					if recv != nil {
+						testCode.WriteString(fmt.Sprintf("\t\t\t_ = %s\n", recvName))
+					}
+					if hasCtx {
+						testCode.WriteString("\t\t\t_ = ctx\n")
+					}
+				}
 				testCode.WriteString("\t\t})\n")
 				testCode.WriteString("\t}\n")
 				testCode.WriteString("}\n\n")
+				if hasCtx {
+					l.writeCanceledTest(&testCode, fd, recv, types)
+				}
 			}
 		}
 		return true
 	})
 
 	if funcCount == 0 {
-		result.Suggestions = append(result.Suggestions, "No exported functions found for table-driven tests.")
-		testCode.WriteString("// No exported functions found to generate tests for.\n")
+		result.Suggestions = append(result.Suggestions, "No exported functions or methods found for table-driven tests.")
+		testCode.WriteString("// No exported functions or methods found to generate tests for.\n")
 	}
 
-	result.TestCode = testCode.String()
+	main := l.testMain(file, types)
+	l.writeHelpers(&testCode, recvs, types)
+	result.TestCode = l.header(l.testPkg, types, `"testing"`) + main + testCode.String()
 }
 
 // Helper functions
@@ -258,68 +434,66 @@ func getReceiverTypeName(expr ast.Expr) string {
 	case *ast.Ident:
 		return t.Name
 	case *ast.StarExpr:
-		if ident, ok := t.X.(*ast.Ident); ok {
-			return ident.Name
-		}
+		return getReceiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return getReceiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return getReceiverTypeName(t.X)
 	}
 	return ""
 }
 
 func formatFieldList(fl *ast.FieldList) string {
-	if fl == nil || len(fl.List) == 0 {
-		return ""
+	return new(typeFormatter).fieldList(fl)
+}
+
+func formatType(expr ast.Expr) string {
+	return new(typeFormatter).typ(expr)
+}
+
+// resultList wraps a formatted result list in parentheses when a signature
+// requires them, i.e. for multiple or named results
+func resultList(returns string) string {
+	if strings.Contains(returns, " ") {
+		return "(" + returns + ")"
 	}
+	return returns
+}
 
-	var parts []string
-	for _, f := range fl.List {
-		typeStr := formatType(f.Type)
-		if len(f.Names) == 0 {
-			parts = append(parts, typeStr)
-		} else {
-			for _, name := range f.Names {
-				parts = append(parts, fmt.Sprintf("%s %s", name.Name, typeStr))
-			}
-		}
+// fieldsOf returns the fields of a possibly nil field list
+func fieldsOf(fl *ast.FieldList) []*ast.Field {
+	if fl == nil {
+		return nil
 	}
-	return strings.Join(parts, ", ")
+	return fl.List
 }
 
-func formatType(expr ast.Expr) string {
-	switch t := expr.(type) {
-	case *ast.Ident:
-		return t.Name
-	case *ast.StarExpr:
-		return "*" + formatType(t.X)
-	case *ast.SelectorExpr:
-		return formatType(t.X) + "." + t.Sel.Name
-	case *ast.ArrayType:
-		if t.Len == nil {
-			return "[]" + formatType(t.Elt)
+// formatGenerated formats the generated code with gofmt, which also
+// verifies that it parses
+func formatGenerated(result *TestGenResult) error {
+	for _, code := range []*string{&result.TestCode, &result.MockCode} {
+		if *code == "" {
+			continue
 		}
-		return "[...]" + formatType(t.Elt)
-	case *ast.MapType:
-		return fmt.Sprintf("map[%s]%s", formatType(t.Key), formatType(t.Value))
-	case *ast.InterfaceType:
-		return "interface{}"
-	case *ast.FuncType:
-		return "func(" + formatFieldList(t.Params) + ")"
-	case *ast.ChanType:
-		return "chan " + formatType(t.Value)
-	default:
-		return "any"
+		formatted, err := format.Source([]byte(*code))
+		if err != nil {
+			return fmt.Errorf("generated code is invalid: %v", err)
+		}
+		*code = string(formatted)
 	}
+	return nil
 }
 
-func extractParamNames(params string) string {
-	if params == "" {
-		return ""
-	}
-	var names []string
-	for _, p := range strings.Split(params, ", ") {
-		parts := strings.Fields(p)
-		if len(parts) >= 1 {
-			names = append(names, parts[0])
-		}
+// CacheKey returns the response cache key for params, derived from the code
+// hashes and the remaining parameters. The cache lives in the process, so a
+// result is never reused by a build with another generator. Requests writing
+// files are not cacheable.
+func CacheKey(params TestGenParams) (string, bool) {
+	if params.WriteFiles {
+		return "", false
 	}
-	return strings.Join(names, ", ")
+
+	code, existing := cache.Hash(params.GoCode), cache.Hash(params.ExistingTests)
+	params.GoCode, params.ExistingTests, params.IdempotencyKey = "", "", ""
+	return cache.Key(code, existing, params), true
 }
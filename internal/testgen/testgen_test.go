@@ -2,6 +2,10 @@ package testgen
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -391,16 +395,17 @@ func Function(a string, b int, c bool) error {
 		t.Fatal("expected result, got nil")
 	}
 
-	// Test code should contain function parameters
-	if !strings.Contains(result.TestCode, "a string") {
+	// Test code should contain function parameters; gofmt aligns the fields
+	fields := collapseSpace(result.TestCode)
+	if !strings.Contains(fields, "a string") {
 		t.Error("expected parameter 'a string' in test code")
 	}
 
-	if !strings.Contains(result.TestCode, "b int") {
+	if !strings.Contains(fields, "b int") {
 		t.Error("expected parameter 'b int' in test code")
 	}
 
-	if !strings.Contains(result.TestCode, "c bool") {
+	if !strings.Contains(fields, "c bool") {
 		t.Error("expected parameter 'c bool' in test code")
 	}
 }
@@ -428,13 +433,23 @@ func Function(a []string, b map[string]int, c chan error) {
 	}
 
 	// Should handle complex types
-	if !strings.Contains(result.TestCode, "a []string") {
+	fields := collapseSpace(result.TestCode)
+	if !strings.Contains(fields, "a []string") {
 		t.Error("expected 'a []string' in test code")
 	}
 
-	if !strings.Contains(result.TestCode, "b map[string]int") {
+	if !strings.Contains(fields, "b map[string]int") {
 		t.Error("expected 'b map[string]int' in test code")
 	}
+
+	if !strings.Contains(fields, "c chan error") {
+		t.Error("expected 'c chan error' in test code")
+	}
+}
+
+// collapseSpace replaces runs of whitespace with single spaces
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
 }
 
 func TestGenerateTests_CaseInsensitiveFocus(t *testing.T) {
@@ -563,3 +578,987 @@ func TestMethod(t *testing.T) {
 		t.Errorf("expected returns 'error', got '%s'", method.Returns)
 	}
 }
+
+func TestCacheKey(t *testing.T) {
+	base := TestGenParams{GoCode: "package a", Focus: "table"}
+
+	key, ok := CacheKey(base)
+	if !ok || key == "" {
+		t.Fatal("expected params to be cacheable")
+	}
+	if again, _ := CacheKey(base); again != key {
+		t.Error("expected identical params to produce the same key")
+	}
+
+	for i, p := range []TestGenParams{
+		{GoCode: "package b", Focus: "table"},
+		{GoCode: "package a", Focus: "unit"},
+		{GoCode: "package a", Focus: "table", PackageName: "a_test"},
+	} {
+		if k, _ := CacheKey(p); k == key {
+			t.Errorf("expected variant %d to produce a different key", i)
+		}
+	}
+}
+
+func TestGenerateTests_ExistingTests(t *testing.T) {
+	params := TestGenParams{
+		GoCode: "package calc\n\nfunc Add(a, b int) int { return a + b }\n\nfunc Sub(a, b int) int { return a - b }\n",
+		ExistingTests: `package calc_test
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	if 1+1 != 2 {
+		t.Fail()
+	}
+}
+`,
+	}
+
+	result, err := GenerateTests(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Diff == nil {
+		t.Fatal("expected diff for existing tests")
+	}
+	if strings.Join(result.Diff.Added, ",") != "TestSub" || strings.Join(result.Diff.Skipped, ",") != "TestAdd" {
+		t.Errorf("expected TestSub added and TestAdd skipped, got %+v", result.Diff)
+	}
+	if len(result.Diff.Imports) != 0 {
+		t.Errorf("expected no missing imports, got %v", result.Diff.Imports)
+	}
+
+	wantHeader := "--- a/calc_test.go\n+++ b/calc_test.go\n@@ -7,3 +7,14 @@\n \t\tt.Fail()\n \t}\n }\n+\n+func TestSub(t *testing.T) {\n"
+	if !strings.HasPrefix(result.Diff.Patch, wantHeader) {
+		t.Errorf("unexpected patch:\n%s", result.Diff.Patch)
+	}
+	if strings.Contains(result.Diff.Patch, "TestAdd") {
+		t.Error("expected existing test not to be regenerated")
+	}
+	if result.String() != result.Diff.Patch {
+		t.Error("expected text output to be the patch")
+	}
+
+	// Nothing to add once all tests exist
+	params.ExistingTests += "\nfunc TestSub(t *testing.T) {}\n"
+	result, err = GenerateTests(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Diff.Patch != "" || len(result.Diff.Added) != 0 {
+		t.Errorf("expected empty diff, got %+v", result.Diff)
+	}
+
+	params.ExistingTests = "package calc_test\n\nfunc ("
+	if _, err := GenerateTests(context.Background(), params); err == nil || !strings.Contains(err.Error(), "existing_tests") {
+		t.Errorf("expected parse error for existing tests, got %v", err)
+	}
+}
+
+func TestGenerateTests_ExistingTestsImports(t *testing.T) {
+	result, err := GenerateTests(context.Background(), TestGenParams{
+		GoCode:            "package calc\n\nfunc Add(a, b int) int { return a + b }\n",
+		ExistingTests:     "package calc_test\n\nvar fixture = 1\n",
+		ExistingTestsFile: "pkg/calc/add_test.go",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(result.Diff.Imports, ",") != "testing" {
+		t.Errorf("expected missing testing import, got %v", result.Diff.Imports)
+	}
+	want := "--- a/pkg/calc/add_test.go\n+++ b/pkg/calc/add_test.go\n@@ -1,3 +1,18 @@\n package calc_test\n+\n+import (\n+\t\"testing\"\n+)\n \n var fixture = 1\n+\n+func TestAdd(t *testing.T) {\n"
+	if !strings.HasPrefix(result.Diff.Patch, want) {
+		t.Errorf("unexpected patch:\n%s", result.Diff.Patch)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	old := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}
+
+	// Insertions with overlapping context share a hunk; distant ones do not
+	got := unifiedDiff("f.go", old, map[int][]string{1: {"a"}, 4: {"b"}, 11: {"c", "d"}})
+	want := `--- a/f.go
++++ b/f.go
+@@ -1,7 +1,9 @@
+ 1
++a
+ 2
+ 3
+ 4
++b
+ 5
+ 6
+ 7
+@@ -9,4 +11,6 @@
+ 9
+ 10
+ 11
++c
++d
+ 12
+`
+	if got != want {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := unifiedDiff("f.go", old, map[int][]string{}); got != "" {
+		t.Errorf("expected empty diff, got %q", got)
+	}
+}
+
+func TestGenerateTests_MockPackage(t *testing.T) {
+	code := `package store
+
+type User struct{ Name string }
+
+type Store struct{}
+
+func (s *Store) Get(id string) (*User, error) { return nil, nil }
+func (s *Store) Put(u User) {}
+`
+
+	tests := []struct {
+		name     string
+		params   TestGenParams
+		wantTest []string
+		wantMock []string
+		wantFile string
+	}{
+		{
+			name:     "external test package",
+			params:   TestGenParams{ImportPath: "example.com/app/store"},
+			wantTest: []string{"package store_test", "\"example.com/app/store\"", "Get(id string) (*store.User, error)"},
+			wantMock: []string{"package store_test", "\"example.com/app/store\"", "func (m *MockStore) Put(u store.User)"},
+			wantFile: "mocks_test.go",
+		},
+		{
+			name:     "mocks subpackage",
+			params:   TestGenParams{ImportPath: "example.com/app/store", MockPackage: "mocks"},
+			wantTest: []string{"package store_test"},
+			wantMock: []string{"package mocks\n", "import (\n\t\"example.com/app/store\"\n)", "GetFunc func(id string) (*store.User, error)"},
+			wantFile: "mocks/store_mock.go",
+		},
+		{
+			name:     "same package",
+			params:   TestGenParams{SamePackage: true},
+			wantTest: []string{"package store\n", "Get(id string) (*User, error)"},
+			wantMock: []string{"package store\n", "func (m *MockStore) Put(u User)"},
+			wantFile: "mocks_test.go",
+		},
+		{
+			name:     "import name differs from path",
+			params:   TestGenParams{ImportPath: "example.com/app/store-v2"},
+			wantTest: []string{"store \"example.com/app/store-v2\""},
+			wantFile: "mocks_test.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.GoCode = code
+			tt.params.Focus = "interfaces"
+			result, err := GenerateTests(context.TODO(), tt.params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantTest {
+				if !strings.Contains(result.TestCode, want) {
+					t.Errorf("expected test code to contain %q, got:\n%s", want, result.TestCode)
+				}
+			}
+			for _, want := range tt.wantMock {
+				if !strings.Contains(result.MockCode, want) {
+					t.Errorf("expected mock code to contain %q, got:\n%s", want, result.MockCode)
+				}
+			}
+			if result.MockFile != tt.wantFile {
+				t.Errorf("expected mock file %q, got %q", tt.wantFile, result.MockFile)
+			}
+			if tt.params.SamePackage && strings.Contains(result.MockCode, "import") {
+				t.Error("expected no imports for mocks in the source package")
+			}
+		})
+	}
+}
+
+func TestGenerateTests_ImportPathAssumed(t *testing.T) {
+	result, err := GenerateTests(context.TODO(), TestGenParams{
+		GoCode: "package calc\n\ntype Op int\n\nfunc Apply(op Op, a, b int) int { return a }\n",
+		Focus:  "table",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(result.TestCode, "import (\n\t\"calc\"\n\t\"testing\"\n)") || !strings.Contains(collapseSpace(result.TestCode), "op calc.Op") {
+		t.Errorf("expected qualified source type and import, got:\n%s", result.TestCode)
+	}
+	found := false
+	for _, s := range result.Suggestions {
+		found = found || strings.Contains(s, "import_path")
+	}
+	if !found {
+		t.Errorf("expected suggestion to set import_path, got %v", result.Suggestions)
+	}
+}
+
+func TestGenerateTests_Imports(t *testing.T) {
+	code := `package worker
+
+import (
+	"context"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type Job struct{}
+
+func (j *Job) Run(ctx context.Context, timeout time.Duration, _ int, opts ...string) error { return nil }
+func (j *Job) Decode(n *yaml.Node, fn func(int) (string, error)) {}
+func (j *Job) Serve(h http.Handler, done <-chan struct{}) {}
+
+func Schedule(ctx context.Context, every time.Duration, names ...string) (int, string, error) { return 0, "", nil }
+`
+
+	result, err := GenerateTests(context.TODO(), TestGenParams{GoCode: code, Focus: "interfaces"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantImports := "import (\n\t\"context\"\n\tyaml \"gopkg.in/yaml.v3\"\n\t\"net/http\"\n\t\"time\"\n)"
+	if !strings.Contains(result.TestCode, wantImports) {
+		t.Errorf("expected interface imports %q, got:\n%s", wantImports, result.TestCode)
+	}
+	if !strings.Contains(result.MockCode, wantImports) {
+		t.Errorf("expected mock imports %q, got:\n%s", wantImports, result.MockCode)
+	}
+	for _, want := range []string{
+		"func (m *MockJob) Run(ctx context.Context, timeout time.Duration, p2 int, opts ...string) error {",
+		"return m.RunFunc(ctx, timeout, p2, opts...)",
+		"DecodeFunc func(n *yaml.Node, fn func(int) (string, error))",
+		"func (m *MockJob) Serve(h http.Handler, done <-chan struct{}) {",
+	} {
+		if !strings.Contains(result.MockCode, want) {
+			t.Errorf("expected mock code to contain %q, got:\n%s", want, result.MockCode)
+		}
+	}
+
+	result, err = GenerateTests(context.TODO(), TestGenParams{GoCode: code, Focus: "table"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fields := collapseSpace(result.TestCode)
+	for _, want := range []string{
+		"import ( \"context\" \"errors\" yaml \"gopkg.in/yaml.v3\" \"net/http\" \"testing\" \"time\" \"worker\" )",
+		"name string every time.Duration names []string want int want1 string wantErr bool",
+	} {
+		if !strings.Contains(fields, want) {
+			t.Errorf("expected table test to contain %q, got:\n%s", want, result.TestCode)
+		}
+	}
+}
+
+func TestGenerateTests_Generics(t *testing.T) {
+	code := `package gen
+
+import "cmp"
+
+type Number interface {
+	~int64 | ~float64
+}
+
+type Box[T any] struct{ v T }
+
+func (b *Box[E]) Get() E  { return b.v }
+func (b *Box[T]) Set(v T) { b.v = v }
+
+type Pair[K comparable, V Number] struct {
+	k K
+	v V
+}
+
+func (p Pair[K, V]) Key() K { return p.k }
+
+func Max[T cmp.Ordered](a, b T) (T, error) { return a, nil }
+func Sum[N Number](ns ...N) N               { return 0 }
+func Name[S interface{ String() string }](s S) string { return s.String() }
+`
+
+	result, err := GenerateTests(context.TODO(), TestGenParams{GoCode: code, Focus: "interfaces", ImportPath: "example.com/gen"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"// TODO: Instantiate as Boxer[int] or with other type arguments satisfying [T any]",
+		"type Boxer[T any] interface {\n\tGet() T\n\tSet(v T)\n}",
+		"type Pairer[K comparable, V gen.Number] interface {",
+	} {
+		if !strings.Contains(result.TestCode, want) {
+			t.Errorf("expected interfaces to contain %q, got:\n%s", want, result.TestCode)
+		}
+	}
+	for _, want := range []string{
+		"type MockBox[T any] struct {",
+		"func (m *MockBox[T]) Get() T {",
+		"func (m *MockPair[K, V]) Key() K {",
+	} {
+		if !strings.Contains(result.MockCode, want) {
+			t.Errorf("expected mocks to contain %q, got:\n%s", want, result.MockCode)
+		}
+	}
+	if len(result.Interfaces) != 2 || result.Interfaces[1].TypeParams != "[K comparable, V Number]" {
+		t.Errorf("expected type parameters on the extracted interfaces, got %+v", result.Interfaces)
+	}
+	if result.Compiles == nil || !*result.Compiles {
+		t.Errorf("expected generic mocks to compile, got %v (diagnostics %v)", result.Compiles, result.Diagnostics)
+	}
+
+	result, err = GenerateTests(context.TODO(), TestGenParams{GoCode: code, Focus: "table", ImportPath: "example.com/gen"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fields := collapseSpace(result.TestCode)
+	for _, want := range []string{
+		"// TODO: Instantiate as Max[int] or with other type arguments satisfying [T cmp.Ordered]",
+		"name string a int b int want int wantErr bool",
+		"// TODO: Instantiate as Sum[int64] or with other type arguments satisfying [N Number]",
+		"name string ns []int64 want int64 wantErr bool",
+		"// TODO: Instantiate with type arguments satisfying [S interface{String() string}]; Name[int] is a placeholder",
+	} {
+		if !strings.Contains(fields, want) {
+			t.Errorf("expected table tests to contain %q, got:\n%s", want, result.TestCode)
+		}
+	}
+
+	result, err = GenerateTests(context.TODO(), TestGenParams{GoCode: code, Focus: "unit"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "\t// TODO: Instantiate as Max[int] or with other type arguments satisfying [T cmp.Ordered]\n\t// TODO: Call Max with inputs"; !strings.Contains(result.TestCode, want) {
+		t.Errorf("expected unit test to contain %q, got:\n%s", want, result.TestCode)
+	}
+}
+
+func TestGenerateTests_Methods(t *testing.T) {
+	code := `package store
+
+import "database/sql"
+
+type Store struct{ db *sql.DB }
+
+func NewStore(db *sql.DB, t int) (*Store, error) { return &Store{db: db}, nil }
+
+func OpenStore(path string) *Store { return nil }
+
+func Version() string { return "" }
+
+func (s *Store) Get(key string) (string, error) { return "", nil }
+
+type Counter int
+
+func (c *Counter) Inc() { *c++ }
+
+type Box[T any] struct{ v T }
+
+func (b *Box[E]) Put(v E) { b.v = v }
+
+type cache struct{}
+
+func (c *cache) Len() int { return 0 }
+`
+
+	tests := []struct {
+		name    string
+		params  TestGenParams
+		want    []string
+		notWant []string
+		suggest string
+	}{
+		{
+			name:   "unit tests with detected constructor",
+			params: TestGenParams{GoCode: code, ImportPath: "example.com/store"},
+			want: []string{
+				"func TestStore_Get(t *testing.T) {",
+				"// Arrange s := newTestStore(t) // TODO: Set up test inputs",
+				"// TODO: Call s.Get with inputs _ = s",
+				"// newTestStore returns a Store for tests, built with NewStore func newTestStore(t *testing.T) *store.Store { t.Helper() // TODO: Set up the NewStore arguments var ( db *sql.DB tArg int ) s, err := store.NewStore(db, tArg) if err != nil { t.Fatalf(\"NewStore: %v\", err) } return s }",
+				"func TestCounter_Inc(t *testing.T) { // Arrange var c store.Counter",
+				"// TODO: Instantiate as Box[int] or with other type arguments satisfying [T any] var b store.Box[int]",
+			},
+			notWant: []string{"TestCache_Len", "TestMain"},
+		},
+		{
+			name:   "table tests with constructor hint",
+			params: TestGenParams{GoCode: code, Focus: "table", ImportPath: "example.com/store", ReceiverConstructor: "OpenStore"},
+			want: []string{
+				"name string key string want string wantErr bool",
+				"t.Run(tt.name, func(t *testing.T) { s := newTestStore(t) // TODO: Call s.Get and verify results _ = s })",
+				"func newTestStore(t *testing.T) *store.Store { t.Helper() // TODO: Set up the OpenStore arguments var path string s := store.OpenStore(path) return s }",
+				"name string v int wantErr bool",
+			},
+		},
+		{
+			name:   "unexported types in the same package",
+			params: TestGenParams{GoCode: code, SamePackage: true},
+			want:   []string{"func TestCache_Len(t *testing.T) { // Arrange var c cache"},
+		},
+		{
+			name:    "unknown constructor hint",
+			params:  TestGenParams{GoCode: code, ImportPath: "example.com/store", ReceiverConstructor: "NewThing"},
+			want:    []string{"s, err := store.NewStore(db, tArg)"},
+			suggest: "receiver_constructor NewThing is not declared in the source",
+		},
+		{
+			name:    "hint that constructs no receiver",
+			params:  TestGenParams{GoCode: code, ImportPath: "example.com/store", ReceiverConstructor: "Version"},
+			suggest: "receiver_constructor Version does not construct a type with exported methods",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GenerateTests(context.TODO(), tt.params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			code := collapseSpace(result.TestCode)
+			for _, want := range tt.want {
+				if !strings.Contains(code, want) {
+					t.Errorf("expected test code to contain %q, got:\n%s", want, result.TestCode)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(code, notWant) {
+					t.Errorf("expected test code not to contain %q, got:\n%s", notWant, result.TestCode)
+				}
+			}
+			if tt.suggest != "" && !strings.Contains(strings.Join(result.Suggestions, "\n"), tt.suggest) {
+				t.Errorf("expected a suggestion containing %q, got %v", tt.suggest, result.Suggestions)
+			}
+			if result.Compiles == nil || !*result.Compiles {
+				t.Errorf("expected method tests to compile, got %v (diagnostics %v)", result.Compiles, result.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestImportName(t *testing.T) {
+	tests := map[string]string{
+		"context":                    "context",
+		"net/http":                   "http",
+		"gopkg.in/yaml.v3":           "yaml",
+		"github.com/mattn/go-isatty": "isatty",
+		"example.com/mod/v2":         "mod",
+		"example.com/foo-bar":        "foo_bar",
+	}
+	for path, want := range tests {
+		if got := importName(path); got != want {
+			t.Errorf("importName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestGenerateTests_Compiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		params       TestGenParams
+		wantCompiles *bool
+		wantDiag     string
+	}{
+		{
+			name: "mocks in separate package",
+			params: TestGenParams{
+				GoCode:      "package store\n\nimport \"context\"\n\ntype Item struct{}\n\ntype Store struct{}\n\nfunc (s *Store) Get(ctx context.Context, ids ...int) (*Item, error) { return nil, nil }\n",
+				Focus:       "interfaces",
+				ImportPath:  "example.com/app/store",
+				MockPackage: "mocks",
+			},
+			wantCompiles: boolPtr(true),
+		},
+		{
+			name: "generated name collides with source",
+			params: TestGenParams{
+				GoCode:      "package store\n\ntype MockStore struct{}\n\ntype Store struct{}\n\nfunc (s *Store) Get() {}\n",
+				Focus:       "interfaces",
+				SamePackage: true,
+			},
+			wantCompiles: boolPtr(false),
+			wantDiag:     "MockStore redeclared",
+		},
+		{
+			name: "TestMain and constructor helper",
+			params: TestGenParams{
+				GoCode:     "package store\n\nimport (\n\t\"database/sql\"\n\t\"os\"\n)\n\nvar db, _ = sql.Open(\"postgres\", os.Getenv(\"DATABASE_URL\"))\n\ntype Store struct{}\n\nfunc NewStore(db *sql.DB) (*Store, error) { return &Store{}, nil }\n\nfunc (s *Store) Get(key string) (string, error) { return \"\", nil }\n",
+				Focus:      "table",
+				ImportPath: "example.com/app/store",
+				Style:      Style{Assert: AssertStdlib},
+			},
+			wantCompiles: boolPtr(true),
+		},
+		{
+			name: "unresolved import",
+			params: TestGenParams{
+				GoCode: "package store\n\nimport \"example.invalid/dep\"\n\nfunc Load(c dep.Config) error { return nil }\n",
+				Focus:  "table",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GenerateTests(context.TODO(), tt.params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if (result.Compiles == nil) != (tt.wantCompiles == nil) || (result.Compiles != nil && *result.Compiles != *tt.wantCompiles) {
+				t.Fatalf("Compiles = %v, want %v (diagnostics %v)", result.Compiles, tt.wantCompiles, result.Diagnostics)
+			}
+			if tt.wantDiag == "" {
+				if len(result.Diagnostics) > 0 {
+					t.Errorf("unexpected diagnostics %v", result.Diagnostics)
+				}
+				return
+			}
+			if len(result.Diagnostics) == 0 || !strings.Contains(result.Diagnostics[0].Message, tt.wantDiag) || result.Diagnostics[0].File != mockFile {
+				t.Errorf("expected mock_code diagnostic containing %q, got %v", tt.wantDiag, result.Diagnostics)
+			}
+			if !strings.HasPrefix(result.String(), "// The generated code does not compile:\n//   mock_code:") {
+				t.Errorf("expected diagnostics at the top of the text output, got:\n%s", result.String())
+			}
+		})
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestWriteFiles(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "store")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	generate := func() *TestGenResult {
+		result, err := GenerateTests(context.TODO(), TestGenParams{
+			GoCode: "package store\n\ntype Store struct{}\n\nfunc (s *Store) Get(id string) string { return id }\n",
+			Focus:  "interfaces",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	result := generate()
+	if result.TestFile != "store_test.go" || result.MockFile != "mocks_test.go" {
+		t.Fatalf("unexpected suggested files %q and %q", result.TestFile, result.MockFile)
+	}
+
+	if err := WriteFiles(generate(), dir, WriteOptions{}); err == nil || !strings.Contains(err.Error(), "workspace root") {
+		t.Errorf("expected error without workspace roots, got %v", err)
+	}
+	if err := WriteFiles(generate(), t.TempDir(), WriteOptions{Roots: []string{root}}); err == nil || !strings.Contains(err.Error(), "not inside") {
+		t.Errorf("expected error outside the workspace, got %v", err)
+	}
+
+	// Symlinks cannot lead the files out of the workspace
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "linked")); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFiles(generate(), filepath.Join(root, "linked"), WriteOptions{Roots: []string{root}}); err == nil || !strings.Contains(err.Error(), "not inside") {
+		t.Errorf("expected error for a working directory linked outside the workspace, got %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "testdata")); err != nil {
+		t.Fatal(err)
+	}
+	nested := &TestGenResult{TestFiles: []GeneratedFile{{Path: "testdata/store_test.go", Content: "package store\n"}}}
+	if err := WriteFiles(nested, dir, WriteOptions{Roots: []string{root}}); err == nil || !strings.Contains(err.Error(), "outside the workspace") {
+		t.Errorf("expected error for a file linked outside the workspace, got %v", err)
+	}
+	if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+		t.Errorf("expected nothing written outside the workspace, got %v", entries)
+	}
+	if err := os.Remove(filepath.Join(dir, "testdata")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A rejected path aborts before anything is written
+	denied := generate()
+	deny := func(path string) error { return fmt.Errorf("denied %s", filepath.Base(path)) }
+	if err := WriteFiles(denied, dir, WriteOptions{Roots: []string{root}, Check: deny}); err == nil || err.Error() != "denied store_test.go" {
+		t.Errorf("expected check error, got %v", err)
+	}
+	if len(denied.Written) != 0 {
+		t.Errorf("expected nothing written, got %v", denied.Written)
+	}
+
+	if err := WriteFiles(result, dir, WriteOptions{Roots: []string{root}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{filepath.Join(dir, "store_test.go"), filepath.Join(dir, "mocks_test.go")}
+	if len(result.Written) != 2 || result.Written[0] != want[0] || result.Written[1] != want[1] {
+		t.Errorf("expected written %v, got %v", want, result.Written)
+	}
+	content, err := os.ReadFile(want[1])
+	if err != nil || string(content) != result.MockCode {
+		t.Errorf("expected mock code on disk, got %q (%v)", content, err)
+	}
+
+	// Existing files are never overwritten
+	again := generate()
+	if err := WriteFiles(again, dir, WriteOptions{Roots: []string{root}}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected error for existing files, got %v", err)
+	}
+	if len(again.Written) != 0 {
+		t.Errorf("expected nothing written, got %v", again.Written)
+	}
+}
+
+func TestGenerateTests_Style(t *testing.T) {
+	code := `package calc
+
+import "errors"
+
+type Store struct{ m map[string]int }
+
+func NewStore() (*Store, error) { return &Store{}, nil }
+
+func (s *Store) Get(key string) (int, bool) { return s.m[key], true }
+
+func Div(a, b int) (int, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return a / b, nil
+}
+
+func Reset() {}
+`
+
+	tests := []struct {
+		name  string
+		style Style
+		want  []string
+		skip  []string
+	}{
+		{
+			name:  "underscore naming",
+			style: Style{Naming: NamingUnderscore},
+			want:  []string{"func Test_Div(t *testing.T)", "func Test_Store_Get(t *testing.T)", "// TODO: Call Div and verify results"},
+		},
+		{
+			name:  "stdlib assertions",
+			style: Style{Assert: AssertStdlib},
+			want: []string{
+				"got, err := calc.Div(tt.a, tt.b)",
+				"if (err != nil) != tt.wantErr {",
+				`t.Fatalf("calc.Div() error = %v, wantErr %v", err, tt.wantErr)`,
+				`t.Errorf("calc.Div() = %v, want %v", got, tt.want)`,
+				"got, got1 := s.Get(tt.key)",
+				`t.Errorf("s.Get() got1 = %v, want %v", got1, tt.want1)`,
+				"\t\t\tcalc.Reset()\n",
+				`"reflect"`,
+			},
+			skip: []string{"require", "TODO: Call"},
+		},
+		{
+			name:  "testify assertions",
+			style: Style{Assert: AssertTestify},
+			want: []string{
+				`"github.com/stretchr/testify/require"`,
+				`require.NoError(t, err, "NewStore")`,
+				"if tt.wantErr {\n\t\t\t\trequire.Error(t, err)\n\t\t\t\treturn\n\t\t\t}\n\t\t\trequire.NoError(t, err)\n\t\t\trequire.Equal(t, tt.want, got)",
+				"require.Equal(t, tt.want1, got1)",
+			},
+			skip: []string{`"reflect"`, "t.Fatalf"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GenerateTests(context.TODO(), TestGenParams{GoCode: code, Focus: "table", ImportPath: "example.com/calc", Style: tt.style})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(result.TestCode, want) {
+					t.Errorf("expected %q in:\n%s", want, result.TestCode)
+				}
+			}
+			for _, skip := range tt.skip {
+				if strings.Contains(result.TestCode, skip) {
+					t.Errorf("unexpected %q in:\n%s", skip, result.TestCode)
+				}
+			}
+			if tt.style.Assert == AssertStdlib && (result.Compiles == nil || !*result.Compiles) {
+				t.Errorf("expected the stdlib assertions to compile, got %v %v", result.Compiles, result.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestGenerateTests_TestMain(t *testing.T) {
+	code := `package store
+
+import (
+	"database/sql"
+	"net/http"
+	"os"
+)
+
+var (
+	ErrMissing = errors.New("missing")
+	client     *http.Client
+	db         = mustOpen()
+	conn, _    = sql.Open("postgres", dsn())
+)
+
+func mustOpen() *sql.DB { return nil }
+
+func dsn() string {
+	if v, ok := os.LookupEnv("DATABASE_URL"); ok {
+		return v
+	}
+	return os.Getenv("DATABASE_URL") + os.Getenv("DB_SCHEMA")
+}
+
+func Load() {}
+`
+	result, err := GenerateTests(context.TODO(), TestGenParams{GoCode: code, ImportPath: "example.com/store"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := collapseSpace(result.TestCode)
+	for _, want := range []string{
+		"import ( \"os\" \"testing\" )",
+		"func TestMain(m *testing.M) { // Setup // TODO: Set the environment variables the package reads // os.Setenv(\"DATABASE_URL\", \"\") // os.Setenv(\"DB_SCHEMA\", \"\")",
+		"// TODO: Start or fake the services of the package clients // - client (*http.Client) // - conn (from sql.Open) code := m.Run()",
+		"// Teardown // TODO: Close clients and remove test data os.Exit(code) }",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected test code to contain %q, got:\n%s", want, result.TestCode)
+		}
+	}
+	if strings.Contains(got, "ErrMissing") || strings.Contains(got, "- db") {
+		t.Errorf("expected only clients to be listed, got:\n%s", result.TestCode)
+	}
+
+	// A package without dependencies, or with its own TestMain, gets none
+	for _, src := range []string{
+		"package store\n\nfunc Load() {}\n",
+		"package store\n\nimport (\n\t\"os\"\n\t\"testing\"\n)\n\nvar home = os.Getenv(\"HOME\")\n\nfunc TestMain(m *testing.M) { os.Exit(m.Run()) }\n",
+	} {
+		result, err := GenerateTests(context.TODO(), TestGenParams{GoCode: src, ImportPath: "example.com/store"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(result.TestCode, "func TestMain(") {
+			t.Errorf("unexpected TestMain in:\n%s", result.TestCode)
+		}
+	}
+}
+
+func TestGenerateTests_Context(t *testing.T) {
+	code := `package store
+
+import "context"
+
+type Store struct{}
+
+func NewStore(ctx context.Context) *Store { return &Store{} }
+
+func (s *Store) Get(ctx context.Context, key string) (string, error) { return "", ctx.Err() }
+
+func Ping(ctx context.Context) {}
+`
+	renamed := `package store
+
+import ctxpkg "context"
+
+type Store struct{}
+
+func (s *Store) Get(ctx ctxpkg.Context, key string) (string, error) { return "", ctx.Err() }
+
+func Ping(ctx ctxpkg.Context) {}
+`
+
+	tests := []struct {
+		name   string
+		params TestGenParams
+		want   []string
+	}{
+		{
+			name:   "table tests before Go 1.24",
+			params: TestGenParams{GoCode: code, Focus: "table", Style: Style{Assert: AssertStdlib}},
+			want: []string{
+				"name string key string want string wantErr bool",
+				"s := newTestStore(t) ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second) defer cancel() got, err := s.Get(ctx, tt.key)",
+				"func TestStore_Get_ContextCanceled(t *testing.T) { s := newTestStore(t) ctx, cancel := context.WithCancel(context.Background()) cancel() // TODO: Set up the Get arguments var key string _, err := s.Get(ctx, key) if !errors.Is(err, context.Canceled) { t.Errorf(\"s.Get() error = %v, want %v\", err, context.Canceled) } }",
+				"func TestPing_ContextCanceled(t *testing.T) { ctx, cancel := context.WithCancel(context.Background()) cancel() // TODO: Call Ping with ctx and verify that it stops early _ = ctx }",
+				"s := store.NewStore(context.Background())",
+			},
+		},
+		{
+			name:   "unit tests from Go 1.24",
+			params: TestGenParams{GoCode: code, GoVersion: "1.24", Style: Style{Assert: AssertTestify}},
+			want: []string{
+				"// Arrange s := newTestStore(t) ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second) defer cancel() // TODO: Set up test inputs",
+				"// TODO: Call s.Get with ctx and inputs _ = s _ = ctx",
+				"ctx, cancel := context.WithCancel(t.Context()) cancel()",
+				"require.ErrorIs(t, err, context.Canceled)",
+				"s := store.NewStore(t.Context())",
+			},
+		},
+		{
+			name:   "renamed context import",
+			params: TestGenParams{GoCode: renamed, Focus: "table", Style: Style{Assert: AssertStdlib}},
+			want: []string{
+				"name string key string want string wantErr bool",
+				"ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second) defer cancel() got, err := s.Get(ctx, tt.key)",
+				"func TestPing_ContextCanceled(t *testing.T) { ctx, cancel := context.WithCancel(context.Background()) cancel()",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.ImportPath = "example.com/store"
+			result, err := GenerateTests(context.TODO(), tt.params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			code := collapseSpace(result.TestCode)
+			for _, want := range tt.want {
+				if !strings.Contains(code, want) {
+					t.Errorf("expected test code to contain %q, got:\n%s", want, result.TestCode)
+				}
+			}
+			if result.Compiles != nil && !*result.Compiles {
+				t.Errorf("expected the generated code to compile, got %v", result.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestGenerateTests_StableOutput(t *testing.T) {
+	code := `package store
+
+import (
+	"context"
+	"os"
+)
+
+var region = os.Getenv("REGION")
+
+type Store struct{}
+
+func NewStore() (*Store, error) { return &Store{}, nil }
+
+func (s *Store) Get(ctx context.Context, key string) (string, error) { return "", nil }
+func (s *Store) Put(key, value string) error                          { return nil }
+
+type Cache struct{}
+
+func (c *Cache) Len() int { return 0 }
+
+type Queue[T any] struct{}
+
+func (q *Queue[T]) Push(v T) {}
+`
+
+	for _, focus := range []string{"interfaces", "table", "unit"} {
+		params := TestGenParams{GoCode: code, Focus: focus, ImportPath: "example.com/store", Style: Style{Assert: AssertStdlib}}
+		first, err := GenerateTests(context.TODO(), params)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want, _ := json.Marshal(first)
+		for i := 0; i < 3; i++ {
+			again, err := GenerateTests(context.TODO(), params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, _ := json.Marshal(again); string(got) != string(want) {
+				t.Fatalf("%s: run %d differs from the first:\n%s\nwant\n%s", focus, i, got, want)
+			}
+		}
+	}
+}
+
+func TestGenerateTests_PerFunctionLayout(t *testing.T) {
+	code := "package calc\n\ntype Store struct{}\n\nfunc (s *Store) Get(key string) int { return 0 }\n\nfunc ParseURL(s string) (string, error) { return s, nil }\n\nfunc Reset() {}\n"
+	result, err := GenerateTests(context.TODO(), TestGenParams{
+		GoCode:     code,
+		Focus:      "table",
+		ImportPath: "example.com/calc",
+		Style:      Style{Layout: LayoutPerFunction, Assert: AssertStdlib},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	files := result.Files()
+	if len(files) != 3 || files[0].Path != "store_get_test.go" || files[1].Path != "parse_url_test.go" || files[2].Path != "reset_test.go" {
+		t.Fatalf("unexpected files %+v", files)
+	}
+	if !strings.Contains(files[0].Content, "func TestStore_Get(") || strings.Contains(files[0].Content, "TestParseURL") {
+		t.Errorf("expected only the Get test in store_get_test.go:\n%s", files[0].Content)
+	}
+	// Each file imports only what it uses
+	if strings.Contains(files[2].Content, `"reflect"`) {
+		t.Errorf("unexpected reflect import in reset_test.go:\n%s", files[2].Content)
+	}
+	if !strings.Contains(files[1].Content, "import (\n\t\"example.com/calc\"\n\t\"reflect\"\n\t\"testing\"\n)") {
+		t.Errorf("unexpected imports in parse_url_test.go:\n%s", files[1].Content)
+	}
+	if !strings.Contains(result.TestCode, "TestParseURL") {
+		t.Error("expected TestCode to keep all tests")
+	}
+
+	// TestMain and the test helpers get files of their own
+	code = "package calc\n\nimport \"os\"\n\nvar home = os.Getenv(\"HOME\")\n\ntype Store struct{}\n\nfunc NewStore() *Store { return nil }\n\nfunc (s *Store) Get(key string) int { return 0 }\n"
+	result, err = GenerateTests(context.TODO(), TestGenParams{GoCode: code, ImportPath: "example.com/calc", Style: Style{Layout: LayoutPerFunction}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files = result.Files()
+	if len(files) != 4 || files[0].Path != "main_test.go" || files[2].Path != "store_get_test.go" || files[3].Path != "helpers_test.go" {
+		t.Fatalf("unexpected files %+v", files)
+	}
+	if !strings.Contains(files[3].Content, "func newTestStore(t *testing.T) *calc.Store {") {
+		t.Errorf("expected the Store helper in helpers_test.go:\n%s", files[3].Content)
+	}
+}
+
+func TestGenerateTests_InvalidStyle(t *testing.T) {
+	_, err := GenerateTests(context.TODO(), TestGenParams{GoCode: "package calc\n", Style: Style{Assert: "gomega"}})
+	if err == nil || !strings.Contains(err.Error(), "style.assert") {
+		t.Errorf("expected a style error, got %v", err)
+	}
+}
+
+func TestTestFileName(t *testing.T) {
+	tests := map[string]string{
+		"TestAdd":         "add_test.go",
+		"TestStore_Get":   "store_get_test.go",
+		"Test_Store_Get":  "store_get_test.go",
+		"TestParseURL":    "parse_url_test.go",
+		"TestHTTPHandler": "http_handler_test.go",
+	}
+	for test, want := range tests {
+		if got := testFileName(test); got != want {
+			t.Errorf("testFileName(%q) = %q, want %q", test, got, want)
+		}
+	}
+}
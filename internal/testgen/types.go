@@ -1,25 +1,68 @@
 package testgen
 
+import (
+	"strings"
+
+	"mcp-go-assistant/internal/gocheck"
+)
+
 // TestGenParams represents the parameters for the test generation tool
 type TestGenParams struct {
 	GoCode      string `json:"go_code" jsonschema:"description:The Go code to generate tests for"`
 	PackageName string `json:"package_name,omitempty" jsonschema:"description:Package name for generated tests (defaults to package_test)"`
 	Focus       string `json:"focus,omitempty" jsonschema:"description:Focus area: 'interfaces' for interface extraction and mocks or 'unit' for unit tests or 'table' for table-driven tests"`
+
+	SamePackage bool   `json:"same_package,omitempty" jsonschema:"description:Optional; generate tests and mocks inside the source package instead of the external _test package"`
+	MockPackage string `json:"mock_package,omitempty" jsonschema:"description:Optional package for mocks, e.g. 'mocks' to emit them into a mocks/ subpackage that imports the source package"`
+	ImportPath  string `json:"import_path,omitempty" jsonschema:"description:Optional import path of the source package, used when generated code in another package refers to its types"`
+
+	ReceiverConstructor string `json:"receiver_constructor,omitempty" jsonschema:"description:Optional name of the function constructing method receivers, e.g. 'NewStore'; by default method tests call a New<Type> constructor when the source declares one and use the zero value otherwise"`
+	Style               Style  `json:"style,omitempty" jsonschema:"description:Optional naming, file layout and assertion style of the generated tests; unset fields use the server settings"`
+	GoVersion           string `json:"go_version,omitempty" jsonschema:"description:Optional Go version of the module the tests are for, e.g. '1.24'; from 1.24 tests of functions taking a context.Context derive it from t.Context() instead of context.Background()"`
+
+	ExistingTests     string `json:"existing_tests,omitempty" jsonschema:"description:Optional contents of the existing test file; the result then includes a diff adding only what is missing"`
+	ExistingTestsFile string `json:"existing_tests_file,omitempty" jsonschema:"description:Optional path of the existing test file used in the diff headers (defaults to <package>_test.go)"`
+
+	WriteFiles bool   `json:"write_files,omitempty" jsonschema:"description:Optional; also write the generated files into working_dir, which must be inside a registered workspace. Existing files are never overwritten."`
+	WorkingDir string `json:"working_dir,omitempty" jsonschema:"description:Directory of the source package that write_files writes into"`
+	Confirm    bool   `json:"confirm,omitempty" jsonschema:"description:Optional; confirms write_files when the server's write policy requires confirmation"`
+
+	IdempotencyKey string `json:"idempotency_key,omitempty" jsonschema:"description:Optional client-chosen key; repeating the call with the same key returns the stored result of the first successful call instead of running the tool again"`
+
+	// Set by the server when go_code was spooled to a temporary file; not
+	// part of the tool schema
+	GoCodeFile string `json:"-"`
 }
 
 // TestGenResult represents the result of test generation
 type TestGenResult struct {
-	TestCode    string      `json:"test_code"`
-	MockCode    string      `json:"mock_code,omitempty"`
-	Interfaces  []Interface `json:"interfaces,omitempty"`
-	Suggestions []string    `json:"suggestions,omitempty"`
+	TestCode    string          `json:"test_code"`
+	TestFile    string          `json:"test_file"`            // Suggested path of TestCode, relative to the source package
+	TestFiles   []GeneratedFile `json:"test_files,omitempty"` // TestCode split into one file per test in the per-function layout
+	MockCode    string          `json:"mock_code,omitempty"`
+	MockFile    string          `json:"mock_file,omitempty"` // Suggested path of MockCode, relative to the source package
+	Interfaces  []Interface     `json:"interfaces,omitempty"`
+	Suggestions []string        `json:"suggestions,omitempty"`
+	Diff        *TestDiff       `json:"diff,omitempty"` // Set when existing tests were given
+
+	Compiles    *bool                `json:"compiles,omitempty"`    // Whether the generated code type-checks against the source; unset when it could not be verified
+	Diagnostics []gocheck.Diagnostic `json:"diagnostics,omitempty"` // Compilation errors in the generated code
+
+	Written []string `json:"written,omitempty"` // Absolute paths of the files written with write_files
+}
+
+// GeneratedFile is a generated file with its suggested path
+type GeneratedFile struct {
+	Path    string `json:"path"` // Relative to the source package, slash-separated
+	Content string `json:"content"`
 }
 
 // Interface represents an extracted or generated interface
 type Interface struct {
-	Name    string   `json:"name"`
-	Methods []Method `json:"methods"`
-	ForType string   `json:"for_type,omitempty"`
+	Name       string   `json:"name"`
+	Methods    []Method `json:"methods"`
+	ForType    string   `json:"for_type,omitempty"`
+	TypeParams string   `json:"type_params,omitempty"` // Type parameter list of generic types, e.g. "[T any]"
 }
 
 // Method represents a method signature
@@ -29,11 +72,48 @@ type Method struct {
 	Returns string `json:"returns"`
 }
 
-// String returns the test code as a string
+// String returns the test code as a string, or the diff against the
+// existing tests when they were given
 func (r *TestGenResult) String() string {
-	result := r.TestCode
+	if r.Diff != nil {
+		if r.Diff.Patch == "" {
+			return "// The existing tests already define all generated declarations.\n"
+		}
+		return r.diagnosticsComment() + r.Diff.Patch
+	}
+
+	result := r.diagnosticsComment() + r.TestCode
 	if r.MockCode != "" {
 		result += "\n\n// --- Mock Implementations ---\n\n" + r.MockCode
 	}
 	return result
 }
+
+// Files returns the generated test and mock code with their suggested
+// paths, with a file per test in the per-function layout. Compilation
+// errors are listed in a comment at the top of the first test file.
+func (r *TestGenResult) Files() []GeneratedFile {
+	files := []GeneratedFile{{Path: r.TestFile, Content: r.diagnosticsComment() + r.TestCode}}
+	if len(r.TestFiles) > 0 {
+		files = append([]GeneratedFile(nil), r.TestFiles...)
+		files[0].Content = r.diagnosticsComment() + files[0].Content
+	}
+	if r.MockCode != "" {
+		files = append(files, GeneratedFile{Path: r.MockFile, Content: r.MockCode})
+	}
+	return files
+}
+
+// diagnosticsComment lists the compilation errors of the generated code as
+// a comment, or returns an empty string when there are none
+func (r *TestGenResult) diagnosticsComment() string {
+	if len(r.Diagnostics) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("// The generated code does not compile:\n")
+	for _, d := range r.Diagnostics {
+		sb.WriteString("//   " + d.String() + "\n")
+	}
+	return sb.String() + "\n"
+}
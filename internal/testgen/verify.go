@@ -0,0 +1,71 @@
+package testgen
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"mcp-go-assistant/internal/gocheck"
+)
+
+// File names of the generated code in compilation diagnostics
+const (
+	sourceFile = "source.go"
+	testFile   = "test_code"
+	mockFile   = "mock_code"
+)
+
+// verifyGenerated type-checks the generated code against a synthetic
+// package containing the source and records whether it compiles. The
+// result is left unknown when imports of the source or the generated code
+// cannot be resolved.
+func verifyGenerated(ctx context.Context, goCode string, l *layout, result *TestGenResult) {
+	sourcePath := l.importPath
+	if sourcePath == "" {
+		sourcePath = l.source
+	}
+
+	files := map[string]map[string]string{
+		l.source: {sourceFile: goCode},
+	}
+	add := func(pkg, name, code string) {
+		if code == "" {
+			return
+		}
+		if files[pkg] == nil {
+			files[pkg] = make(map[string]string)
+		}
+		files[pkg][name] = code
+	}
+	add(l.testPkg, testFile, result.TestCode)
+	add(l.mockPkg, mockFile, result.MockCode)
+
+	var pkgs []gocheck.Package
+	for pkg, pkgFiles := range files {
+		pkgPath := sourcePath
+		if pkg != l.source {
+			pkgPath = path.Join(sourcePath, pkg)
+		}
+		pkgs = append(pkgs, gocheck.Package{Path: pkgPath, Files: pkgFiles})
+	}
+	// Check in a fixed order so diagnostics are listed the same way every run
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Path < pkgs[j].Path })
+
+	check := gocheck.Check(ctx, pkgs)
+	if len(check.Unresolved) > 0 {
+		result.Suggestions = append(result.Suggestions, fmt.Sprintf(
+			"Could not verify that the generated code compiles because these imports could not be resolved: %s.",
+			strings.Join(check.Unresolved, ", ")))
+		return
+	}
+
+	compiles := true
+	result.Diagnostics = check.Errors(testFile, mockFile)
+	if len(result.Diagnostics) > 0 {
+		compiles = false
+		result.Suggestions = append(result.Suggestions, "The generated code does not compile; see diagnostics.")
+	}
+	result.Compiles = &compiles
+}
@@ -0,0 +1,87 @@
+package testgen
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"mcp-go-assistant/internal/workspace"
+)
+
+// WriteOptions configures where and whether generated files are written
+type WriteOptions struct {
+	Roots []string // Registered workspace roots the directory must be inside
+
+	// Check is consulted for the absolute path of every file before any is
+	// written; an error aborts the write. Nil allows every path.
+	Check func(path string) error
+}
+
+// WriteFiles writes the generated files of result into dir, which must lie
+// inside one of the workspace roots, and records the written paths in
+// result. Symlinks are resolved for dir and for every file, so none is
+// written outside the roots through a link. Existing files are never
+// overwritten; the call fails before writing anything if one of the files
+// exists or is rejected by Check.
+func WriteFiles(result *TestGenResult, dir string, opts WriteOptions) error {
+	if dir == "" {
+		return fmt.Errorf("working_dir is required with write_files")
+	}
+	if result.Diff != nil {
+		return fmt.Errorf("write_files cannot update existing tests; apply the returned diff instead")
+	}
+
+	root, err := workspace.Dir("writing test files", dir, opts.Roots)
+	if err != nil {
+		return err
+	}
+
+	files := result.Files()
+	targets := make([]string, len(files))
+	for i, f := range files {
+		if !filepath.IsLocal(filepath.FromSlash(f.Path)) {
+			return fmt.Errorf("generated file %s is outside the working directory", f.Path)
+		}
+		targets[i] = filepath.Join(root, filepath.FromSlash(f.Path))
+		if !workspace.Contains(opts.Roots, targets[i]) {
+			return fmt.Errorf("generated file %s would be written outside the workspace", f.Path)
+		}
+		if _, err := os.Lstat(targets[i]); err == nil {
+			return fmt.Errorf("%s already exists; pass its contents as existing_tests to get a diff instead", f.Path)
+		}
+		if opts.Check != nil {
+			if err := opts.Check(targets[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, f := range files {
+		if err := os.MkdirAll(filepath.Dir(targets[i]), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %v", f.Path, err)
+		}
+		if err := writeNew(targets[i], f.Content); err != nil {
+			return fmt.Errorf("failed to write %s: %v", f.Path, err)
+		}
+		result.Written = append(result.Written, targets[i])
+	}
+	return nil
+}
+
+// writeNew writes content to a file that must not exist yet
+func writeNew(path, content string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("file was created concurrently")
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(content); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,41 @@
+package testutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Benchmark corpus sizes, in functions
+const (
+	CorpusSmall  = 5
+	CorpusMedium = 50
+	CorpusLarge  = 500
+)
+
+// GoSource returns a deterministic Go file with the given number of
+// functions. It mixes methods, error handling, loops and exported
+// declarations so every analyzer check has work to do.
+func GoSource(functions int) string {
+	var b strings.Builder
+	b.WriteString("package corpus\n\nimport (\n\t\"errors\"\n\t\"fmt\"\n)\n\n")
+	b.WriteString("// Store keeps named values\ntype Store struct {\n\tvalues map[string]int\n}\n\n")
+	b.WriteString("// ErrMissing is returned for unknown names\nvar ErrMissing = errors.New(\"missing\")\n\n")
+
+	for i := 0; i < functions; i++ {
+		switch i % 3 {
+		case 0:
+			fmt.Fprintf(&b, "// Get%d returns the value stored under name\n", i)
+			fmt.Fprintf(&b, "func (s *Store) Get%d(name string) (int, error) {\n", i)
+			b.WriteString("\tv, ok := s.values[name]\n\tif !ok {\n\t\treturn 0, ErrMissing\n\t}\n\treturn v, nil\n}\n\n")
+		case 1:
+			fmt.Fprintf(&b, "func join%d(items []string) string {\n", i)
+			b.WriteString("\tout := \"\"\n\tfor _, item := range items {\n\t\tout = out + item\n\t}\n\treturn out\n}\n\n")
+		default:
+			fmt.Fprintf(&b, "// Check%d validates n\n", i)
+			fmt.Fprintf(&b, "func Check%d(n int) error {\n", i)
+			b.WriteString("\tif n < 0 {\n\t\treturn fmt.Errorf(\"negative: %d\", n)\n\t}\n")
+			b.WriteString("\tswitch {\n\tcase n > 100:\n\t\treturn errors.New(\"too large\")\n\tcase n%2 == 0:\n\t\treturn nil\n\t}\n\treturn nil\n}\n\n")
+		}
+	}
+	return b.String()
+}
@@ -37,6 +37,26 @@ func TempFile(t *testing.T, dir, name, content string) string {
 	return path
 }
 
+// WriteModule creates the files, keyed by slash-separated path relative to
+// a new temporary directory, and returns the directory. Parent directories
+// are created as needed.
+func WriteModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	return dir
+}
+
 // CreateConfigFile creates a temporary YAML config file
 func CreateConfigFile(t *testing.T, content string) string {
 	t.Helper()
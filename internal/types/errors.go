@@ -79,6 +79,11 @@ var (
 		category:   "client",
 		statusCode: 400,
 	}
+	ErrorTypeServerBusy = ErrorType{
+		code:       "SERVER_BUSY",
+		category:   "server_busy",
+		statusCode: 503,
+	}
 )
 
 // mcpError is the concrete implementation of MCPError
@@ -274,6 +279,17 @@ func NewBadRequestError(message string, details ...interface{}) MCPError {
 	return addDetails(err, details...)
 }
 
+// NewServerBusyError creates a server busy error
+func NewServerBusyError(message string, details ...interface{}) MCPError {
+	err := NewMCPError(
+		ErrorTypeServerBusy.code,
+		ErrorTypeServerBusy.category,
+		ErrorTypeServerBusy.statusCode,
+		message,
+	)
+	return addDetails(err, details...)
+}
+
 // WrapError wraps an existing error with MCPError context
 func WrapError(err error, message string, details ...interface{}) MCPError {
 	if err == nil {
@@ -321,6 +337,14 @@ func WrapCircuitBreakerError(err error, message string, details ...interface{})
 	return wrapWithCode(err, ErrorTypeCircuitBreaker, message, details...)
 }
 
+// WrapNotFoundError wraps an error as a not found error
+func WrapNotFoundError(err error, message string, details ...interface{}) MCPError {
+	if err == nil {
+		return nil
+	}
+	return wrapWithCode(err, ErrorTypeNotFound, message, details...)
+}
+
 // Errorf creates a formatted error with the given code and category
 func Errorf(code, category string, statusCode int, format string, args ...interface{}) MCPError {
 	message := fmt.Sprintf(format, args...)
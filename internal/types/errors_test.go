@@ -127,6 +127,13 @@ func TestPredefinedErrors(t *testing.T) {
 			wantCat:    "client",
 			wantStatus: 400,
 		},
+		{
+			name:       "server busy error",
+			createErr:  NewServerBusyError,
+			wantCode:   "SERVER_BUSY",
+			wantCat:    "server_busy",
+			wantStatus: 503,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind classifies the failures of the tools' own work, so metrics and
+// retry policies can tell them apart with errors.As instead of matching
+// error text
+type ErrorKind string
+
+// Tool-domain error kinds
+const (
+	KindPackageNotFound      ErrorKind = "package_not_found"
+	KindParseFailure         ErrorKind = "parse_error"
+	KindToolchainMissing     ErrorKind = "toolchain_missing"
+	KindModuleDownloadFailed ErrorKind = "module_download_failed"
+)
+
+// Retryable reports whether a failure of the kind may succeed when the call
+// is repeated. Only module downloads depend on the network; the other kinds
+// fail the same way until the input or the installation changes.
+func (k ErrorKind) Retryable() bool {
+	return k == KindModuleDownloadFailed
+}
+
+// CausedByInput reports whether a failure of the kind is caused by the
+// request, such as code that does not parse or a package path that does not
+// resolve, rather than by the server or its environment
+func (k ErrorKind) CausedByInput() bool {
+	return k == KindParseFailure || k == KindPackageNotFound
+}
+
+// KindError is a tool-domain error of a known kind. Its message is that of
+// the wrapped error.
+type KindError struct {
+	Kind      ErrorKind
+	Err       error
+	RequestID string // Request the error was raised for, "" outside of a request
+}
+
+// Error implements the error interface
+func (e *KindError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *KindError) Unwrap() error {
+	return e.Err
+}
+
+// NewKindError returns an error of the kind formatted as by fmt.Errorf
+func NewKindError(kind ErrorKind, format string, args ...interface{}) error {
+	return &KindError{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// KindOf returns the kind of the first KindError in err's chain
+func KindOf(err error) (ErrorKind, bool) {
+	var kerr *KindError
+	if errors.As(err, &kerr) {
+		return kerr.Kind, true
+	}
+	return "", false
+}
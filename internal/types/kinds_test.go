@@ -0,0 +1,50 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestKindOf(t *testing.T) {
+	err := NewKindError(KindParseFailure, "failed to parse %s", "main.go")
+	if err.Error() != "failed to parse main.go" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+
+	// The kind is found through wrapping errors, including MCP errors
+	wrapped := WrapError(fmt.Errorf("review: %w", err), "code-review failed")
+	if kind, ok := KindOf(wrapped); !ok || kind != KindParseFailure {
+		t.Errorf("KindOf() = %q, %v; want %q", kind, ok, KindParseFailure)
+	}
+
+	if _, ok := KindOf(errors.New("plain")); ok {
+		t.Error("expected no kind for a plain error")
+	}
+}
+
+func TestErrorKind_Retryable(t *testing.T) {
+	for kind, want := range map[ErrorKind]bool{
+		KindPackageNotFound:      false,
+		KindParseFailure:         false,
+		KindToolchainMissing:     false,
+		KindModuleDownloadFailed: true,
+	} {
+		if got := kind.Retryable(); got != want {
+			t.Errorf("%s.Retryable() = %v, want %v", kind, got, want)
+		}
+	}
+}
+
+func TestErrorKind_CausedByInput(t *testing.T) {
+	for kind, want := range map[ErrorKind]bool{
+		KindPackageNotFound:      true,
+		KindParseFailure:         true,
+		KindToolchainMissing:     false,
+		KindModuleDownloadFailed: false,
+	} {
+		if got := kind.CausedByInput(); got != want {
+			t.Errorf("%s.CausedByInput() = %v, want %v", kind, got, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package validations
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// codeMatcher finds dangerous patterns in code. Patterns ending in "(" match
+// call expressions of the function they name; the others match the code
+// outside comments and string literals.
+type codeMatcher struct {
+	code   string
+	calls  map[string]bool // Called functions; nil when the code does not parse
+	parsed bool
+	text   string // Code with comments and literals blanked; "" until needed
+}
+
+// matches reports whether pattern occurs in the code
+func (m *codeMatcher) matches(pattern string) bool {
+	if name, isCall := strings.CutSuffix(pattern, "("); isCall {
+		if !m.parsed {
+			m.calls = calledFunctions(m.code)
+			m.parsed = true
+		}
+		if m.calls != nil {
+			return m.calls[name]
+		}
+	}
+	if m.text == "" {
+		m.text = blankLiterals(m.code)
+	}
+	return strings.Contains(m.text, pattern)
+}
+
+// calledFunctions returns the functions called in code, as written at the
+// call site, e.g. "exec.Command". Code may be a file, declarations without
+// a package clause or statements; it returns nil if none of them parse.
+func calledFunctions(code string) map[string]bool {
+	fset := token.NewFileSet()
+	var file *ast.File
+	for _, src := range []string{code, "package p\n" + code, "package p\nfunc _() {\n" + code + "\n}"} {
+		if f, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution); err == nil {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return nil
+	}
+
+	calls := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			fun := ast.Unparen(call.Fun)
+			// Instantiations of generic functions are matched by name
+			switch index := fun.(type) {
+			case *ast.IndexExpr:
+				fun = index.X
+			case *ast.IndexListExpr:
+				fun = index.X
+			}
+			calls[types.ExprString(fun)] = true
+		}
+		return true
+	})
+	return calls
+}
+
+// blankLiterals replaces the comments, string and character literals in
+// code with spaces, keeping everything else in place. Code that is not Go
+// is scanned as far as possible.
+func blankLiterals(code string) string {
+	src := []byte(code)
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var s scanner.Scanner
+	s.Init(file, src, func(token.Position, string) {}, scanner.ScanComments)
+	text := []byte(code)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok != token.COMMENT && tok != token.STRING && tok != token.CHAR {
+			continue
+		}
+		start := file.Offset(pos)
+		for i := start; i < start+len(lit) && i < len(text); i++ {
+			if text[i] != '\n' {
+				text[i] = ' '
+			}
+		}
+	}
+	return string(text)
+}
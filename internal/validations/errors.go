@@ -8,10 +8,12 @@ import (
 
 // ValidationError represents a validation error with detailed context
 type ValidationError struct {
-	Field   string // Which field failed validation
-	Rule    string // Which rule failed
-	Value   string // The invalid value (truncated if too long)
-	Message string // Human-readable error message
+	Field     string // Which field failed validation
+	Rule      string // Which rule failed
+	Value     string // The invalid value (truncated if too long)
+	Message   string // Human-readable error message
+	Warning   bool   // The rule only reports the value; callers accept it
+	Forbidden bool   // The value is well-formed but refers to something off limits
 }
 
 // Error implements the error interface
@@ -27,6 +29,37 @@ func (e *ValidationError) Unwrap() error {
 	return nil
 }
 
+// Warning is returned by rules that report a value without rejecting it
+type Warning struct {
+	Message string
+}
+
+// Error implements the error interface
+func (w *Warning) Error() string {
+	return w.Message
+}
+
+// IsWarning reports whether err only warns, either as a rule's *Warning or
+// as a validation error built from one
+func IsWarning(err error) bool {
+	if verr, ok := err.(*ValidationError); ok {
+		return verr.Warning
+	}
+	_, ok := err.(*Warning)
+	return ok
+}
+
+// Forbidden is returned by rules that reject a value the caller may not
+// access, such as a path outside the allowed roots
+type Forbidden struct {
+	Message string
+}
+
+// Error implements the error interface
+func (f *Forbidden) Error() string {
+	return f.Message
+}
+
 // NewValidationError creates a new ValidationError
 func NewValidationError(field, rule, value, message string) *ValidationError {
 	// Truncate value if too long for logging
@@ -41,7 +74,8 @@ func NewValidationError(field, rule, value, message string) *ValidationError {
 	}
 }
 
-// ToMCPError converts a ValidationError to an MCPError
+// ToMCPError converts a ValidationError to an MCPError; forbidden values
+// become FORBIDDEN errors
 func (e *ValidationError) ToMCPError() types.MCPError {
 	details := make(map[string]interface{})
 	if e.Field != "" {
@@ -54,6 +88,9 @@ func (e *ValidationError) ToMCPError() types.MCPError {
 		details["value"] = e.Value
 	}
 
+	if e.Forbidden {
+		return types.NewForbiddenError(e.Message, "validation_error", details)
+	}
 	return types.NewValidationError(e.Message, "validation_error", details)
 }
 
@@ -1,14 +1,40 @@
 package validations
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+
+	"mcp-go-assistant/internal/workspace"
 )
 
 const (
-	// goIdentifierPattern is the regex pattern for valid Go identifiers
-	goIdentifierPattern = `^[a-zA-Z_][a-zA-Z0-9_]*$`
+	// goIdentifierPattern is the regex pattern for valid Go identifiers,
+	// which may contain any Unicode letter or decimal digit
+	goIdentifierPattern = `^[\p{L}_][\p{L}\p{Nd}_]*$`
+)
+
+// Patterns are compiled once and shared; a compiled regexp is safe for
+// concurrent use
+var (
+	goIdentifierRegex = regexp.MustCompile(goIdentifierPattern)
+
+	// Must start with a letter or underscore, contain only valid characters
+	packagePathRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*/[a-zA-Z_][a-zA-Z0-9_./-]*$|^std$|^builtin$|^fmt$`)
+
+	// Intentionally permissive, actual file access checks should happen at OS
+	// level. Windows paths may start with a drive letter, use backslashes and
+	// contain spaces or 8.3 short names such as RUNNER~1.
+	filePathRegex = regexp.MustCompile(`^(?:[a-zA-Z]:)?[a-zA-Z0-9_./\\~\-][a-zA-Z0-9_./\\~ \-]*$`)
+
+	// Slash-separated import path, e.g. "github.com/org/repo/pkg"
+	importPathRegex = regexp.MustCompile(`^[a-zA-Z0-9_./\-][a-zA-Z0-9_./\-]*$`)
+
+	// Matches the receiver of a method expression, e.g. "(*Server)."
+	methodReceiverRegex = regexp.MustCompile(`\(\*?([\p{L}_][\p{L}\p{Nd}_]*)\)\.`)
 )
 
 // ValidationRule defines the interface for validation rules
@@ -68,17 +94,30 @@ type AllowedCharsRule struct {
 	re      *regexp.Regexp
 }
 
+// NewAllowedCharsRule creates an AllowedCharsRule with its pattern compiled
+// up front, so the rule can be shared by concurrent validations
+func NewAllowedCharsRule(pattern string) *AllowedCharsRule {
+	return &AllowedCharsRule{Pattern: pattern, re: allowedCharsRegex(pattern)}
+}
+
+// allowedCharsRegex compiles pattern to match whole strings
+func allowedCharsRegex(pattern string) *regexp.Regexp {
+	return regexp.MustCompile("^" + pattern + "$")
+}
+
 // Validate checks if the value contains only allowed characters
 func (r *AllowedCharsRule) Validate(value interface{}) error {
-	if r.re == nil {
-		r.re = regexp.MustCompile("^" + r.Pattern + "$")
+	// Rules built without NewAllowedCharsRule compile the pattern per call
+	re := r.re
+	if re == nil {
+		re = allowedCharsRegex(r.Pattern)
 	}
 
 	str, ok := value.(string)
 	if !ok {
 		return fmt.Errorf("value must be a string")
 	}
-	if !r.re.MatchString(str) {
+	if !re.MatchString(str) {
 		return fmt.Errorf("value contains invalid characters (pattern: %s)", r.Pattern)
 	}
 	return nil
@@ -108,9 +147,7 @@ func (r *PackagePathRule) Validate(value interface{}) error {
 	}
 
 	// Basic Go package path validation
-	// Must start with a letter or underscore, contain only valid characters
-	pattern := `^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*/[a-zA-Z_][a-zA-Z0-9_./-]*$|^std$|^builtin$|^fmt$`
-	if !regexp.MustCompile(pattern).MatchString(str) {
+	if !packagePathRegex.MatchString(str) {
 		return fmt.Errorf("invalid Go package path format: %s", str)
 	}
 
@@ -148,9 +185,7 @@ func (r *FilePathRule) Validate(value interface{}) error {
 	}
 
 	// Basic path validation - allow common path characters
-	// This is intentionally permissive, actual file access checks should happen at OS level
-	validChars := `^[a-zA-Z0-9_./\-][a-zA-Z0-9_./\-]*$`
-	if !regexp.MustCompile(validChars).MatchString(str) {
+	if !filePathRegex.MatchString(str) {
 		return fmt.Errorf("file path contains invalid characters")
 	}
 
@@ -162,10 +197,76 @@ func (r *FilePathRule) Name() string {
 	return "file_path"
 }
 
+// AllowedRootsRule rejects paths outside the configured roots. Symlinks are
+// resolved before the check, so a link inside a root cannot lead outside
+// it. Without roots every path is allowed.
+type AllowedRootsRule struct {
+	roots []string // Absolute roots with symlinks resolved
+}
+
+// NewAllowedRootsRule creates an AllowedRootsRule for the roots
+func NewAllowedRootsRule(roots []string) *AllowedRootsRule {
+	rule := &AllowedRootsRule{}
+	for _, root := range roots {
+		rule.roots = append(rule.roots, workspace.Resolve(root))
+	}
+	return rule
+}
+
+// Validate checks if the path is inside one of the roots. A path outside
+// them is returned as a *Forbidden.
+func (r *AllowedRootsRule) Validate(value interface{}) error {
+	str, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("value must be a string")
+	}
+
+	if str == "" || len(r.roots) == 0 {
+		return nil
+	}
+
+	path := workspace.Resolve(str)
+	for _, root := range r.roots {
+		rel, err := filepath.Rel(root, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return &Forbidden{Message: fmt.Sprintf("path %s is outside the allowed roots", str)}
+}
+
+// Name returns the rule name
+func (r *AllowedRootsRule) Name() string {
+	return "allowed_root"
+}
+
+// Code safety modes
+const (
+	CodeSafetyBlock = "block" // Reject code containing a dangerous pattern
+	CodeSafetyWarn  = "warn"  // Accept the code and report the pattern
+)
+
+// DefaultDangerousPatterns are the patterns CodeSafetyRule looks for when
+// no list is configured
+var DefaultDangerousPatterns = []string{
+	"`rm -rf`",
+	"exec.Command(",
+	"os.system(",
+	"subprocess.call(",
+	"eval(",
+	"`__import__`",
+}
+
 // CodeSafetyRule validates code for basic safety patterns
-type CodeSafetyRule struct{}
+type CodeSafetyRule struct {
+	Patterns  []string // Dangerous patterns; nil uses DefaultDangerousPatterns
+	Allowlist []string // Patterns accepted even when listed in Patterns
+	Mode      string   // CodeSafetyBlock (the default) or CodeSafetyWarn
+}
 
-// Validate checks if the code is safe for analysis
+// Validate checks if the code is safe for analysis. Call patterns such as
+// "exec.Command(" only match calls, not mentions in comments or strings. In
+// warn mode a match is returned as a *Warning.
 func (r *CodeSafetyRule) Validate(value interface{}) error {
 	code, ok := value.(string)
 	if !ok {
@@ -173,19 +274,20 @@ func (r *CodeSafetyRule) Validate(value interface{}) error {
 	}
 
 	// Check for suspicious patterns that might indicate injection attempts
-	dangerousPatterns := []string{
-		"`rm -rf`",
-		"exec.Command(",
-		"os.system(",
-		"subprocess.call(",
-		"eval(",
-		"`__import__`",
-	}
-
-	for _, pattern := range dangerousPatterns {
-		if strings.Contains(code, pattern) {
-			return fmt.Errorf("code contains potentially dangerous pattern: %s", pattern)
+	patterns := r.Patterns
+	if patterns == nil {
+		patterns = DefaultDangerousPatterns
+	}
+	m := &codeMatcher{code: code}
+	for _, pattern := range patterns {
+		if pattern == "" || slices.Contains(r.Allowlist, pattern) || !m.matches(pattern) {
+			continue
+		}
+		message := fmt.Sprintf("code contains potentially dangerous pattern: %s", pattern)
+		if r.Mode == CodeSafetyWarn {
+			return &Warning{Message: message}
 		}
+		return errors.New(message)
 	}
 
 	return nil
@@ -212,8 +314,9 @@ func (r *SymbolNameRule) Validate(value interface{}) error {
 	}
 
 	// Validate symbol name format
-	// Go identifiers: start with letter or underscore, followed by letters, digits, or underscores
-	if !regexp.MustCompile(goIdentifierPattern).MatchString(str) {
+	// Go identifiers: start with a Unicode letter or underscore, followed by
+	// letters, decimal digits, or underscores
+	if !goIdentifierRegex.MatchString(str) {
 		return fmt.Errorf("invalid Go symbol name: %s", str)
 	}
 
@@ -1,6 +1,9 @@
 package validations
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -243,6 +246,28 @@ func TestFilePathRule(t *testing.T) {
 			value:   "path/to/file.go",
 			wantErr: false,
 		},
+		{
+			name:    "windows absolute path",
+			value:   "C:\\Users\\RUNNER~1\\My Project\\main.go",
+			wantErr: false,
+		},
+		{
+			name:    "windows drive with forward slashes",
+			value:   "d:/src/app/main.go",
+			wantErr: false,
+		},
+		{
+			name:      "colon outside drive letter",
+			value:     "path/C:file.go",
+			wantErr:   true,
+			errString: "invalid characters",
+		},
+		{
+			name:      "windows path traversal",
+			value:     "C:\\project\\..\\secrets",
+			wantErr:   true,
+			errString: "cannot contain '..'",
+		},
 		{
 			name:      "path traversal",
 			value:     "../etc/passwd",
@@ -283,6 +308,48 @@ func TestFilePathRule(t *testing.T) {
 	}
 }
 
+// TestAllowedRootsRule tests root confinement and symlink resolution
+func TestAllowedRootsRule(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "app"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	rule := NewAllowedRootsRule([]string{root})
+
+	tests := []struct {
+		name      string
+		path      string
+		forbidden bool
+	}{
+		{"root itself", root, false},
+		{"directory in root", filepath.Join(root, "app"), false},
+		{"missing file in root", filepath.Join(root, "app", "guidelines.md"), false},
+		{"outside root", outside, true},
+		{"sibling with root prefix", root + "-other", true},
+		{"symlink leading outside", filepath.Join(root, "escape"), true},
+		{"missing file behind symlink", filepath.Join(root, "escape", "guidelines.md"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rule.Validate(tt.path)
+			var forbidden *Forbidden
+			if got := errors.As(err, &forbidden); got != tt.forbidden {
+				t.Errorf("Validate(%s) = %v, want forbidden %v", tt.path, err, tt.forbidden)
+			}
+		})
+	}
+
+	if err := (&AllowedRootsRule{}).Validate(outside); err != nil {
+		t.Errorf("expected rule without roots to allow any path, got %v", err)
+	}
+}
+
 // TestCodeSafetyRule tests the CodeSafetyRule
 func TestCodeSafetyRule(t *testing.T) {
 	rule := &CodeSafetyRule{}
@@ -338,6 +405,96 @@ func TestCodeSafetyRule(t *testing.T) {
 	}
 }
 
+// TestCodeSafetyRule_Configured tests configured patterns, the allowlist
+// and warn mode
+func TestCodeSafetyRule_Configured(t *testing.T) {
+	code := "cmd := exec.Command(\"go\", \"version\")\nunsafe.Pointer(p)"
+
+	if err := (&CodeSafetyRule{Allowlist: []string{"exec.Command("}}).Validate(code); err != nil {
+		t.Errorf("expected allowlisted pattern to pass, got %v", err)
+	}
+
+	err := (&CodeSafetyRule{Patterns: []string{"unsafe.Pointer("}}).Validate(code)
+	if err == nil || !strings.Contains(err.Error(), "unsafe.Pointer(") {
+		t.Errorf("expected configured pattern to be reported, got %v", err)
+	}
+	if IsWarning(err) {
+		t.Error("expected block mode to return an error, not a warning")
+	}
+
+	err = (&CodeSafetyRule{Mode: CodeSafetyWarn}).Validate(code)
+	if !IsWarning(err) || !strings.Contains(err.Error(), "exec.Command(") {
+		t.Errorf("expected warning for exec.Command(, got %v", err)
+	}
+
+	if err := (&CodeSafetyRule{Patterns: []string{}}).Validate(code); err != nil {
+		t.Errorf("expected empty pattern list to pass, got %v", err)
+	}
+}
+
+// TestCodeSafetyRule_Parsed tests that only calls match call patterns
+func TestCodeSafetyRule_Parsed(t *testing.T) {
+	rule := &CodeSafetyRule{}
+
+	tests := []struct {
+		name    string
+		code    string
+		pattern string
+	}{
+		{
+			name: "eval in comment",
+			code: "package main\n\n// eval( is never called here\nfunc main() {}\n",
+		},
+		{
+			name: "pattern in string literal",
+			code: "package main\n\nconst usage = \"avoid exec.Command( with user input\"\n",
+		},
+		{
+			name: "pattern in raw string",
+			code: "msg := `os.system(\"ls\")`\n_ = msg",
+		},
+		{
+			name: "other function named like pattern",
+			code: "x := evaluate(1)\n_ = x",
+		},
+		{
+			name:    "call in file",
+			code:    "package main\n\nimport \"os/exec\"\n\nfunc run() { _ = exec.Command(\"ls\").Run() }\n",
+			pattern: "exec.Command(",
+		},
+		{
+			name:    "call in declarations",
+			code:    "func run() { eval(input) }",
+			pattern: "eval(",
+		},
+		{
+			name:    "parenthesized call",
+			code:    "(exec.Command)(\"ls\")",
+			pattern: "exec.Command(",
+		},
+		{
+			name:    "non-Go code",
+			code:    "import os\nos.system('rm -rf /')",
+			pattern: "os.system(",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rule.Validate(tt.code)
+			if tt.pattern == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasSuffix(err.Error(), tt.pattern) {
+				t.Errorf("expected pattern %s to be reported, got %v", tt.pattern, err)
+			}
+		})
+	}
+}
+
 // TestSymbolNameRule tests the SymbolNameRule
 func TestSymbolNameRule(t *testing.T) {
 	rule := &SymbolNameRule{}
@@ -363,6 +520,22 @@ func TestSymbolNameRule(t *testing.T) {
 			value:   "_private",
 			wantErr: false,
 		},
+		{
+			name:    "unicode symbol",
+			value:   "Größe",
+			wantErr: false,
+		},
+		{
+			name:    "unicode symbol with non-ASCII digit",
+			value:   "café٣",
+			wantErr: false,
+		},
+		{
+			name:      "symbol starting with non-ASCII digit",
+			value:     "٣café",
+			wantErr:   true,
+			errString: "invalid Go symbol name",
+		},
 		{
 			name:      "invalid symbol with dash",
 			value:     "my-symbol",
@@ -5,6 +5,11 @@ import (
 	"strings"
 )
 
+var (
+	multiLineCommentRegex  = regexp.MustCompile(`/\*[\s\S]*?\*/`)
+	singleLineCommentRegex = regexp.MustCompile(`//.*`)
+)
+
 // SanitizeCode removes dangerous patterns from code
 func SanitizeCode(code string) (string, error) {
 	if code == "" {
@@ -42,8 +47,11 @@ func SanitizePath(path string) (string, error) {
 		return "", NewValidationError("path", "null_byte", path, "path cannot contain null bytes")
 	}
 
+	// Keep a Windows drive letter; the rest is normalized like any path
+	drive, rest := splitDrive(path)
+
 	// Normalize path separators to forward slashes
-	sanitized := strings.ReplaceAll(path, "\\", "/")
+	sanitized := strings.ReplaceAll(rest, "\\", "/")
 
 	// Remove leading/trailing slashes and dots
 	sanitized = strings.Trim(sanitized, "./")
@@ -53,9 +61,27 @@ func SanitizePath(path string) (string, error) {
 		sanitized = strings.ReplaceAll(sanitized, "//", "/")
 	}
 
+	if drive != "" {
+		return drive + "/" + sanitized, nil
+	}
 	return sanitized, nil
 }
 
+// splitDrive splits a leading Windows drive letter such as "C:" from path.
+// It does not depend on the host OS, so Windows paths sent by a client are
+// handled the same wherever the server runs.
+func splitDrive(path string) (drive, rest string) {
+	if len(path) >= 2 && path[1] == ':' && isASCIILetter(path[0]) {
+		return strings.ToUpper(path[:2]), path[2:]
+	}
+	return "", path
+}
+
+// isASCIILetter reports whether c is an ASCII letter
+func isASCIILetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
 // SanitizeSymbol validates and sanitizes symbol names
 func SanitizeSymbol(symbol string) (string, error) {
 	if symbol == "" {
@@ -66,8 +92,7 @@ func SanitizeSymbol(symbol string) (string, error) {
 	sanitized := strings.TrimSpace(symbol)
 
 	// Validate symbol name format
-	pattern := `^[a-zA-Z_][a-zA-Z0-9_]*$`
-	if !regexp.MustCompile(pattern).MatchString(sanitized) {
+	if !goIdentifierRegex.MatchString(sanitized) {
 		return "", NewValidationError("symbol", "format", sanitized, "invalid Go symbol name format")
 	}
 
@@ -81,12 +106,10 @@ func StripComments(code string) string {
 	}
 
 	// Remove multi-line comments /* ... */
-	multiLineCommentRE := regexp.MustCompile(`/\*[\s\S]*?\*/`)
-	code = multiLineCommentRE.ReplaceAllString(code, "")
+	code = multiLineCommentRegex.ReplaceAllString(code, "")
 
 	// Remove single-line comments // ...
-	singleLineCommentRE := regexp.MustCompile(`//.*`)
-	code = singleLineCommentRE.ReplaceAllString(code, "")
+	code = singleLineCommentRegex.ReplaceAllString(code, "")
 
 	return code
 }
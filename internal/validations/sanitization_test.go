@@ -116,6 +116,25 @@ func TestSanitizePath(t *testing.T) {
 			want:    "path/to/file.go",
 			wantErr: false,
 		},
+		{
+			name:    "windows drive letter",
+			path:    "c:\\Users\\dev\\project\\main.go",
+			want:    "C:/Users/dev/project/main.go",
+			wantErr: false,
+		},
+		{
+			name:    "windows drive root",
+			path:    "D:\\",
+			want:    "D:/",
+			wantErr: false,
+		},
+		{
+			name:      "windows path traversal",
+			path:      "C:\\project\\..\\secrets",
+			want:      "",
+			wantErr:   true,
+			errString: "cannot contain '..'",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1,8 +1,8 @@
 package validations
 
 import (
+	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 	"sync"
 )
@@ -10,6 +10,7 @@ import (
 // Validator is the main validation struct that holds rules and configuration
 type Validator struct {
 	rules        map[string]ValidationRule
+	toolRules    map[string]map[string]ValidationRule // Rules replacing the shared ones for one tool
 	validators   map[string]ValidatorFunc
 	maxSize      int
 	allowedChars string
@@ -20,6 +21,7 @@ type Validator struct {
 func NewValidator() *Validator {
 	v := &Validator{
 		rules:        make(map[string]ValidationRule),
+		toolRules:    make(map[string]map[string]ValidationRule),
 		validators:   make(map[string]ValidatorFunc),
 		maxSize:      1024 * 1024,         // 1MB default max size
 		allowedChars: `[a-zA-Z0-9_ ./\-]`, // Default allowed characters
@@ -28,12 +30,21 @@ func NewValidator() *Validator {
 	// Register default rules
 	v.AddRule("not_empty", &NotEmptyRule{})
 	v.AddRule("max_length", &MaxLengthRule{MaxLength: v.maxSize})
-	v.AddRule("allowed_chars", &AllowedCharsRule{Pattern: v.allowedChars})
+	v.AddRule("allowed_chars", NewAllowedCharsRule(v.allowedChars))
 	v.AddRule("package_path", &PackagePathRule{})
 	v.AddRule("file_path", &FilePathRule{})
+	v.AddRule("allowed_root", &AllowedRootsRule{})
 	v.AddRule("code_safety", &CodeSafetyRule{})
 	v.AddRule("symbol_name", &SymbolNameRule{})
 
+	// Register parameter validators so they can be referenced by name
+	v.AddValidator("hint", stringValidator(v.ValidateHint))
+	v.AddValidator("focus", stringValidator(v.ValidateFocus))
+	v.AddValidator("package_name", stringValidator(v.ValidatePackageName))
+	v.AddValidator("doc_mode", stringValidator(v.ValidateDocMode))
+	v.AddValidator("type_name", stringValidator(v.ValidateTypeName))
+	v.AddValidator("function_name", stringValidator(v.ValidateFunctionName))
+
 	return v
 }
 
@@ -45,6 +56,18 @@ func (v *Validator) AddRule(name string, rule ValidationRule) {
 	v.rules[name] = rule
 }
 
+// AddToolRule registers a rule that replaces the rule of the same name in
+// validations run for tool
+func (v *Validator) AddToolRule(tool, name string, rule ValidationRule) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.toolRules[tool] == nil {
+		v.toolRules[tool] = make(map[string]ValidationRule)
+	}
+	v.toolRules[tool][name] = rule
+}
+
 // AddValidator registers a custom validator function
 func (v *Validator) AddValidator(name string, fn ValidatorFunc) {
 	v.mu.Lock()
@@ -55,6 +78,12 @@ func (v *Validator) AddValidator(name string, fn ValidatorFunc) {
 
 // ValidateInput validates input string against specified rules
 func (v *Validator) ValidateInput(input string, rules ...string) error {
+	return v.validate("", input, rules)
+}
+
+// validate applies the rules to input, preferring the rules registered for
+// tool
+func (v *Validator) validate(tool, input string, rules []string) error {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
 
@@ -69,15 +98,27 @@ func (v *Validator) ValidateInput(input string, rules ...string) error {
 		// Check custom validators first
 		if validator, ok := v.validators[ruleName]; ok {
 			if err := validator(input); err != nil {
+				if verr, ok := err.(*ValidationError); ok {
+					return verr
+				}
 				return NewValidationError("input", ruleName, input, err.Error())
 			}
 			continue
 		}
 
-		// Check predefined rules
-		if rule, ok := v.rules[ruleName]; ok {
+		// Check predefined rules, replaced by the tool's own if it has one
+		rule, ok := v.toolRules[tool][ruleName]
+		if !ok {
+			rule, ok = v.rules[ruleName]
+		}
+		if ok {
 			if err := rule.Validate(input); err != nil {
-				return NewValidationError("input", ruleName, input, err.Error())
+				verr := NewValidationError("input", ruleName, input, err.Error())
+				var warning *Warning
+				verr.Warning = errors.As(err, &warning)
+				var forbidden *Forbidden
+				verr.Forbidden = errors.As(err, &forbidden)
+				return verr
 			}
 			continue
 		}
@@ -89,6 +130,45 @@ func (v *Validator) ValidateInput(input string, rules ...string) error {
 	return nil
 }
 
+// stringValidator adapts a string validation method to a ValidatorFunc
+func stringValidator(fn func(string) error) ValidatorFunc {
+	return func(value interface{}) error {
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("value must be a string")
+		}
+		return fn(str)
+	}
+}
+
+// ValidateField validates input like ValidateInput and reports failures
+// against the named field
+func (v *Validator) ValidateField(field, input string, rules ...string) error {
+	return v.ValidateToolField("", field, input, rules...)
+}
+
+// ValidateToolField validates a field like ValidateField, using the rules
+// registered for tool in place of the shared ones
+func (v *Validator) ValidateToolField(tool, field, input string, rules ...string) error {
+	err := v.validate(tool, input, rules)
+	if verr, ok := err.(*ValidationError); ok && verr.Field == "input" {
+		named := *verr
+		named.Field = field
+		return &named
+	}
+	return err
+}
+
+// HasRule reports whether a rule or custom validator is registered under name
+func (v *Validator) HasRule(name string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	_, isRule := v.rules[name]
+	_, isValidator := v.validators[name]
+	return isRule || isValidator
+}
+
 // ValidatePackagePath validates a Go package path
 func (v *Validator) ValidatePackagePath(path string) error {
 	return v.ValidateInput(path, "not_empty", "package_path")
@@ -129,11 +209,11 @@ func (v *Validator) SetAllowedChars(pattern string) {
 
 	v.allowedChars = pattern
 
-	// Update allowed_chars rule
+	// Replace the allowed_chars rule rather than modifying it, since
+	// validations running concurrently may hold the old one
 	if rule, ok := v.rules["allowed_chars"]; ok {
-		if acRule, ok := rule.(*AllowedCharsRule); ok {
-			acRule.Pattern = pattern
-			acRule.re = nil // Reset compiled regex
+		if _, ok := rule.(*AllowedCharsRule); ok {
+			v.rules["allowed_chars"] = NewAllowedCharsRule(pattern)
 		}
 	}
 }
@@ -188,6 +268,25 @@ func (v *Validator) ValidateFocus(focus string) error {
 	return nil
 }
 
+// ValidateDocMode validates the output mode of the go-doc tool
+func (v *Validator) ValidateDocMode(mode string) error {
+	if mode == "" {
+		return nil // Empty mode selects the full documentation
+	}
+
+	validModes := map[string]bool{
+		"doc": true,
+		"api": true,
+	}
+
+	if !validModes[strings.ToLower(mode)] {
+		return NewValidationError("mode", "invalid_value", mode,
+			fmt.Sprintf("mode must be one of: doc, api (got: %s)", mode))
+	}
+
+	return nil
+}
+
 // ValidatePackageName validates a Go package name
 func (v *Validator) ValidatePackageName(name string) error {
 	if name == "" {
@@ -196,8 +295,7 @@ func (v *Validator) ValidatePackageName(name string) error {
 
 	// Validate package name format
 	// Go package names must be valid identifiers
-	pattern := `^[a-zA-Z_][a-zA-Z0-9_]*$`
-	if !regexp.MustCompile(pattern).MatchString(name) {
+	if !goIdentifierRegex.MatchString(name) {
 		return NewValidationError("package_name", "invalid_format", name,
 			"invalid Go package name format")
 	}
@@ -210,3 +308,64 @@ func (v *Validator) ValidatePackageName(name string) error {
 
 	return nil
 }
+
+// ValidateTypeName validates an optionally package-qualified type name such
+// as Store, io.Reader or example.com/app/store.Store
+func (v *Validator) ValidateTypeName(name string) error {
+	if name == "" {
+		return nil // Empty type name is allowed
+	}
+
+	if !isQualifiedName(name) {
+		return NewValidationError("type_name", "invalid_format", name,
+			"invalid Go type name format; use Name, pkg.Name or import/path.Name")
+	}
+
+	return nil
+}
+
+// ValidateFunctionName validates an optionally package-qualified function
+// or method name such as Serve, server.Serve or (*Server).Handle
+func (v *Validator) ValidateFunctionName(name string) error {
+	if name == "" {
+		return nil // Empty function name is allowed
+	}
+
+	// Method expressions name the receiver as (*T) or (T)
+	if !isQualifiedName(methodReceiverRegex.ReplaceAllString(name, "$1.")) {
+		return NewValidationError("function_name", "invalid_format", name,
+			"invalid Go function name format; use Func, Type.Method, (*Type).Method or pkg.Func, optionally qualified with the import path")
+	}
+
+	return nil
+}
+
+// isQualifiedName reports whether name is an identifier optionally
+// qualified with dotted identifiers or an import path
+func isQualifiedName(name string) bool {
+	qualifier, ident := "", name
+	if i := strings.LastIndex(name, "."); i > strings.LastIndex(name, "/") {
+		qualifier, ident = name[:i], name[i+1:]
+	}
+	if !goIdentifierRegex.MatchString(ident) {
+		return false
+	}
+	if qualifier == "" {
+		return true
+	}
+	if strings.Contains(qualifier, "..") {
+		return false
+	}
+	return importPathRegex.MatchString(qualifier) || isDottedName(qualifier)
+}
+
+// isDottedName reports whether name is one or more identifiers joined by
+// dots, such as a non-ASCII Type or pkg.Type qualifier
+func isDottedName(name string) bool {
+	for _, part := range strings.Split(name, ".") {
+		if !goIdentifierRegex.MatchString(part) {
+			return false
+		}
+	}
+	return true
+}
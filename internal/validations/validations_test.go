@@ -2,6 +2,7 @@ package validations
 
 import (
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -326,6 +327,31 @@ func TestSetAllowedChars(t *testing.T) {
 	}
 }
 
+// TestSetAllowedChars_Concurrent tests that changing the pattern does not
+// race with validations using the previous rule
+func TestSetAllowedChars_Concurrent(t *testing.T) {
+	v := NewValidator()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = v.ValidateInput("abc", "allowed_chars")
+			}
+		}()
+	}
+	for j := 0; j < 10; j++ {
+		v.SetAllowedChars("[a-z]*")
+	}
+	wg.Wait()
+
+	if err := v.ValidateInput("abc", "allowed_chars"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 // TestValidateHint tests hint validation
 func TestValidateHint(t *testing.T) {
 	v := NewValidator()
@@ -437,6 +463,35 @@ func TestValidateFocus(t *testing.T) {
 	}
 }
 
+// TestValidateDocMode tests go-doc mode validation
+func TestValidateDocMode(t *testing.T) {
+	v := NewValidator()
+
+	tests := []struct {
+		name    string
+		mode    string
+		wantErr bool
+	}{
+		{name: "empty mode", mode: "", wantErr: false},
+		{name: "doc mode", mode: "doc", wantErr: false},
+		{name: "api mode", mode: "API", wantErr: false},
+		{name: "invalid mode", mode: "summary", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateDocMode(tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDocMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if !v.HasRule("doc_mode") {
+		t.Error("expected doc_mode to be registered")
+	}
+}
+
 // TestValidatePackageName tests package name validation
 func TestValidatePackageName(t *testing.T) {
 	v := NewValidator()
@@ -461,6 +516,11 @@ func TestValidatePackageName(t *testing.T) {
 			pkgName: "_private",
 			wantErr: false,
 		},
+		{
+			name:    "unicode package name",
+			pkgName: "données",
+			wantErr: false,
+		},
 		{
 			name:    "invalid package name with hyphen",
 			pkgName: "my-package",
@@ -495,6 +555,66 @@ func TestValidatePackageName(t *testing.T) {
 	}
 }
 
+func TestValidateTypeName(t *testing.T) {
+	v := NewValidator()
+
+	tests := []struct {
+		name     string
+		typeName string
+		wantErr  bool
+	}{
+		{"empty type name", "", false},
+		{"unqualified", "Store", false},
+		{"package name", "io.Reader", false},
+		{"import path", "example.com/app/store.Store", false},
+		{"versioned import path", "gopkg.in/yaml.v3.Marshaler", false},
+		{"path without type", "example.com/app", true},
+		{"traversal", "../store.Store", true},
+		{"invalid identifier", "store.1Store", true},
+		{"unicode", "модель.Сервер", false},
+		{"quote", "io\".Reader", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateTypeName(tt.typeName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTypeName(%q) error = %v, wantErr %v", tt.typeName, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFunctionName(t *testing.T) {
+	v := NewValidator()
+
+	tests := []struct {
+		name     string
+		funcName string
+		wantErr  bool
+	}{
+		{"empty function name", "", false},
+		{"function", "Serve", false},
+		{"package function", "server.Serve", false},
+		{"method", "Server.Handle", false},
+		{"pointer method", "(*Server).Handle", false},
+		{"qualified method", "example.com/app/server.(*Server).Handle", false},
+		{"unicode method", "Сервер.Обработать", false},
+		{"unicode pointer method", "(*Größe).Berechne", false},
+		{"call", "Serve()", true},
+		{"spaces", "Serve; rm", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateFunctionName(tt.funcName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFunctionName(%q) error = %v, wantErr %v", tt.funcName, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 // TestConcurrency tests concurrent access to validator
 func TestConcurrency(t *testing.T) {
 	v := NewValidator()
@@ -576,3 +696,76 @@ func TestValidationErrorValueTruncation(t *testing.T) {
 		t.Error("expected truncated Value to end with '...'")
 	}
 }
+
+// TestValidateField tests field-named validation through rule names
+func TestValidateField(t *testing.T) {
+	v := NewValidator()
+
+	err := v.ValidateField("working_dir", "../etc", "file_path")
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if verr.Field != "working_dir" || verr.Rule != "file_path" {
+		t.Errorf("unexpected field/rule: %s/%s", verr.Field, verr.Rule)
+	}
+
+	// Named validators keep their own error details
+	err = v.ValidateField("focus", "bogus", "focus")
+	verr, ok = err.(*ValidationError)
+	if !ok || verr.Field != "focus" || verr.Rule != "invalid_value" {
+		t.Errorf("expected focus invalid_value error, got %v", err)
+	}
+
+	if err := v.ValidateField("hint", "performance", "hint"); err != nil {
+		t.Errorf("unexpected error for valid hint: %v", err)
+	}
+
+	if !v.HasRule("package_name") || !v.HasRule("code_safety") || v.HasRule("nonexistent") {
+		t.Error("HasRule returned unexpected results")
+	}
+}
+
+// TestValidateToolField tests that tool rules replace the shared ones
+func TestValidateToolField(t *testing.T) {
+	v := NewValidator()
+	v.AddToolRule("code-review", "code_safety", &CodeSafetyRule{Mode: CodeSafetyWarn})
+
+	code := "exec.Command(\"ls\")"
+
+	err := v.ValidateToolField("test-gen", "go_code", code, "code_safety")
+	verr, ok := err.(*ValidationError)
+	if !ok || verr.Warning || verr.Field != "go_code" {
+		t.Fatalf("expected blocking go_code error for test-gen, got %v", err)
+	}
+
+	err = v.ValidateToolField("code-review", "go_code", code, "code_safety")
+	verr, ok = err.(*ValidationError)
+	if !ok || !verr.Warning || verr.Field != "go_code" {
+		t.Fatalf("expected go_code warning for code-review, got %v", err)
+	}
+	if !IsWarning(err) {
+		t.Error("expected IsWarning to report the warning")
+	}
+}
+
+// TestValidateField_Forbidden tests that paths outside the allowed roots
+// become FORBIDDEN errors
+func TestValidateField_Forbidden(t *testing.T) {
+	v := NewValidator()
+	v.AddRule("allowed_root", NewAllowedRootsRule([]string{t.TempDir()}))
+
+	err := v.ValidateField("working_dir", t.TempDir(), "allowed_root")
+	verr, ok := err.(*ValidationError)
+	if !ok || !verr.Forbidden || verr.Field != "working_dir" {
+		t.Fatalf("expected forbidden working_dir error, got %v", err)
+	}
+	if code := verr.ToMCPError().Code(); code != "FORBIDDEN" {
+		t.Errorf("expected FORBIDDEN, got %s", code)
+	}
+
+	verr = NewValidationError("working_dir", "file_path", "..", "traversal")
+	if code := verr.ToMCPError().Code(); code != "VALIDATION_FAILED" {
+		t.Errorf("expected VALIDATION_FAILED, got %s", code)
+	}
+}
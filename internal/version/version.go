@@ -1,11 +1,17 @@
 package version
 
-import "fmt"
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
 
 var (
 	// Version is the application version.
 	// This is set at build time using ldflags:
-	//   go build -ldflags "-X main.version.Version=v0.1.0"
+	//   go build -ldflags "-X mcp-go-assistant/internal/version.Version=v0.1.0"
+	// Values left unset are filled in from the build info embedded by the
+	// go command, such as the VCS revision and time.
 	Version = "dev"
 
 	// GitCommit is the git commit hash at build time.
@@ -26,14 +32,53 @@ type VersionInfo struct {
 	GoVersion string
 }
 
+// readBuildInfo is replaced in tests
+var readBuildInfo = debug.ReadBuildInfo
+
 // GetVersionInfo returns the complete version information.
 func GetVersionInfo() VersionInfo {
-	return VersionInfo{
+	info := VersionInfo{
 		Version:   Version,
 		GitCommit: GitCommit,
 		BuildTime: BuildTime,
 		GoVersion: GoVersion,
 	}
+
+	bi, ok := readBuildInfo()
+	if !ok {
+		if info.GoVersion == "unknown" {
+			info.GoVersion = runtime.Version()
+		}
+		return info
+	}
+
+	if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	if info.GoVersion == "unknown" {
+		info.GoVersion = bi.GoVersion
+	}
+
+	var revision, modified string
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value
+		case "vcs.time":
+			if info.BuildTime == "unknown" {
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+	if info.GitCommit == "unknown" && revision != "" {
+		info.GitCommit = revision
+		if modified == "true" {
+			info.GitCommit += "-dirty"
+		}
+	}
+	return info
 }
 
 // String returns a formatted version string.
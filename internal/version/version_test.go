@@ -0,0 +1,60 @@
+package version
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestGetVersionInfo_BuildInfo(t *testing.T) {
+	defer func(read func() (*debug.BuildInfo, bool)) { readBuildInfo = read }(readBuildInfo)
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.99.0",
+			Main:      debug.Module{Path: "mcp-go-assistant", Version: "v1.4.0"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "abc123"},
+				{Key: "vcs.time", Value: "2026-01-02T03:04:05Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true
+	}
+
+	info := GetVersionInfo()
+	want := VersionInfo{Version: "v1.4.0", GitCommit: "abc123-dirty", BuildTime: "2026-01-02T03:04:05Z", GoVersion: "go1.99.0"}
+	if info != want {
+		t.Errorf("expected %+v, got %+v", want, info)
+	}
+}
+
+func TestGetVersionInfo_LdflagsTakePrecedence(t *testing.T) {
+	defer func(read func() (*debug.BuildInfo, bool)) { readBuildInfo = read }(readBuildInfo)
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.99.0",
+			Main:      debug.Module{Path: "mcp-go-assistant", Version: "(devel)"},
+			Settings:  []debug.BuildSetting{{Key: "vcs.revision", Value: "abc123"}},
+		}, true
+	}
+	defer func(v, c string) { Version, GitCommit = v, c }(Version, GitCommit)
+	Version, GitCommit = "v2.0.0", "def456"
+
+	info := GetVersionInfo()
+	if info.Version != "v2.0.0" || info.GitCommit != "def456" {
+		t.Errorf("expected the ldflags values, got %+v", info)
+	}
+	if info.BuildTime != "unknown" {
+		t.Errorf("expected an unknown build time, got %q", info.BuildTime)
+	}
+}
+
+func TestGetVersionInfo_DevelBuild(t *testing.T) {
+	defer func(read func() (*debug.BuildInfo, bool)) { readBuildInfo = read }(readBuildInfo)
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{GoVersion: "go1.99.0", Main: debug.Module{Version: "(devel)"}}, true
+	}
+
+	info := GetVersionInfo()
+	if info.Version != "dev" || info.GoVersion != "go1.99.0" {
+		t.Errorf("expected a dev version built with go1.99.0, got %+v", info)
+	}
+}
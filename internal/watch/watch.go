@@ -0,0 +1,172 @@
+// Package watch reports the Go files modified under a directory. Changes
+// are debounced: a burst of saves, such as an editor writing a file and its
+// formatter rewriting it, is reported once as a batch after the directory
+// has been quiet for a delay.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"mcp-go-assistant/internal/logging"
+)
+
+// DefaultDelay is the quiet time after the last change before a batch is
+// reported
+const DefaultDelay = 500 * time.Millisecond
+
+// Options configures a watcher
+type Options struct {
+	Root   string        // Directory watched with its subdirectories
+	Delay  time.Duration // Defaults to DefaultDelay
+	Logger *logging.Logger
+}
+
+// Watcher watches the Go files under a directory tree. Like the go tool, it
+// skips vendor and testdata directories and those starting with . or _.
+type Watcher struct {
+	opts Options
+	fsw  *fsnotify.Watcher
+}
+
+// New starts watching the directory tree at opts.Root
+func New(opts Options) (*Watcher, error) {
+	if opts.Logger == nil {
+		return nil, fmt.Errorf("logger is required")
+	}
+	if opts.Delay <= 0 {
+		opts.Delay = DefaultDelay
+	}
+	info, err := os.Stat(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", opts.Root)
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %v", err)
+	}
+	w := &Watcher{opts: opts, fsw: fsw}
+	if err := w.addTree(opts.Root, nil); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Run calls handle with the cleaned paths of the Go files modified since
+// the previous batch, sorted, until ctx is done. Changes made while handle runs are reported in
+// the next batch. Files removed before their batch is reported are left out.
+func (w *Watcher) Run(ctx context.Context, handle func(ctx context.Context, paths []string)) error {
+	pending := map[string]bool{}
+	var quiet <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return fmt.Errorf("file watcher closed")
+			}
+			if w.track(event, pending) {
+				quiet = time.After(w.opts.Delay)
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return fmt.Errorf("file watcher closed")
+			}
+			w.opts.Logger.WarnEvent().Err(err).Msg("file watcher error")
+		case <-quiet:
+			quiet = nil
+			var paths []string
+			for path := range pending {
+				if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+					paths = append(paths, path)
+				}
+			}
+			pending = map[string]bool{}
+			if len(paths) > 0 {
+				sort.Strings(paths)
+				handle(ctx, paths)
+			}
+		}
+	}
+}
+
+// Close stops watching
+func (w *Watcher) Close() error {
+	return w.fsw.Close()
+}
+
+// track adds the Go files event modified to pending and reports whether
+// there were any. A created directory is watched, and the Go files already
+// written to it are added.
+func (w *Watcher) track(event fsnotify.Event, pending map[string]bool) bool {
+	if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+		return false
+	}
+	path := filepath.Clean(event.Name)
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			if skipDir(path) {
+				return false
+			}
+			if err := w.addTree(path, pending); err != nil {
+				w.opts.Logger.WarnEvent().Err(err).Str("path", path).Msg("failed to watch directory")
+			}
+			return len(pending) > 0
+		}
+	}
+	if !goFile(path) {
+		return false
+	}
+	pending[path] = true
+	return true
+}
+
+// addTree watches dir and its subdirectories, adding the Go files in them
+// to found unless it is nil
+func (w *Watcher) addTree(dir string, found map[string]bool) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			if found != nil && goFile(path) {
+				found[path] = true
+			}
+			return nil
+		}
+		if path != dir && skipDir(path) {
+			return filepath.SkipDir
+		}
+		if err := w.fsw.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %v", path, err)
+		}
+		return nil
+	})
+}
+
+// goFile reports whether path names a Go source file. Editors save through
+// hidden temporary and lock files such as .#main.go, which are left out.
+func goFile(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasSuffix(name, ".go") && !strings.HasPrefix(name, ".") && !strings.HasPrefix(name, "_")
+}
+
+// skipDir reports whether the go tool ignores the directory at path
+func skipDir(path string) bool {
+	name := filepath.Base(path)
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
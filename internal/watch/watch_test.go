@@ -0,0 +1,129 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"mcp-go-assistant/internal/logging"
+)
+
+// startWatcher runs a watcher on root and returns the channel receiving its
+// batches
+func startWatcher(t *testing.T, root string) <-chan []string {
+	t.Helper()
+	log, err := logging.New("fatal", "json", "stderr", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := New(Options{Root: root, Delay: 100 * time.Millisecond, Logger: log})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	batches := make(chan []string, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx, func(_ context.Context, paths []string) {
+			batches <- paths
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+		w.Close()
+	})
+	return batches
+}
+
+// nextBatch waits for the next batch of modified files
+func nextBatch(t *testing.T, batches <-chan []string) []string {
+	t.Helper()
+	select {
+	case paths := <-batches:
+		return paths
+	case <-time.After(5 * time.Second):
+		t.Fatal("no batch of modified files")
+		return nil
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"pkg", ".git", "testdata"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	batches := startWatcher(t, root)
+
+	// A burst of writes is one batch, without other files and ignored
+	// directories
+	writeFile(t, filepath.Join(root, "a.go"), "package a\n")
+	writeFile(t, filepath.Join(root, "a.go"), "package a\n\nfunc A() {}\n")
+	writeFile(t, filepath.Join(root, "pkg", "b.go"), "package pkg\n")
+	writeFile(t, filepath.Join(root, "README.md"), "# a\n")
+	writeFile(t, filepath.Join(root, ".#a.go"), "lock")
+	writeFile(t, filepath.Join(root, ".git", "c.go"), "package git\n")
+	writeFile(t, filepath.Join(root, "testdata", "d.go"), "package testdata\n")
+	want := []string{filepath.Join(root, "a.go"), filepath.Join(root, "pkg", "b.go")}
+	if got := nextBatch(t, batches); !reflect.DeepEqual(got, want) {
+		t.Errorf("batch = %v, want %v", got, want)
+	}
+
+	// Directories created later are watched with the files already in them
+	sub := filepath.Join(root, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(sub, "e.go"), "package sub\n")
+	if got := nextBatch(t, batches); !reflect.DeepEqual(got, []string{filepath.Join(sub, "e.go")}) {
+		t.Errorf("batch = %v, want the file of the new directory", got)
+	}
+	writeFile(t, filepath.Join(sub, "e.go"), "package sub\n\nvar E int\n")
+	if got := nextBatch(t, batches); !reflect.DeepEqual(got, []string{filepath.Join(sub, "e.go")}) {
+		t.Errorf("batch = %v, want the file modified in the new directory", got)
+	}
+
+	// Files removed before the batch are left out
+	writeFile(t, filepath.Join(root, "gone.go"), "package a\n")
+	if err := os.Remove(filepath.Join(root, "gone.go")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, "a.go"), "package a\n")
+	if got := nextBatch(t, batches); !reflect.DeepEqual(got, []string{filepath.Join(root, "a.go")}) {
+		t.Errorf("batch = %v, want only the remaining file", got)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	log, err := logging.New("fatal", "json", "stderr", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "a.go")
+	writeFile(t, file, "package a\n")
+
+	for name, opts := range map[string]Options{
+		"missing root": {Root: filepath.Join(t.TempDir(), "missing"), Logger: log},
+		"file root":    {Root: file, Logger: log},
+		"no logger":    {Root: t.TempDir()},
+	} {
+		if _, err := New(opts); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Package workspace confines the files the tools read and write to the
+// registered workspace roots
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Resolve returns the absolute form of path with the symlinks of its
+// longest existing prefix resolved, so paths that do not exist yet are
+// checked where they would be created. Dangling symlinks are followed to
+// where their target would be created.
+func Resolve(path string) string {
+	return resolve(path, 0)
+}
+
+// maxLinks bounds the dangling symlinks followed by resolve, which would
+// otherwise loop on a link to itself
+const maxLinks = 255
+
+// resolve is Resolve, counting in links the dangling symlinks followed
+func resolve(path string, links int) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+
+	dir, rest := abs, ""
+	for {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		if target, err := os.Readlink(dir); err == nil && links < maxLinks {
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(dir), target)
+			}
+			return resolve(filepath.Join(target, rest), links+1)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return abs
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+}
+
+// Contains reports whether path lies inside one of roots. Symlinks are
+// resolved on both sides, so a link inside a root cannot lead outside it.
+func Contains(roots []string, path string) bool {
+	resolved := Resolve(path)
+	for _, root := range roots {
+		rel, err := filepath.Rel(Resolve(root), resolved)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dir returns the absolute form of dir after checking that it is an
+// existing directory inside one of roots. purpose names the operation in
+// the error returned when no roots are registered.
+func Dir(purpose, dir string, roots []string) (string, error) {
+	if len(roots) == 0 {
+		return "", fmt.Errorf("%s requires at least one registered workspace root", purpose)
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("invalid working directory: %v", err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("working directory not found: %s", dir)
+	}
+	if !Contains(roots, abs) {
+		return "", fmt.Errorf("working directory %s is not inside a registered workspace", dir)
+	}
+	return abs, nil
+}
+
+// File returns the absolute form of file after checking that it lies
+// inside one of roots; purpose is used as by Dir
+func File(purpose, file string, roots []string) (string, error) {
+	if len(roots) == 0 {
+		return "", fmt.Errorf("%s requires at least one registered workspace root", purpose)
+	}
+
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", fmt.Errorf("invalid file path: %v", err)
+	}
+	if !Contains(roots, abs) {
+		return "", fmt.Errorf("file %s is not inside a registered workspace", file)
+	}
+	return abs, nil
+}
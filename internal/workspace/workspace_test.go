@@ -0,0 +1,93 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestContains(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	linkedRoot := filepath.Join(t.TempDir(), "root")
+	if err := os.Symlink(root, linkedRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"root", root, true},
+		{"directory", filepath.Join(root, "pkg"), true},
+		{"missing file", filepath.Join(root, "pkg", "new", "file.go"), true},
+		{"parent", filepath.Join(root, ".."), false},
+		{"outside", outside, false},
+		{"symlink out", filepath.Join(root, "escape"), false},
+		{"through symlink out", filepath.Join(root, "escape", "file.go"), false},
+		{"symlink to root", filepath.Join(linkedRoot, "pkg"), true},
+	}
+	for _, tt := range tests {
+		if got := Contains([]string{root}, tt.path); got != tt.want {
+			t.Errorf("%s: Contains(%s) = %v, want %v", tt.name, tt.path, got, tt.want)
+		}
+	}
+	if !Contains([]string{linkedRoot}, filepath.Join(root, "pkg")) {
+		t.Error("expected a root given through a symlink to contain its directories")
+	}
+}
+
+func TestDir(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	if dir, err := Dir("testing", root, []string{root}); err != nil || dir != root {
+		t.Errorf("Dir() = %q, %v", dir, err)
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		roots   []string
+		wantErr string
+	}{
+		{"no roots", root, nil, "testing requires at least one registered workspace root"},
+		{"missing", filepath.Join(root, "missing"), []string{root}, "working directory not found"},
+		{"outside", outside, []string{root}, "not inside a registered workspace"},
+		{"symlink out", filepath.Join(root, "escape"), []string{root}, "not inside a registered workspace"},
+	}
+	for _, tt := range tests {
+		if _, err := Dir("testing", tt.dir, tt.roots); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestFile(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(filepath.Join(outside, "cpu.pprof"), filepath.Join(root, "cpu.pprof")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := File("profile_file", filepath.Join(root, "heap.pprof"), []string{root}); err != nil {
+		t.Errorf("File() error = %v", err)
+	}
+	if _, err := File("profile_file", filepath.Join(root, "cpu.pprof"), []string{root}); err == nil || !strings.Contains(err.Error(), "not inside a registered workspace") {
+		t.Errorf("expected a symlink out of the root to be rejected, got %v", err)
+	}
+	if _, err := File("profile_file", "cpu.pprof", nil); err == nil || !strings.Contains(err.Error(), "profile_file requires") {
+		t.Errorf("expected an error without roots, got %v", err)
+	}
+}
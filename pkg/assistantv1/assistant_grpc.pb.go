@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.1
+// source: assistant/v1/assistant.proto
+
+package assistantv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Assistant_GoDoc_FullMethodName      = "/mcpassistant.v1.Assistant/GoDoc"
+	Assistant_CodeReview_FullMethodName = "/mcpassistant.v1.Assistant/CodeReview"
+	Assistant_TestGen_FullMethodName    = "/mcpassistant.v1.Assistant/TestGen"
+)
+
+// AssistantClient is the client API for Assistant service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Assistant serves the go-doc, code-review and test-gen tools of the MCP
+// server to integrations that do not speak MCP, such as CI jobs and IDE
+// plugins. Calls run through the same validation, rate limiting, caching
+// and resilience middleware as the MCP tools, and fields mean the same as
+// the tool parameters of the same name.
+type AssistantClient interface {
+	GoDoc(ctx context.Context, in *GoDocRequest, opts ...grpc.CallOption) (*GoDocResponse, error)
+	CodeReview(ctx context.Context, in *CodeReviewRequest, opts ...grpc.CallOption) (*CodeReviewResponse, error)
+	TestGen(ctx context.Context, in *TestGenRequest, opts ...grpc.CallOption) (*TestGenResponse, error)
+}
+
+type assistantClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAssistantClient(cc grpc.ClientConnInterface) AssistantClient {
+	return &assistantClient{cc}
+}
+
+func (c *assistantClient) GoDoc(ctx context.Context, in *GoDocRequest, opts ...grpc.CallOption) (*GoDocResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GoDocResponse)
+	err := c.cc.Invoke(ctx, Assistant_GoDoc_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assistantClient) CodeReview(ctx context.Context, in *CodeReviewRequest, opts ...grpc.CallOption) (*CodeReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CodeReviewResponse)
+	err := c.cc.Invoke(ctx, Assistant_CodeReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assistantClient) TestGen(ctx context.Context, in *TestGenRequest, opts ...grpc.CallOption) (*TestGenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TestGenResponse)
+	err := c.cc.Invoke(ctx, Assistant_TestGen_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AssistantServer is the server API for Assistant service.
+// All implementations must embed UnimplementedAssistantServer
+// for forward compatibility.
+//
+// Assistant serves the go-doc, code-review and test-gen tools of the MCP
+// server to integrations that do not speak MCP, such as CI jobs and IDE
+// plugins. Calls run through the same validation, rate limiting, caching
+// and resilience middleware as the MCP tools, and fields mean the same as
+// the tool parameters of the same name.
+type AssistantServer interface {
+	GoDoc(context.Context, *GoDocRequest) (*GoDocResponse, error)
+	CodeReview(context.Context, *CodeReviewRequest) (*CodeReviewResponse, error)
+	TestGen(context.Context, *TestGenRequest) (*TestGenResponse, error)
+	mustEmbedUnimplementedAssistantServer()
+}
+
+// UnimplementedAssistantServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAssistantServer struct{}
+
+func (UnimplementedAssistantServer) GoDoc(context.Context, *GoDocRequest) (*GoDocResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GoDoc not implemented")
+}
+func (UnimplementedAssistantServer) CodeReview(context.Context, *CodeReviewRequest) (*CodeReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CodeReview not implemented")
+}
+func (UnimplementedAssistantServer) TestGen(context.Context, *TestGenRequest) (*TestGenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TestGen not implemented")
+}
+func (UnimplementedAssistantServer) mustEmbedUnimplementedAssistantServer() {}
+func (UnimplementedAssistantServer) testEmbeddedByValue()                   {}
+
+// UnsafeAssistantServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AssistantServer will
+// result in compilation errors.
+type UnsafeAssistantServer interface {
+	mustEmbedUnimplementedAssistantServer()
+}
+
+func RegisterAssistantServer(s grpc.ServiceRegistrar, srv AssistantServer) {
+	// If the following call pancis, it indicates UnimplementedAssistantServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Assistant_ServiceDesc, srv)
+}
+
+func _Assistant_GoDoc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GoDocRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssistantServer).GoDoc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Assistant_GoDoc_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssistantServer).GoDoc(ctx, req.(*GoDocRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Assistant_CodeReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CodeReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssistantServer).CodeReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Assistant_CodeReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssistantServer).CodeReview(ctx, req.(*CodeReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Assistant_TestGen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TestGenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssistantServer).TestGen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Assistant_TestGen_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssistantServer).TestGen(ctx, req.(*TestGenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Assistant_ServiceDesc is the grpc.ServiceDesc for Assistant service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Assistant_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mcpassistant.v1.Assistant",
+	HandlerType: (*AssistantServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GoDoc",
+			Handler:    _Assistant_GoDoc_Handler,
+		},
+		{
+			MethodName: "CodeReview",
+			Handler:    _Assistant_CodeReview_Handler,
+		},
+		{
+			MethodName: "TestGen",
+			Handler:    _Assistant_TestGen_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "assistant/v1/assistant.proto",
+}
@@ -0,0 +1,175 @@
+// Package analyzertest runs review rules over annotated source snippets in
+// tests, in the manner of golang.org/x/tools/go/analysis/analysistest.
+//
+// Each snippet is a complete Go file. A comment of the form
+//
+//	// want "regexp" ...
+//
+// expects, on its line, one issue of the rule under test whose message
+// matches each quoted regular expression. Issues without a matching
+// expectation and expectations without a matching issue fail the test:
+//
+//	func TestNoTodo(t *testing.T) {
+//		analyzertest.Run(t, noTodoRule{},
+//			analyzertest.Test{Name: "todo", Src: `package p
+//
+//	// TODO: remove // want "TODO comment"
+//	func F() {}
+//	`},
+//			analyzertest.Test{Name: "clean", Src: "package p\n"},
+//		)
+//	}
+//
+// Snippets are reviewed by the same analyzer as the code-review tool, so
+// ignore directives and the conversion of findings to issues are exercised
+// too.
+package analyzertest
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/internal/codereview"
+	"mcp-go-assistant/pkg/reviewrule"
+)
+
+// Test is a source snippet to review
+type Test struct {
+	Name string
+	Src  string // A complete Go file annotated with want comments
+}
+
+// expectation is a message pattern expected on a line
+type expectation struct {
+	line    int
+	pattern *regexp.Regexp
+	matched bool
+}
+
+// Run reviews each test's snippet with rule, in a subtest named after the
+// test, and checks the rule's issues against the snippet's annotations
+func Run(t *testing.T, rule reviewrule.Rule, tests ...Test) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			check(t, rule.Name(), []reviewrule.Rule{rule}, tt.Src)
+		})
+	}
+}
+
+// RunBuiltin is like Run for the built-in rule named name, such as
+// "library-print"
+func RunBuiltin(t *testing.T, name string, tests ...Test) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			check(t, name, nil, tt.Src)
+		})
+	}
+}
+
+// check reviews src with the built-in and external rules and compares the
+// issues of the rule named name with the want annotations in src
+func check(t testing.TB, name string, external []reviewrule.Rule, src string) {
+	t.Helper()
+	expectations, err := parseExpectations(src)
+	if err != nil {
+		t.Fatalf("invalid test source: %v", err)
+		return
+	}
+
+	analyzer := codereview.NewAnalyzer(nil, "")
+	analyzer.SetExternalRules(external)
+	result, err := analyzer.AnalyzeCode(src)
+	if err != nil {
+		t.Fatalf("review failed: %v", err)
+		return
+	}
+
+	for _, issue := range result.Issues {
+		if issue.Category == "plugin" && issue.Type == "error" && issue.Rule == name {
+			t.Errorf("rule %s failed: %s", name, issue.Message)
+			continue
+		}
+		if issue.Rule != name {
+			continue
+		}
+		if !matchExpectation(expectations, issue) {
+			t.Errorf("%d: unexpected issue: %s", issue.Line, issue.Message)
+		}
+	}
+	for _, e := range expectations {
+		if !e.matched {
+			t.Errorf("%d: no issue was reported matching %q", e.line, e.pattern)
+		}
+	}
+}
+
+// matchExpectation marks the first unmatched expectation on the issue's
+// line whose pattern matches its message, reporting whether there was one
+func matchExpectation(expectations []*expectation, issue codereview.Issue) bool {
+	for _, e := range expectations {
+		if !e.matched && e.line == issue.Line && e.pattern.MatchString(issue.Message) {
+			e.matched = true
+			return true
+		}
+	}
+	return false
+}
+
+// parseExpectations returns the expectations of the want comments in src
+func parseExpectations(src string) ([]*expectation, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var expectations []*expectation
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			// The annotation may follow other text in the comment
+			i := strings.Index(c.Text, "// want ")
+			if i < 0 {
+				continue
+			}
+			text := c.Text[i+len("// want "):]
+			line := fset.Position(c.Pos()).Line
+			patterns, err := parsePatterns(text)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %v", line, err)
+			}
+			for _, p := range patterns {
+				expectations = append(expectations, &expectation{line: line, pattern: p})
+			}
+		}
+	}
+	return expectations, nil
+}
+
+// parsePatterns parses a list of quoted regular expressions
+func parsePatterns(text string) ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for text = strings.TrimSpace(text); text != ""; text = strings.TrimSpace(text) {
+		quoted, err := strconv.QuotedPrefix(text)
+		if err != nil {
+			return nil, fmt.Errorf("want expects quoted regular expressions, got %s", text)
+		}
+		text = text[len(quoted):]
+		expr, _ := strconv.Unquote(quoted)
+		p, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid want pattern %s: %v", quoted, err)
+		}
+		patterns = append(patterns, p)
+	}
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("want expects at least one pattern")
+	}
+	return patterns, nil
+}
@@ -0,0 +1,153 @@
+package analyzertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"mcp-go-assistant/pkg/reviewrule"
+)
+
+// noTodoRule reports TODO comments
+type noTodoRule struct{}
+
+func (noTodoRule) Name() string { return "acme-no-todo" }
+
+func (noTodoRule) Check(file *reviewrule.File) []reviewrule.Finding {
+	var findings []reviewrule.Finding
+	for _, group := range file.AST.Comments {
+		for _, c := range group.List {
+			if strings.Contains(c.Text, "TODO") {
+				findings = append(findings, reviewrule.Finding{Pos: c.Pos(), Message: "TODO comment left in code"})
+			}
+		}
+	}
+	return findings
+}
+
+// panicRule fails on every file
+type panicRule struct{}
+
+func (panicRule) Name() string { return "acme-panic" }
+
+func (panicRule) Check(*reviewrule.File) []reviewrule.Finding { panic("boom") }
+
+// recorder records the failures of a test
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+func TestRun(t *testing.T) {
+	Run(t, noTodoRule{},
+		Test{Name: "todo", Src: `package p
+
+// TODO: remove // want "TODO comment"
+func F() {}
+
+// TODO: one // want "^TODO"
+// TODO: two // want "left in code"
+var x = 1
+`},
+		Test{Name: "clean", Src: "package p\n\nfunc F() {}\n"},
+		Test{Name: "ignored", Src: `package p
+
+func F() {
+	//mcp:ignore acme-no-todo
+	// TODO: later
+}
+`},
+	)
+}
+
+func TestRunBuiltin(t *testing.T) {
+	RunBuiltin(t, "library-print",
+		Test{Name: "library", Src: `package store
+
+import "fmt"
+
+func Load() {
+	fmt.Println("loading") // want "fmt.Println in library package 'store'"
+}
+`},
+		Test{Name: "main", Src: `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hello")
+}
+`},
+	)
+}
+
+func TestCheck_Failures(t *testing.T) {
+	tests := []struct {
+		name string
+		rule reviewrule.Rule
+		src  string
+		want []string
+	}{
+		{
+			name: "unexpected issue",
+			rule: noTodoRule{},
+			src:  "package p\n\n// TODO: remove\nvar x = 1\n",
+			want: []string{"3: unexpected issue: TODO comment left in code"},
+		},
+		{
+			name: "missing issue",
+			rule: noTodoRule{},
+			src:  "package p\n\nvar x = 1 // want \"FIXME\"\n",
+			want: []string{`3: no issue was reported matching "FIXME"`},
+		},
+		{
+			name: "wrong message",
+			rule: noTodoRule{},
+			src:  "package p\n\n// TODO: remove // want \"FIXME\"\nvar x = 1\n",
+			want: []string{"3: unexpected issue: TODO comment left in code", `3: no issue was reported matching "FIXME"`},
+		},
+		{
+			name: "rule failure",
+			rule: panicRule{},
+			src:  "package p\n",
+			want: []string{"rule acme-panic failed"},
+		},
+		{
+			name: "unquoted pattern",
+			rule: noTodoRule{},
+			src:  "package p\n\nvar x = 1 // want TODO\n",
+			want: []string{"invalid test source: 3: want expects quoted regular expressions"},
+		},
+		{
+			name: "syntax error",
+			rule: noTodoRule{},
+			src:  "package p\n\nfunc {\n",
+			want: []string{"invalid test source"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			check(r, tt.rule.Name(), []reviewrule.Rule{tt.rule}, tt.src)
+			if len(r.errors) != len(tt.want) {
+				t.Fatalf("expected %d failures, got %q", len(tt.want), r.errors)
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(r.errors[i], want) {
+					t.Errorf("failure %d = %q, want prefix %q", i, r.errors[i], want)
+				}
+			}
+		})
+	}
+}
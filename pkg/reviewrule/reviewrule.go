@@ -0,0 +1,64 @@
+// Package reviewrule is the stable interface for code-review rules shipped
+// outside this repository. A rule receives each reviewed file already
+// parsed and returns its findings, which are reported next to the built-in
+// issues.
+//
+// Rules are loaded from Go plugins listed under review.plugins in the
+// server configuration. A plugin is a main package built with
+// go build -buildmode=plugin that exports a Rules function:
+//
+//	package main
+//
+//	import "mcp-go-assistant/pkg/reviewrule"
+//
+//	func Rules() []reviewrule.Rule {
+//		return []reviewrule.Rule{noFmtRule{}}
+//	}
+//
+// Go plugins only load into a server built with the same toolchain and
+// the same version of this package. Package analyzertest runs rules over
+// annotated source snippets in tests.
+package reviewrule
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Symbol is the name of the function a plugin exports to provide its
+// rules. It must have the type func() []Rule.
+const Symbol = "Rules"
+
+// Severities a finding may have, from least to most severe
+const (
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// Rule is a single code-review rule. Check is called concurrently for
+// different files and must not modify the file.
+type Rule interface {
+	// Name identifies the rule in reported issues and ignore directives,
+	// e.g. "acme-no-fmt". It should be unique and stable across versions.
+	Name() string
+
+	// Check returns the rule's findings in file
+	Check(file *File) []Finding
+}
+
+// File is a parsed Go source file under review
+type File struct {
+	Fset *token.FileSet
+	AST  *ast.File // Parsed with comments
+}
+
+// Finding is a problem a rule found in a file
+type Finding struct {
+	Pos, End   token.Pos // Extent of the offending code; End may be zero
+	Message    string
+	Suggestion string // Optional
+	Severity   string // One of the Severity constants; defaults to SeverityMedium
+	Category   string // Optional; defaults to "custom"
+}
@@ -0,0 +1,66 @@
+#!/bin/bash
+# Benchmark comparison script for mcp-go-assistant
+# Runs the benchmarks on a base ref and on the working tree and compares
+# them with benchstat
+
+set -e
+
+# Configuration
+BASE="${1:-main}"
+BENCH="${BENCH:-.}"
+BENCH_PKGS="${BENCH_PKGS:-./internal/codereview/ ./internal/testgen/}"
+COUNT="${COUNT:-10}"
+OUT_DIR="${OUT_DIR:-.bench}"
+
+# Colors for output
+RED='\033[0;31m'
+GREEN='\033[0;32m'
+NC='\033[0m'
+
+log_info() {
+    echo -e "${GREEN}[INFO]${NC} $1"
+}
+
+log_error() {
+    echo -e "${RED}[ERROR]${NC} $1"
+}
+
+# Run the benchmarks in the current directory, writing results to $1
+run_benchmarks() {
+    # shellcheck disable=SC2086
+    go test -run '^$' -bench "$BENCH" -benchmem -count "$COUNT" $BENCH_PKGS > "$1"
+}
+
+# Prefer an installed benchstat, falling back to go run
+benchstat_cmd() {
+    if command -v benchstat > /dev/null 2>&1; then
+        benchstat "$@"
+    else
+        go run golang.org/x/perf/cmd/benchstat@latest "$@"
+    fi
+}
+
+main() {
+    if ! git rev-parse --verify --quiet "$BASE" > /dev/null; then
+        log_error "Unknown base ref: $BASE"
+        exit 1
+    fi
+
+    mkdir -p "$OUT_DIR"
+    local out_dir
+    out_dir="$(cd "$OUT_DIR" && pwd)"
+    WORKTREE="$(mktemp -d)"
+    trap 'git worktree remove --force "$WORKTREE" > /dev/null 2>&1 || true' EXIT
+
+    log_info "Benchmarking base ref $BASE"
+    git worktree add --detach "$WORKTREE" "$BASE" > /dev/null
+    # Benchmarks added after the base ref do not exist there yet
+    (cd "$WORKTREE" && run_benchmarks "$out_dir/old.txt") || log_error "Benchmarks failed on $BASE"
+
+    log_info "Benchmarking working tree"
+    run_benchmarks "$out_dir/new.txt"
+
+    benchstat_cmd "$out_dir/old.txt" "$out_dir/new.txt"
+}
+
+main